@@ -0,0 +1,69 @@
+package alerts
+
+import (
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/notify"
+)
+
+const (
+	// ActionWebhook sends the alert as a JSON POST request to the action URL
+	ActionWebhook = "webhook"
+
+	// ActionTopic publishes the alert to the alert subscribers (the newAlerts WS subscription)
+	ActionTopic = "topic"
+
+	// ActionLog writes the alert to the indexer log
+	ActionLog = "log"
+
+	// ActionSlack sends the alert as a message to the action URL (Slack incoming webhook)
+	ActionSlack = "slack"
+
+	// ActionDiscord sends the alert as a message to the action URL (Discord webhook)
+	ActionDiscord = "discord"
+)
+
+// Action is a single alert rule action
+type Action struct {
+	// Type is the action type (webhook, topic, log, slack, discord)
+	Type string `json:"type"`
+
+	// URL is the webhook URL, for the webhook, slack and discord actions
+	URL string `json:"url,omitempty"`
+}
+
+// validate validates the action configuration
+func (a *Action) validate() error {
+	switch a.Type {
+	case ActionWebhook, ActionSlack, ActionDiscord:
+		u, err := url.Parse(a.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s action URL %q", a.Type, a.URL)
+		}
+
+		return nil
+	case ActionTopic, ActionLog:
+		return nil
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+}
+
+// notifier creates the notifier carrying out the action
+func (a *Action) notifier(events Events, logger *zap.Logger) notify.Notifier {
+	switch a.Type {
+	case ActionWebhook:
+		return notify.NewWebhook(a.URL)
+	case ActionSlack:
+		return notify.NewSlack(a.URL)
+	case ActionDiscord:
+		return notify.NewDiscord(a.URL)
+	case ActionTopic:
+		return notify.NewPublisher(events)
+	default:
+		return notify.NewLog(logger)
+	}
+}
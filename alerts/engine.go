@@ -0,0 +1,171 @@
+// Package alerts evaluates the configured alert rules against every indexed transaction,
+// triggering the rule actions (webhook, event topic, log) for the matching ones
+package alerts
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/notify"
+	commonTypes "github.com/gnolang/tx-indexer/types"
+)
+
+const (
+	// Topic is the alert notification topic
+	Topic = "alerts"
+
+	// DefaultNotifyTimeout is the default timeout for carrying out a single alert action
+	DefaultNotifyTimeout = 10 * time.Second
+)
+
+// rule is an alert rule, with the notifiers carrying out its actions
+type rule struct {
+	*Rule
+
+	notifiers []notify.Notifier
+}
+
+// Engine evaluates the alert rules against the new transactions
+type Engine struct {
+	logger *zap.Logger
+	events Events
+
+	rules   []*rule
+	decoder *decode.Decoder
+
+	notifyTimeout time.Duration
+}
+
+// New creates a new alert engine for the (validated) rules,
+// evaluating the new transactions until the context is cancelled
+func New(ctx context.Context, events Events, rules []*Rule, opts ...Option) *Engine {
+	e := &Engine{
+		logger:        zap.NewNop(),
+		events:        events,
+		rules:         make([]*rule, 0, len(rules)),
+		notifyTimeout: DefaultNotifyTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	for _, r := range rules {
+		notifiers := make([]notify.Notifier, 0, len(r.Actions))
+
+		for _, action := range r.Actions {
+			notifiers = append(notifiers, action.notifier(events, e.logger))
+		}
+
+		e.rules = append(e.rules, &rule{
+			Rule:      r,
+			notifiers: notifiers,
+		})
+	}
+
+	// Subscribe to new blocks
+	go e.subscribeToEvents(ctx)
+
+	return e
+}
+
+// subscribeToEvents evaluates the rules against the transactions in the new blocks
+func (e *Engine) subscribeToEvents(ctx context.Context) {
+	subscription := e.events.Subscribe([]events.Type{commonTypes.NewBlockEvent})
+	defer e.events.CancelSubscription(subscription.ID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, more := <-subscription.SubCh:
+			if !more {
+				return
+			}
+
+			newBlock, ok := event.(*commonTypes.NewBlock)
+			if !ok {
+				continue
+			}
+
+			for _, txResult := range newBlock.Results {
+				e.evaluate(ctx, txResult)
+			}
+		}
+	}
+}
+
+// evaluate evaluates the rules against the transaction, triggering the actions of the matching ones
+func (e *Engine) evaluate(ctx context.Context, txResult *types.TxResult) {
+	tx, err := e.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded are not evaluated
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for _, r := range e.rules {
+		if !r.matches(txResult, tx) {
+			continue
+		}
+
+		notification := &notify.Notification{
+			Topic:     Topic,
+			Source:    r.Name,
+			Message:   r.Description,
+			Height:    txResult.Height,
+			Index:     txResult.Index,
+			TxHash:    base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+			Addresses: make([]string, 0),
+		}
+
+		if notification.Message == "" {
+			notification.Message = fmt.Sprintf("alert rule %q matched", r.Name)
+		}
+
+		for _, address := range decode.Addresses(tx) {
+			notification.Addresses = append(notification.Addresses, address.String())
+		}
+
+		for _, notifier := range r.notifiers {
+			wg.Add(1)
+
+			go func(notifier notify.Notifier) {
+				defer wg.Done()
+
+				e.trigger(ctx, r.Name, notifier, notification)
+			}(notifier)
+		}
+	}
+
+	// Wait for the actions, so the alerts stay in order
+	wg.Wait()
+}
+
+// trigger carries out a single rule action
+func (e *Engine) trigger(
+	ctx context.Context,
+	name string,
+	notifier notify.Notifier,
+	notification *notify.Notification,
+) {
+	notifyCtx, cancelFn := context.WithTimeout(ctx, e.notifyTimeout)
+	defer cancelFn()
+
+	if err := notifier.Notify(notifyCtx, notification); err != nil {
+		e.logger.Warn(
+			"unable to carry out alert action",
+			zap.String("rule", name),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,106 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/notify"
+	commonTypes "github.com/gnolang/tx-indexer/types"
+)
+
+func TestEngine_TopicAction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from = crypto.Address{1}
+		to   = crypto.Address{2}
+
+		blockCh         = make(chan events.Event)
+		notificationsCh = make(chan *notify.Notification, 10)
+
+		mockEvents = &mock.Events{
+			SubscribeFn: func(_ []events.Type) *events.Subscription {
+				return &events.Subscription{
+					SubCh: blockCh,
+				}
+			},
+			SignalEventFn: func(event events.Event) {
+				notification, ok := event.GetData().(*notify.Notification)
+				require.True(t, ok)
+
+				notificationsCh <- notification
+			},
+		}
+
+		newSend = func(amount int64) []byte {
+			return amino.MustMarshal(std.Tx{
+				Msgs: []std.Msg{
+					bank.MsgSend{
+						FromAddress: from,
+						ToAddress:   to,
+						Amount:      std.Coins{{Denom: "ugnot", Amount: amount}},
+					},
+				},
+			})
+		}
+
+		rules = []*Rule{
+			{
+				Name:        "large-transfers",
+				Description: "large transfer",
+				MsgType:     "send",
+				MinAmount:   "1000000ugnot",
+				Actions: []*Action{
+					{Type: ActionTopic},
+				},
+			},
+		}
+	)
+
+	for _, rule := range rules {
+		require.NoError(t, rule.validate())
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	New(ctx, mockEvents, rules)
+
+	blockCh <- &commonTypes.NewBlock{
+		Block: &types.Block{},
+		Results: []*types.TxResult{
+			{Height: 10, Index: 0, Tx: newSend(10)},
+			{Height: 10, Index: 1, Tx: newSend(5000000)},
+		},
+	}
+
+	select {
+	case notification := <-notificationsCh:
+		assert.Equal(t, Topic, notification.Topic)
+		assert.Equal(t, "large-transfers", notification.Source)
+		assert.Equal(t, "large transfer", notification.Message)
+		assert.Equal(t, int64(10), notification.Height)
+		assert.Equal(t, uint32(1), notification.Index)
+		assert.ElementsMatch(t, []string{from.String(), to.String()}, notification.Addresses)
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert not published")
+	}
+
+	// Make sure the small transfer didn't trigger an alert
+	select {
+	case notification := <-notificationsCh:
+		t.Fatalf("unexpected alert, %v", notification)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
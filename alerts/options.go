@@ -0,0 +1,35 @@
+package alerts
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+type Option func(e *Engine)
+
+// WithLogger sets the logger to be used
+// with the alert engine
+func WithLogger(logger *zap.Logger) Option {
+	return func(e *Engine) {
+		e.logger = logger
+	}
+}
+
+// WithNotifyTimeout sets the timeout for
+// carrying out a single alert action
+func WithNotifyTimeout(timeout time.Duration) Option {
+	return func(e *Engine) {
+		e.notifyTimeout = timeout
+	}
+}
+
+// WithDecoder sets the chain decoder of the txs
+// evaluated against the rules
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(e *Engine) {
+		e.decoder = decoder
+	}
+}
@@ -0,0 +1,195 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+var (
+	errMissingName    = errors.New("missing rule name")
+	errMissingActions = errors.New("missing rule actions")
+	errDuplicateName  = errors.New("duplicate rule name")
+)
+
+// amountRegex matches a single coin amount, such as 1000000ugnot
+var amountRegex = regexp.MustCompile(`^([0-9]+)([a-zA-Z][a-zA-Z0-9/:._-]*)$`)
+
+// Config is the alert rules configuration file
+type Config struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule is a single alert rule. A transaction matches the rule if it matches all the set conditions,
+// with the message conditions (type, realm, amount) matched by a single message
+type Rule struct {
+	// Name is the unique rule name
+	Name string `json:"name"`
+
+	// Description is the alert message, if any
+	Description string `json:"description,omitempty"`
+
+	// MsgType is the message type (ex. send, exec, add_package, run)
+	MsgType string `json:"msgType,omitempty"`
+
+	// Realm is the called or deployed realm path
+	Realm string `json:"realm,omitempty"`
+
+	// MinAmount is the minimum amount sent by the message, such as 1000000ugnot
+	MinAmount string `json:"minAmount,omitempty"`
+
+	// Failed is a flag indicating if only failed transactions match
+	Failed bool `json:"failed,omitempty"`
+
+	// Errors are the matched transaction error types (ex. OutOfGasError), if any.
+	// Setting them implies the failed condition
+	Errors []string `json:"errors,omitempty"`
+
+	// Actions are the actions triggered when the rule matches
+	Actions []*Action `json:"actions"`
+
+	minAmount std.Coin
+}
+
+// LoadRules loads the alert rules from the JSON configuration file
+func LoadRules(path string) ([]*Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read alert rules, %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse alert rules, %w", err)
+	}
+
+	names := make(map[string]struct{}, len(config.Rules))
+
+	for _, rule := range config.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid alert rule %q, %w", rule.Name, err)
+		}
+
+		if _, ok := names[rule.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicateName, rule.Name)
+		}
+
+		names[rule.Name] = struct{}{}
+	}
+
+	return config.Rules, nil
+}
+
+// validate validates the rule, and parses its conditions
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return errMissingName
+	}
+
+	if len(r.Actions) == 0 {
+		return errMissingActions
+	}
+
+	for _, action := range r.Actions {
+		if err := action.validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.MinAmount == "" {
+		return nil
+	}
+
+	matches := amountRegex.FindStringSubmatch(r.MinAmount)
+	if matches == nil {
+		return fmt.Errorf("invalid minimum amount %q", r.MinAmount)
+	}
+
+	amount, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid minimum amount %q, %w", r.MinAmount, err)
+	}
+
+	r.minAmount = std.Coin{Denom: matches[2], Amount: amount}
+
+	return nil
+}
+
+// matches returns a flag indicating if the transaction matches the rule
+func (r *Rule) matches(txResult *types.TxResult, tx *std.Tx) bool {
+	if (r.Failed || len(r.Errors) > 0) && !txResult.Response.IsErr() {
+		return false
+	}
+
+	if len(r.Errors) > 0 && !r.matchesError(txResult.Response.Error) {
+		return false
+	}
+
+	if r.MsgType == "" && r.Realm == "" && r.MinAmount == "" {
+		return true
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		if r.matchesMsg(msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesError returns a flag indicating if the error type is one of the rule error types
+func (r *Rule) matchesError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return slices.Contains(r.Errors, decode.ErrorCode(err))
+}
+
+// matchesMsg returns a flag indicating if the message matches the rule message conditions
+func (r *Rule) matchesMsg(msg std.Msg) bool {
+	if r.MsgType != "" && msg.Type() != r.MsgType {
+		return false
+	}
+
+	realm, amount := msgRealmAndAmount(msg)
+
+	if r.Realm != "" && realm != r.Realm {
+		return false
+	}
+
+	return r.MinAmount == "" || amount.AmountOf(r.minAmount.Denom) >= r.minAmount.Amount
+}
+
+// msgRealmAndAmount returns the realm called or deployed by the message,
+// and the amount sent by the message, if any
+func msgRealmAndAmount(msg std.Msg) (string, std.Coins) {
+	switch m := msg.(type) {
+	case bank.MsgSend:
+		return "", m.Amount
+	case vm.MsgCall:
+		return m.PkgPath, m.Send
+	case vm.MsgRun:
+		return "", m.Send
+	case vm.MsgAddPackage:
+		if m.Package == nil {
+			return "", m.Deposit
+		}
+
+		return m.Package.Path, m.Deposit
+	default:
+		return "", nil
+	}
+}
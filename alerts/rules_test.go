@@ -0,0 +1,279 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRules writes the alert rules configuration to a temporary file
+func writeRules(t *testing.T, config string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	return path
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid rules", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeRules(t, `{
+			"rules": [
+				{
+					"name": "large-transfers",
+					"msgType": "send",
+					"minAmount": "1000000ugnot",
+					"actions": [
+						{"type": "webhook", "url": "https://example.com/alerts"},
+						{"type": "log"},
+						{"type": "slack", "url": "https://hooks.slack.com/services/T000/B000/XXXX"},
+						{"type": "discord", "url": "https://discord.com/api/webhooks/000/XXXX"}
+					]
+				},
+				{
+					"name": "failed-calls",
+					"realm": "gno.land/r/demo/boards",
+					"failed": true,
+					"actions": [{"type": "topic"}]
+				}
+			]
+		}`)
+
+		rules, err := LoadRules(path)
+		require.NoError(t, err)
+
+		require.Len(t, rules, 2)
+
+		assert.Equal(t, "large-transfers", rules[0].Name)
+		assert.Equal(t, std.Coin{Denom: "ugnot", Amount: 1000000}, rules[0].minAmount)
+		assert.Len(t, rules[0].Actions, 4)
+
+		assert.Equal(t, "failed-calls", rules[1].Name)
+		assert.True(t, rules[1].Failed)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		t.Parallel()
+
+		testTable := []struct {
+			name   string
+			config string
+		}{
+			{
+				"invalid JSON",
+				`{"rules": [`,
+			},
+			{
+				"missing name",
+				`{"rules": [{"actions": [{"type": "log"}]}]}`,
+			},
+			{
+				"missing actions",
+				`{"rules": [{"name": "rule"}]}`,
+			},
+			{
+				"unknown action",
+				`{"rules": [{"name": "rule", "actions": [{"type": "email"}]}]}`,
+			},
+			{
+				"invalid webhook URL",
+				`{"rules": [{"name": "rule", "actions": [{"type": "webhook", "url": "ftp://example.com"}]}]}`,
+			},
+			{
+				"missing slack URL",
+				`{"rules": [{"name": "rule", "actions": [{"type": "slack"}]}]}`,
+			},
+			{
+				"invalid minimum amount",
+				`{"rules": [{"name": "rule", "minAmount": "ugnot", "actions": [{"type": "log"}]}]}`,
+			},
+			{
+				"duplicate name",
+				`{"rules": [
+					{"name": "rule", "actions": [{"type": "log"}]},
+					{"name": "rule", "actions": [{"type": "log"}]}
+				]}`,
+			},
+		}
+
+		for _, testCase := range testTable {
+			testCase := testCase
+
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				rules, err := LoadRules(writeRules(t, testCase.config))
+				assert.Nil(t, rules)
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadRules(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestRule_Matches(t *testing.T) {
+	t.Parallel()
+
+	var (
+		realm = "gno.land/r/demo/boards"
+
+		send = &std.Tx{
+			Msgs: []std.Msg{
+				bank.MsgSend{
+					FromAddress: crypto.Address{1},
+					ToAddress:   crypto.Address{2},
+					Amount:      std.Coins{{Denom: "ugnot", Amount: 5000000}},
+				},
+			},
+		}
+
+		call = &std.Tx{
+			Msgs: []std.Msg{
+				vm.MsgCall{
+					Caller:  crypto.Address{1},
+					PkgPath: realm,
+					Func:    "CreatePost",
+				},
+			},
+		}
+
+		okResult     = &types.TxResult{}
+		failedResult = &types.TxResult{
+			Response: abci.ResponseDeliverTx{
+				ResponseBase: abci.ResponseBase{
+					Error: abci.StringError("out of gas"),
+				},
+			},
+		}
+	)
+
+	testTable := []struct {
+		name     string
+		rule     *Rule
+		txResult *types.TxResult
+		tx       *std.Tx
+		matches  bool
+	}{
+		{
+			"no conditions",
+			&Rule{},
+			okResult,
+			send,
+			true,
+		},
+		{
+			"message type match",
+			&Rule{MsgType: "send"},
+			okResult,
+			send,
+			true,
+		},
+		{
+			"message type mismatch",
+			&Rule{MsgType: "exec"},
+			okResult,
+			send,
+			false,
+		},
+		{
+			"realm match",
+			&Rule{MsgType: "exec", Realm: realm},
+			okResult,
+			call,
+			true,
+		},
+		{
+			"realm mismatch",
+			&Rule{Realm: "gno.land/r/demo/other"},
+			okResult,
+			call,
+			false,
+		},
+		{
+			"amount above threshold",
+			&Rule{MinAmount: "1000000ugnot", minAmount: std.Coin{Denom: "ugnot", Amount: 1000000}},
+			okResult,
+			send,
+			true,
+		},
+		{
+			"amount below threshold",
+			&Rule{MinAmount: "10000000ugnot", minAmount: std.Coin{Denom: "ugnot", Amount: 10000000}},
+			okResult,
+			send,
+			false,
+		},
+		{
+			"amount in other denom",
+			&Rule{MinAmount: "1foo", minAmount: std.Coin{Denom: "foo", Amount: 1}},
+			okResult,
+			send,
+			false,
+		},
+		{
+			"failed match",
+			&Rule{Failed: true},
+			failedResult,
+			send,
+			true,
+		},
+		{
+			"failed mismatch",
+			&Rule{Failed: true},
+			okResult,
+			send,
+			false,
+		},
+		{
+			"error type match",
+			&Rule{Errors: []string{"StringError"}},
+			failedResult,
+			call,
+			true,
+		},
+		{
+			"error type mismatch",
+			&Rule{Errors: []string{"OutOfGasError"}},
+			failedResult,
+			call,
+			false,
+		},
+		{
+			"error type on successful transaction",
+			&Rule{Errors: []string{"StringError"}},
+			okResult,
+			call,
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.matches, testCase.rule.matches(testCase.txResult, testCase.tx))
+		})
+	}
+}
@@ -0,0 +1,556 @@
+// Package apikeys manages the API keys of the indexer clients, accounting
+// for the requests and data volume of each key, and enforcing its daily and monthly quotas
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	// DefaultFlushInterval is the default interval of saving the accounted usage
+	DefaultFlushInterval = 10 * time.Second
+
+	// Component is the storage system component of the API keys and their usage,
+	// saved as the plugin data of the chain namespace by the earlier versions
+	Component = "apikeys"
+
+	// The key prefixes of the API keys, and their usage counters (by key name and period)
+	prefixKey   = "k/"
+	prefixUsage = "u/"
+
+	// secretPrefix is the prefix of the generated API keys, so they're recognizable
+	secretPrefix = "txi_"
+
+	// secretSize is the number of random bytes of the generated API keys
+	secretSize = 24
+
+	// The formats of the quota period IDs
+	dayFormat   = "2006-01-02"
+	monthFormat = "2006-01"
+)
+
+var (
+	ErrKeyNotFound = errors.New("API key not found")
+	ErrKeyExists   = errors.New("API key already exists")
+	ErrInvalidName = errors.New("invalid API key name, expected 1 to 64 letters, digits, '.', '_' or '-'")
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// keyRecord is the saved API key. Only the hash of the key is saved
+type keyRecord struct {
+	Created time.Time `json:"created"`
+	Name    string    `json:"name"`
+	Hash    string    `json:"hash"`
+	Quota   Quota     `json:"quota"`
+}
+
+// counter is the usage of a single quota period
+type counter struct {
+	Requests uint64 `json:"requests"`
+	Bytes    uint64 `json:"bytes"`
+}
+
+// period is the usage of the current quota period
+type period struct {
+	id string
+	counter
+}
+
+// entry is the API key, with its current usage
+type entry struct {
+	record *keyRecord
+
+	day, month period
+
+	// closed are the previous periods, with the usage not yet saved
+	closed []period
+
+	// dirty is a flag indicating if the usage changed since it was saved
+	dirty bool
+}
+
+// Manager manages the API keys, and accounts for their usage.
+// The usage is kept in memory, and saved periodically
+type Manager struct {
+	db     storage.SystemStore
+	logger *zap.Logger
+	now    func() time.Time
+
+	byName map[string]*entry
+	byHash map[string]*entry
+
+	flushInterval time.Duration
+
+	mux sync.Mutex
+}
+
+// New creates a new API key manager, loading the saved keys
+// along with their usage of the current periods
+func New(db storage.SystemStore, opts ...Option) (*Manager, error) {
+	m := &Manager{
+		db:            db,
+		logger:        zap.NewNop(),
+		now:           time.Now,
+		byName:        make(map[string]*entry),
+		byHash:        make(map[string]*entry),
+		flushInterval: DefaultFlushInterval,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if err := m.load(); err != nil {
+		return nil, fmt.Errorf("unable to load API keys, %w", err)
+	}
+
+	return m, nil
+}
+
+// load loads the saved keys, and their usage of the current periods
+func (m *Manager) load() error {
+	it, err := m.db.Iterator([]byte(prefixKey), prefixEnd(prefixKey))
+	if err != nil {
+		return err
+	}
+
+	defer it.Close()
+
+	now := m.now().UTC()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return err
+		}
+
+		var record keyRecord
+		if err := json.Unmarshal(kv.Value, &record); err != nil {
+			return fmt.Errorf("unable to decode API key, %w", err)
+		}
+
+		e := &entry{
+			record: &record,
+			day:    period{id: now.Format(dayFormat)},
+			month:  period{id: now.Format(monthFormat)},
+		}
+
+		if e.day.counter, err = m.getCounter(record.Name, e.day.id); err != nil {
+			return err
+		}
+
+		if e.month.counter, err = m.getCounter(record.Name, e.month.id); err != nil {
+			return err
+		}
+
+		m.byName[record.Name] = e
+		m.byHash[record.Hash] = e
+	}
+
+	return it.Error()
+}
+
+// Run saves the accounted usage periodically, until the context is cancelled.
+// The pending usage is saved once more on shutdown
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return m.Flush()
+		case <-ticker.C:
+			if err := m.Flush(); err != nil {
+				m.logger.Error("unable to save API key usage", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Create creates a new API key with the quota, returning the generated key.
+// The key itself isn't saved, so it can't be retrieved later
+func (m *Manager) Create(name string, quota Quota) (string, error) {
+	if !nameRegex.MatchString(name) {
+		return "", ErrInvalidName
+	}
+
+	raw := make([]byte, secretSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("unable to generate API key, %w", err)
+	}
+
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, ok := m.byName[name]; ok {
+		return "", ErrKeyExists
+	}
+
+	now := m.now().UTC()
+
+	e := &entry{
+		record: &keyRecord{
+			Name:    name,
+			Hash:    hashSecret(secret),
+			Quota:   quota,
+			Created: now.Truncate(time.Second),
+		},
+		day:   period{id: now.Format(dayFormat)},
+		month: period{id: now.Format(monthFormat)},
+	}
+
+	if err := m.saveRecord(e.record); err != nil {
+		return "", fmt.Errorf("unable to save API key, %w", err)
+	}
+
+	m.byName[name] = e
+	m.byHash[e.record.Hash] = e
+
+	return secret, nil
+}
+
+// SetQuota replaces the quota of the API key
+func (m *Manager) SetQuota(name string, quota Quota) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	e, ok := m.byName[name]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	record := *e.record
+	record.Quota = quota
+
+	if err := m.saveRecord(&record); err != nil {
+		return fmt.Errorf("unable to save API key, %w", err)
+	}
+
+	e.record = &record
+
+	return nil
+}
+
+// Delete removes the API key, along with its saved usage
+func (m *Manager) Delete(name string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	e, ok := m.byName[name]
+	if !ok {
+		return ErrKeyNotFound
+	}
+
+	if err := m.deleteKey(name); err != nil {
+		return fmt.Errorf("unable to delete API key, %w", err)
+	}
+
+	delete(m.byName, name)
+	delete(m.byHash, e.record.Hash)
+
+	return nil
+}
+
+// Authenticate returns the name of the API key, if it exists
+func (m *Manager) Authenticate(secret string) (string, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	e, ok := m.byHash[hashSecret(secret)]
+	if !ok {
+		return "", false
+	}
+
+	return e.record.Name, true
+}
+
+// Allow checks if the API key is within its quota, returning the exceeded
+// quota otherwise. The unknown (deleted) keys are not allowed
+func (m *Manager) Allow(name string) (*Exceeded, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	e, ok := m.byName[name]
+	if !ok {
+		return nil, false
+	}
+
+	now := m.now().UTC()
+	e.roll(now)
+
+	var (
+		quota = e.record.Quota
+
+		nextDay   = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		nextMonth = time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	switch {
+	case exceeds(e.month.Requests, quota.MonthlyRequests):
+		return &Exceeded{Period: PeriodMonthly, Resource: ResourceRequests, Limit: quota.MonthlyRequests, Reset: nextMonth}, false
+	case exceeds(e.month.Bytes, quota.MonthlyBytes):
+		return &Exceeded{Period: PeriodMonthly, Resource: ResourceBytes, Limit: quota.MonthlyBytes, Reset: nextMonth}, false
+	case exceeds(e.day.Requests, quota.DailyRequests):
+		return &Exceeded{Period: PeriodDaily, Resource: ResourceRequests, Limit: quota.DailyRequests, Reset: nextDay}, false
+	case exceeds(e.day.Bytes, quota.DailyBytes):
+		return &Exceeded{Period: PeriodDaily, Resource: ResourceBytes, Limit: quota.DailyBytes, Reset: nextDay}, false
+	default:
+		return nil, true
+	}
+}
+
+// exceeds checks if the usage reached the limit, if any
+func exceeds(usage, limit uint64) bool {
+	return limit != 0 && usage >= limit
+}
+
+// Record accounts for the requests and response bytes of the API key
+func (m *Manager) Record(name string, requests, bytes uint64) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	e, ok := m.byName[name]
+	if !ok {
+		return
+	}
+
+	e.roll(m.now().UTC())
+
+	e.day.Requests += requests
+	e.day.Bytes += bytes
+	e.month.Requests += requests
+	e.month.Bytes += bytes
+
+	e.dirty = true
+}
+
+// Usage returns the current usage of the API key
+func (m *Manager) Usage(name string) (*Usage, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	e, ok := m.byName[name]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return m.usage(e), nil
+}
+
+// List returns the current usage of all the API keys, by name
+func (m *Manager) List() []*Usage {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	usages := make([]*Usage, 0, len(m.byName))
+
+	for _, e := range m.byName {
+		usages = append(usages, m.usage(e))
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Name < usages[j].Name
+	})
+
+	return usages
+}
+
+// usage returns the current usage of the API key entry
+func (m *Manager) usage(e *entry) *Usage {
+	e.roll(m.now().UTC())
+
+	return &Usage{
+		Name:            e.record.Name,
+		Created:         e.record.Created,
+		Quota:           e.record.Quota,
+		Day:             e.day.id,
+		Month:           e.month.id,
+		DailyRequests:   e.day.Requests,
+		MonthlyRequests: e.month.Requests,
+		DailyBytes:      e.day.Bytes,
+		MonthlyBytes:    e.month.Bytes,
+	}
+}
+
+// Flush saves the usage accounted since the previous flush
+func (m *Manager) Flush() error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	wb := m.db.WriteBatch()
+
+	dirty := make([]*entry, 0)
+
+	for _, e := range m.byName {
+		if !e.dirty {
+			continue
+		}
+
+		for _, p := range append(e.closed, e.day, e.month) {
+			raw, err := json.Marshal(p.counter)
+			if err != nil {
+				return errors.Join(err, wb.Rollback())
+			}
+
+			if err := wb.Set(usageKey(e.record.Name, p.id), raw); err != nil {
+				return errors.Join(err, wb.Rollback())
+			}
+		}
+
+		dirty = append(dirty, e)
+	}
+
+	if len(dirty) == 0 {
+		return wb.Rollback()
+	}
+
+	if err := wb.Commit(); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	for _, e := range dirty {
+		e.closed = nil
+		e.dirty = false
+	}
+
+	return wb.Rollback()
+}
+
+// roll starts the new quota periods, if the current ones are over.
+// The unsaved usage of the previous periods is saved in the following flush
+func (e *entry) roll(now time.Time) {
+	if day := now.Format(dayFormat); day != e.day.id {
+		if e.dirty {
+			e.closed = append(e.closed, e.day)
+		}
+
+		e.day = period{id: day}
+	}
+
+	if month := now.Format(monthFormat); month != e.month.id {
+		if e.dirty {
+			e.closed = append(e.closed, e.month)
+		}
+
+		e.month = period{id: month}
+	}
+}
+
+// getCounter fetches the saved usage of the key period, if any
+func (m *Manager) getCounter(name, id string) (counter, error) {
+	var c counter
+
+	raw, err := m.db.Get(usageKey(name, id))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return c, nil
+	}
+
+	if err != nil {
+		return c, fmt.Errorf("unable to fetch API key usage, %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, fmt.Errorf("unable to decode API key usage, %w", err)
+	}
+
+	return c, nil
+}
+
+// saveRecord saves the API key record
+func (m *Manager) saveRecord(record *keyRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	wb := m.db.WriteBatch()
+
+	if err := wb.Set([]byte(prefixKey+record.Name), raw); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	if err := wb.Commit(); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	return wb.Rollback()
+}
+
+// deleteKey removes the API key record, along with its usage of all the periods
+func (m *Manager) deleteKey(name string) error {
+	prefix := prefixUsage + name + "/"
+
+	it, err := m.db.Iterator([]byte(prefix), prefixEnd(prefix))
+	if err != nil {
+		return err
+	}
+
+	keys := make([][]byte, 0)
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return errors.Join(err, it.Close())
+		}
+
+		keys = append(keys, kv.Key)
+	}
+
+	if err := errors.Join(it.Error(), it.Close()); err != nil {
+		return err
+	}
+
+	wb := m.db.WriteBatch()
+
+	for _, key := range append(keys, []byte(prefixKey+name)) {
+		if err := wb.Delete(key); err != nil {
+			return errors.Join(err, wb.Rollback())
+		}
+	}
+
+	if err := wb.Commit(); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	return wb.Rollback()
+}
+
+// usageKey returns the key of the usage counter of the key period
+func usageKey(name, id string) []byte {
+	return []byte(prefixUsage + name + "/" + id)
+}
+
+// prefixEnd returns the (exclusive) upper bound of the keys with the prefix
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	end[len(end)-1]++
+
+	return end
+}
+
+// hashSecret returns the saved hash of the API key
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
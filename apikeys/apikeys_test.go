@@ -0,0 +1,262 @@
+package apikeys
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTestManager creates a new API key manager at the given time, backed by the storage
+func newTestManager(t *testing.T, db *storage.Pebble, now *time.Time) *Manager {
+	t.Helper()
+
+	m, err := New(db.System(Component))
+	require.NoError(t, err)
+
+	m.now = func() time.Time {
+		return *now
+	}
+
+	// Reload the keys at the test time
+	m.byName = make(map[string]*entry)
+	m.byHash = make(map[string]*entry)
+
+	require.NoError(t, m.load())
+
+	return m
+}
+
+func TestManager_Keys(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	m := newTestManager(t, db, &now)
+
+	// Create the key
+	secret, err := m.Create("tier-1", Quota{DailyRequests: 10})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(secret, secretPrefix))
+
+	_, err = m.Create("tier-1", Quota{})
+	assert.ErrorIs(t, err, ErrKeyExists)
+
+	_, err = m.Create("invalid/name", Quota{})
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	// Authenticate with it
+	name, ok := m.Authenticate(secret)
+	require.True(t, ok)
+	assert.Equal(t, "tier-1", name)
+
+	_, ok = m.Authenticate("txi_unknown")
+	assert.False(t, ok)
+
+	// Update the quota
+	require.NoError(t, m.SetQuota("tier-1", Quota{DailyRequests: 20}))
+	assert.ErrorIs(t, m.SetQuota("unknown", Quota{}), ErrKeyNotFound)
+
+	// Make sure the keys are loaded on restart
+	restarted := newTestManager(t, db, &now)
+
+	name, ok = restarted.Authenticate(secret)
+	require.True(t, ok)
+	assert.Equal(t, "tier-1", name)
+
+	usage, err := restarted.Usage("tier-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(20), usage.Quota.DailyRequests)
+	assert.Equal(t, now, usage.Created)
+
+	// Delete the key
+	require.NoError(t, restarted.Delete("tier-1"))
+	assert.ErrorIs(t, restarted.Delete("tier-1"), ErrKeyNotFound)
+
+	_, ok = restarted.Authenticate(secret)
+	assert.False(t, ok)
+
+	assert.Empty(t, newTestManager(t, db, &now).List())
+}
+
+func TestManager_ChainReset(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	m := newTestManager(t, db, &now)
+
+	secret, err := m.Create("key", Quota{})
+	require.NoError(t, err)
+
+	m.Record("key", 5, 100)
+	require.NoError(t, m.Flush())
+
+	// Make sure the keys and their usage survive the chain data wipe
+	require.NoError(t, db.Wipe())
+
+	restarted := newTestManager(t, db, &now)
+
+	name, ok := restarted.Authenticate(secret)
+	require.True(t, ok)
+	assert.Equal(t, "key", name)
+
+	usage, err := restarted.Usage("key")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), usage.DailyRequests)
+}
+
+func TestManager_Quota(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		quota    Quota
+		expected *Exceeded
+		requests uint64
+		bytes    uint64
+	}{
+		{
+			"unlimited",
+			Quota{},
+			nil,
+			1000,
+			1000000,
+		},
+		{
+			"within quota",
+			Quota{DailyRequests: 10, DailyBytes: 1000},
+			nil,
+			9,
+			999,
+		},
+		{
+			"daily requests exceeded",
+			Quota{DailyRequests: 10, MonthlyRequests: 100},
+			&Exceeded{
+				Period:   PeriodDaily,
+				Resource: ResourceRequests,
+				Limit:    10,
+				Reset:    time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+			},
+			10,
+			0,
+		},
+		{
+			"monthly bytes exceeded",
+			Quota{DailyBytes: 1000, MonthlyBytes: 500},
+			&Exceeded{
+				Period:   PeriodMonthly,
+				Resource: ResourceBytes,
+				Limit:    500,
+				Reset:    time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+			1,
+			500,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := storage.NewPebble(t.TempDir())
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				require.NoError(t, db.Close())
+			})
+
+			now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+			m := newTestManager(t, db, &now)
+
+			_, err = m.Create("key", testCase.quota)
+			require.NoError(t, err)
+
+			m.Record("key", testCase.requests, testCase.bytes)
+
+			exceeded, ok := m.Allow("key")
+
+			assert.Equal(t, testCase.expected == nil, ok)
+			assert.Equal(t, testCase.expected, exceeded)
+		})
+	}
+}
+
+func TestManager_Usage(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	now := time.Date(2024, time.March, 31, 23, 0, 0, 0, time.UTC)
+	m := newTestManager(t, db, &now)
+
+	_, err = m.Create("key", Quota{DailyRequests: 2})
+	require.NoError(t, err)
+
+	m.Record("key", 2, 100)
+
+	_, ok := m.Allow("key")
+	assert.False(t, ok)
+
+	// Make sure the flushed usage is loaded on restart
+	require.NoError(t, m.Flush())
+
+	usage, err := newTestManager(t, db, &now).Usage("key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024-03-31", usage.Day)
+	assert.Equal(t, "2024-03", usage.Month)
+	assert.Equal(t, uint64(2), usage.DailyRequests)
+	assert.Equal(t, uint64(100), usage.MonthlyBytes)
+
+	// Make sure the new periods start from zero,
+	// while the previous ones are saved
+	m.Record("key", 1, 10)
+
+	now = now.Add(2 * time.Hour)
+
+	_, ok = m.Allow("key")
+	assert.True(t, ok)
+
+	usage, err = m.Usage("key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2024-04-01", usage.Day)
+	assert.Equal(t, "2024-04", usage.Month)
+	assert.Zero(t, usage.DailyRequests)
+	assert.Zero(t, usage.MonthlyBytes)
+
+	require.NoError(t, m.Flush())
+
+	previous, err := m.getCounter("key", "2024-03")
+	require.NoError(t, err)
+
+	assert.Equal(t, counter{Requests: 3, Bytes: 110}, previous)
+}
@@ -0,0 +1,24 @@
+package apikeys
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(m *Manager)
+
+// WithLogger sets the logger to be used
+// with the API key manager
+func WithLogger(logger *zap.Logger) Option {
+	return func(m *Manager) {
+		m.logger = logger
+	}
+}
+
+// WithFlushInterval sets the interval of saving the accounted usage
+func WithFlushInterval(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.flushInterval = interval
+	}
+}
@@ -0,0 +1,63 @@
+package apikeys
+
+import "time"
+
+// The quota periods, in UTC
+const (
+	PeriodDaily   = "daily"
+	PeriodMonthly = "monthly"
+)
+
+// The quota resources
+const (
+	ResourceRequests = "requests"
+	ResourceBytes    = "bytes"
+)
+
+// Quota is the API key usage quota. The zero limits are unlimited
+type Quota struct {
+	// DailyRequests is the maximum number of requests per (UTC) day
+	DailyRequests uint64 `json:"dailyRequests,omitempty"`
+
+	// MonthlyRequests is the maximum number of requests per (UTC) month
+	MonthlyRequests uint64 `json:"monthlyRequests,omitempty"`
+
+	// DailyBytes is the maximum response data volume per (UTC) day, in bytes
+	DailyBytes uint64 `json:"dailyBytes,omitempty"`
+
+	// MonthlyBytes is the maximum response data volume per (UTC) month, in bytes
+	MonthlyBytes uint64 `json:"monthlyBytes,omitempty"`
+}
+
+// Usage is the API key usage of the current periods, along with its quota
+type Usage struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Quota   Quota     `json:"quota"`
+
+	// Day is the current (UTC) day, as YYYY-MM-DD
+	Day string `json:"day"`
+
+	// Month is the current (UTC) month, as YYYY-MM
+	Month string `json:"month"`
+
+	DailyRequests   uint64 `json:"dailyRequests"`
+	MonthlyRequests uint64 `json:"monthlyRequests"`
+	DailyBytes      uint64 `json:"dailyBytes"`
+	MonthlyBytes    uint64 `json:"monthlyBytes"`
+}
+
+// Exceeded is the exceeded API key quota
+type Exceeded struct {
+	// Reset is the start of the next quota period
+	Reset time.Time
+
+	// Period is the quota period, one of the Period constants
+	Period string
+
+	// Resource is the quota resource, one of the Resource constants
+	Resource string
+
+	// Limit is the exceeded quota limit
+	Limit uint64
+}
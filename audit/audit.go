@@ -0,0 +1,186 @@
+// Package audit keeps the append-only audit log of the administrative actions,
+// recording each admin method call along with its caller, params and result
+package audit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	// Component is the storage system component of the audit log,
+	// saved as the plugin data of the chain namespace by the earlier versions
+	Component = "audit"
+
+	// keyNextID is the key of the ID of the next appended entry
+	keyNextID = "n"
+
+	// prefixEntry is the key prefix of the entries, by ID
+	prefixEntry = "e/"
+
+	// readChunk is the number of entries read at once, when paging through the log
+	readChunk = 256
+)
+
+// Log is the append-only audit log. The entries have sequential IDs,
+// starting from 1, and are never updated or removed
+type Log struct {
+	db  storage.SystemStore
+	now func() time.Time
+
+	// next is the ID of the next appended entry
+	next uint64
+
+	mux sync.Mutex
+}
+
+// New creates a new audit log, appending to the saved entries
+func New(db storage.SystemStore) (*Log, error) {
+	l := &Log{
+		db:   db,
+		now:  time.Now,
+		next: 1,
+	}
+
+	raw, err := db.Get([]byte(keyNextID))
+	if err != nil && !errors.Is(err, storageErrors.ErrNotFound) {
+		return nil, fmt.Errorf("unable to fetch next audit log ID, %w", err)
+	}
+
+	if err == nil {
+		l.next = binary.BigEndian.Uint64(raw)
+	}
+
+	return l, nil
+}
+
+// Append appends the entry to the log, setting its ID and time
+func (l *Log) Append(entry *Entry) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	entry.ID = l.next
+	entry.Time = l.now().UTC()
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("unable to encode audit log entry, %w", err)
+	}
+
+	wb := l.db.WriteBatch()
+
+	if err := wb.Set(entryKey(entry.ID), raw); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	if err := wb.Set([]byte(keyNextID), binary.BigEndian.AppendUint64(nil, entry.ID+1)); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	if err := wb.Commit(); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	l.next++
+
+	return wb.Rollback()
+}
+
+// Entries returns a page of the log entries, newest first, optionally
+// of the given method. The cursor is returned with the previous page, if any
+func (l *Log) Entries(method, cursor string, limit int) (*Page, error) {
+	l.mux.Lock()
+	before := l.next
+	l.mux.Unlock()
+
+	if cursor != "" {
+		id, err := cursors.DecodeUint64(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if id == 0 {
+			return nil, cursors.ErrInvalid
+		}
+
+		before = min(before, id)
+	}
+
+	page := &Page{
+		Entries: make([]*Entry, 0),
+	}
+
+	// The entries are read in chunks, from the newest ones
+	for to := before; to > 1; {
+		from := uint64(1)
+		if to > readChunk+1 {
+			from = to - readChunk
+		}
+
+		entries, err := l.read(from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+
+			if method != "" && entry.Method != method {
+				continue
+			}
+
+			if len(page.Entries) == limit {
+				page.Cursor = cursors.EncodeUint64(page.Entries[limit-1].ID)
+
+				return page, nil
+			}
+
+			page.Entries = append(page.Entries, entry)
+		}
+
+		to = from
+	}
+
+	return page, nil
+}
+
+// read reads the entries with the IDs in the range [from, to)
+func (l *Log) read(from, to uint64) ([]*Entry, error) {
+	it, err := l.db.Iterator(entryKey(from), entryKey(to))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate audit log, %w", err)
+	}
+
+	defer it.Close()
+
+	entries := make([]*Entry, 0, to-from)
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(kv.Value, &entry); err != nil {
+			return nil, fmt.Errorf("unable to decode audit log entry, %w", err)
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, it.Error()
+}
+
+// entryKey returns the key of the entry with the ID
+func entryKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(prefixEntry), id)
+}
@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+func TestLog_Entries(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	l, err := New(db.System(Component))
+	require.NoError(t, err)
+
+	// Append the entries, over a few read chunks
+	total := 2*readChunk + 10
+
+	for i := 0; i < total; i++ {
+		method := "admin_createApiKey"
+		if i%2 == 1 {
+			method = "admin_deleteApiKey"
+		}
+
+		require.NoError(t, l.Append(&Entry{
+			Method: method,
+			Params: json.RawMessage(fmt.Sprintf(`["key-%d"]`, i)),
+		}))
+	}
+
+	// Make sure the appends continue after a restart
+	l, err = New(db.System(Component))
+	require.NoError(t, err)
+
+	require.NoError(t, l.Append(&Entry{Method: "admin_getAuditLog"}))
+
+	total++
+
+	t.Run("all entries", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ids    = make([]uint64, 0, total)
+			cursor = ""
+		)
+
+		for {
+			page, err := l.Entries("", cursor, 100)
+			require.NoError(t, err)
+
+			for _, entry := range page.Entries {
+				ids = append(ids, entry.ID)
+			}
+
+			if page.Cursor == "" {
+				break
+			}
+
+			cursor = page.Cursor
+		}
+
+		require.Len(t, ids, total)
+
+		// Make sure the entries are in the newest first order
+		for i, id := range ids {
+			assert.Equal(t, uint64(total-i), id)
+		}
+	})
+
+	t.Run("method entries", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := l.Entries("admin_deleteApiKey", "", 1000)
+		require.NoError(t, err)
+
+		assert.Empty(t, page.Cursor)
+		require.Len(t, page.Entries, (total-1)/2)
+
+		for _, entry := range page.Entries {
+			assert.Equal(t, "admin_deleteApiKey", entry.Method)
+			assert.Zero(t, entry.ID%2)
+		}
+
+		assert.JSONEq(t, fmt.Sprintf(`["key-%d"]`, total-2), string(page.Entries[0].Params))
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := l.Entries("", "cursor", 10)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+}
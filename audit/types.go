@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entry is a single audit log entry, of a handled admin method call
+type Entry struct {
+	// Time is the time the call was handled
+	Time time.Time `json:"time"`
+
+	// Params are the (JSON encoded) call params
+	Params json.RawMessage `json:"params"`
+
+	// Method is the admin method
+	Method string `json:"method"`
+
+	// Actor is the operator name of the X-Admin-Actor header, if any
+	Actor string `json:"actor,omitempty"`
+
+	// Remote is the remote address of the caller
+	Remote string `json:"remote"`
+
+	// ClientIP is the (resolved) client IP of the caller
+	ClientIP string `json:"clientIP"`
+
+	// UserAgent is the user agent of the caller, if any
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// Error is the message of the call error, if it failed
+	Error string `json:"error,omitempty"`
+
+	// ID is the sequential entry ID
+	ID uint64 `json:"id"`
+
+	// Code is the code of the call error, if it failed
+	Code int `json:"code,omitempty"`
+}
+
+// Page is a page of the audit log entries, newest first
+type Page struct {
+	// Cursor is the cursor of the next page, if there is one
+	Cursor string `json:"cursor,omitempty"`
+
+	Entries []*Entry `json:"entries"`
+}
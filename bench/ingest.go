@@ -0,0 +1,167 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	// DefaultIngestBlocks is the default number of ingested synthetic blocks
+	DefaultIngestBlocks = 10_000
+
+	// DefaultTxsPerBlock is the default number of txs of each synthetic block
+	DefaultTxsPerBlock = 10
+
+	// DefaultBatchSize is the default number of blocks written in a single storage batch
+	DefaultBatchSize = 1000
+)
+
+var errInvalidIngest = errors.New("the ingested blocks and batch size must be positive")
+
+// IngestReport is the result of the synthetic chain ingestion
+type IngestReport struct {
+	// Blocks and Txs are the number of saved blocks and txs
+	Blocks int
+	Txs    int
+
+	// Duration is the total save time, excluding the chain generation
+	Duration time.Duration
+}
+
+// BlocksPerSecond returns the block save throughput
+func (r *IngestReport) BlocksPerSecond() float64 {
+	return float64(r.Blocks) / r.Duration.Seconds()
+}
+
+// TxsPerSecond returns the tx save throughput
+func (r *IngestReport) TxsPerSecond() float64 {
+	return float64(r.Txs) / r.Duration.Seconds()
+}
+
+// Ingest saves a synthetic chain of the given number of blocks (each with the given number of transfer txs)
+// into the storage, in batches of the given number of blocks, and measures the save throughput.
+// The blocks are saved from height 1, along with the latest height
+func Ingest(ctx context.Context, s Storage, blocks, txsPerBlock, batchSize int) (*IngestReport, error) {
+	if blocks <= 0 || batchSize <= 0 {
+		return nil, errInvalidIngest
+	}
+
+	report := &IngestReport{}
+
+	for from := 1; from <= blocks; from += batchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		to := min(from+batchSize-1, blocks)
+
+		// Generate the batch chain data up front, so only the saves are measured
+		chainBlocks, chainTxs := generateChain(from, to, txsPerBlock)
+
+		start := time.Now()
+
+		if err := saveChain(s, chainBlocks, chainTxs); err != nil {
+			return nil, fmt.Errorf("unable to save heights %d-%d, %w", from, to, err)
+		}
+
+		report.Duration += time.Since(start)
+		report.Blocks += len(chainBlocks)
+		report.Txs += len(chainTxs)
+	}
+
+	return report, nil
+}
+
+// saveChain saves the blocks and txs in a single storage batch
+func saveChain(s Storage, blocks []*types.Block, txs []*types.TxResult) error {
+	wb := s.WriteBatch()
+
+	for _, block := range blocks {
+		if err := wb.SetBlock(block); err != nil {
+			return errors.Join(fmt.Errorf("unable to save block, %w", err), wb.Rollback())
+		}
+	}
+
+	for _, tx := range txs {
+		if err := wb.SetTx(tx); err != nil {
+			return errors.Join(fmt.Errorf("unable to save tx, %w", err), wb.Rollback())
+		}
+	}
+
+	if err := wb.SetLatestHeight(uint64(blocks[len(blocks)-1].Height)); err != nil {
+		return errors.Join(fmt.Errorf("unable to save latest height, %w", err), wb.Rollback())
+	}
+
+	if err := wb.Commit(); err != nil {
+		return errors.Join(fmt.Errorf("unable to commit batch, %w", err), wb.Rollback())
+	}
+
+	return wb.Rollback()
+}
+
+// generateChain generates the synthetic blocks of the height range,
+// each with the given number of transfer txs between distinct addresses
+func generateChain(from, to, txsPerBlock int) ([]*types.Block, []*types.TxResult) {
+	var (
+		blocks = make([]*types.Block, 0, to-from+1)
+		txs    = make([]*types.TxResult, 0, (to-from+1)*txsPerBlock)
+	)
+
+	for height := from; height <= to; height++ {
+		blockTxs := make(types.Txs, 0, txsPerBlock)
+
+		for index := 0; index < txsPerBlock; index++ {
+			tx := amino.MustMarshal(&std.Tx{
+				Msgs: []std.Msg{
+					bank.MsgSend{
+						FromAddress: syntheticAddress(height + index),
+						ToAddress:   syntheticAddress(height + index + 1),
+						Amount:      std.NewCoins(std.NewCoin("ugnot", int64(height))),
+					},
+				},
+				Fee:  std.NewFee(100_000, std.NewCoin("ugnot", 1_000)),
+				Memo: fmt.Sprintf("bench tx %d-%d", height, index),
+			})
+
+			blockTxs = append(blockTxs, tx)
+
+			txs = append(txs, &types.TxResult{
+				Height: int64(height),
+				Index:  uint32(index),
+				Tx:     tx,
+			})
+		}
+
+		blocks = append(blocks, &types.Block{
+			Header: types.Header{
+				ChainID:  "bench",
+				Height:   int64(height),
+				Time:     time.Unix(int64(height), 0).UTC(),
+				NumTxs:   int64(txsPerBlock),
+				TotalTxs: int64(height * txsPerBlock),
+			},
+			Data: types.Data{
+				Txs: blockTxs,
+			},
+		})
+	}
+
+	return blocks, txs
+}
+
+// syntheticAddress returns one of the synthetic chain addresses
+func syntheticAddress(seed int) crypto.Address {
+	var address crypto.Address
+
+	address[0] = byte(seed % 100)
+
+	return address
+}
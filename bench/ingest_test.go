@@ -0,0 +1,97 @@
+package bench
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+func TestIngest(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blocks      = 25
+		txsPerBlock = 3
+
+		s = &mockStorage{}
+	)
+
+	report, err := Ingest(context.Background(), s, blocks, txsPerBlock, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, blocks, report.Blocks)
+	assert.Equal(t, blocks*txsPerBlock, report.Txs)
+	assert.Positive(t, report.BlocksPerSecond())
+
+	// Make sure the synthetic chain is saved in order
+	require.Len(t, s.blocks, blocks)
+	require.Len(t, s.txs, blocks*txsPerBlock)
+
+	for index, block := range s.blocks {
+		assert.Equal(t, int64(index+1), block.Height)
+		assert.Len(t, block.Txs, txsPerBlock)
+	}
+
+	for index, tx := range s.txs {
+		assert.Equal(t, int64(index/txsPerBlock+1), tx.Height)
+		assert.Equal(t, uint32(index%txsPerBlock), tx.Index)
+	}
+
+	assert.Equal(t, uint64(blocks), s.latest)
+}
+
+func TestIngest_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Ingest(context.Background(), &mockStorage{}, 0, DefaultTxsPerBlock, DefaultBatchSize)
+	assert.ErrorIs(t, err, errInvalidIngest)
+
+	_, err = Ingest(context.Background(), &mockStorage{}, DefaultIngestBlocks, DefaultTxsPerBlock, 0)
+	assert.ErrorIs(t, err, errInvalidIngest)
+}
+
+func TestIngest_Pebble(t *testing.T) {
+	t.Parallel()
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	_, err = Ingest(context.Background(), s, 20, 2, 8)
+	require.NoError(t, err)
+
+	latest, err := s.GetLatestHeight()
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(20), latest)
+
+	tx, err := s.GetTx(20, 1)
+	require.NoError(t, err)
+
+	// Make sure the synthetic txs are indexed by address
+	it, err := s.TxByAddressIterator(syntheticAddress(21).String(), 0, 0)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, it.Close())
+	}()
+
+	var found bool
+
+	for it.Next() {
+		indexed, err := it.Value()
+		require.NoError(t, err)
+
+		found = found || indexed.Height == tx.Height && indexed.Index == tx.Index
+	}
+
+	require.NoError(t, it.Error())
+	assert.True(t, found)
+}
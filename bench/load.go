@@ -0,0 +1,218 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// DefaultConcurrency is the default number of concurrent load clients
+	DefaultConcurrency = 10
+
+	// DefaultLoadDuration is the default load duration
+	DefaultLoadDuration = 30 * time.Second
+)
+
+var errNoRequests = errors.New("no load requests")
+
+// Request is a JSON-RPC request sent to the indexer
+type Request struct {
+	Method string
+	Params []any
+}
+
+// LoadReport is the result of the RPC load
+type LoadReport struct {
+	// Errors is the number of failed requests, including the JSON-RPC errors
+	Errors int
+
+	// Duration is the total load time
+	Duration time.Duration
+
+	// latencies are the sorted request latencies
+	latencies []time.Duration
+}
+
+// Requests returns the number of sent requests
+func (r *LoadReport) Requests() int {
+	return len(r.latencies)
+}
+
+// RequestsPerSecond returns the request throughput
+func (r *LoadReport) RequestsPerSecond() float64 {
+	return float64(len(r.latencies)) / r.Duration.Seconds()
+}
+
+// Percentile returns the request latency percentile, in the (0, 100] range
+func (r *LoadReport) Percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+
+	// Nearest rank
+	rank := int(p/100*float64(len(r.latencies))+0.5) - 1
+
+	return r.latencies[min(max(rank, 0), len(r.latencies)-1)]
+}
+
+// Loader generates JSON-RPC load against a running indexer,
+// cycling through the given requests from concurrent clients
+type Loader struct {
+	client *http.Client
+
+	url      string
+	requests []Request
+
+	concurrency int
+	duration    time.Duration
+	maxRequests int
+}
+
+// NewLoader creates a new RPC loader of the indexer JSON-RPC URL
+func NewLoader(url string, requests []Request, opts ...Option) *Loader {
+	l := &Loader{
+		client:      http.DefaultClient,
+		url:         url,
+		requests:    requests,
+		concurrency: DefaultConcurrency,
+		duration:    DefaultLoadDuration,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Run sends the requests until the load duration passes
+// (or the maximum number of requests is sent), measuring their latencies
+func (l *Loader) Run(ctx context.Context) (*LoadReport, error) {
+	if len(l.requests) == 0 {
+		return nil, errNoRequests
+	}
+
+	ctx, cancelFn := context.WithTimeout(ctx, l.duration)
+	defer cancelFn()
+
+	var (
+		report = &LoadReport{}
+
+		sent int
+		mux  sync.Mutex
+		wg   sync.WaitGroup
+	)
+
+	// next claims the next request to send, if any
+	next := func() (Request, bool) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		if ctx.Err() != nil || (l.maxRequests > 0 && sent >= l.maxRequests) {
+			return Request{}, false
+		}
+
+		req := l.requests[sent%len(l.requests)]
+		sent++
+
+		return req, true
+	}
+
+	start := time.Now()
+
+	for i := 0; i < max(l.concurrency, 1); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				req, ok := next()
+				if !ok {
+					return
+				}
+
+				callStart := time.Now()
+				err := l.call(ctx, req)
+				latency := time.Since(callStart)
+
+				// The requests interrupted by the load end are not measured
+				if ctx.Err() != nil {
+					return
+				}
+
+				mux.Lock()
+				report.latencies = append(report.latencies, latency)
+
+				if err != nil {
+					report.Errors++
+				}
+				mux.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+
+	sort.Slice(report.latencies, func(i, j int) bool {
+		return report.latencies[i] < report.latencies[j]
+	})
+
+	return report, nil
+}
+
+// call sends the request, failing on the JSON-RPC errors
+func (l *Loader) call(ctx context.Context, req Request) error {
+	params := req.Params
+	if params == nil {
+		params = []any{}
+	}
+
+	body, err := json.Marshal(spec.NewJSONRequest(1, req.Method, params))
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, l.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := l.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status, %s", httpResp.Status)
+	}
+
+	var response struct {
+		Error *spec.BaseJSONError `json:"error"`
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("unable to decode response, %w", err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("%s (code %d)", response.Error.Message, response.Error.Code)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// newIndexer starts a JSON-RPC server failing the given method,
+// and recording the received methods
+func newIndexer(t *testing.T, failingMethod string) (string, func() []string) {
+	t.Helper()
+
+	var (
+		methods []string
+		mux     sync.Mutex
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req *spec.BaseJSONRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		mux.Lock()
+		methods = append(methods, req.Method)
+		mux.Unlock()
+
+		var jsonErr *spec.BaseJSONError
+
+		if req.Method == failingMethod {
+			jsonErr = spec.NewJSONError("failed", spec.ServerErrorCode)
+		}
+
+		_ = json.NewEncoder(w).Encode(spec.NewJSONResponse(req.ID, "result", jsonErr))
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv.URL, func() []string {
+		mux.Lock()
+		defer mux.Unlock()
+
+		return methods
+	}
+}
+
+func TestLoader_Run(t *testing.T) {
+	t.Parallel()
+
+	url, methods := newIndexer(t, "getTxResult")
+
+	l := NewLoader(
+		url,
+		[]Request{
+			{Method: "getBlock", Params: []any{1}},
+			{Method: "getTxResult", Params: []any{1, 0}},
+		},
+		WithConcurrency(4),
+		WithMaxRequests(20),
+		WithDuration(10*time.Second),
+	)
+
+	report, err := l.Run(context.Background())
+	require.NoError(t, err)
+
+	// Make sure the requests are cycled through
+	require.Len(t, methods(), 20)
+
+	assert.Equal(t, 20, report.Requests())
+	assert.Equal(t, 10, report.Errors)
+	assert.Positive(t, report.RequestsPerSecond())
+
+	// Make sure the percentiles are ordered
+	assert.Positive(t, report.Percentile(50))
+	assert.LessOrEqual(t, report.Percentile(50), report.Percentile(99))
+	assert.LessOrEqual(t, report.Percentile(99), report.Percentile(100))
+}
+
+func TestLoader_Duration(t *testing.T) {
+	t.Parallel()
+
+	url, methods := newIndexer(t, "")
+
+	l := NewLoader(
+		url,
+		[]Request{{Method: "getStatus"}},
+		WithConcurrency(2),
+		WithDuration(200*time.Millisecond),
+	)
+
+	report, err := l.Run(context.Background())
+	require.NoError(t, err)
+
+	// Make sure the load ends once the duration passes
+	assert.Less(t, report.Duration, 2*time.Second)
+	assert.NotEmpty(t, methods())
+	assert.Zero(t, report.Errors)
+}
+
+func TestLoader_NoRequests(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewLoader("http://127.0.0.1", nil).Run(context.Background())
+	assert.ErrorIs(t, err, errNoRequests)
+}
+
+func TestLoadReport_Percentile(t *testing.T) {
+	t.Parallel()
+
+	latencies := make([]time.Duration, 0, 100)
+
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	report := &LoadReport{latencies: latencies}
+
+	assert.Equal(t, 50*time.Millisecond, report.Percentile(50))
+	assert.Equal(t, 99*time.Millisecond, report.Percentile(99))
+	assert.Equal(t, 100*time.Millisecond, report.Percentile(100))
+	assert.Equal(t, time.Millisecond, report.Percentile(0.1))
+
+	assert.Zero(t, (&LoadReport{}).Percentile(50))
+}
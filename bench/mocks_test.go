@@ -0,0 +1,65 @@
+package bench
+
+import (
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// mockStorage records the committed blocks and txs
+type mockStorage struct {
+	blocks []*types.Block
+	txs    []*types.TxResult
+	latest uint64
+
+	mux sync.Mutex
+}
+
+func (m *mockStorage) WriteBatch() storage.Batch {
+	return &mockBatch{s: m}
+}
+
+type mockBatch struct {
+	storage.Batch
+
+	s *mockStorage
+
+	blocks []*types.Block
+	txs    []*types.TxResult
+	latest uint64
+}
+
+func (m *mockBatch) SetBlock(block *types.Block) error {
+	m.blocks = append(m.blocks, block)
+
+	return nil
+}
+
+func (m *mockBatch) SetTx(tx *types.TxResult) error {
+	m.txs = append(m.txs, tx)
+
+	return nil
+}
+
+func (m *mockBatch) SetLatestHeight(height uint64) error {
+	m.latest = height
+
+	return nil
+}
+
+func (m *mockBatch) Commit() error {
+	m.s.mux.Lock()
+	defer m.s.mux.Unlock()
+
+	m.s.blocks = append(m.s.blocks, m.blocks...)
+	m.s.txs = append(m.s.txs, m.txs...)
+	m.s.latest = m.latest
+
+	return nil
+}
+
+func (m *mockBatch) Rollback() error {
+	return nil
+}
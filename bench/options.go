@@ -0,0 +1,37 @@
+package bench
+
+import (
+	"net/http"
+	"time"
+)
+
+type Option func(l *Loader)
+
+// WithConcurrency sets the number of concurrent load clients
+func WithConcurrency(concurrency int) Option {
+	return func(l *Loader) {
+		l.concurrency = concurrency
+	}
+}
+
+// WithDuration sets the load duration
+func WithDuration(duration time.Duration) Option {
+	return func(l *Loader) {
+		l.duration = duration
+	}
+}
+
+// WithMaxRequests sets the maximum number of sent requests,
+// ending the load before its duration. Unlimited by default
+func WithMaxRequests(maxRequests int) Option {
+	return func(l *Loader) {
+		l.maxRequests = maxRequests
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the load requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(l *Loader) {
+		l.client = client
+	}
+}
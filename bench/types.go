@@ -0,0 +1,9 @@
+package bench
+
+import "github.com/gnolang/tx-indexer/storage"
+
+// Storage is the storage the synthetic chain is ingested into
+type Storage interface {
+	// WriteBatch provides a batch for writing data to the storage
+	WriteBatch() storage.Batch
+}
@@ -0,0 +1,312 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"golang.org/x/sync/errgroup"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// DefaultMaxSlots is the default number of heights checked concurrently
+const DefaultMaxSlots = 10
+
+var errNoData = errors.New("no indexed data to check")
+
+// Divergence is a difference between the indexed and the node data of a height
+type Divergence struct {
+	// Reason describes the difference
+	Reason string
+
+	// Height is the diverging height
+	Height uint64
+}
+
+// Report is the result of the consistency check
+type Report struct {
+	// Divergences are the found divergences, ordered by height
+	Divergences []Divergence
+
+	// From and To are the checked height range
+	From uint64
+	To   uint64
+
+	// Checked is the number of checked (sampled) heights
+	Checked int
+}
+
+// Checker cross-verifies the indexed blocks and tx results with the node
+type Checker struct {
+	storage Storage
+	client  Client
+
+	from       uint64
+	to         uint64
+	sampleRate float64
+	maxSlots   int
+	seed       int64
+}
+
+// New creates a new consistency checker
+func New(storage Storage, client Client, opts ...Option) *Checker {
+	c := &Checker{
+		storage:    storage,
+		client:     client,
+		sampleRate: 1,
+		maxSlots:   DefaultMaxSlots,
+		seed:       time.Now().UnixNano(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Run checks the sampled heights of the range, reporting the divergences.
+// The node errors stop the check
+func (c *Checker) Run(ctx context.Context) (*Report, error) {
+	from, to, err := c.resolveRange()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		report = &Report{
+			Divergences: make([]Divergence, 0),
+			From:        from,
+			To:          to,
+		}
+
+		heights = make(chan uint64)
+		mux     sync.Mutex
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	// Sample the heights
+	g.Go(func() error {
+		defer close(heights)
+
+		rng := rand.New(rand.NewSource(c.seed)) //nolint:gosec // Sampling doesn't need a secure source
+
+		for height := from; height <= to; height++ {
+			if c.sampleRate < 1 && rng.Float64() >= c.sampleRate {
+				continue
+			}
+
+			select {
+			case <-gCtx.Done():
+				return gCtx.Err()
+			case heights <- height:
+			}
+		}
+
+		return nil
+	})
+
+	for i := 0; i < max(c.maxSlots, 1); i++ {
+		g.Go(func() error {
+			for height := range heights {
+				divergences, err := c.checkHeight(height)
+				if err != nil {
+					return err
+				}
+
+				mux.Lock()
+				report.Checked++
+				report.Divergences = append(report.Divergences, divergences...)
+				mux.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(report.Divergences, func(i, j int) bool {
+		return report.Divergences[i].Height < report.Divergences[j].Height
+	})
+
+	return report, nil
+}
+
+// resolveRange resolves the checked height range,
+// capped at the indexed heights
+func (c *Checker) resolveRange() (uint64, uint64, error) {
+	latest, err := c.storage.GetLatestHeight()
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, 0, errNoData
+	}
+
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	to := latest
+	if c.to != 0 {
+		to = min(c.to, latest)
+	}
+
+	if c.from != 0 {
+		return c.from, to, nil
+	}
+
+	// The indexing might not have started from genesis
+	it, err := c.storage.BlockIterator(0, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to iterate blocks, %w", err)
+	}
+
+	defer it.Close()
+
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return 0, 0, fmt.Errorf("unable to iterate blocks, %w", err)
+		}
+
+		return 0, 0, errNoData
+	}
+
+	first, err := it.Value()
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to read first block, %w", err)
+	}
+
+	return uint64(first.Height), to, nil
+}
+
+// checkHeight compares the indexed block and tx results of the height with the node ones
+func (c *Checker) checkHeight(height uint64) ([]Divergence, error) {
+	remote, err := c.client.GetBlock(height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch block %d from the node, %w", height, err)
+	}
+
+	var (
+		divergences = make([]Divergence, 0)
+
+		diverge = func(format string, args ...any) {
+			divergences = append(divergences, Divergence{
+				Height: height,
+				Reason: fmt.Sprintf(format, args...),
+			})
+		}
+	)
+
+	block, err := c.storage.GetBlock(height)
+
+	switch {
+	case errors.Is(err, storageErrors.ErrNotFound):
+		diverge("block missing from storage")
+
+		return divergences, nil
+	case err != nil:
+		return nil, fmt.Errorf("unable to fetch block %d from storage, %w", height, err)
+	}
+
+	if fields := headerDiff(&block.Header, &remote.Block.Header); len(fields) > 0 {
+		diverge("block header differs (%s)", strings.Join(fields, ", "))
+	}
+
+	var results []abci.ResponseDeliverTx
+
+	if len(remote.Block.Txs) > 0 {
+		blockResults, err := c.client.GetBlockResults(height)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch block %d results from the node, %w", height, err)
+		}
+
+		results = blockResults.Results.DeliverTxs
+	}
+
+	for index, remoteTx := range remote.Block.Txs {
+		tx, err := c.storage.GetTx(height, uint32(index))
+
+		switch {
+		case errors.Is(err, storageErrors.ErrNotFound):
+			diverge("tx %d missing from storage", index)
+
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("unable to fetch tx %d-%d from storage, %w", height, index, err)
+		}
+
+		if !bytes.Equal(tx.Tx, remoteTx) {
+			diverge("tx %d differs", index)
+		}
+
+		if index < len(results) && !sameResponse(&tx.Response, &results[index]) {
+			diverge("tx %d result differs", index)
+		}
+	}
+
+	// Make sure there are no extra txs indexed for the height
+	_, err = c.storage.GetTx(height, uint32(len(remote.Block.Txs)))
+
+	switch {
+	case err == nil:
+		diverge("unexpected tx %d in storage", len(remote.Block.Txs))
+	case !errors.Is(err, storageErrors.ErrNotFound):
+		return nil, fmt.Errorf("unable to fetch tx %d-%d from storage, %w", height, len(remote.Block.Txs), err)
+	}
+
+	return divergences, nil
+}
+
+// headerDiff returns the names of the differing header fields
+func headerDiff(a, b *types.Header) []string {
+	fields := make([]string, 0)
+
+	diff := func(name string, equal bool) {
+		if !equal {
+			fields = append(fields, name)
+		}
+	}
+
+	diff("chain ID", a.ChainID == b.ChainID)
+	diff("height", a.Height == b.Height)
+	diff("time", a.Time.Equal(b.Time))
+	diff("tx count", a.NumTxs == b.NumTxs && a.TotalTxs == b.TotalTxs)
+	diff("last block ID", bytes.Equal(a.LastBlockID.Hash, b.LastBlockID.Hash))
+	diff("data hash", bytes.Equal(a.DataHash, b.DataHash))
+	diff("validators hash", bytes.Equal(a.ValidatorsHash, b.ValidatorsHash))
+	diff("app hash", bytes.Equal(a.AppHash, b.AppHash))
+	diff("results hash", bytes.Equal(a.LastResultsHash, b.LastResultsHash))
+	diff("proposer", a.ProposerAddress == b.ProposerAddress)
+
+	return fields
+}
+
+// sameResponse returns a flag indicating if the tx responses match
+func sameResponse(a, b *abci.ResponseDeliverTx) bool {
+	return bytes.Equal(a.Data, b.Data) &&
+		a.Log == b.Log &&
+		a.Info == b.Info &&
+		a.GasWanted == b.GasWanted &&
+		a.GasUsed == b.GasUsed &&
+		len(a.Events) == len(b.Events) &&
+		errorMessage(a.Error) == errorMessage(b.Error)
+}
+
+func errorMessage(err abci.Error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,294 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// generateChain generates the node blocks and their results,
+// with each block containing 2 txs
+func generateChain(count int) ([]*types.Block, []*core_types.ResultBlockResults) {
+	var (
+		blocks  = make([]*types.Block, 0, count)
+		results = make([]*core_types.ResultBlockResults, 0, count)
+	)
+
+	for height := 1; height <= count; height++ {
+		block := &types.Block{
+			Header: types.Header{
+				ChainID: "dev",
+				Height:  int64(height),
+				NumTxs:  2,
+				AppHash: []byte(fmt.Sprintf("app hash %d", height)),
+			},
+			Data: types.Data{
+				Txs: types.Txs{
+					[]byte(fmt.Sprintf("tx %d-0", height)),
+					[]byte(fmt.Sprintf("tx %d-1", height)),
+				},
+			},
+		}
+
+		deliverTxs := make([]abci.ResponseDeliverTx, 0, 2)
+
+		for index := 0; index < 2; index++ {
+			deliverTxs = append(deliverTxs, abci.ResponseDeliverTx{
+				GasWanted: 100,
+				GasUsed:   int64(height*10 + index),
+			})
+		}
+
+		blocks = append(blocks, block)
+		results = append(results, &core_types.ResultBlockResults{
+			Height:  int64(height),
+			Results: &state.ABCIResponses{DeliverTxs: deliverTxs},
+		})
+	}
+
+	return blocks, results
+}
+
+// newNode creates the mock node client serving the chain
+func newNode(blocks []*types.Block, results []*core_types.ResultBlockResults) *mockClient {
+	return &mockClient{
+		getBlockFn: func(height uint64) (*core_types.ResultBlock, error) {
+			return &core_types.ResultBlock{Block: blocks[height-1]}, nil
+		},
+		getBlockResultsFn: func(height uint64) (*core_types.ResultBlockResults, error) {
+			return results[height-1], nil
+		},
+	}
+}
+
+// saveChain saves the blocks and txs to the storage,
+// skipping the given heights and txs
+func saveChain(
+	t *testing.T,
+	s storage.Storage,
+	blocks []*types.Block,
+	results []*core_types.ResultBlockResults,
+	skip func(height int64, index int) bool,
+) {
+	t.Helper()
+
+	wb := s.WriteBatch()
+
+	for i, block := range blocks {
+		if skip(block.Height, -1) {
+			continue
+		}
+
+		require.NoError(t, wb.SetBlock(block))
+
+		for index, tx := range block.Txs {
+			if skip(block.Height, index) {
+				continue
+			}
+
+			require.NoError(t, wb.SetTx(&types.TxResult{
+				Height:   block.Height,
+				Index:    uint32(index),
+				Tx:       tx,
+				Response: results[i].Results.DeliverTxs[index],
+			}))
+		}
+	}
+
+	require.NoError(t, wb.SetLatestHeight(uint64(blocks[len(blocks)-1].Height)))
+	require.NoError(t, wb.Commit())
+}
+
+func newStorage(t *testing.T) *storage.Pebble {
+	t.Helper()
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	return s
+}
+
+func TestChecker_Divergences(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blocks, results = generateChain(10)
+		s               = newStorage(t)
+	)
+
+	saveChain(t, s, blocks, results, func(height int64, index int) bool {
+		return height == 8 || (height == 5 && index == 1)
+	})
+
+	// Diverge from the indexed data
+	nodeBlocks, nodeResults := generateChain(10)
+
+	nodeBlocks[2].AppHash = []byte("forked")
+	nodeResults[6].Results.DeliverTxs[0].GasUsed = 0
+
+	report, err := New(s, newNode(nodeBlocks, nodeResults)).Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), report.From)
+	assert.Equal(t, uint64(10), report.To)
+	assert.Equal(t, 10, report.Checked)
+
+	assert.Equal(
+		t,
+		[]Divergence{
+			{Height: 3, Reason: "block header differs (app hash)"},
+			{Height: 5, Reason: "tx 1 missing from storage"},
+			{Height: 7, Reason: "tx 0 result differs"},
+			{Height: 8, Reason: "block missing from storage"},
+		},
+		report.Divergences,
+	)
+}
+
+func TestChecker_Consistent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blocks, results = generateChain(10)
+		s               = newStorage(t)
+	)
+
+	// The indexing started from height 4
+	saveChain(t, s, blocks, results, func(height int64, _ int) bool {
+		return height < 4
+	})
+
+	report, err := New(s, newNode(blocks, results)).Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(4), report.From)
+	assert.Equal(t, 7, report.Checked)
+	assert.Empty(t, report.Divergences)
+}
+
+func TestChecker_Sample(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blocks, results = generateChain(100)
+		s               = newStorage(t)
+	)
+
+	saveChain(t, s, blocks, results, func(_ int64, _ int) bool {
+		return false
+	})
+
+	run := func() *Report {
+		report, err := New(
+			s,
+			newNode(blocks, results),
+			WithSampleRate(0.2),
+			WithSeed(42),
+		).Run(context.Background())
+		require.NoError(t, err)
+
+		return report
+	}
+
+	report := run()
+
+	assert.Greater(t, report.Checked, 0)
+	assert.Less(t, report.Checked, 100)
+
+	// Make sure the sampling follows the seed
+	assert.Equal(t, report.Checked, run().Checked)
+}
+
+func TestChecker_Range(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blocks, results = generateChain(10)
+		s               = newStorage(t)
+
+		checked = make(chan uint64, 10)
+		node    = newNode(blocks, results)
+	)
+
+	saveChain(t, s, blocks, results, func(_ int64, _ int) bool {
+		return false
+	})
+
+	getBlockFn := node.getBlockFn
+	node.getBlockFn = func(height uint64) (*core_types.ResultBlock, error) {
+		checked <- height
+
+		return getBlockFn(height)
+	}
+
+	// The range is capped at the latest height
+	report, err := New(s, node, WithRange(8, 20)).Run(context.Background())
+	require.NoError(t, err)
+
+	close(checked)
+
+	assert.Equal(t, uint64(8), report.From)
+	assert.Equal(t, uint64(10), report.To)
+	assert.Equal(t, 3, report.Checked)
+
+	assert.ElementsMatch(t, []uint64{8, 9, 10}, collect(checked))
+}
+
+func TestChecker_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty storage", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(newStorage(t), &mockClient{}).Run(context.Background())
+		assert.ErrorIs(t, err, errNoData)
+	})
+
+	t.Run("node error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			blocks, results = generateChain(10)
+			s               = newStorage(t)
+
+			nodeErr = errors.New("node error")
+		)
+
+		saveChain(t, s, blocks, results, func(_ int64, _ int) bool {
+			return false
+		})
+
+		node := &mockClient{
+			getBlockFn: func(_ uint64) (*core_types.ResultBlock, error) {
+				return nil, nodeErr
+			},
+		}
+
+		_, err := New(s, node).Run(context.Background())
+		assert.ErrorIs(t, err, nodeErr)
+	})
+}
+
+func collect(ch <-chan uint64) []uint64 {
+	values := make([]uint64, 0)
+
+	for value := range ch {
+		values = append(values, value)
+	}
+
+	return values
+}
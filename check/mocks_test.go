@@ -0,0 +1,31 @@
+package check
+
+import (
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+)
+
+type (
+	getBlockDelegate        func(uint64) (*core_types.ResultBlock, error)
+	getBlockResultsDelegate func(uint64) (*core_types.ResultBlockResults, error)
+)
+
+type mockClient struct {
+	getBlockFn        getBlockDelegate
+	getBlockResultsFn getBlockResultsDelegate
+}
+
+func (m *mockClient) GetBlock(blockNum uint64) (*core_types.ResultBlock, error) {
+	if m.getBlockFn != nil {
+		return m.getBlockFn(blockNum)
+	}
+
+	return nil, nil
+}
+
+func (m *mockClient) GetBlockResults(blockNum uint64) (*core_types.ResultBlockResults, error) {
+	if m.getBlockResultsFn != nil {
+		return m.getBlockResultsFn(blockNum)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,34 @@
+package check
+
+type Option func(c *Checker)
+
+// WithSampleRate sets the fraction of the heights checked,
+// picked at random. All heights are checked by default
+func WithSampleRate(rate float64) Option {
+	return func(c *Checker) {
+		c.sampleRate = rate
+	}
+}
+
+// WithRange sets the checked height range. The range starts from
+// the first saved height, and ends at the latest saved height by default
+func WithRange(from, to uint64) Option {
+	return func(c *Checker) {
+		c.from = from
+		c.to = to
+	}
+}
+
+// WithMaxSlots sets the number of heights checked concurrently
+func WithMaxSlots(maxSlots int) Option {
+	return func(c *Checker) {
+		c.maxSlots = maxSlots
+	}
+}
+
+// WithSeed sets the seed of the height sampling
+func WithSeed(seed int64) Option {
+	return func(c *Checker) {
+		c.seed = seed
+	}
+}
@@ -0,0 +1,33 @@
+package check
+
+import (
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Storage is the indexer storage being checked
+type Storage interface {
+	// GetLatestHeight returns the latest saved height
+	GetLatestHeight() (uint64, error)
+
+	// GetBlock fetches the block by its number
+	GetBlock(uint64) (*types.Block, error)
+
+	// GetTx fetches the tx using the block height and the transaction index
+	GetTx(blockNum uint64, index uint32) (*types.TxResult, error)
+
+	// BlockIterator iterates over the blocks, between the provided block numbers
+	BlockIterator(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error)
+}
+
+// Client is the node client, serving the reference chain data
+type Client interface {
+	// GetBlock returns specified block
+	GetBlock(uint64) (*core_types.ResultBlock, error)
+
+	// GetBlockResults returns the results of executing the transactions
+	// for the specified block
+	GetBlockResults(uint64) (*core_types.ResultBlockResults, error)
+}
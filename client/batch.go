@@ -3,13 +3,16 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	rpcClient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"go.uber.org/zap"
 )
 
 // Batch is the wrapper for HTTP batch requests
 type Batch struct {
-	batch *rpcClient.RPCBatch
+	batch  *rpcClient.RPCBatch
+	client *Client
 }
 
 // AddBlockRequest adds a new block request (block fetch) to the batch
@@ -34,7 +37,15 @@ func (b *Batch) AddBlockResultsRequest(blockNum uint64) error {
 
 // Execute sends the batch off for processing by the node
 func (b *Batch) Execute(ctx context.Context) ([]any, error) {
-	return b.batch.Send(ctx)
+	var (
+		start = time.Now()
+		count = b.batch.Count()
+	)
+
+	results, err := b.batch.Send(ctx)
+	b.client.observe("batch", start, err, zap.Int("requests", count))
+
+	return results, err
 }
 
 // Count returns the number of requests in the batch
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+)
+
+// httpCaller is the JSON-RPC HTTP transport of the client,
+// sending the requests using the configured HTTP client
+type httpCaller struct {
+	client  *http.Client
+	headers http.Header
+	remote  string
+}
+
+// newHTTPCaller creates a new JSON-RPC HTTP transport for the remote,
+// sending the given headers with every request
+func newHTTPCaller(client *http.Client, remote string, headers http.Header) *httpCaller {
+	// TCP remotes are served over HTTP
+	if strings.HasPrefix(remote, "tcp://") {
+		remote = "http://" + strings.TrimPrefix(remote, "tcp://")
+	}
+
+	return &httpCaller{
+		client:  client,
+		headers: headers,
+		remote:  remote,
+	}
+}
+
+func (c *httpCaller) SendRequest(ctx context.Context, request types.RPCRequest) (*types.RPCResponse, error) {
+	var response *types.RPCResponse
+
+	if err := c.send(ctx, request, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (c *httpCaller) SendBatch(ctx context.Context, requests types.RPCRequests) (types.RPCResponses, error) {
+	var responses types.RPCResponses
+
+	if err := c.send(ctx, requests, &responses); err != nil {
+		return nil, err
+	}
+
+	return responses, nil
+}
+
+func (c *httpCaller) Close() error {
+	c.client.CloseIdleConnections()
+
+	return nil
+}
+
+// send posts the JSON-RPC request (or batch) to the remote, and decodes the response
+func (c *httpCaller) send(ctx context.Context, request, response any) error {
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("unable to encode request, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.remote, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("unable to create request, %w", err)
+	}
+
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request, %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response, %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %d, %s", resp.StatusCode, body)
+	}
+
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("unable to decode response, %w", err)
+	}
+
+	return nil
+}
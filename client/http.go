@@ -1,40 +1,172 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
 
 	rpcClient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 
+	"go.uber.org/zap"
+
 	clientTypes "github.com/gnolang/tx-indexer/client/types"
 )
 
-// Client is the TM2 HTTP client
+var (
+	// errWSHeaders is returned when custom headers are set for a WS remote
+	errWSHeaders = errors.New("custom headers are not supported for WS remotes")
+
+	// errWSTransport is returned when the HTTP transport options are set for a WS remote,
+	// as the WS connection is managed by the TM2 WS client, with its own timeouts
+	errWSTransport = errors.New(
+		"the request timeout, dial timeout, keep-alive and idle connection options are not supported for WS remotes",
+	)
+
+	// errGRPCRemote is returned for gRPC remotes, as TM2 nodes only serve JSON-RPC
+	errGRPCRemote = errors.New("gRPC remotes are not supported, TM2 nodes only serve JSON-RPC (http:// or ws://)")
+)
+
+// Client is the TM2 RPC client
 type Client struct {
 	client *rpcClient.RPCClient
+
+	requestTimeout time.Duration
+	dialTimeout    time.Duration
+	keepAlive      time.Duration
+	maxIdleConns   int
+
+	headers http.Header
+
+	logger            *zap.Logger
+	metrics           *Metrics
+	chain             string
+	slowCallThreshold time.Duration
 }
 
-// NewClient creates a new TM2 HTTP client
-func NewClient(remote string) (*Client, error) {
-	client, err := rpcClient.NewHTTPClient(remote)
-	if err != nil {
+// NewClient creates a new TM2 RPC client. The transport is selected by the remote URL scheme:
+// ws:// and wss:// remotes are served over a single persistent WebSocket connection,
+// over which the requests are multiplexed, while any other remote is served over HTTP.
+// The headers and HTTP transport options are not supported for the WS remotes
+func NewClient(remote string, opts ...Option) (*Client, error) {
+	c := &Client{
+		requestTimeout: DefaultRequestTimeout,
+		dialTimeout:    DefaultDialTimeout,
+		keepAlive:      DefaultKeepAlive,
+		maxIdleConns:   DefaultMaxIdleConns,
+		headers:        make(http.Header),
+		logger:         zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if isGRPCRemote(remote) {
+		return nil, errGRPCRemote
+	}
+
+	if isWSRemote(remote) {
+		if len(c.headers) != 0 {
+			return nil, errWSHeaders
+		}
+
+		if c.hasTransportOptions() {
+			return nil, errWSTransport
+		}
+
+		client, err := rpcClient.NewWSClient(remote)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create WS client, %w", err)
+		}
+
+		c.client = client
+
+		return c, nil
+	}
+
+	if _, err := url.Parse(remote); err != nil {
 		return nil, fmt.Errorf("unable to create HTTP client, %w", err)
 	}
 
-	return &Client{
-		client: client,
-	}, nil
+	c.client = rpcClient.NewRPCClient(
+		newHTTPCaller(c.httpClient(), remote, c.headers),
+		rpcClient.WithRequestTimeout(c.requestTimeout),
+	)
+
+	return c, nil
+}
+
+// httpClient creates the HTTP client for the node requests. All the fetch workers
+// share the connection pool, so the idle connections are kept per host
+func (c *Client) httpClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.DialContext = (&net.Dialer{
+		Timeout:   c.dialTimeout,
+		KeepAlive: c.keepAlive,
+	}).DialContext
+	transport.MaxIdleConns = c.maxIdleConns
+	transport.MaxIdleConnsPerHost = c.maxIdleConns
+	transport.DisableKeepAlives = c.keepAlive < 0
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   c.requestTimeout,
+	}
+}
+
+// hasTransportOptions returns a flag indicating if any of the HTTP transport options
+// (the request timeout, dial timeout, keep-alive or idle connections) differs from the defaults
+func (c *Client) hasTransportOptions() bool {
+	return c.requestTimeout != DefaultRequestTimeout ||
+		c.dialTimeout != DefaultDialTimeout ||
+		c.keepAlive != DefaultKeepAlive ||
+		c.maxIdleConns != DefaultMaxIdleConns
+}
+
+// isWSRemote returns a flag indicating if the remote is a WebSocket URL
+func isWSRemote(remote string) bool {
+	u, err := url.Parse(remote)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "ws" || u.Scheme == "wss"
+}
+
+// isGRPCRemote returns a flag indicating if the remote is a gRPC URL
+func isGRPCRemote(remote string) bool {
+	u, err := url.Parse(remote)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "grpc" || u.Scheme == "grpcs"
+}
+
+// Close closes the client connection, if any
+func (c *Client) Close() error {
+	return c.client.Close()
 }
 
 // CreateBatch creates a new request batch
 func (c *Client) CreateBatch() clientTypes.Batch {
 	return &Batch{
-		batch: c.client.NewBatch(),
+		batch:  c.client.NewBatch(),
+		client: c,
 	}
 }
 
 func (c *Client) GetLatestBlockNumber() (uint64, error) {
+	start := time.Now()
+
 	status, err := c.client.Status()
+	c.observe("status", start, err)
+
 	if err != nil {
 		return 0, fmt.Errorf("unable to get chain status, %w", err)
 	}
@@ -45,7 +177,11 @@ func (c *Client) GetLatestBlockNumber() (uint64, error) {
 func (c *Client) GetBlock(blockNum uint64) (*core_types.ResultBlock, error) {
 	bn := int64(blockNum)
 
+	start := time.Now()
+
 	block, err := c.client.Block(&bn)
+	c.observe("block", start, err, zap.Uint64("height", blockNum))
+
 	if err != nil {
 		return nil, fmt.Errorf("unable to get block, %w", err)
 	}
@@ -56,10 +192,30 @@ func (c *Client) GetBlock(blockNum uint64) (*core_types.ResultBlock, error) {
 func (c *Client) GetBlockResults(blockNum uint64) (*core_types.ResultBlockResults, error) {
 	bn := int64(blockNum)
 
+	start := time.Now()
+
 	results, err := c.client.BlockResults(&bn)
+	c.observe("block_results", start, err, zap.Uint64("height", blockNum))
+
 	if err != nil {
 		return nil, fmt.Errorf("unable to get block results, %w", err)
 	}
 
 	return results, nil
 }
+
+// GetValidators returns the validator set at the given height
+func (c *Client) GetValidators(blockNum uint64) (*core_types.ResultValidators, error) {
+	bn := int64(blockNum)
+
+	start := time.Now()
+
+	validators, err := c.client.Validators(&bn)
+	c.observe("validators", start, err, zap.Uint64("height", blockNum))
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to get validators, %w", err)
+	}
+
+	return validators, nil
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Metrics are the node request metrics, shared by the clients of all the indexed chains
+type Metrics struct {
+	latency *prometheus.HistogramVec
+	errors  *prometheus.CounterVec
+}
+
+// NewMetrics creates the node request metrics, and registers them with the registerer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "tx_indexer",
+				Subsystem: "client",
+				Name:      "request_duration_seconds",
+				Help:      "The latency of the node requests, by chain and method",
+				Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+			},
+			[]string{"chain", "method"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "tx_indexer",
+				Subsystem: "client",
+				Name:      "request_errors_total",
+				Help:      "The number of failed node requests, by chain and method",
+			},
+			[]string{"chain", "method"},
+		),
+	}
+
+	registerer.MustRegister(m.latency, m.errors)
+
+	return m
+}
+
+// observe records the node request, and warns about it if it is slow
+func (c *Client) observe(method string, start time.Time, err error, fields ...zap.Field) {
+	duration := time.Since(start)
+
+	if c.metrics != nil {
+		c.metrics.latency.WithLabelValues(c.chain, method).Observe(duration.Seconds())
+
+		if err != nil {
+			c.metrics.errors.WithLabelValues(c.chain, method).Inc()
+		}
+	}
+
+	if c.slowCallThreshold > 0 && duration >= c.slowCallThreshold {
+		c.logger.Warn(
+			"slow node request",
+			append(
+				fields,
+				zap.String("method", method),
+				zap.Duration("duration", duration),
+				zap.Error(err),
+			)...,
+		)
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/base64"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRequestTimeout is the default timeout of a single node request (or batch)
+	DefaultRequestTimeout = 60 * time.Second
+
+	// DefaultDialTimeout is the default timeout for connecting to the node
+	DefaultDialTimeout = 10 * time.Second
+
+	// DefaultKeepAlive is the default keep-alive period of the node connections
+	DefaultKeepAlive = 30 * time.Second
+
+	// DefaultMaxIdleConns is the default number of idle node connections kept in the pool
+	DefaultMaxIdleConns = 100
+)
+
+type Option func(c *Client)
+
+// WithRequestTimeout sets the timeout of a single node request (or batch)
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithDialTimeout sets the timeout for connecting to the node
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.dialTimeout = timeout
+	}
+}
+
+// WithKeepAlive sets the keep-alive period of the node connections.
+// A negative period disables the keep-alives
+func WithKeepAlive(keepAlive time.Duration) Option {
+	return func(c *Client) {
+		c.keepAlive = keepAlive
+	}
+}
+
+// WithMaxIdleConns sets the number of idle node connections kept in the pool,
+// for reuse by the fetch workers
+func WithMaxIdleConns(maxIdleConns int) Option {
+	return func(c *Client) {
+		c.maxIdleConns = maxIdleConns
+	}
+}
+
+// WithHeader adds a header sent with every node request,
+// for example to authenticate against an RPC gateway
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithBearerToken authenticates the node requests using the bearer token
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithBasicAuth authenticates the node requests using basic authentication
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+
+		c.headers.Set("Authorization", "Basic "+credentials)
+	}
+}
+
+// WithLogger sets the logger to be used
+// with the client
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithMetrics records the node request latencies and errors
+// into the metrics, for the given chain
+func WithMetrics(metrics *Metrics, chain string) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+		c.chain = chain
+	}
+}
+
+// WithSlowCallThreshold sets the duration above which the node
+// requests are logged as slow. A 0 threshold disables the warnings
+func WithSlowCallThreshold(threshold time.Duration) Option {
+	return func(c *Client) {
+		c.slowCallThreshold = threshold
+	}
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// backupExt is the file extension of the indexer backups
+const backupExt = ".backup"
+
+var errNoBackups = errors.New("no backups found")
+
+type backupCfg struct {
+	dbPath      string
+	dbNamespace string
+	backupDir   string
+	coldDBPath  string
+
+	decodingRules string
+
+	incremental bool
+}
+
+// newBackupCmd creates the indexer storage backup command
+func newBackupCmd() *ffcli.Command {
+	cfg := &backupCfg{}
+
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "backup",
+		ShortUsage: "backup [flags]",
+		ShortHelp:  "Backs up the indexer DB",
+		LongHelp: "Backs up the indexer DB data to a new file in the backup directory. " +
+			"Incremental backups only contain the heights indexed since the latest backup in the directory. " +
+			"The indexer must not be running",
+		FlagSet: fs,
+		Exec: func(_ context.Context, _ []string) error {
+			return cfg.exec(os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer backup command flags
+func (c *backupCfg) registerFlags(fs *flag.FlagSet) {
+	registerBackupFlags(fs, &c.dbPath, &c.dbNamespace, &c.backupDir)
+
+	fs.StringVar(
+		&c.coldDBPath,
+		"cold-db-path",
+		"",
+		"the absolute path for the cold tier DB of the indexer DB, if any",
+	)
+
+	registerDecodingRulesFlag(fs, &c.decodingRules)
+
+	fs.BoolVar(
+		&c.incremental,
+		"incremental",
+		false,
+		"flag indicating if only the heights since the latest backup in the directory are backed up",
+	)
+}
+
+// exec executes the indexer backup command
+func (c *backupCfg) exec(out io.Writer) error {
+	if c.backupDir == "" {
+		return errors.New("backup directory is not set")
+	}
+
+	if err := os.MkdirAll(c.backupDir, 0o755); err != nil {
+		return fmt.Errorf("unable to create backup directory, %w", err)
+	}
+
+	var fromHeight uint64
+
+	if c.incremental {
+		backups, err := readBackups(c.backupDir)
+		if err != nil {
+			return err
+		}
+
+		// Continue from the latest backup checkpoint
+		fromHeight = backups[len(backups)-1].info.To + 1
+	}
+
+	// The tx index entries are backed up with the chain decoder
+	decoder, err := loadDecoder(c.decodingRules)
+	if err != nil {
+		return err
+	}
+
+	dbOpts := []storage.Option{
+		storage.WithNamespace(c.dbNamespace),
+		storage.WithDecoder(decoder),
+	}
+
+	if c.coldDBPath != "" {
+		// The cold heights are backed up along with the hot ones
+		dbOpts = append(dbOpts, storage.WithColdTier(c.coldDBPath, storage.DefaultColdCompressionLevel))
+	}
+
+	db, err := storage.NewPebble(c.dbPath, dbOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	info, err := c.backup(db, fromHeight)
+	if err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("unable to back up storage DB, %w", err)
+	}
+
+	if info.Incremental() && info.To < info.From {
+		_, _ = fmt.Fprintf(out, "No heights indexed since the latest backup (%d)\n", info.From-1)
+	} else {
+		_, _ = fmt.Fprintf(out, "Backed up heights %d to %d (%d records)\n", info.From, info.To, info.Records)
+	}
+
+	return db.Close()
+}
+
+// backup writes the backup to a temporary file, moved to
+// the backup directory once the backup is complete
+func (c *backupCfg) backup(db *storage.Pebble, fromHeight uint64) (*storage.BackupInfo, error) {
+	f, err := os.CreateTemp(c.backupDir, "*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create backup file, %w", err)
+	}
+
+	defer os.Remove(f.Name()) //nolint:errcheck // The file is moved on success
+
+	info, err := db.Backup(f, fromHeight)
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+
+		return nil, fmt.Errorf("unable to sync backup file, %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("unable to close backup file, %w", err)
+	}
+
+	// Skip empty incremental backups
+	if info.Incremental() && info.To < info.From {
+		return info, nil
+	}
+
+	name := filepath.Join(c.backupDir, fmt.Sprintf("%020d-%020d%s", info.From, info.To, backupExt))
+
+	if err := os.Rename(f.Name(), name); err != nil {
+		return nil, fmt.Errorf("unable to save backup file, %w", err)
+	}
+
+	return info, nil
+}
+
+type restoreCfg struct {
+	dbPath      string
+	dbNamespace string
+	backupDir   string
+}
+
+// newRestoreCmd creates the indexer storage restore command
+func newRestoreCmd() *ffcli.Command {
+	cfg := &restoreCfg{}
+
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	registerBackupFlags(fs, &cfg.dbPath, &cfg.dbNamespace, &cfg.backupDir)
+
+	return &ffcli.Command{
+		Name:       "restore",
+		ShortUsage: "restore [flags]",
+		ShortHelp:  "Restores the indexer DB from backups",
+		LongHelp: "Restores the indexer DB data from the backup directory, applying the latest full backup " +
+			"followed by the incremental backups made after it. The indexer must not be running",
+		FlagSet: fs,
+		Exec: func(_ context.Context, _ []string) error {
+			return cfg.exec(os.Stdout)
+		},
+	}
+}
+
+// exec executes the indexer restore command
+func (c *restoreCfg) exec(out io.Writer) error {
+	backups, err := readBackups(c.backupDir)
+	if err != nil {
+		return err
+	}
+
+	// Start from the latest full backup, followed by the incremental backups
+	start := -1
+
+	for i, backup := range backups {
+		if !backup.info.Incremental() {
+			start = i
+		}
+	}
+
+	if start == -1 {
+		return fmt.Errorf("%w, no full backup in the directory", errNoBackups)
+	}
+
+	db, err := storage.NewPebble(c.dbPath, storage.WithNamespace(c.dbNamespace))
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	restored := backups[start].info.From
+
+	for _, backup := range backups[start:] {
+		// Skip the backups not following the restored ones
+		if backup.info.From != restored {
+			continue
+		}
+
+		if err := restoreBackup(db, backup.path); err != nil {
+			_ = db.Close()
+
+			return fmt.Errorf("unable to restore backup %s, %w", backup.path, err)
+		}
+
+		_, _ = fmt.Fprintf(out, "Restored heights %d to %d\n", backup.info.From, backup.info.To)
+
+		restored = backup.info.To + 1
+	}
+
+	return db.Close()
+}
+
+// restoreBackup restores the backup file to the storage DB
+func restoreBackup(db *storage.Pebble, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	_, err = db.Restore(f)
+
+	return err
+}
+
+// backupFile is a backup in the backup directory
+type backupFile struct {
+	info *storage.BackupInfo
+	path string
+}
+
+// readBackups reads the backups in the directory, ordered by height
+func readBackups(dir string) ([]backupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backup directory, %w", err)
+	}
+
+	backups := make([]backupFile, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), backupExt) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		info, err := readBackupInfo(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read backup %s, %w", path, err)
+		}
+
+		backups = append(backups, backupFile{info: info, path: path})
+	}
+
+	if len(backups) == 0 {
+		return nil, fmt.Errorf("%w in %s", errNoBackups, dir)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].info.To != backups[j].info.To {
+			return backups[i].info.To < backups[j].info.To
+		}
+
+		// Full backups precede the incremental ones
+		return backups[i].info.From < backups[j].info.From
+	})
+
+	return backups, nil
+}
+
+// readBackupInfo reads the header of the backup file
+func readBackupInfo(path string) (*storage.BackupInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return storage.ReadBackupInfo(f)
+}
+
+// registerBackupFlags registers the flags common to the backup and restore commands
+func registerBackupFlags(fs *flag.FlagSet, dbPath, dbNamespace, backupDir *string) {
+	fs.StringVar(
+		dbPath,
+		"db-path",
+		defaultDBPath,
+		"the absolute path for the indexer DB (embedded)",
+	)
+
+	fs.StringVar(
+		dbNamespace,
+		"db-namespace",
+		"",
+		"the key namespace (chain / network identifier) of the backed up data, none by default",
+	)
+
+	fs.StringVar(
+		backupDir,
+		"backup-dir",
+		"",
+		"the directory of the indexer backup files",
+	)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/bench"
+	"github.com/gnolang/tx-indexer/serve/handlers/status"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// benchQueriedHeights is the number of random heights queried by the RPC load
+const benchQueriedHeights = 1000
+
+type benchIngestCfg struct {
+	dbPath             string
+	dbCompressionLevel int
+
+	blocks      int
+	txsPerBlock int
+	batchSize   int
+}
+
+type benchRPCCfg struct {
+	rpc string
+
+	concurrency int
+	duration    time.Duration
+	requests    int
+}
+
+// newBenchCmd creates the indexer benchmark command
+func newBenchCmd() *ffcli.Command {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+
+	return &ffcli.Command{
+		Name:       "bench",
+		ShortUsage: "bench <subcommand> [flags]",
+		ShortHelp:  "Benchmarks the indexer performance",
+		LongHelp: "Measures the storage save throughput of a synthetic chain, " +
+			"or the query latencies of a running indexer under load",
+		FlagSet: fs,
+		Subcommands: []*ffcli.Command{
+			newBenchIngestCmd(),
+			newBenchRPCCmd(),
+		},
+		Exec: func(_ context.Context, _ []string) error {
+			return flag.ErrHelp
+		},
+	}
+}
+
+// newBenchIngestCmd creates the indexer ingestion benchmark command
+func newBenchIngestCmd() *ffcli.Command {
+	cfg := &benchIngestCfg{}
+
+	fs := flag.NewFlagSet("ingest", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "ingest",
+		ShortUsage: "bench ingest [flags]",
+		ShortHelp:  "Measures the save throughput of a synthetic chain",
+		LongHelp: "Saves a synthetic chain of transfer transactions into a new indexer DB, " +
+			"in write batches, and reports the block and transaction save throughput",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer ingestion benchmark command flags
+func (c *benchIngestCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.dbPath,
+		"db-path",
+		"",
+		"the absolute path for the benchmark DB, which must not exist. A temporary DB is used (and removed) by default",
+	)
+
+	fs.IntVar(
+		&c.dbCompressionLevel,
+		"db-compression-level",
+		storage.DefaultCompressionLevel,
+		"the zstd level of the stored blocks and transactions. Level 0 disables the compression",
+	)
+
+	fs.IntVar(
+		&c.blocks,
+		"blocks",
+		bench.DefaultIngestBlocks,
+		"the number of saved synthetic blocks",
+	)
+
+	fs.IntVar(
+		&c.txsPerBlock,
+		"txs-per-block",
+		bench.DefaultTxsPerBlock,
+		"the number of transactions of each synthetic block",
+	)
+
+	fs.IntVar(
+		&c.batchSize,
+		"batch-size",
+		bench.DefaultBatchSize,
+		"the number of blocks saved in a single write batch",
+	)
+}
+
+// exec executes the indexer ingestion benchmark command
+func (c *benchIngestCfg) exec(ctx context.Context, out io.Writer) error {
+	dbPath := c.dbPath
+
+	if dbPath == "" {
+		dir, err := os.MkdirTemp("", "tx-indexer-bench")
+		if err != nil {
+			return fmt.Errorf("unable to create benchmark DB directory, %w", err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		dbPath = dir
+	} else if _, err := os.Stat(dbPath); err == nil {
+		return errors.New("the benchmark DB path already exists")
+	}
+
+	db, err := storage.NewPebble(dbPath, storage.WithCompressionLevel(c.dbCompressionLevel))
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	report, err := bench.Ingest(ctx, db, c.blocks, c.txsPerBlock, c.batchSize)
+
+	size, sizeErr := db.Size()
+
+	if closeErr := errors.Join(sizeErr, db.Close()); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to ingest synthetic chain, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(
+		out,
+		"Saved %d blocks and %d transactions in %s (%.0f blocks/s, %.0f transactions/s)\n",
+		report.Blocks,
+		report.Txs,
+		report.Duration.Round(time.Millisecond),
+		report.BlocksPerSecond(),
+		report.TxsPerSecond(),
+	)
+
+	_, _ = fmt.Fprintf(out, "Storage size %s\n", formatBytes(size))
+
+	return nil
+}
+
+// newBenchRPCCmd creates the indexer RPC load benchmark command
+func newBenchRPCCmd() *ffcli.Command {
+	cfg := &benchRPCCfg{}
+
+	fs := flag.NewFlagSet("rpc", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "rpc",
+		ShortUsage: "bench rpc [flags]",
+		ShortHelp:  "Measures the query latencies of a running indexer under load",
+		LongHelp: "Queries the blocks and transactions of random indexed heights from concurrent clients, " +
+			"and reports the query throughput and latency percentiles",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer RPC load benchmark command flags
+func (c *benchRPCCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rpc,
+		"rpc",
+		defaultIndexerRPC,
+		"the JSON-RPC URL of the running indexer",
+	)
+
+	fs.IntVar(
+		&c.concurrency,
+		"concurrency",
+		bench.DefaultConcurrency,
+		"the number of concurrent clients",
+	)
+
+	fs.DurationVar(
+		&c.duration,
+		"duration",
+		bench.DefaultLoadDuration,
+		"the load duration",
+	)
+
+	fs.IntVar(
+		&c.requests,
+		"requests",
+		0,
+		"the maximum number of sent requests, ending the load before its duration. Unlimited by default",
+	)
+}
+
+// exec executes the indexer RPC load benchmark command
+func (c *benchRPCCfg) exec(ctx context.Context, out io.Writer) error {
+	var s status.Status
+
+	if err := callRPC(ctx, c.rpc, "getStatus", nil, &s); err != nil {
+		return fmt.Errorf("unable to fetch indexer status, %w", err)
+	}
+
+	if s.LatestHeight == 0 {
+		return errors.New("the indexer has no indexed heights to query")
+	}
+
+	// Query the blocks and first txs of random indexed heights
+	requests := make([]bench.Request, 0, 2*benchQueriedHeights)
+
+	for i := 0; i < benchQueriedHeights; i++ {
+		height := rand.Uint64()%s.LatestHeight + 1 //nolint:gosec // Sampling doesn't need a secure source
+
+		requests = append(
+			requests,
+			bench.Request{Method: "getBlock", Params: []any{height}},
+			bench.Request{Method: "getTxResult", Params: []any{height, 0}},
+		)
+	}
+
+	loader := bench.NewLoader(
+		c.rpc,
+		requests,
+		bench.WithConcurrency(c.concurrency),
+		bench.WithDuration(c.duration),
+		bench.WithMaxRequests(c.requests),
+	)
+
+	report, err := loader.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to load indexer, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(
+		out,
+		"Sent %d requests in %s (%.0f requests/s), %d failed\n",
+		report.Requests(),
+		report.Duration.Round(time.Millisecond),
+		report.RequestsPerSecond(),
+		report.Errors,
+	)
+
+	_, _ = fmt.Fprintf(
+		out,
+		"Latency p50 %s, p90 %s, p99 %s, max %s\n",
+		report.Percentile(50),
+		report.Percentile(90),
+		report.Percentile(99),
+		report.Percentile(100),
+	)
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/DataDog/zstd"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+var (
+	errIncrementalSnapshot = errors.New("the bootstrap snapshot must be a full backup")
+	errSnapshotChecksum    = errors.New("the bootstrap snapshot checksum doesn't match")
+	errSnapshotChain       = errors.New("the bootstrap snapshot doesn't match the remote chain")
+)
+
+// snapshotRemote is the chain remote the restored snapshot is verified against
+type snapshotRemote interface {
+	// GetBlock returns the block at the height
+	GetBlock(blockNum uint64) (*core_types.ResultBlock, error)
+}
+
+// bootstrap restores the empty storage namespace from the snapshot (a full backup)
+// at the URL or path, before the chain is fetched. Namespaces holding indexed data are
+// not bootstrapped, so restarting the indexer with the same flags resumes the sync.
+// The latest height is restored last, so interrupted bootstraps are repeated on restart.
+// The snapshot is verified against the (hex) SHA-256 checksum of the file, and its latest
+// block against the remote one, with the restored data wiped if either doesn't match
+func bootstrap(
+	ctx context.Context,
+	db *storage.Pebble,
+	remote snapshotRemote,
+	source,
+	checksum string,
+	logger *zap.Logger,
+) error {
+	latest, err := db.GetLatestHeight()
+	if err == nil {
+		logger.Info(
+			"storage already indexed, skipping bootstrap",
+			zap.Uint64("latest", latest),
+		)
+
+		return nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	logger.Info("bootstrapping storage from snapshot", zap.String("source", source))
+
+	hash := sha256.New()
+
+	snapshot, err := openSnapshot(ctx, source, hash)
+	if err != nil {
+		return fmt.Errorf("unable to open snapshot, %w", err)
+	}
+
+	info, err := db.Restore(snapshot)
+	if errors.Is(err, storage.ErrBackupOutOfOrder) {
+		// Incremental backups don't follow an empty namespace
+		err = errIncrementalSnapshot
+	}
+
+	if err == nil {
+		// The checksum covers the whole file, past the backup end
+		err = snapshot.drain()
+	}
+
+	if err != nil {
+		return multierr.Combine(fmt.Errorf("unable to restore snapshot, %w", err), snapshot.Close(), db.Wipe())
+	}
+
+	if err := snapshot.Close(); err != nil {
+		return multierr.Append(err, db.Wipe())
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(sum, checksum) {
+		return multierr.Append(fmt.Errorf("%w, got %s, expected %s", errSnapshotChecksum, sum, checksum), db.Wipe())
+	}
+
+	if err := verifySnapshot(db, remote); err != nil {
+		return multierr.Append(err, db.Wipe())
+	}
+
+	logger.Info(
+		"bootstrapped storage from snapshot",
+		zap.Uint64("latest", info.To),
+		zap.Int("records", info.Records),
+	)
+
+	return nil
+}
+
+// verifySnapshot makes sure the restored snapshot belongs to the remote chain,
+// comparing the chain ID and the hash of the latest restored block with the remote block
+func verifySnapshot(db *storage.Pebble, remote snapshotRemote) error {
+	latest, err := db.GetLatestHeight()
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// Nothing was indexed at the time of the backup
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to fetch restored latest height, %w", err)
+	}
+
+	local, err := db.GetBlock(latest)
+	if err != nil {
+		return fmt.Errorf("unable to fetch restored block %d, %w", latest, err)
+	}
+
+	result, err := remote.GetBlock(latest)
+	if err != nil {
+		return fmt.Errorf("unable to fetch remote block %d, %w", latest, err)
+	}
+
+	if result == nil || result.Block == nil {
+		return fmt.Errorf("%w, block %d not found on the remote", errSnapshotChain, latest)
+	}
+
+	if local.ChainID != result.Block.ChainID {
+		return fmt.Errorf(
+			"%w, snapshot chain ID %q, remote chain ID %q",
+			errSnapshotChain,
+			local.ChainID,
+			result.Block.ChainID,
+		)
+	}
+
+	// The blocks without a hash (ex. missing the validator set hash) are compared by their time
+	localHash, remoteHash := local.Hash(), result.Block.Hash()
+
+	if len(localHash) != 0 && len(remoteHash) != 0 {
+		if !bytes.Equal(localHash, remoteHash) {
+			return fmt.Errorf("%w, block %d hash %X, remote hash %X", errSnapshotChain, latest, localHash, remoteHash)
+		}
+
+		return nil
+	}
+
+	if !local.Time.Equal(result.Block.Time) {
+		return fmt.Errorf("%w, block %d time differs from the remote one", errSnapshotChain, latest)
+	}
+
+	return nil
+}
+
+// openSnapshot opens the snapshot at the URL (http:// or https://) or path,
+// decompressing it if it has the .gz or .zst extension. The (compressed) file
+// is written to the hash as it's read
+func openSnapshot(ctx context.Context, source string, hash io.Writer) (*snapshotReader, error) {
+	var (
+		r   io.ReadCloser
+		err error
+	)
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		r, err = downloadSnapshot(ctx, source)
+	} else {
+		r, err = os.Open(source)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	file := io.TeeReader(r, hash)
+
+	// The extension is checked without the URL query, if any
+	name, _, _ := strings.Cut(source, "?")
+
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, multierr.Append(fmt.Errorf("unable to decompress snapshot, %w", err), r.Close())
+		}
+
+		return &snapshotReader{Reader: gz, file: file, closers: []io.Closer{gz, r}}, nil
+	case strings.HasSuffix(name, ".zst"):
+		zr := zstd.NewReader(file)
+
+		return &snapshotReader{Reader: zr, file: file, closers: []io.Closer{zr, r}}, nil
+	default:
+		return &snapshotReader{Reader: file, file: file, closers: []io.Closer{r}}, nil
+	}
+}
+
+// downloadSnapshot streams the snapshot from the URL
+func downloadSnapshot(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request, %w", err)
+	}
+
+	// The snapshot can take long to download, so no overall timeout is set
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download snapshot, %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("unable to download snapshot, status %s", resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// snapshotReader reads the decompressed snapshot,
+// closing the decompressor along with the source
+type snapshotReader struct {
+	io.Reader
+
+	// file is the (compressed) snapshot file
+	file io.Reader
+
+	closers []io.Closer
+}
+
+// drain reads the rest of the snapshot file
+func (r *snapshotReader) drain() error {
+	_, err := io.Copy(io.Discard, r.file)
+
+	return err
+}
+
+func (r *snapshotReader) Close() error {
+	var err error
+
+	for _, c := range r.closers {
+		err = multierr.Append(err, c.Close())
+	}
+
+	return err
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// chainQueryParam is the URL query parameter used
+// for selecting the chain in multi-chain mode
+const chainQueryParam = "chain"
+
+var (
+	errInvalidChainConfig = errors.New("invalid chain configuration")
+	errInvalidChainName   = errors.New("invalid chain name, only lowercase letters, digits and dashes are allowed")
+	errDuplicateChain     = errors.New("duplicate chain name")
+	errChainsNamespace    = errors.New("the db namespace can't be set in multi-chain mode, the chain names are the namespaces")
+)
+
+// chainNameRegex matches the valid chain names, which are used as the storage namespaces,
+// the metric labels and the sink prefixes, and are set in the URL query parameter
+var chainNameRegex = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// chainCfg is the configuration of a single indexed chain
+type chainCfg struct {
+	name        string
+	remote      string
+	startHeight uint64
+	endHeight   uint64
+
+	// decodingRules is the path to the chain decoding rules
+	// configuration file, if other than the default one
+	decodingRules string
+}
+
+// chainsFlag is a repeatable flag containing chain configurations,
+// in the format: name=<name>,remote=<url>[,start-height=<height>][,end-height=<height>][,decoding-rules=<path>]
+type chainsFlag []chainCfg
+
+func (c *chainsFlag) String() string {
+	chains := make([]string, 0, len(*c))
+
+	for _, chain := range *c {
+		chains = append(
+			chains,
+			fmt.Sprintf(
+				"name=%s,remote=%s,start-height=%d,end-height=%d,decoding-rules=%s",
+				chain.name,
+				chain.remote,
+				chain.startHeight,
+				chain.endHeight,
+				chain.decodingRules,
+			),
+		)
+	}
+
+	return strings.Join(chains, " ")
+}
+
+func (c *chainsFlag) Set(value string) error {
+	var chain chainCfg
+
+	for _, field := range strings.Split(value, ",") {
+		key, val, found := strings.Cut(field, "=")
+		if !found {
+			return fmt.Errorf("%w, field %q", errInvalidChainConfig, field)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "name":
+			chain.name = strings.TrimSpace(val)
+		case "remote":
+			chain.remote = strings.TrimSpace(val)
+		case "start-height":
+			height, err := strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w, invalid start height, %w", errInvalidChainConfig, err)
+			}
+
+			chain.startHeight = height
+		case "decoding-rules":
+			chain.decodingRules = strings.TrimSpace(val)
+		case "end-height":
+			height, err := strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w, invalid end height, %w", errInvalidChainConfig, err)
+			}
+
+			chain.endHeight = height
+		default:
+			return fmt.Errorf("%w, unknown field %q", errInvalidChainConfig, key)
+		}
+	}
+
+	if chain.name == "" || chain.remote == "" {
+		return fmt.Errorf("%w, name and remote are required", errInvalidChainConfig)
+	}
+
+	if !chainNameRegex.MatchString(chain.name) {
+		return fmt.Errorf("%w, %q", errInvalidChainName, chain.name)
+	}
+
+	if chain.endHeight != 0 && chain.endHeight < chain.startHeight {
+		return fmt.Errorf("%w, end height below start height", errInvalidChainConfig)
+	}
+
+	for _, existing := range *c {
+		if existing.name == chain.name {
+			return fmt.Errorf("%w, %s", errDuplicateChain, chain.name)
+		}
+	}
+
+	*c = append(*c, chain)
+
+	return nil
+}
+
+// chainRouter routes incoming requests to the chain handler
+// selected by the chain query parameter, defaulting to the first chain
+type chainRouter struct {
+	handlers     map[string]http.Handler
+	defaultChain string
+}
+
+// newChainRouter creates a new chain router
+func newChainRouter(defaultChain string) *chainRouter {
+	return &chainRouter{
+		handlers:     make(map[string]http.Handler),
+		defaultChain: defaultChain,
+	}
+}
+
+// addChain registers the handler for the given chain
+func (r *chainRouter) addChain(name string, handler http.Handler) {
+	r.handlers[name] = handler
+}
+
+func (r *chainRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get(chainQueryParam)
+	if name == "" {
+		name = r.defaultChain
+	}
+
+	handler, ok := r.handlers[name]
+	if !ok {
+		http.Error(w, `{"error": "unknown chain"}`, http.StatusNotFound)
+
+		return
+	}
+
+	handler.ServeHTTP(w, req)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/check"
+	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+var errDivergent = errors.New("the indexed data diverges from the node")
+
+type checkCfg struct {
+	dbPath      string
+	dbNamespace string
+	coldDBPath  string
+	remote      string
+
+	sample   float64
+	from     uint64
+	to       uint64
+	maxSlots int
+}
+
+// newCheckCmd creates the indexer consistency check command
+func newCheckCmd() *ffcli.Command {
+	cfg := &checkCfg{}
+
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "check",
+		ShortUsage: "check [flags]",
+		ShortHelp:  "Checks the indexed data against the node",
+		LongHelp: "Cross-verifies a random sample (or the full range) of the indexed blocks and transaction results " +
+			"with the remote node, and reports the divergences. The indexer must not be running",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer consistency check command flags
+func (c *checkCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.dbPath,
+		"db-path",
+		defaultDBPath,
+		"the absolute path for the indexer DB (embedded)",
+	)
+
+	fs.StringVar(
+		&c.dbNamespace,
+		"db-namespace",
+		"",
+		"the key namespace (chain / network identifier) of the checked data, none by default",
+	)
+
+	fs.StringVar(
+		&c.coldDBPath,
+		"cold-db-path",
+		"",
+		"the absolute path for the cold tier DB of the indexer DB, if any",
+	)
+
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		defaultRemote,
+		"the JSON-RPC URL of the Gno chain",
+	)
+
+	fs.Float64Var(
+		&c.sample,
+		"sample",
+		1,
+		"the fraction of the heights checked, picked at random. All heights are checked by default",
+	)
+
+	fs.Uint64Var(
+		&c.from,
+		"from",
+		0,
+		"the first checked height, the first indexed height by default",
+	)
+
+	fs.Uint64Var(
+		&c.to,
+		"to",
+		0,
+		"the last checked height, the latest indexed height by default",
+	)
+
+	fs.IntVar(
+		&c.maxSlots,
+		"max-slots",
+		check.DefaultMaxSlots,
+		"the number of heights checked concurrently",
+	)
+}
+
+// exec executes the indexer consistency check command
+func (c *checkCfg) exec(ctx context.Context, out io.Writer) error {
+	if c.sample <= 0 || c.sample > 1 {
+		return errors.New("the sample must be in the (0, 1] range")
+	}
+
+	dbOpts := []storage.Option{
+		storage.WithNamespace(c.dbNamespace),
+		storage.WithReadOnly(),
+	}
+
+	if c.coldDBPath != "" {
+		dbOpts = append(dbOpts, storage.WithColdTier(c.coldDBPath, storage.DefaultColdCompressionLevel))
+	}
+
+	db, err := storage.NewPebble(c.dbPath, dbOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	tm2Client, err := client.NewClient(c.remote)
+	if err != nil {
+		return errors.Join(fmt.Errorf("unable to create client, %w", err), db.Close())
+	}
+
+	checker := check.New(
+		db,
+		tm2Client,
+		check.WithSampleRate(c.sample),
+		check.WithRange(c.from, c.to),
+		check.WithMaxSlots(c.maxSlots),
+	)
+
+	report, err := checker.Run(ctx)
+
+	if closeErr := errors.Join(tm2Client.Close(), db.Close()); closeErr != nil && err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to check indexed data, %w", err)
+	}
+
+	for _, divergence := range report.Divergences {
+		_, _ = fmt.Fprintf(out, "Height %d: %s\n", divergence.Height, divergence.Reason)
+	}
+
+	_, _ = fmt.Fprintf(
+		out,
+		"Checked %d heights from %d to %d, found %d divergences\n",
+		report.Checked,
+		report.From,
+		report.To,
+		len(report.Divergences),
+	)
+
+	if len(report.Divergences) > 0 {
+		return errDivergent
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// daemonEnv is the environment variable
+// set for the daemonized indexer process
+const daemonEnv = "TX_INDEXER_DAEMON"
+
+var errAlreadyRunning = errors.New("indexer already running")
+
+// isDaemon returns a flag indicating if the process
+// is the daemonized indexer process
+func isDaemon() bool {
+	return os.Getenv(daemonEnv) != ""
+}
+
+// daemonize starts the indexer in the background, detached from the terminal,
+// with the same arguments. It returns once the daemon process is started
+func daemonize() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to resolve executable, %w", err)
+	}
+
+	pid, err := startDetached(executable, os.Args[1:], append(os.Environ(), daemonEnv+"=1"))
+	if err != nil {
+		return fmt.Errorf("unable to start daemon, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "indexer started in the background, PID %d\n", pid)
+
+	return nil
+}
+
+// writePIDFile writes the process PID to the PID file, returning the function
+// removing it. Fails if the PID file belongs to another running process,
+// other than the parent process handing off its listener
+func writePIDFile(path string) (func(), error) {
+	if data, err := os.ReadFile(path); err == nil {
+		pid, parseErr := strconv.Atoi(strings.TrimSpace(string(data)))
+		// The PID of the process handing off its listener is taken over
+		if parseErr == nil && pid != os.Getpid() && pid != os.Getppid() && processAlive(pid) {
+			return nil, fmt.Errorf("%w, PID %d", errAlreadyRunning, pid)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("unable to write PID file, %w", err)
+	}
+
+	pid := strconv.Itoa(os.Getpid())
+
+	// The PID file is only removed if not taken over by another process
+	return func() {
+		if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == pid {
+			_ = os.Remove(path)
+		}
+	}, nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package main
+
+import "errors"
+
+var errDaemonUnsupported = errors.New("daemon mode is only supported on unix systems")
+
+// startDetached is not supported on non-unix systems
+func startDetached(_ string, _, _ []string) (int, error) {
+	return 0, errDaemonUnsupported
+}
+
+// processAlive is not supported on non-unix systems,
+// so the processes are assumed to be stopped
+func processAlive(_ int) bool {
+	return false
+}
@@ -0,0 +1,40 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// startDetached starts the process in a new session,
+// detached from the terminal, returning its PID
+func startDetached(executable string, args, env []string) (int, error) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	defer devNull.Close()
+
+	process, err := os.StartProcess(executable, append([]string{executable}, args...), &os.ProcAttr{
+		Env:   env,
+		Files: []*os.File{devNull, devNull, devNull},
+		Sys:   &syscall.SysProcAttr{Setsid: true},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	pid := process.Pid
+
+	return pid, process.Release()
+}
+
+// processAlive returns a flag indicating if the process with the PID is running
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+// registerDecodingRulesFlag registers the decoding rules flag, shared by the commands decoding the transactions
+func registerDecodingRulesFlag(fs *flag.FlagSet, decodingRules *string) {
+	fs.StringVar(
+		decodingRules,
+		"decoding-rules",
+		"",
+		"the path to the JSON decoding rules configuration file, for the messages renamed by chain upgrades, if any",
+	)
+}
+
+// loadDecoder loads the chain decoder, applying the decoding rules of the file, if any
+func loadDecoder(decodingRules string) (*decode.Decoder, error) {
+	decoder, err := decode.LoadDecoder(decodingRules)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load decoding rules, %w", err)
+	}
+
+	return decoder, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/systemd"
+)
+
+const (
+	// handoffFDEnv is the environment variable of the descriptor the new process
+	// signals its readiness on, to the process handing off its listener
+	handoffFDEnv = "TX_INDEXER_HANDOFF_FD"
+
+	// handoffTimeout is the time the new process is given
+	// to signal its readiness, before the handoff is aborted
+	handoffTimeout = 30 * time.Second
+
+	// handoffDBRetryInterval is the interval between the attempts of the new process
+	// to open the DB, while it's still held by the process handing off its listener
+	handoffDBRetryInterval = 100 * time.Millisecond
+)
+
+var errHandedOff = errors.New("listener handed off to the new process")
+
+// listen returns the listener passed to the process (by systemd socket activation,
+// or by the process handing off its listener), if any, or listens on the address
+func listen(address string, logger *zap.Logger) (net.Listener, error) {
+	listeners, err := systemd.Listeners()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read passed listeners, %w", err)
+	}
+
+	if len(listeners) == 0 {
+		return net.Listen("tcp", address)
+	}
+
+	logger.Info(
+		"using the passed listener",
+		zap.String("address", listeners[0].Addr().String()),
+	)
+
+	// Only a single listener is served
+	for _, ln := range listeners[1:] {
+		_ = ln.Close()
+	}
+
+	return listeners[0], nil
+}
+
+// isHandoff returns a flag indicating if the process
+// is taking over the listener of its parent process
+func isHandoff() bool {
+	return os.Getenv(handoffFDEnv) != ""
+}
+
+// signalHandoffReady signals the readiness to the process handing off its listener,
+// which then shuts down, releasing the DB
+func signalHandoffReady() error {
+	fd, err := strconv.Atoi(os.Getenv(handoffFDEnv))
+	if err != nil {
+		return fmt.Errorf("invalid handoff descriptor, %w", err)
+	}
+
+	_ = os.Unsetenv(handoffFDEnv)
+
+	ready := os.NewFile(uintptr(fd), "handoff")
+	defer ready.Close()
+
+	if _, err := ready.Write([]byte{1}); err != nil {
+		return fmt.Errorf("unable to signal handoff readiness, %w", err)
+	}
+
+	return nil
+}
+
+// retryUntil retries the function until it succeeds, or the timeout passes
+func retryUntil(timeout time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		err := fn()
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+
+		time.Sleep(handoffDBRetryInterval)
+	}
+}
+
+// handoffEnv returns the environment of the process taking over the listener
+func handoffEnv() []string {
+	env := make([]string, 0, len(os.Environ())+2)
+
+	for _, variable := range os.Environ() {
+		switch {
+		case strings.HasPrefix(variable, "LISTEN_PID="),
+			strings.HasPrefix(variable, "LISTEN_FDS="),
+			strings.HasPrefix(variable, handoffFDEnv+"="):
+			continue
+		default:
+			env = append(env, variable)
+		}
+	}
+
+	// The listener and readiness descriptors follow stdin, stdout and stderr
+	return append(env, "LISTEN_FDS=1", handoffFDEnv+"=4")
+}
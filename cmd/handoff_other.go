@@ -0,0 +1,20 @@
+//go:build !unix
+
+package main
+
+import (
+	"context"
+	"net"
+
+	"go.uber.org/zap"
+)
+
+// handoffOnSignal is not supported on non-unix systems,
+// so the service only waits for the context to be cancelled
+func handoffOnSignal(_ net.Listener, _ *zap.Logger) waitFunc {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}
+}
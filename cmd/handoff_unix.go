@@ -0,0 +1,123 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	errHandoffTimeout  = errors.New("new process not ready before the handoff timeout")
+	errHandoffListener = errors.New("listener can't be handed off")
+)
+
+// handoffOnSignal hands off the listener to a new indexer process on SIGUSR2,
+// started from the (upgraded) executable with the same arguments. Once the new
+// process is ready, the service stops with errHandedOff, for the indexer to shut down
+func handoffOnSignal(ln net.Listener, logger *zap.Logger) waitFunc {
+	return func(ctx context.Context) error {
+		signalCh := make(chan os.Signal, 1)
+
+		signal.Notify(signalCh, syscall.SIGUSR2)
+		defer signal.Stop(signalCh)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-signalCh:
+				logger.Info("handing off the listener to a new process")
+
+				pid, err := handoff(ln)
+				if err != nil {
+					logger.Error("unable to hand off the listener", zap.Error(err))
+
+					continue
+				}
+
+				logger.Info("listener handed off, shutting down", zap.Int("pid", pid))
+
+				return errHandedOff
+			}
+		}
+	}
+}
+
+// handoff starts the new process with the listener,
+// and waits for its readiness, returning its PID
+func handoff(ln net.Listener) (int, error) {
+	fileLn, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return 0, errHandoffListener
+	}
+
+	lnFile, err := fileLn.File()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get listener descriptor, %w", err)
+	}
+
+	defer lnFile.Close()
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return 0, fmt.Errorf("unable to create handoff pipe, %w", err)
+	}
+
+	defer readyR.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		_ = readyW.Close()
+
+		return 0, fmt.Errorf("unable to resolve executable, %w", err)
+	}
+
+	process, err := os.StartProcess(executable, append([]string{executable}, os.Args[1:]...), &os.ProcAttr{
+		Env:   handoffEnv(),
+		Files: []*os.File{os.Stdin, os.Stdout, os.Stderr, lnFile, readyW},
+	})
+
+	// Only the new process holds the write end,
+	// so the read fails once it exits
+	_ = readyW.Close()
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to start new process, %w", err)
+	}
+
+	readyCh := make(chan error, 1)
+
+	go func() {
+		_, readErr := readyR.Read(make([]byte, 1))
+
+		readyCh <- readErr
+	}()
+
+	select {
+	case readErr := <-readyCh:
+		if readErr != nil {
+			// Reap the exited process
+			_, _ = process.Wait()
+
+			return 0, fmt.Errorf("new process exited before being ready, %w", readErr)
+		}
+	case <-time.After(handoffTimeout):
+		_ = process.Kill()
+		_, _ = process.Wait()
+
+		return 0, errHandoffTimeout
+	}
+
+	pid := process.Pid
+
+	return pid, process.Release()
+}
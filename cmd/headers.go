@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/client"
+)
+
+var (
+	errInvalidHeader    = errors.New("invalid header, expected <key>: <value>")
+	errInvalidBasicAuth = errors.New("invalid basic auth, expected <username>:<password>")
+)
+
+// header is a single custom remote request header
+type header struct {
+	key   string
+	value string
+}
+
+// headersFlag is a repeatable flag containing custom
+// remote request headers, in the format: <key>: <value>
+type headersFlag []header
+
+func (h *headersFlag) String() string {
+	headers := make([]string, 0, len(*h))
+
+	for _, header := range *h {
+		headers = append(headers, fmt.Sprintf("%s: %s", header.key, header.value))
+	}
+
+	return strings.Join(headers, ", ")
+}
+
+func (h *headersFlag) Set(value string) error {
+	key, val, found := strings.Cut(value, ":")
+	if !found || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("%w, %q", errInvalidHeader, value)
+	}
+
+	*h = append(*h, header{
+		key:   strings.TrimSpace(key),
+		value: strings.TrimSpace(val),
+	})
+
+	return nil
+}
+
+// authOptions returns the node client options authenticating
+// the remote requests, based on the start configuration
+func (c *startCfg) authOptions() ([]client.Option, error) {
+	opts := make([]client.Option, 0, len(c.remoteHeaders)+2)
+
+	for _, header := range c.remoteHeaders {
+		opts = append(opts, client.WithHeader(header.key, header.value))
+	}
+
+	if c.remoteBasicAuth != "" {
+		username, password, found := strings.Cut(c.remoteBasicAuth, ":")
+		if !found {
+			return nil, errInvalidBasicAuth
+		}
+
+		opts = append(opts, client.WithBasicAuth(username, password))
+	}
+
+	if c.remoteBearerToken != "" {
+		opts = append(opts, client.WithBearerToken(c.remoteBearerToken))
+	}
+
+	return opts, nil
+}
@@ -25,6 +25,17 @@ func main() {
 	// Add the subcommands
 	cmd.Subcommands = []*ffcli.Command{
 		newStartCmd(),
+		newStatusCmd(),
+		newTailCmd(),
+		newQueryCmd(),
+		newMigrateCmd(),
+		newCheckCmd(),
+		newResyncCmd(),
+		newReindexCmd(),
+		newBackupCmd(),
+		newRestoreCmd(),
+		newRouterCmd(),
+		newBenchCmd(),
 		// newResetCmd(),
 		// newRepairCmd(),
 	}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+type migrateCfg struct {
+	dbPath      string
+	dbNamespace string
+	coldDBPath  string
+
+	decodingRules string
+}
+
+// newMigrateCmd creates the indexer storage migrate command
+func newMigrateCmd() *ffcli.Command {
+	cfg := &migrateCfg{}
+
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "migrate",
+		ShortUsage: "migrate [flags]",
+		ShortHelp:  "Migrates the indexer DB to the current storage format",
+		LongHelp: "Moves the blocks and transactions of the indexer DB to the current key schema, " +
+			"rewrites the legacy Amino encoded values in the current (protobuf) encoding, " +
+			"and indexes the transactions saved before the address index was introduced. " +
+			"The indexer must not be running",
+		FlagSet: fs,
+		Exec: func(_ context.Context, _ []string) error {
+			return cfg.exec(os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer migrate command flags
+func (c *migrateCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.dbPath,
+		"db-path",
+		defaultDBPath,
+		"the absolute path for the indexer DB (embedded)",
+	)
+
+	fs.StringVar(
+		&c.dbNamespace,
+		"db-namespace",
+		"",
+		"the key namespace (chain / network identifier) of the migrated data, none by default",
+	)
+
+	fs.StringVar(
+		&c.coldDBPath,
+		"cold-db-path",
+		"",
+		"the absolute path for the cold tier DB of the indexer DB, if any",
+	)
+
+	registerDecodingRulesFlag(fs, &c.decodingRules)
+}
+
+// exec executes the indexer migrate command
+func (c *migrateCfg) exec(out io.Writer) error {
+	decoder, err := loadDecoder(c.decodingRules)
+	if err != nil {
+		return err
+	}
+
+	dbOpts := []storage.Option{
+		storage.WithNamespace(c.dbNamespace),
+		storage.WithDecoder(decoder),
+	}
+
+	if c.coldDBPath != "" {
+		dbOpts = append(dbOpts, storage.WithColdTier(c.coldDBPath, storage.DefaultColdCompressionLevel))
+	}
+
+	db, err := storage.NewPebble(c.dbPath, dbOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	moved, err := db.MigrateSchema()
+	if err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("unable to migrate storage key schema, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Moved %d keys to the current key schema\n", moved)
+
+	migrated, err := db.MigrateEncoding()
+	if err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("unable to migrate storage DB, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Migrated %d values\n", migrated)
+
+	indexed, err := db.MigrateAddressIndex()
+	if err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("unable to build storage address index, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Indexed %d transactions by address\n", indexed)
+
+	return db.Close()
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/plugins/balances"
+	"github.com/gnolang/tx-indexer/plugins/chainstats"
+	"github.com/gnolang/tx-indexer/plugins/failures"
+	"github.com/gnolang/tx-indexer/plugins/fees"
+	"github.com/gnolang/tx-indexer/plugins/gas"
+	"github.com/gnolang/tx-indexer/plugins/grc20"
+	"github.com/gnolang/tx-indexer/plugins/leaderboard"
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+	"github.com/gnolang/tx-indexer/plugins/realmstats"
+	"github.com/gnolang/tx-indexer/plugins/rollups"
+	"github.com/gnolang/tx-indexer/plugins/search"
+	"github.com/gnolang/tx-indexer/plugins/signers"
+	"github.com/gnolang/tx-indexer/plugins/validators"
+	"github.com/gnolang/tx-indexer/serve"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// builtinPlugin is an indexer plugin bundled with the indexer
+type builtinPlugin struct {
+	// newFn creates the plugin instance, for the chain client and decoder
+	newFn func(c *client.Client, d *decode.Decoder) plugins.Indexer
+
+	// registerFn registers the endpoints serving the plugin data
+	registerFn func(j *serve.JSONRPC, db storage.Reader)
+}
+
+// builtinPlugins are the indexer plugins that can be enabled with the plugins flag
+var builtinPlugins = map[string]builtinPlugin{
+	balances.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return balances.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterAccountEndpoints(balances.NewReader(db))
+		},
+	},
+	chainstats.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return chainstats.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterChainEndpoints(chainstats.NewReader(db))
+		},
+	},
+	failures.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return failures.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterFailureEndpoints(failures.NewReader(db))
+		},
+	},
+	fees.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return fees.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterFeeEndpoints(fees.NewReader(db))
+		},
+	},
+	gas.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return gas.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterGasEndpoints(gas.NewReader(db))
+		},
+	},
+	leaderboard.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return leaderboard.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterLeaderboardEndpoints(leaderboard.NewReader(db))
+		},
+	},
+	realmevents.Name: {
+		newFn: func(_ *client.Client, _ *decode.Decoder) plugins.Indexer {
+			return realmevents.New()
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterRealmEndpoints(realmevents.NewReader(db))
+		},
+	},
+	realmstats.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return realmstats.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterRealmStatsEndpoints(realmstats.NewReader(db))
+		},
+	},
+	rollups.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return rollups.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterSeriesEndpoints(rollups.NewReader(db))
+		},
+	},
+	search.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return search.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterSearchEndpoints(search.NewReader(db))
+		},
+	},
+	signers.Name: {
+		newFn: func(_ *client.Client, d *decode.Decoder) plugins.Indexer {
+			return signers.New(d)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterSignerEndpoints(signers.NewReader(db))
+		},
+	},
+	validators.Name: {
+		newFn: func(c *client.Client, _ *decode.Decoder) plugins.Indexer {
+			return validators.New(c)
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterValidatorEndpoints(validators.NewReader(db))
+		},
+	},
+	grc20.Name: {
+		newFn: func(_ *client.Client, _ *decode.Decoder) plugins.Indexer {
+			return grc20.New()
+		},
+		registerFn: func(j *serve.JSONRPC, db storage.Reader) {
+			j.RegisterTokenEndpoints(grc20.NewReader(db))
+		},
+	},
+}
+
+// rebuildPlugins wipes the data of the plugins, and replays the indexed heights
+// up to the latest one through the plugins. Returns the number of replayed heights
+func rebuildPlugins(
+	ctx context.Context,
+	db *storage.Pebble,
+	tm2Client *client.Client,
+	decoder *decode.Decoder,
+	pluginNames []string,
+	latest uint64,
+) (uint64, error) {
+	indexers := make([]plugins.Indexer, 0, len(pluginNames))
+
+	for _, name := range pluginNames {
+		if err := db.WipePlugin(name); err != nil {
+			return 0, err
+		}
+
+		indexers = append(indexers, builtinPlugins[name].newFn(tm2Client, decoder))
+	}
+
+	return plugins.Replay(ctx, db, indexers, 0, latest)
+}
+
+// pluginsWithData returns the sorted names of the builtin plugins whose data is saved in the DB
+func pluginsWithData(db *storage.Pebble) ([]string, error) {
+	names := make([]string, 0)
+
+	for name := range builtinPlugins {
+		found, err := db.HasPluginData(name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check plugin %s data, %w", name, err)
+		}
+
+		if found {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// parsePlugins parses the comma separated list of builtin plugin names
+func parsePlugins(list string) ([]string, error) {
+	names := make([]string, 0)
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := builtinPlugins[name]; !ok {
+			return nil, fmt.Errorf("unknown plugin %q, available plugins: %s", name, availablePlugins())
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// availablePlugins returns the sorted builtin plugin names
+func availablePlugins() string {
+	names := make([]string, 0, len(builtinPlugins))
+
+	for name := range builtinPlugins {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/objstore"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// pruneInterval is the interval between
+// the prunes of the older heights
+const pruneInterval = time.Minute
+
+var errMissingArchiveCredentials = errors.New(
+	"the S3 prune archive requires the " + objstore.EnvS3AccessKey + " and " + objstore.EnvS3SecretKey + " variables",
+)
+
+// pruneHeights periodically prunes the heights older than
+// the kept heights of each storage namespace
+func pruneHeights(stores []*storage.Pebble, keepHeights uint64, logger *zap.Logger) waitFunc {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			for _, store := range stores {
+				pruned, err := store.Prune(ctx, keepHeights)
+				if err != nil && ctx.Err() == nil {
+					logger.Error(
+						"unable to prune heights",
+						zap.String("namespace", store.Namespace()),
+						zap.Error(err),
+					)
+				}
+
+				if pruned != 0 {
+					logger.Info(
+						"pruned heights",
+						zap.String("namespace", store.Namespace()),
+						zap.Uint64("heights", pruned),
+					)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// newPruneArchive creates the object store of the prune archive, which is either
+// a directory path or an s3://<bucket>/<prefix> URL. Returns the archive key prefix
+func newPruneArchive(archive, endpoint, region string) (objstore.Store, string, error) {
+	if !strings.HasPrefix(archive, "s3://") {
+		return objstore.NewDir(archive), "", nil
+	}
+
+	u, err := url.Parse(archive)
+	if err != nil || u.Host == "" {
+		return nil, "", fmt.Errorf("invalid prune archive URL %q", archive)
+	}
+
+	accessKey, secretKey := os.Getenv(objstore.EnvS3AccessKey), os.Getenv(objstore.EnvS3SecretKey)
+	if accessKey == "" || secretKey == "" {
+		return nil, "", errMissingArchiveCredentials
+	}
+
+	opts := []objstore.S3Option{
+		objstore.WithS3SessionToken(os.Getenv(objstore.EnvS3SessionToken)),
+	}
+
+	if endpoint != "" {
+		opts = append(opts, objstore.WithS3Endpoint(endpoint))
+	}
+
+	if region != "" {
+		opts = append(opts, objstore.WithS3Region(region))
+	}
+
+	// The prefix is a key directory, if set
+	prefix := strings.TrimPrefix(u.Path, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return objstore.NewS3(u.Host, accessKey, secretKey, opts...), prefix, nil
+}
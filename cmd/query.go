@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+var errInvalidArgs = errors.New("invalid number of arguments")
+
+type queryCfg struct {
+	rpc string
+}
+
+type queryTxsCfg struct {
+	queryCfg
+
+	address   string
+	fromBlock uint64
+	toBlock   uint64
+}
+
+// queryTx is the printable transaction result
+type queryTx struct {
+	Hash     string          `json:"hash"`
+	Tx       json.RawMessage `json:"tx"`
+	Response json.RawMessage `json:"response"`
+	Height   int64           `json:"height"`
+	Index    uint32          `json:"index"`
+}
+
+// newQueryCmd creates the indexer query command
+func newQueryCmd() *ffcli.Command {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+
+	return &ffcli.Command{
+		Name:       "query",
+		ShortUsage: "query <subcommand> [flags] [<arg>...]",
+		ShortHelp:  "Queries data from a running indexer",
+		LongHelp:   "Queries transactions and blocks from a running indexer, and prints them as JSON",
+		FlagSet:    fs,
+		Subcommands: []*ffcli.Command{
+			newQueryTxCmd(),
+			newQueryBlockCmd(),
+			newQueryTxsCmd(),
+		},
+		Exec: func(_ context.Context, _ []string) error {
+			return flag.ErrHelp
+		},
+	}
+}
+
+// newQueryTxCmd creates the indexer query tx command
+func newQueryTxCmd() *ffcli.Command {
+	cfg := &queryCfg{}
+
+	fs := flag.NewFlagSet("tx", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "tx",
+		ShortUsage: "query tx [flags] <hash>",
+		ShortHelp:  "Fetches a transaction by its base64 hash",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			return cfg.execTx(ctx, args, os.Stdout)
+		},
+	}
+}
+
+// newQueryBlockCmd creates the indexer query block command
+func newQueryBlockCmd() *ffcli.Command {
+	cfg := &queryCfg{}
+
+	fs := flag.NewFlagSet("block", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "block",
+		ShortUsage: "query block [flags] <height>",
+		ShortHelp:  "Fetches a block by its height",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, args []string) error {
+			return cfg.execBlock(ctx, args, os.Stdout)
+		},
+	}
+}
+
+// newQueryTxsCmd creates the indexer query txs command
+func newQueryTxsCmd() *ffcli.Command {
+	cfg := &queryTxsCfg{}
+
+	fs := flag.NewFlagSet("txs", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "txs",
+		ShortUsage: "query txs [flags]",
+		ShortHelp:  "Fetches the transactions an address participated in",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer query command flags
+func (c *queryCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rpc,
+		"rpc",
+		defaultIndexerRPC,
+		"the JSON-RPC URL of the running indexer",
+	)
+}
+
+// registerFlags registers the indexer query txs command flags
+func (c *queryTxsCfg) registerFlags(fs *flag.FlagSet) {
+	c.queryCfg.registerFlags(fs)
+
+	fs.StringVar(
+		&c.address,
+		"address",
+		"",
+		"the address that participated in the transactions",
+	)
+
+	fs.Uint64Var(
+		&c.fromBlock,
+		"from",
+		0,
+		"the starting block height (inclusive)",
+	)
+
+	fs.Uint64Var(
+		&c.toBlock,
+		"to",
+		0,
+		"the ending block height (exclusive), latest by default",
+	)
+}
+
+// execTx executes the indexer query tx command
+func (c *queryCfg) execTx(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errInvalidArgs
+	}
+
+	var encoded *string
+	if err := callRPC(ctx, c.rpc, "getTxResultByHash", []any{args[0]}, &encoded); err != nil {
+		return fmt.Errorf("unable to fetch transaction, %w", err)
+	}
+
+	if encoded == nil {
+		return fmt.Errorf("transaction %s not found", args[0])
+	}
+
+	tx, err := toQueryTx(*encoded)
+	if err != nil {
+		return err
+	}
+
+	return printJSON(out, tx)
+}
+
+// execBlock executes the indexer query block command
+func (c *queryCfg) execBlock(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errInvalidArgs
+	}
+
+	if _, err := strconv.ParseUint(args[0], 10, 64); err != nil {
+		return fmt.Errorf("invalid block height, %w", err)
+	}
+
+	var encoded *string
+	if err := callRPC(ctx, c.rpc, "getBlock", []any{args[0]}, &encoded); err != nil {
+		return fmt.Errorf("unable to fetch block, %w", err)
+	}
+
+	if encoded == nil {
+		return fmt.Errorf("block %s not found", args[0])
+	}
+
+	var block types.Block
+	if err := decodeValue(*encoded, &block); err != nil {
+		return fmt.Errorf("unable to decode block, %w", err)
+	}
+
+	blockJSON, err := amino.MarshalJSON(&block)
+	if err != nil {
+		return fmt.Errorf("unable to marshal block, %w", err)
+	}
+
+	return printJSON(out, json.RawMessage(blockJSON))
+}
+
+// exec executes the indexer query txs command
+func (c *queryTxsCfg) exec(ctx context.Context, out io.Writer) error {
+	if c.address == "" {
+		return errors.New("address is required")
+	}
+
+	var (
+		txs    = make([]*queryTx, 0)
+		cursor string
+	)
+
+	// The pages are fetched until the last one
+	for {
+		params := []any{
+			c.address,
+			strconv.FormatUint(c.fromBlock, 10),
+			strconv.FormatUint(c.toBlock, 10),
+			nil,
+			cursor,
+		}
+
+		var page struct {
+			Cursor string   `json:"cursor"`
+			Txs    []string `json:"txs"`
+		}
+
+		if err := callRPC(ctx, c.rpc, "getTxsByAddress", params, &page); err != nil {
+			return fmt.Errorf("unable to fetch transactions, %w", err)
+		}
+
+		for _, e := range page.Txs {
+			tx, err := toQueryTx(e)
+			if err != nil {
+				return err
+			}
+
+			txs = append(txs, tx)
+		}
+
+		if page.Cursor == "" {
+			return printJSON(out, txs)
+		}
+
+		cursor = page.Cursor
+	}
+}
+
+// toQueryTx converts the base64, amino encoded transaction result
+// into a printable transaction
+func toQueryTx(encoded string) (*queryTx, error) {
+	var txResult types.TxResult
+	if err := decodeValue(encoded, &txResult); err != nil {
+		return nil, fmt.Errorf("unable to decode transaction result, %w", err)
+	}
+
+	tx, err := decode.Tx(txResult.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	txJSON, err := amino.MarshalJSON(tx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	responseJSON, err := amino.MarshalJSON(txResult.Response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal transaction response, %w", err)
+	}
+
+	return &queryTx{
+		Hash:     base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+		Height:   txResult.Height,
+		Index:    txResult.Index,
+		Tx:       txJSON,
+		Response: responseJSON,
+	}, nil
+}
+
+// decodeValue decodes the base64, amino encoded value
+func decodeValue(encoded string, value any) error {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	return amino.Unmarshal(raw, value)
+}
+
+// printJSON prints the value as indented JSON
+func printJSON(out io.Writer, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("unable to marshal output, %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return fmt.Errorf("unable to format output, %w", err)
+	}
+
+	buf.WriteByte('\n')
+
+	_, err = buf.WriteTo(out)
+
+	return err
+}
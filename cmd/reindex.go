@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+var errNoReindexPlugins = errors.New("no plugins to reindex")
+
+type reindexCfg struct {
+	dbPath      string
+	dbNamespace string
+	coldDBPath  string
+	remote      string
+	plugins     string
+
+	decodingRules string
+}
+
+// newReindexCmd creates the indexer plugin reindex command
+func newReindexCmd() *ffcli.Command {
+	cfg := &reindexCfg{}
+
+	fs := flag.NewFlagSet("reindex", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "reindex",
+		ShortUsage: "reindex [flags]",
+		ShortHelp:  "Rebuilds the data of the indexer plugins",
+		LongHelp: "Deletes the data of the given plugins from the indexer DB, " +
+			"and rebuilds it from all the indexed blocks and transactions, " +
+			"backfilling the plugins enabled after the blocks were indexed. " +
+			"The indexer must not be running",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer reindex command flags
+func (c *reindexCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.dbPath,
+		"db-path",
+		defaultDBPath,
+		"the absolute path for the indexer DB (embedded)",
+	)
+
+	fs.StringVar(
+		&c.dbNamespace,
+		"db-namespace",
+		"",
+		"the key namespace (chain / network identifier) of the reindexed data, none by default",
+	)
+
+	fs.StringVar(
+		&c.coldDBPath,
+		"cold-db-path",
+		"",
+		"the absolute path for the cold tier DB of the indexer DB, if any",
+	)
+
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		defaultRemote,
+		"the JSON-RPC URL of the Gno chain, for the plugins fetching chain data",
+	)
+
+	registerDecodingRulesFlag(fs, &c.decodingRules)
+
+	fs.StringVar(
+		&c.plugins,
+		"plugins",
+		"",
+		fmt.Sprintf("the comma separated list of indexer plugins to reindex (%s)", availablePlugins()),
+	)
+}
+
+// exec executes the indexer reindex command
+func (c *reindexCfg) exec(ctx context.Context, out io.Writer) error {
+	pluginNames, err := parsePlugins(c.plugins)
+	if err != nil {
+		return fmt.Errorf("unable to parse plugins, %w", err)
+	}
+
+	if len(pluginNames) == 0 {
+		return errNoReindexPlugins
+	}
+
+	decoder, err := loadDecoder(c.decodingRules)
+	if err != nil {
+		return err
+	}
+
+	dbOpts := []storage.Option{
+		storage.WithNamespace(c.dbNamespace),
+		storage.WithDecoder(decoder),
+	}
+
+	if c.coldDBPath != "" {
+		dbOpts = append(dbOpts, storage.WithColdTier(c.coldDBPath, storage.DefaultColdCompressionLevel))
+	}
+
+	db, err := storage.NewPebble(c.dbPath, dbOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	reindexed, err := c.reindex(ctx, db, decoder, pluginNames)
+	if err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("unable to reindex plugins, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Reindexed %d heights for the plugins %s\n", reindexed, strings.Join(pluginNames, ", "))
+
+	return db.Close()
+}
+
+// reindex wipes the plugin data, and replays all the indexed heights through the plugins
+func (c *reindexCfg) reindex(
+	ctx context.Context,
+	db *storage.Pebble,
+	decoder *decode.Decoder,
+	pluginNames []string,
+) (uint64, error) {
+	latest, err := db.GetLatestHeight()
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	tm2Client, err := client.NewClient(c.remote)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create client, %w", err)
+	}
+
+	defer tm2Client.Close()
+
+	return rebuildPlugins(ctx, db, tm2Client, decoder, pluginNames, latest)
+}
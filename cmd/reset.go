@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// The handlings of the remote chain resets, by flag value
+const (
+	chainResetHalt      = "halt"
+	chainResetResync    = "resync"
+	chainResetNamespace = "namespace"
+)
+
+var chainResetPolicies = []string{chainResetHalt, chainResetResync, chainResetNamespace}
+
+// newResetHook creates the fetcher hook handling the remote chain resets
+// of the storage namespace, by the flag value. The fetcher halts with no hook
+func newResetHook(policy string, store *storage.Pebble, logger *zap.Logger) fetch.ResetHook {
+	switch policy {
+	case chainResetResync:
+		return func(_ context.Context, _ *fetch.ChainResetError) error {
+			logger.Warn(
+				"Wiping the indexed data of the reset chain",
+				zap.String("namespace", store.Namespace()),
+			)
+
+			return store.Wipe()
+		}
+	case chainResetNamespace:
+		return func(_ context.Context, _ *fetch.ChainResetError) error {
+			archive := archiveNamespace(store.Namespace(), time.Now())
+
+			logger.Warn(
+				"Archiving the indexed data of the reset chain",
+				zap.String("namespace", store.Namespace()),
+				zap.String("archive", archive),
+			)
+
+			return store.Archive(archive)
+		}
+	default:
+		return nil
+	}
+}
+
+// archiveNamespace returns the namespace the data of the reset chain is archived to
+func archiveNamespace(namespace string, resetAt time.Time) string {
+	archive := fmt.Sprintf("reset-%d", resetAt.Unix())
+	if namespace != "" {
+		archive = fmt.Sprintf("%s-%s", namespace, archive)
+	}
+
+	return archive
+}
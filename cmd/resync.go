@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/client"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+var errStalePlugins = errors.New("the DB holds the data of plugins not rebuilt by the resync")
+
+type resyncCfg struct {
+	dbPath      string
+	dbNamespace string
+	coldDBPath  string
+	remote      string
+	plugins     string
+
+	decodingRules string
+
+	dbCompressionLevel     int
+	coldDBCompressionLevel int
+
+	from     uint64
+	to       uint64
+	maxSlots int
+}
+
+// newResyncCmd creates the indexer resync command
+func newResyncCmd() *ffcli.Command {
+	cfg := &resyncCfg{}
+
+	fs := flag.NewFlagSet("resync", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "resync",
+		ShortUsage: "resync [flags]",
+		ShortHelp:  "Re-fetches a height range of the indexer DB",
+		LongHelp: "Deletes the blocks and transactions of the height range from the indexer DB, " +
+			"and re-fetches them from the remote node, rebuilding their index entries. " +
+			"The data of the given plugins is rebuilt from all the indexed heights, and the resync " +
+			"is refused if the DB holds the data of other plugins. The indexer must not be running",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer resync command flags
+func (c *resyncCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.dbPath,
+		"db-path",
+		defaultDBPath,
+		"the absolute path for the indexer DB (embedded)",
+	)
+
+	fs.StringVar(
+		&c.dbNamespace,
+		"db-namespace",
+		"",
+		"the key namespace (chain / network identifier) of the resynced data, none by default",
+	)
+
+	fs.StringVar(
+		&c.coldDBPath,
+		"cold-db-path",
+		"",
+		"the absolute path for the cold tier DB of the indexer DB, if any",
+	)
+
+	fs.IntVar(
+		&c.dbCompressionLevel,
+		"db-compression-level",
+		storage.DefaultCompressionLevel,
+		"the zstd level of the stored blocks and transactions. Level 0 disables the compression",
+	)
+
+	fs.IntVar(
+		&c.coldDBCompressionLevel,
+		"cold-db-compression-level",
+		storage.DefaultColdCompressionLevel,
+		"the zstd level of the blocks and transactions stored in the cold tier DB",
+	)
+
+	fs.StringVar(
+		&c.remote,
+		"remote",
+		defaultRemote,
+		"the JSON-RPC URL of the Gno chain",
+	)
+
+	registerDecodingRulesFlag(fs, &c.decodingRules)
+
+	fs.StringVar(
+		&c.plugins,
+		"plugins",
+		"",
+		fmt.Sprintf(
+			"the comma separated list of indexer plugins enabled for the DB, rebuilt after the resync (%s)",
+			availablePlugins(),
+		),
+	)
+
+	fs.Uint64Var(
+		&c.from,
+		"from",
+		0,
+		"the first resynced height",
+	)
+
+	fs.Uint64Var(
+		&c.to,
+		"to",
+		0,
+		"the last resynced height, up to the latest indexed height",
+	)
+
+	fs.IntVar(
+		&c.maxSlots,
+		"max-slots",
+		fetch.DefaultMaxSlots,
+		"the amount of slots (workers) fetching the heights",
+	)
+}
+
+// exec executes the indexer resync command
+func (c *resyncCfg) exec(ctx context.Context, out io.Writer) error {
+	if c.to < c.from {
+		return errors.New("the resync range end is below its start")
+	}
+
+	pluginNames, err := parsePlugins(c.plugins)
+	if err != nil {
+		return fmt.Errorf("unable to parse plugins, %w", err)
+	}
+
+	// The index entries (and the plugin data) are rebuilt with the chain decoder
+	decoder, err := loadDecoder(c.decodingRules)
+	if err != nil {
+		return err
+	}
+
+	dbOpts := []storage.Option{
+		storage.WithNamespace(c.dbNamespace),
+		storage.WithCompressionLevel(c.dbCompressionLevel),
+		storage.WithDecoder(decoder),
+	}
+
+	if c.coldDBPath != "" {
+		dbOpts = append(dbOpts, storage.WithColdTier(c.coldDBPath, c.coldDBCompressionLevel))
+	}
+
+	db, err := storage.NewPebble(c.dbPath, dbOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to open storage DB, %w", err)
+	}
+
+	resynced, err := c.resync(ctx, db, decoder, pluginNames)
+	if err != nil {
+		_ = db.Close()
+
+		return fmt.Errorf("unable to resync heights, %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Resynced %d heights from %d to %d\n", resynced, c.from, c.to)
+
+	return db.Close()
+}
+
+// resync re-fetches the height range, up to the latest indexed height, and rebuilds the plugin data.
+// The plugin data is aggregated over the whole chain, so it's rebuilt from all the indexed heights
+func (c *resyncCfg) resync(
+	ctx context.Context,
+	db *storage.Pebble,
+	decoder *decode.Decoder,
+	pluginNames []string,
+) (uint64, error) {
+	latest, err := db.GetLatestHeight()
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	if c.to > latest {
+		return 0, fmt.Errorf("the resync range end is above the latest indexed height %d", latest)
+	}
+
+	// The data of the plugins not rebuilt would be left stale
+	saved, err := pluginsWithData(db)
+	if err != nil {
+		return 0, err
+	}
+
+	if stale := slices.DeleteFunc(saved, func(name string) bool {
+		return slices.Contains(pluginNames, name)
+	}); len(stale) > 0 {
+		return 0, fmt.Errorf(
+			"%w: %s, set them with --plugins", errStalePlugins, strings.Join(stale, ", "),
+		)
+	}
+
+	tm2Client, err := client.NewClient(c.remote)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create client, %w", err)
+	}
+
+	defer tm2Client.Close()
+
+	resynced, err := fetch.Resync(ctx, db, tm2Client, c.from, c.to, c.maxSlots)
+	if err != nil || len(pluginNames) == 0 {
+		return resynced, err
+	}
+
+	if _, err := rebuildPlugins(ctx, db, tm2Client, decoder, pluginNames, latest); err != nil {
+		return resynced, fmt.Errorf("unable to rebuild plugins (rerun the reindex command), %w", err)
+	}
+
+	return resynced, nil
+}
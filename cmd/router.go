@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve"
+	"github.com/gnolang/tx-indexer/shard"
+)
+
+var errInvalidShardConfig = errors.New("invalid shard configuration")
+
+type routerCfg struct {
+	listenAddress string
+	logLevel      string
+
+	shards shardsFlag
+}
+
+// newRouterCmd creates the shard router command
+func newRouterCmd() *ffcli.Command {
+	cfg := &routerCfg{}
+
+	fs := flag.NewFlagSet("router", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "router",
+		ShortUsage: "router [flags]",
+		ShortHelp:  "Starts the shard router",
+		LongHelp: "Starts the JSON-RPC router of the indexer shards, each indexing a height range of the chain, " +
+			"which routes the queries to the shards indexing the queried heights, and aggregates the results",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx)
+		},
+	}
+}
+
+// registerFlags registers the shard router command flags
+func (c *routerCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.listenAddress,
+		"listen-address",
+		serve.DefaultListenAddress,
+		"the IP:PORT URL for the router JSON-RPC server",
+	)
+
+	fs.StringVar(
+		&c.logLevel,
+		"log-level",
+		zap.InfoLevel.String(),
+		"the log level for the CLI output",
+	)
+
+	fs.Var(
+		&c.shards,
+		"shard",
+		"the indexer shard, in the format url=<url>,from=<height>[,to=<height>] (repeatable). "+
+			"The shard without the end height follows the chain tip",
+	)
+}
+
+// exec executes the shard router command
+func (c *routerCfg) exec(ctx context.Context) error {
+	// Parse the log level
+	logLevel, err := zap.ParseAtomicLevel(c.logLevel)
+	if err != nil {
+		return fmt.Errorf("unable to parse log level, %w", err)
+	}
+
+	cfg := zap.NewDevelopmentConfig()
+	cfg.Level = logLevel
+
+	// Create a new logger
+	logger, err := cfg.Build()
+	if err != nil {
+		return fmt.Errorf("unable to create logger, %w", err)
+	}
+
+	router, err := shard.NewRouter(c.shards, shard.WithLogger(logger.Named("router")))
+	if err != nil {
+		return fmt.Errorf("unable to create shard router, %w", err)
+	}
+
+	// Create a new waiter
+	w := newWaiter(ctx)
+
+	// Create the HTTP server
+	hs := serve.NewHTTPServer(router, c.listenAddress, logger.Named("http-server"))
+
+	// Add the JSON-RPC service
+	w.add(hs.Serve)
+
+	// Wait for the services to stop
+	return errors.Join(
+		w.wait(),
+		logger.Sync(),
+	)
+}
+
+// shardsFlag is a repeatable flag containing shard configurations,
+// in the format: url=<url>,from=<height>[,to=<height>]
+type shardsFlag []shard.Shard
+
+func (s *shardsFlag) String() string {
+	shards := make([]string, 0, len(*s))
+
+	for _, sh := range *s {
+		shards = append(shards, fmt.Sprintf("url=%s,from=%d,to=%d", sh.URL, sh.From, sh.To))
+	}
+
+	return strings.Join(shards, " ")
+}
+
+func (s *shardsFlag) Set(value string) error {
+	var sh shard.Shard
+
+	for _, field := range strings.Split(value, ",") {
+		key, val, found := strings.Cut(field, "=")
+		if !found {
+			return fmt.Errorf("%w, field %q", errInvalidShardConfig, field)
+		}
+
+		key = strings.TrimSpace(key)
+
+		switch key {
+		case "url":
+			sh.URL = strings.TrimSpace(val)
+		case "from", "to":
+			height, err := strconv.ParseUint(strings.TrimSpace(val), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w, invalid height, %w", errInvalidShardConfig, err)
+			}
+
+			if key == "from" {
+				sh.From = height
+			} else {
+				sh.To = height
+			}
+		default:
+			return fmt.Errorf("%w, unknown field %q", errInvalidShardConfig, key)
+		}
+	}
+
+	if sh.URL == "" {
+		return fmt.Errorf("%w, url is required", errInvalidShardConfig)
+	}
+
+	if sh.To != 0 && sh.To < sh.From {
+		return fmt.Errorf("%w, end height below start height", errInvalidShardConfig)
+	}
+
+	*s = append(*s, sh)
+
+	return nil
+}
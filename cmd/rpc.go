@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const defaultIndexerRPC = "http://127.0.0.1:8546"
+
+// rpcResponse is the raw JSON-RPC response of the indexer
+type rpcResponse struct {
+	Error  *spec.BaseJSONError `json:"error,omitempty"`
+	Result json.RawMessage     `json:"result"`
+}
+
+// callRPC executes a single JSON-RPC request against a running indexer,
+// and decodes the result into the given value
+func callRPC(
+	ctx context.Context,
+	remote,
+	method string,
+	params []any,
+	result any,
+) error {
+	if params == nil {
+		params = []any{}
+	}
+
+	body, err := json.Marshal(spec.NewJSONRequest(1, method, params))
+	if err != nil {
+		return fmt.Errorf("unable to marshal request, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, remote, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request, %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request, %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status, %s", resp.Status)
+	}
+
+	var response rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("unable to decode response, %w", err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("%s (code %d)", response.Error.Message, response.Error.Code)
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unable to decode result, %w", err)
+	}
+
+	return nil
+}
@@ -2,23 +2,46 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httprate"
 	"github.com/peterbourgon/ff/v3/ffcli"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/gnolang/tx-indexer/alerts"
+	"github.com/gnolang/tx-indexer/apikeys"
+	"github.com/gnolang/tx-indexer/audit"
 	"github.com/gnolang/tx-indexer/client"
-	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/export"
 	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/indexer"
+	"github.com/gnolang/tx-indexer/leader"
+	"github.com/gnolang/tx-indexer/logrotate"
+	"github.com/gnolang/tx-indexer/objstore"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/replication"
 	"github.com/gnolang/tx-indexer/serve"
+	"github.com/gnolang/tx-indexer/serve/filters"
 	"github.com/gnolang/tx-indexer/serve/graph"
+	"github.com/gnolang/tx-indexer/sinks/clickhouse"
+	"github.com/gnolang/tx-indexer/sinks/elasticsearch"
 	"github.com/gnolang/tx-indexer/storage"
+	"github.com/gnolang/tx-indexer/systemd"
 )
 
 const (
@@ -26,16 +49,110 @@ const (
 	defaultDBPath = "indexer-db"
 )
 
+// conflictPolicies are the storage handlings of the conflicting saves, by flag value
+var conflictPolicies = map[string]storage.ConflictPolicy{
+	"overwrite": storage.ConflictOverwrite,
+	"skip":      storage.ConflictSkip,
+	"error":     storage.ConflictError,
+}
+
 type startCfg struct {
 	listenAddress string
 	remote        string
 	dbPath        string
+	dbNamespace   string
 	logLevel      string
 
+	daemonize      bool
+	pidFile        string
+	logFile        string
+	logFileMaxSize int64
+	logFileBackups int
+
+	dbCompressionLevel int
+	readOnly           bool
+	writeConflicts     string
+	chainReset         string
+
+	coldDBPath             string
+	coldDBCompressionLevel int
+	hotHeights             uint64
+
+	bootstrapFrom     string
+	bootstrapChecksum string
+
+	pruneHeights         uint64
+	pruneArchive         string
+	pruneArchiveEndpoint string
+	pruneArchiveRegion   string
+	pruneRestoreLimit    int
+	pruneRestoredTTL     time.Duration
+	pruneRestoreTimeout  time.Duration
+
+	chains chainsFlag
+
+	remoteTimeout      time.Duration
+	remoteDialTimeout  time.Duration
+	remoteKeepAlive    time.Duration
+	remoteMaxIdleConns int
+
+	remoteSlowCallThreshold time.Duration
+
+	remoteHeaders     headersFlag
+	remoteBearerToken string
+	remoteBasicAuth   string
+
 	maxSlots     int
 	maxChunkSize int64
+	startHeight  uint64
+	endHeight    uint64
+
+	flushSize        int
+	flushInterval    time.Duration
+	persistQueueSize int
+	newestFirst      uint64
+
+	rateLimit       int
+	metrics         bool
+	shutdownTimeout time.Duration
+
+	ipAllowlist    string
+	ipDenylist     string
+	trustedProxies string
+
+	apiKeys    bool
+	adminToken string
 
-	rateLimit int
+	wsPingPeriod    time.Duration
+	wsIdleTimeout   time.Duration
+	wsMaxConns      int
+	wsMaxConnsPerIP int
+	wsRateLimit     int
+
+	maxSubscriptions        int
+	maxSubscriptionsPerConn int
+
+	replicationListenAddress    string
+	replicationAdvertiseAddress string
+	replicateFrom               string
+	leaderLock                  string
+	replicationTLSCert          string
+	replicationTLSKey           string
+	replicationTLSCA            string
+	replicationToken            string
+
+	plugins    string
+	webhooks   bool
+	alertRules string
+	exportJobs string
+
+	decodingRules string
+
+	elasticsearchURL    string
+	elasticsearchPrefix string
+
+	clickhouseURL      string
+	clickhouseDatabase string
 }
 
 // newStartCmd creates the indexer start command
@@ -70,7 +187,62 @@ func (c *startCfg) registerFlags(fs *flag.FlagSet) {
 		&c.remote,
 		"remote",
 		defaultRemote,
-		"the JSON-RPC URL of the Gno chain",
+		"the JSON-RPC URL of the Gno chain (http:// or ws://)",
+	)
+
+	fs.DurationVar(
+		&c.remoteTimeout,
+		"remote-timeout",
+		client.DefaultRequestTimeout,
+		"the timeout of a single (or batch) request to the remote",
+	)
+
+	fs.DurationVar(
+		&c.remoteDialTimeout,
+		"remote-dial-timeout",
+		client.DefaultDialTimeout,
+		"the timeout for connecting to the remote",
+	)
+
+	fs.DurationVar(
+		&c.remoteKeepAlive,
+		"remote-keep-alive",
+		client.DefaultKeepAlive,
+		"the keep-alive period of the remote connections. A negative period disables the keep-alives",
+	)
+
+	fs.IntVar(
+		&c.remoteMaxIdleConns,
+		"remote-max-idle-conns",
+		client.DefaultMaxIdleConns,
+		"the maximum number of idle remote connections kept for reuse",
+	)
+
+	fs.DurationVar(
+		&c.remoteSlowCallThreshold,
+		"remote-slow-call-threshold",
+		0,
+		"the duration above which the remote requests are logged as slow, disabled by default",
+	)
+
+	fs.Var(
+		&c.remoteHeaders,
+		"remote-header",
+		"a custom header sent with the remote requests, in the format <key>: <value> (repeatable)",
+	)
+
+	fs.StringVar(
+		&c.remoteBearerToken,
+		"remote-bearer-token",
+		"",
+		"the bearer token authenticating the remote requests, if any",
+	)
+
+	fs.StringVar(
+		&c.remoteBasicAuth,
+		"remote-basic-auth",
+		"",
+		"the basic auth credentials of the remote requests, in the format <username>:<password>, if any",
 	)
 
 	fs.StringVar(
@@ -80,6 +252,126 @@ func (c *startCfg) registerFlags(fs *flag.FlagSet) {
 		"the absolute path for the indexer DB (embedded)",
 	)
 
+	fs.StringVar(
+		&c.dbNamespace,
+		"db-namespace",
+		"",
+		"the key namespace (chain / network identifier) for the indexed data, none by default. "+
+			"Not allowed with the chain flag, as the chain names are the namespaces",
+	)
+
+	fs.IntVar(
+		&c.dbCompressionLevel,
+		"db-compression-level",
+		storage.DefaultCompressionLevel,
+		"the zstd level of the stored blocks and transactions. Level 0 disables the compression",
+	)
+
+	fs.StringVar(
+		&c.writeConflicts,
+		"write-conflicts",
+		"overwrite",
+		"the handling of the saves conflicting with the indexed data (overwrite, skip, error)",
+	)
+
+	fs.StringVar(
+		&c.chainReset,
+		"chain-reset",
+		chainResetHalt,
+		"the handling of the remote chain restarted below the indexed height (halt, resync, namespace)",
+	)
+
+	fs.StringVar(
+		&c.coldDBPath,
+		"cold-db-path",
+		"",
+		"the absolute path for the cold tier DB, holding the heights older than the hot heights, disabled by default",
+	)
+
+	fs.IntVar(
+		&c.coldDBCompressionLevel,
+		"cold-db-compression-level",
+		storage.DefaultColdCompressionLevel,
+		"the zstd level of the blocks and transactions moved to the cold tier DB",
+	)
+
+	fs.Uint64Var(
+		&c.hotHeights,
+		"hot-heights",
+		storage.DefaultHotHeights,
+		"the number of most recent heights kept in the indexer DB, when the cold tier DB is set",
+	)
+
+	fs.StringVar(
+		&c.bootstrapFrom,
+		"bootstrap-from",
+		"",
+		"the URL or path of the DB snapshot (full backup, optionally .gz or .zst compressed) restored to the empty indexer DB before the chain is fetched, if any",
+	)
+
+	fs.StringVar(
+		&c.bootstrapChecksum,
+		"bootstrap-checksum",
+		"",
+		"the hex SHA-256 checksum of the bootstrap snapshot file (as downloaded), required with the snapshot bootstrap",
+	)
+
+	fs.Uint64Var(
+		&c.pruneHeights,
+		"prune-heights",
+		0,
+		"the number of most recent heights kept, the older heights are pruned in segments of 1000 heights. Pruning is disabled by default",
+	)
+
+	fs.StringVar(
+		&c.pruneArchive,
+		"prune-archive",
+		"",
+		"the directory path or s3://<bucket>/<prefix> URL the pruned segments are archived to, and restored from on demand, if any",
+	)
+
+	fs.StringVar(
+		&c.pruneArchiveEndpoint,
+		"prune-archive-endpoint",
+		"",
+		"the S3 API endpoint of the prune archive, for the S3 compatible stores (GCS, MinIO). AWS S3 by default",
+	)
+
+	fs.StringVar(
+		&c.pruneArchiveRegion,
+		"prune-archive-region",
+		objstore.DefaultS3Region,
+		"the bucket region of the S3 prune archive",
+	)
+
+	fs.IntVar(
+		&c.pruneRestoreLimit,
+		"prune-restore-limit",
+		storage.DefaultRestoreLimit,
+		"the maximum number of pruned segments restored from the prune archive per minute, 0 disables the on-demand restores",
+	)
+
+	fs.DurationVar(
+		&c.pruneRestoredTTL,
+		"prune-restored-ttl",
+		storage.DefaultRestoredSegmentTTL,
+		"the time the segments restored from the prune archive are kept for, before being pruned again",
+	)
+
+	fs.DurationVar(
+		&c.pruneRestoreTimeout,
+		"prune-restore-timeout",
+		storage.DefaultRestoreTimeout,
+		"the timeout of fetching a single segment restored from the prune archive, for the read of its heights",
+	)
+
+	fs.BoolVar(
+		&c.readOnly,
+		"read-only",
+		false,
+		"serve the queries from the existing indexer DB, opened in read-only mode, without fetching the chain",
+	)
+
 	fs.StringVar(
 		&c.logLevel,
 		"log-level",
@@ -87,6 +379,41 @@ func (c *startCfg) registerFlags(fs *flag.FlagSet) {
 		"the log level for the CLI output",
 	)
 
+	fs.BoolVar(
+		&c.daemonize,
+		"daemonize",
+		false,
+		"run the indexer in the background, detached from the terminal. Requires the log file",
+	)
+
+	fs.StringVar(
+		&c.pidFile,
+		"pid-file",
+		"",
+		"the path to the file the indexer process PID is written to, while running, if any",
+	)
+
+	fs.StringVar(
+		&c.logFile,
+		"log-file",
+		"",
+		"the path to the log file, rotated once it reaches the max size. The logs are written to stderr by default",
+	)
+
+	fs.Int64Var(
+		&c.logFileMaxSize,
+		"log-file-max-size",
+		logrotate.DefaultMaxSize>>20,
+		"the size (in MB) of the log file, before it's rotated",
+	)
+
+	fs.IntVar(
+		&c.logFileBackups,
+		"log-file-backups",
+		logrotate.DefaultMaxBackups,
+		"the number of the rotated log files kept",
+	)
+
 	fs.IntVar(
 		&c.maxSlots,
 		"max-slots",
@@ -101,12 +428,317 @@ func (c *startCfg) registerFlags(fs *flag.FlagSet) {
 		"the range for fetching blockchain data by a single worker",
 	)
 
+	fs.IntVar(
+		&c.flushSize,
+		"flush-size",
+		fetch.DefaultFlushSize,
+		"the number of fetched blocks buffered before being written to storage",
+	)
+
+	fs.DurationVar(
+		&c.flushInterval,
+		"flush-interval",
+		fetch.DefaultFlushInterval,
+		"the maximum time the fetched blocks are buffered before being written to storage",
+	)
+
+	fs.IntVar(
+		&c.persistQueueSize,
+		"persist-queue-size",
+		fetch.DefaultPersistQueueSize,
+		"the number of fetched chunks queued for writing to storage, before the workers wait for the writes",
+	)
+
+	fs.Uint64Var(
+		&c.newestFirst,
+		"newest-first",
+		0,
+		"the number of most recent heights indexed first, before backfilling the chain history, disabled by default",
+	)
+
+	fs.Uint64Var(
+		&c.startHeight,
+		"start-height",
+		0,
+		"the height from which the indexer starts indexing the chain",
+	)
+
+	fs.Uint64Var(
+		&c.endHeight,
+		"end-height",
+		0,
+		"the height up to which the indexer indexes the chain, for indexing a shard of the chain history. "+
+			"The chain is indexed up to the tip by default",
+	)
+
+	fs.Var(
+		&c.chains,
+		"chain",
+		"the chain to index, in the format "+
+			"name=<name>,remote=<url>[,start-height=<height>][,end-height=<height>][,decoding-rules=<path>] (repeatable). "+
+			"If set, the remote, start-height and end-height flags are ignored, and the chain is selected with the ?chain= URL parameter. "+
+			"The names can only contain lowercase letters, digits and dashes",
+	)
+
 	fs.IntVar(
 		&c.rateLimit,
 		"http-rate-limit",
 		0,
 		"the maximum HTTP requests allowed per minute per IP, unlimited by default",
 	)
+
+	fs.StringVar(
+		&c.ipAllowlist,
+		"ip-allowlist",
+		"",
+		"the comma separated list of the CIDRs (or IPs) of the clients allowed to use the API, all by default",
+	)
+
+	fs.StringVar(
+		&c.ipDenylist,
+		"ip-denylist",
+		"",
+		"the comma separated list of the CIDRs (or IPs) of the clients denied the API, none by default",
+	)
+
+	fs.StringVar(
+		&c.trustedProxies,
+		"trusted-proxies",
+		"",
+		"the comma separated list of the CIDRs (or IPs) of the proxies whose X-Forwarded-For and X-Real-IP headers are trusted, for the IP lists",
+	)
+
+	fs.BoolVar(
+		&c.apiKeys,
+		"api-keys",
+		false,
+		"require an API key for the API requests, enforcing the daily and monthly quotas of the keys managed with the admin methods",
+	)
+
+	fs.StringVar(
+		&c.adminToken,
+		"admin-token",
+		"",
+		"the token of the X-Admin-Token header required by the admin methods, which are disabled if not set",
+	)
+
+	fs.DurationVar(
+		&c.wsPingPeriod,
+		"ws-ping-period",
+		serve.DefaultWSPingPeriod,
+		"the period of the pings sent to the WS clients, kept below the WS idle timeout",
+	)
+
+	fs.DurationVar(
+		&c.wsIdleTimeout,
+		"ws-idle-timeout",
+		serve.DefaultWSIdleTimeout,
+		"the time the WS connections are kept open without a pong from the client, before being closed",
+	)
+
+	fs.IntVar(
+		&c.wsMaxConns,
+		"ws-max-conns",
+		0,
+		"the maximum number of open WS connections, unlimited by default",
+	)
+
+	fs.IntVar(
+		&c.wsMaxConnsPerIP,
+		"ws-max-conns-per-ip",
+		0,
+		"the maximum number of open WS connections per IP, unlimited by default",
+	)
+
+	fs.IntVar(
+		&c.wsRateLimit,
+		"ws-rate-limit",
+		0,
+		"the maximum WS requests allowed per minute per IP, unlimited by default",
+	)
+
+	fs.IntVar(
+		&c.maxSubscriptions,
+		"max-subscriptions",
+		0,
+		"the maximum number of active WS subscriptions, unlimited by default",
+	)
+
+	fs.IntVar(
+		&c.maxSubscriptionsPerConn,
+		"max-subscriptions-per-conn",
+		0,
+		"the maximum number of active subscriptions per WS connection, unlimited by default",
+	)
+
+	fs.DurationVar(
+		&c.shutdownTimeout,
+		"shutdown-timeout",
+		serve.DefaultShutdownTimeout,
+		"the time the in-flight JSON-RPC requests are given to finish on shutdown",
+	)
+
+	fs.BoolVar(
+		&c.metrics,
+		"metrics",
+		false,
+		"expose the Prometheus metrics (remote and served request latencies and errors) at /metrics",
+	)
+
+	fs.StringVar(
+		&c.replicationListenAddress,
+		"replication-listen-address",
+		"",
+		"the IP:PORT address of the gRPC server streaming the indexed data to the standby instances, disabled by default",
+	)
+
+	fs.StringVar(
+		&c.replicationAdvertiseAddress,
+		"replication-advertise-address",
+		"",
+		"the replication address advertised to the followers once elected leader, the replication listen address by default",
+	)
+
+	fs.StringVar(
+		&c.leaderLock,
+		"leader-lock",
+		"",
+		"the path to the lock file shared by the indexer instances electing the leader, which runs the fetcher "+
+			"while the others replicate it. Leader election is disabled by default",
+	)
+
+	fs.StringVar(
+		&c.replicateFrom,
+		"replicate-from",
+		"",
+		"the replication address of the primary indexer. If set, the data is replicated from the primary instead of fetched",
+	)
+
+	fs.StringVar(
+		&c.replicationTLSCert,
+		"replication-tls-cert",
+		"",
+		"the PEM certificate file of the replication TLS, served by the primary and presented by the standby (mTLS)",
+	)
+
+	fs.StringVar(
+		&c.replicationTLSKey,
+		"replication-tls-key",
+		"",
+		"the PEM key file of the replication TLS certificate",
+	)
+
+	fs.StringVar(
+		&c.replicationTLSCA,
+		"replication-tls-ca",
+		"",
+		"the PEM CA file verifying the replication peers. If set, the primary requires the standby certificates signed by it (mTLS). "+
+			"The standby verifies the primary with the system roots if not set",
+	)
+
+	fs.StringVar(
+		&c.replicationToken,
+		"replication-token",
+		"",
+		"the token shared by the primary and the standby instances, required by the primary unless mTLS is set",
+	)
+
+	fs.StringVar(
+		&c.plugins,
+		"plugins",
+		"",
+		fmt.Sprintf("the comma separated list of indexer plugins to enable (%s), none by default", availablePlugins()),
+	)
+
+	fs.BoolVar(
+		&c.webhooks,
+		"webhooks",
+		false,
+		"allow the address watchlist notifications to be delivered to webhooks registered over the JSON-RPC API",
+	)
+
+	fs.StringVar(
+		&c.alertRules,
+		"alert-rules",
+		"",
+		"the path to the JSON alert rules configuration file, if any",
+	)
+
+	fs.StringVar(
+		&c.exportJobs,
+		"export-jobs",
+		"",
+		"the path to the JSON scheduled export jobs configuration file, if any",
+	)
+
+	registerDecodingRulesFlag(fs, &c.decodingRules)
+
+	fs.StringVar(
+		&c.elasticsearchURL,
+		"elasticsearch-url",
+		"",
+		"the Elasticsearch (OpenSearch) URL the indexed data is mirrored to, if any",
+	)
+
+	fs.StringVar(
+		&c.elasticsearchPrefix,
+		"elasticsearch-index-prefix",
+		elasticsearch.DefaultIndexPrefix,
+		"the prefix of the Elasticsearch index names. In multi-chain mode, it is followed by the chain name",
+	)
+
+	fs.StringVar(
+		&c.clickhouseURL,
+		"clickhouse-url",
+		"",
+		"the ClickHouse HTTP interface URL the transaction, message and event rows are mirrored to, if any",
+	)
+
+	fs.StringVar(
+		&c.clickhouseDatabase,
+		"clickhouse-database",
+		clickhouse.DefaultDatabase,
+		"the ClickHouse database of the mirrored tables. In multi-chain mode, the table names are prefixed with the chain name",
+	)
+}
+
+// newLogger creates the indexer logger, writing to the rotated log file if set.
+// The returned function closes the log file
+func (c *startCfg) newLogger(level zap.AtomicLevel) (*zap.Logger, func(), error) {
+	cfg := zap.NewDevelopmentConfig()
+	cfg.Level = level
+
+	if c.logFile == "" {
+		logger, err := cfg.Build()
+
+		return logger, func() {}, err
+	}
+
+	file, err := logrotate.New(
+		c.logFile,
+		logrotate.WithMaxSize(c.logFileMaxSize<<20),
+		logrotate.WithMaxBackups(c.logFileBackups),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	core := zapcore.NewCore(
+		zapcore.NewConsoleEncoder(cfg.EncoderConfig),
+		zapcore.AddSync(file),
+		level,
+	)
+
+	logger := zap.New(
+		core,
+		zap.Development(),
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.WarnLevel),
+	)
+
+	return logger, func() {
+		_ = file.Close()
+	}, nil
 }
 
 // exec executes the indexer start command
@@ -117,18 +749,262 @@ func (c *startCfg) exec(ctx context.Context) error {
 		return fmt.Errorf("unable to parse log level, %w", err)
 	}
 
-	cfg := zap.NewDevelopmentConfig()
-	cfg.Level = logLevel
+	if c.daemonize && c.logFile == "" {
+		return errors.New("the daemon mode requires the log file")
+	}
+
+	if c.logFile != "" && (c.logFileMaxSize <= 0 || c.logFileBackups < 0) {
+		return errors.New("the log file max size needs to be positive, and the backups non-negative")
+	}
+
+	// Start the daemon process, if not already the daemon
+	if c.daemonize && !isDaemon() {
+		return daemonize()
+	}
+
+	// Write the process PID, if set
+	if c.pidFile != "" {
+		removePIDFile, pidErr := writePIDFile(c.pidFile)
+		if pidErr != nil {
+			return pidErr
+		}
+
+		defer removePIDFile()
+	}
 
 	// Create a new logger
-	logger, err := cfg.Build()
+	logger, closeLogger, err := c.newLogger(logLevel)
 	if err != nil {
 		return fmt.Errorf("unable to create logger, %w", err)
 	}
 
+	defer closeLogger()
+
+	if c.readOnly && c.replicateFrom != "" {
+		return errors.New("the read-only mode and replication from a primary are mutually exclusive")
+	}
+
+	if c.leaderLock != "" && (c.readOnly || c.replicateFrom != "") {
+		return errors.New("leader election is exclusive with the read-only mode and replication from a primary")
+	}
+
+	if c.bootstrapFrom != "" && (c.readOnly || len(c.chains) != 0) {
+		return errors.New("the snapshot bootstrap is exclusive with the read-only and multi-chain modes")
+	}
+
+	if c.bootstrapFrom != "" {
+		if sum, err := hex.DecodeString(c.bootstrapChecksum); err != nil || len(sum) != sha256.Size {
+			return errors.New("the snapshot bootstrap requires the hex SHA-256 checksum of the snapshot")
+		}
+	} else if c.bootstrapChecksum != "" {
+		return errors.New("the bootstrap checksum requires the snapshot bootstrap")
+	}
+
+	if c.apiKeys && c.adminToken == "" {
+		return errors.New("the API keys require the admin token, for managing the keys")
+	}
+
+	if c.adminToken != "" && (c.readOnly || c.replicateFrom != "") {
+		return errors.New("the admin methods are exclusive with the read-only mode and replication from a primary")
+	}
+
+	if c.coldDBPath != "" && c.hotHeights == 0 {
+		return errors.New("the cold tier requires at least one hot height")
+	}
+
+	conflictPolicy, ok := conflictPolicies[c.writeConflicts]
+	if !ok {
+		return fmt.Errorf("invalid write conflict handling %q", c.writeConflicts)
+	}
+
+	if !slices.Contains(chainResetPolicies, c.chainReset) {
+		return fmt.Errorf("invalid chain reset handling %q", c.chainReset)
+	}
+
+	if c.endHeight != 0 && c.endHeight < c.startHeight {
+		return errors.New("the end height is below the start height")
+	}
+
+	if c.wsIdleTimeout <= 0 || c.wsPingPeriod <= 0 {
+		return errors.New("the WS ping period and idle timeout need to be positive")
+	}
+
+	if c.shutdownTimeout <= 0 {
+		return errors.New("the shutdown timeout needs to be positive")
+	}
+
+	if c.wsMaxConns < 0 || c.wsMaxConnsPerIP < 0 {
+		return errors.New("the WS connection limits can't be negative")
+	}
+
+	if c.wsRateLimit < 0 {
+		return errors.New("the WS rate limit can't be negative")
+	}
+
+	if c.maxSubscriptions < 0 || c.maxSubscriptionsPerConn < 0 {
+		return errors.New("the subscription limits can't be negative")
+	}
+
+	if c.leaderLock != "" && c.replicationListenAddress == "" {
+		return errors.New("leader election requires the replication listen address, for the followers")
+	}
+
+	replicating := c.replicationListenAddress != "" || c.replicateFrom != ""
+
+	if c.replicationListenAddress != "" && (c.replicationTLSCert == "" || c.replicationTLSKey == "") {
+		return errors.New("the replication server requires the replication TLS certificate and key")
+	}
+
+	if replicating && c.replicationToken == "" &&
+		(c.replicationTLSCA == "" || c.replicationTLSCert == "" || c.replicationTLSKey == "") {
+		return errors.New("the replication requires the replication token, or the TLS client certificates (mTLS)")
+	}
+
+	// Resolve the enabled plugins
+	pluginNames, err := parsePlugins(c.plugins)
+	if err != nil {
+		return fmt.Errorf("unable to parse plugins, %w", err)
+	}
+
+	// Resolve the remote authentication, if any
+	authOpts, err := c.authOptions()
+	if err != nil {
+		return fmt.Errorf("unable to parse remote authentication, %w", err)
+	}
+
+	// Load the decoding rules of the chain upgrades, if any.
+	// The chains without their own decoding rules use these
+	decoder, err := loadDecoder(c.decodingRules)
+	if err != nil {
+		return err
+	}
+
+	// Load the alert rules, if any
+	var alertRules []*alerts.Rule
+
+	if c.alertRules != "" {
+		if alertRules, err = alerts.LoadRules(c.alertRules); err != nil {
+			return fmt.Errorf("unable to load alert rules, %w", err)
+		}
+	}
+
+	// Load the export jobs, if any
+	var exportJobs []*export.Job
+
+	if c.exportJobs != "" {
+		if exportJobs, err = export.LoadJobs(c.exportJobs); err != nil {
+			return fmt.Errorf("unable to load export jobs, %w", err)
+		}
+	}
+
+	// Resolve the chains that should be indexed.
+	// If no chains are explicitly configured, the indexer
+	// runs in single-chain mode
+	chains := c.chains
+	multiChain := len(chains) != 0
+
+	if multiChain && c.dbNamespace != "" {
+		return errChainsNamespace
+	}
+
+	if !multiChain {
+		chains = chainsFlag{
+			{
+				remote:      c.remote,
+				startHeight: c.startHeight,
+				endHeight:   c.endHeight,
+			},
+		}
+	}
+
 	// Create a DB instance
-	db, err := storage.NewPebble(c.dbPath)
+	dbOpts := []storage.Option{
+		storage.WithNamespace(c.dbNamespace),
+		storage.WithCompressionLevel(c.dbCompressionLevel),
+		storage.WithConflictPolicy(conflictPolicy),
+		storage.WithDecoder(decoder),
+	}
+
+	if c.coldDBPath != "" {
+		dbOpts = append(dbOpts, storage.WithColdTier(c.coldDBPath, c.coldDBCompressionLevel))
+	}
+
+	if c.pruneArchive != "" {
+		archive, prefix, err := newPruneArchive(c.pruneArchive, c.pruneArchiveEndpoint, c.pruneArchiveRegion)
+		if err != nil {
+			return fmt.Errorf("unable to create prune archive, %w", err)
+		}
+
+		dbOpts = append(
+			dbOpts,
+			storage.WithPruneArchive(archive, prefix),
+			storage.WithRestoreLimit(c.pruneRestoreLimit),
+			storage.WithRestoredSegmentTTL(c.pruneRestoredTTL),
+			storage.WithRestoreTimeout(c.pruneRestoreTimeout),
+		)
+	}
+
+	// Load the replication TLS configs of the primary and the standby, if enabled
+	var serverTLS, standbyTLS *tls.Config
+
+	if c.replicationListenAddress != "" {
+		if serverTLS, err = replication.ServerTLSConfig(
+			c.replicationTLSCert,
+			c.replicationTLSKey,
+			c.replicationTLSCA,
+		); err != nil {
+			return fmt.Errorf("unable to load replication server TLS config, %w", err)
+		}
+	}
+
+	if c.replicateFrom != "" || c.leaderLock != "" {
+		if standbyTLS, err = replication.StandbyTLSConfig(
+			c.replicationTLSCA,
+			c.replicationTLSCert,
+			c.replicationTLSKey,
+		); err != nil {
+			return fmt.Errorf("unable to load replication standby TLS config, %w", err)
+		}
+	}
+
+	if c.readOnly {
+		logger.Info("read-only mode set, the chain is not fetched")
+
+		dbOpts = append(dbOpts, storage.WithReadOnly())
+	}
+
+	// Listen on the JSON-RPC address, unless the listener is passed to the process
+	ln, err := listen(c.listenAddress, logger)
+	if err != nil {
+		return fmt.Errorf("unable to listen, %w", err)
+	}
+
+	// Open the DB, which is held by the process handing off its listener
+	// until it shuts down, once signaled the readiness
+	var (
+		db        *storage.Pebble
+		handedOff = isHandoff()
+	)
+
+	openDB := func() error {
+		var openErr error
+
+		db, openErr = storage.NewPebble(c.dbPath, dbOpts...)
+
+		return openErr
+	}
+
+	if handedOff {
+		if err = signalHandoffReady(); err == nil {
+			err = retryUntil(c.shutdownTimeout+handoffTimeout, openDB)
+		}
+	} else {
+		err = openDB()
+	}
+
 	if err != nil {
+		_ = ln.Close()
+
 		return fmt.Errorf("unable to open storage DB, %w", err)
 	}
 
@@ -138,96 +1014,510 @@ func (c *startCfg) exec(ctx context.Context) error {
 		}
 	}()
 
-	// Create an Event Manager instance
-	em := events.NewManager()
+	if c.bootstrapFrom != "" {
+		// The bootstrap is interrupted by the shutdown signals
+		bootstrapCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 
-	// Create a TM2 client
-	tm2Client, err := client.NewClient(c.remote)
-	if err != nil {
-		return fmt.Errorf("unable to create client, %w", err)
+		// The restored snapshot is verified against the remote chain
+		remote, err := client.NewClient(
+			c.remote,
+			append(
+				[]client.Option{
+					client.WithRequestTimeout(c.remoteTimeout),
+					client.WithDialTimeout(c.remoteDialTimeout),
+					client.WithLogger(logger.Named("bootstrap").Named("client")),
+				},
+				authOpts...,
+			)...,
+		)
+		if err == nil {
+			err = bootstrap(
+				bootstrapCtx,
+				db,
+				remote,
+				c.bootstrapFrom,
+				c.bootstrapChecksum,
+				logger.Named("bootstrap"),
+			)
+
+			if closeErr := remote.Close(); closeErr != nil {
+				logger.Error("unable to gracefully close bootstrap client", zap.Error(closeErr))
+			}
+		}
+
+		stop()
+
+		if err != nil {
+			_ = ln.Close()
+
+			return fmt.Errorf("unable to bootstrap storage, %w", err)
+		}
 	}
 
-	// Create the fetcher service
-	f := fetch.New(
-		db,
-		tm2Client,
-		em,
-		fetch.WithLogger(
-			logger.Named("fetcher"),
-		),
-		fetch.WithMaxSlots(c.maxSlots),
-		fetch.WithMaxChunkSize(c.maxChunkSize),
-	)
+	var (
+		// The API key manager and admin audit log, shared by the chains, if the admin methods are enabled
+		keys     *apikeys.Manager
+		keyAuth  *serve.APIKeyAuth
+		auditLog *audit.Log
 
-	// Create the JSON-RPC service
-	j := setupJSONRPC(
-		db,
-		em,
-		logger,
+		serveMiddlewares = make([]serve.Middleware, 0)
 	)
 
+	if c.wsRateLimit != 0 {
+		// The WS requests are limited per method call, as they share the handshake request
+		logger.Info("WS rate-limit set", zap.Int("ws-rate-limit", c.wsRateLimit))
+
+		serveMiddlewares = append(serveMiddlewares, serve.WSRateLimitMiddleware(c.wsRateLimit, c.trustedProxies != ""))
+	}
+
+	if c.adminToken != "" {
+		// Move the API keys saved by the earlier versions out of the chain data
+		if err := db.MovePluginToSystem(apikeys.Component, apikeys.Component); err != nil {
+			_ = ln.Close()
+
+			return fmt.Errorf("unable to migrate API keys, %w", err)
+		}
+
+		if keys, err = apikeys.New(
+			db.System(apikeys.Component),
+			apikeys.WithLogger(logger.Named("api-keys")),
+		); err != nil {
+			_ = ln.Close()
+
+			return fmt.Errorf("unable to create API key manager, %w", err)
+		}
+
+		// Move the audit log saved by the earlier versions out of the chain data
+		if err := db.MovePluginToSystem(audit.Component, audit.Component); err != nil {
+			_ = ln.Close()
+
+			return fmt.Errorf("unable to migrate audit log, %w", err)
+		}
+
+		if auditLog, err = audit.New(db.System(audit.Component)); err != nil {
+			_ = ln.Close()
+
+			return fmt.Errorf("unable to open audit log, %w", err)
+		}
+
+		// The admin calls are recorded to the audit log before being authorized,
+		// so the rejected attempts are recorded as well (up to a limit per client IP)
+		serveMiddlewares = append(
+			serveMiddlewares,
+			serve.AuditMiddleware(auditLog, c.trustedProxies != "", logger.Named("audit")),
+			serve.AdminMiddleware(c.adminToken),
+		)
+	}
+
+	if c.apiKeys {
+		keyAuth = serve.NewAPIKeyAuth(keys, c.adminToken, logger.Named("api-keys"))
+
+		// The WS requests are accounted for per method call
+		serveMiddlewares = append(serveMiddlewares, keyAuth.MethodMiddleware())
+	}
+
 	mux := chi.NewMux()
 
+	if c.ipAllowlist != "" || c.ipDenylist != "" || c.trustedProxies != "" {
+		ipFilter, err := serve.NewIPFilter(
+			splitList(c.ipAllowlist),
+			splitList(c.ipDenylist),
+			splitList(c.trustedProxies),
+		)
+		if err != nil {
+			_ = ln.Close()
+
+			return fmt.Errorf("unable to create IP filter, %w", err)
+		}
+
+		// The IP filter resolves the client IPs of the following middlewares
+		mux.Use(ipFilter.Middleware(logger.Named("ip-filter")))
+	}
+
 	if c.rateLimit != 0 {
 		logger.Info("rate-limit set", zap.Int("rate-limit", c.rateLimit))
-		mux.Use(httprate.Limit(
-			c.rateLimit,
-			1*time.Minute,
-			httprate.WithKeyFuncs(httprate.KeyByRealIP),
-			httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
-				//nolint:errcheck // no need to handle error here, it had been checked before
-				ip, _ := httprate.KeyByRealIP(r)
-				logger.Debug("too many requests", zap.String("from", ip))
+		mux.Use(serve.RateLimitMiddleware(c.rateLimit, c.trustedProxies != "", logger))
+	}
+
+	// Flush the rows buffered by the ClickHouse sinks on shutdown
+	clickhouseSinks := make([]*clickhouse.Sink, 0)
+
+	defer func() {
+		flushCtx, cancelFn := context.WithTimeout(context.Background(), clickhouse.DefaultTimeout)
+		defer cancelFn()
+
+		for _, sink := range clickhouseSinks {
+			if flushErr := sink.Flush(flushCtx); flushErr != nil {
+				logger.Error("unable to flush ClickHouse sink", zap.Error(flushErr))
+			}
+		}
+	}()
 
-				// send a json response to give more info when using the graphQL explorer
-				http.Error(w, `{"error": "too many requests"}`, http.StatusTooManyRequests)
-			}),
-		))
+	// Create the remote and served request metrics, exposed if enabled
+	registry := prometheus.NewRegistry()
+	clientMetrics := client.NewMetrics(registry)
+	serveMetrics := serve.NewMetrics(registry)
+
+	if c.metrics {
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 	}
 
-	mux = j.SetupRoutes(mux)
-	mux = graph.Setup(db, em, mux)
+	// Create the WS connection limiter, shared by the chains
+	wsLimiter := serve.NewWSConnLimiter(c.wsMaxConns, c.wsMaxConnsPerIP, c.trustedProxies != "")
 
-	// Create the HTTP server
-	hs := serve.NewHTTPServer(mux, c.listenAddress, logger.Named("http-server"))
+	// Create the subscription limiter, shared by the chains
+	subscriptionLimiter := filters.NewSubscriptionLimiter(c.maxSubscriptions, c.maxSubscriptionsPerConn)
 
 	// Create a new waiter
 	w := newWaiter(ctx)
 
-	// Add the fetcher service
-	w.add(f.FetchChainData)
+	if keys != nil {
+		// Add the API key usage saving service
+		w.add(keys.Run)
+	}
+
+	router := newChainRouter(chains[0].name)
+
+	var (
+		// The chain namespaces streamed to the standby instances, if enabled
+		replicated = make([]replication.Storage, 0, len(chains))
+
+		// The chain namespaces whose older heights are moved to the cold tier (and pruned), if enabled
+		tiered = make([]*storage.Pebble, 0, len(chains))
+
+		// The chain WS connection closers, called on shutdown
+		wsClosers = make([]func(), 0, len(chains))
+
+		// The chain fetchers, monitored by the systemd watchdog
+		chainFetchers = make([]*fetch.Fetcher, 0, len(chains))
+
+		// The chain fetcher and standby services, one of which is run by the instance
+		fetchers = make([]waitFunc, 0, len(chains))
+		standbys = make([]func(primary string) waitFunc, 0, len(chains))
+	)
+
+	for _, chain := range chains {
+		var (
+			chainDB      = db
+			chainLogger  = logger
+			chainDecoder = decoder
+		)
+
+		if multiChain {
+			// Each chain is isolated in its own key namespace, and decoded with its own rules (if set)
+			if chainDB, err = db.WithNamespace(chain.name); err != nil {
+				return fmt.Errorf("unable to open storage namespace %s, %w", chain.name, err)
+			}
+
+			if chain.decodingRules != "" {
+				if chainDecoder, err = loadDecoder(chain.decodingRules); err != nil {
+					return fmt.Errorf("unable to load chain %s decoder, %w", chain.name, err)
+				}
+
+				chainDB = chainDB.WithDecoder(chainDecoder)
+			}
+
+			chainLogger = logger.Named(chain.name)
+		}
+
+		// Create a TM2 client
+		clientOpts := append(
+			[]client.Option{
+				client.WithRequestTimeout(c.remoteTimeout),
+				client.WithDialTimeout(c.remoteDialTimeout),
+				client.WithKeepAlive(c.remoteKeepAlive),
+				client.WithMaxIdleConns(c.remoteMaxIdleConns),
+				client.WithLogger(chainLogger.Named("client")),
+				client.WithMetrics(clientMetrics, chain.name),
+				client.WithSlowCallThreshold(c.remoteSlowCallThreshold),
+			},
+			authOpts...,
+		)
+
+		tm2Client, err := client.NewClient(chain.remote, clientOpts...)
+		if err != nil {
+			return fmt.Errorf("unable to create client, %w", err)
+		}
+
+		defer func() {
+			if closeErr := tm2Client.Close(); closeErr != nil {
+				logger.Error("unable to gracefully close client", zap.Error(closeErr))
+			}
+		}()
+
+		// Create the plugin instances, for each chain
+		chainPlugins := make([]plugins.Indexer, 0, len(pluginNames))
+
+		for _, name := range pluginNames {
+			chainPlugins = append(chainPlugins, builtinPlugins[name].newFn(tm2Client, chainDecoder))
+		}
+
+		indexerOpts := []indexer.Option{
+			indexer.WithLogger(chainLogger),
+			indexer.WithDecoder(chainDecoder),
+			indexer.WithFetcherOptions(
+				fetch.WithMaxSlots(c.maxSlots),
+				fetch.WithMaxChunkSize(c.maxChunkSize),
+				fetch.WithFlushSize(c.flushSize),
+				fetch.WithFlushInterval(c.flushInterval),
+				fetch.WithPersistQueueSize(c.persistQueueSize),
+				fetch.WithNewestFirst(c.newestFirst),
+				fetch.WithStartHeight(chain.startHeight),
+				fetch.WithEndHeight(chain.endHeight),
+				fetch.WithPlugins(chainPlugins...),
+				fetch.WithResetHook(newResetHook(c.chainReset, chainDB, chainLogger.Named("reset"))),
+			),
+			indexer.WithServeOptions(
+				serve.WithWSPingPeriod(c.wsPingPeriod),
+				serve.WithWSIdleTimeout(c.wsIdleTimeout),
+				serve.WithWSConnLimiter(wsLimiter),
+				serve.WithSubscriptionLimiter(subscriptionLimiter),
+				serve.WithMiddleware(serveMetrics.Middleware(chain.name)),
+				serve.WithMiddleware(serveMiddlewares...),
+			),
+			indexer.WithGraphOptions(
+				graph.WithWSConnLimiter(wsLimiter),
+				graph.WithSubscriptionLimiter(subscriptionLimiter),
+				graph.WithAPIKeyAuth(keyAuth),
+			),
+		}
+
+		if c.webhooks {
+			indexerOpts = append(indexerOpts, indexer.WithWebhooks())
+		}
+
+		if len(alertRules) != 0 {
+			indexerOpts = append(indexerOpts, indexer.WithAlertRules(alertRules))
+		}
+
+		if c.elasticsearchURL != "" {
+			prefix := c.elasticsearchPrefix
+			if multiChain {
+				prefix = fmt.Sprintf("%s-%s", prefix, chain.name)
+			}
+
+			sink := elasticsearch.New(
+				c.elasticsearchURL,
+				elasticsearch.WithIndexPrefix(prefix),
+				elasticsearch.WithDecoder(chainDecoder),
+			)
+			if err := sink.Init(ctx); err != nil {
+				return fmt.Errorf("unable to initialize Elasticsearch sink, %w", err)
+			}
+
+			indexerOpts = append(
+				indexerOpts,
+				indexer.WithFetcherOptions(fetch.WithPostSaveHook(sink.Save)),
+			)
+		}
+
+		if c.clickhouseURL != "" {
+			sinkOpts := []clickhouse.Option{
+				clickhouse.WithDatabase(c.clickhouseDatabase),
+				clickhouse.WithDecoder(chainDecoder),
+			}
+
+			if multiChain {
+				sinkOpts = append(sinkOpts, clickhouse.WithTablePrefix(fmt.Sprintf("%s_", chain.name)))
+			}
+
+			sink := clickhouse.New(c.clickhouseURL, sinkOpts...)
+			if err := sink.Init(ctx); err != nil {
+				return fmt.Errorf("unable to initialize ClickHouse sink, %w", err)
+			}
+
+			clickhouseSinks = append(clickhouseSinks, sink)
+
+			indexerOpts = append(
+				indexerOpts,
+				indexer.WithFetcherOptions(fetch.WithPostSaveHook(sink.Save)),
+			)
+		}
+
+		// Create the indexer instance
+		idx := indexer.New(chainDB, tm2Client, indexerOpts...)
+
+		// Register the plugin endpoints
+		for _, name := range pluginNames {
+			builtinPlugins[name].registerFn(idx.JSONRPC(), chainDB)
+		}
+
+		if keys != nil {
+			idx.JSONRPC().RegisterAPIKeyEndpoints(keys)
+			idx.JSONRPC().RegisterAuditEndpoints(auditLog)
+		}
+
+		handler := idx.Handler()
+
+		router.addChain(chain.name, handler)
+
+		if !multiChain && c.dbNamespace != "" {
+			// The only chain can also be selected by its namespace
+			router.addChain(c.dbNamespace, handler)
+		}
+
+		wsClosers = append(wsClosers, idx.JSONRPC().CloseWSConnections)
+
+		replicated = append(replicated, chainDB)
+		tiered = append(tiered, chainDB)
+		fetchers = append(fetchers, idx.Fetch)
+
+		if len(exportJobs) != 0 {
+			exportOpts := []export.Option{
+				export.WithLogger(chainLogger.Named("export")),
+				export.WithDecoder(chainDecoder),
+			}
+
+			if multiChain {
+				exportOpts = append(exportOpts, export.WithNamespace(chain.name))
+			}
+
+			// The export jobs run alongside the fetcher, so a single instance exports the data
+			fetchers = append(fetchers, export.New(chainDB, exportJobs, exportOpts...).Run)
+		}
+		chainFetchers = append(chainFetchers, idx.Fetcher())
+
+		standbys = append(standbys, func(primary string) waitFunc {
+			return replication.NewStandby(
+				primary,
+				chainDB,
+				replication.WithStandbyTLS(standbyTLS),
+				replication.WithStandbyToken(c.replicationToken),
+				replication.WithStandbyLogger(chainLogger.Named("standby")),
+			).Run
+		})
+	}
+
+	switch {
+	case c.readOnly:
+		// Only serve the existing data
+	case c.replicateFrom != "":
+		// Add the standby services, in place of the fetchers
+		for _, standby := range standbys {
+			w.add(standby(c.replicateFrom))
+		}
+	case c.leaderLock != "":
+		advertiseAddress := c.replicationAdvertiseAddress
+		if advertiseAddress == "" {
+			advertiseAddress = c.replicationListenAddress
+		}
+
+		election := leader.New(
+			c.leaderLock,
+			advertiseAddress,
+			leader.WithLogger(logger.Named("leader")),
+		)
+
+		// Add the fetcher services while leading, and the standby services while following
+		w.add(func(ctx context.Context) error {
+			return election.Run(
+				ctx,
+				leader.LeadFn(runAll(fetchers...)),
+				func(ctx context.Context, primary string) error {
+					services := make([]waitFunc, 0, len(standbys))
+
+					for _, standby := range standbys {
+						services = append(services, standby(primary))
+					}
+
+					return runAll(services...)(ctx)
+				},
+			)
+		})
+	default:
+		// Add the fetcher services
+		w.add(fetchers...)
+	}
+
+	if c.coldDBPath != "" && !c.readOnly {
+		// Add the cold tier service
+		w.add(moveToColdTier(tiered, c.hotHeights, logger.Named("cold-tier")))
+	}
+
+	if c.pruneHeights != 0 && !c.readOnly {
+		// Add the pruning service
+		w.add(pruneHeights(tiered, c.pruneHeights, logger.Named("prune")))
+	}
+
+	if c.replicationListenAddress != "" {
+		rs := replication.NewServer(
+			c.replicationListenAddress,
+			replicated,
+			replication.WithTLS(serverTLS),
+			replication.WithToken(c.replicationToken),
+			replication.WithLogger(logger.Named("replication")),
+		)
+
+		// Add the replication service
+		w.add(rs.Serve)
+	}
+
+	// The API requests (without the metrics) require the API keys, if enabled
+	var api http.Handler = router
+	if keyAuth != nil {
+		api = keyAuth.Middleware()(api)
+	}
+
+	mux.Handle("/*", api)
+
+	// Notify systemd of the readiness, and of the new main process, once taken over
+	readyState := systemd.StateReady
+	if handedOff {
+		readyState += "\n" + systemd.StateMainPID(os.Getpid())
+	}
+
+	// Create the HTTP server
+	hs := serve.NewHTTPServer(
+		mux,
+		c.listenAddress,
+		logger.Named("http-server"),
+		serve.WithShutdownTimeout(c.shutdownTimeout),
+		serve.WithListener(ln),
+		serve.WithStartHooks(notifyState(readyState, logger)),
+		serve.WithShutdownHooks(wsClosers...),
+	)
 
 	// Add the JSON-RPC service
 	w.add(hs.Serve)
 
+	// Add the listener handoff service, for the zero-downtime restarts
+	w.add(handoffOnSignal(ln, logger.Named("handoff")))
+
+	// Add the systemd services, notifying the shutdown
+	// and pinging the watchdog, if enabled
+	w.add(notifyStopping(logger))
+
+	watchdog, err := newWatchdog(chainFetchers, logger.Named("watchdog"))
+	if err != nil {
+		return err
+	}
+
+	if watchdog != nil {
+		w.add(watchdog)
+	}
+
 	// Wait for the services to stop
+	waitErr := w.wait()
+	if errors.Is(waitErr, errHandedOff) {
+		// The new process took over
+		waitErr = nil
+	}
+
 	return errors.Join(
-		w.wait(),
+		waitErr,
 		logger.Sync(),
 	)
 }
 
-// setupJSONRPC sets up the JSONRPC instance
-func setupJSONRPC(
-	db *storage.Pebble,
-	em *events.Manager,
-	logger *zap.Logger,
-) *serve.JSONRPC {
-	j := serve.NewJSONRPC(
-		em,
-		serve.WithLogger(
-			logger.Named("json-rpc"),
-		),
-	)
-
-	// Transaction handlers
-	j.RegisterTxEndpoints(db)
+// splitList splits the comma separated list, skipping the empty entries
+func splitList(list string) []string {
+	entries := make([]string, 0)
 
-	// Block handlers
-	j.RegisterBlockEndpoints(db)
-
-	// Sub handlers
-	j.RegisterSubEndpoints(db)
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
 
-	return j
+	return entries
 }
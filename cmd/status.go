@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/serve/handlers/status"
+)
+
+type statusCfg struct {
+	rpc        string
+	jsonOutput bool
+}
+
+// newStatusCmd creates the indexer status command
+func newStatusCmd() *ffcli.Command {
+	cfg := &statusCfg{}
+
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "status",
+		ShortUsage: "status [flags]",
+		ShortHelp:  "Shows the status of a running indexer",
+		LongHelp:   "Queries a running indexer instance and prints its sync and runtime status",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer status command flags
+func (c *statusCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rpc,
+		"rpc",
+		defaultIndexerRPC,
+		"the JSON-RPC URL of the running indexer",
+	)
+
+	fs.BoolVar(
+		&c.jsonOutput,
+		"json",
+		false,
+		"flag indicating if the status should be printed as JSON",
+	)
+}
+
+// exec executes the indexer status command
+func (c *statusCfg) exec(ctx context.Context, out io.Writer) error {
+	var s status.Status
+
+	if err := callRPC(ctx, c.rpc, "getStatus", nil, &s); err != nil {
+		return fmt.Errorf("unable to fetch indexer status, %w", err)
+	}
+
+	if c.jsonOutput {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(s)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "Latest height\t%d\n", s.LatestHeight)
+	_, _ = fmt.Fprintf(w, "Remote height\t%d\n", s.RemoteHeight)
+	_, _ = fmt.Fprintf(w, "Lag\t%d blocks\n", s.Lag)
+	_, _ = fmt.Fprintf(w, "Storage size\t%s\n", formatBytes(s.StorageSize))
+	_, _ = fmt.Fprintf(w, "Subscriptions\t%d\n", s.Subscriptions)
+	_, _ = fmt.Fprintf(w, "Started at\t%s\n", s.StartedAt)
+	_, _ = fmt.Fprintf(w, "Uptime\t%s\n", time.Duration(s.Uptime)*time.Second)
+
+	return w.Flush()
+}
+
+// formatBytes formats the byte size into a human-readable string
+func formatBytes(size uint64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/systemd"
+)
+
+// notifyState returns the hook notifying systemd of the service state,
+// if the indexer is run by systemd
+func notifyState(state string, logger *zap.Logger) func() {
+	return func() {
+		if err := systemd.Notify(state); err != nil {
+			logger.Error("unable to notify systemd", zap.String("state", state), zap.Error(err))
+		}
+	}
+}
+
+// notifyStopping notifies systemd of the service shutdown, once the context is cancelled
+func notifyStopping(logger *zap.Logger) waitFunc {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+
+		notifyState(systemd.StateStopping, logger)()
+
+		return nil
+	}
+}
+
+// newWatchdog creates the systemd watchdog service, pinged while the running
+// fetchers are alive (they ran within the watchdog timeout), if the watchdog is enabled
+func newWatchdog(fetchers []*fetch.Fetcher, logger *zap.Logger) (waitFunc, error) {
+	timeout, err := systemd.WatchdogTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read watchdog timeout, %w", err)
+	}
+
+	if timeout == 0 {
+		return nil, nil
+	}
+
+	logger.Info("systemd watchdog enabled", zap.Duration("timeout", timeout))
+
+	aliveFn := func() bool {
+		for _, f := range fetchers {
+			lastActive := f.LastActive()
+
+			// Fetchers not running (ex. while following the leader) are ignored
+			if !lastActive.IsZero() && time.Since(lastActive) > timeout {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return systemd.NewWatchdog(timeout, aliveFn, systemd.WithLogger(logger)).Run, nil
+}
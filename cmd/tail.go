@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gorilla/websocket"
+	"github.com/peterbourgon/ff/v3/ffcli"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/serve/filters/subscription"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const defaultIndexerWS = "ws://127.0.0.1:8546/ws"
+
+type tailCfg struct {
+	rpc     string
+	address string
+	msgType string
+}
+
+// newTailCmd creates the indexer tail command
+func newTailCmd() *ffcli.Command {
+	cfg := &tailCfg{}
+
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	cfg.registerFlags(fs)
+
+	return &ffcli.Command{
+		Name:       "tail",
+		ShortUsage: "tail [flags]",
+		ShortHelp:  "Streams new transactions from a running indexer",
+		LongHelp:   "Subscribes to new transactions on a running indexer, and prints the matching ones as they arrive",
+		FlagSet:    fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return cfg.exec(ctx, os.Stdout)
+		},
+	}
+}
+
+// registerFlags registers the indexer tail command flags
+func (c *tailCfg) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rpc,
+		"rpc",
+		defaultIndexerWS,
+		"the WS URL of the running indexer",
+	)
+
+	fs.StringVar(
+		&c.address,
+		"address",
+		"",
+		"the address that needs to participate in the transaction, if any",
+	)
+
+	fs.StringVar(
+		&c.msgType,
+		"type",
+		"",
+		"the message type (ex. send, exec, add_package, run) the transaction needs to contain, if any",
+	)
+}
+
+// exec executes the indexer tail command
+func (c *tailCfg) exec(ctx context.Context, out io.Writer) error {
+	var address *crypto.Address
+
+	if c.address != "" {
+		parsed, err := crypto.AddressFromBech32(c.address)
+		if err != nil {
+			return fmt.Errorf("invalid address, %w", err)
+		}
+
+		address = &parsed
+	}
+
+	w := newWaiter(ctx)
+
+	w.add(func(ctx context.Context) error {
+		return c.tail(ctx, out, address)
+	})
+
+	return w.wait()
+}
+
+// tail subscribes to new transactions and prints out the matching ones,
+// until the context is cancelled
+func (c *tailCfg) tail(ctx context.Context, out io.Writer, address *crypto.Address) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.rpc, nil)
+	if err != nil {
+		return fmt.Errorf("unable to connect to indexer, %w", err)
+	}
+
+	// Close the connection when the context is done,
+	// which unblocks any pending reads
+	go func() {
+		<-ctx.Done()
+
+		_ = conn.Close()
+	}()
+
+	request := spec.NewJSONRequest(
+		1,
+		"subscribe",
+		[]any{subscription.NewTransactionsEvent},
+	)
+
+	if err := conn.WriteJSON(request); err != nil {
+		return fmt.Errorf("unable to send subscribe request, %w", err)
+	}
+
+	// Read the subscription response
+	var response rpcResponse
+	if err := conn.ReadJSON(&response); err != nil {
+		return fmt.Errorf("unable to read subscribe response, %w", err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("unable to subscribe, %s", response.Error.Message)
+	}
+
+	for {
+		var notification spec.BaseJSONSubscribeResponse
+		if err := conn.ReadJSON(&notification); err != nil {
+			if ctx.Err() != nil {
+				// The tail has been stopped
+				return nil
+			}
+
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) {
+				return fmt.Errorf("connection closed by indexer, %w", err)
+			}
+
+			return fmt.Errorf("unable to read notification, %w", err)
+		}
+
+		if notification.Params == nil {
+			continue
+		}
+
+		encoded, ok := notification.Params.Result.(string)
+		if !ok {
+			continue
+		}
+
+		txResult, tx, err := decodeTxNotification(encoded)
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "unable to decode transaction, %s\n", err)
+
+			continue
+		}
+
+		if !c.matches(tx, address) {
+			continue
+		}
+
+		printTx(out, txResult, tx)
+	}
+}
+
+// matches returns a flag indicating if the transaction
+// matches the tail filters
+func (c *tailCfg) matches(tx *std.Tx, address *crypto.Address) bool {
+	if address != nil {
+		found := false
+
+		for _, participant := range decode.Addresses(tx) {
+			if participant == *address {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if c.msgType != "" {
+		for _, msg := range tx.GetMsgs() {
+			if msg.Type() == c.msgType {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+// decodeTxNotification decodes the base64, amino encoded transaction result
+func decodeTxNotification(encoded string) (*types.TxResult, *std.Tx, error) {
+	var txResult types.TxResult
+	if err := decodeValue(encoded, &txResult); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := decode.Tx(txResult.Tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &txResult, tx, nil
+}
+
+// printTx pretty-prints the transaction
+func printTx(out io.Writer, txResult *types.TxResult, tx *std.Tx) {
+	txStatus := "OK"
+	if txResult.Response.IsErr() {
+		txStatus = "FAILED"
+	}
+
+	_, _ = fmt.Fprintf(
+		out,
+		"[%d/%d] %s %s gas=%d/%d\n",
+		txResult.Height,
+		txResult.Index,
+		base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+		txStatus,
+		txResult.Response.GasUsed,
+		txResult.Response.GasWanted,
+	)
+
+	for i, msg := range tx.GetMsgs() {
+		_, _ = fmt.Fprintf(out, "  #%d %s\n", i, formatMsg(msg))
+	}
+
+	if tx.GetMemo() != "" {
+		_, _ = fmt.Fprintf(out, "  memo: %s\n", tx.GetMemo())
+	}
+
+	if txResult.Response.IsErr() && txResult.Response.Log != "" {
+		_, _ = fmt.Fprintf(out, "  log: %s\n", firstLine(txResult.Response.Log))
+	}
+}
+
+// formatMsg formats the message into a short, single-line summary
+func formatMsg(msg std.Msg) string {
+	switch m := msg.(type) {
+	case bank.MsgSend:
+		return fmt.Sprintf("%s %s -> %s %s", m.Type(), m.FromAddress, m.ToAddress, m.Amount)
+	case vm.MsgCall:
+		return fmt.Sprintf("%s %s %s.%s(%s)", m.Type(), m.Caller, m.PkgPath, m.Func, strings.Join(m.Args, ", "))
+	case vm.MsgAddPackage:
+		path := ""
+		if m.Package != nil {
+			path = m.Package.Path
+		}
+
+		return fmt.Sprintf("%s %s %s", m.Type(), m.Creator, path)
+	case vm.MsgRun:
+		return fmt.Sprintf("%s %s", m.Type(), m.Caller)
+	default:
+		return fmt.Sprintf("%s/%s", msg.Route(), msg.Type())
+	}
+}
+
+// firstLine returns the first line of the given text
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+
+	return line
+}
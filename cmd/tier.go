@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// coldTierInterval is the interval between
+// the moves of the older heights to the cold tier
+const coldTierInterval = time.Minute
+
+// moveToColdTier periodically moves the heights older than
+// the hot heights of each storage namespace to the cold tier
+func moveToColdTier(stores []*storage.Pebble, hotHeights uint64, logger *zap.Logger) waitFunc {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(coldTierInterval)
+		defer ticker.Stop()
+
+		for {
+			for _, store := range stores {
+				moved, err := store.MoveToColdTier(hotHeights)
+				if err != nil {
+					logger.Error(
+						"unable to move heights to the cold tier",
+						zap.String("namespace", store.Namespace()),
+						zap.Error(err),
+					)
+
+					continue
+				}
+
+				if moved != 0 {
+					logger.Info(
+						"moved heights to the cold tier",
+						zap.String("namespace", store.Namespace()),
+						zap.Uint64("heights", moved),
+					)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
@@ -64,3 +64,21 @@ func (w *waiter) wait() error {
 
 	return g.Wait()
 }
+
+// runAll combines the services into a single one,
+// which runs until all the services finish
+func runAll(fns ...waitFunc) waitFunc {
+	return func(ctx context.Context) error {
+		g, gCtx := errgroup.WithContext(ctx)
+
+		for _, fn := range fns {
+			fn := fn
+
+			g.Go(func() error {
+				return fn(gCtx)
+			})
+		}
+
+		return g.Wait()
+	}
+}
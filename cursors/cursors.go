@@ -0,0 +1,54 @@
+// Package cursors encodes the page cursors of the paginated queries. A cursor is the
+// position the next page starts from (ex. the storage key suffix of its first item),
+// hex encoded, so the clients pass it back as an opaque string
+package cursors
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrInvalid is returned when the page cursor is malformed
+var ErrInvalid = errors.New("invalid cursor")
+
+// Encode encodes the position as the page cursor
+func Encode(position []byte) string {
+	return hex.EncodeToString(position)
+}
+
+// Decode decodes the position of the given size from the page cursor
+func Decode(cursor string, size int) ([]byte, error) {
+	position, err := hex.DecodeString(cursor)
+	if err != nil || len(position) != size {
+		return nil, ErrInvalid
+	}
+
+	return position, nil
+}
+
+// DecodeAtLeast decodes the position of at least the given size from the page cursor,
+// for the positions ending with a variable size part (ex. an address)
+func DecodeAtLeast(cursor string, size int) ([]byte, error) {
+	position, err := hex.DecodeString(cursor)
+	if err != nil || len(position) < size {
+		return nil, ErrInvalid
+	}
+
+	return position, nil
+}
+
+// EncodeUint64 encodes the numeric position as the page cursor
+func EncodeUint64(position uint64) string {
+	return Encode(binary.BigEndian.AppendUint64(nil, position))
+}
+
+// DecodeUint64 decodes the numeric position from the page cursor
+func DecodeUint64(cursor string) (uint64, error) {
+	position, err := Decode(cursor, 8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(position), nil
+}
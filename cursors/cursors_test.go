@@ -0,0 +1,41 @@
+package cursors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursors_Decode(t *testing.T) {
+	t.Parallel()
+
+	position := []byte{0, 1, 2, 3}
+
+	decoded, err := Decode(Encode(position), len(position))
+	require.NoError(t, err)
+
+	assert.Equal(t, position, decoded)
+
+	number, err := DecodeUint64(EncodeUint64(42))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(42), number)
+
+	// Make sure the malformed cursors are rejected
+	for _, cursor := range []string{"cursor", "0001", Encode(position) + "00"} {
+		_, err := Decode(cursor, len(position))
+		assert.ErrorIs(t, err, ErrInvalid)
+	}
+
+	// Make sure the variable size positions are decoded
+	decoded, err = DecodeAtLeast(Encode(position)+"00", len(position))
+	require.NoError(t, err)
+
+	assert.Equal(t, append(position, 0), decoded)
+
+	for _, cursor := range []string{"cursor", "0001"} {
+		_, err := DecodeAtLeast(cursor, len(position))
+		assert.ErrorIs(t, err, ErrInvalid)
+	}
+}
@@ -0,0 +1,127 @@
+// Package decode contains helpers for decoding
+// and inspecting indexed TM2 transactions
+package decode
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Tx decodes the raw amino transaction into a standard transaction
+func Tx(raw types.Tx) (*std.Tx, error) {
+	var tx std.Tx
+
+	if err := amino.Unmarshal(raw, &tx); err != nil {
+		return nil, fmt.Errorf("unable to decode transaction, %w", err)
+	}
+
+	return &tx, nil
+}
+
+// Decoder decodes the transactions of a chain, applying the decoding rules of its upgrades, if any.
+// The nil decoder applies no rules
+type Decoder struct {
+	rules []*Rule
+}
+
+// NewDecoder creates a new chain transaction decoder, applying the given decoding rules
+func NewDecoder(rules []*Rule) *Decoder {
+	return &Decoder{
+		rules: rules,
+	}
+}
+
+// LoadDecoder creates a new chain transaction decoder, applying the decoding rules
+// of the JSON configuration file (see LoadRules). The empty path applies no rules
+func LoadDecoder(path string) (*Decoder, error) {
+	if path == "" {
+		//nolint:nilnil // The nil decoder applies no rules
+		return nil, nil
+	}
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDecoder(rules), nil
+}
+
+// TxAt decodes the raw amino transaction of the height into a standard transaction,
+// applying the decoding rules of the height
+func (d *Decoder) TxAt(raw types.Tx, height int64) (*std.Tx, error) {
+	if d == nil {
+		return Tx(raw)
+	}
+
+	renames := renamesAt(d.rules, height)
+	if len(renames) == 0 {
+		return Tx(raw)
+	}
+
+	renamed, err := renameTypes(raw, renames)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply decoding rules, %w", err)
+	}
+
+	return Tx(renamed)
+}
+
+// TxResult decodes the transaction of the result into a standard transaction,
+// applying the decoding rules of its height
+func (d *Decoder) TxResult(result *types.TxResult) (*std.Tx, error) {
+	return d.TxAt(result.Tx, result.Height)
+}
+
+// Addresses returns the unique addresses participating in the transaction,
+// which are the message signers, followed by any message recipients
+func Addresses(tx *std.Tx) []crypto.Address {
+	var (
+		seen      = make(map[crypto.Address]struct{})
+		addresses = make([]crypto.Address, 0)
+	)
+
+	add := func(address crypto.Address) {
+		if address.IsZero() {
+			return
+		}
+
+		if _, ok := seen[address]; ok {
+			return
+		}
+
+		seen[address] = struct{}{}
+		addresses = append(addresses, address)
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		for _, signer := range msg.GetSigners() {
+			add(signer)
+		}
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		for _, recipient := range recipients(msg) {
+			add(recipient)
+		}
+	}
+
+	return addresses
+}
+
+// recipients returns the message recipients, if any
+func recipients(msg std.Msg) []crypto.Address {
+	switch m := msg.(type) {
+	case bank.MsgSend:
+		return []crypto.Address{m.ToAddress}
+	case *bank.MsgSend:
+		return []crypto.Address{m.ToAddress}
+	default:
+		return nil
+	}
+}
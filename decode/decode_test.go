@@ -0,0 +1,110 @@
+package decode_test
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/mock"
+)
+
+func TestDecode_Tx(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid transaction", func(t *testing.T) {
+		t.Parallel()
+
+		tx, err := decode.Tx([]byte("totally valid amino"))
+		assert.Nil(t, tx)
+		assert.Error(t, err)
+	})
+
+	t.Run("valid transaction", func(t *testing.T) {
+		t.Parallel()
+
+		stdTx := std.Tx{
+			Memo: "example memo",
+		}
+
+		tx, err := decode.Tx(amino.MustMarshal(stdTx))
+		require.NoError(t, err)
+
+		assert.Equal(t, stdTx.Memo, tx.Memo)
+	})
+}
+
+func TestDecode_Addresses(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sender    = crypto.Address{1}
+		recipient = crypto.Address{2}
+
+		tx = &std.Tx{
+			Msgs: []std.Msg{
+				bank.MsgSend{
+					FromAddress: sender,
+					ToAddress:   recipient,
+				},
+				bank.MsgSend{
+					FromAddress: recipient,
+					ToAddress:   sender,
+				},
+			},
+		}
+	)
+
+	assert.Equal(
+		t,
+		[]crypto.Address{sender, recipient},
+		decode.Addresses(tx),
+	)
+}
+
+func TestDecode_Events(t *testing.T) {
+	t.Parallel()
+
+	var (
+		realmEvent = mock.GnoEvent{
+			Type:    "Transfer",
+			PkgPath: "gno.land/r/demo/foo20",
+			Func:    "Transfer",
+			Attrs: []decode.EventAttribute{
+				{Key: "from", Value: "g1from"},
+				{Key: "to", Value: "g1to"},
+			},
+		}
+
+		response = abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: []abci.Event{
+					abci.EventString("not a realm event"),
+					realmEvent,
+				},
+			},
+		}
+	)
+
+	events := decode.Events(response)
+	require.Len(t, events, 1)
+
+	event := events[0]
+
+	assert.Equal(t, realmEvent.Type, event.Type)
+	assert.Equal(t, realmEvent.PkgPath, event.PkgPath)
+	assert.Equal(t, realmEvent.Func, event.Func)
+
+	from, ok := event.Attr("from")
+	require.True(t, ok)
+	assert.Equal(t, "g1from", from)
+
+	_, ok = event.Attr("value")
+	assert.False(t, ok)
+}
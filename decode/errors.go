@@ -0,0 +1,21 @@
+package decode
+
+import (
+	"reflect"
+)
+
+// ErrorCode returns the code of the transaction execution error,
+// which is its (Amino registered) type name, ex. OutOfGasError.
+// The code is empty if there is no error
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	errType := reflect.TypeOf(err)
+	for errType.Kind() == reflect.Pointer {
+		errType = errType.Elem()
+	}
+
+	return errType.Name()
+}
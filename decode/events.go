@@ -0,0 +1,54 @@
+package decode
+
+import (
+	"encoding/json"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+// Event is a realm event, emitted by the Gno VM
+type Event struct {
+	Type    string           `json:"type"`
+	PkgPath string           `json:"pkg_path"`
+	Func    string           `json:"func"`
+	Attrs   []EventAttribute `json:"attrs"`
+}
+
+// EventAttribute is a single realm event attribute
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Attr returns the value of the event attribute with the given key, if any
+func (e *Event) Attr(key string) (string, bool) {
+	for _, attr := range e.Attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Events returns the realm events emitted during the transaction execution,
+// in order. Events that are not realm events are skipped
+func Events(response abci.ResponseDeliverTx) []*Event {
+	events := make([]*Event, 0, len(response.Events))
+
+	for _, abciEvent := range response.Events {
+		data, err := json.Marshal(abciEvent)
+		if err != nil {
+			continue
+		}
+
+		var event *Event
+		if err := json.Unmarshal(data, &event); err != nil || event == nil || event.Type == "" {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
@@ -0,0 +1,189 @@
+package decode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	errInvalidHeights = errors.New("invalid height range")
+	errMissingRenames = errors.New("no type renames")
+	errInvalidRename  = errors.New("invalid type rename")
+)
+
+// The amino field numbers of the renamed message types
+const (
+	fieldTxMsgs     protowire.Number = 1 // std.Tx messages
+	fieldAnyTypeURL protowire.Number = 1 // interface value type URL
+)
+
+// Config is the decoding rules configuration
+type Config struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// Rule is a decoding rule of the transactions in the height range,
+// for the message types renamed (or registered) by a chain upgrade.
+// The messages of the renamed types are decoded as the types they are renamed to
+type Rule struct {
+	// TypeRenames are the message type URLs in the height range,
+	// mapped to the type URLs they are decoded as (ex. "/vm.m_addpkg": "/vm.m_addpackage")
+	TypeRenames map[string]string `json:"typeRenames"`
+
+	// Name is the name of the rule (ex. the chain upgrade)
+	Name string `json:"name"`
+
+	// FromHeight is the first height the rule applies to
+	FromHeight int64 `json:"fromHeight"`
+
+	// ToHeight is the last height the rule applies to,
+	// unbounded if 0
+	ToHeight int64 `json:"toHeight"`
+}
+
+// LoadRules loads the decoding rules from the JSON configuration file
+func LoadRules(path string) ([]*Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read decoding rules, %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse decoding rules, %w", err)
+	}
+
+	for _, rule := range config.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid decoding rule %q, %w", rule.Name, err)
+		}
+	}
+
+	return config.Rules, nil
+}
+
+// validate validates the rule
+func (r *Rule) validate() error {
+	if r.FromHeight < 0 || r.ToHeight < 0 || (r.ToHeight != 0 && r.ToHeight < r.FromHeight) {
+		return errInvalidHeights
+	}
+
+	if len(r.TypeRenames) == 0 {
+		return errMissingRenames
+	}
+
+	for from, to := range r.TypeRenames {
+		if from == "" || to == "" || from == to {
+			return fmt.Errorf("%w: %q to %q", errInvalidRename, from, to)
+		}
+	}
+
+	return nil
+}
+
+// applies checks if the rule applies to the height
+func (r *Rule) applies(height int64) bool {
+	return height >= r.FromHeight && (r.ToHeight == 0 || height <= r.ToHeight)
+}
+
+// renamesAt returns the message type renames of the rules, applying to the height, if any.
+// The earlier rules take precedence for the same type
+func renamesAt(set []*Rule, height int64) map[string]string {
+	var renames map[string]string
+
+	for _, rule := range set {
+		if !rule.applies(height) {
+			continue
+		}
+
+		if renames == nil {
+			renames = make(map[string]string, len(rule.TypeRenames))
+		}
+
+		for from, to := range rule.TypeRenames {
+			if _, ok := renames[from]; !ok {
+				renames[from] = to
+			}
+		}
+	}
+
+	return renames
+}
+
+// renameTypes rewrites the message type URLs of the raw amino transaction,
+// keeping all the other fields as they are
+func renameTypes(raw []byte, renames map[string]string) ([]byte, error) {
+	renamed := make([]byte, 0, len(raw))
+
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+
+		fieldLen := protowire.ConsumeFieldValue(num, typ, raw[n:])
+		if fieldLen < 0 {
+			return nil, protowire.ParseError(fieldLen)
+		}
+
+		field := raw[:n+fieldLen]
+		raw = raw[n+fieldLen:]
+
+		if num != fieldTxMsgs || typ != protowire.BytesType {
+			renamed = append(renamed, field...)
+
+			continue
+		}
+
+		msg, _ := protowire.ConsumeBytes(field[n:])
+
+		renamedMsg, err := renameAnyType(msg, renames)
+		if err != nil {
+			return nil, err
+		}
+
+		renamed = protowire.AppendTag(renamed, num, typ)
+		renamed = protowire.AppendBytes(renamed, renamedMsg)
+	}
+
+	return renamed, nil
+}
+
+// renameAnyType rewrites the type URL of the raw amino interface value, if renamed
+func renameAnyType(raw []byte, renames map[string]string) ([]byte, error) {
+	renamed := make([]byte, 0, len(raw))
+
+	for len(raw) > 0 {
+		num, typ, n := protowire.ConsumeTag(raw)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+
+		fieldLen := protowire.ConsumeFieldValue(num, typ, raw[n:])
+		if fieldLen < 0 {
+			return nil, protowire.ParseError(fieldLen)
+		}
+
+		field := raw[:n+fieldLen]
+		raw = raw[n+fieldLen:]
+
+		if num == fieldAnyTypeURL && typ == protowire.BytesType {
+			typeURL, _ := protowire.ConsumeString(field[n:])
+
+			if to, ok := renames[typeURL]; ok {
+				renamed = protowire.AppendTag(renamed, num, typ)
+				renamed = protowire.AppendString(renamed, to)
+
+				continue
+			}
+		}
+
+		renamed = append(renamed, field...)
+	}
+
+	return renamed, nil
+}
@@ -0,0 +1,177 @@
+package decode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// encodeAny encodes the amino interface value of the type
+func encodeAny(typeURL string, value []byte) []byte {
+	var b []byte
+
+	b = protowire.AppendTag(b, fieldAnyTypeURL, protowire.BytesType)
+	b = protowire.AppendString(b, typeURL)
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendBytes(b, value)
+
+	return b
+}
+
+// encodeTx encodes the amino transaction of the messages, with the memo
+func encodeTx(memo string, msgs ...[]byte) []byte {
+	var b []byte
+
+	for _, msg := range msgs {
+		b = protowire.AppendTag(b, fieldTxMsgs, protowire.BytesType)
+		b = protowire.AppendBytes(b, msg)
+	}
+
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendString(b, memo)
+
+	return b
+}
+
+func TestRules_Load(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		expectedErr error
+		name        string
+		config      string
+	}{
+		{
+			nil,
+			"valid rules",
+			`{"rules": [{"name": "v2", "toHeight": 100, "typeRenames": {"/vm.m_addpkg": "/vm.m_addpackage"}}]}`,
+		},
+		{
+			errInvalidHeights,
+			"inverted height range",
+			`{"rules": [{"name": "v2", "fromHeight": 100, "toHeight": 10, "typeRenames": {"/a": "/b"}}]}`,
+		},
+		{
+			errMissingRenames,
+			"no renames",
+			`{"rules": [{"name": "v2", "fromHeight": 100}]}`,
+		},
+		{
+			errInvalidRename,
+			"same type rename",
+			`{"rules": [{"name": "v2", "typeRenames": {"/a": "/a"}}]}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "rules.json")
+			require.NoError(t, os.WriteFile(path, []byte(testCase.config), 0o600))
+
+			loaded, err := LoadRules(path)
+			if testCase.expectedErr != nil {
+				assert.ErrorIs(t, err, testCase.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, loaded, 1)
+
+			assert.Equal(t, "/vm.m_addpackage", loaded[0].TypeRenames["/vm.m_addpkg"])
+		})
+	}
+}
+
+func TestRules_LoadDecoder(t *testing.T) {
+	t.Parallel()
+
+	// Make sure no rules are applied without the configuration file
+	decoder, err := LoadDecoder("")
+	require.NoError(t, err)
+
+	assert.Nil(t, decoder)
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(
+		path,
+		[]byte(`{"rules": [{"name": "v2", "typeRenames": {"/vm.m_addpkg": "/vm.m_addpackage"}}]}`),
+		0o600,
+	))
+
+	decoder, err = LoadDecoder(path)
+	require.NoError(t, err)
+	require.NotNil(t, decoder)
+
+	assert.Equal(t, map[string]string{"/vm.m_addpkg": "/vm.m_addpackage"}, renamesAt(decoder.rules, 10))
+}
+
+func TestRules_RenamesAt(t *testing.T) {
+	t.Parallel()
+
+	set := []*Rule{
+		{
+			Name:        "v2",
+			ToHeight:    100,
+			TypeRenames: map[string]string{"/vm.m_addpkg": "/vm.m_addpackage"},
+		},
+		{
+			Name:        "v3",
+			FromHeight:  50,
+			TypeRenames: map[string]string{"/vm.m_addpkg": "/vm.m_other", "/vm.m_run2": "/vm.m_run"},
+		},
+	}
+
+	// Make sure the height ranges are respected,
+	// with the earlier rules taking precedence
+	assert.Equal(t, map[string]string{"/vm.m_addpkg": "/vm.m_addpackage"}, renamesAt(set, 10))
+	assert.Equal(
+		t,
+		map[string]string{"/vm.m_addpkg": "/vm.m_addpackage", "/vm.m_run2": "/vm.m_run"},
+		renamesAt(set, 100),
+	)
+	assert.Equal(
+		t,
+		map[string]string{"/vm.m_addpkg": "/vm.m_other", "/vm.m_run2": "/vm.m_run"},
+		renamesAt(set, 101),
+	)
+	assert.Nil(t, renamesAt(set[:1], 101))
+}
+
+func TestRules_RenameTypes(t *testing.T) {
+	t.Parallel()
+
+	value := []byte{0x0a, 0x01, 0x02}
+
+	raw := encodeTx(
+		"example memo",
+		encodeAny("/vm.m_addpkg", value),
+		encodeAny("/bank.MsgSend", value),
+	)
+
+	renamed, err := renameTypes(raw, map[string]string{"/vm.m_addpkg": "/vm.m_addpackage"})
+	require.NoError(t, err)
+
+	// Make sure only the renamed message types are rewritten
+	assert.Equal(
+		t,
+		encodeTx(
+			"example memo",
+			encodeAny("/vm.m_addpackage", value),
+			encodeAny("/bank.MsgSend", value),
+		),
+		renamed,
+	)
+
+	// Make sure the invalid transactions are not rewritten
+	_, err = renameTypes([]byte("totally valid amino"), map[string]string{"/a": "/b"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+// Package export runs the scheduled export jobs, periodically exporting the heights
+// indexed since the previous run of each job to its target (files, S3 objects, sinks)
+package export
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// stateNamespace is the storage (plugin) namespace of the exported heights of the jobs,
+// so the job runs resume after the indexer restarts
+const stateNamespace = "exports"
+
+// scheduledJob is an export job, with the exporter writing to its target
+type scheduledJob struct {
+	*Job
+
+	exporter exporter
+	next     time.Time
+}
+
+// Scheduler runs the export jobs on their schedules
+type Scheduler struct {
+	db     storage.Storage
+	logger *zap.Logger
+	now    func() time.Time
+
+	namespace string
+	decoder   *decode.Decoder
+	jobs      []*scheduledJob
+}
+
+// New creates a new export job scheduler for the (validated) jobs,
+// exporting the heights of the storage
+func New(db storage.Storage, jobs []*Job, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		db:     db,
+		logger: zap.NewNop(),
+		now:    time.Now,
+		jobs:   make([]*scheduledJob, 0, len(jobs)),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for _, job := range jobs {
+		s.jobs = append(s.jobs, &scheduledJob{
+			Job:      job,
+			exporter: newExporter(job.Target, s.namespace, s.decoder),
+		})
+	}
+
+	return s
+}
+
+// Run runs the jobs on their schedules, until the context is cancelled.
+// Failed job runs are retried on the next schedule trigger
+func (s *Scheduler) Run(ctx context.Context) error {
+	if len(s.jobs) == 0 {
+		return nil
+	}
+
+	for _, job := range s.jobs {
+		job.next = job.schedule.Next(s.now())
+	}
+
+	for {
+		next := s.jobs[0].next
+
+		for _, job := range s.jobs[1:] {
+			if job.next.Before(next) {
+				next = job.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil
+		case <-timer.C:
+		}
+
+		now := s.now()
+
+		for _, job := range s.jobs {
+			if job.next.After(now) {
+				continue
+			}
+
+			if err := s.runJob(ctx, job); err != nil && ctx.Err() == nil {
+				s.logger.Error(
+					"unable to run export job",
+					zap.String("job", job.Name),
+					zap.Error(err),
+				)
+			}
+
+			job.next = job.schedule.Next(now)
+		}
+	}
+}
+
+// runJob exports the heights indexed since the previous job run,
+// in segments of at most the maximum job heights
+func (s *Scheduler) runJob(ctx context.Context, job *scheduledJob) error {
+	latest, err := s.db.GetLatestHeight()
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// Nothing is indexed yet
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	exported, err := s.getExported(job.Name)
+	if err != nil {
+		return fmt.Errorf("unable to fetch exported height, %w", err)
+	}
+
+	for from := exported + 1; from <= latest; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		to := min(from+job.MaxHeights-1, latest)
+
+		if err := job.exporter.export(ctx, job.Name, from, to, s.heights(from, to)); err != nil {
+			return fmt.Errorf("unable to export heights %d to %d, %w", from, to, err)
+		}
+
+		if err := s.setExported(job.Name, to); err != nil {
+			return fmt.Errorf("unable to save exported height, %w", err)
+		}
+
+		s.logger.Info(
+			"Exported heights",
+			zap.String("job", job.Name),
+			zap.Uint64("from", from),
+			zap.Uint64("to", to),
+		)
+
+		from = to + 1
+	}
+
+	return nil
+}
+
+// heights returns the reader of the saved heights of the range [from, to]
+func (s *Scheduler) heights(from, to uint64) heightsFn {
+	return func(fn heightFn) error {
+		it, err := s.db.BlockIterator(from, to+1)
+		if err != nil {
+			return fmt.Errorf("unable to iterate blocks, %w", err)
+		}
+
+		defer it.Close()
+
+		for it.Next() {
+			block, err := it.Value()
+			if err != nil {
+				return err
+			}
+
+			results, err := storage.BlockResults(s.db, block)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(block, results); err != nil {
+				return err
+			}
+		}
+
+		return it.Error()
+	}
+}
+
+// getExported fetches the last exported height of the job, if any
+func (s *Scheduler) getExported(job string) (uint64, error) {
+	raw, err := s.db.GetPluginValue(stateNamespace, []byte(job))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(raw), nil
+}
+
+// setExported saves the last exported height of the job
+func (s *Scheduler) setExported(job string, height uint64) error {
+	wb := s.db.WriteBatch()
+
+	if err := wb.SetPluginValue(stateNamespace, []byte(job), binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	if err := wb.Commit(); err != nil {
+		return errors.Join(err, wb.Rollback())
+	}
+
+	return wb.Rollback()
+}
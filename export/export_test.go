@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// saveHeights saves the heights of the range [from, to], each with a single transaction
+func saveHeights(t *testing.T, db *storage.Pebble, from, to int64) {
+	t.Helper()
+
+	wb := db.WriteBatch()
+
+	for height := from; height <= to; height++ {
+		require.NoError(t, wb.SetBlock(&types.Block{Header: types.Header{Height: height, NumTxs: 1}}))
+		require.NoError(t, wb.SetTx(&types.TxResult{Height: height, Tx: []byte{byte(height)}}))
+	}
+
+	require.NoError(t, wb.SetLatestHeight(uint64(to)))
+	require.NoError(t, wb.Commit())
+}
+
+// readSegment reads the record kinds of the exported segment
+func readSegment(t *testing.T, path string, compressed bool) []string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	var r io.Reader = f
+
+	if compressed {
+		gz, err := gzip.NewReader(f)
+		require.NoError(t, err)
+
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+
+	kinds := make([]string, 0)
+
+	for scanner.Scan() {
+		var line struct {
+			Record json.RawMessage `json:"record"`
+			Kind   string          `json:"kind"`
+		}
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		require.NotEmpty(t, line.Record)
+
+		kinds = append(kinds, line.Kind)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return kinds
+}
+
+func TestScheduler_RunJob(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	var (
+		dir = t.TempDir()
+		job = &Job{
+			Name:       "extract",
+			Schedule:   "@hourly",
+			MaxHeights: 2,
+			Target: &Target{
+				Type:     TargetFile,
+				Path:     dir,
+				Compress: true,
+			},
+		}
+	)
+
+	require.NoError(t, job.validate())
+
+	s := New(db, []*Job{job}, WithNamespace("chain"))
+	scheduled := s.jobs[0]
+
+	// Nothing is indexed yet
+	require.NoError(t, s.runJob(context.Background(), scheduled))
+
+	saveHeights(t, db, 1, 3)
+
+	require.NoError(t, s.runJob(context.Background(), scheduled))
+
+	exported, err := s.getExported(job.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), exported)
+
+	// The heights are exported in segments of the max heights
+	segments, err := filepath.Glob(filepath.Join(dir, "chain", "extract", "*"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "chain", "extract", "000000000001-000000000002.ndjson.gz"),
+		filepath.Join(dir, "chain", "extract", "000000000003-000000000003.ndjson.gz"),
+	}, segments)
+
+	assert.Equal(t, []string{"block", "tx", "block", "tx"}, readSegment(t, segments[0], true))
+	assert.Equal(t, []string{"block", "tx"}, readSegment(t, segments[1], true))
+
+	// Only the newly indexed heights are exported by the next run
+	saveHeights(t, db, 4, 4)
+
+	require.NoError(t, s.runJob(context.Background(), scheduled))
+
+	segments, err = filepath.Glob(filepath.Join(dir, "chain", "extract", "*"))
+	require.NoError(t, err)
+
+	require.Len(t, segments, 3)
+	assert.Equal(t, filepath.Join(dir, "chain", "extract", "000000000004-000000000004.ndjson.gz"), segments[2])
+
+	exported, err = s.getExported(job.Name)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), exported)
+}
+
+// savedSink keeps the saved heights
+type savedSink struct {
+	heights     []int64
+	initialized int
+	flushed     int
+}
+
+func (s *savedSink) Init(_ context.Context) error {
+	s.initialized++
+
+	return nil
+}
+
+func (s *savedSink) Save(_ context.Context, block *types.Block, _ []*types.TxResult) error {
+	s.heights = append(s.heights, block.Height)
+
+	return nil
+}
+
+func (s *savedSink) Flush(_ context.Context) error {
+	s.flushed++
+
+	return nil
+}
+
+func TestScheduler_RunJob_Sink(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	saveHeights(t, db, 1, 3)
+
+	job := &Job{
+		Name:     "mirror",
+		Schedule: "@daily",
+		Target: &Target{
+			Type: TargetSink,
+			Sink: SinkElasticsearch,
+			URL:  "http://localhost:9200",
+		},
+	}
+
+	require.NoError(t, job.validate())
+
+	var (
+		s    = New(db, []*Job{job})
+		sink = &savedSink{}
+	)
+
+	s.jobs[0].exporter = &sinkExporter{sink: sink}
+
+	require.NoError(t, s.runJob(context.Background(), s.jobs[0]))
+
+	saveHeights(t, db, 4, 5)
+
+	require.NoError(t, s.runJob(context.Background(), s.jobs[0]))
+
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, sink.heights)
+	assert.Equal(t, 1, sink.initialized)
+	assert.Equal(t, 2, sink.flushed)
+}
@@ -0,0 +1,190 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/objstore"
+)
+
+const (
+	// TargetFile writes the exported segments as files to the target directory
+	TargetFile = "file"
+
+	// TargetS3 uploads the exported segments as objects to the target S3 (compatible) bucket
+	TargetS3 = "s3"
+
+	// TargetSink mirrors the exported heights into the target sink (Elasticsearch, ClickHouse)
+	TargetSink = "sink"
+)
+
+const (
+	SinkElasticsearch = "elasticsearch"
+	SinkClickHouse    = "clickhouse"
+)
+
+// DefaultMaxHeights is the default maximum number of heights in a single exported segment
+const DefaultMaxHeights = 10000
+
+var (
+	errMissingName        = errors.New("missing job name")
+	errMissingTarget      = errors.New("missing job target")
+	errDuplicateName      = errors.New("duplicate job name")
+	errInvalidName        = errors.New("invalid job name")
+	errMissingPath        = errors.New("missing target path")
+	errMissingBucket      = errors.New("missing target bucket")
+	errMissingCredentials = errors.New("missing S3 access key, " + objstore.EnvS3AccessKey + " and " + objstore.EnvS3SecretKey + " are required")
+)
+
+// Config is the export jobs configuration file
+type Config struct {
+	Jobs []*Job `json:"jobs"`
+}
+
+// Job is a scheduled export job, exporting the heights
+// indexed since its previous run to the target
+type Job struct {
+	// Target is the export target
+	Target *Target `json:"target"`
+
+	schedule *Schedule
+
+	// Name is the unique job name, part of the exported segment names
+	Name string `json:"name"`
+
+	// Schedule is the cron schedule of the job runs, in UTC (ex. "0 * * * *" or "@hourly")
+	Schedule string `json:"schedule"`
+
+	// MaxHeights is the maximum number of heights in a single exported segment,
+	// DefaultMaxHeights if 0. Larger ranges are exported as multiple segments
+	MaxHeights uint64 `json:"maxHeights,omitempty"`
+}
+
+// Target is the export job target
+type Target struct {
+	// Type is the target type (file, s3, sink)
+	Type string `json:"type"`
+
+	// Path is the export directory, for the file target
+	Path string `json:"path,omitempty"`
+
+	// Bucket is the bucket name, for the s3 target. The access key
+	// is read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY variables
+	Bucket string `json:"bucket,omitempty"`
+
+	// Prefix is the key prefix of the exported objects, for the s3 target
+	Prefix string `json:"prefix,omitempty"`
+
+	// Endpoint is the S3 API endpoint, for the S3 compatible stores (ex. https://storage.googleapis.com)
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region is the bucket region, for the s3 target
+	Region string `json:"region,omitempty"`
+
+	// Sink is the sink type (elasticsearch, clickhouse), for the sink target
+	Sink string `json:"sink,omitempty"`
+
+	// URL is the sink URL, for the sink target
+	URL string `json:"url,omitempty"`
+
+	// Compress is a flag indicating if the exported segments are gzip compressed,
+	// for the file and s3 targets
+	Compress bool `json:"compress,omitempty"`
+}
+
+// LoadJobs loads the export jobs from the JSON configuration file
+func LoadJobs(path string) ([]*Job, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read export jobs, %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse export jobs, %w", err)
+	}
+
+	names := make(map[string]struct{}, len(config.Jobs))
+
+	for _, job := range config.Jobs {
+		if err := job.validate(); err != nil {
+			return nil, fmt.Errorf("invalid export job %q, %w", job.Name, err)
+		}
+
+		if _, ok := names[job.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicateName, job.Name)
+		}
+
+		names[job.Name] = struct{}{}
+	}
+
+	return config.Jobs, nil
+}
+
+// validate validates the job, and parses its schedule
+func (j *Job) validate() error {
+	if j.Name == "" {
+		return errMissingName
+	}
+
+	// The name is part of the segment paths
+	if strings.ContainsAny(j.Name, `/\`) || j.Name == "." || j.Name == ".." {
+		return errInvalidName
+	}
+
+	schedule, err := ParseSchedule(j.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q, %w", j.Schedule, err)
+	}
+
+	j.schedule = schedule
+
+	if j.MaxHeights == 0 {
+		j.MaxHeights = DefaultMaxHeights
+	}
+
+	if j.Target == nil {
+		return errMissingTarget
+	}
+
+	return j.Target.validate()
+}
+
+// validate validates the target configuration
+func (t *Target) validate() error {
+	switch t.Type {
+	case TargetFile:
+		if t.Path == "" {
+			return errMissingPath
+		}
+
+		return nil
+	case TargetS3:
+		if t.Bucket == "" {
+			return errMissingBucket
+		}
+
+		if os.Getenv(objstore.EnvS3AccessKey) == "" || os.Getenv(objstore.EnvS3SecretKey) == "" {
+			return errMissingCredentials
+		}
+
+		return nil
+	case TargetSink:
+		if t.Sink != SinkElasticsearch && t.Sink != SinkClickHouse {
+			return fmt.Errorf("unknown sink type %q", t.Sink)
+		}
+
+		u, err := url.Parse(t.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid sink URL %q", t.URL)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown target type %q", t.Type)
+	}
+}
@@ -0,0 +1,126 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeJobs writes the export jobs configuration to a temporary file
+func writeJobs(t *testing.T, config string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "exports.json")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	return path
+}
+
+func TestLoadJobs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid jobs", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeJobs(t, `{
+			"jobs": [
+				{
+					"name": "hourly-files",
+					"schedule": "@hourly",
+					"target": {"type": "file", "path": "/tmp/exports", "compress": true}
+				},
+				{
+					"name": "warehouse",
+					"schedule": "*/15 * * * *",
+					"maxHeights": 500,
+					"target": {"type": "sink", "sink": "clickhouse", "url": "http://localhost:8123"}
+				}
+			]
+		}`)
+
+		jobs, err := LoadJobs(path)
+		require.NoError(t, err)
+
+		require.Len(t, jobs, 2)
+
+		assert.Equal(t, "hourly-files", jobs[0].Name)
+		assert.Equal(t, uint64(DefaultMaxHeights), jobs[0].MaxHeights)
+		assert.NotNil(t, jobs[0].schedule)
+		assert.True(t, jobs[0].Target.Compress)
+
+		assert.Equal(t, "warehouse", jobs[1].Name)
+		assert.Equal(t, uint64(500), jobs[1].MaxHeights)
+		assert.Equal(t, SinkClickHouse, jobs[1].Target.Sink)
+	})
+
+	t.Run("invalid jobs", func(t *testing.T) {
+		t.Parallel()
+
+		testTable := []struct {
+			name   string
+			config string
+		}{
+			{
+				"invalid JSON",
+				`{"jobs": [`,
+			},
+			{
+				"missing name",
+				`{"jobs": [{"schedule": "@daily", "target": {"type": "file", "path": "/tmp"}}]}`,
+			},
+			{
+				"invalid name",
+				`{"jobs": [{"name": "../up", "schedule": "@daily", "target": {"type": "file", "path": "/tmp"}}]}`,
+			},
+			{
+				"invalid schedule",
+				`{"jobs": [{"name": "job", "schedule": "daily", "target": {"type": "file", "path": "/tmp"}}]}`,
+			},
+			{
+				"missing target",
+				`{"jobs": [{"name": "job", "schedule": "@daily"}]}`,
+			},
+			{
+				"unknown target",
+				`{"jobs": [{"name": "job", "schedule": "@daily", "target": {"type": "ftp"}}]}`,
+			},
+			{
+				"missing path",
+				`{"jobs": [{"name": "job", "schedule": "@daily", "target": {"type": "file"}}]}`,
+			},
+			{
+				"missing bucket",
+				`{"jobs": [{"name": "job", "schedule": "@daily", "target": {"type": "s3"}}]}`,
+			},
+			{
+				"unknown sink",
+				`{"jobs": [{"name": "job", "schedule": "@daily", "target": {"type": "sink", "sink": "kafka", "url": "http://localhost"}}]}`,
+			},
+			{
+				"invalid sink URL",
+				`{"jobs": [{"name": "job", "schedule": "@daily", "target": {"type": "sink", "sink": "elasticsearch", "url": "localhost"}}]}`,
+			},
+			{
+				"duplicate name",
+				`{"jobs": [
+					{"name": "job", "schedule": "@daily", "target": {"type": "file", "path": "/tmp"}},
+					{"name": "job", "schedule": "@hourly", "target": {"type": "file", "path": "/tmp"}}
+				]}`,
+			},
+		}
+
+		for _, testCase := range testTable {
+			testCase := testCase
+
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := LoadJobs(writeJobs(t, testCase.config))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
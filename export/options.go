@@ -0,0 +1,33 @@
+package export
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+type Option func(s *Scheduler)
+
+// WithLogger sets the logger to be used
+// with the export job scheduler
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Scheduler) {
+		s.logger = logger
+	}
+}
+
+// WithNamespace sets the namespace (chain identifier) of the exported data,
+// prefixing the exported segment keys, index names and table names
+func WithNamespace(namespace string) Option {
+	return func(s *Scheduler) {
+		s.namespace = namespace
+	}
+}
+
+// WithDecoder sets the chain decoder
+// of the exported transactions
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(s *Scheduler) {
+		s.decoder = decoder
+	}
+}
@@ -0,0 +1,189 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidFieldCount = errors.New("the schedule must have 5 fields (minute hour day month weekday)")
+	errNeverScheduled    = errors.New("the schedule never triggers")
+)
+
+// scheduleHorizon is how far ahead the next schedule trigger is searched for
+const scheduleHorizon = 5 * 366 * 24 * time.Hour
+
+// descriptors are the schedule shorthands
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Schedule is a cron schedule, evaluated in UTC
+type Schedule struct {
+	minutes, hours, days, months, weekdays uint64
+
+	// anyDay and anyWeekday are flags indicating if the day fields are unrestricted
+	anyDay, anyWeekday bool
+}
+
+// ParseSchedule parses the cron schedule, with the minute, hour, day of month, month
+// and day of week fields (0 or 7 is Sunday). Fields are "*", values, ranges ("1-5")
+// and lists ("1,15"), with optional steps ("*/15"). The @hourly, @daily, @weekly
+// and @monthly shorthands are supported as well
+func ParseSchedule(spec string) (*Schedule, error) {
+	if expanded, ok := descriptors[strings.TrimSpace(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errInvalidFieldCount
+	}
+
+	var (
+		s   = &Schedule{}
+		err error
+	)
+
+	if s.minutes, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute field, %w", err)
+	}
+
+	if s.hours, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour field, %w", err)
+	}
+
+	if s.days, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day field, %w", err)
+	}
+
+	if s.months, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month field, %w", err)
+	}
+
+	if s.weekdays, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid weekday field, %w", err)
+	}
+
+	// Sunday is both 0 and 7
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+
+	s.anyDay = fields[2] == "*"
+	s.anyWeekday = fields[4] == "*"
+
+	// Make sure the schedule triggers at all (ex. not on February 30)
+	if s.Next(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, errNeverScheduled
+	}
+
+	return s, nil
+}
+
+// parseField parses the schedule field into the bitset of its values
+func parseField(field string, minValue, maxValue int) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		expr, step := part, 1
+
+		if i := strings.Index(part, "/"); i != -1 {
+			parsed, err := strconv.Atoi(part[i+1:])
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+
+			expr, step = part[:i], parsed
+		}
+
+		from, to := minValue, maxValue
+
+		switch before, after, isRange := strings.Cut(expr, "-"); {
+		case expr == "*":
+		case isRange:
+			var err error
+
+			if from, err = strconv.Atoi(before); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+
+			if to, err = strconv.Atoi(after); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(expr)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+
+			// A value with a step starts the range
+			from = value
+			if step == 1 {
+				to = value
+			}
+		}
+
+		if from < minValue || to > maxValue || from > to {
+			return 0, fmt.Errorf("value out of range %q", part)
+		}
+
+		for value := from; value <= to; value += step {
+			set |= 1 << value
+		}
+	}
+
+	return set, nil
+}
+
+// Next returns the first schedule trigger after the time,
+// or the zero time if it doesn't trigger in the next 5 years
+func (s *Schedule) Next(after time.Time) time.Time {
+	var (
+		t       = after.UTC().Truncate(time.Minute).Add(time.Minute)
+		horizon = t.Add(scheduleHorizon)
+	)
+
+	for t.Before(horizon) {
+		switch {
+		case s.months&(1<<t.Month()) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hours&(1<<t.Hour()) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches checks if the day matches the schedule. As in cron,
+// if both day fields are restricted, either of them has to match
+func (s *Schedule) dayMatches(t time.Time) bool {
+	var (
+		day     = s.days&(1<<t.Day()) != 0
+		weekday = s.weekdays&(1<<t.Weekday()) != 0
+	)
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
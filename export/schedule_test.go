@@ -0,0 +1,135 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name string
+		spec string
+	}{
+		{
+			"missing fields",
+			"0 * * *",
+		},
+		{
+			"unknown shorthand",
+			"@yearly",
+		},
+		{
+			"invalid value",
+			"x * * * *",
+		},
+		{
+			"value out of range",
+			"60 * * * *",
+		},
+		{
+			"invalid range",
+			"0 5-1 * * *",
+		},
+		{
+			"invalid step",
+			"*/0 * * * *",
+		},
+		{
+			"never triggers",
+			"0 0 30 2 *",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseSchedule(testCase.spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	after := time.Date(2024, time.January, 10, 10, 17, 30, 0, time.UTC)
+
+	testTable := []struct {
+		expected time.Time
+		name     string
+		spec     string
+	}{
+		{
+			time.Date(2024, time.January, 10, 10, 18, 0, 0, time.UTC),
+			"every minute",
+			"* * * * *",
+		},
+		{
+			time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC),
+			"minute steps",
+			"*/15 * * * *",
+		},
+		{
+			time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC),
+			"hourly",
+			"@hourly",
+		},
+		{
+			time.Date(2024, time.January, 11, 2, 30, 0, 0, time.UTC),
+			"daily at a time",
+			"30 2 * * *",
+		},
+		{
+			time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC),
+			"weekly",
+			"@weekly",
+		},
+		{
+			time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC),
+			"sunday as 7",
+			"0 0 * * 7",
+		},
+		{
+			time.Date(2024, time.January, 11, 9, 0, 0, 0, time.UTC),
+			"weekday range",
+			"0 9 * * 1-5",
+		},
+		{
+			time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			"monthly",
+			"@monthly",
+		},
+		{
+			time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC),
+			"day or weekday",
+			"0 0 15 * 5",
+		},
+		{
+			time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			"leap day",
+			"0 0 29 2 *",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseSchedule(testCase.spec)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, schedule.Next(after))
+		})
+	}
+}
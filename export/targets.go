@@ -0,0 +1,234 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/objstore"
+	"github.com/gnolang/tx-indexer/sinks"
+	"github.com/gnolang/tx-indexer/sinks/clickhouse"
+	"github.com/gnolang/tx-indexer/sinks/elasticsearch"
+)
+
+// heightFn is called for each exported height, in height order
+type heightFn func(block *types.Block, results []*types.TxResult) error
+
+// heightsFn reads the exported heights, calling the height function for each
+type heightsFn func(fn heightFn) error
+
+// exporter exports the height ranges to the job target
+type exporter interface {
+	// export exports the heights of the range [from, to]
+	export(ctx context.Context, job string, from, to uint64, heights heightsFn) error
+}
+
+// newExporter creates the exporter of the (validated) target.
+// The namespace is the key prefix of the exported segments, if any
+func newExporter(target *Target, namespace string, decoder *decode.Decoder) exporter {
+	switch target.Type {
+	case TargetFile:
+		return &segmentExporter{
+			store:     objstore.NewDir(target.Path),
+			decoder:   decoder,
+			namespace: namespace,
+			compress:  target.Compress,
+		}
+	case TargetS3:
+		opts := []objstore.S3Option{
+			objstore.WithS3SessionToken(os.Getenv(objstore.EnvS3SessionToken)),
+		}
+
+		if target.Endpoint != "" {
+			opts = append(opts, objstore.WithS3Endpoint(target.Endpoint))
+		}
+
+		if target.Region != "" {
+			opts = append(opts, objstore.WithS3Region(target.Region))
+		}
+
+		return &segmentExporter{
+			store: objstore.NewS3(
+				target.Bucket,
+				os.Getenv(objstore.EnvS3AccessKey),
+				os.Getenv(objstore.EnvS3SecretKey),
+				opts...,
+			),
+			decoder:   decoder,
+			prefix:    target.Prefix,
+			namespace: namespace,
+			compress:  target.Compress,
+		}
+	default:
+		return &sinkExporter{
+			sink: newSink(target, namespace, decoder),
+		}
+	}
+}
+
+// segmentLine is a single line of the exported segment
+type segmentLine struct {
+	Record any `json:"record"`
+
+	// Kind is the record kind (block, tx, msg, event)
+	Kind string `json:"kind"`
+}
+
+// segmentExporter exports the height ranges as segment objects, with the flattened
+// block, transaction, message and event records of the heights as JSON lines
+type segmentExporter struct {
+	store   objstore.Store
+	decoder *decode.Decoder
+
+	prefix    string
+	namespace string
+	compress  bool
+}
+
+func (e *segmentExporter) export(ctx context.Context, job string, from, to uint64, heights heightsFn) error {
+	var (
+		buf bytes.Buffer
+		w   io.Writer = &buf
+		gz  *gzip.Writer
+	)
+
+	if e.compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+
+	encoder := json.NewEncoder(w)
+
+	encode := func(kind string, record any) error {
+		return encoder.Encode(&segmentLine{Kind: kind, Record: record})
+	}
+
+	err := heights(func(block *types.Block, results []*types.TxResult) error {
+		records := sinks.Flatten(block, results, e.decoder)
+
+		if err := encode("block", records.Block); err != nil {
+			return err
+		}
+
+		for _, tx := range records.Txs {
+			if err := encode("tx", tx); err != nil {
+				return err
+			}
+		}
+
+		for _, msg := range records.Msgs {
+			if err := encode("msg", msg); err != nil {
+				return err
+			}
+		}
+
+		for _, event := range records.Events {
+			if err := encode("event", event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("unable to compress segment, %w", err)
+		}
+	}
+
+	return e.store.Put(ctx, e.segmentKey(job, from, to), buf.Bytes())
+}
+
+// segmentKey returns the object key of the exported segment.
+// Heights are zero padded, so the segments are listed in height order
+func (e *segmentExporter) segmentKey(job string, from, to uint64) string {
+	name := fmt.Sprintf("%012d-%012d.ndjson", from, to)
+	if e.compress {
+		name += ".gz"
+	}
+
+	return e.prefix + path.Join(e.namespace, job, name)
+}
+
+// sink is the indexed data sink
+type sink interface {
+	// Init prepares the sink for saving the data
+	Init(ctx context.Context) error
+
+	// Save saves the block and its transaction results
+	Save(ctx context.Context, block *types.Block, results []*types.TxResult) error
+}
+
+// flusher is the sink buffering the saved data
+type flusher interface {
+	// Flush saves the buffered data
+	Flush(ctx context.Context) error
+}
+
+// newSink creates the sink of the target
+func newSink(target *Target, namespace string, decoder *decode.Decoder) sink {
+	if target.Sink == SinkClickHouse {
+		opts := []clickhouse.Option{
+			clickhouse.WithDecoder(decoder),
+		}
+
+		if namespace != "" {
+			opts = append(opts, clickhouse.WithTablePrefix(fmt.Sprintf("%s_", namespace)))
+		}
+
+		return clickhouse.New(target.URL, opts...)
+	}
+
+	prefix := elasticsearch.DefaultIndexPrefix
+	if namespace != "" {
+		prefix = fmt.Sprintf("%s-%s", prefix, namespace)
+	}
+
+	return elasticsearch.New(
+		target.URL,
+		elasticsearch.WithIndexPrefix(prefix),
+		elasticsearch.WithDecoder(decoder),
+	)
+}
+
+// sinkExporter exports the height ranges by saving them to the sink
+type sinkExporter struct {
+	sink sink
+
+	initialized bool
+}
+
+func (e *sinkExporter) export(ctx context.Context, _ string, _, _ uint64, heights heightsFn) error {
+	if !e.initialized {
+		if err := e.sink.Init(ctx); err != nil {
+			return fmt.Errorf("unable to initialize sink, %w", err)
+		}
+
+		e.initialized = true
+	}
+
+	err := heights(func(block *types.Block, results []*types.TxResult) error {
+		return e.sink.Save(ctx, block, results)
+	})
+	if err != nil {
+		return err
+	}
+
+	if f, ok := e.sink.(flusher); ok {
+		return f.Flush(ctx)
+	}
+
+	return nil
+}
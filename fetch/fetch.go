@@ -2,23 +2,36 @@ package fetch
 
 import (
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"sort"
+	"sync/atomic"
 	"time"
 
+	tm2Types "github.com/gnolang/gno/tm2/pkg/bft/types"
 	queue "github.com/madz-lab/insertion-queue"
 	"go.uber.org/zap"
 
+	"github.com/gnolang/tx-indexer/plugins"
 	"github.com/gnolang/tx-indexer/storage"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
-	"github.com/gnolang/tx-indexer/types"
 )
 
 const (
 	DefaultMaxSlots     = 100
 	DefaultMaxChunkSize = 100
+
+	// DefaultFlushSize is the default number of
+	// buffered blocks that triggers a storage write
+	DefaultFlushSize = 1000
+
+	// DefaultFlushInterval is the default maximum
+	// time the blocks are buffered before being written
+	DefaultFlushInterval = 5 * time.Second
+
+	// DefaultPersistQueueSize is the default number of
+	// fetched chunks queued for the persist stage
+	DefaultPersistQueueSize = 10
 )
 
 // Fetcher is an instance of the block indexer
@@ -33,8 +46,37 @@ type Fetcher struct {
 
 	maxSlots     int
 	maxChunkSize int64
+	startHeight  uint64
+	endHeight    uint64
+
+	flushSize        int
+	flushInterval    time.Duration
+	persistQueueSize int
+
+	// queuedHeight is the latest height queued for persisting (fetch stage)
+	queuedHeight uint64
+
+	// pending are the buffered writes, not yet persisted (persist stage)
+	pending *pendingWrites
+
+	// newestFirst is the number of recent heights indexed before the history
+	newestFirst uint64
+	tip         *tipFollower
+
+	plugins []plugins.Indexer
+
+	preSaveHooks  []SaveHook
+	postSaveHooks []SaveHook
+
+	// resetHook handles the remote chain resets. The fetcher halts if not set
+	resetHook ResetHook
 
 	queryInterval time.Duration // block query interval
+
+	// fetchActive and persistActive are the (unix nano) times
+	// the fetch and persist stage loops last ran, while fetching
+	fetchActive   atomic.Int64
+	persistActive atomic.Int64
 }
 
 // New creates a new data fetcher instance
@@ -46,13 +88,16 @@ func New(
 	opts ...Option,
 ) *Fetcher {
 	f := &Fetcher{
-		storage:       storage,
-		client:        client,
-		events:        events,
-		queryInterval: 1 * time.Second,
-		logger:        zap.NewNop(),
-		maxSlots:      DefaultMaxSlots,
-		maxChunkSize:  DefaultMaxChunkSize,
+		storage:          storage,
+		client:           client,
+		events:           events,
+		queryInterval:    1 * time.Second,
+		logger:           zap.NewNop(),
+		maxSlots:         DefaultMaxSlots,
+		maxChunkSize:     DefaultMaxChunkSize,
+		flushSize:        DefaultFlushSize,
+		flushInterval:    DefaultFlushInterval,
+		persistQueueSize: DefaultPersistQueueSize,
 	}
 
 	for _, opt := range opts {
@@ -68,8 +113,121 @@ func New(
 }
 
 // FetchChainData starts the fetching process that indexes
-// blockchain data
+// blockchain data. The chain data is fetched and persisted in separate stages,
+// connected by a bounded queue of fetched chunks. A remote chain reset
+// halts the fetcher with a ChainResetError, unless a reset hook is set
 func (f *Fetcher) FetchChainData(ctx context.Context) error {
+	now := time.Now().UnixNano()
+
+	f.fetchActive.Store(now)
+	f.persistActive.Store(now)
+
+	defer func() {
+		f.fetchActive.Store(0)
+		f.persistActive.Store(0)
+	}()
+
+	for {
+		err := f.fetchChain(ctx)
+
+		var resetErr *ChainResetError
+		if f.resetHook == nil || !errors.As(err, &resetErr) {
+			return err
+		}
+
+		f.logger.Warn(
+			"Chain reset detected",
+			zap.Uint64("local", resetErr.Local),
+			zap.Uint64("remote", resetErr.Remote),
+		)
+
+		if err := f.resetHook(ctx, resetErr); err != nil {
+			return fmt.Errorf("unable to handle chain reset, %w", err)
+		}
+
+		// Index the restarted chain from the start height
+		f.queuedHeight = 0
+		f.chunkBuffer = &slots{
+			Queue:    make([]queue.Item, 0),
+			maxSlots: f.maxSlots,
+		}
+	}
+}
+
+// fetchChain runs the fetch and persist stages, until the context is cancelled,
+// either of them fails, or the remote chain reset is detected
+func (f *Fetcher) fetchChain(ctx context.Context) error {
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
+
+	// Index the chain tip first, if configured
+	if err := f.startTipFollower(fetchCtx); err != nil {
+		return err
+	}
+
+	var (
+		persistCh    = make(chan *persistItem, f.persistQueueSize)
+		persistErrCh = make(chan error, 1)
+		persistDone  = make(chan struct{})
+	)
+
+	// Start the persist stage. The queued chunks are persisted
+	// even after shutdown, so the persist stage is not canceled
+	go func() {
+		defer close(persistDone)
+
+		err := f.persist(context.WithoutCancel(ctx), persistCh)
+		if err != nil {
+			// Persisting failed, stop fetching
+			cancelFetch()
+		}
+
+		persistErrCh <- err
+	}()
+
+	fetchErr := f.fetch(fetchCtx, persistCh, persistDone)
+
+	// Wait for the persist stage to write the queued chunks
+	close(persistCh)
+
+	persistErr := <-persistErrCh
+
+	if f.tip != nil {
+		f.tip.stop()
+	}
+
+	// Log the height the indexing resumes from
+	if latest, err := f.storage.GetLatestHeight(); err == nil {
+		f.logger.Info(
+			"Persisted the fetched chain data",
+			zap.Uint64("latest", latest),
+			zap.Uint64("resume", latest+1),
+		)
+	}
+
+	return errors.Join(fetchErr, persistErr)
+}
+
+// LastActive returns the time the fetcher stages last ran (the least recent of the two),
+// for detecting a wedged fetcher. The stages run at least every second while fetching.
+// Returns the zero time if the fetcher is not fetching
+func (f *Fetcher) LastActive() time.Time {
+	fetchActive, persistActive := f.fetchActive.Load(), f.persistActive.Load()
+	if fetchActive == 0 || persistActive == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, min(fetchActive, persistActive))
+}
+
+// fetch runs the fetch stage, which spawns the workers fetching the chain data,
+// and queues the sequential fetched chunks for persisting. On shutdown, the already
+// fetched sequential chunks are queued as well, unless the persist stage is done
+func (f *Fetcher) fetch(
+	ctx context.Context,
+	persistCh chan<- *persistItem,
+	persistDone <-chan struct{},
+) error {
 	collectorCh := make(chan *workerResponse, DefaultMaxSlots)
 
 	// attemptRangeFetch compares local and remote state
@@ -95,14 +253,55 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 			return nil
 		}
 
+		// Check if the remote chain was reset below the indexed height
+		if latestRemote < latestLocal {
+			reset, resetErr := f.isChainReset(latestRemote)
+			if resetErr != nil {
+				return resetErr
+			}
+
+			if reset {
+				return &ChainResetError{Local: latestLocal, Remote: latestRemote}
+			}
+
+			f.logger.Warn(
+				"remote chain behind the indexed height",
+				zap.Uint64("local", latestLocal),
+				zap.Uint64("remote", latestRemote),
+			)
+
+			return nil
+		}
+
+		// Queued heights are not persisted yet, but are not fetched again
+		if f.queuedHeight > latestLocal {
+			latestLocal = f.queuedHeight
+		}
+
+		// Heights above the end height (shard range) are never fetched
+		if f.endHeight != 0 && latestRemote > f.endHeight {
+			latestRemote = f.endHeight
+		}
+
+		// The tip heights are backfilled once the history is indexed
+		if f.tip != nil && !f.tip.stopped() && latestRemote >= f.tip.from {
+			latestRemote = f.tip.from - 1
+		}
+
+		// Heights below the start height are never fetched
+		from := latestLocal + 1
+		if from < f.startHeight {
+			from = f.startHeight
+		}
+
 		// Check if there is a block gap
-		if latestRemote <= latestLocal {
+		if latestRemote < from {
 			// No gap, nothing to sync
 			return nil
 		}
 
 		gaps := f.chunkBuffer.reserveChunkRanges(
-			latestLocal+1,
+			from,
 			latestRemote,
 			f.maxChunkSize,
 		)
@@ -135,36 +334,100 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 		return err
 	}
 
+	// saveChunk saves the fetched chunk in its slot
+	saveChunk := func(response *workerResponse) {
+		// Find the slot index.
+		// The reason for this search, is because the underlying
+		// slots are shifted constantly to accommodate new ranges,
+		// so by the time a slot is fetched, its original
+		// position is not guaranteed
+		index := sort.Search(f.chunkBuffer.Len(), func(i int) bool {
+			return f.chunkBuffer.getSlot(i).chunkRange.from >= response.chunkRange.from
+		})
+
+		if response.error != nil {
+			f.logger.Error(
+				"error encountered during chunk fetch",
+				zap.String("error", response.error.Error()),
+			)
+		}
+
+		if response.chunk == nil {
+			// Nothing was fetched, free the slot so the range is fetched again.
+			// A nil chunk would otherwise block the queueing of the following chunks
+			f.chunkBuffer.removeSlot(index)
+
+			return
+		}
+
+		f.chunkBuffer.setChunk(index, response.chunk)
+	}
+
+	// queueFetched queues the fetched sequential chunks for persisting,
+	// until the persist stage is done
+	queueFetched := func() {
+		for f.chunkBuffer.Len() > 0 {
+			item := f.chunkBuffer.getSlot(0)
+			if item.chunk == nil {
+				return
+			}
+
+			f.chunkBuffer.PopFront()
+
+			select {
+			case <-persistDone:
+				return
+			case persistCh <- &persistItem{slot: item, caughtUp: f.chunkBuffer.Len() == 0}:
+				f.queuedHeight = item.chunkRange.to
+			}
+		}
+	}
+
+	// shutdown stops the fetch stage, queueing the unqueued chunk (if any),
+	// and the fetched chunks following it, so their progress is not lost.
+	// The in-flight workers are canceled, and their chunks are fetched again on restart
+	shutdown := func(unqueued *persistItem) error {
+		if unqueued != nil {
+			select {
+			case <-persistDone:
+			case persistCh <- unqueued:
+				f.queuedHeight = unqueued.slot.chunkRange.to
+			}
+		}
+
+		// Save the chunks already collected from the workers
+		for collected := true; collected; {
+			select {
+			case response := <-collectorCh:
+				saveChunk(response)
+			default:
+				collected = false
+			}
+		}
+
+		queueFetched()
+
+		f.logger.Info(
+			"Fetcher service shut down",
+			zap.Uint64("queued", f.queuedHeight),
+		)
+
+		return nil
+	}
+
 	for {
+		f.fetchActive.Store(time.Now().UnixNano())
+
 		select {
 		case <-ctx.Done():
-			f.logger.Info("Fetcher service shut down")
-			close(collectorCh)
-
-			return nil
+			return shutdown(nil)
 		case <-ticker.C:
 			if err := attemptRangeFetch(); err != nil {
 				return err
 			}
 		case response := <-collectorCh:
-			// Find the slot index.
-			// The reason for this search, is because the underlying
-			// slots are shifted constantly to accommodate new ranges,
-			// so by the time a slot is fetched, its original
-			// position is not guaranteed
-			index := sort.Search(f.chunkBuffer.Len(), func(i int) bool {
-				return f.chunkBuffer.getSlot(i).chunkRange.from >= response.chunkRange.from
-			})
-
-			if response.error != nil {
-				f.logger.Error(
-					"error encountered during chunk fetch",
-					zap.String("error", response.error.Error()),
-				)
-			}
-
 			// Save the chunk
-			f.chunkBuffer.setChunk(index, response.chunk)
+			saveChunk(response)
 
 			for f.chunkBuffer.Len() > 0 {
 				// Peek the next sequential slot
@@ -178,68 +441,57 @@ func (f *Fetcher) FetchChainData(ctx context.Context) error {
 				// Pop the next chunk
 				f.chunkBuffer.PopFront()
 
-				wb := f.storage.WriteBatch()
-
-				// Save the fetched data
-				for blockIndex, block := range item.chunk.blocks {
-					if saveErr := wb.SetBlock(block); saveErr != nil {
-						// This is a design choice that really highlights the strain
-						// of keeping legacy testnets running. Current TM2 testnets
-						// have blocks / transactions that are no longer compatible
-						// with latest "master" changes for Amino, so these blocks / txs are ignored,
-						// as opposed to this error being a show-stopper for the fetcher
-						f.logger.Error("unable to save block", zap.String("err", saveErr.Error()))
-
-						continue
-					}
-
-					f.logger.Debug("Added block data to batch", zap.Int64("number", block.Height))
-
-					// Get block results
-					txResults := item.chunk.results[blockIndex]
-
-					// Save the fetched transaction results
-					for _, txResult := range txResults {
-						if err := wb.SetTx(txResult); err != nil {
-							f.logger.Error("unable to  save tx", zap.String("err", err.Error()))
-
-							continue
-						}
-
-						f.logger.Debug(
-							"Added tx to batch",
-							zap.String("hash", base64.StdEncoding.EncodeToString(txResult.Tx.Hash())),
-						)
-					}
-
-					// Alert any listeners of a new saved block
-					event := &types.NewBlock{
-						Block:   block,
-						Results: txResults,
-					}
+				queued := &persistItem{
+					slot:     item,
+					caughtUp: f.chunkBuffer.Len() == 0,
+				}
 
-					f.events.SignalEvent(event)
+				// Queue the chunk for persisting, waiting
+				// for the persist stage if the queue is full
+				select {
+				case <-ctx.Done():
+					return shutdown(queued)
+				case persistCh <- queued:
+					f.queuedHeight = item.chunkRange.to
 				}
+			}
+		}
+	}
+}
 
-				f.logger.Info(
-					"Added to batch block and tx data for range",
-					zap.Uint64("from", item.chunkRange.from),
-					zap.Uint64("to", item.chunkRange.to),
-				)
+// runPlugins invokes the registered plugins for the saved block and its transactions,
+// stopping at the first error. The plugin writes share the batch of the block data,
+// so a failing plugin discards the batch, and the block is indexed again on restart
+func (f *Fetcher) runPlugins(wb storage.Batch, block *tm2Types.Block, results []*tm2Types.TxResult) error {
+	for _, p := range f.plugins {
+		store := plugins.NewStore(wb, p.Name())
 
-				// Save the latest height data
-				if err := wb.SetLatestHeight(item.chunkRange.to); err != nil {
-					if rErr := wb.Rollback(); rErr != nil {
-						return fmt.Errorf("unable to save latest height info, %w, %w", err, rErr)
-					}
+		for _, txResult := range results {
+			if err := p.OnTx(store, txResult); err != nil {
+				return fmt.Errorf("unable to run plugin %s on tx, %w", p.Name(), err)
+			}
+		}
 
-					return fmt.Errorf("unable to save latest height info, %w", err)
-				}
+		if err := p.OnBlock(store, block, results); err != nil {
+			return fmt.Errorf("unable to run plugin %s on block, %w", p.Name(), err)
+		}
+	}
 
-				if err := wb.Commit(); err != nil {
-					return fmt.Errorf("error persisting block information into storage, %w", err)
-				}
-			}
+	return nil
+}
+
+// runHooks invokes the given save hooks in order, stopping at the first error
+func runHooks(
+	ctx context.Context,
+	hooks []SaveHook,
+	block *tm2Types.Block,
+	results []*tm2Types.TxResult,
+) error {
+	for _, hook := range hooks {
+		if err := hook(ctx, block, results); err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,7 +20,8 @@ import (
 
 	clientTypes "github.com/gnolang/tx-indexer/client/types"
 	"github.com/gnolang/tx-indexer/events"
-	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/plugins"
 	"github.com/gnolang/tx-indexer/storage"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 	indexerTypes "github.com/gnolang/tx-indexer/types"
@@ -55,6 +57,65 @@ func TestFetcher_FetchTransactions_Invalid(t *testing.T) {
 			fetchErr,
 		)
 	})
+
+	t.Run("unable to persist fetched data", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			commitErr = errors.New("random DB error")
+			blocks    = generateBlocks(t, 11, []*std.Tx{})
+
+			mockStorage = &mock.Storage{
+				GetLatestSavedHeightFn: func() (uint64, error) {
+					return 0, storageErrors.ErrNotFound
+				},
+				GetWriteBatchFn: func() storage.Batch {
+					return &mock.WriteBatch{
+						CommitFn: func() error {
+							return commitErr
+						},
+					}
+				},
+			}
+
+			mockClient = &mockClient{
+				createBatchFn: func() clientTypes.Batch {
+					return &mockBatch{
+						executeFn: func(_ context.Context) ([]any, error) {
+							// Force an error
+							return nil, errors.New("something is flaky")
+						},
+						countFn: func() int {
+							return 1 // to trigger execution
+						},
+					}
+				},
+				getLatestBlockNumberFn: func() (uint64, error) {
+					return 10, nil
+				},
+				getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+					return &core_types.ResultBlock{
+						Block: blocks[num],
+					}, nil
+				},
+			}
+		)
+
+		// Create the fetcher
+		f := New(
+			mockStorage,
+			mockClient,
+			&mockEvents{},
+			WithLogger(zap.NewNop()),
+		)
+
+		// Make sure the persist error stops the fetcher
+		assert.ErrorIs(
+			t,
+			f.FetchChainData(context.Background()),
+			commitErr,
+		)
+	})
 }
 
 func TestFetcher_FetchTransactions_Valid_FullBlocks(t *testing.T) {
@@ -86,15 +147,15 @@ func TestFetcher_FetchTransactions_Valid_FullBlocks(t *testing.T) {
 				},
 			}
 
-			latestSaved = uint64(0)
+			latestSaved atomic.Uint64
 
 			mockStorage = &mock.Storage{
 				GetLatestSavedHeightFn: func() (uint64, error) {
-					if latestSaved == 0 {
+					if latestSaved.Load() == 0 {
 						return 0, storageErrors.ErrNotFound
 					}
 
-					return latestSaved, nil
+					return latestSaved.Load(), nil
 				},
 				GetWriteBatchFn: func() storage.Batch {
 					return &mock.WriteBatch{
@@ -107,7 +168,7 @@ func TestFetcher_FetchTransactions_Valid_FullBlocks(t *testing.T) {
 								cancelFn()
 							}
 
-							latestSaved = uint64(block.Height)
+							latestSaved.Store(uint64(block.Height))
 
 							return nil
 						},
@@ -249,15 +310,15 @@ func TestFetcher_FetchTransactions_Valid_FullBlocks(t *testing.T) {
 				},
 			}
 
-			latestSaved = uint64(0)
+			latestSaved atomic.Uint64
 
 			mockStorage = &mock.Storage{
 				GetLatestSavedHeightFn: func() (uint64, error) {
-					if latestSaved == 0 {
+					if latestSaved.Load() == 0 {
 						return 0, storageErrors.ErrNotFound
 					}
 
-					return latestSaved, nil
+					return latestSaved.Load(), nil
 				},
 				GetWriteBatchFn: func() storage.Batch {
 					return &mock.WriteBatch{
@@ -270,7 +331,7 @@ func TestFetcher_FetchTransactions_Valid_FullBlocks(t *testing.T) {
 								cancelFn()
 							}
 
-							latestSaved = uint64(block.Height)
+							latestSaved.Store(uint64(block.Height))
 
 							return nil
 						},
@@ -431,15 +492,15 @@ func TestFetcher_FetchTransactions_Valid_FullTransactions(t *testing.T) {
 				},
 			}
 
-			latestSaved = uint64(0)
+			latestSaved atomic.Uint64
 
 			mockStorage = &mock.Storage{
 				GetLatestSavedHeightFn: func() (uint64, error) {
-					if latestSaved == 0 {
+					if latestSaved.Load() == 0 {
 						return 0, storageErrors.ErrNotFound
 					}
 
-					return latestSaved, nil
+					return latestSaved.Load(), nil
 				},
 				GetWriteBatchFn: func() storage.Batch {
 					return &mock.WriteBatch{
@@ -452,7 +513,7 @@ func TestFetcher_FetchTransactions_Valid_FullTransactions(t *testing.T) {
 								cancelFn()
 							}
 
-							latestSaved = uint64(block.Height)
+							latestSaved.Store(uint64(block.Height))
 
 							return nil
 						},
@@ -650,10 +711,10 @@ func TestFetcher_FetchTransactions_Valid_EmptyBlocks(t *testing.T) {
 						Block: blocks[num],
 					}, nil
 				},
-				getBlockResultsFn: func(_ uint64) (*core_types.ResultBlockResults, error) {
-					t.Fatalf("should not request results")
-
-					return nil, nil
+				getBlockResultsFn: func(num uint64) (*core_types.ResultBlockResults, error) {
+					// The results are fetched alongside the block,
+					// but are ignored for empty blocks
+					return nil, fmt.Errorf("no results for block %d", num)
 				},
 			}
 		)
@@ -731,12 +792,15 @@ func TestFetcher_FetchTransactions_Valid_EmptyBlocks(t *testing.T) {
 				},
 			}
 
-			batch = make([]any, 0)
+			batch      = make([]any, 0)
+			executions = 0
 
 			mockClient = &mockClient{
 				createBatchFn: func() clientTypes.Batch {
 					return &mockBatch{
 						executeFn: func(_ context.Context) ([]any, error) {
+							executions++
+
 							results := make([]any, len(batch))
 							copy(results, batch)
 
@@ -785,6 +849,10 @@ func TestFetcher_FetchTransactions_Valid_EmptyBlocks(t *testing.T) {
 		// Run the fetch
 		require.NoError(t, f.FetchChainData(ctx))
 
+		// Make sure the chunk was fetched in a single round trip,
+		// without requesting the results of the empty blocks
+		assert.Equal(t, 1, executions)
+
 		for blockIndex := 0; blockIndex < blockNum; blockIndex++ {
 			assert.Equal(t, blocks[blockIndex+1], savedBlocks[blockIndex])
 		}
@@ -958,3 +1026,1035 @@ func serializeTxs(t *testing.T, txs []*std.Tx) types.Txs {
 
 	return serializedTxs
 }
+
+func TestFetcher_StartHeight(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum    = 100
+		startHeight = 50
+		blocks      = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		savedBlocks = make([]*types.Block, 0, blockNum-startHeight+1)
+
+		latestSaved atomic.Uint64
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestSaved.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestSaved.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						savedBlocks = append(savedBlocks, block)
+
+						// Check if all blocks are saved
+						if block.Height == int64(blockNum) {
+							// At this point, we can cancel the process
+							cancelFn()
+						}
+
+						latestSaved.Store(uint64(block.Height))
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				// Sanity check
+				if num < uint64(startHeight) || num > uint64(blockNum) {
+					t.Fatalf("invalid block requested, %d", num)
+				}
+
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithStartHeight(uint64(startHeight)),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	// Create the context
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure only the blocks from the start height are saved
+	require.Len(t, savedBlocks, blockNum-startHeight+1)
+
+	for index, block := range savedBlocks {
+		assert.Equal(t, blocks[startHeight+index], block)
+	}
+}
+
+func TestFetcher_EndHeight(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blockNum    = 100
+		startHeight = 20
+		endHeight   = 50
+		blocks      = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		savedBlocks = make([]*types.Block, 0, endHeight-startHeight+1)
+
+		latestSaved atomic.Uint64
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestSaved.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestSaved.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						savedBlocks = append(savedBlocks, block)
+
+						latestSaved.Store(uint64(block.Height))
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				// Sanity check
+				if num < uint64(startHeight) || num > uint64(endHeight) {
+					t.Fatalf("invalid block requested, %d", num)
+				}
+
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithStartHeight(uint64(startHeight)),
+		WithEndHeight(uint64(endHeight)),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	// Create the context, running the fetcher
+	// past the end height being indexed
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	go func() {
+		for latestSaved.Load() != uint64(endHeight) {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		time.Sleep(300 * time.Millisecond)
+		cancelFn()
+	}()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure only the blocks of the shard range are saved
+	require.Len(t, savedBlocks, endHeight-startHeight+1)
+
+	for index, block := range savedBlocks {
+		assert.Equal(t, blocks[startHeight+index], block)
+	}
+}
+
+func TestFetcher_WriteConflict(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blockNum       = 20
+		conflictHeight = int64(5)
+		blocks         = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				return 0, storageErrors.ErrNotFound
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						if block.Height == conflictHeight {
+							return storageErrors.ErrConflict
+						}
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+	)
+
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(1),
+		WithMaxChunkSize(10),
+	)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	// Make sure the conflicting save stops the fetcher
+	assert.ErrorIs(t, f.FetchChainData(ctx), storageErrors.ErrConflict)
+}
+
+func TestFetcher_SkippedSaves(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum = 20
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		pluginBlocks  = make([]int64, 0, blockNum)
+		alertedBlocks = make([]int64, 0, blockNum)
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				return 0, storageErrors.ErrNotFound
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						// The even heights are already saved
+						if block.Height%2 == 0 {
+							return storageErrors.ErrSkipped
+						}
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+
+		blockEvents = &mockEvents{
+			signalEventFn: func(e events.Event) {
+				block, ok := e.(*indexerTypes.NewBlock)
+				require.True(t, ok)
+
+				alertedBlocks = append(alertedBlocks, block.Block.Height)
+
+				if block.Block.Height == int64(blockNum-1) {
+					// At this point, we can cancel the process
+					cancelFn()
+				}
+			},
+		}
+
+		plugin = &mockPlugin{
+			name: "heights",
+			onBlockFn: func(_ plugins.Store, block *types.Block, _ []*types.TxResult) error {
+				pluginBlocks = append(pluginBlocks, block.Height)
+
+				return nil
+			},
+		}
+	)
+
+	f := New(
+		mockStorage,
+		mockClient,
+		blockEvents,
+		WithMaxSlots(1),
+		WithMaxChunkSize(10),
+		WithPlugins(plugin),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure the skipped blocks are not run through the plugins, or alerted
+	expected := make([]int64, 0, blockNum/2)
+
+	for height := 1; height < blockNum; height += 2 {
+		expected = append(expected, int64(height))
+	}
+
+	assert.Equal(t, expected, pluginBlocks)
+	assert.Equal(t, expected, alertedBlocks)
+}
+
+func TestFetcher_Plugins(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum = 50
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		pluginName = "heights"
+
+		pluginBlocks = make([]int64, 0, blockNum)
+		pluginWrites = make(map[string]int)
+
+		latestSaved atomic.Uint64
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestSaved.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestSaved.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						latestSaved.Store(uint64(block.Height))
+
+						return nil
+					},
+					SetPluginValueFn: func(plugin string, _, _ []byte) error {
+						pluginWrites[plugin]++
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+
+		plugin = &mockPlugin{
+			name: pluginName,
+			onBlockFn: func(store plugins.Store, block *types.Block, _ []*types.TxResult) error {
+				pluginBlocks = append(pluginBlocks, block.Height)
+
+				if block.Height == int64(blockNum) {
+					// At this point, we can cancel the process
+					cancelFn()
+				}
+
+				return store.Set([]byte(fmt.Sprintf("%d", block.Height)), nil)
+			},
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithPlugins(plugin),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	// Create the context
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure the plugin was invoked for every saved block, in order
+	require.Len(t, pluginBlocks, blockNum)
+
+	for index, height := range pluginBlocks {
+		assert.Equal(t, int64(index+1), height)
+	}
+
+	// Make sure the plugin wrote to its own namespace
+	assert.Equal(t, map[string]int{pluginName: blockNum}, pluginWrites)
+}
+
+func TestFetcher_PluginError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		blockNum = 10
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		pluginErr = errors.New("plugin error")
+
+		committed atomic.Bool
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				return 0, storageErrors.ErrNotFound
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					CommitFn: func() error {
+						committed.Store(true)
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+
+		plugin = &mockPlugin{
+			name: "failing",
+			onBlockFn: func(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+				return pluginErr
+			},
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithPlugins(plugin),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	// Make sure the plugin error stops the fetcher,
+	// and the batch with the partial writes is discarded
+	assert.ErrorIs(t, f.FetchChainData(ctx), pluginErr)
+	assert.False(t, committed.Load())
+}
+
+func TestFetcher_SaveHooks(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum = 50
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		savedBlocks    = make([]int64, 0, blockNum/2)
+		postSaveBlocks = make([]int64, 0, blockNum/2)
+
+		latestSaved atomic.Uint64
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestSaved.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestSaved.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						savedBlocks = append(savedBlocks, block.Height)
+
+						return nil
+					},
+					SetLatestHeightFn: func(height uint64) error {
+						latestSaved.Store(height)
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+
+		// Skip saving even blocks
+		preSaveHook = func(_ context.Context, block *types.Block, _ []*types.TxResult) error {
+			if block.Height%2 == 0 {
+				return errors.New("even block")
+			}
+
+			return nil
+		}
+
+		postSaveHook = func(_ context.Context, block *types.Block, _ []*types.TxResult) error {
+			postSaveBlocks = append(postSaveBlocks, block.Height)
+
+			if block.Height == int64(blockNum-1) {
+				// At this point, we can cancel the process
+				cancelFn()
+			}
+
+			return nil
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithPreSaveHook(preSaveHook),
+		WithPostSaveHook(postSaveHook),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	// Create the context
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure only the blocks accepted by the pre-save hook
+	// were saved, and passed on to the post-save hook
+	require.Len(t, savedBlocks, blockNum/2)
+	assert.Equal(t, savedBlocks, postSaveBlocks)
+
+	for index, height := range savedBlocks {
+		assert.Equal(t, int64(2*index+1), height)
+	}
+}
+
+func TestFetcher_WriteBatching(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum = 100
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		writeBatches = 0
+		commits      = 0
+
+		latestSaved     atomic.Uint64
+		latestCommitted atomic.Uint64
+
+		signaled = make([]int64, 0, blockNum)
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestCommitted.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestCommitted.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				writeBatches++
+
+				return &mock.WriteBatch{
+					SetLatestHeightFn: func(height uint64) error {
+						latestSaved.Store(height)
+
+						return nil
+					},
+					CommitFn: func() error {
+						commits++
+						latestCommitted.Store(latestSaved.Load())
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockEvents = &mockEvents{
+			signalEventFn: func(e events.Event) {
+				blockEvent, ok := e.(*indexerTypes.NewBlock)
+				require.True(t, ok)
+
+				signaled = append(signaled, blockEvent.Block.Height)
+
+				if blockEvent.Block.Height == int64(blockNum) {
+					// At this point, we can cancel the process
+					cancelFn()
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		mockEvents,
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithFlushSize(50),
+		WithFlushInterval(time.Hour),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	// Create the context
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure the chunks were written in a few batches,
+	// and every block was signaled once persisted
+	assert.Equal(t, writeBatches, commits)
+	assert.Less(t, commits, blockNum/10)
+	assert.Equal(t, uint64(blockNum), latestCommitted.Load())
+
+	require.Len(t, signaled, blockNum)
+
+	for index, height := range signaled {
+		assert.Equal(t, int64(index+1), height)
+	}
+}
+
+func TestFetcher_NewestFirst(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum = 100
+		recent   = 10
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		// tipIndexed is closed once the chain tip is alerted
+		tipIndexed = make(chan struct{})
+
+		signaled      = make([]int64, 0, blockNum)
+		pluginHeights = make([]int64, 0, blockNum)
+
+		latestSaved atomic.Uint64
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestSaved.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestSaved.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetLatestHeightFn: func(height uint64) error {
+						latestSaved.Store(height)
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockEvents = &mockEvents{
+			signalEventFn: func(e events.Event) {
+				blockEvent, ok := e.(*indexerTypes.NewBlock)
+				require.True(t, ok)
+
+				signaled = append(signaled, blockEvent.Block.Height)
+
+				if blockEvent.Block.Height == int64(blockNum) {
+					close(tipIndexed)
+				}
+			},
+		}
+
+		plugin = &mockPlugin{
+			name: "heights",
+			onBlockFn: func(_ plugins.Store, block *types.Block, _ []*types.TxResult) error {
+				pluginHeights = append(pluginHeights, block.Height)
+
+				if block.Height == int64(blockNum) {
+					// At this point, we can cancel the process
+					cancelFn()
+				}
+
+				return nil
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				// Hold the history back until the tip is indexed
+				if num <= uint64(blockNum-recent) {
+					<-tipIndexed
+				}
+
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+	)
+
+	// Create the fetcher
+	f := New(
+		mockStorage,
+		mockClient,
+		mockEvents,
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithPlugins(plugin),
+		WithNewestFirst(uint64(recent)),
+	)
+
+	// Short interval to force spawning
+	f.queryInterval = 100 * time.Millisecond
+
+	// Create the context
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure the tip was alerted first, and every block was alerted once
+	require.Len(t, signaled, blockNum)
+
+	for index, height := range signaled {
+		if index < recent {
+			assert.Equal(t, int64(blockNum-recent+index+1), height)
+
+			continue
+		}
+
+		assert.Equal(t, int64(index-recent+1), height)
+	}
+
+	// Make sure the plugins processed the chain in order
+	require.Len(t, pluginHeights, blockNum)
+
+	for index, height := range pluginHeights {
+		assert.Equal(t, int64(index+1), height)
+	}
+
+	assert.Equal(t, uint64(blockNum), latestSaved.Load())
+}
+
+func TestFetcher_ShutdownPersistsFetched(t *testing.T) {
+	t.Parallel()
+
+	var cancelFn context.CancelFunc
+
+	var (
+		blockNum = 100
+		blocks   = generateBlocks(t, blockNum+1, []*std.Tx{})
+
+		fetched atomic.Int64
+
+		latestSaved     atomic.Uint64
+		latestCommitted atomic.Uint64
+
+		mockStorage = &mock.Storage{
+			GetLatestSavedHeightFn: func() (uint64, error) {
+				if latestCommitted.Load() == 0 {
+					return 0, storageErrors.ErrNotFound
+				}
+
+				return latestCommitted.Load(), nil
+			},
+			GetWriteBatchFn: func() storage.Batch {
+				return &mock.WriteBatch{
+					SetBlockFn: func(block *types.Block) error {
+						if block.Height != 1 {
+							return nil
+						}
+
+						// Hold the persist stage until all the chunks are fetched,
+						// and shut down with the fetched chunks not queued yet
+						require.Eventually(t, func() bool {
+							return fetched.Load() == int64(blockNum)
+						}, 5*time.Second, 10*time.Millisecond)
+
+						time.Sleep(100 * time.Millisecond)
+						cancelFn()
+
+						return nil
+					},
+					SetLatestHeightFn: func(height uint64) error {
+						latestSaved.Store(height)
+
+						return nil
+					},
+					CommitFn: func() error {
+						latestCommitted.Store(latestSaved.Load())
+
+						return nil
+					},
+				}
+			},
+		}
+
+		mockClient = &mockClient{
+			createBatchFn: func() clientTypes.Batch {
+				return &mockBatch{
+					executeFn: func(_ context.Context) ([]any, error) {
+						// Force an error
+						return nil, errors.New("something is flaky")
+					},
+					countFn: func() int {
+						return 1 // to trigger execution
+					},
+				}
+			},
+			getLatestBlockNumberFn: func() (uint64, error) {
+				return uint64(blockNum), nil
+			},
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				fetched.Add(1)
+
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+	)
+
+	// Create the fetcher, with a single queued chunk
+	f := New(
+		mockStorage,
+		mockClient,
+		&mockEvents{},
+		WithMaxSlots(10),
+		WithMaxChunkSize(10),
+		WithPersistQueueSize(1),
+		WithFlushInterval(time.Hour),
+	)
+
+	// Create the context
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Run the fetch
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure the fetched chunks were persisted on shutdown
+	assert.Equal(t, uint64(blockNum), latestCommitted.Load())
+}
+
+func TestFetcher_LastActive(t *testing.T) {
+	t.Parallel()
+
+	f := New(&mock.Storage{}, &mockClient{}, &mockEvents{})
+
+	// Make sure the fetcher is not active while not fetching
+	assert.True(t, f.LastActive().IsZero())
+
+	now := time.Now()
+
+	f.fetchActive.Store(now.UnixNano())
+	f.persistActive.Store(now.Add(-time.Minute).UnixNano())
+
+	// Make sure the least recent stage run is returned
+	assert.Equal(t, now.Add(-time.Minute).UnixNano(), f.LastActive().UnixNano())
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 
 	clientTypes "github.com/gnolang/tx-indexer/client/types"
 	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/plugins"
 )
 
 type (
@@ -116,3 +118,49 @@ func (m *mockEvents) SignalEvent(event events.Event) {
 		m.signalEventFn(event)
 	}
 }
+
+type (
+	onTxDelegate    func(plugins.Store, *types.TxResult) error
+	onBlockDelegate func(plugins.Store, *types.Block, []*types.TxResult) error
+)
+
+type mockPlugin struct {
+	onTxFn    onTxDelegate
+	onBlockFn onBlockDelegate
+
+	name string
+}
+
+func (m *mockPlugin) Name() string {
+	return m.name
+}
+
+func (m *mockPlugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	if m.onTxFn != nil {
+		return m.onTxFn(store, txResult)
+	}
+
+	return nil
+}
+
+func (m *mockPlugin) OnBlock(store plugins.Store, block *types.Block, results []*types.TxResult) error {
+	if m.onBlockFn != nil {
+		return m.onBlockFn(store, block, results)
+	}
+
+	return nil
+}
+
+type replaceHeightDelegate func(*types.Block, []*types.TxResult) error
+
+type mockReplacer struct {
+	replaceHeightFn replaceHeightDelegate
+}
+
+func (m *mockReplacer) ReplaceHeight(block *types.Block, txs []*types.TxResult) error {
+	if m.replaceHeightFn != nil {
+		return m.replaceHeightFn(block, txs)
+	}
+
+	return nil
+}
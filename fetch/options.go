@@ -1,6 +1,12 @@
 package fetch
 
-import "go.uber.org/zap"
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
 
 type Option func(f *Fetcher)
 
@@ -27,3 +33,85 @@ func WithMaxChunkSize(maxChunkSize int64) Option {
 		f.maxChunkSize = maxChunkSize
 	}
 }
+
+// WithStartHeight sets the height from which
+// the fetcher starts indexing, if the storage is behind it
+func WithStartHeight(startHeight uint64) Option {
+	return func(f *Fetcher) {
+		f.startHeight = startHeight
+	}
+}
+
+// WithEndHeight sets the height up to which the fetcher indexes the chain,
+// for indexing a shard of the chain history. The chain is indexed to the tip by default
+func WithEndHeight(endHeight uint64) Option {
+	return func(f *Fetcher) {
+		f.endHeight = endHeight
+	}
+}
+
+// WithPlugins registers the indexer plugins, invoked
+// for every block saved by the fetcher
+func WithPlugins(indexers ...plugins.Indexer) Option {
+	return func(f *Fetcher) {
+		f.plugins = append(f.plugins, indexers...)
+	}
+}
+
+// WithPreSaveHook registers a hook called before each block is saved.
+// The hook can enrich the block data in place, or return an error
+// to skip saving the block
+func WithPreSaveHook(hook SaveHook) Option {
+	return func(f *Fetcher) {
+		f.preSaveHooks = append(f.preSaveHooks, hook)
+	}
+}
+
+// WithPostSaveHook registers a hook called after each block
+// is persisted, for example to mirror the data elsewhere
+func WithPostSaveHook(hook SaveHook) Option {
+	return func(f *Fetcher) {
+		f.postSaveHooks = append(f.postSaveHooks, hook)
+	}
+}
+
+// WithResetHook sets the hook handling the remote chain resets,
+// after which the chain is indexed again. The fetcher halts on a reset by default
+func WithResetHook(hook ResetHook) Option {
+	return func(f *Fetcher) {
+		f.resetHook = hook
+	}
+}
+
+// WithFlushSize sets the number of buffered blocks
+// that triggers a storage write
+func WithFlushSize(blocks int) Option {
+	return func(f *Fetcher) {
+		f.flushSize = blocks
+	}
+}
+
+// WithFlushInterval sets the maximum time the blocks
+// are buffered before being written to storage
+func WithFlushInterval(interval time.Duration) Option {
+	return func(f *Fetcher) {
+		f.flushInterval = interval
+	}
+}
+
+// WithPersistQueueSize sets the number of fetched chunks
+// queued for persisting, before the fetch stage waits for storage
+func WithPersistQueueSize(chunks int) Option {
+	return func(f *Fetcher) {
+		f.persistQueueSize = chunks
+	}
+}
+
+// WithNewestFirst sets the number of most recent heights indexed first,
+// when the indexer is further behind the chain. The older heights
+// are backfilled in the background
+func WithNewestFirst(recent uint64) Option {
+	return func(f *Fetcher) {
+		f.newestFirst = recent
+	}
+}
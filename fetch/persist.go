@@ -0,0 +1,273 @@
+package fetch
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	tm2Types "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+	"github.com/gnolang/tx-indexer/types"
+)
+
+// pendingWrites are the block writes buffered in a single storage batch.
+// The latest height is written in the same batch, so a crash
+// never leaves the latest height ahead of the persisted blocks
+type pendingWrites struct {
+	batch storage.Batch
+	saved []*types.NewBlock // the buffered blocks, signaled once persisted
+
+	blocks int       // the number of buffered blocks
+	height uint64    // the latest buffered height
+	since  time.Time // the time of the first buffered write
+}
+
+// persistItem is a fetched chunk queued for the persist stage
+type persistItem struct {
+	slot     *slot
+	caughtUp bool // flag indicating the fetcher is caught up with the chain
+}
+
+// persist runs the persist stage, which writes the queued chunks to storage
+// in order. It returns once the queue is closed, and the queued writes are flushed
+func (f *Fetcher) persist(ctx context.Context, persistCh <-chan *persistItem) error {
+	ticker := time.NewTicker(f.queryInterval)
+	defer ticker.Stop()
+
+	for {
+		f.persistActive.Store(time.Now().UnixNano())
+
+		select {
+		case item, more := <-persistCh:
+			if !more {
+				// Persist any buffered writes before shutting down
+				return f.flush(ctx)
+			}
+
+			if err := f.bufferChunk(ctx, item.slot); err != nil {
+				return err
+			}
+
+			// Flush the buffered writes once they reach the flush size,
+			// or right away when caught up with the chain
+			if f.pending.blocks >= f.flushSize || item.caughtUp {
+				if err := f.flush(ctx); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if f.pending != nil && time.Since(f.pending.since) >= f.flushInterval {
+				if err := f.flush(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// bufferChunk adds the fetched chunk data to the pending storage batch
+func (f *Fetcher) bufferChunk(ctx context.Context, item *slot) error {
+	if f.pending == nil {
+		f.pending = &pendingWrites{
+			batch: f.storage.WriteBatch(),
+			since: time.Now(),
+		}
+	}
+
+	wb := f.pending.batch
+
+	// Save the fetched data
+	saved, err := f.writeBlocks(ctx, wb, item.chunk)
+	if err != nil {
+		f.pending = nil
+
+		if rErr := wb.Rollback(); rErr != nil {
+			return fmt.Errorf("%w, %w", err, rErr)
+		}
+
+		return err
+	}
+
+	// Run the plugins on the saved data
+	for _, savedBlock := range saved {
+		if err := f.runPlugins(wb, savedBlock.Block, savedBlock.Results); err != nil {
+			f.pending = nil
+
+			if rErr := wb.Rollback(); rErr != nil {
+				return fmt.Errorf("%w, %w", err, rErr)
+			}
+
+			return err
+		}
+	}
+
+	f.pending.saved = append(f.pending.saved, saved...)
+
+	f.logger.Info(
+		"Added to batch block and tx data for range",
+		zap.Uint64("from", item.chunkRange.from),
+		zap.Uint64("to", item.chunkRange.to),
+	)
+
+	// Save the latest height data
+	if err := wb.SetLatestHeight(item.chunkRange.to); err != nil {
+		f.pending = nil
+
+		if rErr := wb.Rollback(); rErr != nil {
+			return fmt.Errorf("unable to save latest height info, %w, %w", err, rErr)
+		}
+
+		return fmt.Errorf("unable to save latest height info, %w", err)
+	}
+
+	f.pending.blocks += len(item.chunk.blocks)
+	f.pending.height = item.chunkRange.to
+
+	return nil
+}
+
+// flush persists the pending storage batch, if any, and alerts
+// the listeners and post-save hooks of the persisted blocks
+func (f *Fetcher) flush(ctx context.Context) error {
+	pending := f.pending
+	if pending == nil {
+		return nil
+	}
+
+	f.pending = nil
+
+	if err := pending.batch.Commit(); err != nil {
+		return fmt.Errorf("error persisting block information into storage, %w", err)
+	}
+
+	f.logger.Info(
+		"Persisted block and tx data",
+		zap.Int("blocks", pending.blocks),
+		zap.Uint64("latest", pending.height),
+	)
+
+	// Stop indexing the chain tip separately,
+	// once the history is backfilled up to it
+	if f.tip != nil && pending.height+1 >= f.tip.from {
+		f.tip.stop()
+	}
+
+	f.alert(ctx, pending.saved)
+
+	return nil
+}
+
+// writeBlocks adds the chunk blocks and their results to the storage batch,
+// returning the saved blocks. The blocks and txs whose saves are skipped (already saved)
+// are left out, so they are not run through the plugins or alerted again.
+// Only the saves conflicting with the saved data (when configured to fail) stop the write
+func (f *Fetcher) writeBlocks(ctx context.Context, wb storage.Batch, c *chunk) ([]*types.NewBlock, error) {
+	saved := make([]*types.NewBlock, 0, len(c.blocks))
+
+	for blockIndex, block := range c.blocks {
+		// Get block results
+		txResults := c.results[blockIndex]
+
+		if hookErr := runHooks(ctx, f.preSaveHooks, block, txResults); hookErr != nil {
+			f.logger.Error(
+				"pre-save hook failed, skipping block",
+				zap.Int64("number", block.Height),
+				zap.String("err", hookErr.Error()),
+			)
+
+			continue
+		}
+
+		if saveErr := wb.SetBlock(block); saveErr != nil {
+			if errors.Is(saveErr, storageErrors.ErrConflict) {
+				return nil, fmt.Errorf("unable to save block %d, %w", block.Height, saveErr)
+			}
+
+			if errors.Is(saveErr, storageErrors.ErrSkipped) {
+				// The height is already saved, along with its txs
+				f.logger.Debug("Skipped saved block", zap.Int64("number", block.Height))
+
+				continue
+			}
+
+			// This is a design choice that really highlights the strain
+			// of keeping legacy testnets running. Current TM2 testnets
+			// have blocks / transactions that are no longer compatible
+			// with latest "master" changes for Amino, so these blocks / txs are ignored,
+			// as opposed to this error being a show-stopper for the fetcher
+			f.logger.Error("unable to save block", zap.String("err", saveErr.Error()))
+
+			continue
+		}
+
+		f.logger.Debug("Added block data to batch", zap.Int64("number", block.Height))
+
+		// Save the fetched transaction results
+		savedResults := make([]*tm2Types.TxResult, 0, len(txResults))
+
+		for _, txResult := range txResults {
+			if err := wb.SetTx(txResult); err != nil {
+				if errors.Is(err, storageErrors.ErrConflict) {
+					return nil, fmt.Errorf("unable to save tx %d-%d, %w", txResult.Height, txResult.Index, err)
+				}
+
+				if errors.Is(err, storageErrors.ErrSkipped) {
+					f.logger.Debug(
+						"Skipped saved tx",
+						zap.Int64("height", txResult.Height),
+						zap.Uint32("index", txResult.Index),
+					)
+
+					continue
+				}
+
+				f.logger.Error("unable to  save tx", zap.String("err", err.Error()))
+
+				savedResults = append(savedResults, txResult)
+
+				continue
+			}
+
+			f.logger.Debug(
+				"Added tx to batch",
+				zap.String("hash", base64.StdEncoding.EncodeToString(txResult.Tx.Hash())),
+			)
+
+			savedResults = append(savedResults, txResult)
+		}
+
+		saved = append(saved, &types.NewBlock{
+			Block:   block,
+			Results: savedResults,
+		})
+	}
+
+	return saved, nil
+}
+
+// alert alerts the listeners and post-save hooks of the persisted blocks.
+// Blocks already indexed as part of the chain tip are not alerted again
+func (f *Fetcher) alert(ctx context.Context, saved []*types.NewBlock) {
+	for _, savedBlock := range saved {
+		if f.tip != nil && f.tip.indexed(savedBlock.Block.Height) {
+			continue
+		}
+
+		// Alert any listeners of a new saved block
+		f.events.SignalEvent(savedBlock)
+
+		// Run the post-save hooks on the persisted data
+		if hookErr := runHooks(ctx, f.postSaveHooks, savedBlock.Block, savedBlock.Results); hookErr != nil {
+			f.logger.Error(
+				"post-save hook failed",
+				zap.Int64("number", savedBlock.Block.Height),
+				zap.String("err", hookErr.Error()),
+			)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"go.uber.org/zap"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// ChainResetError is the error of the remote chain,
+// restarted below the indexed height (ex. a testnet reset)
+type ChainResetError struct {
+	// Local is the latest indexed height
+	Local uint64
+
+	// Remote is the latest height of the restarted chain
+	Remote uint64
+}
+
+func (e *ChainResetError) Error() string {
+	return fmt.Sprintf(
+		"chain reset detected, the remote chain is at height %d, below the indexed height %d",
+		e.Remote,
+		e.Local,
+	)
+}
+
+// ResetHook is invoked once the remote chain reset is detected, with the fetcher stopped,
+// for the indexed data to be cleared (or moved). The chain is indexed again
+// from the start height if the hook succeeds
+type ResetHook func(ctx context.Context, reset *ChainResetError) error
+
+// isChainReset checks if the remote chain, behind the indexed height, was reset.
+// The remote block at its latest height is compared with the indexed one,
+// as a lagging remote node (ex. behind a load balancer) serves the same block
+func (f *Fetcher) isChainReset(latestRemote uint64) (bool, error) {
+	if latestRemote == 0 {
+		// The chain has no blocks yet, nothing to compare
+		return false, nil
+	}
+
+	local, err := f.storage.GetBlock(latestRemote)
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// The height is not indexed (ex. below the start height),
+		// the lower remote height is the only indication
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("unable to fetch block %d, %w", latestRemote, err)
+	}
+
+	remote, err := f.client.GetBlock(latestRemote)
+	if err != nil {
+		f.logger.Error("unable to fetch block", zap.Uint64("height", latestRemote), zap.Error(err))
+
+		return false, nil
+	}
+
+	return !sameBlock(local, remote.Block), nil
+}
+
+// sameBlock checks if the blocks are the same, comparing their hashes.
+// The blocks without a hash (ex. missing the validator set hash)
+// are compared by their chain ID and time
+func sameBlock(a, b *types.Block) bool {
+	if b == nil {
+		return false
+	}
+
+	aHash, bHash := a.Hash(), b.Hash()
+	if len(aHash) != 0 && len(bHash) != 0 {
+		return bytes.Equal(aHash, bHash)
+	}
+
+	return a.ChainID == b.ChainID && a.Time.Equal(b.Time)
+}
@@ -0,0 +1,215 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientTypes "github.com/gnolang/tx-indexer/client/types"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	indexedHeight = 100
+	remoteHeight  = 10
+)
+
+// resetChain is the remote chain restarted
+// below the indexed height
+type resetChain struct {
+	indexed []*types.Block
+	remote  []*types.Block
+
+	latestSaved     atomic.Uint64
+	latestCommitted atomic.Uint64
+}
+
+func newResetChain(t *testing.T) *resetChain {
+	t.Helper()
+
+	c := &resetChain{
+		indexed: generateBlocks(t, indexedHeight+1, []*std.Tx{}),
+		remote:  generateBlocks(t, remoteHeight+1, []*std.Tx{}),
+	}
+
+	// The restarted chain blocks differ from the indexed ones
+	for _, block := range c.remote {
+		block.Time = time.Now()
+	}
+
+	c.latestCommitted.Store(indexedHeight)
+
+	return c
+}
+
+func (c *resetChain) storage() *mock.Storage {
+	return &mock.Storage{
+		GetLatestSavedHeightFn: func() (uint64, error) {
+			if c.latestCommitted.Load() == 0 {
+				return 0, storageErrors.ErrNotFound
+			}
+
+			return c.latestCommitted.Load(), nil
+		},
+		GetBlockFn: func(num uint64) (*types.Block, error) {
+			if num > c.latestCommitted.Load() {
+				return nil, storageErrors.ErrNotFound
+			}
+
+			return c.indexed[num], nil
+		},
+		GetWriteBatchFn: func() storage.Batch {
+			return &mock.WriteBatch{
+				SetLatestHeightFn: func(height uint64) error {
+					c.latestSaved.Store(height)
+
+					return nil
+				},
+				CommitFn: func() error {
+					c.latestCommitted.Store(c.latestSaved.Load())
+
+					return nil
+				},
+			}
+		},
+	}
+}
+
+func (c *resetChain) client(blocks []*types.Block) *mockClient {
+	return &mockClient{
+		createBatchFn: func() clientTypes.Batch {
+			return &mockBatch{
+				executeFn: func(_ context.Context) ([]any, error) {
+					// Force the single block fetches
+					return nil, errors.New("something is flaky")
+				},
+				countFn: func() int {
+					return 1 // to trigger execution
+				},
+			}
+		},
+		getLatestBlockNumberFn: func() (uint64, error) {
+			return remoteHeight, nil
+		},
+		getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+			return &core_types.ResultBlock{
+				Block: blocks[num],
+			}, nil
+		},
+	}
+}
+
+func TestFetcher_ChainReset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("halt", func(t *testing.T) {
+		t.Parallel()
+
+		c := newResetChain(t)
+
+		f := New(c.storage(), c.client(c.remote), &mockEvents{})
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		// Make sure the fetcher halts with the reset heights
+		var resetErr *ChainResetError
+
+		require.ErrorAs(t, f.FetchChainData(ctx), &resetErr)
+
+		assert.Equal(t, uint64(indexedHeight), resetErr.Local)
+		assert.Equal(t, uint64(remoteHeight), resetErr.Remote)
+		assert.Equal(t, uint64(indexedHeight), c.latestCommitted.Load())
+	})
+
+	t.Run("lagging remote node", func(t *testing.T) {
+		t.Parallel()
+
+		c := newResetChain(t)
+
+		// The remote node serves the indexed blocks
+		f := New(c.storage(), c.client(c.indexed), &mockEvents{})
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancelFn()
+
+		// Make sure the fetcher keeps running, and the indexed data is kept
+		require.NoError(t, f.FetchChainData(ctx))
+
+		assert.Equal(t, uint64(indexedHeight), c.latestCommitted.Load())
+	})
+
+	t.Run("reset hook", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			c     = newResetChain(t)
+			reset *ChainResetError
+		)
+
+		ctx, cancelFn := context.WithCancel(context.Background())
+		defer cancelFn()
+
+		f := New(
+			c.storage(),
+			c.client(c.remote),
+			&mockEvents{},
+			WithResetHook(func(_ context.Context, resetErr *ChainResetError) error {
+				reset = resetErr
+
+				// Wipe the indexed data
+				c.latestCommitted.Store(0)
+
+				return nil
+			}),
+			WithPostSaveHook(func(_ context.Context, block *types.Block, _ []*types.TxResult) error {
+				if block.Height == remoteHeight {
+					cancelFn()
+				}
+
+				return nil
+			}),
+		)
+
+		require.NoError(t, f.FetchChainData(ctx))
+
+		// Make sure the restarted chain is indexed again
+		require.NotNil(t, reset)
+
+		assert.Equal(t, uint64(indexedHeight), reset.Local)
+		assert.Equal(t, uint64(remoteHeight), c.latestCommitted.Load())
+	})
+
+	t.Run("failed reset hook", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			c       = newResetChain(t)
+			hookErr = errors.New("unable to wipe")
+		)
+
+		f := New(
+			c.storage(),
+			c.client(c.remote),
+			&mockEvents{},
+			WithResetHook(func(_ context.Context, _ *ChainResetError) error {
+				return hookErr
+			}),
+		)
+
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		assert.ErrorIs(t, f.FetchChainData(ctx), hookErr)
+	})
+}
@@ -0,0 +1,65 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// Replacer replaces the saved data of a height
+type Replacer interface {
+	// ReplaceHeight replaces the saved block and txs of the height
+	ReplaceHeight(block *types.Block, txs []*types.TxResult) error
+}
+
+// Resync re-fetches the blocks and tx results of the height range [from, to],
+// replacing the saved data of each height, using up to maxSlots concurrent workers.
+// Returns the number of resynced heights
+func Resync(ctx context.Context, store Replacer, client Client, from, to uint64, maxSlots int) (uint64, error) {
+	var (
+		heights  = make(chan uint64)
+		resynced atomic.Uint64
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		defer close(heights)
+
+		for height := from; height <= to; height++ {
+			select {
+			case <-gCtx.Done():
+				return gCtx.Err()
+			case heights <- height:
+			}
+		}
+
+		return nil
+	})
+
+	for i := 0; i < max(maxSlots, 1); i++ {
+		g.Go(func() error {
+			for height := range heights {
+				block, results, err := getHeight(height, client)
+				if err != nil {
+					return err
+				}
+
+				if err := store.ReplaceHeight(block, results); err != nil {
+					return fmt.Errorf("unable to replace height %d, %w", height, err)
+				}
+
+				resynced.Add(1)
+			}
+
+			return nil
+		})
+	}
+
+	err := g.Wait()
+
+	return resynced.Load(), err
+}
@@ -0,0 +1,107 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResync(t *testing.T) {
+	t.Parallel()
+
+	var (
+		txCount = 2
+		txs     = generateTransactions(t, txCount)
+		blocks  = generateBlocks(t, 20, txs)
+
+		replaced = make(map[int64][]*types.TxResult)
+		mux      sync.Mutex
+
+		mockClient = &mockClient{
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+			getBlockResultsFn: func(num uint64) (*core_types.ResultBlockResults, error) {
+				return &core_types.ResultBlockResults{
+					Height: int64(num),
+					Results: &state.ABCIResponses{
+						DeliverTxs: make([]abci.ResponseDeliverTx, txCount),
+					},
+				}, nil
+			},
+		}
+
+		mockReplacer = &mockReplacer{
+			replaceHeightFn: func(block *types.Block, txs []*types.TxResult) error {
+				mux.Lock()
+				defer mux.Unlock()
+
+				replaced[block.Height] = txs
+
+				return nil
+			},
+		}
+	)
+
+	resynced, err := Resync(context.Background(), mockReplacer, mockClient, 5, 14, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(10), resynced)
+
+	// Make sure only the range heights are replaced, with their txs
+	require.Len(t, replaced, 10)
+
+	for height := int64(5); height <= 14; height++ {
+		results, ok := replaced[height]
+		require.True(t, ok)
+
+		require.Len(t, results, txCount)
+
+		for index, result := range results {
+			assert.Equal(t, height, result.Height)
+			assert.Equal(t, uint32(index), result.Index)
+			assert.Equal(t, blocks[height].Txs[index], result.Tx)
+		}
+	}
+}
+
+func TestResync_Error(t *testing.T) {
+	t.Parallel()
+
+	var (
+		replaceErr = errors.New("unable to replace")
+
+		blocks = generateBlocks(t, 10, nil)
+
+		mockClient = &mockClient{
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+		}
+
+		mockReplacer = &mockReplacer{
+			replaceHeightFn: func(block *types.Block, _ []*types.TxResult) error {
+				if block.Height == 5 {
+					return replaceErr
+				}
+
+				return nil
+			},
+		}
+	)
+
+	_, err := Resync(context.Background(), mockReplacer, mockClient, 0, 9, 1)
+	assert.ErrorIs(t, err, replaceErr)
+}
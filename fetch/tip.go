@@ -0,0 +1,169 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// tipFollower indexes the chain tip, while the fetcher backfills the chain history.
+// The tip blocks are saved and alerted right away, but the latest height (and the plugins)
+// only advance once the backfill reaches them
+type tipFollower struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	from   uint64 // the first height of the tip
+	height uint64 // the latest indexed height of the tip, owned by the follower routine
+}
+
+// stop stops the tip follower, and waits for it to exit
+func (t *tipFollower) stop() {
+	t.cancel()
+
+	<-t.done
+}
+
+// stopped returns a flag indicating if the tip follower exited
+func (t *tipFollower) stopped() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// indexed returns a flag indicating if the height
+// was indexed (and alerted) by a stopped tip follower
+func (t *tipFollower) indexed(height int64) bool {
+	return t.stopped() && height >= 0 && uint64(height) >= t.from && uint64(height) <= t.height
+}
+
+// startTipFollower starts indexing the most recent chain heights, if the indexer
+// is further behind the chain than the newest-first window
+func (f *Fetcher) startTipFollower(ctx context.Context) error {
+	f.tip = nil
+
+	if f.newestFirst == 0 {
+		return nil
+	}
+
+	latestLocal, err := f.storage.GetLatestHeight()
+	if err != nil && !errors.Is(err, storageErrors.ErrNotFound) {
+		return fmt.Errorf("unable to fetch latest block height, %w", err)
+	}
+
+	latestRemote, err := f.client.GetLatestBlockNumber()
+	if err != nil {
+		f.logger.Error("unable to fetch latest block number, indexing oldest first", zap.Error(err))
+
+		return nil
+	}
+
+	if f.endHeight != 0 && latestRemote > f.endHeight {
+		latestRemote = f.endHeight
+	}
+
+	if latestRemote <= latestLocal+f.newestFirst || latestRemote-f.newestFirst < f.startHeight {
+		// The indexer is close to the tip, no need to backfill
+		return nil
+	}
+
+	tipCtx, cancelFn := context.WithCancel(ctx)
+
+	f.tip = &tipFollower{
+		cancel: cancelFn,
+		done:   make(chan struct{}),
+		from:   latestRemote - f.newestFirst + 1,
+	}
+
+	f.logger.Info(
+		"Indexing the chain tip first",
+		zap.Uint64("from", f.tip.from),
+		zap.Uint64("backfill", latestLocal),
+	)
+
+	go f.followTip(tipCtx, f.tip)
+
+	return nil
+}
+
+// followTip indexes the chain tip heights, until stopped
+func (f *Fetcher) followTip(ctx context.Context, tip *tipFollower) {
+	defer close(tip.done)
+
+	ticker := time.NewTicker(f.queryInterval)
+	defer ticker.Stop()
+
+	next := tip.from
+
+	for {
+		latestRemote, err := f.client.GetLatestBlockNumber()
+		if err != nil {
+			f.logger.Error("unable to fetch latest block number", zap.Error(err))
+		}
+
+		if f.endHeight != 0 && latestRemote > f.endHeight {
+			latestRemote = f.endHeight
+		}
+
+		for err == nil && next <= latestRemote && ctx.Err() == nil {
+			to := min(next+uint64(f.maxChunkSize)-1, latestRemote)
+
+			if err = f.saveTipChunk(ctx, chunkRange{from: next, to: to}); err != nil {
+				// The range is fetched again on the next attempt
+				f.logger.Error("unable to index chain tip", zap.Error(err))
+
+				break
+			}
+
+			tip.height = to
+			next = to + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// saveTipChunk fetches and persists the tip range, without updating the latest height
+func (f *Fetcher) saveTipChunk(ctx context.Context, chunkRange chunkRange) error {
+	c, err := getChunkFromBatch(chunkRange, f.client)
+	if err != nil {
+		return fmt.Errorf("unable to fetch range %d-%d, %w", chunkRange.from, chunkRange.to, err)
+	}
+
+	wb := f.storage.WriteBatch()
+
+	saved, err := f.writeBlocks(ctx, wb, c)
+	if err != nil {
+		if rErr := wb.Rollback(); rErr != nil {
+			return fmt.Errorf("%w, %w", err, rErr)
+		}
+
+		return err
+	}
+
+	if err := wb.Commit(); err != nil {
+		return fmt.Errorf("error persisting block information into storage, %w", err)
+	}
+
+	f.logger.Info(
+		"Persisted chain tip block and tx data for range",
+		zap.Uint64("from", chunkRange.from),
+		zap.Uint64("to", chunkRange.to),
+	)
+
+	f.alert(ctx, saved)
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package fetch
 
 import (
+	"context"
+
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 
 	clientTypes "github.com/gnolang/tx-indexer/client/types"
 	"github.com/gnolang/tx-indexer/events"
@@ -28,3 +31,7 @@ type Events interface {
 	// SignalEvent signals a new event to the event manager
 	SignalEvent(events.Event)
 }
+
+// SaveHook is invoked with the block and its transaction results,
+// around the block persistence
+type SaveHook func(ctx context.Context, block *types.Block, results []*types.TxResult) error
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 )
 
+// maxConcurrentHeights is the maximum number of heights
+// a worker fetches at a time, when not using batch requests
+const maxConcurrentHeights = 10
+
 // workerInfo is the work context for the fetch routine
 type workerInfo struct {
 	resCh      chan<- *workerResponse // response channel
@@ -28,23 +33,7 @@ func handleChunk(
 	client Client,
 	info *workerInfo,
 ) {
-	extractChunk := func() (*chunk, error) {
-		errs := make([]error, 0)
-
-		// Get block data from the node
-		blocks, err := getBlocksFromBatch(info.chunkRange, client)
-		errs = append(errs, err)
-
-		results, err := getTxResultFromBatch(blocks, client)
-		errs = append(errs, err)
-
-		return &chunk{
-			blocks:  blocks,
-			results: results,
-		}, errors.Join(errs...)
-	}
-
-	c, err := extractChunk()
+	c, err := getChunkFromBatch(info.chunkRange, client)
 
 	response := &workerResponse{
 		error:      err,
@@ -58,16 +47,35 @@ func handleChunk(
 	}
 }
 
-// getBlocksFromBatch gets the blocks using batch requests.
-// In case of encountering an error during fetching (remote temporarily closed, batch error...),
-// the fetch is attempted again using sequential block fetches
+// getChunkFromBatch gets the blocks and their results using batch requests: a single batch
+// of the block requests for every height in the range, followed by a single batch of the block results
+// requests of the blocks with transactions (empty blocks have no results to fetch).
+// In case of encountering any error during fetching (remote temporarily closed, batch error,
+// unexpected batch response...), the fetch is attempted again using individual block and block results requests
+func getChunkFromBatch(chunkRange chunkRange, client Client) (*chunk, error) {
+	blocks, err := getBlocksFromBatch(chunkRange, client)
+	if err != nil {
+		// Try to fetch using individual requests
+		return getChunkConcurrently(chunkRange, client)
+	}
+
+	results, err := getTxResultsFromBatch(blocks, client)
+	if err != nil {
+		// Try to fetch using individual requests
+		return getChunkConcurrently(chunkRange, client)
+	}
+
+	return &chunk{
+		blocks:  blocks,
+		results: results,
+	}, nil
+}
+
+// getBlocksFromBatch gets the blocks of the range using a single batch request
 func getBlocksFromBatch(chunkRange chunkRange, client Client) ([]*types.Block, error) {
-	var (
-		batch         = client.CreateBatch()
-		fetchedBlocks = make([]*types.Block, 0)
-	)
+	batch := client.CreateBatch()
 
-	// Add block requests to the batch
+	// Add the block requests to the batch
 	for blockNum := chunkRange.from; blockNum <= chunkRange.to; blockNum++ {
 		if err := batch.AddBlockRequest(blockNum); err != nil {
 			return nil, fmt.Errorf(
@@ -78,67 +86,47 @@ func getBlocksFromBatch(chunkRange chunkRange, client Client) ([]*types.Block, e
 		}
 	}
 
-	// Get the block results
-	blocksRaw, err := batch.Execute(context.Background())
+	// Get the blocks
+	responses, err := batch.Execute(context.Background())
 	if err != nil {
-		// Try to fetch sequentially
-		return getBlocksSequentially(chunkRange, client)
+		return nil, fmt.Errorf("unable to execute block batch, %w", err)
 	}
 
-	// Extract the blocks
-	for _, blockRaw := range blocksRaw {
-		block, ok := blockRaw.(*core_types.ResultBlock)
-		if !ok {
-			return nil, errors.New("unable to cast batch result into ResultBlock")
-		}
-
-		// Save block
-		fetchedBlocks = append(fetchedBlocks, block.Block)
+	if len(responses) != int(chunkRange.to-chunkRange.from+1) {
+		return nil, fmt.Errorf("unexpected block batch response count %d", len(responses))
 	}
 
-	return fetchedBlocks, nil
-}
+	blocks := make([]*types.Block, 0, len(responses))
 
-// getBlocksSequentially attempts to fetch blocks from the client, using sequential requests
-func getBlocksSequentially(chunkRange chunkRange, client Client) ([]*types.Block, error) {
-	var (
-		errs   = make([]error, 0)
-		blocks = make([]*types.Block, 0)
-	)
-
-	for blockNum := chunkRange.from; blockNum <= chunkRange.to; blockNum++ {
-		// Get block info from the chain
-		block, err := client.GetBlock(blockNum)
-		if err != nil {
-			errs = append(errs, fmt.Errorf("unable to get block %d, %w", blockNum, err))
-
-			continue
+	for _, response := range responses {
+		block, ok := response.(*core_types.ResultBlock)
+		if !ok || block.Block == nil {
+			return nil, errors.New("unable to cast batch result into ResultBlock")
 		}
 
 		blocks = append(blocks, block.Block)
 	}
 
-	return blocks, errors.Join(errs...)
+	return blocks, nil
 }
 
-// getTxResultFromBatch gets the tx results using batch requests.
-// In case of encountering an error during fetching (remote temporarily closed, batch error...),
-// the fetch is attempted again using sequential tx result fetches
-func getTxResultFromBatch(blocks []*types.Block, client Client) ([][]*types.TxResult, error) {
+// getTxResultsFromBatch gets the tx results of the blocks with transactions,
+// using a single batch request. Empty blocks have no results
+func getTxResultsFromBatch(blocks []*types.Block, client Client) ([][]*types.TxResult, error) {
 	var (
-		batch          = client.CreateBatch()
-		fetchedResults = make([][]*types.TxResult, len(blocks))
+		batch   = client.CreateBatch()
+		results = make([][]*types.TxResult, len(blocks))
+		indexes = make([]int, 0, len(blocks))
 	)
 
-	// Create the results request batch
-	for _, block := range blocks {
+	// Add the block results requests to the batch
+	for index, block := range blocks {
 		if block.NumTxs == 0 {
 			// No need to request results
 			// for an empty block
 			continue
 		}
 
-		// Add the request to the batch
 		if err := batch.AddBlockResultsRequest(uint64(block.Height)); err != nil {
 			return nil, fmt.Errorf(
 				"unable to add block results request for block %d, %w",
@@ -146,100 +134,150 @@ func getTxResultFromBatch(blocks []*types.Block, client Client) ([][]*types.TxRe
 				err,
 			)
 		}
+
+		indexes = append(indexes, index)
 	}
 
 	// Check if there is anything to execute
-	if batch.Count() == 0 {
-		// Batch is empty, nothing to fetch
-		return fetchedResults, nil
+	if len(indexes) == 0 {
+		return results, nil
 	}
 
 	// Get the block results
-	blockResultsRaw, err := batch.Execute(context.Background())
+	responses, err := batch.Execute(context.Background())
 	if err != nil {
-		// Try to fetch sequentially
-		return getTxResultsSequentially(blocks, client)
+		return nil, fmt.Errorf("unable to execute block results batch, %w", err)
 	}
 
-	indexOfBlockHeight := make(map[int64]int, len(blocks))
-
-	for index, block := range blocks {
-		indexOfBlockHeight[block.Height] = index
+	if len(responses) != len(indexes) {
+		return nil, fmt.Errorf("unexpected block results batch response count %d", len(responses))
 	}
 
-	// Extract the results
-	for resultsIndex, resultsRaw := range blockResultsRaw {
-		results, ok := resultsRaw.(*core_types.ResultBlockResults)
-		if !ok {
+	for i, response := range responses {
+		blockResults, ok := response.(*core_types.ResultBlockResults)
+		if !ok || blockResults.Results == nil {
 			return nil, errors.New("unable to cast batch result into ResultBlockResults")
 		}
 
-		height := results.Height
-		deliverTxs := results.Results.DeliverTxs
-		blockIndex := indexOfBlockHeight[height]
-
-		txResults := make([]*types.TxResult, blocks[blockIndex].NumTxs)
+		block := blocks[indexes[i]]
 
-		for txIndex, tx := range blocks[blockIndex].Txs {
-			result := &types.TxResult{
-				Height:   height,
-				Index:    uint32(txIndex),
-				Tx:       tx,
-				Response: deliverTxs[txIndex],
-			}
-
-			txResults[txIndex] = result
+		if len(blockResults.Results.DeliverTxs) != len(block.Txs) {
+			return nil, fmt.Errorf("unexpected result count for block %d", block.Height)
 		}
 
-		fetchedResults[resultsIndex] = txResults
+		results[indexes[i]] = txResults(block, blockResults)
 	}
 
-	return fetchedResults, nil
+	return results, nil
 }
 
-// getTxResultsSequentially attempts to fetch tx results from the client, using sequential requests
-func getTxResultsSequentially(blocks []*types.Block, client Client) ([][]*types.TxResult, error) {
+// getChunkConcurrently attempts to fetch the blocks and their results from the client,
+// using individual requests. The block and block results of a height are fetched concurrently,
+// and up to maxConcurrentHeights heights of the chunk are fetched at a time
+func getChunkConcurrently(chunkRange chunkRange, client Client) (*chunk, error) {
 	var (
-		errs    = make([]error, 0)
-		results = make([][]*types.TxResult, len(blocks))
+		count = int(chunkRange.to - chunkRange.from + 1)
+
+		blocks  = make([]*types.Block, count)
+		results = make([][]*types.TxResult, count)
+		errs    = make([]error, count)
+
+		sem = make(chan struct{}, maxConcurrentHeights)
+		wg  sync.WaitGroup
 	)
 
+	for index := 0; index < count; index++ {
+		sem <- struct{}{}
+
+		wg.Add(1)
+
+		go func(index int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			blocks[index], results[index], errs[index] = getHeight(chunkRange.from+uint64(index), client)
+		}(index)
+	}
+
+	wg.Wait()
+
+	c := &chunk{
+		blocks:  make([]*types.Block, 0, count),
+		results: make([][]*types.TxResult, 0, count),
+	}
+
 	for index, block := range blocks {
-		if block.NumTxs == 0 {
+		if block == nil {
+			// Unable to fetch the block
 			continue
 		}
 
-		// Get the transaction execution results
-		blockResults, err := client.GetBlockResults(uint64(block.Height))
-		if err != nil {
-			errs = append(
-				errs,
-				fmt.Errorf(
-					"unable to get block results for block %d, %w",
-					block.Height,
-					err,
-				),
-			)
+		c.blocks = append(c.blocks, block)
+		c.results = append(c.results, results[index])
+	}
 
-			continue
-		}
+	return c, errors.Join(errs...)
+}
 
-		// Save the transaction result
-		txResults := make([]*types.TxResult, block.NumTxs)
+// getHeight fetches the block and its results from the client, using concurrent requests
+func getHeight(height uint64, client Client) (*types.Block, []*types.TxResult, error) {
+	var (
+		blockResults *core_types.ResultBlockResults
+		resultsErr   error
 
-		for index, tx := range block.Txs {
-			result := &types.TxResult{
-				Height:   block.Height,
-				Index:    uint32(index),
-				Tx:       tx,
-				Response: blockResults.Results.DeliverTxs[index],
-			}
+		done = make(chan struct{})
+	)
 
-			txResults[index] = result
-		}
+	go func() {
+		defer close(done)
+
+		blockResults, resultsErr = client.GetBlockResults(height)
+	}()
+
+	// Get block info from the chain
+	block, err := client.GetBlock(height)
+
+	<-done
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get block %d, %w", height, err)
+	}
+
+	if block.Block.NumTxs == 0 {
+		// Empty blocks have no results
+		return block.Block, nil, nil
+	}
 
-		results[index] = txResults
+	if resultsErr != nil {
+		return block.Block, nil, fmt.Errorf(
+			"unable to get block results for block %d, %w",
+			height,
+			resultsErr,
+		)
+	}
+
+	return block.Block, txResults(block.Block, blockResults), nil
+}
+
+// txResults pairs the block transactions with their execution results.
+// Empty blocks have no results
+func txResults(block *types.Block, blockResults *core_types.ResultBlockResults) []*types.TxResult {
+	if block.NumTxs == 0 {
+		return nil
+	}
+
+	results := make([]*types.TxResult, block.NumTxs)
+
+	for index, tx := range block.Txs {
+		results[index] = &types.TxResult{
+			Height:   block.Height,
+			Index:    uint32(index),
+			Tx:       tx,
+			Response: blockResults.Results.DeliverTxs[index],
+		}
 	}
 
-	return results, errors.Join(errs...)
+	return results
 }
@@ -0,0 +1,197 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientTypes "github.com/gnolang/tx-indexer/client/types"
+)
+
+func TestWorker_GetChunkConcurrently(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunkSize = 5
+		txCount   = 2
+		txs       = generateTransactions(t, txCount)
+		blocks    = generateBlocks(t, chunkSize+1, txs)
+
+		// resultsRequested are closed once the height results are requested
+		resultsRequested = make([]chan struct{}, chunkSize+1)
+
+		mockClient = &mockClient{
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				// Make sure the results are requested
+				// while the block request is in flight
+				select {
+				case <-resultsRequested[num]:
+				case <-time.After(5 * time.Second):
+					return nil, errors.New("block results not requested concurrently")
+				}
+
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+			getBlockResultsFn: func(num uint64) (*core_types.ResultBlockResults, error) {
+				close(resultsRequested[num])
+
+				return &core_types.ResultBlockResults{
+					Height: int64(num),
+					Results: &state.ABCIResponses{
+						DeliverTxs: make([]abci.ResponseDeliverTx, txCount),
+					},
+				}, nil
+			},
+		}
+	)
+
+	for index := range resultsRequested {
+		resultsRequested[index] = make(chan struct{})
+	}
+
+	c, err := getChunkConcurrently(chunkRange{from: 1, to: uint64(chunkSize)}, mockClient)
+	require.NoError(t, err)
+
+	// Make sure the chunk is in order
+	require.Len(t, c.blocks, chunkSize)
+	require.Len(t, c.results, chunkSize)
+
+	for index, block := range c.blocks {
+		assert.Equal(t, blocks[index+1], block)
+
+		require.Len(t, c.results[index], txCount)
+
+		for txIndex, result := range c.results[index] {
+			assert.Equal(t, block.Height, result.Height)
+			assert.Equal(t, uint32(txIndex), result.Index)
+		}
+	}
+}
+
+func TestWorker_GetChunkConcurrently_MissingBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunkSize = 6
+		blocks    = generateBlocks(t, chunkSize+1, []*std.Tx{})
+
+		mockClient = &mockClient{
+			getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+				// Fail fetching the even blocks
+				if num%2 == 0 {
+					return nil, errors.New("block not available")
+				}
+
+				return &core_types.ResultBlock{
+					Block: blocks[num],
+				}, nil
+			},
+			getBlockResultsFn: func(_ uint64) (*core_types.ResultBlockResults, error) {
+				return nil, errors.New("results not available")
+			},
+		}
+	)
+
+	c, err := getChunkConcurrently(chunkRange{from: 1, to: uint64(chunkSize)}, mockClient)
+	require.Error(t, err)
+
+	// Make sure only the available blocks are in the chunk, in order
+	require.Len(t, c.blocks, chunkSize/2)
+
+	for index, block := range c.blocks {
+		assert.Equal(t, blocks[2*index+1], block)
+		assert.Nil(t, c.results[index])
+	}
+}
+
+func TestWorker_GetChunkFromBatch_Fallback(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunkSize = 4
+		txCount   = 1
+		txs       = generateTransactions(t, txCount)
+		blocks    = generateBlocks(t, chunkSize+1, txs)
+	)
+
+	testTable := []struct {
+		executeFn executeDelegate
+		name      string
+	}{
+		{
+			func(_ context.Context) ([]any, error) {
+				return nil, errors.New("batch failed")
+			},
+			"batch error",
+		},
+		{
+			func(_ context.Context) ([]any, error) {
+				// Missing responses
+				return []any{&core_types.ResultBlock{Block: blocks[1]}}, nil
+			},
+			"unexpected response count",
+		},
+		{
+			func(_ context.Context) ([]any, error) {
+				responses := make([]any, chunkSize)
+				for index := range responses {
+					responses[index] = "block"
+				}
+
+				return responses, nil
+			},
+			"unexpected response type",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := &mockClient{
+				createBatchFn: func() clientTypes.Batch {
+					return &mockBatch{
+						executeFn: testCase.executeFn,
+					}
+				},
+				getBlockFn: func(num uint64) (*core_types.ResultBlock, error) {
+					return &core_types.ResultBlock{
+						Block: blocks[num],
+					}, nil
+				},
+				getBlockResultsFn: func(num uint64) (*core_types.ResultBlockResults, error) {
+					return &core_types.ResultBlockResults{
+						Height: int64(num),
+						Results: &state.ABCIResponses{
+							DeliverTxs: make([]abci.ResponseDeliverTx, txCount),
+						},
+					}, nil
+				},
+			}
+
+			// Make sure the chunk is fetched using the individual requests
+			c, err := getChunkFromBatch(chunkRange{from: 1, to: uint64(chunkSize)}, mockClient)
+			require.NoError(t, err)
+
+			require.NotNil(t, c)
+			require.Len(t, c.blocks, chunkSize)
+
+			for index, block := range c.blocks {
+				assert.Equal(t, blocks[index+1], block)
+				assert.Len(t, c.results[index], txCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Package indexer wires the fetcher, storage and serve components
+// into a single service, that can be embedded into other Go programs
+package indexer
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/gnolang/tx-indexer/alerts"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/serve"
+	"github.com/gnolang/tx-indexer/serve/graph"
+)
+
+// Indexer is a single-chain indexer instance, consisting of
+// the chain fetcher, and the JSON-RPC / GraphQL services
+type Indexer struct {
+	storage Storage
+	client  Client
+	events  *events.Manager
+
+	fetcher *fetch.Fetcher
+	jsonrpc *serve.JSONRPC
+	mux     *chi.Mux
+
+	logger      *zap.Logger
+	fetcherOpts []fetch.Option
+	serveOpts   []serve.Option
+	graphOpts   []graph.Option
+
+	listenAddress string
+	rateLimit     int
+	webhooks      bool
+	alertRules    []*alerts.Rule
+	decoder       *decode.Decoder
+}
+
+// New creates a new indexer instance, for the given storage and chain client.
+// The storage lifecycle is managed by the caller
+func New(storage Storage, client Client, opts ...Option) *Indexer {
+	i := &Indexer{
+		storage:       storage,
+		client:        client,
+		events:        events.NewManager(),
+		logger:        zap.NewNop(),
+		listenAddress: serve.DefaultListenAddress,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	// Create the fetcher service
+	i.fetcher = fetch.New(
+		i.storage,
+		i.client,
+		i.events,
+		append(
+			[]fetch.Option{
+				fetch.WithLogger(i.logger.Named("fetcher")),
+			},
+			i.fetcherOpts...,
+		)...,
+	)
+
+	// Create the JSON-RPC service
+	i.jsonrpc = serve.NewJSONRPC(
+		i.events,
+		append(
+			[]serve.Option{
+				serve.WithLogger(
+					i.logger.Named("json-rpc"),
+				),
+				serve.WithStorage(i.storage),
+				serve.WithDecoder(i.decoder),
+			},
+			i.serveOpts...,
+		)...,
+	)
+
+	// Transaction handlers
+	i.jsonrpc.RegisterTxEndpoints(i.storage)
+
+	// Block handlers
+	i.jsonrpc.RegisterBlockEndpoints(i.storage)
+
+	// Sub handlers
+	i.jsonrpc.RegisterSubEndpoints(i.storage)
+
+	// Status handlers
+	i.jsonrpc.RegisterStatusEndpoints(i.storage, i.client)
+
+	// Watchlist handlers
+	i.jsonrpc.RegisterWatchlistEndpoints(i.webhooks)
+
+	// Create the alert rules engine
+	if len(i.alertRules) != 0 {
+		alerts.New(
+			context.Background(),
+			i.events,
+			i.alertRules,
+			alerts.WithLogger(i.logger.Named("alerts")),
+			alerts.WithDecoder(i.decoder),
+		)
+	}
+
+	// Set up the routes
+	i.mux = chi.NewMux()
+
+	if i.rateLimit != 0 {
+		i.logger.Info("rate-limit set", zap.Int("rate-limit", i.rateLimit))
+		i.mux.Use(serve.RateLimitMiddleware(i.rateLimit, false, i.logger))
+	}
+
+	i.mux = i.jsonrpc.SetupRoutes(i.mux)
+	graphOpts := append([]graph.Option{graph.WithLogger(i.logger.Named("graphql"))}, i.graphOpts...)
+
+	i.mux = graph.Setup(i.storage, i.events, i.decoder, i.mux, graphOpts...)
+
+	return i
+}
+
+// Storage returns the indexer storage
+func (i *Indexer) Storage() Storage {
+	return i.storage
+}
+
+// Events returns the indexer event manager
+func (i *Indexer) Events() *events.Manager {
+	return i.events
+}
+
+// Fetcher returns the indexer chain fetcher,
+// which can be used for monitoring its liveness
+func (i *Indexer) Fetcher() *fetch.Fetcher {
+	return i.fetcher
+}
+
+// JSONRPC returns the indexer JSON-RPC server,
+// which can be used for registering custom method handlers
+func (i *Indexer) JSONRPC() *serve.JSONRPC {
+	return i.jsonrpc
+}
+
+// Router returns the indexer HTTP router,
+// which can be used for registering custom routes
+func (i *Indexer) Router() *chi.Mux {
+	return i.mux
+}
+
+// Handler returns the indexer HTTP handler,
+// which can be mounted on any HTTP server
+func (i *Indexer) Handler() http.Handler {
+	return i.mux
+}
+
+// Fetch runs the chain fetcher, until the context is cancelled
+func (i *Indexer) Fetch(ctx context.Context) error {
+	return i.fetcher.FetchChainData(ctx)
+}
+
+// Serve runs the HTTP server on the listen address, until the context is cancelled
+func (i *Indexer) Serve(ctx context.Context) error {
+	return serve.NewHTTPServer(
+		i.mux,
+		i.listenAddress,
+		i.logger.Named("http-server"),
+		serve.WithShutdownHooks(i.jsonrpc.CloseWSConnections),
+	).Serve(ctx)
+}
+
+// Run runs both the chain fetcher, and the HTTP server,
+// until the context is cancelled or either of them fails
+func (i *Indexer) Run(ctx context.Context) error {
+	group, gCtx := errgroup.WithContext(ctx)
+
+	group.Go(func() error {
+		return i.Fetch(gCtx)
+	})
+
+	group.Go(func() error {
+		return i.Serve(gCtx)
+	})
+
+	return group.Wait()
+}
@@ -0,0 +1,104 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientTypes "github.com/gnolang/tx-indexer/client/types"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+type mockClient struct{}
+
+func (m *mockClient) GetLatestBlockNumber() (uint64, error) {
+	return 10, nil
+}
+
+func (m *mockClient) GetBlock(_ uint64) (*core_types.ResultBlock, error) {
+	return nil, nil
+}
+
+func (m *mockClient) GetBlockResults(_ uint64) (*core_types.ResultBlockResults, error) {
+	return nil, nil
+}
+
+func (m *mockClient) CreateBatch() clientTypes.Batch {
+	return nil
+}
+
+// sendRequest sends the JSON-RPC request to the handler, and returns the response
+func sendRequest(t *testing.T, h http.Handler, method string) *spec.BaseJSONResponse {
+	t.Helper()
+
+	body, err := json.Marshal(spec.NewJSONRequest(1, method, []any{}))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response spec.BaseJSONResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+
+	return &response
+}
+
+func TestIndexer_Handler(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	var (
+		customMethod = "customMethod"
+		customResult = "custom result"
+	)
+
+	i := New(db, &mockClient{})
+
+	// Register a custom handler
+	i.JSONRPC().RegisterHandler(
+		customMethod,
+		func(_ *metadata.Metadata, _ []any) (any, *spec.BaseJSONError) {
+			return customResult, nil
+		},
+	)
+
+	t.Run("built-in handler", func(t *testing.T) {
+		t.Parallel()
+
+		response := sendRequest(t, i.Handler(), "getStatus")
+		require.Nil(t, response.Error)
+
+		result, ok := response.Result.(map[string]any)
+		require.True(t, ok)
+
+		assert.EqualValues(t, 10, result["remoteHeight"])
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		t.Parallel()
+
+		response := sendRequest(t, i.Handler(), customMethod)
+		require.Nil(t, response.Error)
+
+		assert.Equal(t, customResult, response.Result)
+	})
+}
@@ -0,0 +1,85 @@
+package indexer
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/alerts"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/serve"
+	"github.com/gnolang/tx-indexer/serve/graph"
+)
+
+type Option func(i *Indexer)
+
+// WithLogger sets the logger to be used
+// with the indexer services
+func WithLogger(logger *zap.Logger) Option {
+	return func(i *Indexer) {
+		i.logger = logger
+	}
+}
+
+// WithListenAddress sets the IP:PORT address
+// the indexer HTTP server listens on
+func WithListenAddress(address string) Option {
+	return func(i *Indexer) {
+		i.listenAddress = address
+	}
+}
+
+// WithRateLimit sets the maximum HTTP requests
+// allowed per minute per IP. Unlimited by default
+func WithRateLimit(limit int) Option {
+	return func(i *Indexer) {
+		i.rateLimit = limit
+	}
+}
+
+// WithWebhooks allows the address watchlist notifications
+// to be delivered to webhooks registered over the JSON-RPC API
+func WithWebhooks() Option {
+	return func(i *Indexer) {
+		i.webhooks = true
+	}
+}
+
+// WithAlertRules sets the alert rules evaluated
+// against every indexed transaction
+func WithAlertRules(rules []*alerts.Rule) Option {
+	return func(i *Indexer) {
+		i.alertRules = rules
+	}
+}
+
+// WithDecoder sets the chain decoder of the txs, applying the decoding rules of the chain upgrades
+// for the served queries, the watchlist and the alert rules. The chain storage decodes them with its own
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(i *Indexer) {
+		i.decoder = decoder
+	}
+}
+
+// WithFetcherOptions sets the options
+// the indexer fetcher is created with
+func WithFetcherOptions(opts ...fetch.Option) Option {
+	return func(i *Indexer) {
+		i.fetcherOpts = append(i.fetcherOpts, opts...)
+	}
+}
+
+// WithGraphOptions sets the options
+// the indexer GraphQL server is created with
+func WithGraphOptions(opts ...graph.Option) Option {
+	return func(i *Indexer) {
+		i.graphOpts = append(i.graphOpts, opts...)
+	}
+}
+
+// WithServeOptions sets the options
+// the indexer JSON-RPC server is created with
+func WithServeOptions(opts ...serve.Option) Option {
+	return func(i *Indexer) {
+		i.serveOpts = append(i.serveOpts, opts...)
+	}
+}
@@ -0,0 +1,19 @@
+package indexer
+
+import (
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Storage is the indexer storage abstraction
+type Storage interface {
+	storage.Storage
+
+	// Size returns the approximate on-disk size of the storage, in bytes
+	Size() (uint64, error)
+}
+
+// Client is the chain (node) client abstraction
+type Client interface {
+	fetch.Client
+}
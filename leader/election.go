@@ -0,0 +1,183 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultRetryInterval is the default interval between
+// the follower attempts to become the leader
+const DefaultRetryInterval = 5 * time.Second
+
+var errNoLeaderAddress = errors.New("leader address not advertised")
+
+// LeadFn runs the leader services, until the context is cancelled
+type LeadFn func(ctx context.Context) error
+
+// FollowFn runs the follower services for the given
+// leader address, until the context is cancelled
+type FollowFn func(ctx context.Context, leader string) error
+
+// Election elects the single instance leading the indexer writes, among the
+// instances sharing the lock file. The leader holds the lock for as long as it
+// runs, while the other instances follow it, and take over once it stops
+type Election struct {
+	logger *zap.Logger
+
+	lockPath      string
+	address       string
+	retryInterval time.Duration
+}
+
+// New creates a new election over the lock file,
+// advertising the given address to the followers once elected
+func New(lockPath, address string, opts ...Option) *Election {
+	e := &Election{
+		logger:        zap.NewNop(),
+		lockPath:      lockPath,
+		address:       address,
+		retryInterval: DefaultRetryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Run campaigns for the leadership until the context is cancelled.
+// While another instance leads, the follower services run for its address,
+// and are stopped before the leader services start
+func (e *Election) Run(ctx context.Context, leadFn LeadFn, followFn FollowFn) error {
+	ticker := time.NewTicker(e.retryInterval)
+	defer ticker.Stop()
+
+	f := &follower{}
+	defer f.stop()
+
+	for {
+		lock, elected, err := tryLock(e.lockPath)
+		if err != nil {
+			return fmt.Errorf("unable to acquire leader lock, %w", err)
+		}
+
+		if elected {
+			f.stop()
+
+			return e.lead(ctx, lock, leadFn)
+		}
+
+		leader, err := readAddress(e.lockPath)
+
+		switch {
+		case err != nil:
+			e.logger.Warn("unable to resolve leader", zap.Error(err))
+		case leader != f.leader:
+			// Follow the current leader
+			f.stop()
+
+			e.logger.Info("following leader", zap.String("leader", leader))
+
+			f.start(ctx, leader, followFn, e.logger)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead advertises the leader address, and runs the leader services
+func (e *Election) lead(ctx context.Context, lock *os.File, leadFn LeadFn) error {
+	// The lock is released on close
+	defer lock.Close()
+
+	if err := writeAddress(lock, e.address); err != nil {
+		return fmt.Errorf("unable to advertise leader address, %w", err)
+	}
+
+	e.logger.Info("elected leader", zap.String("address", e.address))
+
+	defer e.logger.Info("leadership released")
+
+	return leadFn(ctx)
+}
+
+// follower runs the follower services of a single leader
+type follower struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	leader string
+}
+
+func (f *follower) start(ctx context.Context, leader string, followFn FollowFn, logger *zap.Logger) {
+	followCtx, cancelFn := context.WithCancel(ctx)
+
+	f.cancel = cancelFn
+	f.done = make(chan struct{})
+	f.leader = leader
+
+	go func() {
+		defer close(f.done)
+
+		if err := followFn(followCtx, leader); err != nil {
+			logger.Error("unable to follow leader", zap.String("leader", leader), zap.Error(err))
+		}
+	}()
+}
+
+// stop stops the follower services, if any, and waits for them
+func (f *follower) stop() {
+	if f.cancel == nil {
+		return
+	}
+
+	f.cancel()
+	<-f.done
+
+	*f = follower{}
+}
+
+// writeAddress writes the leader address to the lock file
+func writeAddress(lock *os.File, address string) error {
+	if err := lock.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := lock.WriteAt([]byte(address), 0); err != nil {
+		return err
+	}
+
+	return lock.Sync()
+}
+
+// readAddress reads the leader address from the lock file
+func readAddress(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	address, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+
+	if len(address) == 0 {
+		return "", errNoLeaderAddress
+	}
+
+	return strings.TrimSpace(string(address)), nil
+}
@@ -0,0 +1,107 @@
+package leader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElection_Failover(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lockPath = filepath.Join(t.TempDir(), "leader.lock")
+
+		firstCtx, firstCancelFn = context.WithCancel(context.Background())
+		secondCtx, secondCancel = context.WithCancel(context.Background())
+
+		firstElected  = make(chan struct{})
+		secondElected = make(chan struct{})
+		following     = make(chan string, 1)
+		unfollowed    = make(chan struct{})
+
+		firstDone = make(chan error, 1)
+	)
+
+	defer firstCancelFn()
+	defer secondCancel()
+
+	lead := func(elected chan struct{}) LeadFn {
+		return func(ctx context.Context) error {
+			close(elected)
+
+			<-ctx.Done()
+
+			return nil
+		}
+	}
+
+	go func() {
+		firstDone <- New(lockPath, "first:8547", WithRetryInterval(10*time.Millisecond)).Run(
+			firstCtx,
+			lead(firstElected),
+			func(_ context.Context, _ string) error {
+				t.Error("first instance should lead")
+
+				return nil
+			},
+		)
+	}()
+
+	select {
+	case <-firstElected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("first instance not elected")
+	}
+
+	go func() {
+		_ = New(lockPath, "second:8547", WithRetryInterval(10*time.Millisecond)).Run(
+			secondCtx,
+			func(ctx context.Context) error {
+				// Make sure the follower services are stopped before leading
+				select {
+				case <-unfollowed:
+				default:
+					t.Error("follower services still running")
+				}
+
+				return lead(secondElected)(ctx)
+			},
+			func(ctx context.Context, leader string) error {
+				following <- leader
+
+				<-ctx.Done()
+				close(unfollowed)
+
+				return nil
+			},
+		)
+	}()
+
+	// Make sure the second instance follows the leader
+	select {
+	case leader := <-following:
+		assert.Equal(t, "first:8547", leader)
+	case <-time.After(5 * time.Second):
+		t.Fatal("second instance not following")
+	}
+
+	// Make sure the second instance takes over once the leader stops
+	firstCancelFn()
+	require.NoError(t, <-firstDone)
+
+	select {
+	case <-secondElected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("second instance not elected")
+	}
+
+	leader, err := readAddress(lockPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "second:8547", leader)
+}
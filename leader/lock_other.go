@@ -0,0 +1,14 @@
+//go:build !unix
+
+package leader
+
+import (
+	"errors"
+	"os"
+)
+
+var errLockUnsupported = errors.New("leader election is not supported on this platform")
+
+func tryLock(_ string) (*os.File, bool, error) {
+	return nil, false, errLockUnsupported
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package leader
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock tries to acquire the exclusive lock of the file, without blocking.
+// The lock is released once the returned file is closed, or the process exits
+func tryLock(path string) (*os.File, bool, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			// Locked by the leader
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return f, true, nil
+}
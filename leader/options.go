@@ -0,0 +1,25 @@
+package leader
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(e *Election)
+
+// WithLogger sets the logger to be used
+// with the election
+func WithLogger(logger *zap.Logger) Option {
+	return func(e *Election) {
+		e.logger = logger
+	}
+}
+
+// WithRetryInterval sets the interval between
+// the follower attempts to become the leader
+func WithRetryInterval(interval time.Duration) Option {
+	return func(e *Election) {
+		e.retryInterval = interval
+	}
+}
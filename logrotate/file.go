@@ -0,0 +1,159 @@
+// Package logrotate implements the size based rotation of the log files
+package logrotate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// DefaultMaxSize is the default size (in bytes)
+	// of the log file, before it's rotated
+	DefaultMaxSize = 100 << 20 // 100MB
+
+	// DefaultMaxBackups is the default number
+	// of the rotated log files kept
+	DefaultMaxBackups = 5
+)
+
+var _ io.WriteCloser = (*File)(nil)
+
+// File is the log file, rotated once it reaches the max size.
+// The rotated files are suffixed with their index (.1 being the most recent),
+// and only the max backups are kept
+type File struct {
+	file *os.File
+
+	path       string
+	size       int64
+	maxSize    int64
+	maxBackups int
+
+	mux sync.Mutex
+}
+
+// New opens the log file at the path, appending to it if it exists
+func New(path string, opts ...Option) (*File, error) {
+	f := &File{
+		path:       path,
+		maxSize:    DefaultMaxSize,
+		maxBackups: DefaultMaxBackups,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// open opens the log file for appending
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open log file, %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("unable to stat log file, %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+// Write writes the data to the log file,
+// rotating it first if the data would exceed the max size
+func (f *File) Write(p []byte) (int, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// rotate moves the log file to the first backup,
+// shifting the existing backups, and opens a new log file
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("unable to close log file, %w", err)
+	}
+
+	f.file = nil
+
+	// Drop the oldest backup, and shift the rest
+	if err := os.Remove(f.backup(f.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove log backup, %w", err)
+	}
+
+	for index := f.maxBackups - 1; index > 0; index-- {
+		if err := os.Rename(f.backup(index), f.backup(index+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to shift log backup, %w", err)
+		}
+	}
+
+	if f.maxBackups > 0 {
+		if err := os.Rename(f.path, f.backup(1)); err != nil {
+			return fmt.Errorf("unable to back up log file, %w", err)
+		}
+	} else if err := os.Remove(f.path); err != nil {
+		return fmt.Errorf("unable to remove log file, %w", err)
+	}
+
+	return f.open()
+}
+
+// backup returns the path of the backup with the index
+func (f *File) backup(index int) string {
+	return fmt.Sprintf("%s.%d", f.path, index)
+}
+
+// Sync commits the written data to disk
+func (f *File) Sync() error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+
+	return f.file.Sync()
+}
+
+// Close closes the log file
+func (f *File) Close() error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
@@ -0,0 +1,108 @@
+package logrotate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readFile reads the file contents
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestFile_Rotate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "indexer.log")
+
+	f, err := New(path, WithMaxSize(10), WithMaxBackups(2))
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	// Write the lines, each filling up the file
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, f.Sync())
+
+	// Make sure the most recent lines are kept, up to the max backups
+	assert.Equal(t, "line 4\n", readFile(t, path))
+	assert.Equal(t, "line 3\n", readFile(t, path+".1"))
+	assert.Equal(t, "line 2\n", readFile(t, path+".2"))
+
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestFile_Append(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "indexer.log")
+
+	require.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o600))
+
+	f, err := New(path, WithMaxSize(20))
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("new\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, f.Close())
+
+	// Make sure the existing file is appended to
+	assert.Equal(t, "existing\nnew\n", readFile(t, path))
+
+	// Make sure the existing size counts towards the rotation
+	f, err = New(path, WithMaxSize(20))
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	_, err = f.Write([]byte(strings.Repeat("a", 10)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "existing\nnew\n", readFile(t, path+".1"))
+}
+
+func TestFile_NoBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "indexer.log")
+
+	f, err := New(path, WithMaxSize(5), WithMaxBackups(0))
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	for _, line := range []string{"abcd\n", "efgh\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "efgh\n", readFile(t, path))
+	assert.NoFileExists(t, path+".1")
+}
+
+func TestFile_Closed(t *testing.T) {
+	t.Parallel()
+
+	f, err := New(filepath.Join(t.TempDir(), "indexer.log"))
+	require.NoError(t, err)
+
+	require.NoError(t, f.Close())
+
+	_, err = f.Write([]byte("data"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
@@ -0,0 +1,19 @@
+package logrotate
+
+type Option func(f *File)
+
+// WithMaxSize sets the size (in bytes)
+// of the log file, before it's rotated
+func WithMaxSize(size int64) Option {
+	return func(f *File) {
+		f.maxSize = size
+	}
+}
+
+// WithMaxBackups sets the number
+// of the rotated log files kept
+func WithMaxBackups(backups int) Option {
+	return func(f *File) {
+		f.maxBackups = backups
+	}
+}
@@ -0,0 +1,213 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	clientTypes "github.com/gnolang/tx-indexer/client/types"
+)
+
+type (
+	getLatestBlockNumberDelegate func() (uint64, error)
+	getBlockDelegate             func(uint64) (*core_types.ResultBlock, error)
+	getBlockResultsDelegate      func(uint64) (*core_types.ResultBlockResults, error)
+	createBatchDelegate          func() clientTypes.Batch
+)
+
+// Client is the node client (see fetch.Client)
+type Client struct {
+	GetLatestBlockNumberFn getLatestBlockNumberDelegate
+	GetBlockFn             getBlockDelegate
+	GetBlockResultsFn      getBlockResultsDelegate
+	CreateBatchFn          createBatchDelegate
+}
+
+// NewChainClient creates a node client serving the given chain
+// (see GenerateChain), with the blocks ordered by height.
+// The batches are executed against the same chain
+func NewChainClient(blocks []*types.Block, txs []*types.TxResult) *Client {
+	var (
+		byHeight  = make(map[uint64]*types.Block, len(blocks))
+		responses = make(map[uint64][]abci.ResponseDeliverTx, len(blocks))
+		latest    uint64
+	)
+
+	for _, block := range blocks {
+		byHeight[uint64(block.Height)] = block
+		latest = max(latest, uint64(block.Height))
+	}
+
+	for _, tx := range txs {
+		responses[uint64(tx.Height)] = append(responses[uint64(tx.Height)], tx.Response)
+	}
+
+	c := &Client{
+		GetLatestBlockNumberFn: func() (uint64, error) {
+			return latest, nil
+		},
+		GetBlockFn: func(height uint64) (*core_types.ResultBlock, error) {
+			block, ok := byHeight[height]
+			if !ok {
+				return nil, fmt.Errorf("block %d not found", height)
+			}
+
+			return &core_types.ResultBlock{Block: block}, nil
+		},
+		GetBlockResultsFn: func(height uint64) (*core_types.ResultBlockResults, error) {
+			if _, ok := byHeight[height]; !ok {
+				return nil, fmt.Errorf("block %d results not found", height)
+			}
+
+			return &core_types.ResultBlockResults{
+				Height: int64(height),
+				Results: &state.ABCIResponses{
+					DeliverTxs: responses[height],
+				},
+			}, nil
+		},
+	}
+
+	c.CreateBatchFn = func() clientTypes.Batch {
+		return NewClientBatch(c)
+	}
+
+	return c
+}
+
+// GetLatestBlockNumber returns the latest block height from the chain
+func (m *Client) GetLatestBlockNumber() (uint64, error) {
+	if m.GetLatestBlockNumberFn != nil {
+		return m.GetLatestBlockNumberFn()
+	}
+
+	return 0, nil
+}
+
+// GetBlock returns the specified block
+func (m *Client) GetBlock(blockNum uint64) (*core_types.ResultBlock, error) {
+	if m.GetBlockFn != nil {
+		return m.GetBlockFn(blockNum)
+	}
+
+	return nil, nil
+}
+
+// GetBlockResults returns the results of executing the transactions for the specified block
+func (m *Client) GetBlockResults(blockNum uint64) (*core_types.ResultBlockResults, error) {
+	if m.GetBlockResultsFn != nil {
+		return m.GetBlockResultsFn(blockNum)
+	}
+
+	return nil, nil
+}
+
+// CreateBatch creates a new client batch
+func (m *Client) CreateBatch() clientTypes.Batch {
+	if m.CreateBatchFn != nil {
+		return m.CreateBatchFn()
+	}
+
+	return nil
+}
+
+type (
+	addBlockRequestDelegate        func(uint64) error
+	addBlockResultsRequestDelegate func(uint64) error
+	executeDelegate                func(context.Context) ([]any, error)
+	countDelegate                  func() int
+)
+
+var _ clientTypes.Batch = &ClientBatch{}
+
+// ClientBatch is the node client batch
+type ClientBatch struct {
+	AddBlockRequestFn        addBlockRequestDelegate
+	AddBlockResultsRequestFn addBlockResultsRequestDelegate
+	ExecuteFn                executeDelegate
+	CountFn                  countDelegate
+}
+
+// NewClientBatch creates a client batch executing the added requests
+// against the given client, in order
+func NewClientBatch(client *Client) *ClientBatch {
+	requests := make([]func() (any, error), 0)
+
+	return &ClientBatch{
+		AddBlockRequestFn: func(height uint64) error {
+			requests = append(requests, func() (any, error) {
+				return client.GetBlock(height)
+			})
+
+			return nil
+		},
+		AddBlockResultsRequestFn: func(height uint64) error {
+			requests = append(requests, func() (any, error) {
+				return client.GetBlockResults(height)
+			})
+
+			return nil
+		},
+		ExecuteFn: func(ctx context.Context) ([]any, error) {
+			results := make([]any, 0, len(requests))
+
+			for _, request := range requests {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+
+				result, err := request()
+				if err != nil {
+					return nil, err
+				}
+
+				results = append(results, result)
+			}
+
+			return results, nil
+		},
+		CountFn: func() int {
+			return len(requests)
+		},
+	}
+}
+
+// AddBlockRequest adds a new block request (block fetch) to the batch
+func (m *ClientBatch) AddBlockRequest(num uint64) error {
+	if m.AddBlockRequestFn != nil {
+		return m.AddBlockRequestFn(num)
+	}
+
+	return nil
+}
+
+// AddBlockResultsRequest adds a new block results request (block results fetch) to the batch
+func (m *ClientBatch) AddBlockResultsRequest(num uint64) error {
+	if m.AddBlockResultsRequestFn != nil {
+		return m.AddBlockResultsRequestFn(num)
+	}
+
+	return nil
+}
+
+// Execute sends the batch off for processing by the node
+func (m *ClientBatch) Execute(ctx context.Context) ([]any, error) {
+	if m.ExecuteFn != nil {
+		return m.ExecuteFn(ctx)
+	}
+
+	return nil, nil
+}
+
+// Count returns the number of requests in the batch
+func (m *ClientBatch) Count() int {
+	if m.CountFn != nil {
+		return m.CountFn()
+	}
+
+	return 0
+}
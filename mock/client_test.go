@@ -0,0 +1,112 @@
+package mock_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/fetch"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+func TestChainClient_Fetch(t *testing.T) {
+	t.Parallel()
+
+	blocks, txs := mock.GenerateChain(30, 2)
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var signaled atomic.Int64
+
+	f := fetch.New(
+		s,
+		mock.NewChainClient(blocks, txs),
+		&mock.Events{
+			SignalEventFn: func(_ events.Event) {
+				signaled.Add(1)
+			},
+		},
+		fetch.WithMaxChunkSize(10),
+	)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	go func() {
+		for {
+			if latest, err := s.GetLatestHeight(); err == nil && latest == uint64(len(blocks)) {
+				cancelFn()
+
+				return
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	require.NoError(t, f.FetchChainData(ctx))
+
+	// Make sure the generated chain is indexed
+	for _, block := range blocks {
+		saved, err := s.GetBlock(uint64(block.Height))
+		require.NoError(t, err)
+
+		assert.Equal(t, block, saved)
+	}
+
+	for _, tx := range txs {
+		saved, err := s.GetTx(uint64(tx.Height), tx.Index)
+		require.NoError(t, err)
+
+		assert.Equal(t, tx, saved)
+	}
+
+	assert.Positive(t, signaled.Load())
+}
+
+func TestChainClient_UnknownHeight(t *testing.T) {
+	t.Parallel()
+
+	blocks, txs := mock.GenerateChain(5, 1)
+
+	c := mock.NewChainClient(blocks, txs)
+
+	latest, err := c.GetLatestBlockNumber()
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), latest)
+
+	_, err = c.GetBlock(6)
+	assert.Error(t, err)
+
+	_, err = c.GetBlockResults(0)
+	assert.Error(t, err)
+
+	// Make sure the batch requests are executed in order
+	batch := c.CreateBatch()
+
+	require.NoError(t, batch.AddBlockRequest(2))
+	require.NoError(t, batch.AddBlockResultsRequest(2))
+
+	assert.Equal(t, 2, batch.Count())
+
+	results, err := batch.Execute(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	block, err := c.GetBlock(2)
+	require.NoError(t, err)
+
+	assert.Equal(t, block, results[0])
+}
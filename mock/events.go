@@ -0,0 +1,13 @@
+package mock
+
+import "github.com/gnolang/tx-indexer/decode"
+
+// GnoEvent mimics the Gno VM realm event
+type GnoEvent struct {
+	Type    string                  `json:"type"`
+	PkgPath string                  `json:"pkg_path"`
+	Func    string                  `json:"func"`
+	Attrs   []decode.EventAttribute `json:"attrs"`
+}
+
+func (GnoEvent) AssertABCIEvent() {}
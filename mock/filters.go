@@ -23,11 +23,13 @@ func (m *Conn) WriteData(data any) error {
 type (
 	subscribeDelegate          func([]events.Type) *events.Subscription
 	cancelSubscriptionDelegate func(events.SubscriptionID)
+	signalEventDelegate        func(events.Event)
 )
 
 type Events struct {
 	SubscribeFn          subscribeDelegate
 	CancelSubscriptionFn cancelSubscriptionDelegate
+	SignalEventFn        signalEventDelegate
 }
 
 func (m *Events) Subscribe(eventTypes []events.Type) *events.Subscription {
@@ -43,3 +45,9 @@ func (m *Events) CancelSubscription(id events.SubscriptionID) {
 		m.CancelSubscriptionFn(id)
 	}
 }
+
+func (m *Events) SignalEvent(event events.Event) {
+	if m.SignalEventFn != nil {
+		m.SignalEventFn(event)
+	}
+}
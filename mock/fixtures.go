@@ -0,0 +1,109 @@
+package mock
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// FixtureChainID is the chain ID of the generated blocks
+const FixtureChainID = "dev"
+
+// FixtureAddress returns the address of the given fixture account.
+// The generated transfers are sent from account 1 to account 2
+func FixtureAddress(account byte) crypto.Address {
+	return crypto.Address{account}
+}
+
+// GenerateTxs generates the given number of transfer txs,
+// each with a distinct memo
+func GenerateTxs(count int) []*std.Tx {
+	txs := make([]*std.Tx, 0, count)
+
+	for i := 0; i < count; i++ {
+		txs = append(txs, &std.Tx{
+			Msgs: []std.Msg{
+				bank.MsgSend{
+					FromAddress: FixtureAddress(1),
+					ToAddress:   FixtureAddress(2),
+					Amount:      std.NewCoins(std.NewCoin("ugnot", int64(i+1))),
+				},
+			},
+			Fee:  std.NewFee(100_000, std.NewCoin("ugnot", 1_000)),
+			Memo: fmt.Sprintf("tx %d", i),
+		})
+	}
+
+	return txs
+}
+
+// GenerateBlocks generates the blocks of the heights [1, count],
+// each containing the given (Amino encoded) txs
+func GenerateBlocks(count int, txs []*std.Tx) []*types.Block {
+	encoded := make(types.Txs, 0, len(txs))
+
+	for _, tx := range txs {
+		encoded = append(encoded, amino.MustMarshal(tx))
+	}
+
+	blocks := make([]*types.Block, 0, count)
+
+	for height := 1; height <= count; height++ {
+		blocks = append(blocks, &types.Block{
+			Header: types.Header{
+				ChainID:  FixtureChainID,
+				Height:   int64(height),
+				Time:     time.Unix(int64(height), 0).UTC(),
+				NumTxs:   int64(len(encoded)),
+				TotalTxs: int64(height * len(encoded)),
+			},
+			Data: types.Data{
+				Txs: encoded,
+			},
+		})
+	}
+
+	return blocks
+}
+
+// GenerateChain generates the blocks of the heights [1, blockNum], each with
+// the given number of (distinct) transfer txs, along with their tx results, ordered by height
+func GenerateChain(blockNum, txsPerBlock int) ([]*types.Block, []*types.TxResult) {
+	var (
+		blocks = make([]*types.Block, 0, blockNum)
+		txs    = make([]*types.TxResult, 0, blockNum*txsPerBlock)
+	)
+
+	for _, block := range GenerateBlocks(blockNum, nil) {
+		for index, tx := range GenerateTxs(txsPerBlock) {
+			tx.Memo = fmt.Sprintf("tx %d-%d", block.Height, index)
+
+			encoded := amino.MustMarshal(tx)
+
+			block.Txs = append(block.Txs, encoded)
+
+			txs = append(txs, &types.TxResult{
+				Height: block.Height,
+				Index:  uint32(index),
+				Tx:     encoded,
+				Response: abci.ResponseDeliverTx{
+					GasWanted: tx.Fee.GasWanted,
+					GasUsed:   tx.Fee.GasWanted / 2,
+				},
+			})
+		}
+
+		block.NumTxs = int64(txsPerBlock)
+		block.TotalTxs = block.Height * int64(txsPerBlock)
+
+		blocks = append(blocks, block)
+	}
+
+	return blocks, txs
+}
@@ -0,0 +1,309 @@
+// Package mock provides the fake implementations of the indexer dependencies (the node client,
+// the storage and the events manager), along with the block and tx fixtures, for testing
+// the indexer (and the embedding services) without a running node
+package mock
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+var _ storage.Storage = &Storage{}
+
+// Storage is the indexer storage (see storage.Storage). The methods without a delegate
+// behave as an empty storage: the lookups fail with storageErrors.ErrNotFound,
+// the iterators are empty, and the writes are discarded
+type Storage struct {
+	GetLatestSavedHeightFn func() (uint64, error)
+	GetWriteBatchFn        func() storage.Batch
+	GetBlockFn             func(uint64) (*types.Block, error)
+	GetTxFn                func(uint64, uint32) (*types.TxResult, error)
+	GetTxByHashFn          func(string) (*types.TxResult, error)
+	BlockIteratorFn        func(uint64, uint64) (storage.Iterator[*types.Block], error)
+	TxIteratorFn           func(uint64, uint64, uint32, uint32) (storage.Iterator[*types.TxResult], error)
+	TxByAddressIteratorFn  func(string, uint64, uint64) (storage.Iterator[*types.TxResult], error)
+	GetPluginValueFn       func(string, []byte) ([]byte, error)
+	PluginIteratorFn       func(string, []byte, []byte) (storage.Iterator[*storage.KeyValue], error)
+
+	ReverseBlockIteratorFn       func(uint64, uint64) (storage.Iterator[*types.Block], error)
+	ReverseTxIteratorFn          func(uint64, uint64, uint32, uint32) (storage.Iterator[*types.TxResult], error)
+	ReverseTxByAddressIteratorFn func(string, uint64, uint64) (storage.Iterator[*types.TxResult], error)
+	ReversePluginIteratorFn      func(string, []byte, []byte) (storage.Iterator[*storage.KeyValue], error)
+}
+
+func (m *Storage) GetLatestHeight() (uint64, error) {
+	if m.GetLatestSavedHeightFn != nil {
+		return m.GetLatestSavedHeightFn()
+	}
+
+	return 0, nil
+}
+
+// GetBlock fetches the block by its number
+func (m *Storage) GetBlock(blockNum uint64) (*types.Block, error) {
+	if m.GetBlockFn != nil {
+		return m.GetBlockFn(blockNum)
+	}
+
+	return nil, storageErrors.ErrNotFound
+}
+
+// GetTx fetches the tx using block height and transaction index
+func (m *Storage) GetTx(blockNum uint64, index uint32) (*types.TxResult, error) {
+	if m.GetTxFn != nil {
+		return m.GetTxFn(blockNum, index)
+	}
+
+	return nil, storageErrors.ErrNotFound
+}
+
+func (m *Storage) GetTxByHash(txHash string) (*types.TxResult, error) {
+	if m.GetTxByHashFn != nil {
+		return m.GetTxByHashFn(txHash)
+	}
+
+	return nil, storageErrors.ErrNotFound
+}
+
+// BlockIterator iterates over Blocks, limiting the results to be between the provided block numbers
+func (m *Storage) BlockIterator(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error) {
+	if m.BlockIteratorFn != nil {
+		return m.BlockIteratorFn(fromBlockNum, toBlockNum)
+	}
+
+	return &Iterator[*types.Block]{}, nil
+}
+
+// TxIterator iterates over transactions, limiting the results to be between the provided block numbers
+// and transaction indexes
+func (m *Storage) TxIterator(
+	fromBlockNum,
+	toBlockNum uint64,
+	fromTxIndex,
+	toTxIndex uint32,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.TxIteratorFn != nil {
+		return m.TxIteratorFn(fromBlockNum, toBlockNum, fromTxIndex, toTxIndex)
+	}
+
+	return &Iterator[*types.TxResult]{}, nil
+}
+
+// TxByAddressIterator iterates over transactions the address participated in
+func (m *Storage) TxByAddressIterator(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.TxByAddressIteratorFn != nil {
+		return m.TxByAddressIteratorFn(address, fromBlockNum, toBlockNum)
+	}
+
+	return &Iterator[*types.TxResult]{}, nil
+}
+
+// GetPluginValue fetches the value saved by the plugin under the given key
+func (m *Storage) GetPluginValue(plugin string, key []byte) ([]byte, error) {
+	if m.GetPluginValueFn != nil {
+		return m.GetPluginValueFn(plugin, key)
+	}
+
+	return nil, storageErrors.ErrNotFound
+}
+
+// PluginIterator iterates over the plugin key-value pairs
+func (m *Storage) PluginIterator(
+	plugin string,
+	fromKey,
+	toKey []byte,
+) (storage.Iterator[*storage.KeyValue], error) {
+	if m.PluginIteratorFn != nil {
+		return m.PluginIteratorFn(plugin, fromKey, toKey)
+	}
+
+	return &Iterator[*storage.KeyValue]{}, nil
+}
+
+// ReverseBlockIterator iterates over Blocks, newest first
+func (m *Storage) ReverseBlockIterator(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error) {
+	if m.ReverseBlockIteratorFn != nil {
+		return m.ReverseBlockIteratorFn(fromBlockNum, toBlockNum)
+	}
+
+	return &Iterator[*types.Block]{}, nil
+}
+
+// ReverseTxIterator iterates over transactions, newest first
+func (m *Storage) ReverseTxIterator(
+	fromBlockNum,
+	toBlockNum uint64,
+	fromTxIndex,
+	toTxIndex uint32,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.ReverseTxIteratorFn != nil {
+		return m.ReverseTxIteratorFn(fromBlockNum, toBlockNum, fromTxIndex, toTxIndex)
+	}
+
+	return &Iterator[*types.TxResult]{}, nil
+}
+
+// ReverseTxByAddressIterator iterates over transactions the address participated in, newest first
+func (m *Storage) ReverseTxByAddressIterator(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.ReverseTxByAddressIteratorFn != nil {
+		return m.ReverseTxByAddressIteratorFn(address, fromBlockNum, toBlockNum)
+	}
+
+	return &Iterator[*types.TxResult]{}, nil
+}
+
+// ReversePluginIterator iterates over the plugin key-value pairs, last key first
+func (m *Storage) ReversePluginIterator(
+	plugin string,
+	fromKey,
+	toKey []byte,
+) (storage.Iterator[*storage.KeyValue], error) {
+	if m.ReversePluginIteratorFn != nil {
+		return m.ReversePluginIteratorFn(plugin, fromKey, toKey)
+	}
+
+	return &Iterator[*storage.KeyValue]{}, nil
+}
+
+// WriteBatch provides a batch intended to do a write action that
+// can be cancelled or committed all at the same time
+func (m *Storage) WriteBatch() storage.Batch {
+	if m.GetWriteBatchFn != nil {
+		return m.GetWriteBatchFn()
+	}
+
+	return &WriteBatch{}
+}
+
+func (m *Storage) Close() error {
+	return nil
+}
+
+type WriteBatch struct {
+	SetLatestHeightFn func(uint64) error
+	SetBlockFn        func(*types.Block) error
+	SetTxFn           func(*types.TxResult) error
+
+	SetPluginValueFn    func(string, []byte, []byte) error
+	GetPluginValueFn    func(string, []byte) ([]byte, error)
+	DeletePluginValueFn func(string, []byte) error
+
+	CommitFn func() error
+}
+
+// SetLatestHeight saves the latest block height to the storage
+func (mb *WriteBatch) SetLatestHeight(h uint64) error {
+	if mb.SetLatestHeightFn != nil {
+		return mb.SetLatestHeightFn(h)
+	}
+
+	return nil
+}
+
+// SetBlock saves the block to the permanent storage
+func (mb *WriteBatch) SetBlock(block *types.Block) error {
+	if mb.SetBlockFn != nil {
+		return mb.SetBlockFn(block)
+	}
+
+	return nil
+}
+
+// SetTx saves the transaction to the permanent storage
+func (mb *WriteBatch) SetTx(tx *types.TxResult) error {
+	if mb.SetTxFn != nil {
+		return mb.SetTxFn(tx)
+	}
+
+	return nil
+}
+
+// SetPluginValue saves the value under the plugin's own namespace
+func (mb *WriteBatch) SetPluginValue(plugin string, key, value []byte) error {
+	if mb.SetPluginValueFn != nil {
+		return mb.SetPluginValueFn(plugin, key, value)
+	}
+
+	return nil
+}
+
+// GetPluginValue fetches the value from the plugin's own namespace
+func (mb *WriteBatch) GetPluginValue(plugin string, key []byte) ([]byte, error) {
+	if mb.GetPluginValueFn != nil {
+		return mb.GetPluginValueFn(plugin, key)
+	}
+
+	return nil, storageErrors.ErrNotFound
+}
+
+// DeletePluginValue removes the value from the plugin's own namespace
+func (mb *WriteBatch) DeletePluginValue(plugin string, key []byte) error {
+	if mb.DeletePluginValueFn != nil {
+		return mb.DeletePluginValueFn(plugin, key)
+	}
+
+	return nil
+}
+
+// Commit stores all the provided info on the storage and make
+// it available for other storage readers
+func (mb *WriteBatch) Commit() error {
+	if mb.CommitFn != nil {
+		return mb.CommitFn()
+	}
+
+	return nil
+}
+
+// Rollback rollbacks the operation not persisting the provided changes
+func (mb *WriteBatch) Rollback() error {
+	return nil
+}
+
+// Iterator is the storage iterator over the given values
+type Iterator[T any] struct {
+	Values []T
+
+	current T
+}
+
+// NewIterator creates the storage iterator over the values
+func NewIterator[T any](values ...T) *Iterator[T] {
+	return &Iterator[T]{Values: values}
+}
+
+// Next moves to the next value, returning a flag indicating if there is one
+func (i *Iterator[T]) Next() bool {
+	if len(i.Values) == 0 {
+		return false
+	}
+
+	i.current, i.Values = i.Values[0], i.Values[1:]
+
+	return true
+}
+
+// Value returns the current value
+func (i *Iterator[T]) Value() (T, error) {
+	return i.current, nil
+}
+
+// Error returns the iteration error, if any
+func (i *Iterator[T]) Error() error {
+	return nil
+}
+
+// Close closes the iterator
+func (i *Iterator[T]) Close() error {
+	return nil
+}
@@ -0,0 +1,72 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+func TestStorage_Defaults(t *testing.T) {
+	t.Parallel()
+
+	s := &mock.Storage{}
+
+	// Make sure the storage without delegates is empty
+	_, err := s.GetBlock(1)
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	_, err = s.GetPluginValue("plugin", []byte("key"))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	txs, err := s.TxByAddressIterator("address", 0, 10)
+	require.NoError(t, err)
+
+	assert.False(t, txs.Next())
+	assert.NoError(t, txs.Close())
+
+	kvs, err := s.PluginIterator("plugin", nil, nil)
+	require.NoError(t, err)
+
+	assert.False(t, kvs.Next())
+	assert.NoError(t, kvs.Close())
+
+	require.NoError(t, s.WriteBatch().Commit())
+}
+
+func TestStorage_TxByAddressIterator(t *testing.T) {
+	t.Parallel()
+
+	_, txs := mock.GenerateChain(3, 2)
+
+	s := &mock.Storage{
+		TxByAddressIteratorFn: func(_ string, _, _ uint64) (storage.Iterator[*types.TxResult], error) {
+			return mock.NewIterator(txs...), nil
+		},
+	}
+
+	it, err := s.TxByAddressIterator("address", 0, 10)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, it.Close())
+	}()
+
+	iterated := make([]*types.TxResult, 0, len(txs))
+
+	for it.Next() {
+		tx, err := it.Value()
+		require.NoError(t, err)
+
+		iterated = append(iterated, tx)
+	}
+
+	require.NoError(t, it.Error())
+
+	assert.Equal(t, txs, iterated)
+}
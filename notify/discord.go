@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+var _ Notifier = &Discord{}
+
+type (
+	// discordMessage is the Discord webhook message
+	discordMessage struct {
+		Embeds []*discordEmbed `json:"embeds"`
+	}
+
+	// discordEmbed is the Discord message embed
+	discordEmbed struct {
+		Title       string          `json:"title"`
+		Description string          `json:"description,omitempty"`
+		Fields      []*discordField `json:"fields"`
+	}
+
+	// discordField is the Discord message embed field
+	discordField struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+)
+
+// Discord delivers the notifications as messages to a Discord webhook
+type Discord struct {
+	webhook *Webhook
+}
+
+// NewDiscord creates a new Discord notifier for the webhook URL
+func NewDiscord(url string, opts ...WebhookOption) *Discord {
+	return &Discord{
+		webhook: NewWebhook(url, opts...),
+	}
+}
+
+func (d *Discord) Notify(ctx context.Context, notification *Notification) error {
+	return d.webhook.post(ctx, formatDiscord(notification))
+}
+
+// formatDiscord formats the notification as a Discord message embed
+func formatDiscord(n *Notification) *discordMessage {
+	embed := &discordEmbed{
+		Title:       n.title(),
+		Description: n.Message,
+		Fields: []*discordField{
+			{
+				Name:  "Transaction",
+				Value: fmt.Sprintf("`%s` (%s)", n.TxHash, n.position()),
+			},
+		},
+	}
+
+	if len(n.Addresses) != 0 {
+		embed.Fields = append(embed.Fields, &discordField{
+			Name:  "Addresses",
+			Value: n.addressList(),
+		})
+	}
+
+	return &discordMessage{
+		Embeds: []*discordEmbed{embed},
+	}
+}
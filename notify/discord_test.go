@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscord_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		received *discordMessage
+
+		addresses = make([]string, 0, maxFormattedAddresses+2)
+	)
+
+	for i := 0; i < maxFormattedAddresses+2; i++ {
+		addresses = append(addresses, fmt.Sprintf("g1address%d", i))
+	}
+
+	notification := &Notification{
+		Topic:     "alerts",
+		Source:    "large-transfers",
+		Message:   "large transfer",
+		Height:    10,
+		Index:     1,
+		TxHash:    "hash",
+		Addresses: addresses,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	require.NoError(t, NewDiscord(server.URL).Notify(context.Background(), notification))
+
+	require.Len(t, received.Embeds, 1)
+
+	embed := received.Embeds[0]
+
+	assert.Equal(t, "[alerts] large-transfers", embed.Title)
+	assert.Equal(t, "large transfer", embed.Description)
+
+	require.Len(t, embed.Fields, 2)
+
+	assert.Equal(t, "`hash` (height 10, index 1)", embed.Fields[0].Value)
+	assert.Contains(t, embed.Fields[1].Value, "g1address9")
+	assert.NotContains(t, embed.Fields[1].Value, "g1address10")
+	assert.Contains(t, embed.Fields[1].Value, "and 2 more")
+}
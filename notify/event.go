@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/gnolang/tx-indexer/events"
+)
+
+// NotificationEvent is the event type of the notifications published to the event manager
+const NotificationEvent events.Type = "notification"
+
+// Event wraps a notification published to the event manager
+type Event struct {
+	Notification *Notification
+}
+
+func (e *Event) GetType() events.Type {
+	return NotificationEvent
+}
+
+func (e *Event) GetData() any {
+	return e.Notification
+}
+
+// Signaler is the event manager abstraction
+type Signaler interface {
+	// SignalEvent signals a new event to the event manager
+	SignalEvent(events.Event)
+}
+
+var _ Notifier = &Publisher{}
+
+// Publisher publishes the notifications as events to the event manager,
+// so they can be delivered to subscribers (ex. WS subscriptions)
+type Publisher struct {
+	signaler Signaler
+}
+
+// NewPublisher creates a new event publisher notifier
+func NewPublisher(signaler Signaler) *Publisher {
+	return &Publisher{
+		signaler: signaler,
+	}
+}
+
+func (p *Publisher) Notify(_ context.Context, notification *Notification) error {
+	p.signaler.SignalEvent(&Event{Notification: notification})
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/events"
+)
+
+// mockSignaler is a mock event manager, capturing the signaled events
+type mockSignaler struct {
+	events []events.Event
+}
+
+func (m *mockSignaler) SignalEvent(event events.Event) {
+	m.events = append(m.events, event)
+}
+
+func TestPublisher_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		signaler     = &mockSignaler{}
+		notification = &Notification{
+			Topic:   "alerts",
+			Source:  "large-transfers",
+			Message: "large transfer",
+			Height:  10,
+		}
+	)
+
+	require.NoError(t, NewPublisher(signaler).Notify(context.Background(), notification))
+
+	require.Len(t, signaler.events, 1)
+
+	assert.Equal(t, NotificationEvent, signaler.events[0].GetType())
+	assert.Equal(t, notification, signaler.events[0].GetData())
+}
@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxFormattedAddresses is the maximum number of addresses listed in a formatted notification
+const maxFormattedAddresses = 10
+
+// title returns the formatted notification title, such as [alerts] large-transfers
+func (n *Notification) title() string {
+	if n.Source == "" {
+		return fmt.Sprintf("[%s]", n.Topic)
+	}
+
+	return fmt.Sprintf("[%s] %s", n.Topic, n.Source)
+}
+
+// position returns the formatted transaction position
+func (n *Notification) position() string {
+	return fmt.Sprintf("height %d, index %d", n.Height, n.Index)
+}
+
+// addressList returns the formatted notification addresses,
+// listing at most maxFormattedAddresses addresses
+func (n *Notification) addressList() string {
+	if len(n.Addresses) <= maxFormattedAddresses {
+		return strings.Join(n.Addresses, ", ")
+	}
+
+	return fmt.Sprintf(
+		"%s and %d more",
+		strings.Join(n.Addresses[:maxFormattedAddresses], ", "),
+		len(n.Addresses)-maxFormattedAddresses,
+	)
+}
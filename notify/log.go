@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+var _ Notifier = &Log{}
+
+// Log writes the notifications to the logger
+type Log struct {
+	logger *zap.Logger
+}
+
+// NewLog creates a new log notifier
+func NewLog(logger *zap.Logger) *Log {
+	return &Log{
+		logger: logger,
+	}
+}
+
+func (l *Log) Notify(_ context.Context, notification *Notification) error {
+	l.logger.Warn(
+		notification.Message,
+		zap.String("topic", notification.Topic),
+		zap.String("source", notification.Source),
+		zap.Int64("height", notification.Height),
+		zap.Uint32("index", notification.Index),
+		zap.String("tx", notification.TxHash),
+		zap.Strings("addresses", notification.Addresses),
+	)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+var _ Notifier = &Slack{}
+
+// slackMessage is the Slack incoming webhook message
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Slack delivers the notifications as messages to a Slack incoming webhook
+type Slack struct {
+	webhook *Webhook
+}
+
+// NewSlack creates a new Slack notifier for the incoming webhook URL
+func NewSlack(url string, opts ...WebhookOption) *Slack {
+	return &Slack{
+		webhook: NewWebhook(url, opts...),
+	}
+}
+
+func (s *Slack) Notify(ctx context.Context, notification *Notification) error {
+	return s.webhook.post(ctx, &slackMessage{
+		Text: formatSlack(notification),
+	})
+}
+
+// formatSlack formats the notification as a Slack (mrkdwn) message
+func formatSlack(n *Notification) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "*%s*\n", n.title())
+
+	if n.Message != "" {
+		fmt.Fprintf(&b, "%s\n", n.Message)
+	}
+
+	fmt.Fprintf(&b, "Transaction `%s` (%s)", n.TxHash, n.position())
+
+	if len(n.Addresses) != 0 {
+		fmt.Fprintf(&b, "\nAddresses: %s", n.addressList())
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlack_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		received map[string]string
+
+		notification = &Notification{
+			Topic:     "alerts",
+			Source:    "large-transfers",
+			Message:   "large transfer",
+			Height:    10,
+			Index:     1,
+			TxHash:    "hash",
+			Addresses: []string{"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"},
+		}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	require.NoError(t, NewSlack(server.URL).Notify(context.Background(), notification))
+
+	assert.Equal(
+		t,
+		"*[alerts] large-transfers*\n"+
+			"large transfer\n"+
+			"Transaction `hash` (height 10, index 1)\n"+
+			"Addresses: g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5",
+		received["text"],
+	)
+}
@@ -0,0 +1,41 @@
+// Package notify delivers transaction notifications to external sinks
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClosed is returned by notifiers that can't deliver notifications anymore
+// (ex. the WS connection is closed), and should be removed
+var ErrClosed = errors.New("notifier closed")
+
+// Notifier delivers notifications to an external sink
+type Notifier interface {
+	// Notify delivers the notification
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+// Notification is a single transaction notification
+type Notification struct {
+	// Topic is the notification topic (ex. the watchlist)
+	Topic string `json:"topic"`
+
+	// Source is the ID of the topic item the notification originates from (ex. the watch ID)
+	Source string `json:"source,omitempty"`
+
+	// Message is the human-readable notification message
+	Message string `json:"message"`
+
+	// Height is the height of the transaction block
+	Height int64 `json:"height"`
+
+	// Index is the index of the transaction in the block
+	Index uint32 `json:"index"`
+
+	// TxHash is the base64 hash of the transaction
+	TxHash string `json:"txHash"`
+
+	// Addresses are the addresses the notification concerns, if any
+	Addresses []string `json:"addresses,omitempty"`
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// DefaultWebhookTimeout is the default timeout for delivering a webhook notification
+const DefaultWebhookTimeout = 10 * time.Second
+
+// ErrNonPublicAddress is the error of the webhook dialing a non-public address
+var ErrNonPublicAddress = errors.New("webhook address is not public")
+
+var _ Notifier = &Webhook{}
+
+// Webhook delivers the notifications as JSON POST requests to the URL
+type Webhook struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhook creates a new webhook notifier for the URL
+func NewWebhook(url string, opts ...WebhookOption) *Webhook {
+	w := &Webhook{
+		client: &http.Client{
+			Timeout: DefaultWebhookTimeout,
+		},
+		url: url,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+func (w *Webhook) Notify(ctx context.Context, notification *Notification) error {
+	return w.post(ctx, notification)
+}
+
+// post sends the payload as a JSON POST request to the webhook URL
+func (w *Webhook) post(ctx context.Context, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to encode webhook payload, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create webhook request, %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to deliver webhook, %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type WebhookOption func(w *Webhook)
+
+// WithHTTPClient sets the HTTP client used for
+// delivering the webhook notifications
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.client = client
+	}
+}
+
+// WithPublicAddressesOnly restricts the webhook to the public addresses, rejecting
+// the loopback, link-local and private addresses when dialing (after the host is resolved).
+// It replaces the HTTP client transport, and the environment proxy is not used
+func WithPublicAddressesOnly() WebhookOption {
+	return func(w *Webhook) {
+		dialer := &net.Dialer{
+			Timeout: DefaultWebhookTimeout,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+
+				if ip := net.ParseIP(host); ip == nil || !IsPublicAddress(ip) {
+					return fmt.Errorf("%w, %s", ErrNonPublicAddress, host)
+				}
+
+				return nil
+			},
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+
+		w.client = &http.Client{
+			Timeout:   w.client.Timeout,
+			Transport: transport,
+		}
+	}
+}
+
+// IsPublicAddress returns a flag indicating if the IP is a public unicast address,
+// and not a loopback, link-local, private, unspecified or multicast address
+func IsPublicAddress(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("notification delivered", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			received *Notification
+
+			notification = &Notification{
+				Topic:     "watchlist",
+				Message:   "watched address in transaction",
+				Height:    10,
+				TxHash:    "hash",
+				Addresses: []string{"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"},
+			}
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		require.NoError(t, NewWebhook(server.URL).Notify(context.Background(), notification))
+
+		assert.Equal(t, notification, received)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		err := NewWebhook(server.URL).Notify(context.Background(), &Notification{})
+		assert.ErrorContains(t, err, "500")
+	})
+
+	t.Run("non-public address", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		// Make sure the loopback test server is not dialed
+		err := NewWebhook(server.URL, WithPublicAddressesOnly()).Notify(context.Background(), &Notification{})
+		assert.ErrorIs(t, err, ErrNonPublicAddress)
+	})
+}
+
+func TestWebhook_IsPublicAddress(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, testCase := range testTable {
+		assert.Equal(t, testCase.public, IsPublicAddress(net.ParseIP(testCase.ip)), testCase.ip)
+	}
+}
@@ -0,0 +1,60 @@
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+var _ Store = &Dir{}
+
+// Dir stores the objects as files in a local directory,
+// with the key as the relative file path
+type Dir struct {
+	path string
+}
+
+// NewDir creates a new object store in the directory
+func NewDir(path string) *Dir {
+	return &Dir{
+		path: path,
+	}
+}
+
+func (d *Dir) Put(_ context.Context, key string, data []byte) error {
+	path := filepath.Join(d.path, filepath.FromSlash(key))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create object directory, %w", err)
+	}
+
+	// Write to a temporary file first, so the object is never partially written
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write object, %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("unable to save object, %w", err)
+	}
+
+	return nil
+}
+
+func (d *Dir) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(d.path, filepath.FromSlash(key)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to open object, %w", err)
+	}
+
+	return f, nil
+}
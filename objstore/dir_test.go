@@ -0,0 +1,33 @@
+package objstore
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir_PutGet(t *testing.T) {
+	t.Parallel()
+
+	d := NewDir(t.TempDir())
+
+	require.NoError(t, d.Put(context.Background(), "segments/1-10.gz", []byte("segment")))
+
+	// Make sure the objects are replaced
+	require.NoError(t, d.Put(context.Background(), "segments/1-10.gz", []byte("replaced")))
+
+	r, err := d.Get(context.Background(), "segments/1-10.gz")
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	assert.Equal(t, []byte("replaced"), data)
+
+	_, err = d.Get(context.Background(), "segments/11-20.gz")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,38 @@
+package objstore
+
+import (
+	"net/http"
+)
+
+type S3Option func(s *S3)
+
+// WithS3HTTPClient sets the HTTP client used
+// for the S3 requests
+func WithS3HTTPClient(client *http.Client) S3Option {
+	return func(s *S3) {
+		s.client = client
+	}
+}
+
+// WithS3Endpoint sets the S3 API endpoint,
+// for the S3 compatible object stores
+func WithS3Endpoint(endpoint string) S3Option {
+	return func(s *S3) {
+		s.endpoint = endpoint
+	}
+}
+
+// WithS3Region sets the bucket region
+func WithS3Region(region string) S3Option {
+	return func(s *S3) {
+		s.region = region
+	}
+}
+
+// WithS3SessionToken sets the session token
+// of the temporary access key pair
+func WithS3SessionToken(token string) S3Option {
+	return func(s *S3) {
+		s.sessionToken = token
+	}
+}
@@ -0,0 +1,237 @@
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultS3Endpoint is the default S3 API endpoint
+	DefaultS3Endpoint = "https://s3.amazonaws.com"
+
+	// DefaultS3Region is the default bucket region
+	DefaultS3Region = "us-east-1"
+
+	// DefaultS3Timeout is the default timeout for a single S3 request
+	DefaultS3Timeout = 5 * time.Minute
+)
+
+// The environment variables of the S3 access key, read by the S3 store users
+const (
+	EnvS3AccessKey    = "AWS_ACCESS_KEY_ID"
+	EnvS3SecretKey    = "AWS_SECRET_ACCESS_KEY"
+	EnvS3SessionToken = "AWS_SESSION_TOKEN"
+)
+
+// The S3 signature (version 4) constants
+const (
+	signAlgorithm = "AWS4-HMAC-SHA256"
+	signService   = "s3"
+	signRequest   = "aws4_request"
+
+	amzDateFormat = "20060102T150405Z"
+)
+
+var _ Store = &S3{}
+
+// S3 stores the objects in an S3 compatible bucket, using path-style requests
+// signed with the access key (AWS signature version 4). GCS buckets are supported
+// through its XML API endpoint (https://storage.googleapis.com), with HMAC keys
+type S3 struct {
+	client *http.Client
+	now    func() time.Time
+
+	endpoint string
+	bucket   string
+	region   string
+
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// NewS3 creates a new object store in the S3 bucket, using the access key pair
+func NewS3(bucket, accessKey, secretKey string, opts ...S3Option) *S3 {
+	s := &S3{
+		client: &http.Client{
+			Timeout: DefaultS3Timeout,
+		},
+		now:       time.Now,
+		endpoint:  DefaultS3Endpoint,
+		bucket:    bucket,
+		region:    DefaultS3Region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *S3) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return checkResponse(resp)
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+
+		return nil, ErrNotFound
+	}
+
+	if err := checkResponse(resp); err != nil {
+		_ = resp.Body.Close()
+
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// do sends the signed request for the object
+func (s *S3) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	u, err := url.Parse(fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.endpoint, "/"), s.bucket, key))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse object URL, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create S3 request, %w", err)
+	}
+
+	s.sign(req, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send S3 request, %w", err)
+	}
+
+	return resp, nil
+}
+
+// sign signs the request with the access key (AWS signature version 4)
+func (s *S3) sign(req *http.Request, body []byte) {
+	var (
+		now         = s.now().UTC()
+		amzDate     = now.Format(amzDateFormat)
+		date        = now.Format("20060102")
+		payloadHash = sha256Hex(body)
+		scope       = strings.Join([]string{date, s.region, signService, signRequest}, "/")
+	)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	// Sign the host and the amz headers
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, signService)
+	key = hmacSHA256(key, signRequest)
+
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			signAlgorithm,
+			s.accessKey,
+			scope,
+			signedHeaders,
+			hex.EncodeToString(hmacSHA256(key, stringToSign)),
+		),
+	)
+}
+
+// checkResponse checks the S3 response status
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return fmt.Errorf("unexpected S3 response status %d, %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,21 @@
+// Package objstore stores the indexer archives and extracts as objects,
+// in a local directory or an S3 compatible bucket (AWS S3, GCS, MinIO)
+package objstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when the object doesn't exist
+var ErrNotFound = errors.New("object not found")
+
+// Store is the object storage
+type Store interface {
+	// Put saves the object under the key, replacing the existing one
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get opens the object under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
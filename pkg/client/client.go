@@ -0,0 +1,268 @@
+// Package client is the Go SDK of the indexer API, wrapping the JSON-RPC (HTTP) methods
+// and the WS subscriptions, which resubscribe whenever the connection is lost
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/handlers/status"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// DefaultReconnectInterval is the default initial wait between the subscription reconnects
+const DefaultReconnectInterval = time.Second
+
+// ErrNotFound is returned when the queried block or tx is not indexed
+var ErrNotFound = errors.New("not found in the indexer")
+
+// Error is the JSON-RPC error returned by the indexer,
+// with its (spec) error code and machine-readable data
+type Error struct {
+	Data    *spec.ErrorData
+	Message string
+	Code    int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// Is matches the not found and not synced errors with ErrNotFound
+func (e *Error) Is(target error) bool {
+	return target == ErrNotFound && (e.Code == spec.NotFoundErrorCode || e.Code == spec.NotSyncedErrorCode)
+}
+
+// Client is the indexer API client
+type Client struct {
+	httpClient *http.Client
+	logger     *zap.Logger
+
+	url   string
+	wsURL string
+
+	reconnectInterval time.Duration
+
+	requestID atomic.Uint64
+}
+
+// New creates a new indexer API client of the indexer JSON-RPC (HTTP) URL.
+// The WS URL is derived from it, unless set
+func New(rpcURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid indexer URL, %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http":
+		parsed.Scheme = "ws"
+	case "https":
+		parsed.Scheme = "wss"
+	default:
+		return nil, fmt.Errorf("invalid indexer URL scheme %q", parsed.Scheme)
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/ws"
+
+	c := &Client{
+		httpClient:        http.DefaultClient,
+		logger:            zap.NewNop(),
+		url:               rpcURL,
+		wsURL:             parsed.String(),
+		reconnectInterval: DefaultReconnectInterval,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Call executes the JSON-RPC method with the given params, decoding the result into the result value.
+// The JSON-RPC errors are returned as *Error
+func (c *Client) Call(ctx context.Context, method string, params []any, result any) error {
+	if params == nil {
+		params = []any{}
+	}
+
+	body, err := json.Marshal(spec.NewJSONRequest(uint(c.requestID.Add(1)), method, params))
+	if err != nil {
+		return fmt.Errorf("unable to marshal request, %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to create request, %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request, %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	// The rate limited responses carry the JSON-RPC error
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("unexpected response status, %s", resp.Status)
+	}
+
+	var response struct {
+		Error  *spec.BaseJSONError `json:"error"`
+		Result json.RawMessage     `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("unable to decode response, %w", err)
+	}
+
+	if response.Error != nil {
+		return &Error{
+			Data:    response.Error.Data,
+			Message: response.Error.Message,
+			Code:    response.Error.Code,
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("unable to decode result, %w", err)
+	}
+
+	return nil
+}
+
+// GetBlock fetches the indexed block of the height
+func (c *Client) GetBlock(ctx context.Context, height uint64) (*types.Block, error) {
+	var block types.Block
+
+	if err := c.callEncoded(ctx, "getBlock", []any{strconv.FormatUint(height, 10)}, &block); err != nil {
+		return nil, err
+	}
+
+	return &block, nil
+}
+
+// GetTxResult fetches the indexed tx result of the height and index
+func (c *Client) GetTxResult(ctx context.Context, height uint64, index uint32) (*types.TxResult, error) {
+	var tx types.TxResult
+
+	params := []any{strconv.FormatUint(height, 10), strconv.FormatUint(uint64(index), 10)}
+
+	if err := c.callEncoded(ctx, "getTxResult", params, &tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// GetTxResultByHash fetches the indexed tx result of the (base64) tx hash
+func (c *Client) GetTxResultByHash(ctx context.Context, hash string) (*types.TxResult, error) {
+	var tx types.TxResult
+
+	if err := c.callEncoded(ctx, "getTxResultByHash", []any{hash}, &tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// GetTxsByAddress fetches the tx results the address participated in, in the height range [from, to),
+// following the page cursors until all the tx results are fetched. A 0 range end means the latest height
+func (c *Client) GetTxsByAddress(ctx context.Context, address string, from, to uint64) ([]*types.TxResult, error) {
+	var (
+		txs    = make([]*types.TxResult, 0)
+		cursor string
+	)
+
+	for {
+		var page struct {
+			Cursor string   `json:"cursor"`
+			Txs    []string `json:"txs"`
+		}
+
+		params := []any{address, strconv.FormatUint(from, 10), strconv.FormatUint(to, 10), nil, cursor}
+
+		if err := c.Call(ctx, "getTxsByAddress", params, &page); err != nil {
+			return nil, err
+		}
+
+		for _, e := range page.Txs {
+			var tx types.TxResult
+
+			if err := decodeValue(e, &tx); err != nil {
+				return nil, fmt.Errorf("unable to decode tx result, %w", err)
+			}
+
+			txs = append(txs, &tx)
+		}
+
+		if page.Cursor == "" {
+			return txs, nil
+		}
+
+		cursor = page.Cursor
+	}
+}
+
+// GetStatus fetches the indexer status
+func (c *Client) GetStatus(ctx context.Context) (*status.Status, error) {
+	var s status.Status
+
+	if err := c.Call(ctx, "getStatus", nil, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// callEncoded executes the JSON-RPC method, decoding the (base64, Amino encoded) result.
+// The empty results are returned as ErrNotFound
+func (c *Client) callEncoded(ctx context.Context, method string, params []any, value any) error {
+	var encoded *string
+
+	if err := c.Call(ctx, method, params, &encoded); err != nil {
+		return err
+	}
+
+	if encoded == nil {
+		return ErrNotFound
+	}
+
+	if err := decodeValue(*encoded, value); err != nil {
+		return fmt.Errorf("unable to decode %s result, %w", method, err)
+	}
+
+	return nil
+}
+
+// decodeValue decodes the base64, Amino encoded value
+func decodeValue(encoded string, value any) error {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	return amino.Unmarshal(raw, value)
+}
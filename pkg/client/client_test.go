@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestNew_URL(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		url   string
+		wsURL string
+	}{
+		{"http://127.0.0.1:8546", "ws://127.0.0.1:8546/ws"},
+		{"https://indexer.example/", "wss://indexer.example/ws"},
+		{"http://127.0.0.1:8546?chain=test", "ws://127.0.0.1:8546/ws?chain=test"},
+	}
+
+	for _, testCase := range testTable {
+		c, err := New(testCase.url)
+		require.NoError(t, err)
+
+		assert.Equal(t, testCase.wsURL, c.wsURL)
+	}
+
+	_, err := New("ws://127.0.0.1:8546")
+	assert.Error(t, err)
+}
+
+func TestClient_Queries(t *testing.T) {
+	t.Parallel()
+
+	blocks, txs := mock.GenerateChain(3, 2)
+
+	encodeValue := func(value any) any {
+		encoded, err := encode.PrepareValue(value)
+		require.NoError(t, err)
+
+		return encoded
+	}
+
+	c := newMockIndexer(t, &mockIndexer{
+		handleFn: func(req *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+			switch req.Method {
+			case "getBlock":
+				switch req.Params[0] {
+				case "2":
+					return encodeValue(blocks[1]), nil
+				case "100":
+					return nil, spec.GenerateNotSyncedError(100, uint64(len(blocks)))
+				}
+
+				return nil, nil
+			case "getTxResult":
+				return encodeValue(txs[1]), nil
+			case "getTxResultByHash":
+				return encodeValue(txs[2]), nil
+			case "getTxsByAddress":
+				// The tx results are split into pages
+				if req.Params[4] == "" {
+					return map[string]any{"txs": []any{encodeValue(txs[0])}, "cursor": "next"}, nil
+				}
+
+				return map[string]any{"txs": []any{encodeValue(txs[1])}}, nil
+			default:
+				return nil, spec.NewJSONError("method not found", spec.MethodNotFoundErrorCode)
+			}
+		},
+	})
+
+	ctx := context.Background()
+
+	block, err := c.GetBlock(ctx, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, blocks[1].Header, block.Header)
+
+	// Make sure the missing values are not found
+	_, err = c.GetBlock(ctx, 10)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = c.GetBlock(ctx, 100)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	tx, err := c.GetTxResult(ctx, 1, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, txs[1], tx)
+
+	tx, err = c.GetTxResultByHash(ctx, "hash")
+	require.NoError(t, err)
+
+	assert.Equal(t, txs[2], tx)
+
+	addressTxs, err := c.GetTxsByAddress(ctx, mock.FixtureAddress(1).String(), 0, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*types.TxResult{txs[0], txs[1]}, addressTxs)
+
+	// Make sure the JSON-RPC errors are returned
+	_, err = c.GetStatus(ctx)
+
+	var rpcErr *Error
+
+	require.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, spec.MethodNotFoundErrorCode, rpcErr.Code)
+	assert.Equal(t, spec.ReasonMethodNotFound, rpcErr.Data.Reason)
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+type (
+	handleDelegate    func(*spec.BaseJSONRequest) (any, *spec.BaseJSONError)
+	subscribeDelegate func(conn *websocket.Conn, connNum int64)
+)
+
+// mockIndexer is the indexer JSON-RPC server, answering the HTTP requests
+// with the handler, and the WS subscriptions with the subscribe handler
+type mockIndexer struct {
+	handleFn    handleDelegate
+	subscribeFn subscribeDelegate
+
+	conns atomic.Int64
+}
+
+func (m *mockIndexer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/ws" {
+		m.serveWS(w, r)
+
+		return
+	}
+
+	var req *spec.BaseJSONRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	result, jsonErr := m.handleFn(req)
+
+	_ = json.NewEncoder(w).Encode(spec.NewJSONResponse(req.ID, result, jsonErr))
+}
+
+func (m *mockIndexer) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	var req *spec.BaseJSONRequest
+
+	if err := conn.ReadJSON(&req); err != nil {
+		return
+	}
+
+	if err := conn.WriteJSON(spec.NewJSONResponse(req.ID, "subscription-id", nil)); err != nil {
+		return
+	}
+
+	m.subscribeFn(conn, m.conns.Add(1))
+}
+
+// newMockIndexer starts the mock indexer server, returning its client
+func newMockIndexer(t *testing.T, m *mockIndexer, opts ...Option) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client of the JSON-RPC requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithWSURL sets the WS URL of the subscriptions,
+// derived from the JSON-RPC URL by default
+func WithWSURL(wsURL string) Option {
+	return func(c *Client) {
+		c.wsURL = wsURL
+	}
+}
+
+// WithReconnectInterval sets the initial wait between the subscription reconnects,
+// doubled after each failed attempt
+func WithReconnectInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.reconnectInterval = interval
+	}
+}
+
+// WithLogger sets the logger of the subscription reconnects
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/filters/subscription"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// maxReconnectInterval caps the wait between the subscription reconnects
+	maxReconnectInterval = time.Minute
+
+	// subscribeTimeout is the timeout of the subscribe response
+	subscribeTimeout = 10 * time.Second
+)
+
+// Subscription is the indexer event subscription, which resubscribes
+// whenever the WS connection is lost. The events emitted while
+// disconnected are missed, and can be caught up with the query methods
+type Subscription[T any] struct {
+	ch     chan T
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Events returns the subscription events channel,
+// closed once the subscription is closed
+func (s *Subscription[T]) Events() <-chan T {
+	return s.ch
+}
+
+// Close closes the subscription, and waits for its connection to close
+func (s *Subscription[T]) Close() {
+	s.cancel()
+
+	<-s.done
+}
+
+// SubscribeNewHeads subscribes to the headers of the newly indexed blocks,
+// until the context is cancelled or the subscription is closed
+func (c *Client) SubscribeNewHeads(ctx context.Context) (*Subscription[*types.Header], error) {
+	return subscribe(ctx, c, subscription.NewHeadsEvent, func(encoded string) (*types.Header, error) {
+		var header types.Header
+
+		return &header, decodeValue(encoded, &header)
+	})
+}
+
+// SubscribeNewTransactions subscribes to the newly indexed tx results,
+// until the context is cancelled or the subscription is closed
+func (c *Client) SubscribeNewTransactions(ctx context.Context) (*Subscription[*types.TxResult], error) {
+	return subscribe(ctx, c, subscription.NewTransactionsEvent, func(encoded string) (*types.TxResult, error) {
+		var tx types.TxResult
+
+		return &tx, decodeValue(encoded, &tx)
+	})
+}
+
+// subscribe subscribes to the event, decoding the (base64, Amino encoded) notifications.
+// Only the initial subscription needs to succeed, the later ones are retried
+func subscribe[T any](
+	ctx context.Context,
+	c *Client,
+	event string,
+	decode func(string) (T, error),
+) (*Subscription[T], error) {
+	conn, err := c.dialSubscription(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFn := context.WithCancel(ctx)
+
+	s := &Subscription[T]{
+		ch:     make(chan T),
+		cancel: cancelFn,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer func() {
+			close(s.ch)
+			close(s.done)
+		}()
+
+		for {
+			err := readNotifications(ctx, conn, s.ch, decode, c.logger)
+
+			_ = conn.Close()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			c.logger.Warn(
+				"subscription connection lost, resubscribing",
+				zap.String("event", event),
+				zap.Error(err),
+			)
+
+			if conn = c.resubscribe(ctx, event); conn == nil {
+				return
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// readNotifications relays the decoded subscription notifications,
+// until the connection is lost or the context is cancelled
+func readNotifications[T any](
+	ctx context.Context,
+	conn *websocket.Conn,
+	ch chan<- T,
+	decode func(string) (T, error),
+	logger *zap.Logger,
+) error {
+	stop := make(chan struct{})
+	defer close(stop)
+
+	// Close the connection when the context is done,
+	// which unblocks the pending reads
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-stop:
+		}
+	}()
+
+	for {
+		var notification spec.BaseJSONSubscribeResponse
+
+		if err := conn.ReadJSON(&notification); err != nil {
+			return err
+		}
+
+		if notification.Params == nil {
+			continue
+		}
+
+		encoded, ok := notification.Params.Result.(string)
+		if !ok {
+			continue
+		}
+
+		value, err := decode(encoded)
+		if err != nil {
+			logger.Error("unable to decode notification", zap.Error(err))
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- value:
+		}
+	}
+}
+
+// resubscribe resubscribes to the event until it succeeds, doubling the wait
+// between the attempts. Returns nil once the context is cancelled
+func (c *Client) resubscribe(ctx context.Context, event string) *websocket.Conn {
+	interval := c.reconnectInterval
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		conn, err := c.dialSubscription(ctx, event)
+		if err == nil {
+			return conn
+		}
+
+		c.logger.Warn(
+			"unable to resubscribe",
+			zap.String("event", event),
+			zap.Duration("retry", interval),
+			zap.Error(err),
+		)
+
+		interval = min(2*interval, maxReconnectInterval)
+	}
+}
+
+// dialSubscription opens a new WS connection, subscribed to the event
+func (c *Client) dialSubscription(ctx context.Context, event string) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, c.wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to indexer, %w", err)
+	}
+
+	request := spec.NewJSONRequest(uint(c.requestID.Add(1)), "subscribe", []any{event})
+
+	if err := conn.WriteJSON(request); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("unable to send subscribe request, %w", err)
+	}
+
+	var response struct {
+		Error  *spec.BaseJSONError `json:"error"`
+		Result json.RawMessage     `json:"result"`
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(subscribeTimeout))
+
+	if err := conn.ReadJSON(&response); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("unable to read subscribe response, %w", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Time{})
+
+	if response.Error != nil {
+		_ = conn.Close()
+
+		return nil, &Error{Message: response.Error.Message, Code: response.Error.Code}
+	}
+
+	return conn, nil
+}
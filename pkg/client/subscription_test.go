@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// notify writes the encoded value notification to the connection,
+// from the mock indexer routine
+func notify(t *testing.T, conn *websocket.Conn, value any) {
+	t.Helper()
+
+	encoded, err := encode.PrepareValue(value)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, conn.WriteJSON(spec.NewJSONSubscribeResponse("subscription-id", encoded)))
+}
+
+func TestClient_SubscribeNewHeads_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	blocks, _ := mock.GenerateChain(4, 0)
+
+	c := newMockIndexer(
+		t,
+		&mockIndexer{
+			subscribeFn: func(conn *websocket.Conn, connNum int64) {
+				// The first connection is lost after a notification
+				if connNum == 1 {
+					notify(t, conn, blocks[0].Header)
+
+					return
+				}
+
+				for _, block := range blocks[1:] {
+					notify(t, conn, block.Header)
+				}
+
+				// Keep the connection open until the client closes it
+				_, _, _ = conn.ReadMessage()
+			},
+		},
+		WithReconnectInterval(10*time.Millisecond),
+	)
+
+	sub, err := c.SubscribeNewHeads(context.Background())
+	require.NoError(t, err)
+
+	headers := make([]types.Header, 0, len(blocks))
+
+	timeout := time.After(5 * time.Second)
+
+	for len(headers) < len(blocks) {
+		select {
+		case header := <-sub.Events():
+			headers = append(headers, *header)
+		case <-timeout:
+			t.Fatal("subscription events not received")
+		}
+	}
+
+	sub.Close()
+
+	for index, header := range headers {
+		assert.Equal(t, blocks[index].Header, header)
+	}
+
+	// Make sure the events channel is closed
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
+
+func TestClient_SubscribeNewTransactions(t *testing.T) {
+	t.Parallel()
+
+	_, txs := mock.GenerateChain(2, 2)
+
+	c := newMockIndexer(t, &mockIndexer{
+		subscribeFn: func(conn *websocket.Conn, _ int64) {
+			for _, tx := range txs {
+				notify(t, conn, tx)
+			}
+
+			_, _, _ = conn.ReadMessage()
+		},
+	})
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	sub, err := c.SubscribeNewTransactions(ctx)
+	require.NoError(t, err)
+
+	for _, tx := range txs {
+		select {
+		case received := <-sub.Events():
+			assert.Equal(t, tx, received)
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription events not received")
+		}
+	}
+
+	// Make sure the subscription ends with the context
+	cancelFn()
+
+	select {
+	case <-sub.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not closed")
+	}
+}
+
+func TestClient_Subscribe_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	c, err := New("http://127.0.0.1:1")
+	require.NoError(t, err)
+
+	// Make sure the initial subscription errors are returned
+	_, err = c.SubscribeNewHeads(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,215 @@
+// Package balances tracks the native coin movements of accounts
+// (bank sends, realm sends and deposits, transaction fees),
+// maintaining their balance history
+package balances
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "balances"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the native coin balance indexer plugin.
+// Balances are derived only from the indexed transactions, so they don't include
+// the genesis balances, or the coins moved internally by realms
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new native coin balance indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// transfer is a coin transfer between two accounts.
+// Fees have no recipient
+type transfer struct {
+	from   crypto.Address
+	to     crypto.Address
+	amount coins
+}
+
+// OnTx indexes the coin movements of the transaction.
+// Fees are paid even if the transaction execution failed
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	tx, err := p.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded are not indexed
+		return nil
+	}
+
+	transfers := make([]transfer, 0)
+
+	// The first signer pays the transaction fee
+	if signers := tx.GetSigners(); len(signers) > 0 && tx.Fee.GasFee.Amount > 0 {
+		transfers = append(transfers, transfer{
+			from:   signers[0],
+			amount: fromStdCoins(tx.Fee.GasFee),
+		})
+	}
+
+	if !txResult.Response.IsErr() {
+		for _, msg := range tx.GetMsgs() {
+			if t, ok := msgTransfer(msg); ok {
+				transfers = append(transfers, t)
+			}
+		}
+	}
+
+	movements := newMovements(store, txResult)
+
+	for _, t := range transfers {
+		if err := movements.apply(t); err != nil {
+			return fmt.Errorf("unable to save coin movement, %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
+
+// msgTransfer returns the coin transfer of the message, if any
+func msgTransfer(msg std.Msg) (transfer, bool) {
+	var t transfer
+
+	switch m := msg.(type) {
+	case bank.MsgSend:
+		t = transfer{from: m.FromAddress, to: m.ToAddress, amount: fromStdCoins(m.Amount...)}
+	case vm.MsgCall:
+		t = transfer{from: m.Caller, to: gnolang.DerivePkgAddr(m.PkgPath), amount: fromStdCoins(m.Send...)}
+	case vm.MsgAddPackage:
+		if m.Package == nil {
+			return t, false
+		}
+
+		t = transfer{from: m.Creator, to: gnolang.DerivePkgAddr(m.Package.Path), amount: fromStdCoins(m.Deposit...)}
+	default:
+		return t, false
+	}
+
+	return t, len(t.amount) != 0
+}
+
+// movements saves the coin movements of a single transaction
+type movements struct {
+	store    plugins.Store
+	txResult *types.TxResult
+	txHash   string
+
+	seq uint32
+}
+
+func newMovements(store plugins.Store, txResult *types.TxResult) *movements {
+	return &movements{
+		store:    store,
+		txResult: txResult,
+		txHash:   base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+	}
+}
+
+// apply saves the transfer history records, and updates the balances of the participants
+func (m *movements) apply(t transfer) error {
+	movementType := MovementFee
+	if !t.to.IsZero() {
+		movementType = MovementSend
+	}
+
+	if err := m.save(t.from, t.to, movementType, t.amount, -1); err != nil {
+		return err
+	}
+
+	if t.to.IsZero() {
+		return nil
+	}
+
+	return m.save(t.to, t.from, MovementReceive, t.amount, 1)
+}
+
+// save saves a single account movement, applying it to the account balance
+func (m *movements) save(
+	address,
+	counterparty crypto.Address,
+	movementType string,
+	amount coins,
+	sign int64,
+) error {
+	movement := &Movement{
+		Address: address.String(),
+		Type:    movementType,
+		Amount:  amount.String(),
+		TxHash:  m.txHash,
+		Height:  m.txResult.Height,
+		Index:   m.txResult.Index,
+	}
+
+	if !counterparty.IsZero() {
+		movement.Counterparty = counterparty.String()
+	}
+
+	encoded, err := json.Marshal(movement)
+	if err != nil {
+		return err
+	}
+
+	if err := m.store.Set(keyHistory(movement.Address, movement.Height, movement.Index, m.seq), encoded); err != nil {
+		return err
+	}
+
+	m.seq++
+
+	// Update the latest balance, and the balance snapshot at the height
+	balance, err := getBalance(m.store.Get(keyBalance(movement.Address)))
+	if err != nil {
+		return err
+	}
+
+	balance.merge(amount, sign)
+
+	encodedBalance := []byte(balance.String())
+
+	if err := m.store.Set(keyBalance(movement.Address), encodedBalance); err != nil {
+		return err
+	}
+
+	return m.store.Set(keySnapshot(movement.Address, uint64(movement.Height)), encodedBalance)
+}
+
+// getBalance parses the saved balance. Missing balances are empty
+func getBalance(raw []byte, err error) (coins, error) {
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return make(coins), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCoins(string(raw))
+}
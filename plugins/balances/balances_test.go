@@ -0,0 +1,181 @@
+package balances
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given fee and messages
+func newTxResult(t *testing.T, height int64, fee int64, msgs ...std.Msg) *types.TxResult {
+	t.Helper()
+
+	tx := std.Tx{
+		Msgs: msgs,
+		Fee:  std.NewFee(100000, std.Coin{Denom: "ugnot", Amount: fee}),
+	}
+
+	return &types.TxResult{
+		Height: height,
+		Tx:     amino.MustMarshal(tx),
+	}
+}
+
+func TestPlugin_Index(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+
+		realm     = "gno.land/r/demo/wallet"
+		realmAddr = gnolang.DerivePkgAddr(realm)
+
+		coins = func(amount int64) std.Coins {
+			return std.Coins{{Denom: "ugnot", Amount: amount}}
+		}
+
+		failedTx = newTxResult(
+			t,
+			3,
+			10,
+			bank.MsgSend{FromAddress: alice, ToAddress: bob, Amount: coins(1000)},
+		)
+
+		txResults = []*types.TxResult{
+			newTxResult(
+				t,
+				1,
+				10,
+				bank.MsgSend{FromAddress: alice, ToAddress: bob, Amount: coins(100)},
+			),
+			newTxResult(
+				t,
+				2,
+				5,
+				vm.MsgCall{Caller: bob, PkgPath: realm, Func: "Deposit", Send: coins(30)},
+			),
+			failedTx,
+		}
+	)
+
+	failedTx.Response.Error = abci.StringError("execution failed")
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+
+	var (
+		p  = New(nil)
+		wb = s.WriteBatch()
+	)
+
+	store := plugins.NewStore(wb, p.Name())
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(store, txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	r := NewReader(s)
+
+	t.Run("account history", func(t *testing.T) {
+		t.Parallel()
+
+		history, err := r.GetAccountHistory(bob.String(), 100)
+		require.NoError(t, err)
+
+		require.Len(t, history, 3)
+
+		assert.Equal(t, MovementReceive, history[0].Type)
+		assert.Equal(t, alice.String(), history[0].Counterparty)
+		assert.Equal(t, "100ugnot", history[0].Amount)
+
+		assert.Equal(t, MovementFee, history[1].Type)
+		assert.Empty(t, history[1].Counterparty)
+		assert.Equal(t, "5ugnot", history[1].Amount)
+
+		assert.Equal(t, MovementSend, history[2].Type)
+		assert.Equal(t, realmAddr.String(), history[2].Counterparty)
+		assert.Equal(t, "30ugnot", history[2].Amount)
+
+		// The failed transaction only charged the fee
+		history, err = r.GetAccountHistory(alice.String(), 100)
+		require.NoError(t, err)
+
+		require.Len(t, history, 3)
+
+		assert.Equal(t, MovementFee, history[2].Type)
+		assert.Equal(t, int64(3), history[2].Height)
+
+		// Make sure the limit is respected
+		history, err = r.GetAccountHistory(alice.String(), 1)
+		require.NoError(t, err)
+
+		assert.Len(t, history, 1)
+	})
+
+	t.Run("account balances", func(t *testing.T) {
+		t.Parallel()
+
+		testTable := []struct {
+			name            string
+			address         crypto.Address
+			height          uint64
+			expectedBalance string
+			expectedHeight  uint64
+		}{
+			{"after the send", bob, 1, "100ugnot", 1},
+			{"after the call", bob, 2, "65ugnot", 2},
+			{"above the latest height", bob, 10, "65ugnot", 10},
+			{"latest", alice, 0, "-120ugnot", 3},
+			{"realm", realmAddr, 0, "30ugnot", 2},
+		}
+
+		for _, testCase := range testTable {
+			balance, err := r.GetAccountBalanceAt(testCase.address.String(), testCase.height)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expectedBalance, balance.Balance, testCase.name)
+			assert.Equal(t, testCase.expectedHeight, balance.Height, testCase.name)
+		}
+
+		// Make sure unknown accounts have an empty balance
+		balance, err := r.GetAccountBalanceAt(crypto.Address{3}.String(), 0)
+		require.NoError(t, err)
+
+		assert.Empty(t, balance.Balance)
+		assert.Equal(t, uint64(0), balance.Height)
+	})
+}
+
+func TestCoins_Parse(t *testing.T) {
+	t.Parallel()
+
+	c, err := parseCoins("100ugnot,-5foo")
+	require.NoError(t, err)
+
+	c.merge(fromStdCoins(std.Coin{Denom: "foo", Amount: 5}), 1)
+
+	assert.Equal(t, "100ugnot", c.String())
+
+	_, err = parseCoins("invalid")
+	assert.Error(t, err)
+}
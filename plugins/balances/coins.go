@@ -0,0 +1,97 @@
+package balances
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// coinRegex matches a single coin, such as 100ugnot or -5ugnot
+var coinRegex = regexp.MustCompile(`^(-?[0-9]+)([a-zA-Z][a-zA-Z0-9/:._-]*)$`)
+
+// coins is a set of coin amounts, by denomination.
+// Amounts can be negative, since balances are derived only from indexed movements
+type coins map[string]*big.Int
+
+// fromStdCoins converts the standard coins into a coin set
+func fromStdCoins(stdCoins ...std.Coin) coins {
+	c := make(coins, len(stdCoins))
+
+	for _, coin := range stdCoins {
+		c.add(coin.Denom, big.NewInt(coin.Amount))
+	}
+
+	return c
+}
+
+// parseCoins parses the encoded coin set
+func parseCoins(raw string) (coins, error) {
+	c := make(coins)
+
+	if raw == "" {
+		return c, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		matches := coinRegex.FindStringSubmatch(part)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid coin %q", part)
+		}
+
+		//nolint:errcheck // The amount is validated by the regex
+		amount, _ := new(big.Int).SetString(matches[1], 10)
+
+		c.add(matches[2], amount)
+	}
+
+	return c, nil
+}
+
+// add adds the amount of the denomination to the set.
+// Empty amounts are removed
+func (c coins) add(denom string, amount *big.Int) {
+	total, ok := c[denom]
+	if !ok {
+		total = new(big.Int)
+	}
+
+	total.Add(total, amount)
+
+	if total.Sign() == 0 {
+		delete(c, denom)
+
+		return
+	}
+
+	c[denom] = total
+}
+
+// merge adds all the coins of the other set, multiplied by the sign
+func (c coins) merge(other coins, sign int64) {
+	for denom, amount := range other {
+		c.add(denom, new(big.Int).Mul(amount, big.NewInt(sign)))
+	}
+}
+
+// String encodes the coin set, sorted by denomination
+func (c coins) String() string {
+	denoms := make([]string, 0, len(c))
+
+	for denom := range c {
+		denoms = append(denoms, denom)
+	}
+
+	sort.Strings(denoms)
+
+	parts := make([]string, 0, len(denoms))
+
+	for _, denom := range denoms {
+		parts = append(parts, c[denom].String()+denom)
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,33 @@
+package balances
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixBalance  = 'b' // latest balances, by address
+	prefixSnapshot = 's' // balances after each height, by address and inverted height
+	prefixHistory  = 'h' // coin movements, by address
+)
+
+func keyBalance(address string) []byte {
+	return plugins.Key(prefixBalance, address)
+}
+
+// keySnapshot returns the balance snapshot key. Heights are inverted,
+// so the latest snapshot at or below a height is the first one when iterating from it
+func keySnapshot(address string, height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixSnapshot, address), math.MaxUint64-height)
+}
+
+func keyHistory(address string, height int64, txIndex, seq uint32) []byte {
+	key := plugins.Key(prefixHistory, address)
+	key = binary.BigEndian.AppendUint64(key, uint64(height))
+	key = binary.BigEndian.AppendUint32(key, txIndex)
+	key = binary.BigEndian.AppendUint32(key, seq)
+
+	return key
+}
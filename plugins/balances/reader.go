@@ -0,0 +1,100 @@
+package balances
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Reader reads the indexed native coin balance data
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new native coin balance data reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetAccountHistory returns up to limit coin movements of the account, oldest first
+func (r *Reader) GetAccountHistory(address string, limit int) ([]*Movement, error) {
+	prefix := plugins.Key(prefixHistory, address)
+
+	it, err := r.reader.Iterator(prefix, plugins.KeyEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate account history, %w", err)
+	}
+
+	defer it.Close()
+
+	movements := make([]*Movement, 0)
+
+	for it.Next() && len(movements) < limit {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var movement *Movement
+		if err := json.Unmarshal(kv.Value, &movement); err != nil {
+			return nil, fmt.Errorf("unable to decode coin movement, %w", err)
+		}
+
+		movements = append(movements, movement)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// GetAccountBalanceAt returns the account balance after the given height.
+// A zero height returns the latest balance, along with the height it last changed at
+func (r *Reader) GetAccountBalanceAt(address string, height uint64) (*Balance, error) {
+	balance := &Balance{
+		Address: address,
+		Height:  height,
+	}
+
+	if height == 0 {
+		height = math.MaxUint64
+	}
+
+	prefix := plugins.Key(prefixSnapshot, address)
+
+	it, err := r.reader.Iterator(keySnapshot(address, height), plugins.KeyEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate account balances, %w", err)
+	}
+
+	defer it.Close()
+
+	// The first snapshot is the latest one at or below the height
+	if it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		balance.Balance = string(kv.Value)
+
+		if balance.Height == 0 {
+			// Report the height of the latest balance change
+			balance.Height = math.MaxUint64 - binary.BigEndian.Uint64(kv.Key[len(prefix):])
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
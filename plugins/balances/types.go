@@ -0,0 +1,31 @@
+package balances
+
+const (
+	// MovementSend is an outgoing coin movement
+	MovementSend = "send"
+
+	// MovementReceive is an incoming coin movement
+	MovementReceive = "receive"
+
+	// MovementFee is a transaction fee payment
+	MovementFee = "fee"
+)
+
+// Movement is a single native coin movement of an account
+type Movement struct {
+	Address string `json:"address"`
+	// Counterparty is the other account of the movement, empty for fees
+	Counterparty string `json:"counterparty"`
+	Type         string `json:"type"`
+	Amount       string `json:"amount"`
+	TxHash       string `json:"txHash"`
+	Height       int64  `json:"height"`
+	Index        uint32 `json:"index"`
+}
+
+// Balance is the native coin balance of an account, at a given height
+type Balance struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+	Height  uint64 `json:"height"`
+}
@@ -0,0 +1,119 @@
+// Package chainstats maintains rolling chain metrics
+// (transactions per second, block time, daily active addresses)
+package chainstats
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "chainstats"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the chain metrics indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new chain metrics indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+func (p *Plugin) OnTx(_ plugins.Store, _ *types.TxResult) error {
+	return nil
+}
+
+// OnBlock saves the block record, and marks the transaction signers as active on the block day
+func (p *Plugin) OnBlock(store plugins.Store, block *types.Block, results []*types.TxResult) error {
+	height := uint64(block.Height)
+
+	encoded, err := json.Marshal(&blockRecord{
+		Time:     block.Time,
+		TotalTxs: block.TotalTxs,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode block record, %w", err)
+	}
+
+	if err := store.Set(keyBlock(height), encoded); err != nil {
+		return fmt.Errorf("unable to save block record, %w", err)
+	}
+
+	if err := store.Set(keyLatest(), binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return fmt.Errorf("unable to save latest height, %w", err)
+	}
+
+	day := dayOf(block.Time)
+
+	for _, txResult := range results {
+		tx, err := p.decoder.TxResult(txResult)
+		if err != nil {
+			// Transactions that can't be decoded are not indexed
+			continue
+		}
+
+		for _, signer := range tx.GetSigners() {
+			if err := markActive(store, day, signer.String()); err != nil {
+				return fmt.Errorf("unable to mark active address, %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// markActive marks the address as active on the day,
+// counting it if it's the first activity of the day
+func markActive(store plugins.Store, day uint64, address string) error {
+	_, err := store.Get(keyDayAddress(day, address))
+	if err == nil {
+		// Already counted
+		return nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return err
+	}
+
+	if err := store.Set(keyDayAddress(day, address), []byte{}); err != nil {
+		return err
+	}
+
+	count, err := getCount(store.Get(keyDayActivity(day)))
+	if err != nil {
+		return err
+	}
+
+	return store.Set(keyDayActivity(day), binary.BigEndian.AppendUint64(nil, count+1))
+}
+
+// getCount parses the saved counter. Missing counters are zero
+func getCount(raw []byte, err error) (uint64, error) {
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(raw), nil
+}
@@ -0,0 +1,124 @@
+package chainstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result signed by the sender
+func newTxResult(sender crypto.Address) *types.TxResult {
+	return &types.TxResult{
+		Tx: amino.MustMarshal(std.Tx{
+			Msgs: []std.Msg{bank.MsgSend{FromAddress: sender, ToAddress: crypto.Address{0xff}}},
+		}),
+	}
+}
+
+func TestPlugin_GetChainStats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+
+		start = time.Date(2024, 5, 1, 23, 59, 40, 0, time.UTC)
+	)
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	reader := NewReader(s)
+
+	// Nothing is indexed yet
+	stats, err := reader.GetChainStats(10, 7)
+	require.NoError(t, err)
+
+	assert.Empty(t, stats.ActiveAddresses)
+
+	var (
+		p  = New(nil)
+		wb = s.WriteBatch()
+
+		blocks = [][]*types.TxResult{
+			{newTxResult(alice), newTxResult(alice)},
+			{newTxResult(bob)},
+			{},
+			{newTxResult(alice), newTxResult(alice), newTxResult(bob), newTxResult(alice)},
+		}
+
+		totalTxs int64
+	)
+
+	// Blocks are 10s apart, with the last two on the next day
+	for i, results := range blocks {
+		totalTxs += int64(len(results))
+
+		block := &types.Block{
+			Header: types.Header{
+				Height:   int64(i + 1),
+				Time:     start.Add(time.Duration(i) * 10 * time.Second),
+				NumTxs:   int64(len(results)),
+				TotalTxs: totalTxs,
+			},
+		}
+
+		require.NoError(t, p.OnBlock(plugins.NewStore(wb, Name), block, results))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	t.Run("full window", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetChainStats(100, 3)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(4), stats.LatestHeight)
+		assert.Equal(t, int64(7), stats.TotalTxs)
+
+		// The first indexed block starts the window
+		assert.Equal(t, uint64(1), stats.FromHeight)
+		assert.InDelta(t, 10, stats.AvgBlockTime, 0.0001)
+		assert.InDelta(t, float64(5)/30, stats.TPS, 0.0001)
+
+		assert.Equal(
+			t,
+			[]*DailyActive{
+				{Date: "2024-04-30", Count: 0},
+				{Date: "2024-05-01", Count: 2},
+				{Date: "2024-05-02", Count: 2},
+			},
+			stats.ActiveAddresses,
+		)
+	})
+
+	t.Run("block window", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetChainStats(1, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(3), stats.FromHeight)
+		assert.InDelta(t, 10, stats.AvgBlockTime, 0.0001)
+		assert.InDelta(t, 0.4, stats.TPS, 0.0001)
+
+		require.Len(t, stats.ActiveAddresses, 1)
+		assert.Equal(t, "2024-05-02", stats.ActiveAddresses[0].Date)
+	})
+}
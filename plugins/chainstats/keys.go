@@ -0,0 +1,30 @@
+package chainstats
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixBlock       = 'b' // block records, by height
+	prefixLatest      = 'l' // latest indexed height
+	prefixDayAddress  = 'a' // active address markers, by day and address
+	prefixDayActivity = 'd' // active address counts, by day
+)
+
+func keyBlock(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixBlock), height)
+}
+
+func keyLatest() []byte {
+	return plugins.Key(prefixLatest)
+}
+
+func keyDayAddress(day uint64, address string) []byte {
+	return append(binary.BigEndian.AppendUint64(plugins.Key(prefixDayAddress), day), address...)
+}
+
+func keyDayActivity(day uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixDayActivity), day)
+}
@@ -0,0 +1,134 @@
+package chainstats
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Reader reads the indexed chain metrics
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new chain metrics reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetChainStats returns the chain metrics, with the rates computed over the window of latest blocks,
+// and the active addresses of the given number of latest days
+func (r *Reader) GetChainStats(window, days uint64) (*Stats, error) {
+	stats := &Stats{
+		ActiveAddresses: make([]*DailyActive, 0),
+	}
+
+	latestHeight, err := getCount(r.reader.Get(keyLatest()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	latest, err := r.getBlockRecord(latestHeight)
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// Nothing is indexed yet
+		return stats, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats.LatestHeight = latestHeight
+	stats.TotalTxs = latest.TotalTxs
+
+	// Find the first indexed block of the window
+	var fromHeight uint64
+	if window < latestHeight {
+		fromHeight = latestHeight - window
+	}
+
+	fromHeight, first, err := r.firstBlockRecord(fromHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.FromHeight = fromHeight
+
+	if elapsed := latest.Time.Sub(first.Time).Seconds(); elapsed > 0 && latestHeight > fromHeight {
+		stats.TPS = float64(latest.TotalTxs-first.TotalTxs) / elapsed
+		stats.AvgBlockTime = elapsed / float64(latestHeight-fromHeight)
+	}
+
+	// Fetch the daily active addresses
+	latestDay := dayOf(latest.Time)
+
+	for day := latestDay - min(days, latestDay+1) + 1; day <= latestDay; day++ {
+		count, err := getCount(r.reader.Get(keyDayActivity(day)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch active addresses, %w", err)
+		}
+
+		stats.ActiveAddresses = append(stats.ActiveAddresses, &DailyActive{
+			Date:  dayDate(day),
+			Count: count,
+		})
+	}
+
+	return stats, nil
+}
+
+// getBlockRecord fetches the block record at the height
+func (r *Reader) getBlockRecord(height uint64) (*blockRecord, error) {
+	raw, err := r.reader.Get(keyBlock(height))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBlockRecord(raw)
+}
+
+// firstBlockRecord fetches the first indexed block record at or above the height
+func (r *Reader) firstBlockRecord(height uint64) (uint64, *blockRecord, error) {
+	it, err := r.reader.Iterator(keyBlock(height), plugins.KeyEnd(plugins.Key(prefixBlock)))
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to iterate block records, %w", err)
+	}
+
+	defer it.Close()
+
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return 0, nil, err
+		}
+
+		return 0, nil, storageErrors.ErrNotFound
+	}
+
+	kv, err := it.Value()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	record, err := decodeBlockRecord(kv.Value)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return binary.BigEndian.Uint64(kv.Key[len(kv.Key)-8:]), record, nil
+}
+
+func decodeBlockRecord(raw []byte) (*blockRecord, error) {
+	var record *blockRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, fmt.Errorf("unable to decode block record, %w", err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,48 @@
+package chainstats
+
+import (
+	"time"
+)
+
+// Stats are the rolling chain metrics
+type Stats struct {
+	LatestHeight uint64 `json:"latestHeight"`
+	TotalTxs     int64  `json:"totalTxs"`
+
+	// FromHeight is the first block of the window the rates are computed over
+	FromHeight uint64 `json:"fromHeight"`
+
+	// TPS is the average number of transactions per second in the window
+	TPS float64 `json:"tps"`
+
+	// AvgBlockTime is the average block time in the window, in seconds
+	AvgBlockTime float64 `json:"avgBlockTime"`
+
+	// ActiveAddresses are the daily number of distinct transaction signers, oldest day first
+	ActiveAddresses []*DailyActive `json:"activeAddresses"`
+}
+
+// DailyActive is the number of distinct transaction signers in a single (UTC) day
+type DailyActive struct {
+	Date  string `json:"date"`
+	Count uint64 `json:"count"`
+}
+
+// blockRecord is the indexed block data the rates are computed from
+type blockRecord struct {
+	Time     time.Time `json:"time"`
+	TotalTxs int64     `json:"totalTxs"`
+}
+
+// dayLength is the length of the active address day
+const dayLength = 24 * time.Hour
+
+// dayOf returns the (UTC) day number of the time
+func dayOf(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(dayLength/time.Second)
+}
+
+// dayDate returns the date of the day number
+func dayDate(day uint64) string {
+	return time.Unix(int64(day*uint64(dayLength/time.Second)), 0).UTC().Format(time.DateOnly)
+}
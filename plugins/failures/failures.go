@@ -0,0 +1,110 @@
+// Package failures indexes the errors of the failed transactions, for computing
+// error statistics over the recent blocks and querying the transactions by error code
+package failures
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "failures"
+
+// maxMessageLength is the maximum length of the indexed error messages
+const maxMessageLength = 256
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the failed transaction error indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new failed transaction error indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the error of the failed transaction, along with its realm calls
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	if !txResult.Response.IsErr() {
+		return nil
+	}
+
+	var (
+		pos = position(txResult.Height, txResult.Index)
+
+		txErr = &TxError{
+			TxHash:  base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+			Code:    decode.ErrorCode(txResult.Response.Error),
+			Message: message(txResult.Response.Error),
+			Calls:   make([]*Call, 0),
+			Height:  txResult.Height,
+			Index:   txResult.Index,
+		}
+	)
+
+	// The transactions that can't be decoded are indexed without their calls
+	if tx, err := p.decoder.TxResult(txResult); err == nil {
+		for _, msg := range tx.GetMsgs() {
+			if call, ok := msg.(vm.MsgCall); ok {
+				txErr.Calls = append(txErr.Calls, &Call{
+					Realm: call.PkgPath,
+					Func:  call.Func,
+				})
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(txErr)
+	if err != nil {
+		return fmt.Errorf("unable to encode transaction error, %w", err)
+	}
+
+	if err := store.Set(keyTx(pos), encoded); err != nil {
+		return fmt.Errorf("unable to save transaction error, %w", err)
+	}
+
+	if err := store.Set(keyCode(txErr.Code, pos), []byte{}); err != nil {
+		return fmt.Errorf("unable to save transaction error code, %w", err)
+	}
+
+	return nil
+}
+
+// OnBlock saves the latest indexed height, from which the error window is counted
+func (p *Plugin) OnBlock(store plugins.Store, block *types.Block, _ []*types.TxResult) error {
+	if err := store.Set(keyLatest(), binary.BigEndian.AppendUint64(nil, uint64(block.Height))); err != nil {
+		return fmt.Errorf("unable to save latest height, %w", err)
+	}
+
+	return nil
+}
+
+// message returns the (truncated) message of the transaction execution error
+func message(err error) string {
+	msg := err.Error()
+
+	if len(msg) > maxMessageLength {
+		// Drop the rune split by the truncation, if any
+		msg = strings.ToValidUTF8(msg[:maxMessageLength], "")
+	}
+
+	return msg
+}
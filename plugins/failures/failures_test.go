@@ -0,0 +1,220 @@
+package failures
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// outOfGasError is an execution error type, other than the string error
+type outOfGasError struct{}
+
+func (outOfGasError) AssertABCIError() {}
+
+func (outOfGasError) Error() string {
+	return "out of gas"
+}
+
+// newTxResult creates a new transaction result calling the realm functions, failed with the error (if any)
+func newTxResult(height int64, index uint32, err abci.Error, funcs ...string) *types.TxResult {
+	msgs := make([]std.Msg, 0, len(funcs))
+
+	for _, fn := range funcs {
+		// The functions are given as <realm>.<function>
+		split := strings.LastIndex(fn, ".")
+
+		msgs = append(msgs, vm.MsgCall{
+			Caller:  crypto.Address{1},
+			PkgPath: fn[:split],
+			Func:    fn[split+1:],
+		})
+	}
+
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     amino.MustMarshal(std.Tx{Msgs: msgs}),
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Error: err,
+			},
+		},
+	}
+}
+
+func TestPlugin_Failures(t *testing.T) {
+	t.Parallel()
+
+	const (
+		boards = "gno.land/r/demo/boards"
+		users  = "gno.land/r/demo/users"
+	)
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	reader := NewReader(s)
+
+	// Nothing is indexed yet
+	stats, err := reader.GetErrorStats(10)
+	require.NoError(t, err)
+
+	assert.Zero(t, stats.FailedTxs)
+
+	var (
+		p  = New(nil)
+		wb = s.WriteBatch()
+
+		unauthorized = abci.StringError("unauthorized")
+
+		txResults = [][]*types.TxResult{
+			{
+				newTxResult(1, 0, outOfGasError{}, boards+".CreateThread"),
+			},
+			{
+				newTxResult(2, 0, nil, boards+".CreateThread"),
+				newTxResult(2, 1, unauthorized, boards+".DeletePost", boards+".DeletePost"),
+			},
+			{
+				newTxResult(3, 0, unauthorized, boards+".DeletePost", users+".Register"),
+				newTxResult(3, 1, outOfGasError{}),
+			},
+		}
+	)
+
+	for i, results := range txResults {
+		block := &types.Block{
+			Header: types.Header{
+				Height: int64(i + 1),
+			},
+		}
+
+		for _, txResult := range results {
+			require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+		}
+
+		require.NoError(t, p.OnBlock(plugins.NewStore(wb, Name), block, results))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	t.Run("block window", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetErrorStats(2)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), stats.FromHeight)
+		assert.Equal(t, uint64(3), stats.ToHeight)
+
+		assert.Equal(t, 3, stats.FailedTxs)
+		assert.Equal(t, map[string]int{"StringError": 2, "outOfGasError": 1}, stats.Codes)
+
+		assert.Equal(
+			t,
+			[]*ErrorCount{
+				{Code: "StringError", Message: "unauthorized", Count: 2},
+				{Code: "outOfGasError", Message: "out of gas", Count: 1},
+			},
+			stats.Errors,
+		)
+
+		// The realms are ranked by their failed transactions
+		require.Len(t, stats.Realms, 2)
+
+		assert.Equal(
+			t,
+			&RealmErrors{
+				Funcs: map[string]int{"DeletePost": 3},
+				Errors: []*ErrorCount{
+					{Code: "StringError", Message: "unauthorized", Count: 2},
+				},
+				Realm:     boards,
+				FailedTxs: 2,
+			},
+			stats.Realms[0],
+		)
+
+		assert.Equal(t, users, stats.Realms[1].Realm)
+		assert.Equal(t, 1, stats.Realms[1].FailedTxs)
+	})
+
+	t.Run("window larger than the chain", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetErrorStats(100)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), stats.FromHeight)
+		assert.Equal(t, 4, stats.FailedTxs)
+	})
+
+	t.Run("transactions by error code", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetTxsByError("outOfGasError", "", 1, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Txs, 1)
+		require.NotEmpty(t, page.Cursor)
+
+		assert.Equal(t, int64(1), page.Txs[0].Height)
+		assert.Equal(t, []*Call{{Realm: boards, Func: "CreateThread"}}, page.Txs[0].Calls)
+
+		page, err = reader.GetTxsByError("outOfGasError", page.Cursor, 1, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Txs, 1)
+		assert.Empty(t, page.Cursor)
+
+		assert.Equal(t, int64(3), page.Txs[0].Height)
+		assert.Equal(t, uint32(1), page.Txs[0].Index)
+
+		// Make sure the newest first pages walk back
+		page, err = reader.GetTxsByError("outOfGasError", "", 1, true)
+		require.NoError(t, err)
+
+		require.Len(t, page.Txs, 1)
+		require.NotEmpty(t, page.Cursor)
+
+		assert.Equal(t, int64(3), page.Txs[0].Height)
+
+		page, err = reader.GetTxsByError("outOfGasError", page.Cursor, 1, true)
+		require.NoError(t, err)
+
+		require.Len(t, page.Txs, 1)
+		assert.Empty(t, page.Cursor)
+
+		assert.Equal(t, int64(1), page.Txs[0].Height)
+
+		// Make sure the malformed cursors are rejected
+		_, err = reader.GetTxsByError("outOfGasError", "cursor", 1, false)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+}
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+
+	// The truncated messages don't end with a split rune
+	msg := message(abci.StringError(strings.Repeat("a", maxMessageLength-1) + "é"))
+
+	assert.Equal(t, strings.Repeat("a", maxMessageLength-1), msg)
+}
@@ -0,0 +1,47 @@
+package failures
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixTx     = 'f' // failed transaction errors, by height and index
+	prefixCode   = 'c' // failed transaction positions, by error code
+	prefixLatest = 'l' // latest indexed height
+
+	// positionSize is the size of the encoded transaction position
+	positionSize = 12
+)
+
+// position returns the sortable transaction position
+func position(height int64, index uint32) []byte {
+	key := binary.BigEndian.AppendUint64(nil, uint64(height))
+
+	return binary.BigEndian.AppendUint32(key, index)
+}
+
+// keyTxHeight returns the key prefix of the failed transaction errors at the given height
+func keyTxHeight(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixTx), height)
+}
+
+// keyTx returns the key for the error of the failed transaction at the position
+func keyTx(position []byte) []byte {
+	return append(plugins.Key(prefixTx), position...)
+}
+
+// keyCodePrefix returns the key prefix for the transactions failed with the error code
+func keyCodePrefix(code string) []byte {
+	return plugins.Key(prefixCode, code)
+}
+
+// keyCode returns the key marking the transaction at the position as failed with the error code
+func keyCode(code string, position []byte) []byte {
+	return append(keyCodePrefix(code), position...)
+}
+
+func keyLatest() []byte {
+	return plugins.Key(prefixLatest)
+}
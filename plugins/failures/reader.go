@@ -0,0 +1,196 @@
+package failures
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	// maxErrors is the maximum number of the most frequent errors in the statistics
+	maxErrors = 20
+
+	// maxRealms is the maximum number of the most failing realms in the statistics
+	maxRealms = 20
+
+	// maxRealmErrors is the maximum number of the most frequent errors of a single realm
+	maxRealmErrors = 5
+)
+
+// Reader reads the indexed failed transaction errors
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new failed transaction error reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetErrorStats returns the error statistics of the failed transactions
+// in the window of latest indexed blocks. A zero window covers all the indexed blocks
+func (r *Reader) GetErrorStats(window uint64) (*Stats, error) {
+	stats := &Stats{
+		Codes:  make(map[string]int),
+		Errors: make([]*ErrorCount, 0),
+		Realms: make([]*RealmErrors, 0),
+	}
+
+	raw, err := r.reader.Get(keyLatest())
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// Nothing is indexed yet
+		return stats, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	stats.ToHeight = binary.BigEndian.Uint64(raw)
+
+	if window != 0 && window <= stats.ToHeight {
+		stats.FromHeight = stats.ToHeight - window + 1
+	}
+
+	it, err := r.reader.Iterator(keyTxHeight(stats.FromHeight), keyTxHeight(stats.ToHeight+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate transaction errors, %w", err)
+	}
+
+	defer it.Close()
+
+	type realmCounts struct {
+		stats  *RealmErrors
+		errors map[errorKey]int
+	}
+
+	var (
+		errorCounts = make(map[errorKey]int)
+		realms      = make(map[string]*realmCounts)
+	)
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var txErr *TxError
+		if err := json.Unmarshal(kv.Value, &txErr); err != nil {
+			return nil, fmt.Errorf("unable to decode transaction error, %w", err)
+		}
+
+		key := errorKey{
+			code:    txErr.Code,
+			message: txErr.Message,
+		}
+
+		stats.FailedTxs++
+		stats.Codes[txErr.Code]++
+		errorCounts[key]++
+
+		// The transaction is counted once per called realm
+		seen := make(map[string]struct{})
+
+		for _, call := range txErr.Calls {
+			realm, ok := realms[call.Realm]
+			if !ok {
+				realm = &realmCounts{
+					stats: &RealmErrors{
+						Funcs: make(map[string]int),
+						Realm: call.Realm,
+					},
+					errors: make(map[errorKey]int),
+				}
+
+				realms[call.Realm] = realm
+			}
+
+			realm.stats.Funcs[call.Func]++
+
+			if _, ok := seen[call.Realm]; ok {
+				continue
+			}
+
+			seen[call.Realm] = struct{}{}
+
+			realm.stats.FailedTxs++
+			realm.errors[key]++
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	stats.Errors = topErrors(errorCounts, maxErrors)
+
+	for _, realm := range realms {
+		realm.stats.Errors = topErrors(realm.errors, maxRealmErrors)
+		stats.Realms = append(stats.Realms, realm.stats)
+	}
+
+	stats.Realms = topRealms(stats.Realms, maxRealms)
+
+	return stats, nil
+}
+
+// GetTxsByError returns a page of up to limit transactions failed with the error code, oldest first
+// (newest first if reversed). The cursor of the previous page is used to fetch the next one,
+// with an empty cursor starting from the first transaction
+func (r *Reader) GetTxsByError(code, cursor string, limit int, reverse bool) (*Page, error) {
+	page := &Page{
+		Txs: make([]*TxError, 0),
+	}
+
+	prefix := keyCodePrefix(code)
+
+	it, err := r.reader.PageIterator(prefix, cursor, positionSize, reverse)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate transaction error codes, %w", err)
+	}
+
+	defer it.Close()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		pos := kv.Key[len(prefix):]
+
+		if len(page.Txs) == limit {
+			// There are more transactions, point the cursor to the next one
+			page.Cursor = cursors.Encode(pos)
+
+			break
+		}
+
+		raw, err := r.reader.Get(keyTx(pos))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch transaction error, %w", err)
+		}
+
+		var txErr *TxError
+		if err := json.Unmarshal(raw, &txErr); err != nil {
+			return nil, fmt.Errorf("unable to decode transaction error, %w", err)
+		}
+
+		page.Txs = append(page.Txs, txErr)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,109 @@
+package failures
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Stats are the error statistics of the failed transactions in the block window
+type Stats struct {
+	// Codes are the failed transaction counts, per error code
+	Codes map[string]int `json:"codes"`
+
+	// Errors are the most frequent errors, most frequent first
+	Errors []*ErrorCount `json:"errors"`
+
+	// Realms are the realms with the most failed calls, most failing first
+	Realms []*RealmErrors `json:"realms"`
+
+	FromHeight uint64 `json:"fromHeight"`
+	ToHeight   uint64 `json:"toHeight"`
+	FailedTxs  int    `json:"failedTxs"`
+}
+
+// ErrorCount is the number of failed transactions with the error code and message
+type ErrorCount struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// RealmErrors are the error statistics of the failed transactions calling the realm
+type RealmErrors struct {
+	// Funcs are the failed call counts, per called realm function
+	Funcs map[string]int `json:"funcs"`
+
+	// Errors are the most frequent errors of the realm calls, most frequent first
+	Errors []*ErrorCount `json:"errors"`
+
+	Realm     string `json:"realm"`
+	FailedTxs int    `json:"failedTxs"`
+}
+
+// TxError is the indexed error of a single failed transaction
+type TxError struct {
+	TxHash  string `json:"txHash"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// Calls are the realm calls of the transaction, if any
+	Calls []*Call `json:"calls"`
+
+	Height int64  `json:"height"`
+	Index  uint32 `json:"index"`
+}
+
+// Call is a realm function call
+type Call struct {
+	Realm string `json:"realm"`
+	Func  string `json:"func"`
+}
+
+// Page is a single page of transactions failed with an error code
+type Page struct {
+	Txs []*TxError `json:"txs"`
+
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// errorKey is the grouping key of the error counts
+type errorKey struct {
+	code    string
+	message string
+}
+
+// topErrors returns up to limit of the most frequent errors, most frequent first
+func topErrors(counts map[errorKey]int, limit int) []*ErrorCount {
+	errs := make([]*ErrorCount, 0, len(counts))
+
+	for key, count := range counts {
+		errs = append(errs, &ErrorCount{
+			Code:    key.code,
+			Message: key.message,
+			Count:   count,
+		})
+	}
+
+	slices.SortFunc(errs, func(a, b *ErrorCount) int {
+		return cmp.Or(
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Code, b.Code),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+
+	return errs[:min(len(errs), limit)]
+}
+
+// topRealms returns up to limit of the most failing realms, most failing first
+func topRealms(realms []*RealmErrors, limit int) []*RealmErrors {
+	slices.SortFunc(realms, func(a, b *RealmErrors) int {
+		return cmp.Or(
+			cmp.Compare(b.FailedTxs, a.FailedTxs),
+			cmp.Compare(a.Realm, b.Realm),
+		)
+	})
+
+	return realms[:min(len(realms), limit)]
+}
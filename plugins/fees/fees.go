@@ -0,0 +1,70 @@
+// Package fees indexes the transaction fees, for computing
+// fee statistics over the recent blocks
+package fees
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "fees"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the transaction fee indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new transaction fee indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the transaction fee.
+// Fees are paid even if the transaction execution failed
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	tx, err := p.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded are not indexed
+		return nil
+	}
+
+	encoded, err := json.Marshal(&txFee{
+		Denom:     tx.Fee.GasFee.Denom,
+		Amount:    tx.Fee.GasFee.Amount,
+		GasWanted: tx.Fee.GasWanted,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode transaction fee, %w", err)
+	}
+
+	if err := store.Set(keyFee(txResult.Height, txResult.Index), encoded); err != nil {
+		return fmt.Errorf("unable to save transaction fee, %w", err)
+	}
+
+	return nil
+}
+
+// OnBlock saves the latest indexed height, from which the fee window is counted
+func (p *Plugin) OnBlock(store plugins.Store, block *types.Block, _ []*types.TxResult) error {
+	if err := store.Set(keyLatest(), binary.BigEndian.AppendUint64(nil, uint64(block.Height))); err != nil {
+		return fmt.Errorf("unable to save latest height, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given fee
+func newTxResult(height int64, index uint32, gasWanted int64, fee std.Coin) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     amino.MustMarshal(std.Tx{Fee: std.NewFee(gasWanted, fee)}),
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	t.Parallel()
+
+	values := make([]float64, 0, 100)
+
+	for i := 100; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+
+	assert.Equal(
+		t,
+		&Percentiles{Min: 1, P10: 10, P25: 25, P50: 50, P75: 75, P90: 90, Max: 100},
+		newPercentiles(values),
+	)
+
+	assert.Equal(
+		t,
+		&Percentiles{Min: 7, P10: 7, P25: 7, P50: 7, P75: 7, P90: 7, Max: 7},
+		newPercentiles([]float64{7}),
+	)
+}
+
+func TestPlugin_GetFeeStats(t *testing.T) {
+	t.Parallel()
+
+	ugnot := func(amount int64) std.Coin {
+		return std.Coin{Denom: "ugnot", Amount: amount}
+	}
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	reader := NewReader(s)
+
+	// Nothing is indexed yet
+	stats, err := reader.GetFeeStats(10)
+	require.NoError(t, err)
+
+	assert.Empty(t, stats.ByDenom)
+
+	var (
+		p  = New(nil)
+		wb = s.WriteBatch()
+
+		txResults = [][]*types.TxResult{
+			{
+				newTxResult(1, 0, 1000, ugnot(1000)),
+			},
+			{
+				newTxResult(2, 0, 1000, ugnot(2000)),
+				newTxResult(2, 1, 2000, ugnot(2000)),
+			},
+			{},
+			{
+				newTxResult(4, 0, 1000, ugnot(4000)),
+				newTxResult(4, 1, 0, std.Coin{Denom: "uatom", Amount: 10}),
+			},
+		}
+	)
+
+	for i, results := range txResults {
+		block := &types.Block{
+			Header: types.Header{
+				Height: int64(i + 1),
+			},
+		}
+
+		for _, txResult := range results {
+			require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+		}
+
+		require.NoError(t, p.OnBlock(plugins.NewStore(wb, Name), block, results))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	t.Run("block window", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetFeeStats(3)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), stats.FromHeight)
+		assert.Equal(t, uint64(4), stats.ToHeight)
+
+		require.Contains(t, stats.ByDenom, "ugnot")
+
+		ugnotStats := stats.ByDenom["ugnot"]
+
+		assert.Equal(t, 3, ugnotStats.TxCount)
+		assert.Equal(t, float64(2000), ugnotStats.Fee.Min)
+		assert.Equal(t, float64(2000), ugnotStats.Fee.P50)
+		assert.Equal(t, float64(4000), ugnotStats.Fee.Max)
+
+		assert.Equal(t, float64(1), ugnotStats.GasPrice.Min)
+		assert.Equal(t, float64(2), ugnotStats.GasPrice.P50)
+		assert.Equal(t, float64(4), ugnotStats.GasPrice.Max)
+
+		// Transactions without wanted gas have no gas price
+		require.Contains(t, stats.ByDenom, "uatom")
+		assert.Nil(t, stats.ByDenom["uatom"].GasPrice)
+	})
+
+	t.Run("window larger than the chain", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetFeeStats(100)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(0), stats.FromHeight)
+		assert.Equal(t, 4, stats.ByDenom["ugnot"].TxCount)
+	})
+}
@@ -0,0 +1,25 @@
+package fees
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixFee    = 'f' // transaction fees, by height and index
+	prefixLatest = 'l' // latest indexed height
+)
+
+// keyFeeHeight returns the key prefix of the transaction fees at the given height
+func keyFeeHeight(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixFee), height)
+}
+
+func keyFee(height int64, index uint32) []byte {
+	return binary.BigEndian.AppendUint32(keyFeeHeight(uint64(height)), index)
+}
+
+func keyLatest() []byte {
+	return plugins.Key(prefixLatest)
+}
@@ -0,0 +1,105 @@
+package fees
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Reader reads the indexed transaction fee data
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new transaction fee data reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetFeeStats returns the fee statistics of the transactions
+// in the window of latest indexed blocks. A zero window covers all the indexed blocks
+func (r *Reader) GetFeeStats(window uint64) (*Stats, error) {
+	stats := &Stats{
+		ByDenom: make(map[string]*DenomStats),
+	}
+
+	raw, err := r.reader.Get(keyLatest())
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// Nothing is indexed yet
+		return stats, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	stats.ToHeight = binary.BigEndian.Uint64(raw)
+
+	if window != 0 && window <= stats.ToHeight {
+		stats.FromHeight = stats.ToHeight - window + 1
+	}
+
+	it, err := r.reader.Iterator(keyFeeHeight(stats.FromHeight), keyFeeHeight(stats.ToHeight+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate transaction fees, %w", err)
+	}
+
+	defer it.Close()
+
+	type denomValues struct {
+		fees      []float64
+		gasPrices []float64
+	}
+
+	values := make(map[string]*denomValues)
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var fee *txFee
+		if err := json.Unmarshal(kv.Value, &fee); err != nil {
+			return nil, fmt.Errorf("unable to decode transaction fee, %w", err)
+		}
+
+		v, ok := values[fee.Denom]
+		if !ok {
+			v = &denomValues{}
+			values[fee.Denom] = v
+		}
+
+		v.fees = append(v.fees, float64(fee.Amount))
+
+		if fee.GasWanted > 0 {
+			v.gasPrices = append(v.gasPrices, float64(fee.Amount)/float64(fee.GasWanted))
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	for denom, v := range values {
+		denomStats := &DenomStats{
+			TxCount: len(v.fees),
+			Fee:     newPercentiles(v.fees),
+		}
+
+		if len(v.gasPrices) > 0 {
+			denomStats.GasPrice = newPercentiles(v.gasPrices)
+		}
+
+		stats.ByDenom[denom] = denomStats
+	}
+
+	return stats, nil
+}
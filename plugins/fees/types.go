@@ -0,0 +1,65 @@
+package fees
+
+import (
+	"math"
+	"sort"
+)
+
+// Stats are the fee statistics of the transactions in the block window
+type Stats struct {
+	FromHeight uint64 `json:"fromHeight"`
+	ToHeight   uint64 `json:"toHeight"`
+
+	// ByDenom are the fee statistics, per fee denomination
+	ByDenom map[string]*DenomStats `json:"byDenom"`
+}
+
+// DenomStats are the fee statistics of a single fee denomination
+type DenomStats struct {
+	TxCount int `json:"txCount"`
+
+	// Fee are the percentiles of the transaction fees
+	Fee *Percentiles `json:"fee"`
+
+	// GasPrice are the percentiles of the fees per unit of wanted gas
+	GasPrice *Percentiles `json:"gasPrice"`
+}
+
+// Percentiles are the percentiles of a set of values
+type Percentiles struct {
+	Min float64 `json:"min"`
+	P10 float64 `json:"p10"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P90 float64 `json:"p90"`
+	Max float64 `json:"max"`
+}
+
+// newPercentiles computes the nearest-rank percentiles of the (non-empty) values
+func newPercentiles(values []float64) *Percentiles {
+	sort.Float64s(values)
+
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p / 100 * float64(len(values))))
+
+		return values[max(rank-1, 0)]
+	}
+
+	return &Percentiles{
+		Min: values[0],
+		P10: percentile(10),
+		P25: percentile(25),
+		P50: percentile(50),
+		P75: percentile(75),
+		P90: percentile(90),
+		Max: values[len(values)-1],
+	}
+}
+
+// txFee is the indexed fee of a single transaction
+type txFee struct {
+	Denom     string `json:"denom"`
+	Amount    int64  `json:"amount"`
+	GasWanted int64  `json:"gasWanted"`
+}
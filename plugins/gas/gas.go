@@ -0,0 +1,157 @@
+// Package gas indexes the gas usage of transactions,
+// aggregated per message type and per realm
+package gas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "gas"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the gas usage indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new gas usage indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the gas usage of the transaction, and updates the all-time statistics.
+// Failed transactions are indexed as well, since they still use gas
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	tx := &txGas{
+		GasWanted: txResult.Response.GasWanted,
+		GasUsed:   txResult.Response.GasUsed,
+		MsgTypes:  make([]string, 0),
+		Realms:    make([]string, 0),
+	}
+
+	// Transactions that can't be decoded only count towards the total
+	if decoded, err := p.decoder.TxResult(txResult); err == nil {
+		tx.MsgTypes, tx.Realms = msgTypesAndRealms(decoded.GetMsgs())
+	}
+
+	encoded, err := json.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("unable to encode gas usage, %w", err)
+	}
+
+	if err := store.Set(keyTx(txResult.Height, txResult.Index), encoded); err != nil {
+		return fmt.Errorf("unable to save gas usage, %w", err)
+	}
+
+	keys := [][]byte{keyTotal()}
+
+	for _, msgType := range tx.MsgTypes {
+		keys = append(keys, keyMsgType(msgType))
+	}
+
+	for _, realm := range tx.Realms {
+		keys = append(keys, keyRealm(realm))
+	}
+
+	for _, key := range keys {
+		if err := updateStats(store, key, tx); err != nil {
+			return fmt.Errorf("unable to update gas usage statistics, %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
+
+// msgTypesAndRealms returns the distinct message types,
+// and the distinct realms called or deployed by the messages
+func msgTypesAndRealms(msgs []std.Msg) ([]string, []string) {
+	var (
+		msgTypes = make([]string, 0, len(msgs))
+		realms   = make([]string, 0, len(msgs))
+
+		seen = make(map[string]struct{})
+	)
+
+	appendDistinct := func(list []string, kind, value string) []string {
+		if _, ok := seen[kind+value]; ok {
+			return list
+		}
+
+		seen[kind+value] = struct{}{}
+
+		return append(list, value)
+	}
+
+	for _, msg := range msgs {
+		msgTypes = appendDistinct(msgTypes, "type:", msg.Type())
+
+		switch m := msg.(type) {
+		case vm.MsgCall:
+			realms = appendDistinct(realms, "realm:", m.PkgPath)
+		case vm.MsgAddPackage:
+			if m.Package != nil {
+				realms = appendDistinct(realms, "realm:", m.Package.Path)
+			}
+		}
+	}
+
+	return msgTypes, realms
+}
+
+// updateStats adds the transaction gas usage to the saved statistics
+func updateStats(store plugins.Store, key []byte, tx *txGas) error {
+	stats, err := getStats(store.Get(key))
+	if err != nil {
+		return err
+	}
+
+	stats.add(tx)
+
+	encoded, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	return store.Set(key, encoded)
+}
+
+// getStats parses the saved statistics. Missing statistics are empty
+func getStats(raw []byte, err error) (*Stats, error) {
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return &Stats{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *Stats
+	if err := json.Unmarshal(raw, &stats); err != nil {
+		return nil, fmt.Errorf("unable to decode gas usage statistics, %w", err)
+	}
+
+	return stats, nil
+}
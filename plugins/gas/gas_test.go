@@ -0,0 +1,141 @@
+package gas
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given gas usage and messages
+func newTxResult(height int64, index uint32, gasUsed int64, msgs ...std.Msg) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     amino.MustMarshal(std.Tx{Msgs: msgs}),
+		Response: abci.ResponseDeliverTx{
+			GasWanted: 1000,
+			GasUsed:   gasUsed,
+		},
+	}
+}
+
+func TestPlugin_GetGasStats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		caller = crypto.Address{1}
+
+		boards = "gno.land/r/demo/boards"
+		users  = "gno.land/r/demo/users"
+
+		failedTx = newTxResult(3, 0, 400, vm.MsgCall{Caller: caller, PkgPath: boards})
+
+		txResults = []*types.TxResult{
+			newTxResult(1, 0, 100, bank.MsgSend{FromAddress: caller, ToAddress: caller}),
+			newTxResult(
+				2,
+				0,
+				200,
+				vm.MsgCall{Caller: caller, PkgPath: boards},
+				vm.MsgCall{Caller: caller, PkgPath: boards},
+			),
+			newTxResult(
+				2,
+				1,
+				300,
+				vm.MsgAddPackage{Creator: caller, Package: &std.MemPackage{Path: users}},
+			),
+			failedTx,
+			{
+				Height: 4,
+				Tx:     []byte("invalid tx"),
+				Response: abci.ResponseDeliverTx{
+					GasWanted: 1000,
+					GasUsed:   50,
+				},
+			},
+		}
+	)
+
+	failedTx.Response.Error = abci.StringError("execution failed")
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	var (
+		p  = New(nil)
+		wb = s.WriteBatch()
+	)
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	reader := NewReader(s)
+
+	t.Run("all-time stats", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := reader.GetGasStats(0, 0)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			&Stats{TxCount: 5, GasWanted: 5000, GasUsed: 1050, AvgGasUsed: 210, MaxGasUsed: 400},
+			report.Total,
+		)
+
+		// Both calls of the same transaction count once
+		assert.Equal(
+			t,
+			&Stats{TxCount: 2, GasWanted: 2000, GasUsed: 600, AvgGasUsed: 300, MaxGasUsed: 400},
+			report.ByMsgType["exec"],
+		)
+		assert.Equal(t, report.ByMsgType["exec"], report.ByRealm[boards])
+
+		assert.Equal(t, int64(300), report.ByRealm[users].GasUsed)
+		assert.Equal(t, int64(100), report.ByMsgType["send"].GasUsed)
+		assert.Len(t, report.ByMsgType, 3)
+	})
+
+	t.Run("block range stats", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := reader.GetGasStats(2, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), report.Total.TxCount)
+		assert.Equal(t, int64(500), report.Total.GasUsed)
+
+		assert.Equal(t, uint64(1), report.ByRealm[boards].TxCount)
+		assert.NotContains(t, report.ByMsgType, "send")
+	})
+
+	t.Run("unbounded block range", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := reader.GetGasStats(3, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), report.Total.TxCount)
+		assert.Equal(t, int64(450), report.Total.GasUsed)
+	})
+}
@@ -0,0 +1,35 @@
+package gas
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixTx      = 't' // transaction gas usage, by height and index
+	prefixTotal   = 'a' // all-time gas usage statistics
+	prefixMsgType = 'm' // all-time gas usage statistics, by message type
+	prefixRealm   = 'r' // all-time gas usage statistics, by realm
+)
+
+// keyTxHeight returns the key prefix of the transactions at the given height
+func keyTxHeight(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixTx), height)
+}
+
+func keyTx(height int64, index uint32) []byte {
+	return binary.BigEndian.AppendUint32(keyTxHeight(uint64(height)), index)
+}
+
+func keyTotal() []byte {
+	return plugins.Key(prefixTotal)
+}
+
+func keyMsgType(msgType string) []byte {
+	return plugins.Key(prefixMsgType, msgType)
+}
+
+func keyRealm(realm string) []byte {
+	return plugins.Key(prefixRealm, realm)
+}
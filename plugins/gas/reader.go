@@ -0,0 +1,112 @@
+package gas
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Reader reads the indexed gas usage data
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new gas usage data reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetGasStats returns the gas usage report of the transactions in the [fromHeight, toHeight] range.
+// A zero toHeight is unbounded, and without a range the all-time statistics are returned
+func (r *Reader) GetGasStats(fromHeight, toHeight uint64) (*Report, error) {
+	if fromHeight == 0 && toHeight == 0 {
+		return r.getAllTimeStats()
+	}
+
+	to := plugins.KeyEnd(plugins.Key(prefixTx))
+	if toHeight != 0 {
+		to = keyTxHeight(toHeight + 1)
+	}
+
+	it, err := r.reader.Iterator(keyTxHeight(fromHeight), to)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate gas usage, %w", err)
+	}
+
+	defer it.Close()
+
+	report := newReport()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var tx *txGas
+		if err := json.Unmarshal(kv.Value, &tx); err != nil {
+			return nil, fmt.Errorf("unable to decode gas usage, %w", err)
+		}
+
+		report.add(tx)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// getAllTimeStats returns the incrementally maintained all-time gas usage report
+func (r *Reader) getAllTimeStats() (*Report, error) {
+	total, err := getStats(r.reader.Get(keyTotal()))
+	if err != nil {
+		return nil, err
+	}
+
+	report := newReport()
+	report.Total = total
+
+	for prefix, stats := range map[byte]map[string]*Stats{
+		prefixMsgType: report.ByMsgType,
+		prefixRealm:   report.ByRealm,
+	} {
+		if err := r.readStats(plugins.Key(prefix), stats); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// readStats reads all the statistics under the key prefix into the map
+func (r *Reader) readStats(prefix []byte, stats map[string]*Stats) error {
+	it, err := r.reader.Iterator(prefix, plugins.KeyEnd(prefix))
+	if err != nil {
+		return fmt.Errorf("unable to iterate gas usage statistics, %w", err)
+	}
+
+	defer it.Close()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return err
+		}
+
+		s, err := getStats(kv.Value, nil)
+		if err != nil {
+			return err
+		}
+
+		// Strip the prefix and the trailing separator
+		stats[string(kv.Key[len(prefix):len(kv.Key)-1])] = s
+	}
+
+	return it.Error()
+}
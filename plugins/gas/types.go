@@ -0,0 +1,66 @@
+package gas
+
+// Stats are the aggregated gas usage statistics of a set of transactions
+type Stats struct {
+	TxCount    uint64 `json:"txCount"`
+	GasWanted  int64  `json:"gasWanted"`
+	GasUsed    int64  `json:"gasUsed"`
+	AvgGasUsed int64  `json:"avgGasUsed"`
+	MaxGasUsed int64  `json:"maxGasUsed"`
+}
+
+// add adds the transaction gas usage to the statistics
+func (s *Stats) add(tx *txGas) {
+	s.TxCount++
+	s.GasWanted += tx.GasWanted
+	s.GasUsed += tx.GasUsed
+	s.AvgGasUsed = s.GasUsed / int64(s.TxCount)
+	s.MaxGasUsed = max(s.MaxGasUsed, tx.GasUsed)
+}
+
+// Report is the gas usage report, in total, per message type and per realm.
+// Transactions with multiple messages count towards each of their message types and realms
+type Report struct {
+	Total     *Stats            `json:"total"`
+	ByMsgType map[string]*Stats `json:"byMsgType"`
+	ByRealm   map[string]*Stats `json:"byRealm"`
+}
+
+func newReport() *Report {
+	return &Report{
+		Total:     &Stats{},
+		ByMsgType: make(map[string]*Stats),
+		ByRealm:   make(map[string]*Stats),
+	}
+}
+
+// add adds the transaction gas usage to the report
+func (r *Report) add(tx *txGas) {
+	r.Total.add(tx)
+
+	for _, msgType := range tx.MsgTypes {
+		addTo(r.ByMsgType, msgType, tx)
+	}
+
+	for _, realm := range tx.Realms {
+		addTo(r.ByRealm, realm, tx)
+	}
+}
+
+func addTo(stats map[string]*Stats, key string, tx *txGas) {
+	s, ok := stats[key]
+	if !ok {
+		s = &Stats{}
+		stats[key] = s
+	}
+
+	s.add(tx)
+}
+
+// txGas is the indexed gas usage of a single transaction
+type txGas struct {
+	GasWanted int64    `json:"gasWanted"`
+	GasUsed   int64    `json:"gasUsed"`
+	MsgTypes  []string `json:"msgTypes"`
+	Realms    []string `json:"realms"`
+}
@@ -0,0 +1,208 @@
+// Package grc20 indexes GRC20 token transfers, mints and burns,
+// based on the events emitted by the token realms
+package grc20
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "grc20"
+
+const (
+	eventTransfer = "Transfer"
+	eventMint     = "Mint"
+	eventBurn     = "Burn"
+)
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the GRC20 token indexer plugin
+type Plugin struct{}
+
+// New creates a new GRC20 token indexer plugin
+func New() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the token movements of the transaction, if it was successful
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	if txResult.Response.IsErr() {
+		return nil
+	}
+
+	txHash := base64.StdEncoding.EncodeToString(txResult.Tx.Hash())
+
+	for eventIndex, event := range decode.Events(txResult.Response) {
+		transfer, ok := parseTransfer(event)
+		if !ok {
+			continue
+		}
+
+		transfer.TxHash = txHash
+		transfer.Height = txResult.Height
+		transfer.Index = txResult.Index
+
+		if err := saveTransfer(store, transfer, uint32(eventIndex)); err != nil {
+			return fmt.Errorf("unable to save token transfer, %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
+
+// parseTransfer parses the GRC20 token movement from the realm event, if any
+func parseTransfer(event *decode.Event) (*Transfer, bool) {
+	if event.Type != eventTransfer && event.Type != eventMint && event.Type != eventBurn {
+		return nil, false
+	}
+
+	from, _ := event.Attr("from")
+	to, _ := event.Attr("to")
+
+	value, ok := event.Attr("value")
+	if !ok {
+		value, ok = event.Attr("amount")
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, false
+	}
+
+	switch event.Type {
+	case eventMint:
+		from = ""
+	case eventBurn:
+		to = ""
+	}
+
+	if from == "" && to == "" {
+		return nil, false
+	}
+
+	return &Transfer{
+		Token:  event.PkgPath,
+		From:   from,
+		To:     to,
+		Amount: amount.String(),
+	}, true
+}
+
+// saveTransfer saves the transfer history records,
+// and updates the balances of the participants
+func saveTransfer(store plugins.Store, transfer *Transfer, eventIndex uint32) error {
+	encoded, err := json.Marshal(transfer)
+	if err != nil {
+		return err
+	}
+
+	key := keyTransfer(transfer.Token, transfer.Height, transfer.Index, eventIndex)
+	if err := store.Set(key, encoded); err != nil {
+		return err
+	}
+
+	//nolint:errcheck // The amount is validated when parsing
+	amount, _ := new(big.Int).SetString(transfer.Amount, 10)
+
+	for _, participant := range []struct {
+		address string
+		delta   *big.Int
+	}{
+		{transfer.From, new(big.Int).Neg(amount)},
+		{transfer.To, amount},
+	} {
+		if participant.address == "" {
+			continue
+		}
+
+		addressKey := keyAddressTransfer(
+			transfer.Token,
+			participant.address,
+			transfer.Height,
+			transfer.Index,
+			eventIndex,
+		)
+
+		if err := store.Set(addressKey, encoded); err != nil {
+			return err
+		}
+
+		if err := updateBalance(store, transfer.Token, participant.address, participant.delta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateBalance applies the delta to the address token balance, and moves the address
+// in the token holders. Empty balances are removed, and only the positive ones are held
+func updateBalance(store plugins.Store, token, address string, delta *big.Int) error {
+	key := keyBalance(token, address)
+
+	previous, err := getBalance(store.Get(key))
+	if err != nil {
+		return err
+	}
+
+	balance := new(big.Int).Add(previous, delta)
+
+	if previous.Sign() > 0 {
+		if err := store.Delete(keyHolder(token, previous, address)); err != nil {
+			return err
+		}
+	}
+
+	if balance.Sign() > 0 {
+		if err := store.Set(keyHolder(token, balance, address), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	if balance.Sign() == 0 {
+		return store.Delete(key)
+	}
+
+	return store.Set(key, []byte(balance.String()))
+}
+
+// getBalance parses the saved balance. Missing balances are empty
+func getBalance(raw []byte, err error) (*big.Int, error) {
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return new(big.Int), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	balance, ok := new(big.Int).SetString(string(raw), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance %q", raw)
+	}
+
+	return balance, nil
+}
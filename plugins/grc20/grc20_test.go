@@ -0,0 +1,291 @@
+package grc20
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newEvent creates a new token realm event
+func newEvent(eventType, token string, attrs ...string) mock.GnoEvent {
+	event := mock.GnoEvent{
+		Type:    eventType,
+		PkgPath: token,
+	}
+
+	for i := 0; i+1 < len(attrs); i += 2 {
+		event.Attrs = append(event.Attrs, decode.EventAttribute{
+			Key:   attrs[i],
+			Value: attrs[i+1],
+		})
+	}
+
+	return event
+}
+
+// newTxResult creates a new transaction result with the given events
+func newTxResult(height int64, index uint32, events ...abci.Event) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     []byte{byte(height), byte(index)},
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: events,
+			},
+		},
+	}
+}
+
+func TestPlugin_Index(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = "gno.land/r/demo/foo20"
+		other = "gno.land/r/demo/bar20"
+
+		alice = "g1alice"
+		bob   = "g1bob"
+		carol = "g1carol"
+
+		txResults = []*types.TxResult{
+			newTxResult(
+				1,
+				0,
+				newEvent(eventTransfer, token, "from", "", "to", alice, "value", "1000"),
+				newEvent(eventTransfer, other, "from", "", "to", alice, "value", "5"),
+			),
+			newTxResult(
+				2,
+				0,
+				newEvent(eventTransfer, token, "from", alice, "to", bob, "value", "300"),
+				newEvent("UnrelatedEvent", token, "from", alice, "to", bob, "value", "300"),
+			),
+			newTxResult(
+				2,
+				1,
+				newEvent(eventTransfer, token, "from", alice, "to", carol, "value", "100"),
+				newEvent(eventTransfer, token, "from", "", "to", carol, "value", "invalid"),
+			),
+			newTxResult(
+				3,
+				0,
+				newEvent(eventBurn, token, "from", bob, "value", "300"),
+			),
+		}
+	)
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+
+	var (
+		p  = New()
+		wb = s.WriteBatch()
+	)
+
+	store := plugins.NewStore(wb, p.Name())
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(store, txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	r := NewReader(s)
+
+	t.Run("token transfers", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := r.GetTokenTransfers(token, "", "", 100, false)
+		require.NoError(t, err)
+
+		transfers := page.Transfers
+		require.Len(t, transfers, 4)
+		assert.Empty(t, page.Cursor)
+
+		assert.Equal(t, "", transfers[0].From)
+		assert.Equal(t, alice, transfers[0].To)
+		assert.Equal(t, "1000", transfers[0].Amount)
+		assert.Equal(t, int64(1), transfers[0].Height)
+
+		assert.Equal(t, bob, transfers[3].From)
+		assert.Equal(t, "", transfers[3].To)
+		assert.Equal(t, int64(3), transfers[3].Height)
+	})
+
+	t.Run("paginated transfers", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := r.GetTokenTransfers(token, "", "", 3, false)
+		require.NoError(t, err)
+
+		require.Len(t, first.Transfers, 3)
+		require.NotEmpty(t, first.Cursor)
+
+		second, err := r.GetTokenTransfers(token, "", first.Cursor, 3, false)
+		require.NoError(t, err)
+
+		require.Len(t, second.Transfers, 1)
+		assert.Empty(t, second.Cursor)
+
+		assert.Equal(t, int64(3), second.Transfers[0].Height)
+
+		// Make sure the malformed cursors are rejected
+		_, err = r.GetTokenTransfers(token, "", "cursor", 3, false)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+
+	t.Run("newest first transfers", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := r.GetTokenTransfers(token, "", "", 3, true)
+		require.NoError(t, err)
+
+		require.Len(t, first.Transfers, 3)
+		require.NotEmpty(t, first.Cursor)
+
+		assert.Equal(t, int64(3), first.Transfers[0].Height)
+		assert.Equal(t, carol, first.Transfers[1].To)
+
+		second, err := r.GetTokenTransfers(token, "", first.Cursor, 3, true)
+		require.NoError(t, err)
+
+		require.Len(t, second.Transfers, 1)
+		assert.Empty(t, second.Cursor)
+
+		assert.Equal(t, int64(1), second.Transfers[0].Height)
+	})
+
+	t.Run("address transfers", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := r.GetTokenTransfers(token, bob, "", 100, false)
+		require.NoError(t, err)
+
+		transfers := page.Transfers
+		require.Len(t, transfers, 2)
+
+		assert.Equal(t, alice, transfers[0].From)
+		assert.Equal(t, bob, transfers[0].To)
+		assert.Equal(t, bob, transfers[1].From)
+
+		page, err = r.GetTokenTransfers(other, alice, "", 100, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Transfers, 1)
+		assert.Equal(t, "5", page.Transfers[0].Amount)
+	})
+
+	t.Run("token holders", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := r.GetTokenHolders(token, "", 100)
+		require.NoError(t, err)
+
+		// Bob burned the whole balance, so is no longer a holder
+		assert.Equal(
+			t,
+			&HolderPage{
+				Holders: []*Holder{
+					{Address: alice, Balance: "600"},
+					{Address: carol, Balance: "100"},
+				},
+			},
+			page,
+		)
+	})
+
+	t.Run("paginated holders", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := r.GetTokenHolders(token, "", 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*Holder{{Address: alice, Balance: "600"}}, first.Holders)
+		require.NotEmpty(t, first.Cursor)
+
+		second, err := r.GetTokenHolders(token, first.Cursor, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, []*Holder{{Address: carol, Balance: "100"}}, second.Holders)
+		assert.Empty(t, second.Cursor)
+
+		// Make sure the malformed cursors are rejected
+		_, err = r.GetTokenHolders(token, "00", 1)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+}
+
+func TestKeyHolder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		huge  = new(big.Int).Lsh(big.NewInt(1), rankSize*8)
+		large = new(big.Int).Sub(huge, big.NewInt(1))
+	)
+
+	// Make sure the larger balances are ranked first
+	keys := [][]byte{
+		keyHolder("token", huge, "g1a"),
+		keyHolder("token", large, "g1a"),
+		keyHolder("token", big.NewInt(256), "g1a"),
+		keyHolder("token", big.NewInt(255), "g1a"),
+		keyHolder("token", big.NewInt(255), "g1b"),
+	}
+
+	assert.True(t, slices.IsSortedFunc(keys, bytes.Compare))
+}
+
+func TestPlugin_FailedTx(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = "gno.land/r/demo/foo20"
+
+		txResult = newTxResult(
+			1,
+			0,
+			newEvent(eventMint, token, "to", "g1alice", "value", "1000"),
+		)
+	)
+
+	txResult.Response.Error = abci.StringError("execution failed")
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var (
+		p  = New()
+		wb = s.WriteBatch()
+	)
+
+	require.NoError(t, p.OnTx(plugins.NewStore(wb, p.Name()), txResult))
+	require.NoError(t, wb.Commit())
+
+	// Make sure failed transactions are not indexed
+	page, err := NewReader(s).GetTokenTransfers(token, "", "", 100, false)
+	require.NoError(t, err)
+
+	assert.Empty(t, page.Transfers)
+}
@@ -0,0 +1,67 @@
+package grc20
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixBalance         = 'b' // token balances, by token and address
+	prefixHolder          = 'h' // token holders, by token and inverted balance
+	prefixTransfer        = 't' // token transfers, by token
+	prefixAddressTransfer = 'a' // token transfers, by token and address
+
+	// positionSize is the size of the encoded transfer position
+	positionSize = 16
+
+	// rankSize is the size of the encoded (inverted) holder balance
+	rankSize = 32
+)
+
+func keyBalance(token, address string) []byte {
+	return plugins.Key(prefixBalance, token, address)
+}
+
+// keyHolderPrefix returns the key prefix of the token holders
+func keyHolderPrefix(token string) []byte {
+	return plugins.Key(prefixHolder, token)
+}
+
+// keyHolder returns the token holder key. Balances are inverted,
+// so the largest balances are the first ones when iterating.
+// The balances wider than the rank are ranked first
+func keyHolder(token string, balance *big.Int, address string) []byte {
+	rank := make([]byte, rankSize)
+	if balance.BitLen() <= rankSize*8 {
+		balance.FillBytes(rank)
+	} else {
+		for i := range rank {
+			rank[i] = 0xff
+		}
+	}
+
+	for i := range rank {
+		rank[i] = ^rank[i]
+	}
+
+	return append(append(keyHolderPrefix(token), rank...), address...)
+}
+
+// keyPosition appends the sortable transfer position to the key
+func keyPosition(key []byte, height int64, txIndex, eventIndex uint32) []byte {
+	key = binary.BigEndian.AppendUint64(key, uint64(height))
+	key = binary.BigEndian.AppendUint32(key, txIndex)
+	key = binary.BigEndian.AppendUint32(key, eventIndex)
+
+	return key
+}
+
+func keyTransfer(token string, height int64, txIndex, eventIndex uint32) []byte {
+	return keyPosition(plugins.Key(prefixTransfer, token), height, txIndex, eventIndex)
+}
+
+func keyAddressTransfer(token, address string, height int64, txIndex, eventIndex uint32) []byte {
+	return keyPosition(plugins.Key(prefixAddressTransfer, token, address), height, txIndex, eventIndex)
+}
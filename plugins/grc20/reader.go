@@ -0,0 +1,133 @@
+package grc20
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Reader reads the indexed GRC20 token data
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new GRC20 token data reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetTokenTransfers returns a page of up to limit token transfers, oldest first (newest first if reversed).
+// If the address is set, only the transfers the address participated in are returned. The cursor
+// of the previous page is used to fetch the next one, with an empty cursor starting from the first transfer
+func (r *Reader) GetTokenTransfers(token, address, cursor string, limit int, reverse bool) (*TransferPage, error) {
+	prefix := plugins.Key(prefixTransfer, token)
+	if address != "" {
+		prefix = plugins.Key(prefixAddressTransfer, token, address)
+	}
+
+	it, err := r.reader.PageIterator(prefix, cursor, positionSize, reverse)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate token transfers, %w", err)
+	}
+
+	defer it.Close()
+
+	page := &TransferPage{
+		Transfers: make([]*Transfer, 0),
+	}
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page.Transfers) == limit {
+			// There are more transfers, point the cursor to the next one
+			page.Cursor = cursors.Encode(kv.Key[len(prefix):])
+
+			break
+		}
+
+		var transfer *Transfer
+		if err := json.Unmarshal(kv.Value, &transfer); err != nil {
+			return nil, fmt.Errorf("unable to decode token transfer, %w", err)
+		}
+
+		page.Transfers = append(page.Transfers, transfer)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetTokenHolders returns a page of up to limit token holders, with the largest balances first.
+// The cursor of the previous page is used to fetch the next one,
+// with an empty cursor starting from the largest balance
+func (r *Reader) GetTokenHolders(token, cursor string, limit int) (*HolderPage, error) {
+	prefix := keyHolderPrefix(token)
+	from := prefix
+
+	if cursor != "" {
+		// The holder position is the balance rank, followed by the address
+		position, err := cursors.DecodeAtLeast(cursor, rankSize+1)
+		if err != nil {
+			return nil, fmt.Errorf("unable to iterate token holders, %w", err)
+		}
+
+		from = append(slices.Clone(prefix), position...)
+	}
+
+	it, err := r.reader.Iterator(from, plugins.KeyEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate token holders, %w", err)
+	}
+
+	defer it.Close()
+
+	page := &HolderPage{
+		Holders: make([]*Holder, 0),
+	}
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page.Holders) == limit {
+			// There are more holders, point the cursor to the next one
+			page.Cursor = cursors.Encode(kv.Key[len(prefix):])
+
+			break
+		}
+
+		// Strip the prefix and the balance rank
+		address := string(kv.Key[len(prefix)+rankSize:])
+
+		balance, err := getBalance(r.reader.Get(keyBalance(token, address)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch token balance, %w", err)
+		}
+
+		page.Holders = append(page.Holders, &Holder{
+			Address: address,
+			Balance: balance.String(),
+		})
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,33 @@
+package grc20
+
+// Transfer is a single GRC20 token movement.
+// Mints have no sender, and burns have no recipient
+type Transfer struct {
+	Token  string `json:"token"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+	TxHash string `json:"txHash"`
+	Height int64  `json:"height"`
+	Index  uint32 `json:"index"`
+}
+
+// Holder is a GRC20 token holder
+type Holder struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+}
+
+// TransferPage is a single page of token transfers
+type TransferPage struct {
+	Transfers []*Transfer `json:"transfers"`
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// HolderPage is a single page of token holders
+type HolderPage struct {
+	Holders []*Holder `json:"holders"`
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
@@ -0,0 +1,26 @@
+package plugins
+
+// keySeparator separates the key parts
+const keySeparator = 0x00
+
+// Key builds a plugin storage key from the record prefix and the key parts,
+// each one followed by a separator. Key parts must not contain the separator (0x00)
+func Key(prefix byte, parts ...string) []byte {
+	key := []byte{prefix, keySeparator}
+
+	for _, part := range parts {
+		key = append(key, part...)
+		key = append(key, keySeparator)
+	}
+
+	return key
+}
+
+// KeyEnd returns the smallest key greater than all the keys starting with the given key,
+// built using Key, for iterating over all the records with the key prefix
+func KeyEnd(key []byte) []byte {
+	end := append([]byte{}, key...)
+	end[len(end)-1]++
+
+	return end
+}
@@ -0,0 +1,28 @@
+package leaderboard
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixAccount = 'c' // account counters, by address
+	prefixRank    = 'r' // account rankings, by metric and inverted metric value
+)
+
+func keyAccount(address string) []byte {
+	return plugins.Key(prefixAccount, address)
+}
+
+// keyRankPrefix returns the key prefix of the account ranking by the metric
+func keyRankPrefix(metric string) []byte {
+	return plugins.Key(prefixRank, metric)
+}
+
+// keyRank returns the account ranking key. Values are inverted,
+// so the highest ranked accounts are the first ones when iterating
+func keyRank(metric string, value uint64, address string) []byte {
+	return append(binary.BigEndian.AppendUint64(keyRankPrefix(metric), math.MaxUint64-value), address...)
+}
@@ -0,0 +1,183 @@
+// Package leaderboard maintains the aggregate activity counters of addresses
+// (transactions, gas used, realms deployed), ranked by each counter
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "leaderboard"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the account activity indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new account activity indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx updates the activity counters of the transaction signers.
+// Failed transactions count towards the transactions and the gas used,
+// and the gas is attributed to the first signer, which pays the fee
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	tx, err := p.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded are not indexed
+		return nil
+	}
+
+	var (
+		accounts = make(map[string]*Account)
+		previous = make(map[string]Account) // the saved counters, for updating the rankings
+		order    = make([]string, 0)
+	)
+
+	getAccount := func(address string) (*Account, error) {
+		if account, ok := accounts[address]; ok {
+			return account, nil
+		}
+
+		account, err := getAccount(store, address)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts[address] = account
+		previous[address] = *account
+		order = append(order, address)
+
+		return account, nil
+	}
+
+	for i, signer := range tx.GetSigners() {
+		if _, ok := accounts[signer.String()]; ok {
+			// Signers are counted once per transaction
+			continue
+		}
+
+		account, err := getAccount(signer.String())
+		if err != nil {
+			return fmt.Errorf("unable to fetch account, %w", err)
+		}
+
+		account.TxCount++
+
+		if i == 0 && txResult.Response.GasUsed > 0 {
+			account.GasUsed += uint64(txResult.Response.GasUsed)
+		}
+	}
+
+	if !txResult.Response.IsErr() {
+		for _, msg := range tx.GetMsgs() {
+			m, ok := msg.(vm.MsgAddPackage)
+			if !ok || m.Package == nil || !isRealmPath(m.Package.Path) {
+				continue
+			}
+
+			account, err := getAccount(m.Creator.String())
+			if err != nil {
+				return fmt.Errorf("unable to fetch account, %w", err)
+			}
+
+			account.RealmsDeployed++
+		}
+	}
+
+	for _, address := range order {
+		prev := previous[address]
+
+		if err := saveAccount(store, &prev, accounts[address]); err != nil {
+			return fmt.Errorf("unable to save account, %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
+
+// isRealmPath returns a flag indicating if the package path is a realm path (<domain>/r/...)
+func isRealmPath(path string) bool {
+	parts := strings.SplitN(path, "/", 3)
+
+	return len(parts) == 3 && parts[1] == "r"
+}
+
+// getter fetches the saved plugin values
+type getter interface {
+	Get(key []byte) ([]byte, error)
+}
+
+// getAccount fetches the saved account counters. Missing accounts are empty
+func getAccount(store getter, address string) (*Account, error) {
+	raw, err := store.Get(keyAccount(address))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return &Account{Address: address}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var account *Account
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("unable to decode account, %w", err)
+	}
+
+	return account, nil
+}
+
+// saveAccount saves the account counters, and moves the account in the changed rankings
+func saveAccount(store plugins.Store, previous, account *Account) error {
+	encoded, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Set(keyAccount(account.Address), encoded); err != nil {
+		return err
+	}
+
+	for _, metric := range Metrics {
+		prev, current := previous.metric(metric), account.metric(metric)
+		if prev == current {
+			continue
+		}
+
+		if prev != 0 {
+			if err := store.Delete(keyRank(metric, prev, account.Address)); err != nil {
+				return err
+			}
+		}
+
+		if err := store.Set(keyRank(metric, current, account.Address), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given gas usage and messages
+func newTxResult(gasUsed int64, msgs ...std.Msg) *types.TxResult {
+	return &types.TxResult{
+		Tx: amino.MustMarshal(std.Tx{Msgs: msgs}),
+		Response: abci.ResponseDeliverTx{
+			GasUsed: gasUsed,
+		},
+	}
+}
+
+func TestIsRealmPath(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isRealmPath("gno.land/r/demo/boards"))
+	assert.False(t, isRealmPath("gno.land/p/demo/avl"))
+	assert.False(t, isRealmPath("gno.land/r"))
+}
+
+func TestPlugin_GetTopAccounts(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+		carol = crypto.Address{3}
+
+		send = func(from crypto.Address) bank.MsgSend {
+			return bank.MsgSend{FromAddress: from, ToAddress: carol}
+		}
+
+		deploy = func(creator crypto.Address, path string) vm.MsgAddPackage {
+			return vm.MsgAddPackage{Creator: creator, Package: &std.MemPackage{Path: path}}
+		}
+
+		failedTx = newTxResult(1000, deploy(bob, "gno.land/r/demo/failed"))
+
+		txResults = []*types.TxResult{
+			newTxResult(100, send(alice)),
+			newTxResult(100, send(alice), send(alice)),
+			newTxResult(50, send(alice), send(bob)),
+			newTxResult(300, deploy(bob, "gno.land/r/demo/boards"), deploy(bob, "gno.land/p/demo/avl")),
+			newTxResult(300, deploy(carol, "gno.land/r/demo/users")),
+			newTxResult(300, deploy(carol, "gno.land/r/demo/wallet")),
+			failedTx,
+		}
+	)
+
+	failedTx.Response.Error = abci.StringError("execution failed")
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	var (
+		p  = New(nil)
+		wb = s.WriteBatch()
+	)
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	reader := NewReader(s)
+
+	testTable := []struct {
+		name     string
+		metric   string
+		limit    int
+		expected []*Account
+	}{
+		{
+			"by transaction count",
+			MetricTxCount,
+			2,
+			[]*Account{
+				{Address: alice.String(), TxCount: 3, GasUsed: 250},
+				{Address: bob.String(), TxCount: 3, GasUsed: 1300, RealmsDeployed: 1},
+			},
+		},
+		{
+			"by gas used",
+			MetricGasUsed,
+			10,
+			[]*Account{
+				{Address: bob.String(), TxCount: 3, GasUsed: 1300, RealmsDeployed: 1},
+				{Address: carol.String(), TxCount: 2, GasUsed: 600, RealmsDeployed: 2},
+				{Address: alice.String(), TxCount: 3, GasUsed: 250},
+			},
+		},
+		{
+			"by realms deployed",
+			MetricRealmsDeployed,
+			10,
+			[]*Account{
+				{Address: carol.String(), TxCount: 2, GasUsed: 600, RealmsDeployed: 2},
+				{Address: bob.String(), TxCount: 3, GasUsed: 1300, RealmsDeployed: 1},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			accounts, err := reader.GetTopAccounts(testCase.metric, testCase.limit)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, accounts)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package leaderboard
+
+import (
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Reader reads the indexed account activity
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new account activity reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetTopAccounts returns up to limit accounts with the highest metric value, highest first
+func (r *Reader) GetTopAccounts(metric string, limit int) ([]*Account, error) {
+	prefix := keyRankPrefix(metric)
+
+	it, err := r.reader.Iterator(prefix, plugins.KeyEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate account rankings, %w", err)
+	}
+
+	defer it.Close()
+
+	addresses := make([]string, 0)
+
+	for it.Next() && len(addresses) < limit {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		// Strip the prefix and the inverted metric value
+		addresses = append(addresses, string(kv.Key[len(prefix)+8:]))
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*Account, 0, len(addresses))
+
+	for _, address := range addresses {
+		account, err := getAccount(r.reader, address)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch account, %w", err)
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
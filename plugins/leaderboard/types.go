@@ -0,0 +1,42 @@
+package leaderboard
+
+const (
+	MetricTxCount        = "txCount"
+	MetricGasUsed        = "gasUsed"
+	MetricRealmsDeployed = "realmsDeployed"
+)
+
+// Metrics are the account ranking metrics
+var Metrics = []string{
+	MetricTxCount,
+	MetricGasUsed,
+	MetricRealmsDeployed,
+}
+
+// Account are the aggregate activity counters of an address
+type Account struct {
+	Address string `json:"address"`
+
+	// TxCount is the number of transactions signed by the address
+	TxCount uint64 `json:"txCount"`
+
+	// GasUsed is the gas used by the transactions the address paid for
+	GasUsed uint64 `json:"gasUsed"`
+
+	// RealmsDeployed is the number of realms deployed by the address
+	RealmsDeployed uint64 `json:"realmsDeployed"`
+}
+
+// metric returns the value of the account metric
+func (a *Account) metric(metric string) uint64 {
+	switch metric {
+	case MetricTxCount:
+		return a.TxCount
+	case MetricGasUsed:
+		return a.GasUsed
+	case MetricRealmsDeployed:
+		return a.RealmsDeployed
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,34 @@
+package realmevents
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixRealm     = 'r' // events, by realm
+	prefixRealmType = 't' // events, by realm and type
+
+	// positionSize is the size of the encoded event position
+	positionSize = 16
+)
+
+// keyPosition appends the sortable event position to the key
+func keyPosition(key []byte, event *Event) []byte {
+	key = binary.BigEndian.AppendUint64(key, uint64(event.Height))
+	key = binary.BigEndian.AppendUint32(key, event.Index)
+	key = binary.BigEndian.AppendUint32(key, event.EventIndex)
+
+	return key
+}
+
+// keyEventsPrefix returns the key prefix for the realm events,
+// optionally limited to the given type
+func keyEventsPrefix(realm, eventType string) []byte {
+	if eventType == "" {
+		return plugins.Key(prefixRealm, realm)
+	}
+
+	return plugins.Key(prefixRealmType, realm, eventType)
+}
@@ -0,0 +1,67 @@
+package realmevents
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Reader reads the indexed realm events
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new realm event reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetEvents returns a page of up to limit realm events, oldest first (newest first if reversed),
+// optionally limited to the given type. The cursor of the previous page
+// is used to fetch the next one, with an empty cursor starting from the first event
+func (r *Reader) GetEvents(realm, eventType, cursor string, limit int, reverse bool) (*Page, error) {
+	prefix := keyEventsPrefix(realm, eventType)
+
+	it, err := r.reader.PageIterator(prefix, cursor, positionSize, reverse)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate realm events, %w", err)
+	}
+
+	defer it.Close()
+
+	page := &Page{
+		Events: make([]*Event, 0),
+	}
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page.Events) == limit {
+			// There are more events, point the cursor to the next one
+			page.Cursor = cursors.Encode(kv.Key[len(prefix):])
+
+			break
+		}
+
+		var event *Event
+		if err := json.Unmarshal(kv.Value, &event); err != nil {
+			return nil, fmt.Errorf("unable to decode realm event, %w", err)
+		}
+
+		page.Events = append(page.Events, event)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
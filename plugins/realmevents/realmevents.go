@@ -0,0 +1,54 @@
+// Package realmevents indexes the events emitted by realms (std.Emit),
+// by realm path and event type
+package realmevents
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "realmevents"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the realm event indexer plugin
+type Plugin struct{}
+
+// New creates a new realm event indexer plugin
+func New() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the realm events emitted in the transaction
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	for _, event := range FromTxResult(txResult) {
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("unable to encode realm event, %w", err)
+		}
+
+		for _, prefix := range [][]byte{
+			keyEventsPrefix(event.Realm, ""),
+			keyEventsPrefix(event.Realm, event.Type),
+		} {
+			if err := store.Set(keyPosition(prefix, event), encoded); err != nil {
+				return fmt.Errorf("unable to save realm event, %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
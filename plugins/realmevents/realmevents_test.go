@@ -0,0 +1,169 @@
+package realmevents
+
+import (
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given events
+func newTxResult(height int64, index uint32, events ...abci.Event) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     []byte{byte(height), byte(index)},
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: events,
+			},
+		},
+	}
+}
+
+// indexTxs indexes the transactions into a fresh storage
+func indexTxs(t *testing.T, txResults ...*types.TxResult) *Reader {
+	t.Helper()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	var (
+		p  = New()
+		wb = db.WriteBatch()
+	)
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	return NewReader(db)
+}
+
+func TestPlugin_GetEvents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		boards = "gno.land/r/demo/boards"
+		users  = "gno.land/r/demo/users"
+
+		failedTx = newTxResult(3, 0, mock.GnoEvent{Type: "PostCreated", PkgPath: boards})
+	)
+
+	failedTx.Response.Error = abci.StringError("tx failed")
+
+	reader := indexTxs(
+		t,
+		newTxResult(
+			1,
+			0,
+			mock.GnoEvent{
+				Type:    "BoardCreated",
+				PkgPath: boards,
+				Func:    "CreateBoard",
+				Attrs:   []decode.EventAttribute{{Key: "id", Value: "1"}},
+			},
+			mock.GnoEvent{Type: "UserRegistered", PkgPath: users},
+		),
+		newTxResult(2, 0, mock.GnoEvent{Type: "PostCreated", PkgPath: boards}),
+		newTxResult(2, 1, mock.GnoEvent{Type: "PostCreated", PkgPath: boards}),
+		failedTx,
+	)
+
+	t.Run("all realm events", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetEvents(boards, "", "", 10, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Events, 3)
+		assert.Empty(t, page.Cursor)
+
+		assert.Equal(t, "BoardCreated", page.Events[0].Type)
+		assert.Equal(t, "CreateBoard", page.Events[0].Func)
+		assert.Equal(t, "1", page.Events[0].Attrs[0].Value)
+		assert.Equal(t, int64(1), page.Events[0].Height)
+
+		assert.Equal(t, int64(2), page.Events[2].Height)
+		assert.Equal(t, uint32(1), page.Events[2].Index)
+	})
+
+	t.Run("events by type", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetEvents(boards, "PostCreated", "", 10, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Events, 2)
+
+		for _, event := range page.Events {
+			assert.Equal(t, "PostCreated", event.Type)
+		}
+	})
+
+	t.Run("paginated events", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := reader.GetEvents(boards, "", "", 2, false)
+		require.NoError(t, err)
+
+		require.Len(t, first.Events, 2)
+		require.NotEmpty(t, first.Cursor)
+
+		second, err := reader.GetEvents(boards, "", first.Cursor, 2, false)
+		require.NoError(t, err)
+
+		require.Len(t, second.Events, 1)
+		assert.Empty(t, second.Cursor)
+
+		assert.Equal(t, uint32(1), second.Events[0].Index)
+	})
+
+	t.Run("newest first", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := reader.GetEvents(boards, "", "", 2, true)
+		require.NoError(t, err)
+
+		require.Len(t, first.Events, 2)
+		require.NotEmpty(t, first.Cursor)
+
+		second, err := reader.GetEvents(boards, "", first.Cursor, 2, true)
+		require.NoError(t, err)
+
+		require.Len(t, second.Events, 1)
+		assert.Empty(t, second.Cursor)
+
+		assert.Equal(t, uint32(0), second.Events[0].Index)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reader.GetEvents(boards, "", "not hex", 2, false)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+
+	t.Run("unknown realm", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetEvents("gno.land/r/demo/unknown", "", "", 10, false)
+		require.NoError(t, err)
+
+		assert.Empty(t, page.Events)
+	})
+}
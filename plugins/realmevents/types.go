@@ -0,0 +1,62 @@
+package realmevents
+
+import (
+	"encoding/base64"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+// Event is a realm event (std.Emit), along with its position in the chain
+type Event struct {
+	Realm      string                  `json:"realm"`
+	Type       string                  `json:"type"`
+	Func       string                  `json:"func"`
+	Attrs      []decode.EventAttribute `json:"attrs"`
+	TxHash     string                  `json:"txHash"`
+	Height     int64                   `json:"height"`
+	Index      uint32                  `json:"index"`
+	EventIndex uint32                  `json:"eventIndex"`
+}
+
+// Matches returns a flag indicating if the event was emitted by the realm,
+// with the given type. Empty values match any realm or type
+func (e *Event) Matches(realm, eventType string) bool {
+	return (realm == "" || e.Realm == realm) && (eventType == "" || e.Type == eventType)
+}
+
+// Page is a single page of realm events
+type Page struct {
+	Events []*Event `json:"events"`
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// FromTxResult returns the realm events emitted in the transaction, in order
+func FromTxResult(txResult *types.TxResult) []*Event {
+	if txResult.Response.IsErr() {
+		return nil
+	}
+
+	var (
+		realmEvents = decode.Events(txResult.Response)
+		txHash      = base64.StdEncoding.EncodeToString(txResult.Tx.Hash())
+		events      = make([]*Event, 0, len(realmEvents))
+	)
+
+	for index, realmEvent := range realmEvents {
+		events = append(events, &Event{
+			Realm:      realmEvent.PkgPath,
+			Type:       realmEvent.Type,
+			Func:       realmEvent.Func,
+			Attrs:      realmEvent.Attrs,
+			TxHash:     txHash,
+			Height:     txResult.Height,
+			Index:      txResult.Index,
+			EventIndex: uint32(index),
+		})
+	}
+
+	return events
+}
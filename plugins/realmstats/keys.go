@@ -0,0 +1,58 @@
+package realmstats
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixRealm     = 's' // all-time realm counters, by path
+	prefixDay       = 'd' // daily realm counters, by path and day
+	prefixCaller    = 'c' // realm caller markers, by path and caller
+	prefixDayCaller = 'u' // daily realm caller markers, by path, day and caller
+	prefixRank      = 'r' // realm rankings, by metric and inverted metric value
+	prefixLatest    = 'l' // latest indexed day
+)
+
+func keyRealm(path string) []byte {
+	return plugins.Key(prefixRealm, path)
+}
+
+// keyDayPrefix returns the key prefix of the daily realm counters
+func keyDayPrefix(path string) []byte {
+	return plugins.Key(prefixDay, path)
+}
+
+func keyDay(path string, day uint64) []byte {
+	return binary.BigEndian.AppendUint64(keyDayPrefix(path), day)
+}
+
+func keyCaller(path, caller string) []byte {
+	return append(plugins.Key(prefixCaller, path), caller...)
+}
+
+// keyDayCallerPrefix returns the key prefix of the daily realm caller markers
+func keyDayCallerPrefix(path string) []byte {
+	return plugins.Key(prefixDayCaller, path)
+}
+
+func keyDayCaller(path string, day uint64, caller string) []byte {
+	return append(binary.BigEndian.AppendUint64(keyDayCallerPrefix(path), day), caller...)
+}
+
+// keyRankPrefix returns the key prefix of the realm ranking by the metric
+func keyRankPrefix(metric string) []byte {
+	return plugins.Key(prefixRank, metric)
+}
+
+// keyRank returns the realm ranking key. Values are inverted,
+// so the highest ranked realms are the first ones when iterating
+func keyRank(metric string, value uint64, path string) []byte {
+	return append(binary.BigEndian.AppendUint64(keyRankPrefix(metric), math.MaxUint64-value), path...)
+}
+
+func keyLatest() []byte {
+	return plugins.Key(prefixLatest)
+}
@@ -0,0 +1,157 @@
+package realmstats
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Reader reads the indexed realm activity
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new realm activity reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetRealmStats returns the realm counters of the given number of latest (indexed) days,
+// or the all-time counters if the window is 0
+func (r *Reader) GetRealmStats(path string, days uint64) (*Stats, error) {
+	if days == 0 {
+		stats, err := getStats(r.reader, keyRealm(path), path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch realm stats, %w", err)
+		}
+
+		return stats, nil
+	}
+
+	stats := &Stats{Path: path}
+
+	raw, err := r.reader.Get(keyLatest())
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// Nothing is indexed yet
+		return stats, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch latest day, %w", err)
+	}
+
+	var (
+		latest = binary.BigEndian.Uint64(raw)
+		from   = latest - min(days, latest+1) + 1
+	)
+
+	stats.From = dayDate(from)
+
+	// Sum the daily counters
+	prefix := keyDayPrefix(path)
+
+	it, err := r.reader.Iterator(keyDay(path, from), plugins.KeyEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate daily realm stats, %w", err)
+	}
+
+	defer it.Close()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		daily, err := decodeStats(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.add(daily)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	// Count the distinct callers of the window
+	if stats.UniqueCallers, err = r.countCallers(path, from); err != nil {
+		return nil, fmt.Errorf("unable to count realm callers, %w", err)
+	}
+
+	return stats, nil
+}
+
+// countCallers returns the number of distinct realm callers, from the given day
+func (r *Reader) countCallers(path string, from uint64) (uint64, error) {
+	prefix := keyDayCallerPrefix(path)
+
+	it, err := r.reader.Iterator(binary.BigEndian.AppendUint64(prefix, from), plugins.KeyEnd(prefix))
+	if err != nil {
+		return 0, err
+	}
+
+	defer it.Close()
+
+	callers := make(map[string]struct{})
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return 0, err
+		}
+
+		// Strip the prefix and the day
+		callers[string(kv.Key[len(prefix)+8:])] = struct{}{}
+	}
+
+	return uint64(len(callers)), it.Error()
+}
+
+// GetTopRealms returns up to limit realms with the highest all-time metric value, highest first
+func (r *Reader) GetTopRealms(metric string, limit int) ([]*Stats, error) {
+	prefix := keyRankPrefix(metric)
+
+	it, err := r.reader.Iterator(prefix, plugins.KeyEnd(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate realm rankings, %w", err)
+	}
+
+	defer it.Close()
+
+	paths := make([]string, 0)
+
+	for it.Next() && len(paths) < limit {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		// Strip the prefix and the inverted metric value
+		paths = append(paths, string(kv.Key[len(prefix)+8:]))
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	realms := make([]*Stats, 0, len(paths))
+
+	for _, path := range paths {
+		stats, err := getStats(r.reader, keyRealm(path), path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch realm stats, %w", err)
+		}
+
+		realms = append(realms, stats)
+	}
+
+	return realms, nil
+}
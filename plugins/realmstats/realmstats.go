@@ -0,0 +1,261 @@
+// Package realmstats maintains the activity counters of realms
+// (calls, unique callers, gas used, failures), all-time and daily,
+// ranked by each all-time counter
+package realmstats
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "realmstats"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the realm activity indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new realm activity indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+func (p *Plugin) OnTx(_ plugins.Store, _ *types.TxResult) error {
+	return nil
+}
+
+// blockActivity is the activity of a realm in a single block
+type blockActivity struct {
+	stats   Stats
+	callers []string
+}
+
+// OnBlock updates the counters of the realms called in the block, on the block day.
+// The calls of failed transactions count towards the calls, gas used and failures
+func (p *Plugin) OnBlock(store plugins.Store, block *types.Block, results []*types.TxResult) error {
+	var (
+		activity = make(map[string]*blockActivity)
+		order    = make([]string, 0)
+	)
+
+	for _, txResult := range results {
+		tx, err := p.decoder.TxResult(txResult)
+		if err != nil {
+			// Transactions that can't be decoded are not indexed
+			continue
+		}
+
+		calls := make([]vm.MsgCall, 0)
+
+		for _, msg := range tx.GetMsgs() {
+			if call, ok := msg.(vm.MsgCall); ok {
+				calls = append(calls, call)
+			}
+		}
+
+		if len(calls) == 0 {
+			continue
+		}
+
+		var (
+			gasUsed = uint64(max(txResult.Response.GasUsed, 0))
+			share   = gasUsed / uint64(len(calls))
+		)
+
+		for i, call := range calls {
+			realm, ok := activity[call.PkgPath]
+			if !ok {
+				realm = &blockActivity{}
+				activity[call.PkgPath] = realm
+				order = append(order, call.PkgPath)
+			}
+
+			realm.stats.Calls++
+			realm.stats.GasUsed += share
+			realm.callers = append(realm.callers, call.Caller.String())
+
+			if i == 0 {
+				// The first call is attributed the remainder of the split
+				realm.stats.GasUsed += gasUsed % uint64(len(calls))
+			}
+
+			if txResult.Response.IsErr() {
+				realm.stats.Failures++
+			}
+		}
+	}
+
+	if len(order) == 0 {
+		return nil
+	}
+
+	day := dayOf(block.Time)
+
+	for _, path := range order {
+		if err := saveActivity(store, path, day, activity[path]); err != nil {
+			return fmt.Errorf("unable to save realm activity, %w", err)
+		}
+	}
+
+	latest, err := getLatestDay(store)
+	if err != nil {
+		return fmt.Errorf("unable to fetch latest day, %w", err)
+	}
+
+	if day <= latest {
+		return nil
+	}
+
+	return store.Set(keyLatest(), binary.BigEndian.AppendUint64(nil, day))
+}
+
+// saveActivity adds the block activity to the all-time and daily realm counters,
+// and moves the realm in the changed rankings
+func saveActivity(store plugins.Store, path string, day uint64, activity *blockActivity) error {
+	realm, err := getStats(store, keyRealm(path), path)
+	if err != nil {
+		return err
+	}
+
+	daily, err := getStats(store, keyDay(path, day), path)
+	if err != nil {
+		return err
+	}
+
+	previous := *realm
+
+	realm.add(&activity.stats)
+	daily.add(&activity.stats)
+
+	for _, caller := range activity.callers {
+		first, err := mark(store, keyCaller(path, caller))
+		if err != nil {
+			return err
+		}
+
+		if first {
+			realm.UniqueCallers++
+		}
+
+		if first, err = mark(store, keyDayCaller(path, day, caller)); err != nil {
+			return err
+		}
+
+		if first {
+			daily.UniqueCallers++
+		}
+	}
+
+	if err := setStats(store, keyDay(path, day), daily); err != nil {
+		return err
+	}
+
+	if err := setStats(store, keyRealm(path), realm); err != nil {
+		return err
+	}
+
+	for _, metric := range Metrics {
+		prev, current := previous.metric(metric), realm.metric(metric)
+		if prev == current {
+			continue
+		}
+
+		if prev != 0 {
+			if err := store.Delete(keyRank(metric, prev, path)); err != nil {
+				return err
+			}
+		}
+
+		if err := store.Set(keyRank(metric, current, path), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mark saves the marker under the key,
+// returning a flag indicating if it wasn't saved before
+func mark(store plugins.Store, key []byte) (bool, error) {
+	_, err := store.Get(key)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return false, err
+	}
+
+	return true, store.Set(key, []byte{})
+}
+
+// getter fetches the saved plugin values
+type getter interface {
+	Get(key []byte) ([]byte, error)
+}
+
+// getStats fetches the saved realm counters. Missing counters are empty
+func getStats(store getter, key []byte, path string) (*Stats, error) {
+	raw, err := store.Get(key)
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return &Stats{Path: path}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeStats(raw)
+}
+
+func decodeStats(raw []byte) (*Stats, error) {
+	var stats *Stats
+	if err := json.Unmarshal(raw, &stats); err != nil {
+		return nil, fmt.Errorf("unable to decode realm stats, %w", err)
+	}
+
+	return stats, nil
+}
+
+// setStats saves the realm counters
+func setStats(store plugins.Store, key []byte, stats *Stats) error {
+	encoded, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	return store.Set(key, encoded)
+}
+
+// getLatestDay fetches the latest indexed day, if any
+func getLatestDay(store getter) (uint64, error) {
+	raw, err := store.Get(keyLatest())
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint64(raw), nil
+}
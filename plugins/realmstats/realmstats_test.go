@@ -0,0 +1,177 @@
+package realmstats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given gas usage and messages
+func newTxResult(gasUsed int64, msgs ...std.Msg) *types.TxResult {
+	return &types.TxResult{
+		Tx: amino.MustMarshal(std.Tx{Msgs: msgs}),
+		Response: abci.ResponseDeliverTx{
+			GasUsed: gasUsed,
+		},
+	}
+}
+
+func TestPlugin_RealmStats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+
+		boards = "gno.land/r/demo/boards"
+		users  = "gno.land/r/demo/users"
+
+		call = func(caller crypto.Address, path string) vm.MsgCall {
+			return vm.MsgCall{Caller: caller, PkgPath: path, Func: "Call"}
+		}
+
+		day = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		failedTx = newTxResult(100, call(bob, users))
+
+		// The blocks are a day apart
+		blocks = []struct {
+			time    time.Time
+			results []*types.TxResult
+		}{
+			{
+				day,
+				[]*types.TxResult{
+					newTxResult(301, call(alice, boards), call(alice, boards), call(alice, users)),
+					newTxResult(100, call(bob, boards)),
+				},
+			},
+			{
+				day.Add(dayLength),
+				[]*types.TxResult{
+					newTxResult(50, call(alice, users)),
+					failedTx,
+				},
+			},
+		}
+	)
+
+	failedTx.Response.Error = abci.StringError("execution failed")
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	p := New(nil)
+
+	for i, block := range blocks {
+		wb := s.WriteBatch()
+
+		require.NoError(
+			t,
+			p.OnBlock(
+				plugins.NewStore(wb, Name),
+				&types.Block{Header: types.Header{Height: int64(i + 1), Time: block.time}},
+				block.results,
+			),
+		)
+
+		require.NoError(t, wb.Commit())
+	}
+
+	reader := NewReader(s)
+
+	t.Run("all-time stats", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetRealmStats(boards, 0)
+		require.NoError(t, err)
+
+		// The remainder of the gas split is attributed to the first call
+		assert.Equal(
+			t,
+			&Stats{Path: boards, Calls: 3, UniqueCallers: 2, GasUsed: 301},
+			stats,
+		)
+
+		stats, err = reader.GetRealmStats(users, 0)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			&Stats{Path: users, Calls: 3, UniqueCallers: 2, GasUsed: 250, Failures: 1},
+			stats,
+		)
+	})
+
+	t.Run("windowed stats", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetRealmStats(users, 1)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			&Stats{Path: users, From: "2024-03-02", Calls: 2, UniqueCallers: 2, GasUsed: 150, Failures: 1},
+			stats,
+		)
+
+		stats, err = reader.GetRealmStats(boards, 1)
+		require.NoError(t, err)
+
+		assert.Equal(t, &Stats{Path: boards, From: "2024-03-02"}, stats)
+
+		// Make sure the callers are counted once across the days
+		stats, err = reader.GetRealmStats(users, 30)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2), stats.UniqueCallers)
+		assert.Equal(t, uint64(3), stats.Calls)
+	})
+
+	t.Run("unknown realm", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetRealmStats("gno.land/r/demo/unknown", 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, &Stats{Path: "gno.land/r/demo/unknown"}, stats)
+	})
+
+	t.Run("top realms", func(t *testing.T) {
+		t.Parallel()
+
+		realms, err := reader.GetTopRealms(MetricGasUsed, 10)
+		require.NoError(t, err)
+
+		require.Len(t, realms, 2)
+		assert.Equal(t, boards, realms[0].Path)
+		assert.Equal(t, users, realms[1].Path)
+
+		realms, err = reader.GetTopRealms(MetricFailures, 10)
+		require.NoError(t, err)
+
+		require.Len(t, realms, 1)
+		assert.Equal(t, users, realms[0].Path)
+
+		realms, err = reader.GetTopRealms(MetricCalls, 1)
+		require.NoError(t, err)
+
+		assert.Len(t, realms, 1)
+	})
+}
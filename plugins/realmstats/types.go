@@ -0,0 +1,77 @@
+package realmstats
+
+import (
+	"time"
+)
+
+const (
+	MetricCalls         = "calls"
+	MetricUniqueCallers = "uniqueCallers"
+	MetricGasUsed       = "gasUsed"
+	MetricFailures      = "failures"
+)
+
+// Metrics are the realm ranking metrics
+var Metrics = []string{
+	MetricCalls,
+	MetricUniqueCallers,
+	MetricGasUsed,
+	MetricFailures,
+}
+
+// Stats are the activity counters of a realm
+type Stats struct {
+	Path string `json:"path"`
+
+	// From is the first (UTC) day of the window the counters cover, if windowed
+	From string `json:"from,omitempty"`
+
+	// Calls is the number of calls to the realm functions (MsgCall messages)
+	Calls uint64 `json:"calls"`
+
+	// UniqueCallers is the number of distinct callers
+	UniqueCallers uint64 `json:"uniqueCallers"`
+
+	// GasUsed is the gas used by the calls. The gas of a transaction
+	// is split evenly among its calls
+	GasUsed uint64 `json:"gasUsed"`
+
+	// Failures is the number of calls in failed transactions
+	Failures uint64 `json:"failures"`
+}
+
+// metric returns the value of the realm metric
+func (s *Stats) metric(metric string) uint64 {
+	switch metric {
+	case MetricCalls:
+		return s.Calls
+	case MetricUniqueCallers:
+		return s.UniqueCallers
+	case MetricGasUsed:
+		return s.GasUsed
+	case MetricFailures:
+		return s.Failures
+	default:
+		return 0
+	}
+}
+
+// add adds the counters, other than the unique callers
+func (s *Stats) add(other *Stats) {
+	s.Calls += other.Calls
+	s.GasUsed += other.GasUsed
+	s.Failures += other.Failures
+}
+
+// dayLength is the length of the daily counters day
+const dayLength = 24 * time.Hour
+
+// dayOf returns the (UTC) day number of the time
+func dayOf(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(dayLength/time.Second)
+}
+
+// dayDate returns the date of the day number
+func dayDate(day uint64) string {
+	return time.Unix(int64(day*uint64(dayLength/time.Second)), 0).UTC().Format(time.DateOnly)
+}
@@ -0,0 +1,100 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"go.uber.org/multierr"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// replayBatchSize is the number of replayed blocks committed in a single batch
+const replayBatchSize = 1000
+
+// Replay runs the plugins over the saved blocks (and their transactions) of the height range [from, to],
+// in height order, as if the blocks were being saved. The plugin data is committed in batches.
+// Returns the number of replayed blocks
+func Replay(ctx context.Context, db storage.Storage, indexers []Indexer, from, to uint64) (uint64, error) {
+	it, err := db.BlockIterator(from, to+1)
+	if err != nil {
+		return 0, fmt.Errorf("unable to iterate blocks, %w", err)
+	}
+
+	defer it.Close()
+
+	var (
+		replayed uint64
+
+		wb = db.WriteBatch()
+	)
+
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return replayed, multierr.Append(err, wb.Rollback())
+		}
+
+		block, err := it.Value()
+		if err != nil {
+			return replayed, multierr.Append(err, wb.Rollback())
+		}
+
+		results, err := storage.BlockResults(db, block)
+		if err != nil {
+			return replayed, multierr.Append(err, wb.Rollback())
+		}
+
+		if err := runIndexers(wb, indexers, block, results); err != nil {
+			return replayed, multierr.Append(
+				fmt.Errorf("unable to replay block %d, %w", block.Height, err),
+				wb.Rollback(),
+			)
+		}
+
+		if replayed++; replayed%replayBatchSize != 0 {
+			continue
+		}
+
+		if err := commit(wb); err != nil {
+			return replayed, err
+		}
+
+		wb = db.WriteBatch()
+	}
+
+	if err := it.Error(); err != nil {
+		return replayed, multierr.Append(err, wb.Rollback())
+	}
+
+	return replayed, commit(wb)
+}
+
+// commit commits the replayed plugin data, closing the batch
+func commit(wb storage.Batch) error {
+	if err := wb.Commit(); err != nil {
+		return multierr.Append(fmt.Errorf("unable to commit plugin data, %w", err), wb.Rollback())
+	}
+
+	return wb.Rollback()
+}
+
+// runIndexers runs the plugins on the block and its transactions,
+// stopping at the first error
+func runIndexers(wb storage.Batch, indexers []Indexer, block *types.Block, results []*types.TxResult) error {
+	for _, p := range indexers {
+		store := NewStore(wb, p.Name())
+
+		for _, txResult := range results {
+			if err := p.OnTx(store, txResult); err != nil {
+				return fmt.Errorf("unable to run plugin %s on tx, %w", p.Name(), err)
+			}
+		}
+
+		if err := p.OnBlock(store, block, results); err != nil {
+			return fmt.Errorf("unable to run plugin %s on block, %w", p.Name(), err)
+		}
+	}
+
+	return nil
+}
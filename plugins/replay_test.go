@@ -0,0 +1,87 @@
+package plugins
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// countingIndexer counts the replayed blocks and transactions
+type countingIndexer struct {
+	heights []int64
+	txs     int
+}
+
+func (c *countingIndexer) Name() string {
+	return "counting"
+}
+
+func (c *countingIndexer) OnTx(_ Store, _ *types.TxResult) error {
+	c.txs++
+
+	return nil
+}
+
+func (c *countingIndexer) OnBlock(store Store, block *types.Block, results []*types.TxResult) error {
+	c.heights = append(c.heights, block.Height)
+
+	// Keep the number of transactions of the block
+	return store.Set(
+		binary.BigEndian.AppendUint64(nil, uint64(block.Height)),
+		binary.BigEndian.AppendUint64(nil, uint64(len(results))),
+	)
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	// Save the blocks, each with as many txs as its height
+	wb := db.WriteBatch()
+
+	for height := int64(1); height <= 4; height++ {
+		require.NoError(t, wb.SetBlock(&types.Block{Header: types.Header{Height: height, NumTxs: height}}))
+
+		for index := range uint32(height) {
+			require.NoError(t, wb.SetTx(&types.TxResult{Height: height, Index: index, Tx: []byte{byte(index)}}))
+		}
+	}
+
+	require.NoError(t, wb.SetLatestHeight(4))
+	require.NoError(t, wb.Commit())
+
+	indexer := &countingIndexer{}
+
+	replayed, err := Replay(context.Background(), db, []Indexer{indexer}, 2, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), replayed)
+	assert.Equal(t, []int64{2, 3}, indexer.heights)
+	assert.Equal(t, 5, indexer.txs)
+
+	// Make sure the plugin data is committed
+	reader := NewReader(db, indexer.Name())
+
+	for height := uint64(2); height <= 3; height++ {
+		raw, err := reader.Get(binary.BigEndian.AppendUint64(nil, height))
+		require.NoError(t, err)
+
+		assert.Equal(t, height, binary.BigEndian.Uint64(raw))
+	}
+
+	_, err = reader.Get(binary.BigEndian.AppendUint64(nil, 4))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+}
@@ -0,0 +1,25 @@
+package rollups
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixBucket = 'b' // rollup buckets, by interval and bucket number
+	prefixActive = 'a' // active address markers, by interval, bucket number and address
+)
+
+// keyBucketPrefix returns the key prefix of the interval rollup buckets
+func keyBucketPrefix(interval string) []byte {
+	return plugins.Key(prefixBucket, interval)
+}
+
+func keyBucket(interval string, bucket uint64) []byte {
+	return binary.BigEndian.AppendUint64(keyBucketPrefix(interval), bucket)
+}
+
+func keyActive(interval string, bucket uint64, address string) []byte {
+	return append(binary.BigEndian.AppendUint64(plugins.Key(prefixActive, interval), bucket), address...)
+}
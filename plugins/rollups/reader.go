@@ -0,0 +1,103 @@
+package rollups
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// Reader reads the indexed chain activity rollups
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new chain activity rollup reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetSeries returns the metric time series of the interval buckets
+// covering the time range, oldest first. Buckets with no activity are zero
+func (r *Reader) GetSeries(metric, interval string, from, to time.Time) ([]*Point, error) {
+	return r.series(interval, from, to, func(b *Bucket) uint64 {
+		return b.metric(metric)
+	})
+}
+
+// GetFeeSeries returns the paid fees time series of the denomination,
+// of the interval buckets covering the time range, oldest first
+func (r *Reader) GetFeeSeries(denom, interval string, from, to time.Time) ([]*Point, error) {
+	return r.series(interval, from, to, func(b *Bucket) uint64 {
+		return uint64(max(b.Fees[denom], 0))
+	})
+}
+
+// series returns the time series of the bucket values
+func (r *Reader) series(
+	interval string,
+	from,
+	to time.Time,
+	value func(*Bucket) uint64,
+) ([]*Point, error) {
+	var (
+		first = bucketOf(interval, from)
+		last  = bucketOf(interval, to)
+
+		prefix = keyBucketPrefix(interval)
+	)
+
+	it, err := r.reader.Iterator(keyBucket(interval, first), keyBucket(interval, last+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate rollup buckets, %w", err)
+	}
+
+	defer it.Close()
+
+	var (
+		points = make([]*Point, 0, last-first+1)
+		next   = first
+	)
+
+	// fill adds the empty points up to the bucket
+	fill := func(bucket uint64) {
+		for ; next < bucket; next++ {
+			points = append(points, &Point{Time: bucketTime(interval, next)})
+		}
+	}
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		saved, err := decodeBucket(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		bucket := binary.BigEndian.Uint64(kv.Key[len(prefix):])
+
+		fill(bucket)
+
+		points = append(points, &Point{
+			Time:  bucketTime(interval, bucket),
+			Value: value(saved),
+		})
+
+		next = bucket + 1
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	fill(last + 1)
+
+	return points, nil
+}
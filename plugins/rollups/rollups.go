@@ -0,0 +1,168 @@
+// Package rollups maintains the hourly and daily rollups of the chain activity
+// (blocks, transactions, gas used, fees, active addresses), for the time series queries
+package rollups
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "rollups"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the chain activity rollup indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new chain activity rollup indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+func (p *Plugin) OnTx(_ plugins.Store, _ *types.TxResult) error {
+	return nil
+}
+
+// OnBlock adds the block activity to the buckets of the block time.
+// Fees and gas are counted for the failed transactions as well,
+// and the transaction signers are the active addresses
+func (p *Plugin) OnBlock(store plugins.Store, block *types.Block, results []*types.TxResult) error {
+	var (
+		activity = &Bucket{
+			Fees:   make(map[string]int64),
+			Blocks: 1,
+			Txs:    uint64(len(results)),
+		}
+
+		addresses = make([]string, 0)
+		seen      = make(map[string]struct{})
+	)
+
+	for _, txResult := range results {
+		activity.GasUsed += uint64(max(txResult.Response.GasUsed, 0))
+
+		tx, err := p.decoder.TxResult(txResult)
+		if err != nil {
+			// Fees of transactions that can't be decoded are not indexed
+			continue
+		}
+
+		if fee := tx.Fee.GasFee; fee.Denom != "" {
+			activity.Fees[fee.Denom] += fee.Amount
+		}
+
+		for _, signer := range tx.GetSigners() {
+			address := signer.String()
+
+			if _, ok := seen[address]; !ok {
+				seen[address] = struct{}{}
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	for interval := range Intervals {
+		bucket := bucketOf(interval, block.Time)
+
+		if err := addActivity(store, interval, bucket, activity, addresses); err != nil {
+			return fmt.Errorf("unable to save %s rollup, %w", interval, err)
+		}
+	}
+
+	return nil
+}
+
+// addActivity adds the activity to the saved interval bucket,
+// counting the addresses not yet active in the bucket
+func addActivity(
+	store plugins.Store,
+	interval string,
+	bucket uint64,
+	activity *Bucket,
+	addresses []string,
+) error {
+	saved, err := getBucket(store, interval, bucket)
+	if err != nil {
+		return err
+	}
+
+	saved.add(activity)
+
+	for _, address := range addresses {
+		first, err := markActive(store, keyActive(interval, bucket, address))
+		if err != nil {
+			return err
+		}
+
+		if first {
+			saved.ActiveAddresses++
+		}
+	}
+
+	encoded, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	return store.Set(keyBucket(interval, bucket), encoded)
+}
+
+// markActive saves the active address marker under the key,
+// returning a flag indicating if it wasn't saved before
+func markActive(store plugins.Store, key []byte) (bool, error) {
+	_, err := store.Get(key)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return false, err
+	}
+
+	return true, store.Set(key, []byte{})
+}
+
+// getter fetches the saved plugin values
+type getter interface {
+	Get(key []byte) ([]byte, error)
+}
+
+// getBucket fetches the saved interval bucket. Missing buckets are empty
+func getBucket(store getter, interval string, bucket uint64) (*Bucket, error) {
+	raw, err := store.Get(keyBucket(interval, bucket))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return &Bucket{Time: bucketTime(interval, bucket)}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBucket(raw)
+}
+
+func decodeBucket(raw []byte) (*Bucket, error) {
+	var bucket *Bucket
+	if err := json.Unmarshal(raw, &bucket); err != nil {
+		return nil, fmt.Errorf("unable to decode rollup bucket, %w", err)
+	}
+
+	return bucket, nil
+}
@@ -0,0 +1,168 @@
+package rollups
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result of the sender, with the given gas usage and fee
+func newTxResult(sender crypto.Address, gasUsed int64, fee std.Coin) *types.TxResult {
+	return &types.TxResult{
+		Tx: amino.MustMarshal(std.Tx{
+			Msgs: []std.Msg{bank.MsgSend{FromAddress: sender, ToAddress: crypto.Address{0xff}}},
+			Fee:  std.NewFee(100_000, fee),
+		}),
+		Response: abci.ResponseDeliverTx{
+			GasUsed: gasUsed,
+		},
+	}
+}
+
+func TestPlugin_GetSeries(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+
+		start = time.Date(2024, time.March, 1, 22, 30, 0, 0, time.UTC)
+
+		failedTx = newTxResult(alice, 500, std.NewCoin("ugnot", 10))
+
+		// The blocks are in the 22h, 22h and 1h (next day) buckets
+		blocks = []struct {
+			time    time.Time
+			results []*types.TxResult
+		}{
+			{
+				start,
+				[]*types.TxResult{
+					newTxResult(alice, 100, std.NewCoin("ugnot", 1000)),
+					newTxResult(bob, 200, std.NewCoin("ufoo", 5)),
+					newTxResult(alice, 0, std.NewCoin("ugnot", 0)),
+				},
+			},
+			{
+				start.Add(10 * time.Minute),
+				nil,
+			},
+			{
+				start.Add(3 * time.Hour),
+				[]*types.TxResult{failedTx},
+			},
+		}
+	)
+
+	failedTx.Response.Error = abci.StringError("execution failed")
+
+	s, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	// The storage is used by the parallel subtests
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	p := New(nil)
+
+	for i, block := range blocks {
+		wb := s.WriteBatch()
+
+		require.NoError(
+			t,
+			p.OnBlock(
+				plugins.NewStore(wb, Name),
+				&types.Block{Header: types.Header{Height: int64(i + 1), Time: block.time}},
+				block.results,
+			),
+		)
+
+		require.NoError(t, wb.Commit())
+	}
+
+	reader := NewReader(s)
+
+	t.Run("hourly series", func(t *testing.T) {
+		t.Parallel()
+
+		points, err := reader.GetSeries(MetricTxs, IntervalHour, start.Add(-time.Hour), start.Add(3*time.Hour))
+		require.NoError(t, err)
+
+		// Make sure the buckets with no activity are zero
+		assert.Equal(
+			t,
+			[]*Point{
+				{Time: time.Date(2024, time.March, 1, 21, 0, 0, 0, time.UTC), Value: 0},
+				{Time: time.Date(2024, time.March, 1, 22, 0, 0, 0, time.UTC), Value: 3},
+				{Time: time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC), Value: 0},
+				{Time: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC), Value: 0},
+				{Time: time.Date(2024, time.March, 2, 1, 0, 0, 0, time.UTC), Value: 1},
+			},
+			points,
+		)
+
+		points, err = reader.GetSeries(MetricBlocks, IntervalHour, start, start)
+		require.NoError(t, err)
+
+		require.Len(t, points, 1)
+		assert.Equal(t, uint64(2), points[0].Value)
+	})
+
+	t.Run("daily series", func(t *testing.T) {
+		t.Parallel()
+
+		points, err := reader.GetSeries(MetricGasUsed, IntervalDay, start, start.Add(3*time.Hour))
+		require.NoError(t, err)
+
+		// The gas of the failed transactions is counted
+		assert.Equal(
+			t,
+			[]*Point{
+				{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Value: 300},
+				{Time: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC), Value: 500},
+			},
+			points,
+		)
+	})
+
+	t.Run("active address series", func(t *testing.T) {
+		t.Parallel()
+
+		points, err := reader.GetSeries(MetricActiveAddresses, IntervalDay, start, start.Add(3*time.Hour))
+		require.NoError(t, err)
+
+		// Make sure the addresses are counted once per bucket
+		require.Len(t, points, 2)
+		assert.Equal(t, uint64(2), points[0].Value)
+		assert.Equal(t, uint64(1), points[1].Value)
+	})
+
+	t.Run("fee series", func(t *testing.T) {
+		t.Parallel()
+
+		points, err := reader.GetFeeSeries("ugnot", IntervalDay, start, start.Add(3*time.Hour))
+		require.NoError(t, err)
+
+		require.Len(t, points, 2)
+		assert.Equal(t, uint64(1000), points[0].Value)
+		assert.Equal(t, uint64(10), points[1].Value)
+
+		points, err = reader.GetFeeSeries("ufoo", IntervalDay, start, start)
+		require.NoError(t, err)
+
+		require.Len(t, points, 1)
+		assert.Equal(t, uint64(5), points[0].Value)
+	})
+}
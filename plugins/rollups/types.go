@@ -0,0 +1,89 @@
+package rollups
+
+import (
+	"time"
+)
+
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+)
+
+// Intervals are the rollup bucket intervals, with their length
+var Intervals = map[string]time.Duration{
+	IntervalHour: time.Hour,
+	IntervalDay:  24 * time.Hour,
+}
+
+const (
+	MetricBlocks          = "blocks"
+	MetricTxs             = "txs"
+	MetricGasUsed         = "gasUsed"
+	MetricActiveAddresses = "activeAddresses"
+)
+
+// Bucket are the chain activity totals of a single (UTC) time bucket
+type Bucket struct {
+	// Fees are the paid transaction fees, per fee denomination
+	Fees map[string]int64 `json:"fees,omitempty"`
+
+	// Time is the start of the bucket
+	Time time.Time `json:"time"`
+
+	Blocks uint64 `json:"blocks"`
+	Txs    uint64 `json:"txs"`
+
+	// GasUsed is the gas used by the transactions, including the failed ones
+	GasUsed uint64 `json:"gasUsed"`
+
+	// ActiveAddresses is the number of distinct transaction signers
+	ActiveAddresses uint64 `json:"activeAddresses"`
+}
+
+// metric returns the value of the bucket metric
+func (b *Bucket) metric(metric string) uint64 {
+	switch metric {
+	case MetricBlocks:
+		return b.Blocks
+	case MetricTxs:
+		return b.Txs
+	case MetricGasUsed:
+		return b.GasUsed
+	case MetricActiveAddresses:
+		return b.ActiveAddresses
+	default:
+		return 0
+	}
+}
+
+// add adds the bucket totals, other than the active addresses
+func (b *Bucket) add(other *Bucket) {
+	b.Blocks += other.Blocks
+	b.Txs += other.Txs
+	b.GasUsed += other.GasUsed
+
+	for denom, amount := range other.Fees {
+		if b.Fees == nil {
+			b.Fees = make(map[string]int64, len(other.Fees))
+		}
+
+		b.Fees[denom] += amount
+	}
+}
+
+// Point is a single point of a time series
+type Point struct {
+	// Time is the start of the point bucket
+	Time  time.Time `json:"time"`
+	Value uint64    `json:"value"`
+}
+
+// bucketOf returns the interval bucket number of the time
+func bucketOf(interval string, t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(Intervals[interval]/time.Second)
+}
+
+// bucketTime returns the start of the interval bucket
+func bucketTime(interval string, bucket uint64) time.Time {
+	return time.Unix(int64(bucket*uint64(Intervals[interval]/time.Second)), 0).UTC()
+}
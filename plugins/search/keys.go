@@ -0,0 +1,43 @@
+package search
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixTerm  = 'w' // transaction positions, by term
+	prefixCount = 'c' // transaction count, by term
+	prefixTx    = 't' // matched transaction, by position
+
+	// positionSize is the size of the encoded transaction position
+	positionSize = 12
+)
+
+// position returns the sortable transaction position
+func position(height int64, index uint32) []byte {
+	key := binary.BigEndian.AppendUint64(nil, uint64(height))
+
+	return binary.BigEndian.AppendUint32(key, index)
+}
+
+// keyTermPrefix returns the key prefix for the transactions containing the term
+func keyTermPrefix(term string) []byte {
+	return plugins.Key(prefixTerm, term)
+}
+
+// keyTerm returns the key marking the term in the transaction at the position
+func keyTerm(term string, position []byte) []byte {
+	return append(keyTermPrefix(term), position...)
+}
+
+// keyCount returns the key for the number of transactions containing the term
+func keyCount(term string) []byte {
+	return plugins.Key(prefixCount, term)
+}
+
+// keyTx returns the key for the transaction at the position
+func keyTx(position []byte) []byte {
+	return append(plugins.Key(prefixTx), position...)
+}
@@ -0,0 +1,154 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// maxQueryTerms is the maximum number of terms in a single search query
+const maxQueryTerms = 10
+
+// ErrInvalidQuery is returned when the search query has no searchable terms, or too many
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Reader reads the full-text search index
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new full-text search reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// SearchTxs returns a page of up to limit transactions containing all the query terms, oldest first
+// (newest first if reversed). The cursor of the previous page is used to fetch the next one,
+// with an empty cursor starting from the first match
+func (r *Reader) SearchTxs(query, cursor string, limit int, reverse bool) (*Page, error) {
+	terms := Terms(query)
+	if len(terms) == 0 || len(terms) > maxQueryTerms {
+		return nil, ErrInvalidQuery
+	}
+
+	page := &Page{
+		Txs: make([]*Match, 0),
+	}
+
+	// Iterate over the rarest term, and check the others for each of its transactions
+	rarest, err := r.rarestTerm(terms)
+	if err != nil {
+		return nil, err
+	}
+
+	if rarest == "" {
+		// At least one of the terms is not indexed
+		return page, nil
+	}
+
+	prefix := keyTermPrefix(rarest)
+
+	it, err := r.reader.PageIterator(prefix, cursor, positionSize, reverse)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate search terms, %w", err)
+	}
+
+	defer it.Close()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		pos := kv.Key[len(prefix):]
+
+		matches, err := r.containsTerms(pos, terms, rarest)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matches {
+			continue
+		}
+
+		if len(page.Txs) == limit {
+			// There are more matches, point the cursor to the next one
+			page.Cursor = cursors.Encode(pos)
+
+			break
+		}
+
+		raw, err := r.reader.Get(keyTx(pos))
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch search match, %w", err)
+		}
+
+		var match *Match
+		if err := json.Unmarshal(raw, &match); err != nil {
+			return nil, fmt.Errorf("unable to decode search match, %w", err)
+		}
+
+		page.Txs = append(page.Txs, match)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// rarestTerm returns the term contained in the fewest transactions,
+// or an empty term if any of the terms is not indexed
+func (r *Reader) rarestTerm(terms []string) (string, error) {
+	var (
+		rarest      string
+		rarestCount uint64
+	)
+
+	for _, term := range terms {
+		count, err := getCount(r.reader.Get(keyCount(term)))
+		if err != nil {
+			return "", fmt.Errorf("unable to fetch search term count, %w", err)
+		}
+
+		if count == 0 {
+			return "", nil
+		}
+
+		if rarest == "" || count < rarestCount {
+			rarest, rarestCount = term, count
+		}
+	}
+
+	return rarest, nil
+}
+
+// containsTerms returns a flag indicating if the transaction at the position
+// contains all the terms, apart from the skipped (already matched) one
+func (r *Reader) containsTerms(pos []byte, terms []string, skip string) (bool, error) {
+	for _, term := range terms {
+		if term == skip {
+			continue
+		}
+
+		_, err := r.reader.Get(keyTerm(term, pos))
+		if errors.Is(err, storageErrors.ErrNotFound) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("unable to fetch search term, %w", err)
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,120 @@
+// Package search indexes the transaction memos and string call arguments
+// into an inverted index, for finding transactions by their human-readable content
+package search
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "search"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the full-text search indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new full-text search indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the terms found in the transaction memo and string call arguments
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	tx, err := p.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded are not searchable
+		return nil
+	}
+
+	texts := []string{tx.GetMemo()}
+
+	for _, msg := range tx.GetMsgs() {
+		if call, ok := msg.(vm.MsgCall); ok {
+			texts = append(texts, call.Args...)
+		}
+	}
+
+	terms := Terms(texts...)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	if len(terms) > maxTermsPerTx {
+		terms = terms[:maxTermsPerTx]
+	}
+
+	pos := position(txResult.Height, txResult.Index)
+
+	encoded, err := json.Marshal(&Match{
+		TxHash: base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+		Memo:   tx.GetMemo(),
+		Height: txResult.Height,
+		Index:  txResult.Index,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode search match, %w", err)
+	}
+
+	if err := store.Set(keyTx(pos), encoded); err != nil {
+		return fmt.Errorf("unable to save search match, %w", err)
+	}
+
+	for _, term := range terms {
+		if err := store.Set(keyTerm(term, pos), []byte{}); err != nil {
+			return fmt.Errorf("unable to save search term, %w", err)
+		}
+
+		count, err := getCount(store.Get(keyCount(term)))
+		if err != nil {
+			return fmt.Errorf("unable to fetch search term count, %w", err)
+		}
+
+		if err := store.Set(keyCount(term), binary.BigEndian.AppendUint64(nil, count+1)); err != nil {
+			return fmt.Errorf("unable to save search term count, %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
+
+// getCount decodes the term transaction count, with missing counts being 0
+func getCount(raw []byte, err error) (uint64, error) {
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(raw) != 8 {
+		return 0, fmt.Errorf("invalid search term count length %d", len(raw))
+	}
+
+	return binary.BigEndian.Uint64(raw), nil
+}
@@ -0,0 +1,176 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given memo and messages
+func newTxResult(height int64, index uint32, memo string, msgs ...std.Msg) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx: amino.MustMarshal(std.Tx{
+			Msgs: msgs,
+			Memo: memo,
+		}),
+	}
+}
+
+// newCall creates a new realm call message with the given arguments
+func newCall(args ...string) vm.MsgCall {
+	return vm.MsgCall{
+		Caller:  crypto.Address{1},
+		PkgPath: "gno.land/r/demo/boards",
+		Func:    "CreatePost",
+		Args:    args,
+	}
+}
+
+// indexTxs indexes the transactions into a fresh storage
+func indexTxs(t *testing.T, txResults ...*types.TxResult) *Reader {
+	t.Helper()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	var (
+		p  = New(nil)
+		wb = db.WriteBatch()
+	)
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	return NewReader(db)
+}
+
+// positions returns the positions of the matched transactions
+func positions(page *Page) [][2]int64 {
+	result := make([][2]int64, 0, len(page.Txs))
+
+	for _, tx := range page.Txs {
+		result = append(result, [2]int64{tx.Height, int64(tx.Index)})
+	}
+
+	return result
+}
+
+func TestTerms(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(
+		t,
+		[]string{"hello", "gno", "world", "über"},
+		Terms("Hello, Gno-World!", "a hello ÜBER"),
+	)
+}
+
+func TestPlugin_SearchTxs(t *testing.T) {
+	t.Parallel()
+
+	reader := indexTxs(
+		t,
+		newTxResult(1, 0, "Rent payment for March"),
+		newTxResult(1, 1, "", newCall("Weekly update", "payment received")),
+		newTxResult(2, 0, "march madness", bank.MsgSend{FromAddress: crypto.Address{1}}),
+		newTxResult(3, 0, "RENT payment, April"),
+		newTxResult(4, 0, ""),
+	)
+
+	t.Run("single term", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.SearchTxs("payment", "", 10, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 0}, {1, 1}, {3, 0}}, positions(page))
+		assert.Empty(t, page.Cursor)
+
+		assert.Equal(t, "Rent payment for March", page.Txs[0].Memo)
+		assert.NotEmpty(t, page.Txs[0].TxHash)
+	})
+
+	t.Run("all terms match", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.SearchTxs("rent PAYMENT", "", 10, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 0}, {3, 0}}, positions(page))
+	})
+
+	t.Run("unknown term", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.SearchTxs("payment unknown", "", 10, false)
+		require.NoError(t, err)
+
+		assert.Empty(t, page.Txs)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.SearchTxs("payment", "", 2, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 0}, {1, 1}}, positions(page))
+		require.NotEmpty(t, page.Cursor)
+
+		page, err = reader.SearchTxs("payment", page.Cursor, 2, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{3, 0}}, positions(page))
+		assert.Empty(t, page.Cursor)
+	})
+
+	t.Run("newest first", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.SearchTxs("payment", "", 2, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{3, 0}, {1, 1}}, positions(page))
+		require.NotEmpty(t, page.Cursor)
+
+		page, err = reader.SearchTxs("payment", page.Cursor, 2, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 0}}, positions(page))
+		assert.Empty(t, page.Cursor)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reader.SearchTxs("a !", "", 10, false)
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reader.SearchTxs("payment", "invalid", 10, false)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+}
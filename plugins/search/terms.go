@@ -0,0 +1,49 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// minTermLength is the minimum indexed term length, in characters
+	minTermLength = 2
+
+	// maxTermLength is the maximum indexed term length, in characters
+	maxTermLength = 64
+
+	// maxTermsPerTx is the maximum number of distinct terms indexed per transaction
+	maxTermsPerTx = 256
+)
+
+// Terms splits the text into its distinct, lowercase alphanumeric terms, in order of appearance.
+// Terms that are too short or too long are dropped
+func Terms(texts ...string) []string {
+	var (
+		terms = make([]string, 0)
+		seen  = make(map[string]struct{})
+	)
+
+	isSeparator := func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+
+	for _, text := range texts {
+		for _, term := range strings.FieldsFunc(strings.ToLower(text), isSeparator) {
+			length := utf8.RuneCountInString(term)
+			if length < minTermLength || length > maxTermLength {
+				continue
+			}
+
+			if _, ok := seen[term]; ok {
+				continue
+			}
+
+			seen[term] = struct{}{}
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,16 @@
+package search
+
+// Match is a transaction matching the search query
+type Match struct {
+	TxHash string `json:"txHash"`
+	Memo   string `json:"memo,omitempty"`
+	Height int64  `json:"height"`
+	Index  uint32 `json:"index"`
+}
+
+// Page is a single page of matching transactions
+type Page struct {
+	Txs []*Match `json:"txs"`
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
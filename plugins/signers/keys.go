@@ -0,0 +1,54 @@
+package signers
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixSigner = 'a' // transaction positions, by signer address
+	prefixPubKey = 'k' // transaction positions, by signer public key
+	prefixTx     = 't' // transaction signers, by position
+	prefixHash   = 'h' // transaction positions, by hash
+
+	// positionSize is the size of the encoded transaction position
+	positionSize = 12
+)
+
+// position returns the sortable transaction position
+func position(height int64, index uint32) []byte {
+	key := binary.BigEndian.AppendUint64(nil, uint64(height))
+
+	return binary.BigEndian.AppendUint32(key, index)
+}
+
+// keySignerPrefix returns the key prefix for the transactions signed by the address
+func keySignerPrefix(address string) []byte {
+	return plugins.Key(prefixSigner, address)
+}
+
+// keySigner returns the key marking the address as a signer of the transaction at the position
+func keySigner(address string, position []byte) []byte {
+	return append(keySignerPrefix(address), position...)
+}
+
+// keyPubKeyPrefix returns the key prefix for the transactions signed by the public key
+func keyPubKeyPrefix(pubKey string) []byte {
+	return plugins.Key(prefixPubKey, pubKey)
+}
+
+// keyPubKey returns the key marking the public key as a signer of the transaction at the position
+func keyPubKey(pubKey string, position []byte) []byte {
+	return append(keyPubKeyPrefix(pubKey), position...)
+}
+
+// keyTx returns the key for the signers of the transaction at the position
+func keyTx(position []byte) []byte {
+	return append(plugins.Key(prefixTx), position...)
+}
+
+// keyHash returns the key for the position of the transaction with the hash
+func keyHash(hash string) []byte {
+	return plugins.Key(prefixHash, hash)
+}
@@ -0,0 +1,112 @@
+package signers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Reader reads the transaction signer index
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new transaction signer reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetTxSigners returns the signers of the transaction with the hash,
+// or nil if the transaction is not indexed
+func (r *Reader) GetTxSigners(hash string) (*TxSigners, error) {
+	pos, err := r.reader.Get(keyHash(hash))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		//nolint:nilnil // The transaction is not indexed
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch transaction position, %w", err)
+	}
+
+	return r.getTx(pos)
+}
+
+// GetTxsBySigner returns a page of up to limit transactions signed by the address, oldest first
+// (newest first if reversed), either as a signing account or as a multisig co-signer. The cursor
+// of the previous page is used to fetch the next one, with an empty cursor starting from the first transaction
+func (r *Reader) GetTxsBySigner(address, cursor string, limit int, reverse bool) (*Page, error) {
+	return r.getTxs(keySignerPrefix(address), cursor, limit, reverse)
+}
+
+// GetTxsByPubKey returns a page of up to limit transactions signed by the (bech32) public key, oldest first
+// (newest first if reversed), either as a signature key or as a multisig member key. The cursor
+// of the previous page is used to fetch the next one, with an empty cursor starting from the first transaction
+func (r *Reader) GetTxsByPubKey(pubKey, cursor string, limit int, reverse bool) (*Page, error) {
+	return r.getTxs(keyPubKeyPrefix(pubKey), cursor, limit, reverse)
+}
+
+// getTxs returns a page of up to limit transactions indexed under the signer prefix
+func (r *Reader) getTxs(prefix []byte, cursor string, limit int, reverse bool) (*Page, error) {
+	page := &Page{
+		Txs: make([]*TxSigners, 0),
+	}
+
+	it, err := r.reader.PageIterator(prefix, cursor, positionSize, reverse)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate transaction signers, %w", err)
+	}
+
+	defer it.Close()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		pos := kv.Key[len(prefix):]
+
+		if len(page.Txs) == limit {
+			// There are more transactions, point the cursor to the next one
+			page.Cursor = cursors.Encode(pos)
+
+			break
+		}
+
+		tx, err := r.getTx(pos)
+		if err != nil {
+			return nil, err
+		}
+
+		page.Txs = append(page.Txs, tx)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// getTx returns the signers of the transaction at the position
+func (r *Reader) getTx(pos []byte) (*TxSigners, error) {
+	raw, err := r.reader.Get(keyTx(pos))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch transaction signers, %w", err)
+	}
+
+	var tx *TxSigners
+	if err := json.Unmarshal(raw, &tx); err != nil {
+		return nil, fmt.Errorf("unable to decode transaction signers, %w", err)
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,182 @@
+// Package signers indexes every signer participating in a transaction, including
+// the member keys of multisig accounts, so transactions can be queried by any of them,
+// either by their address or by their public key
+package signers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "signers"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the transaction signer indexer plugin
+type Plugin struct {
+	// decoder decodes the txs of the chain
+	decoder *decode.Decoder
+}
+
+// New creates a new transaction signer indexer plugin, decoding the txs with the chain decoder
+func New(decoder *decode.Decoder) *Plugin {
+	return &Plugin{
+		decoder: decoder,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// OnTx indexes the transaction under each of its signers and co-signers,
+// and each of the signature public keys
+func (p *Plugin) OnTx(store plugins.Store, txResult *types.TxResult) error {
+	tx, err := p.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded have no known signers
+		return nil
+	}
+
+	var (
+		hash = base64.StdEncoding.EncodeToString(txResult.Tx.Hash())
+		pos  = position(txResult.Height, txResult.Index)
+
+		signers = txSigners(tx)
+	)
+
+	signers.TxHash = hash
+	signers.Height = txResult.Height
+	signers.Index = txResult.Index
+
+	if len(signers.Signers) == 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(signers)
+	if err != nil {
+		return fmt.Errorf("unable to encode transaction signers, %w", err)
+	}
+
+	if err := store.Set(keyTx(pos), encoded); err != nil {
+		return fmt.Errorf("unable to save transaction signers, %w", err)
+	}
+
+	if err := store.Set(keyHash(hash), pos); err != nil {
+		return fmt.Errorf("unable to save transaction position, %w", err)
+	}
+
+	addresses := append([]string{}, signers.Signers...)
+	for _, coSigner := range signers.CoSigners {
+		addresses = append(addresses, coSigner.Address)
+	}
+
+	for _, address := range addresses {
+		if err := store.Set(keySigner(address, pos), []byte{}); err != nil {
+			return fmt.Errorf("unable to save transaction signer, %w", err)
+		}
+	}
+
+	for _, pubKey := range signers.PubKeys {
+		if err := store.Set(keyPubKey(pubKey, pos), []byte{}); err != nil {
+			return fmt.Errorf("unable to save transaction public key, %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Plugin) OnBlock(_ plugins.Store, _ *types.Block, _ []*types.TxResult) error {
+	return nil
+}
+
+// txSigners returns the signing accounts of the transaction, along with the
+// member keys of the multisig accounts. The signatures follow the signer order
+func txSigners(tx *std.Tx) *TxSigners {
+	var (
+		accounts   = tx.GetSigners()
+		signatures = tx.GetSignatures()
+
+		seen = make(map[string]struct{})
+
+		signers = &TxSigners{
+			Signers:   make([]string, 0, len(accounts)),
+			CoSigners: make([]*CoSigner, 0),
+			PubKeys:   make([]string, 0, len(signatures)),
+		}
+
+		memberKeys = make([]string, 0)
+		seenKeys   = make(map[string]struct{})
+	)
+
+	// addPubKey adds the public key to the signer keys, once
+	addPubKey := func(keys []string, pubKey crypto.PubKey) []string {
+		encoded := crypto.PubKeyToBech32(pubKey)
+
+		if _, ok := seenKeys[encoded]; ok {
+			return keys
+		}
+
+		seenKeys[encoded] = struct{}{}
+
+		return append(keys, encoded)
+	}
+
+	for i, account := range accounts {
+		address := account.String()
+
+		seen[address] = struct{}{}
+		signers.Signers = append(signers.Signers, address)
+
+		// The signatures of the accounts with a known public key can omit it
+		if i >= len(signatures) || signatures[i].PubKey == nil {
+			continue
+		}
+
+		signers.PubKeys = addPubKey(signers.PubKeys, signatures[i].PubKey)
+
+		for _, member := range multisigMembers(signatures[i].PubKey) {
+			memberKeys = addPubKey(memberKeys, member)
+
+			memberAddress := member.Address().String()
+
+			if _, ok := seen[memberAddress]; ok {
+				continue
+			}
+
+			seen[memberAddress] = struct{}{}
+			signers.CoSigners = append(signers.CoSigners, &CoSigner{
+				Address:  memberAddress,
+				Multisig: address,
+			})
+		}
+	}
+
+	signers.PubKeys = append(signers.PubKeys, memberKeys...)
+	signers.MultiSigner = len(signers.Signers) > 1 || len(signers.CoSigners) > 0
+
+	return signers
+}
+
+// multisigMembers returns the member keys of the multisig public key,
+// or nil if it is not a multisig key
+func multisigMembers(pubKey crypto.PubKey) []crypto.PubKey {
+	switch key := pubKey.(type) {
+	case multisig.PubKeyMultisigThreshold:
+		return key.PubKeys
+	case *multisig.PubKeyMultisigThreshold:
+		return key.PubKeys
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,258 @@
+package signers
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTxResult creates a new transaction result with the given signatures and messages
+func newTxResult(height int64, index uint32, signatures []std.Signature, msgs ...std.Msg) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx: amino.MustMarshal(std.Tx{
+			Msgs:       msgs,
+			Signatures: signatures,
+		}),
+	}
+}
+
+// newSend creates a new send message from the address
+func newSend(from crypto.Address) bank.MsgSend {
+	return bank.MsgSend{
+		FromAddress: from,
+		ToAddress:   crypto.Address{9},
+	}
+}
+
+// newPubKey creates a new public key from the seed
+func newPubKey(seed byte) crypto.PubKey {
+	var key secp256k1.PubKeySecp256k1
+
+	key[0] = seed
+
+	return key
+}
+
+// indexTxs indexes the transactions into a fresh storage
+func indexTxs(t *testing.T, txResults ...*types.TxResult) *Reader {
+	t.Helper()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	var (
+		p  = New(nil)
+		wb = db.WriteBatch()
+	)
+
+	for _, txResult := range txResults {
+		require.NoError(t, p.OnTx(plugins.NewStore(wb, Name), txResult))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	return NewReader(db)
+}
+
+// positions returns the positions of the page transactions
+func positions(page *Page) [][2]int64 {
+	result := make([][2]int64, 0, len(page.Txs))
+
+	for _, tx := range page.Txs {
+		result = append(result, [2]int64{tx.Height, int64(tx.Index)})
+	}
+
+	return result
+}
+
+func TestPlugin_Signers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+
+		carolKey = newPubKey(3)
+		daveKey  = newPubKey(4)
+
+		// The multisig account address is derived from its member keys
+		multisigAccount = crypto.Address{5}
+		multisigKey     = multisig.NewPubKeyMultisigThreshold(2, []crypto.PubKey{carolKey, daveKey})
+
+		txResults = []*types.TxResult{
+			newTxResult(1, 0, nil, newSend(alice)),
+			newTxResult(1, 1, nil, newSend(alice), newSend(bob)),
+			newTxResult(
+				2,
+				0,
+				[]std.Signature{{PubKey: newPubKey(1)}, {PubKey: multisigKey}},
+				newSend(bob),
+				newSend(multisigAccount),
+			),
+		}
+	)
+
+	reader := indexTxs(t, txResults...)
+
+	t.Run("single signer", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetTxsBySigner(alice.String(), "", 10, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 0}, {1, 1}}, positions(page))
+		assert.Empty(t, page.Cursor)
+
+		assert.False(t, page.Txs[0].MultiSigner)
+		assert.Equal(t, []string{alice.String()}, page.Txs[0].Signers)
+
+		// The transactions without signatures have no known public keys
+		assert.Empty(t, page.Txs[0].PubKeys)
+	})
+
+	t.Run("multiple signing accounts", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetTxsBySigner(bob.String(), "", 10, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 1}, {2, 0}}, positions(page))
+
+		assert.True(t, page.Txs[0].MultiSigner)
+		assert.Equal(t, []string{alice.String(), bob.String()}, page.Txs[0].Signers)
+		assert.Empty(t, page.Txs[0].CoSigners)
+	})
+
+	t.Run("multisig co-signers", func(t *testing.T) {
+		t.Parallel()
+
+		for _, key := range []crypto.PubKey{carolKey, daveKey} {
+			page, err := reader.GetTxsBySigner(key.Address().String(), "", 10, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, [][2]int64{{2, 0}}, positions(page))
+		}
+
+		page, err := reader.GetTxsBySigner(multisigAccount.String(), "", 10, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Txs, 1)
+
+		assert.Equal(
+			t,
+			[]*CoSigner{
+				{Address: carolKey.Address().String(), Multisig: multisigAccount.String()},
+				{Address: daveKey.Address().String(), Multisig: multisigAccount.String()},
+			},
+			page.Txs[0].CoSigners,
+		)
+	})
+
+	t.Run("public keys", func(t *testing.T) {
+		t.Parallel()
+
+		// The signature keys are followed by the multisig member keys
+		page, err := reader.GetTxsByPubKey(crypto.PubKeyToBech32(newPubKey(1)), "", 10, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{2, 0}}, positions(page))
+		assert.Equal(
+			t,
+			[]string{
+				crypto.PubKeyToBech32(newPubKey(1)),
+				crypto.PubKeyToBech32(multisigKey),
+				crypto.PubKeyToBech32(carolKey),
+				crypto.PubKeyToBech32(daveKey),
+			},
+			page.Txs[0].PubKeys,
+		)
+
+		for _, key := range []crypto.PubKey{multisigKey, carolKey, daveKey} {
+			page, err := reader.GetTxsByPubKey(crypto.PubKeyToBech32(key), "", 10, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, [][2]int64{{2, 0}}, positions(page))
+		}
+
+		page, err = reader.GetTxsByPubKey(crypto.PubKeyToBech32(newPubKey(9)), "", 10, false)
+		require.NoError(t, err)
+
+		assert.Empty(t, page.Txs)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetTxsBySigner(bob.String(), "", 1, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 1}}, positions(page))
+		require.NotEmpty(t, page.Cursor)
+
+		page, err = reader.GetTxsBySigner(bob.String(), page.Cursor, 1, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{2, 0}}, positions(page))
+		assert.Empty(t, page.Cursor)
+	})
+
+	t.Run("newest first", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetTxsBySigner(bob.String(), "", 1, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{2, 0}}, positions(page))
+		require.NotEmpty(t, page.Cursor)
+
+		page, err = reader.GetTxsBySigner(bob.String(), page.Cursor, 1, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][2]int64{{1, 1}}, positions(page))
+		assert.Empty(t, page.Cursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reader.GetTxsBySigner(bob.String(), "invalid", 1, false)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+
+	t.Run("transaction signers", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reader.GetTxsBySigner(alice.String(), "", 1, false)
+		require.NoError(t, err)
+
+		require.Len(t, page.Txs, 1)
+
+		signers, err := reader.GetTxSigners(page.Txs[0].TxHash)
+		require.NoError(t, err)
+
+		assert.Equal(t, page.Txs[0], signers)
+
+		signers, err = reader.GetTxSigners("unknown")
+		require.NoError(t, err)
+
+		assert.Nil(t, signers)
+	})
+}
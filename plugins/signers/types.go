@@ -0,0 +1,31 @@
+package signers
+
+// CoSigner is a member key of a multisig signing account
+type CoSigner struct {
+	Address  string `json:"address"`
+	Multisig string `json:"multisig"`
+}
+
+// TxSigners are all the signers participating in a transaction
+type TxSigners struct {
+	TxHash string `json:"txHash"`
+	// Signers are the signing accounts, in the transaction signature order
+	Signers []string `json:"signers"`
+	// CoSigners are the member keys of the multisig signing accounts, if any
+	CoSigners []*CoSigner `json:"coSigners"`
+	// PubKeys are the bech32 public keys of the signatures, in the transaction signature order,
+	// followed by the member keys of the multisig public keys
+	PubKeys []string `json:"pubKeys"`
+	// MultiSigner is set if the transaction has more than one signer,
+	// either as several signing accounts or as a multisig account
+	MultiSigner bool   `json:"multiSigner"`
+	Height      int64  `json:"height"`
+	Index       uint32 `json:"index"`
+}
+
+// Page is a single page of transactions signed by an address
+type Page struct {
+	Txs []*TxSigners `json:"txs"`
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
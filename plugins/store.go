@@ -0,0 +1,95 @@
+package plugins
+
+import (
+	"slices"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+var _ Store = &batchStore{}
+
+// batchStore is the plugin store that writes to the ongoing storage batch
+type batchStore struct {
+	batch  storage.Batch
+	plugin string
+}
+
+// NewStore creates a new plugin store on top of the given storage batch
+func NewStore(batch storage.Batch, plugin string) Store {
+	return &batchStore{
+		batch:  batch,
+		plugin: plugin,
+	}
+}
+
+func (s *batchStore) Get(key []byte) ([]byte, error) {
+	return s.batch.GetPluginValue(s.plugin, key)
+}
+
+func (s *batchStore) Set(key, value []byte) error {
+	return s.batch.SetPluginValue(s.plugin, key, value)
+}
+
+func (s *batchStore) Delete(key []byte) error {
+	return s.batch.DeletePluginValue(s.plugin, key)
+}
+
+// Reader is the read-only access to the committed plugin data,
+// used for serving the derived data
+type Reader struct {
+	reader storage.Reader
+	plugin string
+}
+
+// NewReader creates a new plugin data reader
+func NewReader(reader storage.Reader, plugin string) *Reader {
+	return &Reader{
+		reader: reader,
+		plugin: plugin,
+	}
+}
+
+// Get fetches the value under the given key
+func (r *Reader) Get(key []byte) ([]byte, error) {
+	return r.reader.GetPluginValue(r.plugin, key)
+}
+
+// Iterator iterates over the plugin key-value pairs, with keys in the [fromKey, toKey) range.
+// A nil toKey iterates until the end of the plugin data
+func (r *Reader) Iterator(fromKey, toKey []byte) (storage.Iterator[*storage.KeyValue], error) {
+	return r.reader.PluginIterator(r.plugin, fromKey, toKey)
+}
+
+// PageIterator iterates over the records of the prefix, keyed by their (fixed size) positions,
+// from the page cursor position if any. The reversed pages start from the last position
+func (r *Reader) PageIterator(
+	prefix []byte,
+	cursor string,
+	positionSize int,
+	reverse bool,
+) (storage.Iterator[*storage.KeyValue], error) {
+	from, to := prefix, KeyEnd(prefix)
+
+	if cursor != "" {
+		position, err := cursors.Decode(cursor, positionSize)
+		if err != nil {
+			return nil, err
+		}
+
+		key := append(slices.Clone(prefix), position...)
+
+		if reverse {
+			// The cursor position is the first one iterated, so the (exclusive) end follows it
+			to = append(key, 0)
+		} else {
+			from = key
+		}
+	}
+
+	if reverse {
+		return r.reader.ReversePluginIterator(r.plugin, from, to)
+	}
+
+	return r.reader.PluginIterator(r.plugin, from, to)
+}
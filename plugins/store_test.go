@@ -0,0 +1,62 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+)
+
+func TestStore_Namespace(t *testing.T) {
+	t.Parallel()
+
+	var (
+		plugin = "example"
+		key    = []byte("key")
+		value  = []byte("value")
+
+		setPlugin, getPlugin, deletePlugin string
+	)
+
+	batch := &mock.WriteBatch{
+		SetPluginValueFn: func(p string, k, v []byte) error {
+			setPlugin = p
+
+			assert.Equal(t, key, k)
+			assert.Equal(t, value, v)
+
+			return nil
+		},
+		GetPluginValueFn: func(p string, k []byte) ([]byte, error) {
+			getPlugin = p
+
+			assert.Equal(t, key, k)
+
+			return value, nil
+		},
+		DeletePluginValueFn: func(p string, k []byte) error {
+			deletePlugin = p
+
+			assert.Equal(t, key, k)
+
+			return nil
+		},
+	}
+
+	store := NewStore(batch, plugin)
+
+	require.NoError(t, store.Set(key, value))
+
+	saved, err := store.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, value, saved)
+
+	require.NoError(t, store.Delete(key))
+
+	// Make sure all operations are scoped to the plugin namespace
+	assert.Equal(t, plugin, setPlugin)
+	assert.Equal(t, plugin, getPlugin)
+	assert.Equal(t, plugin, deletePlugin)
+}
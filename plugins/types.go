@@ -0,0 +1,31 @@
+package plugins
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// Indexer is a custom indexing plugin, invoked for every saved block.
+// Plugins can derive app-specific data, saved in their own storage namespace
+type Indexer interface {
+	// Name returns the unique plugin name, used as its storage namespace
+	Name() string
+
+	// OnTx is called for each transaction saved, before its block's OnBlock
+	OnTx(store Store, txResult *types.TxResult) error
+
+	// OnBlock is called for each block saved, with its transaction results
+	OnBlock(store Store, block *types.Block, results []*types.TxResult) error
+}
+
+// Store is the plugin storage, scoped to the plugin namespace.
+// Changes are committed atomically with the indexed block data
+type Store interface {
+	// Get fetches the value under the given key, including uncommitted changes
+	Get(key []byte) ([]byte, error)
+
+	// Set saves the value under the given key
+	Set(key, value []byte) error
+
+	// Delete removes the value under the given key
+	Delete(key []byte) error
+}
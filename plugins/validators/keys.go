@@ -0,0 +1,46 @@
+package validators
+
+import (
+	"encoding/binary"
+
+	"github.com/gnolang/tx-indexer/plugins"
+)
+
+const (
+	prefixHeight = 'h' // validator set hash, by height
+	prefixSet    = 's' // validator sets, by hash
+	prefixChange = 'c' // validator set changes, by height
+	prefixLatest = 'l' // latest validator set hash
+
+	prefixProposer      = 'p' // block proposer, by height
+	prefixSigning       = 'g' // block signing records, by height
+	prefixLatestSigning = 'n' // latest block signing record height
+)
+
+func keyHeight(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixHeight), height)
+}
+
+func keySet(hash []byte) []byte {
+	return append(plugins.Key(prefixSet), hash...)
+}
+
+func keyChange(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixChange), height)
+}
+
+func keyLatest() []byte {
+	return plugins.Key(prefixLatest)
+}
+
+func keyProposer(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixProposer), height)
+}
+
+func keySigning(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(plugins.Key(prefixSigning), height)
+}
+
+func keyLatestSigning() []byte {
+	return plugins.Key(prefixLatestSigning)
+}
@@ -0,0 +1,187 @@
+package validators
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Reader reads the indexed validator sets
+type Reader struct {
+	reader *plugins.Reader
+}
+
+// NewReader creates a new validator set reader
+func NewReader(storage storage.Reader) *Reader {
+	return &Reader{
+		reader: plugins.NewReader(storage, Name),
+	}
+}
+
+// GetValidators returns the validator set at the height,
+// or nil if the height is not indexed
+func (r *Reader) GetValidators(height uint64) (*Set, error) {
+	hash, err := r.reader.Get(keyHeight(height))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		//nolint:nilnil // The height is not indexed
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch validator set hash, %w", err)
+	}
+
+	set, err := getSet(r.reader.Get(keySet(hash)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch validator set, %w", err)
+	}
+
+	return set, nil
+}
+
+// GetValidatorChanges returns up to limit validator set changes in the [fromHeight, toHeight) range,
+// oldest first. A 0 toHeight returns the changes until the latest indexed height
+func (r *Reader) GetValidatorChanges(fromHeight, toHeight uint64, limit int) ([]*Change, error) {
+	var to []byte
+	if toHeight != 0 {
+		to = keyChange(toHeight)
+	} else {
+		to = plugins.KeyEnd(plugins.Key(prefixChange))
+	}
+
+	it, err := r.reader.Iterator(keyChange(fromHeight), to)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate validator set changes, %w", err)
+	}
+
+	defer it.Close()
+
+	changes := make([]*Change, 0)
+
+	for len(changes) < limit && it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var change *Change
+		if err := json.Unmarshal(kv.Value, &change); err != nil {
+			return nil, fmt.Errorf("unable to decode validator set change, %w", err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// GetSigning returns the signing record of the block at the height,
+// or nil if it is not indexed
+func (r *Reader) GetSigning(height uint64) (*Signing, error) {
+	raw, err := r.reader.Get(keySigning(height))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		//nolint:nilnil // The height is not indexed
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch block signing, %w", err)
+	}
+
+	var signing *Signing
+	if err := json.Unmarshal(raw, &signing); err != nil {
+		return nil, fmt.Errorf("unable to decode block signing, %w", err)
+	}
+
+	return signing, nil
+}
+
+// GetSigningStats returns the signing statistics of the validator,
+// over the window of the latest indexed blocks
+func (r *Reader) GetSigningStats(address string, window uint64) (*SigningStats, error) {
+	stats := &SigningStats{
+		Address: address,
+	}
+
+	raw, err := r.reader.Get(keyLatestSigning())
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return stats, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch latest block signing height, %w", err)
+	}
+
+	if window == 0 {
+		return stats, nil
+	}
+
+	latest := binary.BigEndian.Uint64(raw)
+
+	var from uint64
+	if latest >= window {
+		from = latest - window + 1
+	}
+
+	it, err := r.reader.Iterator(keySigning(from), keySigning(latest+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate block signings, %w", err)
+	}
+
+	defer it.Close()
+
+	for it.Next() {
+		kv, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		var signing *Signing
+		if err := json.Unmarshal(kv.Value, &signing); err != nil {
+			return nil, fmt.Errorf("unable to decode block signing, %w", err)
+		}
+
+		if stats.FromHeight == 0 {
+			stats.FromHeight = signing.Height
+		}
+
+		stats.ToHeight = signing.Height
+
+		if signing.Proposer == address {
+			stats.Proposed++
+		}
+
+		stats.Signed += count(signing.Signed, address)
+		stats.Missed += count(signing.Missed, address)
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	if total := stats.Signed + stats.Missed; total > 0 {
+		stats.Uptime = float64(stats.Signed) / float64(total)
+	}
+
+	return stats, nil
+}
+
+// count returns 1 if the address is in the list, 0 otherwise
+func count(addresses []string, address string) uint64 {
+	for _, a := range addresses {
+		if a == address {
+			return 1
+		}
+	}
+
+	return 0
+}
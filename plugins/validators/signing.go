@@ -0,0 +1,92 @@
+package validators
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// indexSigning saves the block proposer, and the signing record of the previous block.
+// The signing record is only saved if the previous block is indexed, as the
+// missed signatures are derived from its validator set
+func indexSigning(store plugins.Store, block *types.Block) error {
+	height := uint64(block.Height)
+
+	if err := store.Set(keyProposer(height), []byte(block.ProposerAddress.String())); err != nil {
+		return fmt.Errorf("unable to save block proposer, %w", err)
+	}
+
+	if height <= 1 || block.LastCommit == nil {
+		return nil
+	}
+
+	previous := height - 1
+
+	hash, err := store.Get(keyHeight(previous))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to fetch validator set hash, %w", err)
+	}
+
+	set, err := getSet(store.Get(keySet(hash)))
+	if err != nil {
+		return fmt.Errorf("unable to fetch validator set, %w", err)
+	}
+
+	proposer, err := store.Get(keyProposer(previous))
+	if err != nil {
+		return fmt.Errorf("unable to fetch block proposer, %w", err)
+	}
+
+	signing := &Signing{
+		Proposer: string(proposer),
+		Signed:   make([]string, 0, len(set.Validators)),
+		Missed:   make([]string, 0),
+		Height:   int64(previous),
+	}
+
+	signed := make(map[string]struct{}, len(block.LastCommit.Precommits))
+
+	for _, precommit := range block.LastCommit.Precommits {
+		// Absent votes are nil
+		if precommit == nil {
+			continue
+		}
+
+		signed[precommit.ValidatorAddress.String()] = struct{}{}
+	}
+
+	for _, validator := range set.Validators {
+		if _, ok := signed[validator.Address]; ok {
+			signing.Signed = append(signing.Signed, validator.Address)
+
+			continue
+		}
+
+		signing.Missed = append(signing.Missed, validator.Address)
+	}
+
+	encoded, err := json.Marshal(signing)
+	if err != nil {
+		return fmt.Errorf("unable to encode block signing, %w", err)
+	}
+
+	if err := store.Set(keySigning(previous), encoded); err != nil {
+		return fmt.Errorf("unable to save block signing, %w", err)
+	}
+
+	if err := store.Set(keyLatestSigning(), binary.BigEndian.AppendUint64(nil, previous)); err != nil {
+		return fmt.Errorf("unable to save latest block signing height, %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newSignedBlock creates a new block with the given proposer,
+// and the last commit signed by the given validators
+func newSignedBlock(height int64, proposer crypto.Address, signers ...*types.Validator) *types.Block {
+	block := newBlock(height, "set 1")
+
+	block.ProposerAddress = proposer
+	block.LastCommit = &types.Commit{
+		Precommits: make([]*types.CommitSig, 0, len(signers)),
+	}
+
+	for _, signer := range signers {
+		var precommit *types.CommitSig
+
+		if signer != nil {
+			precommit = &types.CommitSig{
+				ValidatorAddress: signer.Address,
+			}
+		}
+
+		block.LastCommit.Precommits = append(block.LastCommit.Precommits, precommit)
+	}
+
+	return block
+}
+
+func TestPlugin_Signing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = &types.Validator{Address: crypto.Address{1}, VotingPower: 10}
+		bob   = &types.Validator{Address: crypto.Address{2}, VotingPower: 10}
+
+		client = &mockClient{
+			sets: map[uint64][]*types.Validator{
+				1: {alice, bob},
+			},
+		}
+
+		blocks = []*types.Block{
+			newSignedBlock(1, alice.Address),
+			newSignedBlock(2, bob.Address, alice, bob),
+			newSignedBlock(3, alice.Address, alice, nil),
+			newSignedBlock(4, alice.Address, alice, nil),
+			newSignedBlock(5, bob.Address, alice, bob),
+		}
+	)
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	p := New(client)
+
+	for _, block := range blocks {
+		wb := db.WriteBatch()
+
+		require.NoError(t, p.OnBlock(plugins.NewStore(wb, Name), block, nil))
+		require.NoError(t, wb.Commit())
+	}
+
+	reader := NewReader(db)
+
+	t.Run("block signing", func(t *testing.T) {
+		t.Parallel()
+
+		signing, err := reader.GetSigning(2)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			&Signing{
+				Proposer: bob.Address.String(),
+				Signed:   []string{alice.Address.String()},
+				Missed:   []string{bob.Address.String()},
+				Height:   2,
+			},
+			signing,
+		)
+	})
+
+	t.Run("block signing not indexed", func(t *testing.T) {
+		t.Parallel()
+
+		// The latest block is only signed by the next block's commit
+		signing, err := reader.GetSigning(5)
+		require.NoError(t, err)
+
+		assert.Nil(t, signing)
+	})
+
+	t.Run("signing stats", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetSigningStats(bob.Address.String(), 100)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			&SigningStats{
+				Address:    bob.Address.String(),
+				Proposed:   1,
+				Signed:     2,
+				Missed:     2,
+				Uptime:     0.5,
+				FromHeight: 1,
+				ToHeight:   4,
+			},
+			stats,
+		)
+	})
+
+	t.Run("signing stats window", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := reader.GetSigningStats(alice.Address.String(), 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(3), stats.FromHeight)
+		assert.Equal(t, int64(4), stats.ToHeight)
+		assert.Equal(t, uint64(2), stats.Proposed)
+		assert.Equal(t, uint64(2), stats.Signed)
+		assert.Zero(t, stats.Missed)
+		assert.Equal(t, float64(1), stats.Uptime)
+	})
+}
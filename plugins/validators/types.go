@@ -0,0 +1,62 @@
+package validators
+
+import (
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+)
+
+// Client is the node client, used for fetching the validator sets
+type Client interface {
+	// GetValidators returns the validator set at the given height
+	GetValidators(uint64) (*core_types.ResultValidators, error)
+}
+
+// Validator is a single validator of the set
+type Validator struct {
+	Address     string `json:"address"`
+	PubKey      string `json:"pubKey"`
+	VotingPower int64  `json:"votingPower"`
+}
+
+// Set is the validator set, along with the height it took effect at
+type Set struct {
+	Validators       []*Validator `json:"validators"`
+	Height           int64        `json:"height"`
+	TotalVotingPower int64        `json:"totalVotingPower"`
+}
+
+// PowerChange is the voting power change of a validator
+type PowerChange struct {
+	Address   string `json:"address"`
+	FromPower int64  `json:"fromPower"`
+	ToPower   int64  `json:"toPower"`
+}
+
+// Change is the validator set change at the given height,
+// compared to the previous validator set
+type Change struct {
+	Added   []*Validator   `json:"added"`
+	Removed []*Validator   `json:"removed"`
+	Updated []*PowerChange `json:"updated"`
+	Height  int64          `json:"height"`
+}
+
+// Signing is the signing record of a block: its proposer, and the validators
+// of the set that signed (or missed) its commit
+type Signing struct {
+	Proposer string   `json:"proposer"`
+	Signed   []string `json:"signed"`
+	Missed   []string `json:"missed"`
+	Height   int64    `json:"height"`
+}
+
+// SigningStats are the signing statistics of a validator,
+// over the [FromHeight, ToHeight] window
+type SigningStats struct {
+	Address    string  `json:"address"`
+	Proposed   uint64  `json:"proposed"`
+	Signed     uint64  `json:"signed"`
+	Missed     uint64  `json:"missed"`
+	Uptime     float64 `json:"uptime"`
+	FromHeight int64   `json:"fromHeight"`
+	ToHeight   int64   `json:"toHeight"`
+}
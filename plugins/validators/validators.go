@@ -0,0 +1,181 @@
+// Package validators indexes the validator set of every height, fetching
+// the validator sets from the node whenever the block validators hash changes,
+// along with the block proposers and commit signatures
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// Name is the plugin name, and its storage namespace
+const Name = "validators"
+
+var _ plugins.Indexer = &Plugin{}
+
+// Plugin is the validator set indexer plugin
+type Plugin struct {
+	client Client
+}
+
+// New creates a new validator set indexer plugin,
+// fetching the validator sets using the client
+func New(client Client) *Plugin {
+	return &Plugin{
+		client: client,
+	}
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+func (p *Plugin) OnTx(_ plugins.Store, _ *types.TxResult) error {
+	return nil
+}
+
+// OnBlock indexes the validator set hash of the block, and fetches
+// the new validator set from the node if the hash changed.
+// It also indexes the block proposer, and the signatures of the previous block
+// (carried by the block's last commit)
+func (p *Plugin) OnBlock(store plugins.Store, block *types.Block, _ []*types.TxResult) error {
+	hash := block.ValidatorsHash
+
+	latest, err := store.Get(keyLatest())
+	if err != nil && !errors.Is(err, storageErrors.ErrNotFound) {
+		return fmt.Errorf("unable to fetch latest validator set hash, %w", err)
+	}
+
+	if latest == nil || !bytes.Equal(latest, hash) {
+		if err := p.indexSet(store, block.Height, latest, hash); err != nil {
+			return err
+		}
+	}
+
+	if err := store.Set(keyHeight(uint64(block.Height)), hash); err != nil {
+		return fmt.Errorf("unable to save validator set hash, %w", err)
+	}
+
+	return indexSigning(store, block)
+}
+
+// indexSet fetches and saves the validator set that took effect at the height,
+// along with its change compared to the previous set, if any
+func (p *Plugin) indexSet(store plugins.Store, height int64, previousHash, hash []byte) error {
+	result, err := p.client.GetValidators(uint64(height))
+	if err != nil {
+		return fmt.Errorf("unable to fetch validator set, %w", err)
+	}
+
+	set := &Set{
+		Validators: make([]*Validator, 0, len(result.Validators)),
+		Height:     height,
+	}
+
+	for _, validator := range result.Validators {
+		v := &Validator{
+			Address:     validator.Address.String(),
+			VotingPower: validator.VotingPower,
+		}
+
+		if validator.PubKey != nil {
+			v.PubKey = crypto.PubKeyToBech32(validator.PubKey)
+		}
+
+		set.Validators = append(set.Validators, v)
+		set.TotalVotingPower += validator.VotingPower
+	}
+
+	previous := &Set{}
+
+	if previousHash != nil {
+		if previous, err = getSet(store.Get(keySet(previousHash))); err != nil {
+			return fmt.Errorf("unable to fetch previous validator set, %w", err)
+		}
+	}
+
+	for key, value := range map[string]any{
+		string(keySet(hash)):              set,
+		string(keyChange(uint64(height))): diff(height, previous, set),
+	} {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unable to encode validator set, %w", err)
+		}
+
+		if err := store.Set([]byte(key), encoded); err != nil {
+			return fmt.Errorf("unable to save validator set, %w", err)
+		}
+	}
+
+	if err := store.Set(keyLatest(), hash); err != nil {
+		return fmt.Errorf("unable to save latest validator set hash, %w", err)
+	}
+
+	return nil
+}
+
+// diff returns the change from the previous to the current validator set
+func diff(height int64, previous, current *Set) *Change {
+	change := &Change{
+		Added:   make([]*Validator, 0),
+		Removed: make([]*Validator, 0),
+		Updated: make([]*PowerChange, 0),
+		Height:  height,
+	}
+
+	previousByAddress := make(map[string]*Validator, len(previous.Validators))
+	for _, validator := range previous.Validators {
+		previousByAddress[validator.Address] = validator
+	}
+
+	for _, validator := range current.Validators {
+		old, ok := previousByAddress[validator.Address]
+		if !ok {
+			change.Added = append(change.Added, validator)
+
+			continue
+		}
+
+		delete(previousByAddress, validator.Address)
+
+		if old.VotingPower != validator.VotingPower {
+			change.Updated = append(change.Updated, &PowerChange{
+				Address:   validator.Address,
+				FromPower: old.VotingPower,
+				ToPower:   validator.VotingPower,
+			})
+		}
+	}
+
+	// Keep the removed validators in the previous set order
+	for _, validator := range previous.Validators {
+		if _, ok := previousByAddress[validator.Address]; ok {
+			change.Removed = append(change.Removed, validator)
+		}
+	}
+
+	return change
+}
+
+// getSet decodes the validator set
+func getSet(raw []byte, err error) (*Set, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	var set *Set
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("unable to decode validator set, %w", err)
+	}
+
+	return set, nil
+}
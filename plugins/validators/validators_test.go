@@ -0,0 +1,182 @@
+package validators
+
+import (
+	"errors"
+	"testing"
+
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// mockClient is a mock node client, serving the validator sets by height
+type mockClient struct {
+	sets    map[uint64][]*types.Validator
+	fetched []uint64
+}
+
+func (m *mockClient) GetValidators(height uint64) (*core_types.ResultValidators, error) {
+	m.fetched = append(m.fetched, height)
+
+	validators, ok := m.sets[height]
+	if !ok {
+		return nil, errors.New("validator set not found")
+	}
+
+	return &core_types.ResultValidators{
+		BlockHeight: int64(height),
+		Validators:  validators,
+	}, nil
+}
+
+// newBlock creates a new block with the given validators hash
+func newBlock(height int64, validatorsHash string) *types.Block {
+	return &types.Block{
+		Header: types.Header{
+			Height:         height,
+			ValidatorsHash: []byte(validatorsHash),
+		},
+	}
+}
+
+func TestPlugin_Validators(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = &types.Validator{Address: crypto.Address{1}, VotingPower: 10}
+		bob   = &types.Validator{Address: crypto.Address{2}, VotingPower: 10}
+		carol = &types.Validator{Address: crypto.Address{3}, VotingPower: 5}
+
+		client = &mockClient{
+			sets: map[uint64][]*types.Validator{
+				1: {alice, bob},
+				4: {
+					{Address: alice.Address, VotingPower: 20},
+					carol,
+				},
+			},
+		}
+
+		blocks = []*types.Block{
+			newBlock(1, "set 1"),
+			newBlock(2, "set 1"),
+			newBlock(3, "set 1"),
+			newBlock(4, "set 2"),
+			newBlock(5, "set 2"),
+		}
+	)
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	p := New(client)
+
+	for _, block := range blocks {
+		wb := db.WriteBatch()
+
+		require.NoError(t, p.OnBlock(plugins.NewStore(wb, Name), block, nil))
+		require.NoError(t, wb.Commit())
+	}
+
+	// Make sure the validator sets were only fetched on changes
+	assert.Equal(t, []uint64{1, 4}, client.fetched)
+
+	reader := NewReader(db)
+
+	t.Run("validator set at height", func(t *testing.T) {
+		t.Parallel()
+
+		set, err := reader.GetValidators(3)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), set.Height)
+		assert.Equal(t, int64(20), set.TotalVotingPower)
+		require.Len(t, set.Validators, 2)
+		assert.Equal(t, alice.Address.String(), set.Validators[0].Address)
+
+		set, err = reader.GetValidators(5)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(4), set.Height)
+		assert.Equal(t, int64(25), set.TotalVotingPower)
+	})
+
+	t.Run("height not indexed", func(t *testing.T) {
+		t.Parallel()
+
+		set, err := reader.GetValidators(100)
+		require.NoError(t, err)
+
+		assert.Nil(t, set)
+	})
+
+	t.Run("validator set changes", func(t *testing.T) {
+		t.Parallel()
+
+		changes, err := reader.GetValidatorChanges(0, 0, 10)
+		require.NoError(t, err)
+
+		require.Len(t, changes, 2)
+
+		// The first indexed set is added in full
+		assert.Equal(t, int64(1), changes[0].Height)
+		assert.Len(t, changes[0].Added, 2)
+		assert.Empty(t, changes[0].Removed)
+
+		change := changes[1]
+
+		assert.Equal(t, int64(4), change.Height)
+
+		require.Len(t, change.Added, 1)
+		assert.Equal(t, carol.Address.String(), change.Added[0].Address)
+
+		require.Len(t, change.Removed, 1)
+		assert.Equal(t, bob.Address.String(), change.Removed[0].Address)
+
+		assert.Equal(
+			t,
+			[]*PowerChange{{Address: alice.Address.String(), FromPower: 10, ToPower: 20}},
+			change.Updated,
+		)
+	})
+
+	t.Run("validator set changes in range", func(t *testing.T) {
+		t.Parallel()
+
+		changes, err := reader.GetValidatorChanges(2, 4, 10)
+		require.NoError(t, err)
+
+		assert.Empty(t, changes)
+
+		changes, err = reader.GetValidatorChanges(0, 0, 1)
+		require.NoError(t, err)
+
+		require.Len(t, changes, 1)
+		assert.Equal(t, int64(1), changes[0].Height)
+	})
+}
+
+func TestPlugin_FetchError(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	wb := db.WriteBatch()
+
+	err = New(&mockClient{}).OnBlock(plugins.NewStore(wb, Name), newBlock(1, "set 1"), nil)
+	assert.ErrorContains(t, err, "unable to fetch validator set")
+}
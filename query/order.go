@@ -0,0 +1,225 @@
+package query
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// The orders of the list queries
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// The sort fields of the list queries. The block time follows the height,
+// so both sort the same way
+const (
+	SortHeight = "height"
+	SortTime   = "time"
+	SortGas    = "gas"
+)
+
+// ErrInvalidOrdering is returned when the ordering is not supported
+var ErrInvalidOrdering = errors.New("invalid ordering")
+
+// Ordering is the ordering of the list query results
+type Ordering struct {
+	// Order is the order, asc (default) or desc
+	Order string `json:"order"`
+
+	// SortBy is the sort field, height (default), time or gas
+	SortBy string `json:"sortBy"`
+}
+
+// Validate validates the ordering, out of the supported sort fields (the height and time are always supported),
+// and sets the defaults of the unset ones
+func (o *Ordering) Validate(fields ...string) error {
+	if o.Order == "" {
+		o.Order = OrderAsc
+	}
+
+	if o.SortBy == "" {
+		o.SortBy = SortHeight
+	}
+
+	if o.Order != OrderAsc && o.Order != OrderDesc {
+		return fmt.Errorf("%w: unknown order %q", ErrInvalidOrdering, o.Order)
+	}
+
+	if o.SortBy != SortHeight && o.SortBy != SortTime && !slices.Contains(fields, o.SortBy) {
+		return fmt.Errorf("%w: unsupported sort field %q", ErrInvalidOrdering, o.SortBy)
+	}
+
+	return nil
+}
+
+// Natural checks if the ordering is the storage one (oldest first),
+// which doesn't require scanning the whole range
+func (o Ordering) Natural() bool {
+	return o.Order != OrderDesc && o.Streamed()
+}
+
+// Streamed checks if the ordering follows the storage height order, oldest or newest first,
+// so the elements are iterated in order (in reverse for the newest first) instead of sorted in memory
+func (o Ordering) Streamed() bool {
+	return o.SortBy == "" || o.SortBy == SortHeight || o.SortBy == SortTime
+}
+
+// Reverse checks if the ordering is descending (newest first, for the streamed orderings)
+func (o Ordering) Reverse() bool {
+	return o.Order == OrderDesc
+}
+
+// Rank is the sort position of a list element
+type Rank struct {
+	// Key is the value of the sort field, if it's not the height (or time)
+	Key int64
+
+	Height uint64
+	Index  uint32
+}
+
+// before checks if the rank a comes before b in the ordering
+func (o Ordering) before(a, b Rank) bool {
+	less := func(a, b Rank) bool {
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+
+		if a.Height != b.Height {
+			return a.Height < b.Height
+		}
+
+		return a.Index < b.Index
+	}
+
+	if o.Order == OrderDesc {
+		return less(b, a)
+	}
+
+	return less(a, b)
+}
+
+// Selector selects up to limit elements in the ordering, out of the elements added
+// in the storage order. Only the elements ranked after the (optional) cursor rank are selected
+type Selector[T any] struct {
+	ranks    *rankHeap[T]
+	after    *Rank
+	rank     func(T) Rank
+	ordering Ordering
+	limit    int
+	more     bool
+}
+
+// NewSelector creates a new selector of the ordering, ranking the elements with the rank function
+func NewSelector[T any](ordering Ordering, limit int, after *Rank, rank func(T) Rank) *Selector[T] {
+	return &Selector[T]{
+		ranks:    &rankHeap[T]{ordering: ordering},
+		after:    after,
+		rank:     rank,
+		ordering: ordering,
+		limit:    limit,
+	}
+}
+
+// Add adds the element to the selection. It returns false once no more elements can be selected,
+// so the remaining elements don't have to be added
+func (s *Selector[T]) Add(element T) bool {
+	r := s.rank(element)
+
+	if s.after != nil && !s.ordering.before(*s.after, r) {
+		return true
+	}
+
+	if s.ranks.Len() == s.limit && s.ordering.Natural() {
+		// The remaining elements are ranked after the selected ones
+		s.more = true
+
+		return false
+	}
+
+	heap.Push(s.ranks, rankedElement[T]{element: element, rank: r})
+
+	if s.ranks.Len() > s.limit {
+		heap.Pop(s.ranks)
+
+		s.more = true
+	}
+
+	return true
+}
+
+// Elements returns the selected elements, in the ordering
+func (s *Selector[T]) Elements() []T {
+	ranked := slices.Clone(s.ranks.elements)
+
+	slices.SortFunc(ranked, func(a, b rankedElement[T]) int {
+		switch {
+		case s.ordering.before(a.rank, b.rank):
+			return -1
+		case s.ordering.before(b.rank, a.rank):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	elements := make([]T, 0, len(ranked))
+	for _, r := range ranked {
+		elements = append(elements, r.element)
+	}
+
+	return elements
+}
+
+// More checks if there are more elements ranked after the selected ones
+func (s *Selector[T]) More() bool {
+	return s.more
+}
+
+// Last returns the rank of the last selected element, if any
+func (s *Selector[T]) Last() (Rank, bool) {
+	if s.ranks.Len() == 0 {
+		return Rank{}, false
+	}
+
+	// The last ranked element is on top of the heap
+	return s.ranks.elements[0].rank, true
+}
+
+// rankedElement is a selected element, along with its rank
+type rankedElement[T any] struct {
+	element T
+	rank    Rank
+}
+
+// rankHeap is the heap of the selected elements, with the last ranked element on top
+type rankHeap[T any] struct {
+	elements []rankedElement[T]
+	ordering Ordering
+}
+
+func (h *rankHeap[T]) Len() int {
+	return len(h.elements)
+}
+
+func (h *rankHeap[T]) Less(i, j int) bool {
+	return h.ordering.before(h.elements[j].rank, h.elements[i].rank)
+}
+
+func (h *rankHeap[T]) Swap(i, j int) {
+	h.elements[i], h.elements[j] = h.elements[j], h.elements[i]
+}
+
+func (h *rankHeap[T]) Push(x any) {
+	h.elements = append(h.elements, x.(rankedElement[T])) //nolint:forcetypeassert // Only pushed by the selector
+}
+
+func (h *rankHeap[T]) Pop() any {
+	last := h.elements[len(h.elements)-1]
+	h.elements = h.elements[:len(h.elements)-1]
+
+	return last
+}
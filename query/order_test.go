@@ -0,0 +1,154 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrdering_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		var ordering Ordering
+
+		require.NoError(t, ordering.Validate())
+
+		assert.Equal(t, Ordering{Order: OrderAsc, SortBy: SortHeight}, ordering)
+		assert.True(t, ordering.Natural())
+	})
+
+	testTable := []struct {
+		name     string
+		ordering Ordering
+		fields   []string
+		natural  bool
+		valid    bool
+	}{
+		{
+			"newest first",
+			Ordering{Order: OrderDesc, SortBy: SortTime},
+			nil,
+			false,
+			true,
+		},
+		{
+			"supported sort field",
+			Ordering{SortBy: SortGas},
+			[]string{SortGas},
+			false,
+			true,
+		},
+		{
+			"unsupported sort field",
+			Ordering{SortBy: SortGas},
+			nil,
+			false,
+			false,
+		},
+		{
+			"unknown order",
+			Ordering{Order: "newest"},
+			nil,
+			true,
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ordering := testCase.ordering
+
+			err := ordering.Validate(testCase.fields...)
+			if !testCase.valid {
+				assert.ErrorIs(t, err, ErrInvalidOrdering)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.natural, ordering.Natural())
+		})
+	}
+}
+
+func TestSelector(t *testing.T) {
+	t.Parallel()
+
+	// The elements are added in the storage order (by height)
+	var (
+		values  = []int64{30, 10, 50, 20, 40}
+		heights = map[int64]uint64{30: 1, 10: 2, 50: 3, 20: 4, 40: 5}
+	)
+
+	testTable := []struct {
+		name     string
+		ordering Ordering
+		after    *Rank
+		expected []int64
+		more     bool
+	}{
+		{
+			"storage order",
+			Ordering{},
+			nil,
+			[]int64{30, 10, 50},
+			true,
+		},
+		{
+			"highest first",
+			Ordering{Order: OrderDesc, SortBy: SortGas},
+			nil,
+			[]int64{50, 40, 30},
+			true,
+		},
+		{
+			"lowest first",
+			Ordering{Order: OrderAsc, SortBy: SortGas},
+			nil,
+			[]int64{10, 20, 30},
+			true,
+		},
+		{
+			"after the cursor",
+			Ordering{Order: OrderDesc, SortBy: SortGas},
+			&Rank{Key: 30},
+			[]int64{20, 10},
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			rank := func(v int64) Rank {
+				if testCase.ordering.Natural() {
+					return Rank{Height: heights[v]}
+				}
+
+				return Rank{Key: v, Height: heights[v]}
+			}
+
+			selector := NewSelector(testCase.ordering, 3, testCase.after, rank)
+
+			for _, v := range values {
+				if !selector.Add(v) {
+					break
+				}
+			}
+
+			assert.Equal(t, testCase.expected, selector.Elements())
+			assert.Equal(t, testCase.more, selector.More())
+		})
+	}
+}
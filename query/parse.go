@@ -0,0 +1,333 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+const (
+	// maxExpressionLength is the maximum length of the filter expression
+	maxExpressionLength = 2048
+
+	// maxConditions is the maximum number of conditions in the filter expression
+	maxConditions = 32
+)
+
+// ErrInvalidExpression is returned when the filter expression is malformed
+var ErrInvalidExpression = errors.New("invalid filter expression")
+
+// tokenKind is the kind of the expression token
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+// token is a single expression token
+type token struct {
+	text string
+	kind tokenKind
+	pos  int
+}
+
+// Parse parses the filter expression, made of the field conditions (ex. height>100000)
+// combined with AND and OR (AND binding tighter), and grouped with parentheses.
+// The transactions are matched decoded by the given chain decoder
+func Parse(expression string, decoder *decode.Decoder) (*Filter, error) {
+	if len(expression) > maxExpressionLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidExpression, maxExpressionLength)
+	}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEnd {
+		return nil, unexpected(next)
+	}
+
+	return &Filter{root: root, decoder: decoder}, nil
+}
+
+// tokenize splits the expression into tokens, ending with the end token
+func tokenize(expression string) ([]token, error) {
+	var (
+		tokens = make([]token, 0)
+		runes  = []rune(expression)
+	)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for ; end < len(runes) && runes[end] != '"'; end++ {
+				if runes[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated string at %d", ErrInvalidExpression, i)
+			}
+
+			text, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid string at %d", ErrInvalidExpression, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = end + 1
+		case isOperator(r):
+			end := i + 1
+			if end < len(runes) && runes[end] == '=' && r != '=' {
+				end++
+			}
+
+			text := string(runes[i:end])
+			if text == "!" {
+				return nil, fmt.Errorf("%w: unexpected \"!\" at %d", ErrInvalidExpression, i)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: text, pos: i})
+			i = end
+		default:
+			end := i
+			for end < len(runes) && isWord(runes[end]) {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:end]), pos: i})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, pos: len(runes)}), nil
+}
+
+// isOperator checks if the rune starts a comparison operator
+func isOperator(r rune) bool {
+	return r == '=' || r == '!' || r == '<' || r == '>'
+}
+
+// isWord checks if the rune is part of a bare word (field names, keywords and unquoted values)
+func isWord(r rune) bool {
+	return !unicode.IsSpace(r) && !isOperator(r) && r != '(' && r != ')' && r != '"'
+}
+
+// parser is the recursive descent parser of the expression tokens
+type parser struct {
+	tokens     []token
+	pos        int
+	conditions int
+}
+
+// peek returns the next token, without consuming it
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes the next token
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+// keyword consumes the next token, if it's the (case-insensitive) keyword
+func (p *parser) keyword(keyword string) bool {
+	if t := p.peek(); t.kind == tokenWord && strings.EqualFold(t.text, keyword) {
+		p.pos++
+
+		return true
+	}
+
+	return false
+}
+
+// parseOr parses the OR separated AND expressions
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []node{first}
+
+	for p.keyword("OR") {
+		n, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+
+	return orNode(nodes), nil
+}
+
+// parseAnd parses the AND separated terms
+func (p *parser) parseAnd() (node, error) {
+	first, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []node{first}
+
+	for p.keyword("AND") {
+		n, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+
+	return andNode(nodes), nil
+}
+
+// parseTerm parses a parenthesized expression, or a single condition
+func (p *parser) parseTerm() (node, error) {
+	if p.peek().kind == tokenOpen {
+		p.next()
+
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if t := p.next(); t.kind != tokenClose {
+			return nil, unexpected(t)
+		}
+
+		return n, nil
+	}
+
+	return p.parseCondition()
+}
+
+// parseCondition parses a single field condition
+func (p *parser) parseCondition() (node, error) {
+	fieldToken := p.next()
+	if fieldToken.kind != tokenWord {
+		return nil, unexpected(fieldToken)
+	}
+
+	f, ok := fields[strings.ToLower(fieldToken.text)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidExpression, fieldToken.text)
+	}
+
+	opToken := p.next()
+	if opToken.kind != tokenOperator {
+		return nil, unexpected(opToken)
+	}
+
+	op := operator(opToken.text)
+	if op != opEqual && op != opNotEqual && f.kind != kindNumber {
+		return nil, fmt.Errorf("%w: operator %q not supported by field %q", ErrInvalidExpression, op, f.name)
+	}
+
+	valueToken := p.next()
+	if valueToken.kind != tokenWord && valueToken.kind != tokenString {
+		return nil, unexpected(valueToken)
+	}
+
+	c := &condition{
+		field: f,
+		op:    op,
+		text:  valueToken.text,
+	}
+
+	if err := c.parseValue(); err != nil {
+		return nil, fmt.Errorf("%w: invalid %s value %q", ErrInvalidExpression, f.name, valueToken.text)
+	}
+
+	p.conditions++
+	if p.conditions > maxConditions {
+		return nil, fmt.Errorf("%w: more than %d conditions", ErrInvalidExpression, maxConditions)
+	}
+
+	return c, nil
+}
+
+// parseValue parses the condition value, by the field kind
+func (c *condition) parseValue() error {
+	switch c.field.kind {
+	case kindNumber:
+		number, err := strconv.ParseUint(c.text, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		c.number = number
+	case kindBool:
+		flag, err := strconv.ParseBool(c.text)
+		if err != nil {
+			return err
+		}
+
+		c.flag = flag
+	case kindAddress:
+		if _, err := crypto.AddressFromBech32(c.text); err != nil {
+			return err
+		}
+	case kindString:
+		if c.text == "" {
+			return errors.New("empty value")
+		}
+	}
+
+	return nil
+}
+
+// unexpected returns the error of the unexpected token
+func unexpected(t token) error {
+	if t.kind == tokenEnd {
+		return fmt.Errorf("%w: unexpected end of expression", ErrInvalidExpression)
+	}
+
+	return fmt.Errorf("%w: unexpected %q at %d", ErrInvalidExpression, t.text, t.pos)
+}
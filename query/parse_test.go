@@ -0,0 +1,130 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid expressions", func(t *testing.T) {
+		t.Parallel()
+
+		address := crypto.Address{1}.String()
+
+		testTable := []struct {
+			name       string
+			expression string
+		}{
+			{
+				"single condition",
+				"height>100000",
+			},
+			{
+				"combined conditions",
+				"type=vm.m_call AND realm=gno.land/r/demo/boards AND success=false AND height>100000",
+			},
+			{
+				"grouped conditions",
+				"(type=send OR type = exec) and address=" + address,
+			},
+			{
+				"quoted value",
+				`realm = "gno.land/r/demo/boards"`,
+			},
+			{
+				"all operators",
+				"height=1 OR height!=1 OR height>1 OR height>=1 OR height<1 OR height<=1",
+			},
+		}
+
+		for _, testCase := range testTable {
+			testCase := testCase
+
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				filter, err := Parse(testCase.expression, nil)
+				require.NoError(t, err)
+
+				assert.NotNil(t, filter)
+			})
+		}
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		t.Parallel()
+
+		testTable := []struct {
+			name       string
+			expression string
+		}{
+			{
+				"empty expression",
+				"",
+			},
+			{
+				"unknown field",
+				"memo=rent",
+			},
+			{
+				"missing value",
+				"height >",
+			},
+			{
+				"missing operator",
+				"height 10",
+			},
+			{
+				"unsupported operator",
+				"realm > gno.land/r/demo/boards",
+			},
+			{
+				"invalid number",
+				"height=-1",
+			},
+			{
+				"invalid bool",
+				"success=maybe",
+			},
+			{
+				"invalid address",
+				"address=g1invalid",
+			},
+			{
+				"dangling keyword",
+				"height=1 AND",
+			},
+			{
+				"unbalanced parentheses",
+				"(height=1 OR height=2",
+			},
+			{
+				"unterminated string",
+				`realm="gno.land`,
+			},
+			{
+				"too many conditions",
+				strings.Repeat("height=1 OR ", maxConditions) + "height=1",
+			},
+		}
+
+		for _, testCase := range testTable {
+			testCase := testCase
+
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				filter, err := Parse(testCase.expression, nil)
+				assert.Nil(t, filter)
+
+				assert.ErrorIs(t, err, ErrInvalidExpression)
+			})
+		}
+	})
+}
@@ -0,0 +1,329 @@
+// Package query implements the filter expressions of the transaction queries,
+// such as type=vm.m_call AND realm=gno.land/r/demo/boards AND success=false AND height>100000,
+// and the ordering of the list query results
+package query
+
+import (
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+// operator is the comparison operator of a condition
+type operator string
+
+const (
+	opEqual        operator = "="
+	opNotEqual     operator = "!="
+	opGreater      operator = ">"
+	opGreaterEqual operator = ">="
+	opLess         operator = "<"
+	opLessEqual    operator = "<="
+)
+
+// kind is the value kind of a field
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindBool
+	kindString
+	kindAddress
+)
+
+// field is a filterable transaction field
+type field struct {
+	// number returns the number value of the number fields
+	number func(tx *candidate) uint64
+
+	// flag returns the value of the bool fields
+	flag func(tx *candidate) bool
+
+	// values returns the values of the (multi-valued) string and address fields,
+	// which match the equal conditions if any of the values is equal
+	values func(tx *candidate) []string
+
+	name string
+	kind kind
+}
+
+// The filterable fields
+const (
+	fieldHeight    = "height"
+	fieldIndex     = "index"
+	fieldGasUsed   = "gas_used"
+	fieldGasWanted = "gas_wanted"
+	fieldSuccess   = "success"
+	fieldError     = "error"
+	fieldType      = "type"
+	fieldRealm     = "realm"
+	fieldAddress   = "address"
+)
+
+// fields are the filterable fields, by name
+var fields = map[string]*field{
+	fieldHeight: {
+		name: fieldHeight,
+		kind: kindNumber,
+		number: func(tx *candidate) uint64 {
+			return uint64(tx.result.Height)
+		},
+	},
+	fieldIndex: {
+		name: fieldIndex,
+		kind: kindNumber,
+		number: func(tx *candidate) uint64 {
+			return uint64(tx.result.Index)
+		},
+	},
+	fieldGasUsed: {
+		name: fieldGasUsed,
+		kind: kindNumber,
+		number: func(tx *candidate) uint64 {
+			return uint64(max(tx.result.Response.GasUsed, 0))
+		},
+	},
+	fieldGasWanted: {
+		name: fieldGasWanted,
+		kind: kindNumber,
+		number: func(tx *candidate) uint64 {
+			return uint64(max(tx.result.Response.GasWanted, 0))
+		},
+	},
+	fieldSuccess: {
+		name: fieldSuccess,
+		kind: kindBool,
+		flag: func(tx *candidate) bool {
+			return !tx.result.Response.IsErr()
+		},
+	},
+	fieldError: {
+		name: fieldError,
+		kind: kindString,
+		values: func(tx *candidate) []string {
+			if !tx.result.Response.IsErr() {
+				return nil
+			}
+
+			return []string{decode.ErrorCode(tx.result.Response.Error)}
+		},
+	},
+	fieldType: {
+		name:   fieldType,
+		kind:   kindString,
+		values: msgTypes,
+	},
+	fieldRealm: {
+		name:   fieldRealm,
+		kind:   kindString,
+		values: msgRealms,
+	},
+	fieldAddress: {
+		name:   fieldAddress,
+		kind:   kindAddress,
+		values: addresses,
+	},
+}
+
+// node is a node of the parsed filter expression
+type node interface {
+	matches(tx *candidate) bool
+}
+
+// andNode matches if all of its nodes match
+type andNode []node
+
+func (n andNode) matches(tx *candidate) bool {
+	for _, child := range n {
+		if !child.matches(tx) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// orNode matches if any of its nodes match
+type orNode []node
+
+func (n orNode) matches(tx *candidate) bool {
+	for _, child := range n {
+		if child.matches(tx) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// condition is a single field condition
+type condition struct {
+	field *field
+	op    operator
+	text  string
+
+	number uint64
+	flag   bool
+
+	// scanned marks the condition met by all the scanned transactions
+	// (the address of the address index scan), so it's not matched again
+	scanned bool
+}
+
+func (c *condition) matches(tx *candidate) bool {
+	if c.scanned {
+		return true
+	}
+
+	switch c.field.kind {
+	case kindNumber:
+		return compare(c.field.number(tx), c.op, c.number)
+	case kindBool:
+		return (c.field.flag(tx) == c.flag) == (c.op == opEqual)
+	default:
+		found := false
+
+		for _, value := range c.field.values(tx) {
+			if value == c.text {
+				found = true
+
+				break
+			}
+		}
+
+		return found == (c.op == opEqual)
+	}
+}
+
+// compare compares the number values with the operator
+func compare(value uint64, op operator, to uint64) bool {
+	switch op {
+	case opEqual:
+		return value == to
+	case opNotEqual:
+		return value != to
+	case opGreater:
+		return value > to
+	case opGreaterEqual:
+		return value >= to
+	case opLess:
+		return value < to
+	case opLessEqual:
+		return value <= to
+	default:
+		return false
+	}
+}
+
+// Filter is a parsed filter expression
+type Filter struct {
+	root    node
+	decoder *decode.Decoder
+}
+
+// AddressFilter returns the filter of the transactions the address participated in,
+// in the height range [from, to), as saved in the address index. A 0 range end means no upper bound
+func AddressFilter(address string, from, to uint64, decoder *decode.Decoder) *Filter {
+	root := andNode{
+		&condition{field: fields[fieldAddress], op: opEqual, text: address, scanned: true},
+		&condition{field: fields[fieldHeight], op: opGreaterEqual, number: from},
+	}
+
+	if to != 0 {
+		root = append(root, &condition{field: fields[fieldHeight], op: opLess, number: to})
+	}
+
+	return &Filter{root: root, decoder: decoder}
+}
+
+// Matches checks if the transaction matches the filter
+func (f *Filter) Matches(tx *types.TxResult) bool {
+	return f.root.matches(&candidate{result: tx, decoder: f.decoder})
+}
+
+// candidate is a transaction matched against the filter,
+// decoded only if the message fields are used
+type candidate struct {
+	result  *types.TxResult
+	tx      *std.Tx
+	decoder *decode.Decoder
+
+	decoded bool
+}
+
+// msgs returns the messages of the transaction,
+// or none if the transaction can't be decoded
+func (c *candidate) msgs() []std.Msg {
+	if !c.decoded {
+		c.decoded = true
+		c.tx, _ = c.decoder.TxResult(c.result)
+	}
+
+	if c.tx == nil {
+		return nil
+	}
+
+	return c.tx.GetMsgs()
+}
+
+// msgTypes returns the types of the transaction messages, both as the message type
+// (ex. exec) and the Amino type name (ex. vm.m_call) of the known messages
+func msgTypes(tx *candidate) []string {
+	msgs := tx.msgs()
+	names := make([]string, 0, 2*len(msgs))
+
+	for _, msg := range msgs {
+		names = append(names, msg.Type())
+
+		switch msg.(type) {
+		case bank.MsgSend:
+			names = append(names, "bank.MsgSend")
+		case vm.MsgCall:
+			names = append(names, "vm.m_call")
+		case vm.MsgAddPackage:
+			names = append(names, "vm.m_addpkg")
+		case vm.MsgRun:
+			names = append(names, "vm.m_run")
+		}
+	}
+
+	return names
+}
+
+// msgRealms returns the realms called or deployed by the transaction messages
+func msgRealms(tx *candidate) []string {
+	realms := make([]string, 0)
+
+	for _, msg := range tx.msgs() {
+		switch m := msg.(type) {
+		case vm.MsgCall:
+			realms = append(realms, m.PkgPath)
+		case vm.MsgAddPackage:
+			if m.Package != nil {
+				realms = append(realms, m.Package.Path)
+			}
+		}
+	}
+
+	return realms
+}
+
+// addresses returns the addresses participating in the transaction,
+// same as the ones of the address index
+func addresses(tx *candidate) []string {
+	if tx.msgs(); tx.tx == nil {
+		return nil
+	}
+
+	participants := decode.Addresses(tx.tx)
+	values := make([]string, 0, len(participants))
+
+	for _, address := range participants {
+		values = append(values, address.String())
+	}
+
+	return values
+}
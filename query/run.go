@@ -0,0 +1,357 @@
+package query
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+const (
+	// MaxScannedTxs is the maximum number of transactions scanned for a single page.
+	// Once reached, the page is returned with the cursor of the next transaction to scan,
+	// unless the page is sorted by gas, in which case the query fails (see ErrTooManyTxs)
+	MaxScannedTxs = 50_000
+
+	// cursorSize is the size of the (decoded) cursor, the height and index of the next transaction
+	cursorSize = 12
+
+	// rankCursorSize is the size of the (decoded) cursor of the sorted pages,
+	// the rank of the last returned transaction
+	rankCursorSize = 20
+)
+
+// ErrTooManyTxs is returned when the gas sorted query scans too many transactions
+var ErrTooManyTxs = fmt.Errorf("more than %d transactions to sort", MaxScannedTxs)
+
+// Storage is the transaction storage the filters are run against
+type Storage interface {
+	// TxIterator iterates over transactions, limiting the results to be between the provided block numbers
+	// and transaction indexes
+	TxIterator(fromBlockNum, toBlockNum uint64, fromTxIndex, toTxIndex uint32) (storage.Iterator[*types.TxResult], error)
+
+	// ReverseTxIterator iterates over transactions as TxIterator, newest first
+	ReverseTxIterator(
+		fromBlockNum,
+		toBlockNum uint64,
+		fromTxIndex,
+		toTxIndex uint32,
+	) (storage.Iterator[*types.TxResult], error)
+
+	// TxByAddressIterator iterates over transactions the address participated in,
+	// limiting the results to be between the provided block numbers
+	TxByAddressIterator(address string, fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.TxResult], error)
+
+	// ReverseTxByAddressIterator iterates over transactions the address participated in
+	// as TxByAddressIterator, newest first
+	ReverseTxByAddressIterator(
+		address string,
+		fromBlockNum,
+		toBlockNum uint64,
+	) (storage.Iterator[*types.TxResult], error)
+}
+
+// Page is a single page of the matching transactions
+type Page struct {
+	Txs []*types.TxResult
+
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string
+}
+
+// plan is the storage scan of the filter, narrowed down by its top-level (AND) conditions:
+// the height conditions limit the scanned range, and the address condition selects the address index
+type plan struct {
+	address string
+
+	// from and to are the (inclusive) scanned heights
+	from uint64
+	to   uint64
+}
+
+// plan returns the narrowest scan of the filter,
+// and a flag indicating if any transaction can match
+func (f *Filter) plan() (plan, bool) {
+	p := plan{
+		from: 0,
+		to:   math.MaxInt64,
+	}
+
+	conditions := []node{f.root}
+	if and, ok := f.root.(andNode); ok {
+		conditions = and
+	}
+
+	for _, n := range conditions {
+		c, ok := n.(*condition)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case c.field.name == fieldAddress && c.op == opEqual:
+			if p.address != "" && p.address != c.text {
+				return p, false
+			}
+
+			p.address = c.text
+		case c.field.name == fieldHeight:
+			switch c.op {
+			case opEqual:
+				p.from = max(p.from, c.number)
+				p.to = min(p.to, c.number)
+			case opGreater:
+				if c.number == math.MaxUint64 {
+					return p, false
+				}
+
+				p.from = max(p.from, c.number+1)
+			case opGreaterEqual:
+				p.from = max(p.from, c.number)
+			case opLess:
+				if c.number == 0 {
+					return p, false
+				}
+
+				p.to = min(p.to, c.number-1)
+			case opLessEqual:
+				p.to = min(p.to, c.number)
+			}
+		}
+	}
+
+	return p, p.from <= p.to
+}
+
+// Run returns a page of up to limit transactions matching the filter, in the (validated) ordering.
+// The cursor of the previous page is used to fetch the next one, with an empty cursor
+// starting from the first transaction. In the height (and time) orderings, oldest or newest first,
+// the page can hold fewer transactions than the limit, even while there are more, if the scanned
+// transaction limit is reached. The gas ordering scans the whole (narrowed) range,
+// and fails if it holds too many transactions
+func (f *Filter) Run(s Storage, ordering Ordering, cursor string, limit int) (*Page, error) {
+	if !ordering.Streamed() {
+		return f.runSorted(s, ordering, cursor, limit)
+	}
+
+	page := &Page{
+		Txs: make([]*types.TxResult, 0),
+	}
+
+	p, ok := f.plan()
+
+	// The cursor height is the first one scanned, in the ordering,
+	// starting from the cursor index
+	var (
+		cursorHeight uint64
+		cursorIndex  uint32
+		hasCursor    bool
+	)
+
+	if cursor != "" {
+		height, index, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		cursorHeight, cursorIndex, hasCursor = height, index, true
+
+		if ordering.Reverse() {
+			p.to = min(p.to, height)
+		} else {
+			p.from = max(p.from, height)
+		}
+	}
+
+	if !ok || p.from > p.to {
+		return page, nil
+	}
+
+	it, err := f.iterator(s, p, ordering.Reverse())
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate transactions, %w", err)
+	}
+
+	defer it.Close()
+
+	scanned := 0
+
+	for it.Next() {
+		tx, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if hasCursor && uint64(tx.Height) == cursorHeight {
+			// The transactions of the cursor height before the cursor one were on the previous pages
+			if (!ordering.Reverse() && tx.Index < cursorIndex) || (ordering.Reverse() && tx.Index > cursorIndex) {
+				continue
+			}
+		}
+
+		if len(page.Txs) == limit || scanned == MaxScannedTxs {
+			// There are more transactions to scan, point the cursor to the next one
+			page.Cursor = encodeCursor(uint64(tx.Height), tx.Index)
+
+			break
+		}
+
+		scanned++
+
+		if f.Matches(tx) {
+			page.Txs = append(page.Txs, tx)
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// runSorted returns a page of up to limit matching transactions, sorted by gas,
+// ranked after the cursor (if any)
+func (f *Filter) runSorted(s Storage, ordering Ordering, cursor string, limit int) (*Page, error) {
+	page := &Page{
+		Txs: make([]*types.TxResult, 0),
+	}
+
+	var after *Rank
+
+	if cursor != "" {
+		rank, err := decodeRankCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		after = &rank
+	}
+
+	p, ok := f.plan()
+
+	if !ok || p.from > p.to {
+		return page, nil
+	}
+
+	it, err := f.iterator(s, p, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate transactions, %w", err)
+	}
+
+	defer it.Close()
+
+	var (
+		selector = NewSelector(ordering, limit, after, TxRank(ordering))
+		scanned  = 0
+	)
+
+	for it.Next() {
+		tx, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if scanned == MaxScannedTxs {
+			return nil, ErrTooManyTxs
+		}
+
+		scanned++
+
+		if f.Matches(tx) {
+			selector.Add(tx)
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	page.Txs = selector.Elements()
+
+	if last, ok := selector.Last(); ok && selector.More() {
+		page.Cursor = encodeRankCursor(last)
+	}
+
+	return page, nil
+}
+
+// TxRank returns the transaction rank function of the ordering
+func TxRank(ordering Ordering) func(*types.TxResult) Rank {
+	return func(tx *types.TxResult) Rank {
+		rank := Rank{
+			Height: uint64(tx.Height),
+			Index:  tx.Index,
+		}
+
+		if ordering.SortBy == SortGas {
+			rank.Key = tx.Response.GasUsed
+		}
+
+		return rank
+	}
+}
+
+// iterator returns the iterator of the planned scan, newest first if reversed
+func (f *Filter) iterator(s Storage, p plan, reverse bool) (storage.Iterator[*types.TxResult], error) {
+	switch {
+	case p.address == "" && reverse:
+		return s.ReverseTxIterator(p.from, p.to, 0, 0)
+	case p.address == "":
+		return s.TxIterator(p.from, p.to, 0, 0)
+	case reverse:
+		// The address iterator upper height is exclusive
+		return s.ReverseTxByAddressIterator(p.address, p.from, p.to+1)
+	default:
+		return s.TxByAddressIterator(p.address, p.from, p.to+1)
+	}
+}
+
+// encodeCursor encodes the position of the next transaction as the page cursor
+func encodeCursor(height uint64, index uint32) string {
+	raw := make([]byte, cursorSize)
+
+	binary.BigEndian.PutUint64(raw, height)
+	binary.BigEndian.PutUint32(raw[8:], index)
+
+	return cursors.Encode(raw)
+}
+
+// decodeCursor decodes the position of the next transaction from the page cursor
+func decodeCursor(cursor string) (uint64, uint32, error) {
+	raw, err := cursors.Decode(cursor, cursorSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return binary.BigEndian.Uint64(raw), binary.BigEndian.Uint32(raw[8:]), nil
+}
+
+// encodeRankCursor encodes the rank of the last returned transaction as the sorted page cursor
+func encodeRankCursor(rank Rank) string {
+	raw := make([]byte, rankCursorSize)
+
+	binary.BigEndian.PutUint64(raw, uint64(rank.Key))
+	binary.BigEndian.PutUint64(raw[8:], rank.Height)
+	binary.BigEndian.PutUint32(raw[16:], rank.Index)
+
+	return cursors.Encode(raw)
+}
+
+// decodeRankCursor decodes the rank of the last returned transaction from the sorted page cursor
+func decodeRankCursor(cursor string) (Rank, error) {
+	raw, err := cursors.Decode(cursor, rankCursorSize)
+	if err != nil {
+		return Rank{}, err
+	}
+
+	return Rank{
+		Key:    int64(binary.BigEndian.Uint64(raw)),
+		Height: binary.BigEndian.Uint64(raw[8:]),
+		Index:  binary.BigEndian.Uint32(raw[16:]),
+	}, nil
+}
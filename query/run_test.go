@@ -0,0 +1,376 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// newTestStorage creates the storage with a transaction per height in [1, count],
+// alternating between the successful transfers and the failed realm calls
+func newTestStorage(t *testing.T, count int) *storage.Pebble {
+	t.Helper()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	wb := db.WriteBatch()
+
+	for height := 1; height <= count; height++ {
+		var (
+			msg      std.Msg
+			response abci.ResponseDeliverTx
+		)
+
+		if height%2 == 0 {
+			msg = vm.MsgCall{
+				Caller:  crypto.Address{1},
+				PkgPath: "gno.land/r/demo/boards",
+				Func:    "CreateThread",
+			}
+
+			response.Error = abci.StringError("call failed")
+		} else {
+			msg = bank.MsgSend{
+				FromAddress: crypto.Address{2},
+				ToAddress:   crypto.Address{3},
+				Amount:      std.NewCoins(std.NewCoin("ugnot", 10)),
+			}
+		}
+
+		response.GasUsed = int64(height * 1000)
+
+		require.NoError(t, wb.SetTx(&types.TxResult{
+			Height:   int64(height),
+			Tx:       amino.MustMarshal(std.Tx{Msgs: []std.Msg{msg}}),
+			Response: response,
+		}))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	return db
+}
+
+// heights returns the heights of the transactions
+func heights(txs []*types.TxResult) []int64 {
+	result := make([]int64, 0, len(txs))
+
+	for _, tx := range txs {
+		result = append(result, tx.Height)
+	}
+
+	return result
+}
+
+func TestFilter_Run(t *testing.T) {
+	t.Parallel()
+
+	db := newTestStorage(t, 20)
+
+	testTable := []struct {
+		name       string
+		expression string
+		expected   []int64
+	}{
+		{
+			"message type and realm",
+			"type=vm.m_call AND realm=gno.land/r/demo/boards AND height>14",
+			[]int64{16, 18, 20},
+		},
+		{
+			"message type alias",
+			"type=send AND height<=5",
+			[]int64{1, 3, 5},
+		},
+		{
+			"failed transactions",
+			"success=false AND (height=2 OR height=4)",
+			[]int64{2, 4},
+		},
+		{
+			"address index",
+			"address=" + crypto.Address{3}.String() + " AND height>=15",
+			[]int64{15, 17, 19},
+		},
+		{
+			"gas used",
+			"gas_used>=19000",
+			[]int64{19, 20},
+		},
+		{
+			"error code",
+			"error=StringError AND height<=6",
+			[]int64{2, 4, 6},
+		},
+		{
+			"not equal",
+			"type!=exec AND height>15",
+			[]int64{17, 19},
+		},
+		{
+			"empty height range",
+			"height>10 AND height<5",
+			[]int64{},
+		},
+		{
+			"conflicting addresses",
+			"address=" + crypto.Address{1}.String() + " AND address=" + crypto.Address{2}.String(),
+			[]int64{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := Parse(testCase.expression, nil)
+			require.NoError(t, err)
+
+			page, err := filter.Run(db, Ordering{}, "", 100)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, heights(page.Txs))
+			assert.Empty(t, page.Cursor)
+		})
+	}
+}
+
+func TestFilter_RunPages(t *testing.T) {
+	t.Parallel()
+
+	db := newTestStorage(t, 20)
+
+	filter, err := Parse("success=true OR height=20", nil)
+	require.NoError(t, err)
+
+	var (
+		cursor string
+		pages  [][]int64
+	)
+
+	for {
+		page, err := filter.Run(db, Ordering{}, cursor, 4)
+		require.NoError(t, err)
+
+		pages = append(pages, heights(page.Txs))
+
+		if page.Cursor == "" {
+			break
+		}
+
+		cursor = page.Cursor
+	}
+
+	assert.Equal(t, [][]int64{
+		{1, 3, 5, 7},
+		{9, 11, 13, 15},
+		{17, 19, 20},
+	}, pages)
+
+	// Make sure the malformed cursors are rejected
+	_, err = filter.Run(db, Ordering{}, "cursor", 4)
+	assert.ErrorIs(t, err, cursors.ErrInvalid)
+}
+
+func TestAddressFilter(t *testing.T) {
+	t.Parallel()
+
+	db := newTestStorage(t, 20)
+
+	address := crypto.Address{3}.String()
+
+	// Make sure the height range end is exclusive
+	page, err := AddressFilter(address, 15, 19, nil).Run(db, Ordering{}, "", 100)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{15, 17}, heights(page.Txs))
+	assert.Empty(t, page.Cursor)
+
+	// Make sure the 0 range end means no upper bound
+	page, err = AddressFilter(address, 15, 0, nil).Run(db, Ordering{}, "", 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{15, 17}, heights(page.Txs))
+	require.NotEmpty(t, page.Cursor)
+
+	page, err = AddressFilter(address, 15, 0, nil).Run(db, Ordering{}, page.Cursor, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{19}, heights(page.Txs))
+	assert.Empty(t, page.Cursor)
+
+	// Make sure the newest first pages follow the reverse cursors
+	newest := Ordering{Order: OrderDesc, SortBy: SortTime}
+
+	page, err = AddressFilter(address, 5, 0, nil).Run(db, newest, "", 4)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{19, 17, 15, 13}, heights(page.Txs))
+	require.NotEmpty(t, page.Cursor)
+
+	page, err = AddressFilter(address, 5, 0, nil).Run(db, newest, page.Cursor, 4)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{11, 9, 7, 5}, heights(page.Txs))
+	assert.Empty(t, page.Cursor)
+}
+
+func TestFilter_RunReverse(t *testing.T) {
+	t.Parallel()
+
+	db, err := storage.NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	_, txs := mock.GenerateChain(4, 3)
+
+	wb := db.WriteBatch()
+
+	for _, tx := range txs {
+		require.NoError(t, wb.SetTx(tx))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	filter, err := Parse("height>=2", nil)
+	require.NoError(t, err)
+
+	var (
+		cursor string
+		pages  [][]string
+	)
+
+	// Make sure the page cursors split the heights, newest first
+	for {
+		page, err := filter.Run(db, Ordering{Order: OrderDesc}, cursor, 2)
+		require.NoError(t, err)
+
+		positions := make([]string, 0, len(page.Txs))
+		for _, tx := range page.Txs {
+			positions = append(positions, fmt.Sprintf("%d-%d", tx.Height, tx.Index))
+		}
+
+		pages = append(pages, positions)
+
+		if page.Cursor == "" {
+			break
+		}
+
+		cursor = page.Cursor
+	}
+
+	assert.Equal(t, [][]string{
+		{"4-2", "4-1"},
+		{"4-0", "3-2"},
+		{"3-1", "3-0"},
+		{"2-2", "2-1"},
+		{"2-0"},
+	}, pages)
+}
+
+func TestFilter_RunSorted(t *testing.T) {
+	t.Parallel()
+
+	db := newTestStorage(t, 20)
+
+	testTable := []struct {
+		name       string
+		expression string
+		ordering   Ordering
+		expected   [][]int64
+	}{
+		{
+			"newest first",
+			"success=true OR height=20",
+			Ordering{Order: OrderDesc, SortBy: SortHeight},
+			[][]int64{
+				{20, 19, 17, 15},
+				{13, 11, 9, 7},
+				{5, 3, 1},
+			},
+		},
+		{
+			"most gas used first",
+			"height>=10 AND height<=15",
+			Ordering{Order: OrderDesc, SortBy: SortGas},
+			[][]int64{
+				{15, 14, 13, 12},
+				{11, 10},
+			},
+		},
+		{
+			"least gas used first",
+			"gas_used>=15000",
+			Ordering{Order: OrderAsc, SortBy: SortGas},
+			[][]int64{
+				{15, 16, 17, 18},
+				{19, 20},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := Parse(testCase.expression, nil)
+			require.NoError(t, err)
+
+			var (
+				cursor string
+				pages  [][]int64
+			)
+
+			for {
+				page, err := filter.Run(db, testCase.ordering, cursor, 4)
+				require.NoError(t, err)
+
+				pages = append(pages, heights(page.Txs))
+
+				if page.Cursor == "" {
+					break
+				}
+
+				cursor = page.Cursor
+			}
+
+			assert.Equal(t, testCase.expected, pages)
+		})
+	}
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := Parse("success=true", nil)
+		require.NoError(t, err)
+
+		// The cursors of the height orderings are not valid for the gas sorted pages
+		_, err = filter.Run(db, Ordering{Order: OrderDesc, SortBy: SortGas}, encodeCursor(10, 0), 4)
+		assert.ErrorIs(t, err, cursors.ErrInvalid)
+	})
+}
@@ -0,0 +1,162 @@
+package replication
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenMetadataKey is the stream metadata key carrying the shared replication token
+const tokenMetadataKey = "authorization"
+
+var (
+	errNoTLS  = errors.New("the replication requires TLS")
+	errNoAuth = errors.New("the replication requires a shared token, or verified client certificates (mTLS)")
+)
+
+// ServerTLSConfig loads the TLS config of the replication server, from the PEM certificate and key files.
+// If the CA file is set, the standby client certificates signed by it are required (mTLS)
+func ServerTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load certificate, %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		if config.ClientCAs, err = loadCertPool(caFile); err != nil {
+			return nil, err
+		}
+
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
+
+// StandbyTLSConfig loads the TLS config of the standby. The primary certificate is verified with the CA file
+// (or the system roots, if not set), and the client certificate (if set) is presented to the primary (mTLS)
+func StandbyTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		var err error
+
+		if config.RootCAs, err = loadCertPool(caFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load certificate, %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// loadCertPool loads the PEM CA certificates of the file
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	raw, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA file, %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(raw) {
+		return nil, fmt.Errorf("no CA certificate in %s", caFile)
+	}
+
+	return pool, nil
+}
+
+// checkServerAuth makes sure the standby streams are authenticated,
+// either by the shared token or the verified client certificates
+func checkServerAuth(config *tls.Config, token string) error {
+	if config == nil {
+		return errNoTLS
+	}
+
+	if token == "" && config.ClientAuth != tls.RequireAndVerifyClientCert {
+		return errNoAuth
+	}
+
+	return nil
+}
+
+// checkStandbyAuth makes sure the standby authenticates to the primary,
+// either with the shared token or a client certificate
+func checkStandbyAuth(config *tls.Config, token string) error {
+	if config == nil {
+		return errNoTLS
+	}
+
+	if token == "" && len(config.Certificates) == 0 && config.GetClientCertificate == nil {
+		return errNoAuth
+	}
+
+	return nil
+}
+
+// authenticate rejects the streams without the shared token, if set.
+// The client certificates (if required) are verified by the TLS handshake
+func (s *Server) authenticate(
+	srv any,
+	stream grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if s.token == "" {
+		return handler(srv, stream)
+	}
+
+	md, _ := metadata.FromIncomingContext(stream.Context())
+
+	values := md.Get(tokenMetadataKey)
+	if len(values) != 1 ||
+		subtle.ConstantTimeCompare([]byte(values[0]), []byte(bearer(s.token))) != 1 {
+		s.logger.Warn("rejected unauthenticated standby")
+
+		return status.Error(codes.Unauthenticated, "missing or invalid replication token")
+	}
+
+	return handler(srv, stream)
+}
+
+// bearer returns the metadata value of the token
+func bearer(token string) string {
+	return "Bearer " + token
+}
+
+var _ credentials.PerRPCCredentials = tokenCredentials("")
+
+// tokenCredentials are the standby stream credentials, carrying the shared token
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{tokenMetadataKey: bearer(string(t))}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,160 @@
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The replication messages are encoded following the
+// protobuf schemas in replication.proto. The fields are numbered as in the schemas
+
+// codecName is the name of the replication message codec
+const codecName = "proto"
+
+var errUnknownMessage = errors.New("unknown replication message")
+
+// message is a replication message, encoded in protobuf
+type message interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+// subscribeRequest is the standby request for the namespace changes
+type subscribeRequest struct {
+	// namespace is the replicated key namespace
+	namespace string
+
+	// fromHeight is the first height missing from the standby,
+	// or 0 if the standby namespace is empty
+	fromHeight uint64
+}
+
+func (r *subscribeRequest) marshal() []byte {
+	var b []byte
+
+	if r.namespace != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, r.namespace)
+	}
+
+	if r.fromHeight != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, r.fromHeight)
+	}
+
+	return b
+}
+
+func (r *subscribeRequest) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte, n uint64) {
+		switch num {
+		case 1:
+			r.namespace = string(v)
+		case 2:
+			r.fromHeight = n
+		}
+	})
+}
+
+// change is a message of the changes stream.
+// The stream starts with the snapshot of the data missing from the standby,
+// split in chunks, followed by the write batches committed by the primary
+type change struct {
+	// snapshot is a chunk of the storage backup of the missing data
+	snapshot []byte
+
+	// batch is a committed storage write batch
+	batch []byte
+}
+
+func (c *change) marshal() []byte {
+	var b []byte
+
+	if c.snapshot != nil {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, c.snapshot)
+	}
+
+	if c.batch != nil {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendBytes(b, c.batch)
+	}
+
+	return b
+}
+
+func (c *change) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte, _ uint64) {
+		switch num {
+		case 1:
+			c.snapshot = slices.Clone(v)
+		case 2:
+			c.batch = slices.Clone(v)
+		}
+	})
+}
+
+// consumeFields decodes the protobuf message fields, invoking the callback
+// with the bytes or varint value of each one. Unknown fields are skipped
+func consumeFields(b []byte, fieldFn func(num protowire.Number, bytes []byte, varint uint64)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		b = b[n:]
+
+		var (
+			bytes  []byte
+			varint uint64
+		)
+
+		switch typ {
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		b = b[n:]
+
+		fieldFn(num, bytes, varint)
+	}
+
+	return nil
+}
+
+// codec is the gRPC codec of the replication messages
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("%w, %T", errUnknownMessage, v)
+	}
+
+	return m.marshal(), nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("%w, %T", errUnknownMessage, v)
+	}
+
+	return m.unmarshal(data)
+}
+
+func (codec) Name() string {
+	return codecName
+}
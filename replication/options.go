@@ -0,0 +1,74 @@
+package replication
+
+import (
+	"crypto/tls"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Option func(s *Server)
+
+// WithLogger sets the logger to be used
+// with the replication server
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithQueueSize sets the number of committed batches queued for each standby.
+// Standby instances falling further behind are dropped, and catch up again once reconnected
+func WithQueueSize(batches int) Option {
+	return func(s *Server) {
+		s.queueSize = batches
+	}
+}
+
+// WithTLS sets the TLS config of the replication server. The standby
+// client certificates are verified if required by the config (mTLS)
+func WithTLS(config *tls.Config) Option {
+	return func(s *Server) {
+		s.tlsConfig = config
+	}
+}
+
+// WithToken sets the shared token required from the standby instances
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+type StandbyOption func(s *Standby)
+
+// WithStandbyLogger sets the logger to be used
+// with the standby
+func WithStandbyLogger(logger *zap.Logger) StandbyOption {
+	return func(s *Standby) {
+		s.logger = logger
+	}
+}
+
+// WithRetryInterval sets the interval between
+// the standby reconnects to the primary
+func WithRetryInterval(interval time.Duration) StandbyOption {
+	return func(s *Standby) {
+		s.retryInterval = interval
+	}
+}
+
+// WithStandbyTLS sets the TLS config of the standby connections to the primary,
+// presenting the client certificate of the config (if any) to the primary
+func WithStandbyTLS(config *tls.Config) StandbyOption {
+	return func(s *Standby) {
+		s.tlsConfig = config
+	}
+}
+
+// WithStandbyToken sets the shared token the standby authenticates with
+func WithStandbyToken(token string) StandbyOption {
+	return func(s *Standby) {
+		s.token = token
+	}
+}
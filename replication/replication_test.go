@@ -0,0 +1,428 @@
+package replication
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// saveBlocks saves dummy blocks in the given height range, each with a single tx
+func saveBlocks(t *testing.T, s *storage.Pebble, from, to int64) {
+	t.Helper()
+
+	wb := s.WriteBatch()
+
+	for height := from; height <= to; height++ {
+		require.NoError(t, wb.SetBlock(&types.Block{
+			Header: types.Header{
+				Height: height,
+				NumTxs: 1,
+			},
+		}))
+
+		require.NoError(t, wb.SetTx(&types.TxResult{
+			Height: height,
+			Tx:     []byte(fmt.Sprintf("tx %d", height)),
+		}))
+	}
+
+	require.NoError(t, wb.SetLatestHeight(uint64(to)))
+	require.NoError(t, wb.Commit())
+}
+
+// newStorage creates a new storage namespace, closed with the test
+func newStorage(t *testing.T, namespace string) *storage.Pebble {
+	t.Helper()
+
+	s, err := storage.NewPebble(t.TempDir(), storage.WithNamespace(namespace))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, s.Close())
+	})
+
+	return s
+}
+
+// testToken is the shared replication token of the tests
+const testToken = "replication token"
+
+// testCerts holds the PEM files of the test CA, and of the server and client certificates signed by it
+type testCerts struct {
+	ca         string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+}
+
+// writePEM writes the PEM block to the file of the directory, returning its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return path
+}
+
+// generateCerts generates the test CA, and the server (for 127.0.0.1) and client certificates
+func generateCerts(t *testing.T) testCerts {
+	t.Helper()
+
+	var (
+		dir   = t.TempDir()
+		certs = testCerts{}
+	)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	certs.ca = writePEM(t, dir, "ca.pem", "CERTIFICATE", caDER)
+
+	issue := func(name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		return writePEM(t, dir, name+".pem", "CERTIFICATE", der),
+			writePEM(t, dir, name+"-key.pem", "EC PRIVATE KEY", keyDER)
+	}
+
+	certs.serverCert, certs.serverKey = issue("server", 2, x509.ExtKeyUsageServerAuth)
+	certs.clientCert, certs.clientKey = issue("client", 3, x509.ExtKeyUsageClientAuth)
+
+	return certs
+}
+
+// serverTLS loads the server TLS config, requiring the client certificates if mTLS is set
+func (c testCerts) serverTLS(t *testing.T, mTLS bool) *tls.Config {
+	t.Helper()
+
+	caFile := ""
+	if mTLS {
+		caFile = c.ca
+	}
+
+	config, err := ServerTLSConfig(c.serverCert, c.serverKey, caFile)
+	require.NoError(t, err)
+
+	return config
+}
+
+// standbyTLS loads the standby TLS config, presenting the client certificate if mTLS is set
+func (c testCerts) standbyTLS(t *testing.T, mTLS bool) *tls.Config {
+	t.Helper()
+
+	certFile, keyFile := "", ""
+	if mTLS {
+		certFile, keyFile = c.clientCert, c.clientKey
+	}
+
+	config, err := StandbyTLSConfig(c.ca, certFile, keyFile)
+	require.NoError(t, err)
+
+	return config
+}
+
+// startServer starts the replication server on a random port, returning its address.
+// The server requires TLS and the test token, unless the options are given
+func startServer(t *testing.T, ctx context.Context, certs testCerts, stores []Storage, opts ...Option) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	if len(opts) == 0 {
+		opts = []Option{
+			WithTLS(certs.serverTLS(t, false)),
+			WithToken(testToken),
+		}
+	}
+
+	go func() {
+		_ = NewServer("", stores, opts...).serve(ctx, ln)
+	}()
+
+	return ln.Addr().String()
+}
+
+// newStandby creates the standby of the test server, authenticated with the test token
+func newStandby(t *testing.T, address string, certs testCerts, store StandbyStorage) *Standby {
+	t.Helper()
+
+	return NewStandby(
+		address,
+		store,
+		WithStandbyTLS(certs.standbyTLS(t, false)),
+		WithStandbyToken(testToken),
+		WithRetryInterval(10*time.Millisecond),
+	)
+}
+
+func TestReplication_Standby(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	var (
+		primary = newStorage(t, "chain")
+		standby = newStorage(t, "chain")
+	)
+
+	// Save the chain history before the standby connects
+	saveBlocks(t, primary, 1, 5)
+
+	var (
+		certs   = generateCerts(t)
+		address = startServer(t, ctx, certs, []Storage{primary})
+	)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- newStandby(t, address, certs, standby).Run(ctx)
+	}()
+
+	waitHeight := func(height uint64) {
+		t.Helper()
+
+		require.Eventually(t, func() bool {
+			latest, err := standby.GetLatestHeight()
+
+			return err == nil && latest == height
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Make sure the history is restored
+	waitHeight(5)
+
+	// Make sure the new writes are streamed
+	saveBlocks(t, primary, 6, 8)
+	waitHeight(8)
+
+	for height := uint64(1); height <= 8; height++ {
+		block, err := standby.GetBlock(height)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(height), block.Height)
+
+		hash := base64.StdEncoding.EncodeToString(types.Tx(fmt.Sprintf("tx %d", height)).Hash())
+
+		tx, err := standby.GetTxByHash(hash)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(height), tx.Height)
+	}
+
+	cancelFn()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("standby not stopped")
+	}
+}
+
+func TestReplication_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	var (
+		primary = newStorage(t, "chain")
+		standby = newStorage(t, "chain")
+	)
+
+	saveBlocks(t, primary, 1, 3)
+
+	var (
+		certs   = generateCerts(t)
+		address = startServer(t, ctx, certs, []Storage{primary})
+	)
+
+	// Replicate the history, and disconnect
+	standbyCtx, standbyCancelFn := context.WithCancel(ctx)
+
+	go func() {
+		_ = newStandby(t, address, certs, standby).Run(standbyCtx)
+	}()
+
+	require.Eventually(t, func() bool {
+		latest, err := standby.GetLatestHeight()
+
+		return err == nil && latest == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	standbyCancelFn()
+
+	// Make sure the standby catches up with the heights missed while disconnected
+	saveBlocks(t, primary, 4, 6)
+
+	go func() {
+		_ = newStandby(t, address, certs, standby).Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		latest, err := standby.GetLatestHeight()
+
+		return err == nil && latest == 6
+	}, 5*time.Second, 10*time.Millisecond)
+
+	block, err := standby.GetBlock(5)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5), block.Height)
+}
+
+func TestReplication_UnknownNamespace(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	var (
+		certs   = generateCerts(t)
+		address = startServer(t, ctx, certs, []Storage{newStorage(t, "chain")})
+	)
+
+	err := newStandby(t, address, certs, newStorage(t, "other")).replicate(ctx)
+	require.Error(t, err)
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestReplication_Authentication(t *testing.T) {
+	t.Parallel()
+
+	// The subtests outlive the test function
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	var (
+		certs   = generateCerts(t)
+		primary = newStorage(t, "chain")
+	)
+
+	saveBlocks(t, primary, 1, 3)
+
+	var (
+		tokenAddress = startServer(t, ctx, certs, []Storage{primary})
+		mTLSAddress  = startServer(t, ctx, certs, []Storage{primary}, WithTLS(certs.serverTLS(t, true)))
+	)
+
+	t.Run("missing or invalid token", func(t *testing.T) {
+		t.Parallel()
+
+		for _, opts := range [][]StandbyOption{
+			{WithStandbyTLS(certs.standbyTLS(t, true))},
+			{WithStandbyTLS(certs.standbyTLS(t, false)), WithStandbyToken("invalid")},
+		} {
+			err := NewStandby(tokenAddress, newStorage(t, "chain"), opts...).replicate(ctx)
+			require.Error(t, err)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		}
+	})
+
+	t.Run("missing client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		standby := NewStandby(
+			mTLSAddress,
+			newStorage(t, "chain"),
+			WithStandbyTLS(certs.standbyTLS(t, false)),
+			WithStandbyToken(testToken),
+		)
+
+		assert.Error(t, standby.replicate(ctx))
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		standby := newStorage(t, "chain")
+
+		go func() {
+			_ = NewStandby(
+				mTLSAddress,
+				standby,
+				WithStandbyTLS(certs.standbyTLS(t, true)),
+				WithRetryInterval(10*time.Millisecond),
+			).Run(ctx)
+		}()
+
+		require.Eventually(t, func() bool {
+			latest, err := standby.GetLatestHeight()
+
+			return err == nil && latest == 3
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unauthenticated configs", func(t *testing.T) {
+		t.Parallel()
+
+		// Make sure the server and standby don't run without the TLS and authentication
+		assert.ErrorIs(t, NewServer("127.0.0.1:0", nil).Serve(ctx), errNoTLS)
+		assert.ErrorIs(t, NewServer("127.0.0.1:0", nil, WithTLS(certs.serverTLS(t, false))).Serve(ctx), errNoAuth)
+
+		assert.ErrorIs(t, NewStandby(tokenAddress, newStorage(t, "chain")).Run(ctx), errNoTLS)
+		assert.ErrorIs(
+			t,
+			NewStandby(tokenAddress, newStorage(t, "chain"), WithStandbyTLS(certs.standbyTLS(t, false))).Run(ctx),
+			errNoAuth,
+		)
+	})
+}
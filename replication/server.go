@@ -0,0 +1,214 @@
+package replication
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+const (
+	// DefaultQueueSize is the default number of committed batches
+	// queued for each standby, before the standby is dropped
+	DefaultQueueSize = 1000
+
+	// snapshotChunkSize is the size of the snapshot chunks streamed to the standby
+	snapshotChunkSize = 256 * 1024
+)
+
+// Storage is the primary storage, whose changes are streamed to the standby instances
+type Storage interface {
+	// Namespace returns the storage key namespace
+	Namespace() string
+
+	// Backup writes the namespace data of the heights starting from the given one
+	Backup(w io.Writer, fromHeight uint64) (*storage.BackupInfo, error)
+
+	// SubscribeChanges subscribes to the write batches committed to the namespace
+	SubscribeChanges(size int) (<-chan []byte, func())
+}
+
+// Server is the replication gRPC server, streaming the changes
+// of the primary storage namespaces to the standby instances
+type Server struct {
+	stores map[string]Storage
+	logger *zap.Logger
+
+	tlsConfig *tls.Config
+	token     string
+
+	addr      string
+	queueSize int
+}
+
+// NewServer creates a new replication server, serving the given storage namespaces.
+// The server requires TLS, and the standby authentication with the shared token
+// or the verified client certificates (see WithTLS and WithToken)
+func NewServer(addr string, stores []Storage, opts ...Option) *Server {
+	s := &Server{
+		stores:    make(map[string]Storage, len(stores)),
+		logger:    zap.NewNop(),
+		addr:      addr,
+		queueSize: DefaultQueueSize,
+	}
+
+	for _, store := range stores {
+		s.stores[store.Namespace()] = store
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Serve serves the replication server, until the context is cancelled
+func (s *Server) Serve(ctx context.Context) error {
+	if err := checkServerAuth(s.tlsConfig, s.token); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("unable to listen for replication, %w", err)
+	}
+
+	return s.serve(ctx, ln)
+}
+
+func (s *Server) serve(ctx context.Context, ln net.Listener) error {
+	gs := grpc.NewServer(
+		grpc.ForceServerCodec(codec{}),
+		grpc.Creds(credentials.NewTLS(s.tlsConfig)),
+		grpc.StreamInterceptor(s.authenticate),
+	)
+	gs.RegisterService(&serviceDesc, s)
+
+	s.logger.Info(
+		"replication server started",
+		zap.String("address", ln.Addr().String()),
+	)
+
+	go func() {
+		<-ctx.Done()
+
+		// The change streams don't end on their own
+		gs.Stop()
+	}()
+
+	defer s.logger.Info("replication server shut down")
+
+	return gs.Serve(ln)
+}
+
+// subscribe streams the data missing from the standby,
+// followed by the write batches committed by the primary
+func (s *Server) subscribe(req *subscribeRequest, stream grpc.ServerStream) error {
+	store, ok := s.stores[req.namespace]
+	if !ok {
+		return status.Errorf(codes.NotFound, "unknown namespace %q", req.namespace)
+	}
+
+	logger := s.logger.With(
+		zap.String("namespace", req.namespace),
+		zap.Uint64("from", req.fromHeight),
+	)
+
+	// Subscribe before the snapshot, so no committed batch is missed.
+	// The batches already contained in the snapshot are applied again, to the same result
+	changes, unsubscribe := store.SubscribeChanges(s.queueSize)
+	defer unsubscribe()
+
+	sw := bufio.NewWriterSize(&snapshotWriter{stream: stream}, snapshotChunkSize)
+
+	info, err := store.Backup(sw, req.fromHeight)
+	if err != nil {
+		logger.Error("unable to stream snapshot", zap.Error(err))
+
+		return status.Errorf(codes.Internal, "unable to stream snapshot, %v", err)
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	logger.Info(
+		"standby subscribed",
+		zap.Uint64("snapshot-to", info.To),
+		zap.Int("snapshot-records", info.Records),
+	)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			logger.Info("standby unsubscribed")
+
+			return nil
+		case batch, ok := <-changes:
+			if !ok {
+				logger.Warn("standby fell behind the primary changes")
+
+				return status.Error(codes.ResourceExhausted, "standby fell behind the primary changes")
+			}
+
+			if err := stream.SendMsg(&change{batch: batch}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// snapshotWriter streams the written data as snapshot chunks
+type snapshotWriter struct {
+	stream grpc.ServerStream
+}
+
+func (w *snapshotWriter) Write(p []byte) (int, error) {
+	if err := w.stream.SendMsg(&change{snapshot: p}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// replicationServer is the replication service implementation
+type replicationServer interface {
+	subscribe(req *subscribeRequest, stream grpc.ServerStream) error
+}
+
+// serviceDesc is the replication service description,
+// following the service in replication.proto
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: "txindexer.replication.Replication",
+	HandlerType: (*replicationServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Subscribe",
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				req := &subscribeRequest{}
+				if err := stream.RecvMsg(req); err != nil {
+					return err
+				}
+
+				return srv.(replicationServer).subscribe(req, stream)
+			},
+			ServerStreams: true,
+		},
+	},
+	Metadata: "replication/replication.proto",
+}
+
+// subscribeMethod is the full name of the subscribe method
+const subscribeMethod = "/txindexer.replication.Replication/Subscribe"
@@ -0,0 +1,215 @@
+package replication
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/gnolang/tx-indexer/storage"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// DefaultRetryInterval is the default interval
+// between the standby reconnects to the primary
+const DefaultRetryInterval = 5 * time.Second
+
+// StandbyStorage is the standby storage, kept in sync with the primary
+type StandbyStorage interface {
+	// Namespace returns the storage key namespace
+	Namespace() string
+
+	// GetLatestHeight returns the latest saved height
+	GetLatestHeight() (uint64, error)
+
+	// Restore restores the namespace data from the backup
+	Restore(r io.Reader) (*storage.BackupInfo, error)
+
+	// ApplyChanges applies the write batch committed by the primary
+	ApplyChanges(batch []byte) error
+}
+
+// Standby keeps the standby storage namespace in sync with the primary,
+// by applying the changes streamed by the primary replication server
+type Standby struct {
+	store  StandbyStorage
+	logger *zap.Logger
+
+	tlsConfig *tls.Config
+	token     string
+
+	primary       string
+	retryInterval time.Duration
+}
+
+// NewStandby creates a new standby, replicating the primary at the given address.
+// The standby requires TLS, and authenticates with the shared token or
+// the client certificate (see WithStandbyTLS and WithStandbyToken)
+func NewStandby(primary string, store StandbyStorage, opts ...StandbyOption) *Standby {
+	s := &Standby{
+		store:         store,
+		logger:        zap.NewNop(),
+		primary:       primary,
+		retryInterval: DefaultRetryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Run replicates the primary until the context is cancelled,
+// reconnecting whenever the change stream is interrupted
+func (s *Standby) Run(ctx context.Context) error {
+	if err := checkStandbyAuth(s.tlsConfig, s.token); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(s.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		err := s.replicate(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		s.logger.Warn("replication interrupted", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// replicate subscribes to the primary changes, starting from
+// the first height missing from the standby storage
+func (s *Standby) replicate(ctx context.Context) error {
+	var fromHeight uint64
+
+	latest, err := s.store.GetLatestHeight()
+
+	switch {
+	case errors.Is(err, storageErrors.ErrNotFound):
+		// Empty namespace, the full data is restored
+	case err != nil:
+		return fmt.Errorf("unable to fetch latest height, %w", err)
+	default:
+		fromHeight = latest + 1
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(s.tlsConfig)),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec{})),
+	}
+
+	if s.token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials(s.token)))
+	}
+
+	conn, err := grpc.NewClient(s.primary, dialOpts...)
+	if err != nil {
+		return fmt.Errorf("unable to create replication client, %w", err)
+	}
+
+	defer conn.Close()
+
+	streamCtx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	stream, err := conn.NewStream(streamCtx, &serviceDesc.Streams[0], subscribeMethod)
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to the primary, %w", err)
+	}
+
+	req := &subscribeRequest{
+		namespace:  s.store.Namespace(),
+		fromHeight: fromHeight,
+	}
+
+	if err := stream.SendMsg(req); err != nil {
+		return fmt.Errorf("unable to subscribe to the primary, %w", err)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return fmt.Errorf("unable to subscribe to the primary, %w", err)
+	}
+
+	// The snapshot chunks are restored as they are received
+	var (
+		pr, pw   = io.Pipe()
+		restored = make(chan error, 1)
+	)
+
+	go func() {
+		info, err := s.store.Restore(pr)
+		if err == nil {
+			s.logger.Info(
+				"snapshot restored",
+				zap.Uint64("from", info.From),
+				zap.Uint64("to", info.To),
+				zap.Int("records", info.Records),
+			)
+		}
+
+		// Unblock the snapshot writes, if the restore failed
+		pr.CloseWithError(err)
+
+		restored <- err
+	}()
+
+	// Make sure the restore doesn't outlive the stream
+	defer pw.Close()
+
+	snapshot := true
+
+	for {
+		msg := &change{}
+		if err := stream.RecvMsg(msg); err != nil {
+			return fmt.Errorf("unable to receive changes, %w", err)
+		}
+
+		if msg.snapshot != nil {
+			if !snapshot {
+				return errors.New("unexpected snapshot chunk")
+			}
+
+			if _, err := pw.Write(msg.snapshot); err != nil {
+				// The restore ended before the snapshot
+				if restoreErr := <-restored; restoreErr != nil {
+					err = restoreErr
+				}
+
+				return fmt.Errorf("unable to restore snapshot, %w", err)
+			}
+
+			continue
+		}
+
+		if snapshot {
+			// The batches follow the complete snapshot
+			snapshot = false
+
+			if err := <-restored; err != nil {
+				return fmt.Errorf("unable to restore snapshot, %w", err)
+			}
+		}
+
+		if err := s.store.ApplyChanges(msg.batch); err != nil {
+			return fmt.Errorf("unable to apply changes, %w", err)
+		}
+	}
+}
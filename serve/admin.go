@@ -0,0 +1,139 @@
+package serve
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// AdminMethodPrefix is the prefix of the admin methods, requiring the admin token
+	AdminMethodPrefix = "admin_"
+
+	// headerAdminToken is the header carrying the admin token
+	headerAdminToken = "X-Admin-Token"
+
+	// headerAdminActor is the header carrying the (optional) operator name,
+	// recorded in the audit log
+	headerAdminActor = "X-Admin-Actor"
+
+	// auditRejectedLimit is the number of rejected (unauthorized) admin calls
+	// recorded per minute, per client IP. The rejected calls over the limit are
+	// not recorded, so the token guessing can't flood the append-only log
+	auditRejectedLimit = 10
+)
+
+// AuditLog is the audit log of the admin method calls
+type AuditLog interface {
+	// Append appends the entry to the log
+	Append(entry *audit.Entry) error
+}
+
+// AdminMiddleware requires the admin token of the X-Admin-Token header (or the WS handshake one)
+// for the admin methods. The admin methods are rejected if the token isn't set
+func AdminMiddleware(token string) Middleware {
+	return func(method string, next Handler) Handler {
+		if !strings.HasPrefix(method, AdminMethodPrefix) {
+			return next
+		}
+
+		return func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			if !isAdmin(metadata.Header, token) {
+				return nil, spec.GenerateUnauthorizedError("Missing or invalid admin token")
+			}
+
+			return next(metadata, params)
+		}
+	}
+}
+
+// isAdmin checks if the headers carry the (set) admin token
+func isAdmin(header http.Header, token string) bool {
+	if token == "" || header == nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(header.Get(headerAdminToken)), []byte(token)) == 1
+}
+
+// AuditMiddleware records the admin method calls to the audit log, along with their caller,
+// params and result. It precedes the AdminMiddleware, so the rejected calls are recorded as well,
+// up to auditRejectedLimit per minute, per client IP.
+// The client IP is read from the X-Real-IP header only if trustProxies is set, as the header is then
+// resolved by the IP filter from the trusted proxies. Otherwise, it's the request remote address
+func AuditMiddleware(log AuditLog, trustProxies bool, logger *zap.Logger) Middleware {
+	rejected := &methodRateLimiter{
+		limit:  auditRejectedLimit,
+		counts: make(map[string]int),
+		now:    time.Now,
+	}
+
+	return func(method string, next Handler) Handler {
+		if !strings.HasPrefix(method, AdminMethodPrefix) {
+			return next
+		}
+
+		return func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			response, err := next(metadata, params)
+
+			entry := &audit.Entry{
+				Method:   method,
+				Remote:   metadata.RemoteAddr,
+				ClientIP: clientIP(metadata, trustProxies),
+				Params:   json.RawMessage("[]"),
+			}
+
+			if metadata.Header != nil {
+				entry.Actor = metadata.Header.Get(headerAdminActor)
+				entry.UserAgent = metadata.Header.Get("User-Agent")
+			}
+
+			if len(params) != 0 {
+				if raw, marshalErr := json.Marshal(params); marshalErr == nil {
+					entry.Params = raw
+				}
+			}
+
+			if err != nil {
+				entry.Code = err.Code
+				entry.Error = err.Message
+			}
+
+			if entry.Code == spec.UnauthorizedErrorCode {
+				if _, ok := rejected.allow(entry.ClientIP); !ok {
+					logger.Debug(
+						"rejected admin call not recorded, limit reached",
+						zap.String("method", method),
+						zap.String("from", entry.ClientIP),
+					)
+
+					return response, err
+				}
+			}
+
+			if appendErr := log.Append(entry); appendErr != nil {
+				logger.Error(
+					"unable to record admin call",
+					zap.String("method", method),
+					zap.Error(appendErr),
+				)
+			}
+
+			return response, err
+		}
+	}
+}
+
+// clientIP returns the client IP of the request, from the X-Real-IP header
+// (if resolved by the IP filter from the trusted proxies), or the remote address
+func clientIP(metadata *metadata.Metadata, trustProxies bool) string {
+	return requestIP(metadata.RemoteAddr, metadata.Header, trustProxies)
+}
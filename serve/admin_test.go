@@ -0,0 +1,245 @@
+package serve
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// mockAuditLog is the in-memory audit log
+type mockAuditLog struct {
+	entries []*audit.Entry
+}
+
+func (m *mockAuditLog) Append(entry *audit.Entry) error {
+	m.entries = append(m.entries, entry)
+
+	return nil
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+		return true, nil
+	}
+
+	testTable := []struct {
+		name    string
+		method  string
+		token   string
+		header  string
+		allowed bool
+	}{
+		{
+			"non-admin method",
+			"getBlock",
+			"admin",
+			"",
+			true,
+		},
+		{
+			"valid admin token",
+			AdminMethodPrefix + "getApiKeyUsage",
+			"admin",
+			"admin",
+			true,
+		},
+		{
+			"invalid admin token",
+			AdminMethodPrefix + "getApiKeyUsage",
+			"admin",
+			"invalid",
+			false,
+		},
+		{
+			"admin token not set",
+			AdminMethodPrefix + "getApiKeyUsage",
+			"",
+			"",
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			header.Set(headerAdminToken, testCase.header)
+
+			response, err := AdminMiddleware(testCase.token)(testCase.method, handler)(
+				metadata.NewMetadata("", metadata.WithHeader(header)),
+				nil,
+			)
+
+			if testCase.allowed {
+				require.Nil(t, err)
+				assert.Equal(t, true, response)
+
+				return
+			}
+
+			require.NotNil(t, err)
+			assert.Equal(t, spec.UnauthorizedErrorCode, err.Code)
+		})
+	}
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		log        = &mockAuditLog{}
+		middleware = AuditMiddleware(log, true, zap.NewNop())
+
+		header = http.Header{}
+	)
+
+	header.Set(headerAdminActor, "operator")
+	header.Set(headerRealIP, "203.0.113.10")
+	header.Set("User-Agent", "curl/8.0")
+
+	md := metadata.NewMetadata("10.0.0.1:43210", metadata.WithHeader(header))
+
+	// Make sure the non-admin methods are not recorded
+	_, err := middleware("getBlock", func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+		return true, nil
+	})(md, []any{1})
+	require.Nil(t, err)
+
+	assert.Empty(t, log.entries)
+
+	// Make sure the admin calls are recorded, along with their result
+	_, err = middleware(AdminMethodPrefix+"deleteApiKey", func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+		return nil, spec.GenerateNotFoundError("API key", "key")
+	})(md, []any{"key"})
+	require.NotNil(t, err)
+
+	_, err = middleware(AdminMethodPrefix+"getApiKeyUsage", func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+		return true, nil
+	})(metadata.NewMetadata("10.0.0.2:43210"), nil)
+	require.Nil(t, err)
+
+	require.Len(t, log.entries, 2)
+
+	failed := log.entries[0]
+
+	assert.Equal(t, AdminMethodPrefix+"deleteApiKey", failed.Method)
+	assert.Equal(t, "operator", failed.Actor)
+	assert.Equal(t, "10.0.0.1:43210", failed.Remote)
+	assert.Equal(t, "203.0.113.10", failed.ClientIP)
+	assert.Equal(t, "curl/8.0", failed.UserAgent)
+	assert.JSONEq(t, `["key"]`, string(failed.Params))
+	assert.Equal(t, spec.NotFoundErrorCode, failed.Code)
+	assert.NotEmpty(t, failed.Error)
+
+	succeeded := log.entries[1]
+
+	assert.Equal(t, "10.0.0.2", succeeded.ClientIP)
+	assert.JSONEq(t, `[]`, string(succeeded.Params))
+	assert.Zero(t, succeeded.Code)
+	assert.Empty(t, succeeded.Error)
+}
+
+func TestAuditMiddleware_UntrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		log         = &mockAuditLog{}
+		middlewares = []Middleware{
+			AuditMiddleware(log, false, zap.NewNop()),
+			AdminMiddleware("token"),
+		}
+
+		header = http.Header{}
+	)
+
+	header.Set(headerAdminToken, "invalid")
+	header.Set(headerRealIP, "203.0.113.10")
+
+	handler := applyMiddlewares(
+		AdminMethodPrefix+"deleteApiKey",
+		func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+			t.Fatal("unauthorized call should not be handled")
+
+			return nil, nil
+		},
+		middlewares,
+	)
+
+	_, err := handler(metadata.NewMetadata("10.0.0.1:43210", metadata.WithHeader(header)), []any{"key"})
+	require.NotNil(t, err)
+
+	// Make sure the rejected call is recorded, with the remote address
+	// instead of the spoofed client IP header
+	require.Len(t, log.entries, 1)
+
+	rejected := log.entries[0]
+
+	assert.Equal(t, AdminMethodPrefix+"deleteApiKey", rejected.Method)
+	assert.Equal(t, "10.0.0.1", rejected.ClientIP)
+	assert.Equal(t, spec.UnauthorizedErrorCode, rejected.Code)
+}
+
+func TestAuditMiddleware_RejectedLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		log         = &mockAuditLog{}
+		middlewares = []Middleware{
+			AuditMiddleware(log, false, zap.NewNop()),
+			AdminMiddleware("token"),
+		}
+
+		invalid = http.Header{}
+		valid   = http.Header{}
+	)
+
+	invalid.Set(headerAdminToken, "invalid")
+	valid.Set(headerAdminToken, "token")
+
+	handler := applyMiddlewares(
+		AdminMethodPrefix+"getAuditLog",
+		func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+			return true, nil
+		},
+		middlewares,
+	)
+
+	call := func(remote string, header http.Header) *spec.BaseJSONError {
+		_, err := handler(metadata.NewMetadata(remote, metadata.WithHeader(header)), nil)
+
+		return err
+	}
+
+	// Make sure the rejected calls over the limit are not recorded
+	for i := 0; i < auditRejectedLimit+5; i++ {
+		err := call("10.0.0.1:43210", invalid)
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.UnauthorizedErrorCode, err.Code)
+	}
+
+	require.Len(t, log.entries, auditRejectedLimit)
+
+	// Make sure the rejected calls of the other IPs are still recorded
+	require.NotNil(t, call("10.0.0.2:43210", invalid))
+	require.Len(t, log.entries, auditRejectedLimit+1)
+
+	// Make sure the authorized calls are always recorded
+	require.Nil(t, call("10.0.0.1:43210", valid))
+	require.Len(t, log.entries, auditRejectedLimit+2)
+
+	assert.Equal(t, "10.0.0.1", log.entries[auditRejectedLimit+1].ClientIP)
+	assert.Zero(t, log.entries[auditRejectedLimit+1].Code)
+}
@@ -0,0 +1,206 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/apikeys"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// headerAPIKey is the header carrying the API key
+	headerAPIKey = "X-API-Key"
+
+	// queryAPIKey is the query param carrying the API key,
+	// for the (browser) WS clients unable to set the handshake headers
+	queryAPIKey = "apiKey"
+)
+
+// APIKeys authenticates the API keys, and accounts for their usage
+type APIKeys interface {
+	// Authenticate returns the name of the API key, if it exists
+	Authenticate(secret string) (string, bool)
+
+	// Allow checks if the API key is within its quota,
+	// returning the exceeded quota otherwise
+	Allow(name string) (*apikeys.Exceeded, bool)
+
+	// Record accounts for the requests and response bytes of the API key
+	Record(name string, requests, bytes uint64)
+}
+
+// apiKeyContextKey is the request context key of the API key name
+type apiKeyContextKey struct{}
+
+// APIKeyAuth requires an API key for the requests, enforcing the usage quotas of the keys.
+// The requests with the admin token don't require an API key, and are not accounted for
+type APIKeyAuth struct {
+	keys   APIKeys
+	logger *zap.Logger
+	now    func() time.Time
+
+	adminToken string
+}
+
+// NewAPIKeyAuth creates a new API key authentication, with the optional admin token
+func NewAPIKeyAuth(keys APIKeys, adminToken string, logger *zap.Logger) *APIKeyAuth {
+	return &APIKeyAuth{
+		keys:       keys,
+		logger:     logger,
+		now:        time.Now,
+		adminToken: adminToken,
+	}
+}
+
+// Middleware authenticates the HTTP requests (and WS handshakes) by the API key of the X-API-Key header,
+// or the apiKey query param. Each HTTP request (or batch) is accounted for along with its response bytes,
+// while the WS requests are accounted for by the method middleware, per method call
+func (a *APIKeyAuth) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isAdmin(r.Header, a.adminToken) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			secret := r.Header.Get(headerAPIKey)
+			if secret == "" {
+				secret = r.URL.Query().Get(queryAPIKey)
+			}
+
+			name, ok := a.keys.Authenticate(secret)
+			if !ok {
+				a.writeError(w, http.StatusUnauthorized, spec.GenerateUnauthorizedError("Missing or invalid API key"))
+
+				return
+			}
+
+			if err := a.allow(name); err != nil {
+				a.logger.Debug("API key over quota", zap.String("key", name))
+
+				status := http.StatusTooManyRequests
+				if err.Code == spec.UnauthorizedErrorCode {
+					status = http.StatusUnauthorized
+				} else {
+					w.Header().Set("Retry-After", strconv.Itoa(err.Data.RetryAfter))
+				}
+
+				a.writeError(w, status, err)
+
+				return
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, name))
+
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			a.keys.Record(name, 1, uint64(ww.BytesWritten()))
+		})
+	}
+}
+
+// MethodMiddleware enforces the quotas of the WS requests, and accounts for them
+// along with their (JSON encoded) response size. The HTTP requests are skipped,
+// as they're accounted for by the HTTP middleware
+func (a *APIKeyAuth) MethodMiddleware() Middleware {
+	return func(_ string, next Handler) Handler {
+		return func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			if !metadata.IsWS() {
+				return next(metadata, params)
+			}
+
+			record, err := a.AllowWS(metadata.Context())
+			if err != nil {
+				return nil, err
+			}
+
+			response, err := next(metadata, params)
+
+			var encoded any = response
+			if err != nil {
+				encoded = err
+			}
+
+			size := 0
+			if raw, marshalErr := json.Marshal(encoded); marshalErr == nil {
+				size = len(raw)
+			}
+
+			record(1, uint64(size))
+
+			return response, err
+		}
+	}
+}
+
+// AllowWS enforces the quota of the API key authenticated by the WS handshake of the context, for the requests
+// over the WS connections of the servers other than the JSON-RPC one (ex. GraphQL). The returned func accounts
+// for the requests and response bytes of the API key, and is a no-op if the handshake had no API key (admin)
+func (a *APIKeyAuth) AllowWS(ctx context.Context) (func(requests, bytes uint64), *spec.BaseJSONError) {
+	name := apiKeyName(ctx)
+	if name == "" {
+		return func(uint64, uint64) {}, nil
+	}
+
+	if err := a.allow(name); err != nil {
+		return nil, err
+	}
+
+	return func(requests, bytes uint64) {
+		a.keys.Record(name, requests, bytes)
+	}, nil
+}
+
+// apiKeyName returns the name of the API key authenticated by the request (or WS handshake) of the context,
+// empty if there is none (admin, or without API keys)
+func apiKeyName(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyContextKey{}).(string)
+
+	return name
+}
+
+// allow checks if the API key is within its quota, returning the quota exceeded error
+// otherwise. The unauthorized error is returned for the keys deleted since the handshake
+func (a *APIKeyAuth) allow(name string) *spec.BaseJSONError {
+	exceeded, ok := a.keys.Allow(name)
+	if ok {
+		return nil
+	}
+
+	if exceeded == nil {
+		return spec.GenerateUnauthorizedError("Missing or invalid API key")
+	}
+
+	retryAfter := int(math.Ceil(exceeded.Reset.Sub(a.now()).Seconds()))
+
+	return spec.GenerateQuotaExceededError(exceeded.Period, exceeded.Resource, exceeded.Limit, retryAfter)
+}
+
+// writeError writes the JSON-RPC error response, with the HTTP status
+func (a *APIKeyAuth) writeError(w http.ResponseWriter, status int, err *spec.BaseJSONError) {
+	w.Header().Set("Content-Type", jsonMimeType)
+	w.WriteHeader(status)
+
+	if encodeErr := json.NewEncoder(w).Encode(spec.NewJSONResponse(0, nil, err)); encodeErr != nil {
+		a.logger.Debug("unable to write API key error response", zap.Error(encodeErr))
+	}
+}
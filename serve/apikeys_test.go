@@ -0,0 +1,275 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/apikeys"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// mockAPIKeys is the API key manager with a single key
+type mockAPIKeys struct {
+	exceeded *apikeys.Exceeded
+
+	requests uint64
+	bytes    uint64
+}
+
+func (m *mockAPIKeys) Authenticate(secret string) (string, bool) {
+	return "key", secret == "secret"
+}
+
+func (m *mockAPIKeys) Allow(string) (*apikeys.Exceeded, bool) {
+	return m.exceeded, m.exceeded == nil
+}
+
+func (m *mockAPIKeys) Record(_ string, requests, bytes uint64) {
+	m.requests += requests
+	m.bytes += bytes
+}
+
+func TestAPIKeyAuth_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now  = time.Date(2024, time.March, 10, 23, 59, 30, 0, time.UTC)
+		body = `{"jsonrpc":"2.0","id":1,"result":true}`
+	)
+
+	testTable := []struct {
+		name     string
+		exceeded *apikeys.Exceeded
+		setFn    func(r *http.Request)
+
+		expectedStatus   int
+		expectedCode     int
+		expectedRequests uint64
+	}{
+		{
+			"missing API key",
+			nil,
+			func(*http.Request) {},
+			http.StatusUnauthorized,
+			spec.UnauthorizedErrorCode,
+			0,
+		},
+		{
+			"invalid API key",
+			nil,
+			func(r *http.Request) {
+				r.Header.Set(headerAPIKey, "invalid")
+			},
+			http.StatusUnauthorized,
+			spec.UnauthorizedErrorCode,
+			0,
+		},
+		{
+			"header API key",
+			nil,
+			func(r *http.Request) {
+				r.Header.Set(headerAPIKey, "secret")
+			},
+			http.StatusOK,
+			0,
+			1,
+		},
+		{
+			"query API key",
+			nil,
+			func(r *http.Request) {
+				r.URL.RawQuery = queryAPIKey + "=secret"
+			},
+			http.StatusOK,
+			0,
+			1,
+		},
+		{
+			"admin token",
+			nil,
+			func(r *http.Request) {
+				r.Header.Set(headerAdminToken, "admin")
+			},
+			http.StatusOK,
+			0,
+			0,
+		},
+		{
+			"quota exceeded",
+			&apikeys.Exceeded{
+				Period:   apikeys.PeriodDaily,
+				Resource: apikeys.ResourceRequests,
+				Limit:    100,
+				Reset:    time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC),
+			},
+			func(r *http.Request) {
+				r.Header.Set(headerAPIKey, "secret")
+			},
+			http.StatusTooManyRequests,
+			spec.QuotaExceededErrorCode,
+			0,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys := &mockAPIKeys{exceeded: testCase.exceeded}
+
+			auth := NewAPIKeyAuth(keys, "admin", zap.NewNop())
+			auth.now = func() time.Time {
+				return now
+			}
+
+			handler := auth.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			testCase.setFn(r)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			require.Equal(t, testCase.expectedStatus, rec.Code)
+			assert.Equal(t, testCase.expectedRequests, keys.requests)
+
+			if testCase.expectedCode == 0 {
+				assert.Equal(t, body, rec.Body.String())
+				assert.Equal(t, testCase.expectedRequests*uint64(len(body)), keys.bytes)
+
+				return
+			}
+
+			var response spec.BaseJSONResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+
+			require.NotNil(t, response.Error)
+			assert.Equal(t, testCase.expectedCode, response.Error.Code)
+
+			if testCase.exceeded != nil {
+				assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+				assert.Equal(t, 30, response.Error.Data.RetryAfter)
+				assert.Equal(t, spec.ScopeDaily, response.Error.Data.Scope)
+				assert.Equal(t, apikeys.ResourceRequests, response.Error.Data.Resource)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_MethodMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		keys = &mockAPIKeys{}
+		auth = NewAPIKeyAuth(keys, "", zap.NewNop())
+
+		handler = auth.MethodMiddleware()("getBlock", func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+			return "block", nil
+		})
+	)
+
+	// Authenticate the WS handshake
+	var md *metadata.Metadata
+
+	auth.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		md = metadata.NewMetadata(
+			r.RemoteAddr,
+			metadata.WithWebSocketID("ws"),
+			metadata.WithContext(r.Context()),
+		)
+	})).ServeHTTP(httptest.NewRecorder(), func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		r.Header.Set(headerAPIKey, "secret")
+		r.Header.Set("Upgrade", "websocket")
+
+		return r
+	}())
+
+	require.NotNil(t, md)
+
+	// The handshake is not accounted for
+	assert.Zero(t, keys.requests)
+
+	// Make sure the WS requests are accounted for
+	response, err := handler(md, nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, "block", response)
+	assert.Equal(t, uint64(1), keys.requests)
+	assert.Equal(t, uint64(len(`"block"`)), keys.bytes)
+
+	// Make sure the HTTP requests are skipped
+	_, err = handler(metadata.NewMetadata("", metadata.WithContext(md.Context())), nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, uint64(1), keys.requests)
+
+	// Make sure the quotas are enforced
+	keys.exceeded = &apikeys.Exceeded{
+		Period:   apikeys.PeriodMonthly,
+		Resource: apikeys.ResourceBytes,
+		Limit:    10,
+		Reset:    time.Now().Add(time.Hour),
+	}
+
+	response, err = handler(md, nil)
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+	assert.Equal(t, spec.QuotaExceededErrorCode, err.Code)
+	assert.Equal(t, uint64(1), keys.requests)
+}
+
+func TestAPIKeyAuth_AllowWS(t *testing.T) {
+	t.Parallel()
+
+	var (
+		keys = &mockAPIKeys{}
+		auth = NewAPIKeyAuth(keys, "", zap.NewNop())
+	)
+
+	// Make sure the contexts without an API key (admin) are not accounted for
+	record, err := auth.AllowWS(context.Background())
+	require.Nil(t, err)
+
+	record(1, 10)
+
+	assert.Zero(t, keys.requests)
+
+	// Make sure the operations of the API key are accounted for
+	ctx := context.WithValue(context.Background(), apiKeyContextKey{}, "key")
+
+	record, err = auth.AllowWS(ctx)
+	require.Nil(t, err)
+
+	record(1, 10)
+
+	assert.Equal(t, uint64(1), keys.requests)
+	assert.Equal(t, uint64(10), keys.bytes)
+
+	// Make sure the quotas are enforced
+	keys.exceeded = &apikeys.Exceeded{
+		Period:   apikeys.PeriodDaily,
+		Resource: apikeys.ResourceRequests,
+		Limit:    1,
+		Reset:    time.Now().Add(time.Hour),
+	}
+
+	_, err = auth.AllowWS(ctx)
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.QuotaExceededErrorCode, err.Code)
+}
@@ -2,6 +2,8 @@ package conns
 
 import (
 	"github.com/olahol/melody"
+
+	"github.com/gnolang/tx-indexer/serve/writer"
 )
 
 // ConnectionManager defines a connection manager interface
@@ -15,6 +17,12 @@ type ConnectionManager interface {
 
 	// GetWSConnection fetches a WS connection, if any, using the supplied ID
 	GetWSConnection(id string) WSConnection
+
+	// HandleWSRequest handles the request of the WS connection with the supplied ID,
+	// with the connection response writer. The data written to the connection
+	// while handling is written after the response.
+	// Returns a flag indicating if the connection was found
+	HandleWSRequest(id string, handleFn func(w writer.ResponseWriter)) bool
 }
 
 // WSConnection represents a single WS connection
@@ -2,6 +2,7 @@ package wsconn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -12,10 +13,19 @@ import (
 	"go.uber.org/zap"
 )
 
+// maxHeld is the number of notifications held per connection while handling
+// requests, before the connection is closed. The connection is closed instead of
+// dropping the notifications, as the subscribers can't tell they missed any
+const maxHeld = 1000
+
+// ErrTooManyHeld is the error of writing to a connection holding the maximum number
+// of notifications, closing the connection
+var ErrTooManyHeld = errors.New("too many notifications held, closing the connection")
+
 // Conns manages active WS connections
 type Conns struct {
 	logger *zap.Logger
-	conns  map[string]Conn // ws connection ID -> conn
+	conns  map[string]*Conn // ws connection ID -> conn
 
 	mux sync.RWMutex
 }
@@ -24,7 +34,7 @@ type Conns struct {
 func NewConns(logger *zap.Logger) *Conns {
 	return &Conns{
 		logger: logger,
-		conns:  make(map[string]Conn),
+		conns:  make(map[string]*Conn),
 	}
 }
 
@@ -35,15 +45,16 @@ func (pw *Conns) AddWSConnection(id string, session *melody.Session) {
 
 	ctx, cancelFn := context.WithCancel(context.Background())
 
-	pw.conns[id] = Conn{
+	logger := pw.logger.Named(
+		fmt.Sprintf("ws-%s", id),
+	)
+
+	pw.conns[id] = &Conn{
 		ctx:      ctx,
 		cancelFn: cancelFn,
-		writer: ws.New(
-			pw.logger.Named(
-				fmt.Sprintf("ws-%s", id),
-			),
-			session,
-		),
+		closeFn:  session.Close,
+		logger:   logger,
+		writer:   ws.New(logger, session),
 	}
 }
 
@@ -73,24 +84,106 @@ func (pw *Conns) GetWSConnection(id string) conns.WSConnection {
 		return nil
 	}
 
-	return &conn
+	return conn
+}
+
+// HandleWSRequest handles the request of the WS connection, if any.
+// Returns a flag indicating if the connection was found
+func (pw *Conns) HandleWSRequest(id string, handleFn func(w writer.ResponseWriter)) bool {
+	pw.mux.RLock()
+	conn, found := pw.conns[id]
+	pw.mux.RUnlock()
+
+	if !found {
+		return false
+	}
+
+	conn.handleRequest(handleFn)
+
+	return true
 }
 
 // Conn is a single WS connection
 type Conn struct {
 	ctx      context.Context
 	cancelFn context.CancelFunc
+	closeFn  func() error // closes the underlying WS session
 
+	logger *zap.Logger
 	writer writer.ResponseWriter
+
+	held     []any // notifications held while handling requests
+	handling int   // number of requests being handled
+	mux      sync.Mutex
 }
 
-// WriteData writes arbitrary data to the WS connection
+// WriteData writes arbitrary data to the WS connection.
+// The data written while a request is being handled is held until
+// the response is written, so the subscription notifications always
+// follow the subscribe responses (with the subscription IDs).
+// The connection is closed if it holds more than maxHeld notifications
 func (c *Conn) WriteData(data any) error {
 	if c.ctx.Err() != nil {
 		return c.ctx.Err()
 	}
 
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.handling > 0 {
+		if len(c.held) >= maxHeld {
+			c.overflow()
+
+			return ErrTooManyHeld
+		}
+
+		c.held = append(c.held, data)
+
+		return nil
+	}
+
 	c.writer.WriteResponse(data)
 
 	return nil
 }
+
+// handleRequest runs the request handler with the connection writer,
+// writing the held data after the response, in order
+func (c *Conn) handleRequest(handleFn func(w writer.ResponseWriter)) {
+	c.mux.Lock()
+	c.handling++
+	c.mux.Unlock()
+
+	handleFn(c.writer)
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.handling--
+
+	if c.handling > 0 {
+		return
+	}
+
+	for _, data := range c.held {
+		c.writer.WriteResponse(data)
+	}
+
+	c.held = nil
+}
+
+// overflow drops the held notifications, and closes the connection.
+// The connection is removed, along with its subscriptions, by the disconnect handler
+func (c *Conn) overflow() {
+	c.logger.Warn(
+		"closing WS connection, too many notifications held",
+		zap.Int("held", len(c.held)),
+	)
+
+	c.held = nil
+	c.cancelFn()
+
+	if err := c.closeFn(); err != nil {
+		c.logger.Debug("unable to close WS connection", zap.Error(err))
+	}
+}
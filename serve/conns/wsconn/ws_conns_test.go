@@ -0,0 +1,149 @@
+package wsconn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/writer"
+)
+
+type writeResponseDelegate func(any)
+
+type mockWriter struct {
+	writeResponseFn writeResponseDelegate
+}
+
+func (m *mockWriter) WriteResponse(response any) {
+	if m.writeResponseFn != nil {
+		m.writeResponseFn(response)
+	}
+}
+
+// newTestConns creates the connection manager with
+// a single connection, capturing the written data
+func newTestConns(t *testing.T, id string) (*Conns, *[]any) {
+	t.Helper()
+
+	var (
+		written = make([]any, 0)
+		conns   = NewConns(zap.NewNop())
+	)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	conns.conns[id] = &Conn{
+		ctx:      ctx,
+		cancelFn: cancelFn,
+		closeFn:  func() error { return nil },
+		logger:   zap.NewNop(),
+		writer: &mockWriter{
+			writeResponseFn: func(response any) {
+				written = append(written, response)
+			},
+		},
+	}
+
+	return conns, &written
+}
+
+func TestConns_HandleWSRequest(t *testing.T) {
+	t.Parallel()
+
+	id := "conn"
+
+	conns, written := newTestConns(t, id)
+
+	conn := conns.GetWSConnection(id)
+	require.NotNil(t, conn)
+
+	found := conns.HandleWSRequest(id, func(w writer.ResponseWriter) {
+		// Write a notification while the request is handled
+		require.NoError(t, conn.WriteData("notification"))
+
+		// Make sure the notification is held
+		assert.Empty(t, *written)
+
+		w.WriteResponse("response")
+	})
+	require.True(t, found)
+
+	// Make sure the response is written before the notification
+	assert.Equal(t, []any{"response", "notification"}, *written)
+
+	// Make sure the notifications are not held outside requests
+	require.NoError(t, conn.WriteData("next"))
+
+	assert.Equal(t, []any{"response", "notification", "next"}, *written)
+}
+
+func TestConns_HandleWSRequest_Missing(t *testing.T) {
+	t.Parallel()
+
+	conns, _ := newTestConns(t, "conn")
+
+	assert.False(t, conns.HandleWSRequest("missing", func(_ writer.ResponseWriter) {
+		t.Fatal("unexpected request handling")
+	}))
+}
+
+func TestConn_WriteData_Closed(t *testing.T) {
+	t.Parallel()
+
+	id := "conn"
+
+	conns, written := newTestConns(t, id)
+
+	conn := conns.GetWSConnection(id)
+	require.NotNil(t, conn)
+
+	conns.RemoveWSConnection(id)
+
+	assert.ErrorIs(t, conn.WriteData("notification"), context.Canceled)
+	assert.Empty(t, *written)
+}
+
+func TestConn_WriteData_TooManyHeld(t *testing.T) {
+	t.Parallel()
+
+	var (
+		id     = "conn"
+		closed = false
+	)
+
+	conns, written := newTestConns(t, id)
+
+	conns.conns[id].closeFn = func() error {
+		closed = true
+
+		return nil
+	}
+
+	conn := conns.GetWSConnection(id)
+	require.NotNil(t, conn)
+
+	found := conns.HandleWSRequest(id, func(w writer.ResponseWriter) {
+		// Hold the maximum number of notifications
+		for i := 0; i < maxHeld; i++ {
+			require.NoError(t, conn.WriteData(i))
+		}
+
+		assert.False(t, closed)
+
+		// Make sure the connection is closed over the limit
+		assert.ErrorIs(t, conn.WriteData("notification"), ErrTooManyHeld)
+		assert.True(t, closed)
+
+		w.WriteResponse("response")
+	})
+	require.True(t, found)
+
+	// Make sure the held notifications are dropped
+	assert.Equal(t, []any{"response"}, *written)
+
+	assert.ErrorIs(t, conn.WriteData("next"), context.Canceled)
+}
@@ -2,6 +2,7 @@ package encode
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -30,3 +31,26 @@ func PrepareValue(value any) (string, error) {
 
 	return base64.StdEncoding.EncodeToString(aminoEncoding), nil
 }
+
+// Value is the Amino binary encoded value, served base64 encoded in JSON responses
+// (like PrepareValue), and as raw bytes in the binary wire formats
+type Value []byte
+
+// EncodeValue encodes the given value into Amino binary
+func EncodeValue(value any) (Value, error) {
+	aminoEncoding, err := amino.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to amino encode value")
+	}
+
+	return aminoEncoding, nil
+}
+
+// MarshalJSON encodes the value to base64, and a nil value (a missing item of the list results) to null
+func (v Value) MarshalJSON() ([]byte, error) {
+	if v == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(base64.StdEncoding.EncodeToString(v))
+}
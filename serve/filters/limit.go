@@ -0,0 +1,93 @@
+package filters
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SubscriptionLimitError is the error of the subscription
+// over the active subscription limits
+type SubscriptionLimitError struct {
+	// Max is the exceeded limit
+	Max int
+
+	// PerConn is the flag indicating if the exceeded limit
+	// is the per connection one, or the global one
+	PerConn bool
+}
+
+func (e *SubscriptionLimitError) Error() string {
+	if e.PerConn {
+		return fmt.Sprintf("subscription limit reached, the maximum is %d per connection", e.Max)
+	}
+
+	return fmt.Sprintf("subscription limit reached, the maximum is %d active subscriptions", e.Max)
+}
+
+// SubscriptionLimiter caps the number of active subscriptions,
+// globally and per WS connection. It can be shared by multiple filter managers,
+// for the global limit to apply to all of them
+type SubscriptionLimiter struct {
+	perConn map[string]int
+
+	total      int
+	maxTotal   int
+	maxPerConn int
+
+	mux sync.Mutex
+}
+
+// NewSubscriptionLimiter creates a new subscription limiter,
+// with the given global and per connection limits. A 0 limit is unlimited
+func NewSubscriptionLimiter(maxSubscriptions, maxSubscriptionsPerConn int) *SubscriptionLimiter {
+	return &SubscriptionLimiter{
+		perConn:    make(map[string]int),
+		maxTotal:   maxSubscriptions,
+		maxPerConn: maxSubscriptionsPerConn,
+	}
+}
+
+// acquire reserves a subscription for the connection,
+// if it's within the limits
+func (l *SubscriptionLimiter) acquire(connID string) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.maxPerConn > 0 && l.perConn[connID] >= l.maxPerConn {
+		return &SubscriptionLimitError{Max: l.maxPerConn, PerConn: true}
+	}
+
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return &SubscriptionLimitError{Max: l.maxTotal}
+	}
+
+	l.total++
+	l.perConn[connID]++
+
+	return nil
+}
+
+// Reserve reserves a subscription for the connection, if it's within the limits, for the subscriptions
+// of the servers other than the JSON-RPC one (ex. GraphQL). The returned func releases the subscription
+func (l *SubscriptionLimiter) Reserve(connID string) (func(), error) {
+	if err := l.acquire(connID); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		l.release(connID)
+	}, nil
+}
+
+// release releases the subscription of the connection
+func (l *SubscriptionLimiter) release(connID string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.total--
+
+	l.perConn[connID]--
+	if l.perConn[connID] <= 0 {
+		delete(l.perConn, connID)
+	}
+}
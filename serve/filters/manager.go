@@ -8,6 +8,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 
 	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/notify"
 	"github.com/gnolang/tx-indexer/serve/conns"
 	"github.com/gnolang/tx-indexer/serve/filters/filter"
 	filterSubscription "github.com/gnolang/tx-indexer/serve/filters/subscription"
@@ -76,18 +77,35 @@ func (f *Manager) UninstallFilter(id string) bool {
 }
 
 // NewBlockSubscription creates a new block (new heads) subscription (over WS)
-func (f *Manager) NewBlockSubscription(conn conns.WSConnection) string {
-	return f.newSubscription(filterSubscription.NewBlockSubscription(conn))
+func (f *Manager) NewBlockSubscription(connID string, conn conns.WSConnection) (string, error) {
+	return f.newSubscription(connID, filterSubscription.NewBlockSubscription(conn))
 }
 
 // NewTransactionSubscription creates a new transaction (new transactions) subscription (over WS)
-func (f *Manager) NewTransactionSubscription(conn conns.WSConnection) string {
-	return f.newSubscription(filterSubscription.NewTransactionSubscription(conn))
+func (f *Manager) NewTransactionSubscription(connID string, conn conns.WSConnection) (string, error) {
+	return f.newSubscription(connID, filterSubscription.NewTransactionSubscription(conn))
 }
 
-// newSubscription adds new subscription to the subscription map
-func (f *Manager) newSubscription(subscription subscription) string {
-	return f.subscriptions.addSubscription(subscription)
+// NewEventSubscription creates a new realm event (new events) subscription (over WS),
+// for the events matching the realm and event type (empty matches all)
+func (f *Manager) NewEventSubscription(
+	connID string,
+	conn conns.WSConnection,
+	realm,
+	eventType string,
+) (string, error) {
+	return f.newSubscription(connID, filterSubscription.NewEventSubscription(conn, realm, eventType))
+}
+
+// NewAlertSubscription creates a new alert (new alerts) subscription (over WS)
+func (f *Manager) NewAlertSubscription(connID string, conn conns.WSConnection) (string, error) {
+	return f.newSubscription(connID, filterSubscription.NewAlertSubscription(conn))
+}
+
+// newSubscription adds new subscription of the WS connection to the subscription map.
+// Returns a SubscriptionLimitError if the subscription is over the limits
+func (f *Manager) newSubscription(connID string, subscription subscription) (string, error) {
+	return f.subscriptions.addSubscription(connID, subscription)
 }
 
 // UninstallSubscription removes a subscription from the subscription map.
@@ -96,9 +114,22 @@ func (f *Manager) UninstallSubscription(id string) bool {
 	return f.subscriptions.deleteSubscription(id)
 }
 
+// UninstallConnSubscriptions removes all subscriptions of the WS connection
+func (f *Manager) UninstallConnSubscriptions(connID string) {
+	f.subscriptions.deleteConnSubscriptions(connID)
+}
+
+// NumSubscriptions returns the number of active subscriptions
+func (f *Manager) NumSubscriptions() int {
+	return f.subscriptions.len()
+}
+
 // subscribeToEvents subscribes to new events
 func (f *Manager) subscribeToEvents() {
-	subscription := f.events.Subscribe([]events.Type{commonTypes.NewBlockEvent})
+	subscription := f.events.Subscribe([]events.Type{
+		commonTypes.NewBlockEvent,
+		notify.NotificationEvent,
+	})
 	defer f.events.CancelSubscription(subscription.ID)
 
 	for {
@@ -127,11 +158,19 @@ func (f *Manager) subscribeToEvents() {
 						// Apply transaction to filters
 						f.updateFiltersWithTxResult(txResult)
 
-						// Send events to all `newHeads` subscriptions
+						// Send events to all `newTransactions` subscriptions
 						f.subscriptions.sendEvent(filterSubscription.NewTransactionsEvent, txResult)
+
+						// Send events to all `newEvents` subscriptions
+						f.subscriptions.sendEvent(filterSubscription.NewEventsEvent, txResult)
 					}
 				}
 			}
+
+			if event.GetType() == notify.NotificationEvent {
+				// Send the published alerts to all `newAlerts` subscriptions
+				f.subscriptions.sendEvent(filterSubscription.NewAlertsEvent, event.GetData())
+			}
 		}
 	}
 }
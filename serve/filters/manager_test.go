@@ -11,7 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/tx-indexer/events"
-	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/mock"
 	"github.com/gnolang/tx-indexer/serve/filters/filter"
 	"github.com/gnolang/tx-indexer/types"
 )
@@ -13,3 +13,11 @@ func WithCleanupInterval(interval time.Duration) Option {
 		manager.cleanupInterval = interval
 	}
 }
+
+// WithSubscriptionLimiter creates a filter manager with the specified
+// limiter of the active subscriptions. Unlimited by default
+func WithSubscriptionLimiter(limiter *SubscriptionLimiter) Option {
+	return func(manager *Manager) {
+		manager.subscriptions.limiter = limiter
+	}
+}
@@ -12,9 +12,20 @@ type subscription interface {
 	WriteResponse(id string, data any) error
 }
 
+// subscriptionEntry is the subscription, along with
+// the ID of the WS connection it belongs to
+type subscriptionEntry struct {
+	subscription
+
+	connID string
+}
+
 // subscriptionMap keeps track of ongoing data subscriptions
 type subscriptionMap struct {
-	subscriptions map[string]subscription
+	subscriptions map[string]subscriptionEntry
+
+	// limiter caps the active subscriptions, if set
+	limiter *SubscriptionLimiter
 
 	sync.Mutex
 }
@@ -22,22 +33,47 @@ type subscriptionMap struct {
 // newSubMap creates a new subscription map
 func newSubMap() *subscriptionMap {
 	return &subscriptionMap{
-		subscriptions: make(map[string]subscription),
+		subscriptions: make(map[string]subscriptionEntry),
 	}
 }
 
-// addSubscription adds a new subscription to the subscription map, returning its ID
-func (sm *subscriptionMap) addSubscription(sub subscription) string {
+// addSubscription adds a new subscription of the WS connection to the subscription map,
+// returning its ID. Returns an error if the subscription is over the limits
+func (sm *subscriptionMap) addSubscription(connID string, sub subscription) (string, error) {
 	sm.Lock()
 	defer sm.Unlock()
 
+	if sm.limiter != nil {
+		if err := sm.limiter.acquire(connID); err != nil {
+			return "", err
+		}
+	}
+
 	// Crete new id
 	id := uuid.New().String()
 
 	// Add subscription to the map
-	sm.subscriptions[id] = sub
+	sm.subscriptions[id] = subscriptionEntry{
+		subscription: sub,
+		connID:       connID,
+	}
+
+	return id, nil
+}
+
+// remove removes the subscription from the map, releasing its limit.
+// The map lock needs to be held
+func (sm *subscriptionMap) remove(id string) {
+	entry, exists := sm.subscriptions[id]
+	if !exists {
+		return
+	}
+
+	delete(sm.subscriptions, id)
 
-	return id
+	if sm.limiter != nil {
+		sm.limiter.release(entry.connID)
+	}
 }
 
 // sendEvent alerts all active subscriptions of a event.
@@ -81,10 +117,18 @@ func (sm *subscriptionMap) sendEvent(eventType events.Type, data any) {
 
 	// Prune out the invalid subscriptions
 	for _, invalidID := range invalidSends {
-		delete(sm.subscriptions, invalidID)
+		sm.remove(invalidID)
 	}
 }
 
+// len returns the number of active subscriptions
+func (sm *subscriptionMap) len() int {
+	sm.Lock()
+	defer sm.Unlock()
+
+	return len(sm.subscriptions)
+}
+
 // deleteSubscription removes a subscription using the ID.
 // Returns a flag indicating if the subscription was indeed present and removedd
 func (sm *subscriptionMap) deleteSubscription(id string) bool {
@@ -94,10 +138,22 @@ func (sm *subscriptionMap) deleteSubscription(id string) bool {
 	// If the subscription exists, remove it
 	_, exists := sm.subscriptions[id]
 	if exists {
-		delete(sm.subscriptions, id)
+		sm.remove(id)
 
 		return true
 	}
 
 	return false
 }
+
+// deleteConnSubscriptions removes all subscriptions of the WS connection
+func (sm *subscriptionMap) deleteConnSubscriptions(connID string) {
+	sm.Lock()
+	defer sm.Unlock()
+
+	for id, entry := range sm.subscriptions {
+		if entry.connID == connID {
+			sm.remove(id)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package subscription
+
+import (
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/conns"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	NewAlertsEvent = "newAlerts"
+)
+
+// AlertSubscription is the new-alerts type subscription,
+// for the alerts published by the alert rules topic actions
+type AlertSubscription struct {
+	*baseSubscription
+}
+
+func NewAlertSubscription(conn conns.WSConnection) *AlertSubscription {
+	return &AlertSubscription{
+		baseSubscription: newBaseSubscription(conn),
+	}
+}
+
+func (a *AlertSubscription) GetType() events.Type {
+	return NewAlertsEvent
+}
+
+func (a *AlertSubscription) WriteResponse(id string, data any) error {
+	notification, ok := data.(*notify.Notification)
+	if !ok {
+		return fmt.Errorf("unable to cast notification, %s", data)
+	}
+
+	return a.conn.WriteData(spec.NewJSONSubscribeResponse(id, notification))
+}
@@ -0,0 +1,47 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestAlertSubscription_WriteResponse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		capturedWrite any
+
+		mockNotification = &notify.Notification{
+			Topic:   "alerts",
+			Source:  "large-transfers",
+			Message: "large transfer",
+			Height:  10,
+		}
+	)
+
+	mockConn := &mock.Conn{
+		WriteDataFn: func(data any) error {
+			capturedWrite = data
+
+			return nil
+		},
+	}
+
+	// Create the alert subscription
+	alertSubscription := NewAlertSubscription(mockConn)
+
+	// Write the response
+	require.NoError(t, alertSubscription.WriteResponse("", mockNotification))
+
+	// Make sure the notification was written
+	assert.Equal(t, spec.NewJSONSubscribeResponse("", mockNotification), capturedWrite)
+
+	// Make sure invalid data is rejected
+	assert.Error(t, alertSubscription.WriteResponse("", "invalid"))
+}
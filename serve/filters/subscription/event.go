@@ -0,0 +1,63 @@
+package subscription
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+	"github.com/gnolang/tx-indexer/serve/conns"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	NewEventsEvent = "newEvents"
+)
+
+// EventFilterOption is the realm event subscription filter.
+// Empty values match any realm or event type
+type EventFilterOption struct {
+	Realm string `json:"realm"`
+	Type  string `json:"type"`
+}
+
+// EventSubscription is the new-events type subscription,
+// for realm events matching the realm and event type (empty matches all)
+type EventSubscription struct {
+	*baseSubscription
+
+	realm     string
+	eventType string
+}
+
+func NewEventSubscription(conn conns.WSConnection, realm, eventType string) *EventSubscription {
+	return &EventSubscription{
+		baseSubscription: newBaseSubscription(conn),
+		realm:            realm,
+		eventType:        eventType,
+	}
+}
+
+func (e *EventSubscription) GetType() events.Type {
+	return NewEventsEvent
+}
+
+func (e *EventSubscription) WriteResponse(id string, data any) error {
+	tx, ok := data.(*types.TxResult)
+	if !ok {
+		return fmt.Errorf("unable to cast txResult, %s", data)
+	}
+
+	for _, event := range realmevents.FromTxResult(tx) {
+		if !event.Matches(e.realm, e.eventType) {
+			continue
+		}
+
+		if err := e.conn.WriteData(spec.NewJSONSubscribeResponse(id, event)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package subscription
+
+import (
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestEventSubscription_WriteResponse(t *testing.T) {
+	t.Parallel()
+
+	var (
+		capturedWrites = make([]any, 0)
+
+		realm = "gno.land/r/demo/boards"
+
+		mockTxResult = &types.TxResult{
+			Height: 10,
+			Tx:     []byte("tx"),
+			Response: abci.ResponseDeliverTx{
+				ResponseBase: abci.ResponseBase{
+					Events: []abci.Event{
+						mock.GnoEvent{Type: "PostCreated", PkgPath: realm},
+						mock.GnoEvent{Type: "BoardCreated", PkgPath: realm},
+						mock.GnoEvent{Type: "PostCreated", PkgPath: "gno.land/r/demo/other"},
+					},
+				},
+			},
+		}
+	)
+
+	realmEvents := realmevents.FromTxResult(mockTxResult)
+	require.Len(t, realmEvents, 3)
+
+	mockConn := &mock.Conn{
+		WriteDataFn: func(data any) error {
+			capturedWrites = append(capturedWrites, data)
+
+			return nil
+		},
+	}
+
+	// Create the event subscription
+	eventSubscription := NewEventSubscription(mockConn, realm, "PostCreated")
+
+	// Write the response
+	require.NoError(t, eventSubscription.WriteResponse("", mockTxResult))
+
+	// Make sure only the matching event was written
+	require.Len(t, capturedWrites, 1)
+
+	assert.Equal(t, spec.NewJSONSubscribeResponse("", realmEvents[0]), capturedWrites[0])
+}
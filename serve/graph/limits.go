@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// connIDContextKey is the request context key of the WS connection ID
+type connIDContextKey struct{}
+
+// wsHandler assigns the WS connections an ID, for the subscription limits,
+// and caps the open WS connections with the WS limiter, if set
+func (c *config) wsHandler(next http.Handler) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), connIDContextKey{}, uuid.NewString())
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	if c.wsLimiter == nil {
+		return handler
+	}
+
+	return c.wsLimiter.Middleware(c.logger)(handler)
+}
+
+// aroundOperations enforces the API key quotas and subscription limits of the WS operations,
+// and accounts for the operations along with their response bytes. The HTTP requests are skipped,
+// as they're accounted for by the HTTP middlewares
+func (c *config) aroundOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	connID, ok := ctx.Value(connIDContextKey{}).(string)
+	if !ok {
+		return next(ctx)
+	}
+
+	record := func(uint64, uint64) {}
+
+	if c.keyAuth != nil {
+		allowed, err := c.keyAuth.AllowWS(ctx)
+		if err != nil {
+			return graphql.OneShot(graphql.ErrorResponse(ctx, "%s", err.Message))
+		}
+
+		record = allowed
+	}
+
+	operation := graphql.GetOperationContext(ctx).Operation
+	if c.subscriptionLimiter != nil && operation != nil && operation.Operation == ast.Subscription {
+		release, err := c.subscriptionLimiter.Reserve(connID)
+		if err != nil {
+			return graphql.OneShot(graphql.ErrorResponse(ctx, "%s", err.Error()))
+		}
+
+		// The operation context is cancelled once the subscription
+		// is stopped, or the connection is closed
+		context.AfterFunc(ctx, release)
+	}
+
+	record(1, 0)
+
+	responses := next(ctx)
+
+	return func(ctx context.Context) *graphql.Response {
+		response := responses(ctx)
+		if response == nil {
+			return nil
+		}
+
+		if raw, err := json.Marshal(response); err == nil {
+			record(0, uint64(len(raw)))
+		}
+
+		return response
+	}
+}
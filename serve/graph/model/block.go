@@ -6,21 +6,24 @@ import (
 	"sync"
 	"time"
 
-	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
-	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
 )
 
 type Block struct {
-	b   *types.Block
-	txs []*BlockTransaction
+	b       *types.Block
+	txs     []*BlockTransaction
+	decoder *decode.Decoder
 
 	onceTxs sync.Once
 }
 
-func NewBlock(b *types.Block) *Block {
+// NewBlock creates the block model, with the transactions decoded by the chain decoder
+func NewBlock(b *types.Block, decoder *decode.Decoder) *Block {
 	return &Block{
-		b: b,
+		b:       b,
+		decoder: decoder,
 	}
 }
 
@@ -106,7 +109,7 @@ func (b *Block) getTxs() []*BlockTransaction {
 		var blockTxs []*BlockTransaction
 
 		for _, tx := range b.b.Txs {
-			blockTx := NewBlockTransaction(tx)
+			blockTx := NewBlockTransaction(tx, b.b.Height, b.decoder)
 			if blockTx != nil {
 				blockTxs = append(blockTxs, blockTx)
 			}
@@ -120,9 +123,9 @@ func (b *Block) getTxs() []*BlockTransaction {
 	return b.txs
 }
 
-func NewBlockTransaction(tx types.Tx) *BlockTransaction {
-	var stdTx std.Tx
-	if err := amino.Unmarshal(tx, &stdTx); err != nil {
+func NewBlockTransaction(tx types.Tx, height int64, decoder *decode.Decoder) *BlockTransaction {
+	stdTx, err := decoder.TxAt(tx, height)
+	if err != nil {
 		return nil
 	}
 
@@ -69,6 +69,10 @@ type BlockFilter struct {
 	FromTime *time.Time `json:"from_time,omitempty"`
 	// Maximum timestamp up to which to fetch Blocks, exclusive. Only Blocks created before this time are included.
 	ToTime *time.Time `json:"to_time,omitempty"`
+	// Order of the returned Blocks, `asc` (oldest first, the default) or `desc` (newest first).
+	Order *string `json:"order,omitempty"`
+	// Field the returned Blocks are sorted by, `height` (the default) or `time`.
+	SortBy *string `json:"sort_by,omitempty"`
 }
 
 // Defines a transaction within a block, its execution specifics and content.
@@ -322,6 +326,11 @@ type TransactionFilter struct {
 	// `events` is entered as an array and works exclusively.
 	// ex) `events[0] || events[1] || events[2]`
 	Events []*EventInput `json:"events,omitempty"`
+	// Order of the returned Transactions, `asc` (oldest first, the default) or `desc` (newest first).
+	Order *string `json:"order,omitempty"`
+	// Field the returned Transactions are sorted by, `height` (the default), `time` or `gas` (the `gas_used`).
+	// Sorting by the `gas` requires scanning the whole (height) range of the filter.
+	SortBy *string `json:"sort_by,omitempty"`
 }
 
 // Transaction's message to filter Transactions.
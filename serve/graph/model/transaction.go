@@ -7,16 +7,18 @@ import (
 	"sync"
 
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
-	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
 )
 
 type Transaction struct {
 	stdTx    *std.Tx
 	txResult *types.TxResult
+	decoder  *decode.Decoder
 	messages []*TransactionMessage
 
 	mu           sync.Mutex
@@ -24,9 +26,11 @@ type Transaction struct {
 	onceMessages sync.Once
 }
 
-func NewTransaction(txResult *types.TxResult) *Transaction {
+// NewTransaction creates the transaction model of the result, decoded by the chain decoder
+func NewTransaction(txResult *types.TxResult, decoder *decode.Decoder) *Transaction {
 	return &Transaction{
 		txResult: txResult,
+		decoder:  decoder,
 		messages: make([]*TransactionMessage, 0),
 	}
 }
@@ -94,13 +98,13 @@ func (t *Transaction) GasFee() *Coin {
 func (t *Transaction) getStdTx() *std.Tx {
 	// The function to unmarshal a `std.Tx` is executed once.
 	unmarshalTx := func() {
-		var stdTx std.Tx
-		if err := amino.Unmarshal(t.txResult.Tx, &stdTx); err != nil {
-			t.stdTx = nil
+		stdTx, err := t.decoder.TxResult(t.txResult)
+		if err != nil {
+			stdTx = &std.Tx{}
 		}
 
 		t.mu.Lock()
-		t.stdTx = &stdTx
+		t.stdTx = stdTx
 		t.mu.Unlock()
 	}
 
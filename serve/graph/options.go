@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve"
+	"github.com/gnolang/tx-indexer/serve/filters"
+)
+
+// config is the GraphQL server configuration
+type config struct {
+	logger *zap.Logger
+
+	wsLimiter           *serve.WSConnLimiter
+	subscriptionLimiter *filters.SubscriptionLimiter
+	keyAuth             *serve.APIKeyAuth
+}
+
+type Option func(c *config)
+
+// WithLogger sets the logger to be used
+// with the GraphQL server
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithWSConnLimiter sets the limiter of the open WS connections,
+// usually the one shared with the JSON-RPC server
+func WithWSConnLimiter(limiter *serve.WSConnLimiter) Option {
+	return func(c *config) {
+		c.wsLimiter = limiter
+	}
+}
+
+// WithSubscriptionLimiter sets the limiter of the active subscriptions,
+// usually the one shared with the JSON-RPC server
+func WithSubscriptionLimiter(limiter *filters.SubscriptionLimiter) Option {
+	return func(c *config) {
+		c.subscriptionLimiter = limiter
+	}
+}
+
+// WithAPIKeyAuth sets the API key authentication, enforcing the quotas
+// of the WS operations, and accounting for them (if set)
+func WithAPIKeyAuth(keyAuth *serve.APIKeyAuth) Option {
+	return func(c *config) {
+		c.keyAuth = keyAuth
+	}
+}
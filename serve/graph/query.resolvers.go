@@ -8,6 +8,8 @@ import (
 	"context"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/tx-indexer/query"
 	"github.com/gnolang/tx-indexer/serve/graph/model"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
@@ -19,22 +21,44 @@ func (r *queryResolver) Transactions(ctx context.Context, filter model.Transacti
 		if err != nil {
 			return nil, gqlerror.Wrap(err)
 		}
-		return []*model.Transaction{model.NewTransaction(tx)}, nil
+		return []*model.Transaction{model.NewTransaction(tx, r.decoder)}, nil
 	}
 
-	it, err := r.
-		store.
-		TxIterator(
-			uint64(deref(filter.FromBlockHeight)),
-			uint64(deref(filter.ToBlockHeight)),
-			uint32(deref(filter.FromIndex)),
-			uint32(deref(filter.ToIndex)),
-		)
+	o, err := listOrdering(filter.Order, filter.SortBy, query.SortGas)
+	if err != nil {
+		return nil, gqlerror.Wrap(err)
+	}
+
+	// The newest first heights are iterated in reverse
+	txIterator := r.store.TxIterator
+	if o.Streamed() && o.Reverse() {
+		txIterator = r.store.ReverseTxIterator
+	}
+
+	it, err := txIterator(
+		uint64(deref(filter.FromBlockHeight)),
+		uint64(deref(filter.ToBlockHeight)),
+		uint32(deref(filter.FromIndex)),
+		uint32(deref(filter.ToIndex)),
+	)
 	if err != nil {
 		return nil, gqlerror.Wrap(err)
 	}
 	defer it.Close()
 
+	if !o.Streamed() {
+		out, err := selectSorted(ctx, it, o, func(t *types.TxResult) (*model.Transaction, bool) {
+			transaction := model.NewTransaction(t, r.decoder)
+
+			return transaction, FilteredTransactionBy(transaction, filter)
+		}, transactionRank(o))
+		if err != nil {
+			return nil, gqlerror.Wrap(err)
+		}
+
+		return out, nil
+	}
+
 	var out []*model.Transaction
 	i := 0
 	for {
@@ -58,7 +82,7 @@ func (r *queryResolver) Transactions(ctx context.Context, filter model.Transacti
 				return out, nil
 			}
 
-			transaction := model.NewTransaction(t)
+			transaction := model.NewTransaction(t, r.decoder)
 			if !FilteredTransactionBy(transaction, filter) {
 				continue
 			}
@@ -70,12 +94,21 @@ func (r *queryResolver) Transactions(ctx context.Context, filter model.Transacti
 
 // Blocks is the resolver for the blocks field.
 func (r *queryResolver) Blocks(ctx context.Context, filter model.BlockFilter) ([]*model.Block, error) {
-	it, err := r.
-		store.
-		BlockIterator(
-			uint64(deref(filter.FromHeight)),
-			uint64(deref(filter.ToHeight)),
-		)
+	o, err := listOrdering(filter.Order, filter.SortBy)
+	if err != nil {
+		return nil, gqlerror.Wrap(err)
+	}
+
+	// The blocks are only ordered by height (or time), so the newest first ones are iterated in reverse
+	blockIterator := r.store.BlockIterator
+	if o.Reverse() {
+		blockIterator = r.store.ReverseBlockIterator
+	}
+
+	it, err := blockIterator(
+		uint64(deref(filter.FromHeight)),
+		uint64(deref(filter.ToHeight)),
+	)
 	if err != nil {
 		return nil, gqlerror.Wrap(err)
 	}
@@ -105,7 +138,7 @@ func (r *queryResolver) Blocks(ctx context.Context, filter model.BlockFilter) ([
 				return out, nil
 			}
 
-			block := model.NewBlock(b)
+			block := model.NewBlock(b, r.decoder)
 			if !FilteredBlockBy(block, filter) {
 				continue
 			}
@@ -8,7 +8,10 @@ import (
 
 	"github.com/99designs/gqlgen/graphql"
 
+	"github.com/gnolang/tx-indexer/decode"
 	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/query"
+	"github.com/gnolang/tx-indexer/serve/graph/model"
 	"github.com/gnolang/tx-indexer/storage"
 	"github.com/gnolang/tx-indexer/types"
 )
@@ -19,6 +22,9 @@ import (
 
 const maxElementsPerQuery = 10000
 
+// errTooManyElements is returned when the sorted query scans too many elements
+var errTooManyElements = fmt.Errorf("more than %d elements to sort, narrow down the height range", query.MaxScannedTxs)
+
 func deref[T any](v *T) T {
 	if v == nil {
 		var zero T
@@ -29,10 +35,12 @@ func deref[T any](v *T) T {
 	return *v
 }
 
+// handleChannel streams the elements of the new blocks, returned by the collect function,
+// until the context is done. The elements are dropped once the subscriber is gone
 func handleChannel[T any](
 	ctx context.Context,
 	m *events.Manager,
-	writeToChannel func(*types.NewBlock, chan<- T),
+	collect func(*types.NewBlock) []T,
 ) <-chan T {
 	ch := make(chan T)
 	go func() {
@@ -59,7 +67,13 @@ func handleChannel[T any](
 					return
 				}
 
-				writeToChannel(e, ch)
+				for _, element := range collect(e) {
+					select {
+					case <-ctx.Done():
+						return
+					case ch <- element:
+					}
+				}
 			}
 		}
 	}()
@@ -67,11 +81,85 @@ func handleChannel[T any](
 	return ch
 }
 
+// listOrdering returns the validated ordering of the list query, out of the supported sort fields
+func listOrdering(order, sortBy *string, fields ...string) (query.Ordering, error) {
+	o := query.Ordering{
+		Order:  deref(order),
+		SortBy: deref(sortBy),
+	}
+
+	if err := o.Validate(fields...); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// transactionRank returns the transaction rank function of the ordering
+func transactionRank(ordering query.Ordering) func(*model.Transaction) query.Rank {
+	return func(t *model.Transaction) query.Rank {
+		rank := query.Rank{
+			Height: uint64(t.BlockHeight()),
+			Index:  uint32(t.Index()),
+		}
+
+		if ordering.SortBy == query.SortGas {
+			rank.Key = int64(t.GasUsed())
+		}
+
+		return rank
+	}
+}
+
+// selectSorted selects up to maxElementsPerQuery elements in the (gas) ordering,
+// out of the whole iterated range. The convert function returns the element, if it matches the filter
+func selectSorted[T, E any](
+	ctx context.Context,
+	it storage.Iterator[T],
+	ordering query.Ordering,
+	convert func(T) (E, bool),
+	rank func(E) query.Rank,
+) ([]E, error) {
+	selector := query.NewSelector(ordering, maxElementsPerQuery, nil, rank)
+
+	for scanned := 0; it.Next(); scanned++ {
+		if scanned == query.MaxScannedTxs {
+			return nil, errTooManyElements
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		v, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		if element, ok := convert(v); ok {
+			selector.Add(element)
+		}
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	if selector.More() {
+		graphql.AddErrorf(ctx, "max elements per query reached (%d)", maxElementsPerQuery)
+	}
+
+	return selector.Elements(), nil
+}
+
 type Resolver struct {
 	store   storage.Storage
 	manager *events.Manager
+	decoder *decode.Decoder
 }
 
-func NewResolver(s storage.Storage, m *events.Manager) *Resolver {
-	return &Resolver{store: s, manager: m}
+func NewResolver(s storage.Storage, m *events.Manager, d *decode.Decoder) *Resolver {
+	return &Resolver{store: s, manager: m, decoder: d}
 }
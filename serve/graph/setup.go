@@ -1,24 +1,83 @@
 package graph
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
 
+	"github.com/gnolang/tx-indexer/decode"
 	"github.com/gnolang/tx-indexer/events"
 	"github.com/gnolang/tx-indexer/storage"
 )
 
-func Setup(s storage.Storage, manager *events.Manager, m *chi.Mux) *chi.Mux {
-	srv := handler.NewDefaultServer(NewExecutableSchema(
-		Config{Resolvers: NewResolver(s, manager)},
+const (
+	// keepAlivePingInterval is the interval of the keep-alive messages
+	// of the (legacy) graphql-ws connections
+	keepAlivePingInterval = 10 * time.Second
+
+	// pingPongInterval is the interval of the pings of the graphql-transport-ws connections,
+	// which are closed if the client doesn't respond in time
+	pingPongInterval = 30 * time.Second
+)
+
+// Setup registers the GraphQL endpoint, serving the queries over HTTP, and the subscriptions over WS
+// with both the graphql-transport-ws (graphql-ws) and the legacy graphql-ws (subscriptions-transport-ws) protocols.
+// The transactions are decoded by the chain decoder. The WS connections and subscriptions are capped by the limiters,
+// and the WS operations are accounted for by the API key authentication, the same as with the JSON-RPC WS endpoint
+func Setup(
+	s storage.Storage,
+	manager *events.Manager,
+	decoder *decode.Decoder,
+	m *chi.Mux,
+	opts ...Option,
+) *chi.Mux {
+	c := &config{
+		logger: zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	srv := handler.New(NewExecutableSchema(
+		Config{Resolvers: NewResolver(s, manager, decoder)},
 	))
 
-	srv.AddTransport(&transport.Websocket{})
+	srv.AddTransport(transport.Websocket{
+		Upgrader: websocket.Upgrader{
+			// The frontends are served from other origins,
+			// same as with the JSON-RPC WS endpoint
+			CheckOrigin: func(*http.Request) bool {
+				return true
+			},
+		},
+		KeepAlivePingInterval: keepAlivePingInterval,
+		PingPongInterval:      pingPongInterval,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.AroundOperations(c.aroundOperations)
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New(100),
+	})
 
 	m.Handle("/graphql", playground.Handler("Gno Indexer: GraphQL playground", "/graphql/query"))
-	m.Handle("/graphql/query", srv)
+	m.Handle("/graphql/query", c.wsHandler(srv))
 
 	return m
 }
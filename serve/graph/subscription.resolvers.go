@@ -13,23 +13,36 @@ import (
 
 // Transactions is the resolver for the transactions field.
 func (r *subscriptionResolver) Transactions(ctx context.Context, filter model.TransactionFilter) (<-chan *model.Transaction, error) {
-	return handleChannel(ctx, r.manager, func(nb *types.NewBlock, c chan<- *model.Transaction) {
+	return handleChannel(ctx, r.manager, func(nb *types.NewBlock) []*model.Transaction {
+		transactions := make([]*model.Transaction, 0, len(nb.Results))
+
 		for _, tx := range nb.Results {
-			transaction := model.NewTransaction(tx)
+			transaction := model.NewTransaction(tx, r.decoder)
 			if FilteredTransactionBy(transaction, filter) {
-				c <- transaction
+				transactions = append(transactions, transaction)
 			}
 		}
+
+		return transactions
 	}), nil
 }
 
 // Blocks is the resolver for the blocks field.
 func (r *subscriptionResolver) Blocks(ctx context.Context, filter model.BlockFilter) (<-chan *model.Block, error) {
-	return handleChannel(ctx, r.manager, func(nb *types.NewBlock, c chan<- *model.Block) {
-		block := model.NewBlock(nb.Block)
-		if FilteredBlockBy(block, filter) {
-			c <- block
+	return handleChannel(ctx, r.manager, func(nb *types.NewBlock) []*model.Block {
+		block := model.NewBlock(nb.Block, r.decoder)
+		if !FilteredBlockBy(block, filter) {
+			return nil
 		}
+
+		return []*model.Block{block}
+	}), nil
+}
+
+// NewBlocks is the resolver for the newBlocks field.
+func (r *subscriptionResolver) NewBlocks(ctx context.Context) (<-chan *model.Block, error) {
+	return handleChannel(ctx, r.manager, func(nb *types.NewBlock) []*model.Block {
+		return []*model.Block{model.NewBlock(nb.Block, r.decoder)}
 	}), nil
 }
 
@@ -7,15 +7,22 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/serve/encode"
 	"github.com/gnolang/tx-indexer/serve/metadata"
 	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/serve/wire"
 )
 
 func decodeResponse[T spec.BaseJSONResponse | spec.BaseJSONResponses](t *testing.T, responseBody []byte) *T {
@@ -136,6 +143,394 @@ func TestHTTP_Handle(t *testing.T) {
 	}
 }
 
+// TestHTTP_ContentNegotiation verifies that the responses
+// are encoded in the format accepted by the client
+func TestHTTP_ContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method = "value"
+		value  = encode.Value{0x0a, 0x01}
+	)
+
+	testTable := []struct {
+		name        string
+		accept      string
+		contentType string
+		format      wire.Format
+	}{
+		{"default JSON", "", jsonMimeType, wire.FormatJSON},
+		{"msgpack", "application/msgpack", "application/msgpack", wire.FormatMsgpack},
+		{"protobuf", "application/x-protobuf", "application/x-protobuf", wire.FormatProtobuf},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			webServer := setupTestWebServer(t, func(s *JSONRPC) {
+				s.RegisterHandler(method, func(_ *metadata.Metadata, _ []any) (any, *spec.BaseJSONError) {
+					return value, nil
+				})
+			})
+
+			defer webServer.stop()
+
+			request, err := json.Marshal(
+				spec.NewJSONRequest(1, method, nil),
+			)
+			require.NoError(t, err)
+
+			httpRequest, err := http.NewRequest(http.MethodPost, webServer.address(), bytes.NewBuffer(request))
+			require.NoError(t, err)
+
+			httpRequest.Header.Set("Content-Type", jsonMimeType)
+			httpRequest.Header.Set("Accept", testCase.accept)
+
+			respRaw, err := http.DefaultClient.Do(httpRequest)
+			require.NoError(t, err)
+
+			resp, err := io.ReadAll(respRaw.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.contentType, respRaw.Header.Get("Content-Type"))
+
+			expected, err := wire.Marshal(testCase.format, spec.NewJSONResponse(1, value, nil))
+			require.NoError(t, err)
+
+			if testCase.format == wire.FormatJSON {
+				// The JSON responses are newline terminated
+				expected = append(expected, '\n')
+			}
+
+			assert.Equal(t, expected, resp)
+		})
+	}
+}
+
+// TestHTTP_RequestHandler verifies that the request handlers
+// have access to the decoded params, the request context and the storage
+func TestHTTP_RequestHandler(t *testing.T) {
+	t.Parallel()
+
+	type filter struct {
+		Address string `json:"address"`
+		Limit   int    `json:"limit"`
+	}
+
+	var (
+		method = "custom"
+		db     = &mock.Storage{}
+
+		expectedFilter = filter{
+			Address: "g1address",
+			Limit:   10,
+		}
+	)
+
+	testTable := []struct {
+		name          string
+		params        []any
+		expectedError *spec.BaseJSONError
+	}{
+		{
+			"valid params",
+			[]any{"prefix", expectedFilter},
+			nil,
+		},
+		{
+			"optional param missing",
+			[]any{"prefix"},
+			nil,
+		},
+		{
+			"invalid param",
+			[]any{"prefix", "not an object"},
+			spec.GenerateInvalidParamError(2),
+		},
+		{
+			"too many params",
+			[]any{"prefix", expectedFilter, "extra"},
+			spec.GenerateInvalidParamCountError(),
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create a new JSON-RPC server
+			webServer := setupTestWebServer(t, func(s *JSONRPC) {
+				WithStorage(db)(s)
+
+				s.RegisterRequestHandler(method, func(r *Request) (any, *spec.BaseJSONError) {
+					assert.Equal(t, db, r.Storage)
+					assert.NotNil(t, r.Context())
+
+					var (
+						prefix string
+						f      filter
+					)
+
+					if err := r.DecodeParams(&prefix, &f); err != nil {
+						return nil, err
+					}
+
+					return fmt.Sprintf("%s-%s-%d", prefix, f.Address, f.Limit), nil
+				})
+			})
+
+			defer webServer.stop()
+
+			request, err := json.Marshal(
+				spec.NewJSONRequest(1, method, testCase.params),
+			)
+			require.NoError(t, err)
+
+			respRaw, err := http.Post(
+				webServer.address(),
+				jsonMimeType,
+				bytes.NewBuffer(request),
+			)
+			require.NoError(t, err)
+
+			resp, err := io.ReadAll(respRaw.Body)
+			require.NoError(t, err)
+
+			response := decodeResponse[spec.BaseJSONResponse](t, resp)
+
+			if testCase.expectedError != nil {
+				assert.Equal(t, testCase.expectedError, response.Error)
+
+				return
+			}
+
+			require.Nil(t, response.Error)
+
+			expected := "prefix--0"
+			if len(testCase.params) > 1 {
+				expected = "prefix-g1address-10"
+			}
+
+			assert.Equal(t, expected, response.Result)
+		})
+	}
+}
+
+// TestHTTP_Middleware verifies that the method middlewares
+// wrap the handlers, in order
+func TestHTTP_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		method   = "dummy"
+		tokenKey = "X-Token"
+		token    = "secret"
+	)
+
+	testTable := []struct {
+		name          string
+		token         string
+		expected      any
+		expectedError *spec.BaseJSONError
+	}{
+		{
+			"authorized request",
+			token,
+			"outer-inner-response",
+			nil,
+		},
+		{
+			"unauthorized request",
+			"invalid",
+			nil,
+			spec.NewJSONError("unauthorized", spec.InvalidRequestErrorCode),
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// wrapMiddleware prefixes the handler response
+			wrapMiddleware := func(prefix string) Middleware {
+				return func(_ string, next Handler) Handler {
+					return func(m *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+						response, err := next(m, params)
+						if err != nil {
+							return nil, err
+						}
+
+						return fmt.Sprintf("%s-%s", prefix, response), nil
+					}
+				}
+			}
+
+			authMiddleware := func(_ string, next Handler) Handler {
+				return func(m *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+					if m.Header.Get(tokenKey) != token {
+						return nil, spec.NewJSONError("unauthorized", spec.InvalidRequestErrorCode)
+					}
+
+					return next(m, params)
+				}
+			}
+
+			// Create a new JSON-RPC server
+			webServer := setupTestWebServer(t, func(s *JSONRPC) {
+				s.Use(
+					authMiddleware,
+					wrapMiddleware("outer"),
+					wrapMiddleware("inner"),
+				)
+
+				s.RegisterHandler(method, func(_ *metadata.Metadata, _ []any) (any, *spec.BaseJSONError) {
+					return "response", nil
+				})
+			})
+
+			defer webServer.stop()
+
+			request, err := json.Marshal(
+				spec.NewJSONRequest(1, method, nil),
+			)
+			require.NoError(t, err)
+
+			httpRequest, err := http.NewRequest(http.MethodPost, webServer.address(), bytes.NewBuffer(request))
+			require.NoError(t, err)
+
+			httpRequest.Header.Set("Content-Type", jsonMimeType)
+			httpRequest.Header.Set(tokenKey, testCase.token)
+
+			respRaw, err := http.DefaultClient.Do(httpRequest)
+			require.NoError(t, err)
+
+			resp, err := io.ReadAll(respRaw.Body)
+			require.NoError(t, err)
+
+			response := decodeResponse[spec.BaseJSONResponse](t, resp)
+
+			assert.Equal(t, testCase.expectedError, response.Error)
+			assert.Equal(t, testCase.expected, response.Result)
+		})
+	}
+}
+
+// TestHTTP_RecoveryMiddleware verifies that handler
+// panics are returned as server errors
+func TestHTTP_RecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+
+	method := "panic"
+
+	webServer := setupTestWebServer(t, func(s *JSONRPC) {
+		s.RegisterHandler(method, func(_ *metadata.Metadata, _ []any) (any, *spec.BaseJSONError) {
+			panic("something went wrong")
+		})
+	})
+
+	defer webServer.stop()
+
+	request, err := json.Marshal(
+		spec.NewJSONRequest(1, method, nil),
+	)
+	require.NoError(t, err)
+
+	respRaw, err := http.Post(
+		webServer.address(),
+		jsonMimeType,
+		bytes.NewBuffer(request),
+	)
+	require.NoError(t, err)
+
+	resp, err := io.ReadAll(respRaw.Body)
+	require.NoError(t, err)
+
+	response := decodeResponse[spec.BaseJSONResponse](t, resp)
+
+	require.NotNil(t, response.Error)
+	assert.Equal(t, spec.ServerErrorCode, response.Error.Code)
+
+	// Make sure the panic value is not leaked
+	assert.NotContains(t, response.Error.Message, "something went wrong")
+}
+
+func TestWSRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := WSRateLimitMiddleware(2, false)("getBlock", func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+		return "block", nil
+	})
+
+	var (
+		ws    = metadata.NewMetadata("1.1.1.1:1234", metadata.WithWebSocketID("ws"))
+		other = metadata.NewMetadata("2.2.2.2:1234", metadata.WithWebSocketID("other"))
+	)
+
+	// Make sure the WS requests over the limit are rejected, per IP
+	for i := 0; i < 2; i++ {
+		_, err := handler(ws, nil)
+		require.Nil(t, err)
+	}
+
+	_, err := handler(ws, nil)
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.RateLimitedErrorCode, err.Code)
+	assert.Positive(t, err.Data.RetryAfter)
+
+	_, err = handler(other, nil)
+	assert.Nil(t, err)
+
+	// Make sure the HTTP requests are skipped
+	_, err = handler(metadata.NewMetadata("1.1.1.1:1234"), nil)
+	assert.Nil(t, err)
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		registry = prometheus.NewRegistry()
+		metrics  = NewMetrics(registry)
+
+		handler = metrics.Middleware("chain")("getBlock", func(*metadata.Metadata, []any) (any, *spec.BaseJSONError) {
+			return nil, spec.GenerateNotFoundError("block", "1")
+		})
+	)
+
+	_, err := handler(metadata.NewMetadata("1.1.1.1:1234"), nil)
+	require.NotNil(t, err)
+
+	// Make sure the request and its error are recorded
+	families, gatherErr := registry.Gather()
+	require.NoError(t, gatherErr)
+
+	recorded := make(map[string]float64)
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch {
+			case metric.GetHistogram() != nil:
+				recorded[family.GetName()] = float64(metric.GetHistogram().GetSampleCount())
+			case metric.GetCounter() != nil:
+				recorded[family.GetName()] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"tx_indexer_serve_request_duration_seconds": 1,
+		"tx_indexer_serve_request_errors_total":     1,
+	}, recorded)
+}
+
 type testWebServer struct {
 	mux      *chi.Mux
 	listener net.Listener
@@ -181,3 +576,39 @@ func (ms *testWebServer) stop() {
 func (ms *testWebServer) address() string {
 	return fmt.Sprintf("http://%s", ms.listener.Addr().String())
 }
+
+func TestWS_CloseWSConnections(t *testing.T) {
+	t.Parallel()
+
+	var j *JSONRPC
+
+	s := setupTestWebServer(t, func(s *JSONRPC) {
+		j = s
+	})
+	defer s.stop()
+
+	url := strings.Replace(s.address(), "http://", "ws://", 1) + "/ws"
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	require.Eventually(t, func() bool {
+		return j.ws.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	j.CloseWSConnections()
+
+	// Make sure the client got the going away close frame
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway))
+
+	// Make sure new connections are refused
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+}
@@ -0,0 +1,93 @@
+package account
+
+import (
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxMovementsPerQuery is the maximum number of
+// coin movements returned in a single query
+const maxMovementsPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetAccountHistoryHandler returns the native coin movements of the account
+func (h *Handler) GetAccountHistoryHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	address, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if _, err := crypto.AddressFromBech32(address); err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	// Run the handler
+	history, err := h.storage.GetAccountHistory(address, maxMovementsPerQuery)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return history, nil
+}
+
+// GetAccountBalanceAtHandler returns the native coin balance of the account,
+// after the given height
+func (h *Handler) GetAccountBalanceAtHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	address, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if _, err := crypto.AddressFromBech32(address); err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	requestedHeight, ok := params[1].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	height, err := strconv.ParseUint(requestedHeight, 10, 64)
+	if err != nil {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	// Run the handler
+	balance, err := h.storage.GetAccountBalanceAt(address, height)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,189 @@
+package account
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/balances"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetAccountHistory_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid address type",
+			[]any{10},
+		},
+		{
+			"invalid address",
+			[]any{"not an address"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetAccountHistoryHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetAccountHistory_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getAccountHistoryFn: func(_ string, _ int) ([]*balances.Movement, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetAccountHistoryHandler(nil, []any{crypto.Address{1}.String()})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("account history", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			address = crypto.Address{1}.String()
+
+			history = []*balances.Movement{
+				{
+					Address: address,
+					Type:    balances.MovementFee,
+					Amount:  "10ugnot",
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getAccountHistoryFn: func(a string, limit int) ([]*balances.Movement, error) {
+					require.Equal(t, address, a)
+					require.Equal(t, maxMovementsPerQuery, limit)
+
+					return history, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetAccountHistoryHandler(nil, []any{address})
+		require.Nil(t, err)
+
+		assert.Equal(t, history, response)
+	})
+}
+
+func TestGetAccountBalanceAt_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	address := crypto.Address{1}.String()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{address},
+		},
+		{
+			"invalid address",
+			[]any{"not an address", "10"},
+		},
+		{
+			"invalid height type",
+			[]any{address, 10},
+		},
+		{
+			"invalid height",
+			[]any{address, "-10"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetAccountBalanceAtHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetAccountBalanceAt_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		address = crypto.Address{1}.String()
+		height  = uint64(10)
+
+		balance = &balances.Balance{
+			Address: address,
+			Balance: "100ugnot",
+			Height:  height,
+		}
+
+		mockStorage = &mockStorage{
+			getAccountBalanceAtFn: func(a string, h uint64) (*balances.Balance, error) {
+				require.Equal(t, address, a)
+				require.Equal(t, height, h)
+
+				return balance, nil
+			},
+		}
+	)
+
+	h := NewHandler(mockStorage)
+
+	response, err := h.GetAccountBalanceAtHandler(nil, []any{address, "10"})
+	require.Nil(t, err)
+
+	assert.Equal(t, balance, response)
+}
@@ -0,0 +1,31 @@
+package account
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/balances"
+)
+
+type (
+	getAccountHistoryDelegate   func(string, int) ([]*balances.Movement, error)
+	getAccountBalanceAtDelegate func(string, uint64) (*balances.Balance, error)
+)
+
+type mockStorage struct {
+	getAccountHistoryFn   getAccountHistoryDelegate
+	getAccountBalanceAtFn getAccountBalanceAtDelegate
+}
+
+func (m *mockStorage) GetAccountHistory(address string, limit int) ([]*balances.Movement, error) {
+	if m.getAccountHistoryFn != nil {
+		return m.getAccountHistoryFn(address, limit)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetAccountBalanceAt(address string, height uint64) (*balances.Balance, error) {
+	if m.getAccountBalanceAtFn != nil {
+		return m.getAccountBalanceAtFn(address, height)
+	}
+
+	return nil, nil
+}
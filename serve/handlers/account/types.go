@@ -0,0 +1,13 @@
+package account
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/balances"
+)
+
+type Storage interface {
+	// GetAccountHistory returns the native coin movements of the account
+	GetAccountHistory(address string, limit int) ([]*balances.Movement, error)
+
+	// GetAccountBalanceAt returns the account balance after the given height
+	GetAccountBalanceAt(address string, height uint64) (*balances.Balance, error)
+}
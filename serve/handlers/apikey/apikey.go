@@ -0,0 +1,181 @@
+package apikey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/gnolang/tx-indexer/apikeys"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+type Handler struct {
+	manager Manager
+}
+
+func NewHandler(manager Manager) *Handler {
+	return &Handler{
+		manager: manager,
+	}
+}
+
+// CreateAPIKeyHandler creates a new API key with the (optional) quota,
+// returning the generated key
+func (h *Handler) CreateAPIKeyHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	name, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var quota apikeys.Quota
+
+	if len(params) > 1 {
+		if quota, ok = parseQuota(params[1]); !ok {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	key, err := h.manager.Create(name, quota)
+	if err != nil {
+		return nil, toJSONError(name, err)
+	}
+
+	return &CreatedKey{
+		Name: name,
+		Key:  key,
+	}, nil
+}
+
+// SetAPIKeyQuotaHandler replaces the quota of the API key
+func (h *Handler) SetAPIKeyQuotaHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	name, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	quota, ok := parseQuota(params[1])
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err := h.manager.SetQuota(name, quota); err != nil {
+		return nil, toJSONError(name, err)
+	}
+
+	return h.usage(name)
+}
+
+// DeleteAPIKeyHandler removes the API key
+func (h *Handler) DeleteAPIKeyHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	name, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if err := h.manager.Delete(name); err != nil {
+		return nil, toJSONError(name, err)
+	}
+
+	return true, nil
+}
+
+// GetAPIKeyUsageHandler returns the current usage of the API key,
+// or of all the API keys if no key name is given
+func (h *Handler) GetAPIKeyUsageHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) > 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	if len(params) == 0 {
+		return h.manager.List(), nil
+	}
+
+	// Extract the params
+	name, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	return h.usage(name)
+}
+
+// usage returns the current usage of the API key
+func (h *Handler) usage(name string) (any, *spec.BaseJSONError) {
+	usage, err := h.manager.Usage(name)
+	if err != nil {
+		return nil, toJSONError(name, err)
+	}
+
+	return usage, nil
+}
+
+// parseQuota parses the quota object param, rejecting the unknown fields
+func parseQuota(param any) (apikeys.Quota, bool) {
+	var quota apikeys.Quota
+
+	if _, ok := param.(map[string]any); !ok {
+		return quota, false
+	}
+
+	raw, err := json.Marshal(param)
+	if err != nil {
+		return quota, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&quota); err != nil {
+		return quota, false
+	}
+
+	return quota, true
+}
+
+// toJSONError converts the API key manager error into the JSON-RPC error
+func toJSONError(name string, err error) *spec.BaseJSONError {
+	switch {
+	case errors.Is(err, apikeys.ErrKeyNotFound):
+		return spec.GenerateNotFoundError("API key", name)
+	case errors.Is(err, apikeys.ErrKeyExists), errors.Is(err, apikeys.ErrInvalidName):
+		return spec.NewJSONErrorWithData(
+			err.Error(),
+			spec.InvalidParamsErrorCode,
+			&spec.ErrorData{Param: 1},
+		)
+	default:
+		return spec.GenerateResponseError(err)
+	}
+}
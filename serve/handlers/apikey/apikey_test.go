@@ -0,0 +1,186 @@
+package apikey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/apikeys"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestCreateAPIKey_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid name",
+			[]any{10},
+		},
+		{
+			"invalid quota",
+			[]any{"key", "quota"},
+		},
+		{
+			"unknown quota field",
+			[]any{"key", map[string]any{"dailyRequest": 10}},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockManager{})
+
+			response, err := h.CreateAPIKeyHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestCreateAPIKey_Valid(t *testing.T) {
+	t.Parallel()
+
+	var (
+		name   = "tier-1"
+		secret = "txi_secret"
+
+		mm = &mockManager{
+			createFn: func(n string, quota apikeys.Quota) (string, error) {
+				require.Equal(t, name, n)
+				require.Equal(t, apikeys.Quota{DailyRequests: 100, MonthlyBytes: 1000}, quota)
+
+				return secret, nil
+			},
+		}
+	)
+
+	h := NewHandler(mm)
+
+	response, err := h.CreateAPIKeyHandler(nil, []any{
+		name,
+		map[string]any{"dailyRequests": float64(100), "monthlyBytes": float64(1000)},
+	})
+	require.Nil(t, err)
+
+	assert.Equal(t, &CreatedKey{Name: name, Key: secret}, response)
+}
+
+func TestCreateAPIKey_Exists(t *testing.T) {
+	t.Parallel()
+
+	mm := &mockManager{
+		createFn: func(string, apikeys.Quota) (string, error) {
+			return "", apikeys.ErrKeyExists
+		},
+	}
+
+	h := NewHandler(mm)
+
+	response, err := h.CreateAPIKeyHandler(nil, []any{"key"})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+	assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+}
+
+func TestSetAPIKeyQuota_NotFound(t *testing.T) {
+	t.Parallel()
+
+	mm := &mockManager{
+		setQuotaFn: func(string, apikeys.Quota) error {
+			return apikeys.ErrKeyNotFound
+		},
+	}
+
+	h := NewHandler(mm)
+
+	response, err := h.SetAPIKeyQuotaHandler(nil, []any{"key", map[string]any{}})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+	assert.Equal(t, spec.NotFoundErrorCode, err.Code)
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	t.Parallel()
+
+	deleted := ""
+
+	mm := &mockManager{
+		deleteFn: func(name string) error {
+			deleted = name
+
+			return nil
+		},
+	}
+
+	h := NewHandler(mm)
+
+	response, err := h.DeleteAPIKeyHandler(nil, []any{"key"})
+	require.Nil(t, err)
+
+	assert.Equal(t, true, response)
+	assert.Equal(t, "key", deleted)
+}
+
+func TestGetAPIKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		usages = []*apikeys.Usage{
+			{Name: "key-1", DailyRequests: 10},
+			{Name: "key-2", DailyRequests: 20},
+		}
+
+		mm = &mockManager{
+			listFn: func() []*apikeys.Usage {
+				return usages
+			},
+			usageFn: func(name string) (*apikeys.Usage, error) {
+				for _, usage := range usages {
+					if usage.Name == name {
+						return usage, nil
+					}
+				}
+
+				return nil, apikeys.ErrKeyNotFound
+			},
+		}
+	)
+
+	h := NewHandler(mm)
+
+	// All the keys
+	response, err := h.GetAPIKeyUsageHandler(nil, []any{})
+	require.Nil(t, err)
+
+	assert.Equal(t, usages, response)
+
+	// Single key
+	response, err = h.GetAPIKeyUsageHandler(nil, []any{"key-2"})
+	require.Nil(t, err)
+
+	assert.Equal(t, usages[1], response)
+
+	// Unknown key
+	response, err = h.GetAPIKeyUsageHandler(nil, []any{"key-3"})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+	assert.Equal(t, spec.NotFoundErrorCode, err.Code)
+}
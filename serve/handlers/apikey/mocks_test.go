@@ -0,0 +1,59 @@
+package apikey
+
+import "github.com/gnolang/tx-indexer/apikeys"
+
+type (
+	createDelegate   func(string, apikeys.Quota) (string, error)
+	setQuotaDelegate func(string, apikeys.Quota) error
+	deleteDelegate   func(string) error
+	usageDelegate    func(string) (*apikeys.Usage, error)
+	listDelegate     func() []*apikeys.Usage
+)
+
+type mockManager struct {
+	createFn   createDelegate
+	setQuotaFn setQuotaDelegate
+	deleteFn   deleteDelegate
+	usageFn    usageDelegate
+	listFn     listDelegate
+}
+
+func (m *mockManager) Create(name string, quota apikeys.Quota) (string, error) {
+	if m.createFn != nil {
+		return m.createFn(name, quota)
+	}
+
+	return "", nil
+}
+
+func (m *mockManager) SetQuota(name string, quota apikeys.Quota) error {
+	if m.setQuotaFn != nil {
+		return m.setQuotaFn(name, quota)
+	}
+
+	return nil
+}
+
+func (m *mockManager) Delete(name string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(name)
+	}
+
+	return nil
+}
+
+func (m *mockManager) Usage(name string) (*apikeys.Usage, error) {
+	if m.usageFn != nil {
+		return m.usageFn(name)
+	}
+
+	return nil, nil
+}
+
+func (m *mockManager) List() []*apikeys.Usage {
+	if m.listFn != nil {
+		return m.listFn()
+	}
+
+	return nil
+}
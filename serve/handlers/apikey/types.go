@@ -0,0 +1,29 @@
+package apikey
+
+import "github.com/gnolang/tx-indexer/apikeys"
+
+// Manager is the API key manager abstraction
+type Manager interface {
+	// Create creates a new API key with the quota, returning the generated key
+	Create(name string, quota apikeys.Quota) (string, error)
+
+	// SetQuota replaces the quota of the API key
+	SetQuota(name string, quota apikeys.Quota) error
+
+	// Delete removes the API key
+	Delete(name string) error
+
+	// Usage returns the current usage of the API key
+	Usage(name string) (*apikeys.Usage, error)
+
+	// List returns the current usage of all the API keys
+	List() []*apikeys.Usage
+}
+
+// CreatedKey is the response of the created API key
+type CreatedKey struct {
+	Name string `json:"name"`
+
+	// Key is the generated API key, returned only once
+	Key string `json:"key"`
+}
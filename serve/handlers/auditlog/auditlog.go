@@ -0,0 +1,73 @@
+package auditlog
+
+import (
+	"errors"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxEntriesPerQuery is the maximum number of
+// audit log entries returned in a single query
+const maxEntriesPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetAuditLogHandler returns a page of the audit log entries, newest first,
+// optionally limited to the given admin method
+func (h *Handler) GetAuditLogHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	var method string
+
+	if len(params) > 0 {
+		var ok bool
+
+		method, ok = params[0].(string)
+		if !ok {
+			return nil, spec.GenerateInvalidParamError(1)
+		}
+	}
+
+	pagination := Pagination{
+		Limit: maxEntriesPerQuery,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxEntriesPerQuery {
+		pagination.Limit = maxEntriesPerQuery
+	}
+
+	// Run the handler
+	page, err := h.storage.Entries(method, pagination.Cursor, pagination.Limit)
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,123 @@
+package auditlog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/audit"
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetAuditLog_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{"", map[string]any{}, 10},
+		},
+		{
+			"invalid method",
+			[]any{10},
+		},
+		{
+			"invalid pagination",
+			[]any{"", "not an object"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetAuditLogHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetAuditLog_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			entriesFn: func(string, string, int) (*audit.Page, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetAuditLogHandler(nil, []any{"", map[string]any{"cursor": "invalid"}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			entriesFn: func(string, string, int) (*audit.Page, error) {
+				return nil, errors.New("storage error")
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetAuditLogHandler(nil, []any{})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+	})
+
+	t.Run("valid page", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			method = "admin_deleteApiKey"
+			page   = &audit.Page{
+				Entries: []*audit.Entry{
+					{ID: 2, Method: method},
+				},
+				Cursor: "2",
+			}
+
+			mockStorage = &mockStorage{
+				entriesFn: func(m, cursor string, limit int) (*audit.Page, error) {
+					require.Equal(t, method, m)
+					require.Equal(t, "10", cursor)
+					require.Equal(t, maxEntriesPerQuery, limit)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetAuditLogHandler(nil, []any{method, map[string]any{"cursor": "10", "limit": 5000}})
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+}
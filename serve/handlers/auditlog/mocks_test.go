@@ -0,0 +1,17 @@
+package auditlog
+
+import "github.com/gnolang/tx-indexer/audit"
+
+type entriesDelegate func(string, string, int) (*audit.Page, error)
+
+type mockStorage struct {
+	entriesFn entriesDelegate
+}
+
+func (m *mockStorage) Entries(method, cursor string, limit int) (*audit.Page, error) {
+	if m.entriesFn != nil {
+		return m.entriesFn(method, cursor, limit)
+	}
+
+	return nil, nil
+}
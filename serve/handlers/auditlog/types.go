@@ -0,0 +1,18 @@
+package auditlog
+
+import "github.com/gnolang/tx-indexer/audit"
+
+type Storage interface {
+	// Entries returns a page of the audit log entries, newest first,
+	// optionally of the given method
+	Entries(method, cursor string, limit int) (*audit.Page, error)
+}
+
+// Pagination is the audit log query pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of entries in the page
+	Limit int `json:"limit"`
+}
@@ -2,16 +2,23 @@ package block
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 
+	"github.com/gnolang/tx-indexer/query"
 	"github.com/gnolang/tx-indexer/serve/encode"
 	"github.com/gnolang/tx-indexer/serve/metadata"
 	"github.com/gnolang/tx-indexer/serve/spec"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
+// maxHeadersPerQuery is the maximum number of
+// block headers returned in a single query
+const maxHeadersPerQuery = 100
+
 type Handler struct {
 	storage Storage
 }
@@ -49,10 +56,10 @@ func (h *Handler) GetBlockHandler(
 	}
 
 	if response == nil {
-		return nil, nil
+		return nil, h.checkSynced(blockNum)
 	}
 
-	encodedResponse, err := encode.PrepareValue(response)
+	encodedResponse, err := encode.EncodeValue(response)
 	if err != nil {
 		return nil, spec.GenerateResponseError(err)
 	}
@@ -60,6 +67,118 @@ func (h *Handler) GetBlockHandler(
 	return encodedResponse, nil
 }
 
+// GetBlockHeadersHandler returns the headers of the blocks in the (inclusive) height range,
+// without their transactions. Oldest first by default, up to maxHeadersPerQuery headers
+func (h *Handler) GetBlockHeadersHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 3 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	fromBlockNum, err := toUint64(params[0])
+	if err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var toBlockNum uint64
+
+	if len(params) > 1 {
+		toBlockNum, err = toUint64(params[1])
+		if err != nil || (toBlockNum != 0 && toBlockNum < fromBlockNum) {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	var ordering query.Ordering
+
+	if len(params) > 2 {
+		if err := spec.ParseObjectParameter(params[2], &ordering); err != nil {
+			return nil, spec.GenerateInvalidParamError(3)
+		}
+	}
+
+	if err := ordering.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(3)
+	}
+
+	// Run the handler
+	headers, err := h.getBlockHeaders(fromBlockNum, toBlockNum, ordering)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return headers, nil
+}
+
+// getBlockHeaders fetches the headers of the blocks in the (inclusive) range, from storage.
+// The newest first ordering returns the headers of the latest heights of the range
+func (h *Handler) getBlockHeaders(
+	fromBlockNum,
+	toBlockNum uint64,
+	ordering query.Ordering,
+) ([]*BlockHeader, error) {
+	headers := make([]*BlockHeader, 0)
+
+	if ordering.Order == query.OrderDesc {
+		if toBlockNum == 0 {
+			latest, err := h.storage.GetLatestHeight()
+			if errors.Is(err, storageErrors.ErrNotFound) {
+				// Nothing is indexed yet
+				return headers, nil
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch latest height, %w", err)
+			}
+
+			toBlockNum = latest
+		}
+
+		if toBlockNum < fromBlockNum {
+			return headers, nil
+		}
+
+		// Only the latest heights of the range are fetched
+		fromBlockNum = max(fromBlockNum, toBlockNum-min(toBlockNum, maxHeadersPerQuery-1))
+	}
+
+	it, err := h.storage.BlockIterator(fromBlockNum, toBlockNum)
+	if err != nil {
+		return nil, fmt.Errorf("unable to iterate blocks, %w", err)
+	}
+
+	defer it.Close()
+
+	for len(headers) < maxHeadersPerQuery && it.Next() {
+		block, err := it.Value()
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, &BlockHeader{
+			Time:     block.Time,
+			Hash:     block.Hash(),
+			Proposer: block.ProposerAddress.String(),
+			Height:   block.Height,
+			NumTxs:   block.NumTxs,
+		})
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	if ordering.Order == query.OrderDesc {
+		slices.Reverse(headers)
+	}
+
+	return headers, nil
+}
+
 // getBlock fetches the block from storage, if any
 func (h *Handler) getBlock(blockNum uint64) (*types.Block, error) {
 	block, err := h.storage.GetBlock(blockNum)
@@ -75,3 +194,24 @@ func (h *Handler) getBlock(blockNum uint64) (*types.Block, error) {
 
 	return block, nil
 }
+
+// checkSynced returns the not synced error if the
+// missing block height is above the latest indexed height
+func (h *Handler) checkSynced(blockNum uint64) *spec.BaseJSONError {
+	latest, err := h.storage.GetLatestHeight()
+
+	switch {
+	case errors.Is(err, storageErrors.ErrNotFound):
+		return spec.GenerateNotSyncedError(blockNum, 0)
+	case err != nil:
+		return spec.GenerateResponseError(err)
+	case blockNum > latest:
+		return spec.GenerateNotSyncedError(blockNum, latest)
+	default:
+		return nil
+	}
+}
+
+func toUint64(data any) (uint64, error) {
+	return strconv.ParseUint(fmt.Sprintf("%v", data), 10, 64)
+}
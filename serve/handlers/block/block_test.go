@@ -1,7 +1,6 @@
 package block
 
 import (
-	"encoding/base64"
 	"errors"
 	"strconv"
 	"testing"
@@ -11,7 +10,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/gnolang/tx-indexer/serve/encode"
 	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/storage"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
@@ -60,6 +61,9 @@ func TestGetBlock_Handler(t *testing.T) {
 			getBlockFn: func(_ uint64) (*types.Block, error) {
 				return nil, storageErrors.ErrNotFound
 			},
+			getLatestHeightFn: func() (uint64, error) {
+				return 10, nil
+			},
 		}
 
 		h := NewHandler(mockStorage)
@@ -71,6 +75,26 @@ func TestGetBlock_Handler(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("block not synced", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getBlockFn: func(_ uint64) (*types.Block, error) {
+				return nil, storageErrors.ErrNotFound
+			},
+			getLatestHeightFn: func() (uint64, error) {
+				return 10, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetBlockHandler(nil, []any{"11"})
+		assert.Nil(t, response)
+
+		assert.Equal(t, spec.GenerateNotSyncedError(11, 10), err)
+	})
+
 	t.Run("random fetch error", func(t *testing.T) {
 		t.Parallel()
 
@@ -124,19 +148,168 @@ func TestGetBlock_Handler(t *testing.T) {
 
 		require.NotNil(t, responseRaw)
 
-		// Make sure the response is valid (base64 + amino)
-		response, ok := responseRaw.(string)
+		// Make sure the response is valid (amino)
+		response, ok := responseRaw.(encode.Value)
 		require.True(t, ok)
 
-		// Decode from base64
-		encodedBlock, decodeErr := base64.StdEncoding.DecodeString(response)
-		require.Nil(t, decodeErr)
-
 		// Decode from amino binary
 		var decodedBlock types.Block
 
-		require.NoError(t, amino.Unmarshal(encodedBlock, &decodedBlock))
+		require.NoError(t, amino.Unmarshal(response, &decodedBlock))
 
 		assert.Equal(t, block, &decodedBlock)
 	})
 }
+
+func TestGetBlockHeaders_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid from height",
+			[]any{"one"},
+		},
+		{
+			"invalid height range",
+			[]any{10, 5},
+		},
+		{
+			"invalid ordering",
+			[]any{1, 5, map[string]any{"sortBy": "gas"}},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetBlockHeadersHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetBlockHeaders_Handler(t *testing.T) {
+	t.Parallel()
+
+	// generateBlocks generates the blocks in the (inclusive) height range
+	generateBlocks := func(from, to uint64) []*types.Block {
+		blocks := make([]*types.Block, 0, to-from+1)
+
+		for height := from; height <= to; height++ {
+			blocks = append(blocks, &types.Block{
+				Header: types.Header{
+					Height: int64(height),
+					NumTxs: int64(height % 3),
+				},
+			})
+		}
+
+		return blocks
+	}
+
+	// heights returns the heights of the headers
+	heights := func(headers []*BlockHeader) []int64 {
+		result := make([]int64, 0, len(headers))
+
+		for _, header := range headers {
+			result = append(result, header.Height)
+		}
+
+		return result
+	}
+
+	t.Run("oldest first", func(t *testing.T) {
+		t.Parallel()
+
+		var from, to uint64
+
+		mockStorage := &mockStorage{
+			blockIteratorFn: func(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error) {
+				from, to = fromBlockNum, toBlockNum
+
+				return &mockIterator{blocks: generateBlocks(fromBlockNum, toBlockNum)}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetBlockHeadersHandler(nil, []any{5, 8})
+		require.Nil(t, err)
+
+		headers, ok := response.([]*BlockHeader)
+		require.True(t, ok)
+
+		assert.Equal(t, uint64(5), from)
+		assert.Equal(t, uint64(8), to)
+
+		assert.Equal(t, []int64{5, 6, 7, 8}, heights(headers))
+		assert.Equal(t, int64(2), headers[0].NumTxs)
+	})
+
+	t.Run("newest first", func(t *testing.T) {
+		t.Parallel()
+
+		var from, to uint64
+
+		mockStorage := &mockStorage{
+			getLatestHeightFn: func() (uint64, error) {
+				return 250, nil
+			},
+			blockIteratorFn: func(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error) {
+				from, to = fromBlockNum, toBlockNum
+
+				return &mockIterator{blocks: generateBlocks(fromBlockNum, toBlockNum)}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetBlockHeadersHandler(nil, []any{1, 0, map[string]any{"order": "desc"}})
+		require.Nil(t, err)
+
+		headers, ok := response.([]*BlockHeader)
+		require.True(t, ok)
+
+		// Only the latest heights are fetched
+		assert.Equal(t, uint64(151), from)
+		assert.Equal(t, uint64(250), to)
+
+		require.Len(t, headers, maxHeadersPerQuery)
+
+		assert.Equal(t, int64(250), headers[0].Height)
+		assert.Equal(t, int64(151), headers[len(headers)-1].Height)
+	})
+
+	t.Run("nothing indexed", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getLatestHeightFn: func() (uint64, error) {
+				return 0, storageErrors.ErrNotFound
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetBlockHeadersHandler(nil, []any{1, 0, map[string]any{"order": "desc"}})
+		require.Nil(t, err)
+
+		assert.Equal(t, []*BlockHeader{}, response)
+	})
+}
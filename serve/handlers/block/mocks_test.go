@@ -1,11 +1,21 @@
 package block
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 
-type getBlockDelegate func(uint64) (*types.Block, error)
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+type (
+	getBlockDelegate        func(uint64) (*types.Block, error)
+	getLatestHeightDelegate func() (uint64, error)
+	blockIteratorDelegate   func(uint64, uint64) (storage.Iterator[*types.Block], error)
+)
 
 type mockStorage struct {
-	getBlockFn getBlockDelegate
+	getBlockFn        getBlockDelegate
+	getLatestHeightFn getLatestHeightDelegate
+	blockIteratorFn   blockIteratorDelegate
 }
 
 func (m *mockStorage) GetBlock(num uint64) (*types.Block, error) {
@@ -15,3 +25,47 @@ func (m *mockStorage) GetBlock(num uint64) (*types.Block, error) {
 
 	return nil, nil
 }
+
+func (m *mockStorage) GetLatestHeight() (uint64, error) {
+	if m.getLatestHeightFn != nil {
+		return m.getLatestHeightFn()
+	}
+
+	return 0, nil
+}
+
+func (m *mockStorage) BlockIterator(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error) {
+	if m.blockIteratorFn != nil {
+		return m.blockIteratorFn(fromBlockNum, toBlockNum)
+	}
+
+	return &mockIterator{}, nil
+}
+
+// mockIterator is a simple slice-backed iterator
+type mockIterator struct {
+	blocks []*types.Block
+	pos    int
+}
+
+func (m *mockIterator) Next() bool {
+	if m.pos >= len(m.blocks) {
+		return false
+	}
+
+	m.pos++
+
+	return true
+}
+
+func (m *mockIterator) Error() error {
+	return nil
+}
+
+func (m *mockIterator) Value() (*types.Block, error) {
+	return m.blocks[m.pos-1], nil
+}
+
+func (m *mockIterator) Close() error {
+	return nil
+}
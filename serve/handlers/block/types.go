@@ -1,10 +1,34 @@
 package block
 
 import (
+	"time"
+
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/storage"
 )
 
 type Storage interface {
 	// GetBlock returns specified block from permanent storage
 	GetBlock(uint64) (*types.Block, error)
+
+	// GetLatestHeight returns the latest block height from the storage
+	GetLatestHeight() (uint64, error)
+
+	// BlockIterator iterates over Blocks, limiting the results to be between the provided block numbers
+	BlockIterator(fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.Block], error)
+}
+
+// BlockHeader is the header summary of a block, without its transactions
+type BlockHeader struct {
+	Time time.Time `json:"time"`
+
+	// Hash is the block (header) hash
+	Hash []byte `json:"hash"`
+
+	// Proposer is the address of the block proposer
+	Proposer string `json:"proposer"`
+
+	Height int64 `json:"height"`
+	NumTxs int64 `json:"numTxs"`
 }
@@ -0,0 +1,89 @@
+package chain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// defaultBlockWindow is the default number of latest blocks
+	// the chain rates are computed over
+	defaultBlockWindow = 100
+
+	// maxBlockWindow is the maximum number of latest blocks
+	// the chain rates are computed over
+	maxBlockWindow = 100000
+
+	// defaultDays is the default number of latest days
+	// the active addresses are returned for
+	defaultDays = 7
+
+	// maxDays is the maximum number of latest days
+	// the active addresses are returned for
+	maxDays = 90
+)
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetChainStatsHandler returns the chain metrics (TPS, block time, daily active addresses)
+func (h *Handler) GetChainStatsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	var (
+		window = uint64(defaultBlockWindow)
+		days   = uint64(defaultDays)
+		err    error
+	)
+
+	if len(params) > 0 {
+		window, err = toUint64(params[0])
+		if err != nil || window == 0 {
+			return nil, spec.GenerateInvalidParamError(1)
+		}
+
+		if window > maxBlockWindow {
+			return nil, spec.GenerateOutOfRangeParamError(1, maxBlockWindow)
+		}
+	}
+
+	if len(params) > 1 {
+		days, err = toUint64(params[1])
+		if err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		if days > maxDays {
+			return nil, spec.GenerateOutOfRangeParamError(2, maxDays)
+		}
+	}
+
+	// Run the handler
+	stats, err := h.storage.GetChainStats(window, days)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return stats, nil
+}
+
+func toUint64(data any) (uint64, error) {
+	return strconv.ParseUint(fmt.Sprintf("%v", data), 10, 64)
+}
@@ -0,0 +1,167 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/chainstats"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetChainStats_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{1, 2, 3},
+		},
+		{
+			"invalid window",
+			[]any{0},
+		},
+		{
+			"invalid days",
+			[]any{10, "days"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetChainStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetChainStats_OutOfRangeParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+		index  int
+		max    uint64
+	}{
+		{
+			"window too large",
+			[]any{maxBlockWindow + 1},
+			1,
+			maxBlockWindow,
+		},
+		{
+			"too many days",
+			[]any{10, maxDays + 1},
+			2,
+			maxDays,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetChainStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			assert.Equal(t, spec.GenerateOutOfRangeParamError(testCase.index, testCase.max), err)
+		})
+	}
+}
+
+func TestGetChainStats_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getChainStatsFn: func(_, _ uint64) (*chainstats.Stats, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetChainStatsHandler(nil, []any{})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("default window", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getChainStatsFn: func(window, days uint64) (*chainstats.Stats, error) {
+				require.Equal(t, uint64(defaultBlockWindow), window)
+				require.Equal(t, uint64(defaultDays), days)
+
+				return &chainstats.Stats{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.GetChainStatsHandler(nil, []any{})
+		require.Nil(t, err)
+	})
+
+	t.Run("chain stats", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			window = uint64(1000)
+			days   = uint64(30)
+
+			stats = &chainstats.Stats{
+				LatestHeight: 1000,
+				TPS:          1.5,
+			}
+
+			mockStorage = &mockStorage{
+				getChainStatsFn: func(w, d uint64) (*chainstats.Stats, error) {
+					require.Equal(t, window, w)
+					require.Equal(t, days, d)
+
+					return stats, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetChainStatsHandler(nil, []any{window, days})
+		require.Nil(t, err)
+
+		assert.Equal(t, stats, response)
+	})
+}
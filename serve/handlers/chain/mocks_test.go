@@ -0,0 +1,19 @@
+package chain
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/chainstats"
+)
+
+type getChainStatsDelegate func(uint64, uint64) (*chainstats.Stats, error)
+
+type mockStorage struct {
+	getChainStatsFn getChainStatsDelegate
+}
+
+func (m *mockStorage) GetChainStats(window, days uint64) (*chainstats.Stats, error) {
+	if m.getChainStatsFn != nil {
+		return m.getChainStatsFn(window, days)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,10 @@
+package chain
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/chainstats"
+)
+
+type Storage interface {
+	// GetChainStats returns the chain metrics over the window of latest blocks and days
+	GetChainStats(window, days uint64) (*chainstats.Stats, error)
+}
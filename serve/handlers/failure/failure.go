@@ -0,0 +1,118 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// defaultErrorWindow is the default number of latest blocks
+	// the error statistics are computed over
+	defaultErrorWindow = 1000
+
+	// maxErrorWindow is the maximum number of latest blocks
+	// the error statistics are computed over
+	maxErrorWindow = 100000
+
+	// maxTxsPerQuery is the maximum number of
+	// transactions returned in a single query
+	maxTxsPerQuery = 100
+)
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetErrorStatsHandler returns the error statistics of the failed transactions
+// in the window of latest blocks, per error code and per called realm
+func (h *Handler) GetErrorStatsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) > 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	window := uint64(defaultErrorWindow)
+
+	if len(params) > 0 {
+		var err error
+
+		window, err = strconv.ParseUint(fmt.Sprintf("%v", params[0]), 10, 64)
+		if err != nil || window == 0 {
+			return nil, spec.GenerateInvalidParamError(1)
+		}
+
+		if window > maxErrorWindow {
+			return nil, spec.GenerateOutOfRangeParamError(1, maxErrorWindow)
+		}
+	}
+
+	// Run the handler
+	stats, err := h.storage.GetErrorStats(window)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return stats, nil
+}
+
+// GetTxsByErrorHandler returns a page of transactions failed with the error code
+func (h *Handler) GetTxsByErrorHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	code, ok := params[0].(string)
+	if !ok || code == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	pagination := Pagination{
+		Limit: maxTxsPerQuery,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxTxsPerQuery {
+		pagination.Limit = maxTxsPerQuery
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	// Run the handler
+	page, err := h.storage.GetTxsByError(code, pagination.Cursor, pagination.Limit, pagination.Reverse())
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
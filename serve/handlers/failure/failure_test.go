@@ -0,0 +1,221 @@
+package failure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins/failures"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetErrorStats_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{1, 2},
+		},
+		{
+			"invalid window",
+			[]any{"window"},
+		},
+		{
+			"zero window",
+			[]any{0},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetErrorStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetErrorStats_OutOfRangeParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockStorage{})
+
+	response, err := h.GetErrorStatsHandler(nil, []any{maxErrorWindow + 1})
+	assert.Nil(t, response)
+
+	assert.Equal(t, spec.GenerateOutOfRangeParamError(1, maxErrorWindow), err)
+}
+
+func TestGetErrorStats_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getErrorStatsFn: func(_ uint64) (*failures.Stats, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetErrorStatsHandler(nil, []any{})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("error stats", func(t *testing.T) {
+		t.Parallel()
+
+		stats := &failures.Stats{
+			Codes: map[string]int{
+				"OutOfGasError": 1,
+			},
+			FromHeight: 1,
+			ToHeight:   1000,
+			FailedTxs:  1,
+		}
+
+		mockStorage := &mockStorage{
+			getErrorStatsFn: func(window uint64) (*failures.Stats, error) {
+				require.Equal(t, uint64(defaultErrorWindow), window)
+
+				return stats, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetErrorStatsHandler(nil, []any{})
+		require.Nil(t, err)
+
+		assert.Equal(t, stats, response)
+	})
+}
+
+func TestGetTxsByError_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid code type",
+			[]any{10},
+		},
+		{
+			"empty code",
+			[]any{""},
+		},
+		{
+			"invalid pagination",
+			[]any{"OutOfGasError", "not an object"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTxsByErrorHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTxsByError_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxsByErrorFn: func(_, _ string, _ int, _ bool) (*failures.Page, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsByErrorHandler(nil, []any{"OutOfGasError", map[string]any{"cursor": "invalid"}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("failed transactions", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			page = &failures.Page{
+				Txs: []*failures.TxError{
+					{
+						TxHash:  "hash",
+						Code:    "OutOfGasError",
+						Message: "out of gas",
+						Height:  10,
+					},
+				},
+				Cursor: "000000000000000b00000000",
+			}
+
+			mockStorage = &mockStorage{
+				getTxsByErrorFn: func(code, cursor string, limit int, _ bool) (*failures.Page, error) {
+					require.Equal(t, "OutOfGasError", code)
+					require.Equal(t, "", cursor)
+					require.Equal(t, 1, limit)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsByErrorHandler(nil, []any{"OutOfGasError", map[string]any{"limit": 1}})
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+}
@@ -0,0 +1,31 @@
+package failure
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/failures"
+)
+
+type (
+	getErrorStatsDelegate func(uint64) (*failures.Stats, error)
+	getTxsByErrorDelegate func(string, string, int, bool) (*failures.Page, error)
+)
+
+type mockStorage struct {
+	getErrorStatsFn getErrorStatsDelegate
+	getTxsByErrorFn getTxsByErrorDelegate
+}
+
+func (m *mockStorage) GetErrorStats(window uint64) (*failures.Stats, error) {
+	if m.getErrorStatsFn != nil {
+		return m.getErrorStatsFn(window)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetTxsByError(code, cursor string, limit int, reverse bool) (*failures.Page, error) {
+	if m.getTxsByErrorFn != nil {
+		return m.getTxsByErrorFn(code, cursor, limit, reverse)
+	}
+
+	return nil, nil
+}
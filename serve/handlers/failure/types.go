@@ -0,0 +1,26 @@
+package failure
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/failures"
+	"github.com/gnolang/tx-indexer/query"
+)
+
+type Storage interface {
+	// GetErrorStats returns the error statistics of the failed transactions in the window of latest blocks
+	GetErrorStats(window uint64) (*failures.Stats, error)
+
+	// GetTxsByError returns a page of transactions failed with the error code
+	GetTxsByError(code, cursor string, limit int, reverse bool) (*failures.Page, error)
+}
+
+// Pagination is the failed transaction pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of transactions in the page
+	Limit int `json:"limit"`
+
+	// Ordering is the ordering of the transactions, oldest first by default
+	query.Ordering
+}
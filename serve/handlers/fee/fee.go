@@ -0,0 +1,65 @@
+package fee
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// defaultFeeWindow is the default number of latest blocks
+	// the fee statistics are computed over
+	defaultFeeWindow = 100
+
+	// maxFeeWindow is the maximum number of latest blocks
+	// the fee statistics are computed over
+	maxFeeWindow = 10000
+)
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetFeeStatsHandler returns the fee percentiles of the transactions
+// in the window of latest blocks
+func (h *Handler) GetFeeStatsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) > 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	window := uint64(defaultFeeWindow)
+
+	if len(params) > 0 {
+		var err error
+
+		window, err = strconv.ParseUint(fmt.Sprintf("%v", params[0]), 10, 64)
+		if err != nil || window == 0 {
+			return nil, spec.GenerateInvalidParamError(1)
+		}
+
+		if window > maxFeeWindow {
+			return nil, spec.GenerateOutOfRangeParamError(1, maxFeeWindow)
+		}
+	}
+
+	// Run the handler
+	stats, err := h.storage.GetFeeStats(window)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,141 @@
+package fee
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/fees"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetFeeStats_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{1, 2},
+		},
+		{
+			"invalid window",
+			[]any{"window"},
+		},
+		{
+			"zero window",
+			[]any{0},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetFeeStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetFeeStats_OutOfRangeParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockStorage{})
+
+	response, err := h.GetFeeStatsHandler(nil, []any{maxFeeWindow + 1})
+	assert.Nil(t, response)
+
+	assert.Equal(t, spec.GenerateOutOfRangeParamError(1, maxFeeWindow), err)
+}
+
+func TestGetFeeStats_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getFeeStatsFn: func(_ uint64) (*fees.Stats, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetFeeStatsHandler(nil, []any{})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("default window", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getFeeStatsFn: func(window uint64) (*fees.Stats, error) {
+				require.Equal(t, uint64(defaultFeeWindow), window)
+
+				return &fees.Stats{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.GetFeeStatsHandler(nil, []any{})
+		require.Nil(t, err)
+	})
+
+	t.Run("fee stats", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			window = uint64(50)
+
+			stats = &fees.Stats{
+				FromHeight: 51,
+				ToHeight:   100,
+				ByDenom: map[string]*fees.DenomStats{
+					"ugnot": {
+						TxCount: 1,
+					},
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getFeeStatsFn: func(w uint64) (*fees.Stats, error) {
+					require.Equal(t, window, w)
+
+					return stats, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetFeeStatsHandler(nil, []any{window})
+		require.Nil(t, err)
+
+		assert.Equal(t, stats, response)
+	})
+}
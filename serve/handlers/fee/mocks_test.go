@@ -0,0 +1,19 @@
+package fee
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/fees"
+)
+
+type getFeeStatsDelegate func(uint64) (*fees.Stats, error)
+
+type mockStorage struct {
+	getFeeStatsFn getFeeStatsDelegate
+}
+
+func (m *mockStorage) GetFeeStats(window uint64) (*fees.Stats, error) {
+	if m.getFeeStatsFn != nil {
+		return m.getFeeStatsFn(window)
+	}
+
+	return nil, nil
+}
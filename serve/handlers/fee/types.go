@@ -0,0 +1,10 @@
+package fee
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/fees"
+)
+
+type Storage interface {
+	// GetFeeStats returns the fee statistics of the transactions in the window of latest blocks
+	GetFeeStats(window uint64) (*fees.Stats, error)
+}
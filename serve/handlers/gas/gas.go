@@ -0,0 +1,64 @@
+package gas
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetGasStatsHandler returns the gas usage report, in total, per message type
+// and per realm, for the given block range (all-time, if omitted)
+func (h *Handler) GetGasStatsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	var (
+		fromHeight uint64
+		toHeight   uint64
+		err        error
+	)
+
+	// Extract the params
+	if len(params) > 0 {
+		fromHeight, err = toUint64(params[0])
+		if err != nil {
+			return nil, spec.GenerateInvalidParamError(1)
+		}
+	}
+
+	if len(params) > 1 {
+		toHeight, err = toUint64(params[1])
+		if err != nil || (toHeight != 0 && toHeight < fromHeight) {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	// Run the handler
+	report, err := h.storage.GetGasStats(fromHeight, toHeight)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return report, nil
+}
+
+func toUint64(data any) (uint64, error) {
+	return strconv.ParseUint(fmt.Sprintf("%v", data), 10, 64)
+}
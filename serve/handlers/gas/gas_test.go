@@ -0,0 +1,116 @@
+package gas
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/gas"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetGasStats_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{1, 2, 3},
+		},
+		{
+			"invalid from height",
+			[]any{"-1"},
+		},
+		{
+			"invalid to height",
+			[]any{1, "height"},
+		},
+		{
+			"inverted range",
+			[]any{10, 5},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetGasStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetGasStats_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getGasStatsFn: func(_, _ uint64) (*gas.Report, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetGasStatsHandler(nil, []any{})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("block range report", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fromHeight = uint64(10)
+			toHeight   = uint64(20)
+
+			report = &gas.Report{
+				Total: &gas.Stats{
+					TxCount: 1,
+					GasUsed: 100,
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getGasStatsFn: func(from, to uint64) (*gas.Report, error) {
+					require.Equal(t, fromHeight, from)
+					require.Equal(t, toHeight, to)
+
+					return report, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetGasStatsHandler(nil, []any{fromHeight, toHeight})
+		require.Nil(t, err)
+
+		assert.Equal(t, report, response)
+	})
+}
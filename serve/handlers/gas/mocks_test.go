@@ -0,0 +1,19 @@
+package gas
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/gas"
+)
+
+type getGasStatsDelegate func(uint64, uint64) (*gas.Report, error)
+
+type mockStorage struct {
+	getGasStatsFn getGasStatsDelegate
+}
+
+func (m *mockStorage) GetGasStats(fromHeight, toHeight uint64) (*gas.Report, error) {
+	if m.getGasStatsFn != nil {
+		return m.getGasStatsFn(fromHeight, toHeight)
+	}
+
+	return nil, nil
+}
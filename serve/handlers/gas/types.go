@@ -0,0 +1,10 @@
+package gas
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/gas"
+)
+
+type Storage interface {
+	// GetGasStats returns the gas usage report of the transactions in the height range
+	GetGasStats(fromHeight, toHeight uint64) (*gas.Report, error)
+}
@@ -0,0 +1,61 @@
+package leaderboard
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/plugins/leaderboard"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxAccountsPerQuery is the maximum number of
+// accounts returned in a single query
+const maxAccountsPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetTopAccountsHandler returns the accounts ranked by the given metric
+func (h *Handler) GetTopAccountsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	metric, ok := params[0].(string)
+	if !ok || !slices.Contains(leaderboard.Metrics, metric) {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	limit := maxAccountsPerQuery
+
+	if len(params) > 1 {
+		requestedLimit, err := strconv.Atoi(fmt.Sprintf("%v", params[1]))
+		if err != nil || requestedLimit <= 0 {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		limit = min(requestedLimit, maxAccountsPerQuery)
+	}
+
+	// Run the handler
+	accounts, err := h.storage.GetTopAccounts(metric, limit)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,137 @@
+package leaderboard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/leaderboard"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetTopAccounts_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid metric type",
+			[]any{10},
+		},
+		{
+			"unknown metric",
+			[]any{"balance"},
+		},
+		{
+			"invalid limit",
+			[]any{leaderboard.MetricTxCount, "limit"},
+		},
+		{
+			"zero limit",
+			[]any{leaderboard.MetricTxCount, 0},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTopAccountsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTopAccounts_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getTopAccountsFn: func(_ string, _ int) ([]*leaderboard.Account, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTopAccountsHandler(nil, []any{leaderboard.MetricGasUsed})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("capped limit", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTopAccountsFn: func(_ string, limit int) ([]*leaderboard.Account, error) {
+				require.Equal(t, maxAccountsPerQuery, limit)
+
+				return []*leaderboard.Account{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.GetTopAccountsHandler(nil, []any{leaderboard.MetricTxCount, maxAccountsPerQuery + 1})
+		require.Nil(t, err)
+	})
+
+	t.Run("top accounts", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			metric = leaderboard.MetricRealmsDeployed
+			limit  = 5
+
+			accounts = []*leaderboard.Account{
+				{
+					Address:        "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5",
+					RealmsDeployed: 10,
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getTopAccountsFn: func(m string, l int) ([]*leaderboard.Account, error) {
+					require.Equal(t, metric, m)
+					require.Equal(t, limit, l)
+
+					return accounts, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTopAccountsHandler(nil, []any{metric, limit})
+		require.Nil(t, err)
+
+		assert.Equal(t, accounts, response)
+	})
+}
@@ -0,0 +1,19 @@
+package leaderboard
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/leaderboard"
+)
+
+type getTopAccountsDelegate func(string, int) ([]*leaderboard.Account, error)
+
+type mockStorage struct {
+	getTopAccountsFn getTopAccountsDelegate
+}
+
+func (m *mockStorage) GetTopAccounts(metric string, limit int) ([]*leaderboard.Account, error) {
+	if m.getTopAccountsFn != nil {
+		return m.getTopAccountsFn(metric, limit)
+	}
+
+	return nil, nil
+}
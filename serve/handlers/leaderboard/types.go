@@ -0,0 +1,10 @@
+package leaderboard
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/leaderboard"
+)
+
+type Storage interface {
+	// GetTopAccounts returns the accounts with the highest metric value
+	GetTopAccounts(metric string, limit int) ([]*leaderboard.Account, error)
+}
@@ -0,0 +1,19 @@
+package realm
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+)
+
+type getEventsDelegate func(string, string, string, int, bool) (*realmevents.Page, error)
+
+type mockStorage struct {
+	getEventsFn getEventsDelegate
+}
+
+func (m *mockStorage) GetEvents(realm, eventType, cursor string, limit int, reverse bool) (*realmevents.Page, error) {
+	if m.getEventsFn != nil {
+		return m.getEventsFn(realm, eventType, cursor, limit, reverse)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+package realm
+
+import (
+	"errors"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxEventsPerQuery is the maximum number of
+// realm events returned in a single query
+const maxEventsPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetEventsHandler returns a page of events emitted by the realm,
+// optionally limited to the given event type
+func (h *Handler) GetEventsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 3 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	realm, ok := params[0].(string)
+	if !ok || realm == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var eventType string
+
+	if len(params) > 1 {
+		eventType, ok = params[1].(string)
+		if !ok {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	pagination := Pagination{
+		Limit: maxEventsPerQuery,
+	}
+
+	if len(params) > 2 {
+		if err := spec.ParseObjectParameter(params[2], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(3)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxEventsPerQuery {
+		pagination.Limit = maxEventsPerQuery
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(3)
+	}
+
+	// Run the handler
+	page, err := h.storage.GetEvents(realm, eventType, pagination.Cursor, pagination.Limit, pagination.Reverse())
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(3)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,166 @@
+package realm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetEvents_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid realm",
+			[]any{""},
+		},
+		{
+			"invalid type",
+			[]any{"gno.land/r/demo/boards", 10},
+		},
+		{
+			"invalid pagination",
+			[]any{"gno.land/r/demo/boards", "", "not an object"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetEventsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetEvents_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getEventsFn: func(_, _, _ string, _ int, _ bool) (*realmevents.Page, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetEventsHandler(
+			nil,
+			[]any{"gno.land/r/demo/boards", "", map[string]any{"cursor": "invalid"}},
+		)
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getEventsFn: func(_, _, _ string, _ int, _ bool) (*realmevents.Page, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetEventsHandler(nil, []any{"gno.land/r/demo/boards"})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("paginated events", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			realm     = "gno.land/r/demo/boards"
+			eventType = "PostCreated"
+			cursor    = "cursor"
+			limit     = 10
+
+			page = &realmevents.Page{
+				Events: []*realmevents.Event{
+					{
+						Realm: realm,
+						Type:  eventType,
+					},
+				},
+				Cursor: "next",
+			}
+
+			mockStorage = &mockStorage{
+				getEventsFn: func(r, e, c string, l int, _ bool) (*realmevents.Page, error) {
+					require.Equal(t, realm, r)
+					require.Equal(t, eventType, e)
+					require.Equal(t, cursor, c)
+					require.Equal(t, limit, l)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetEventsHandler(
+			nil,
+			[]any{realm, eventType, map[string]any{"cursor": cursor, "limit": limit}},
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getEventsFn: func(_, _, _ string, l int, _ bool) (*realmevents.Page, error) {
+				require.Equal(t, maxEventsPerQuery, l)
+
+				return &realmevents.Page{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.GetEventsHandler(nil, []any{"gno.land/r/demo/boards", "", map[string]any{"limit": 0}})
+		require.Nil(t, err)
+	})
+}
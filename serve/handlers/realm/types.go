@@ -0,0 +1,23 @@
+package realm
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+	"github.com/gnolang/tx-indexer/query"
+)
+
+type Storage interface {
+	// GetEvents returns a page of realm events, optionally of the given type
+	GetEvents(realm, eventType, cursor string, limit int, reverse bool) (*realmevents.Page, error)
+}
+
+// Pagination is the realm event query pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of events in the page
+	Limit int `json:"limit"`
+
+	// Ordering is the ordering of the events, oldest first by default
+	query.Ordering
+}
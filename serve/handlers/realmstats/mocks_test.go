@@ -0,0 +1,31 @@
+package realmstats
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/realmstats"
+)
+
+type (
+	getRealmStatsDelegate func(string, uint64) (*realmstats.Stats, error)
+	getTopRealmsDelegate  func(string, int) ([]*realmstats.Stats, error)
+)
+
+type mockStorage struct {
+	getRealmStatsFn getRealmStatsDelegate
+	getTopRealmsFn  getTopRealmsDelegate
+}
+
+func (m *mockStorage) GetRealmStats(path string, days uint64) (*realmstats.Stats, error) {
+	if m.getRealmStatsFn != nil {
+		return m.getRealmStatsFn(path, days)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetTopRealms(metric string, limit int) ([]*realmstats.Stats, error) {
+	if m.getTopRealmsFn != nil {
+		return m.getTopRealmsFn(metric, limit)
+	}
+
+	return nil, nil
+}
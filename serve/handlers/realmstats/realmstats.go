@@ -0,0 +1,98 @@
+package realmstats
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/gnolang/tx-indexer/plugins/realmstats"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxRealmsPerQuery is the maximum number of
+// realms returned in a single query
+const maxRealmsPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetRealmStatsHandler returns the activity counters of the realm,
+// over the given number of latest days (all-time if omitted)
+func (h *Handler) GetRealmStatsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	path, ok := params[0].(string)
+	if !ok || path == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var days uint64
+
+	if len(params) > 1 {
+		window, err := strconv.ParseUint(fmt.Sprintf("%v", params[1]), 10, 64)
+		if err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		days = window
+	}
+
+	// Run the handler
+	stats, err := h.storage.GetRealmStats(path, days)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return stats, nil
+}
+
+// GetTopRealmsHandler returns the realms ranked by the given metric
+func (h *Handler) GetTopRealmsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	metric, ok := params[0].(string)
+	if !ok || !slices.Contains(realmstats.Metrics, metric) {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	limit := maxRealmsPerQuery
+
+	if len(params) > 1 {
+		requestedLimit, err := strconv.Atoi(fmt.Sprintf("%v", params[1]))
+		if err != nil || requestedLimit <= 0 {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		limit = min(requestedLimit, maxRealmsPerQuery)
+	}
+
+	// Run the handler
+	realms, err := h.storage.GetTopRealms(metric, limit)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return realms, nil
+}
@@ -0,0 +1,220 @@
+package realmstats
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/realmstats"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetRealmStats_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid path type",
+			[]any{10},
+		},
+		{
+			"empty path",
+			[]any{""},
+		},
+		{
+			"invalid window",
+			[]any{"gno.land/r/demo/boards", "week"},
+		},
+		{
+			"negative window",
+			[]any{"gno.land/r/demo/boards", -1},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetRealmStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetRealmStats_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getRealmStatsFn: func(_ string, _ uint64) (*realmstats.Stats, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetRealmStatsHandler(nil, []any{"gno.land/r/demo/boards"})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("windowed stats", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			path = "gno.land/r/demo/boards"
+			days = uint64(7)
+
+			stats = &realmstats.Stats{
+				Path:          path,
+				From:          "2024-03-01",
+				Calls:         10,
+				UniqueCallers: 3,
+			}
+
+			mockStorage = &mockStorage{
+				getRealmStatsFn: func(p string, d uint64) (*realmstats.Stats, error) {
+					require.Equal(t, path, p)
+					require.Equal(t, days, d)
+
+					return stats, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetRealmStatsHandler(nil, []any{path, days})
+		require.Nil(t, err)
+
+		assert.Equal(t, stats, response)
+	})
+}
+
+func TestGetTopRealms_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid metric type",
+			[]any{10},
+		},
+		{
+			"unknown metric",
+			[]any{"balance"},
+		},
+		{
+			"invalid limit",
+			[]any{realmstats.MetricCalls, "limit"},
+		},
+		{
+			"zero limit",
+			[]any{realmstats.MetricCalls, 0},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTopRealmsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTopRealms_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("capped limit", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTopRealmsFn: func(_ string, limit int) ([]*realmstats.Stats, error) {
+				require.Equal(t, maxRealmsPerQuery, limit)
+
+				return []*realmstats.Stats{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.GetTopRealmsHandler(nil, []any{realmstats.MetricGasUsed, maxRealmsPerQuery + 1})
+		require.Nil(t, err)
+	})
+
+	t.Run("top realms", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			metric = realmstats.MetricUniqueCallers
+			limit  = 5
+
+			realms = []*realmstats.Stats{
+				{
+					Path:          "gno.land/r/demo/users",
+					UniqueCallers: 10,
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getTopRealmsFn: func(m string, l int) ([]*realmstats.Stats, error) {
+					require.Equal(t, metric, m)
+					require.Equal(t, limit, l)
+
+					return realms, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTopRealmsHandler(nil, []any{metric, limit})
+		require.Nil(t, err)
+
+		assert.Equal(t, realms, response)
+	})
+}
@@ -0,0 +1,13 @@
+package realmstats
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/realmstats"
+)
+
+type Storage interface {
+	// GetRealmStats returns the realm counters of the latest days, or all-time if 0
+	GetRealmStats(path string, days uint64) (*realmstats.Stats, error)
+
+	// GetTopRealms returns the realms with the highest metric value
+	GetTopRealms(metric string, limit int) ([]*realmstats.Stats, error)
+}
@@ -0,0 +1,19 @@
+package search
+
+import (
+	txSearch "github.com/gnolang/tx-indexer/plugins/search"
+)
+
+type searchTxsDelegate func(string, string, int, bool) (*txSearch.Page, error)
+
+type mockStorage struct {
+	searchTxsFn searchTxsDelegate
+}
+
+func (m *mockStorage) SearchTxs(query, cursor string, limit int, reverse bool) (*txSearch.Page, error) {
+	if m.searchTxsFn != nil {
+		return m.searchTxsFn(query, cursor, limit, reverse)
+	}
+
+	return nil, nil
+}
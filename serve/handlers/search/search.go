@@ -0,0 +1,76 @@
+package search
+
+import (
+	"errors"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	txSearch "github.com/gnolang/tx-indexer/plugins/search"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxTxsPerQuery is the maximum number of
+// transactions returned in a single query
+const maxTxsPerQuery = 100
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// SearchTxsHandler returns a page of transactions whose memo
+// or string call arguments contain all the query terms
+func (h *Handler) SearchTxsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	query, ok := params[0].(string)
+	if !ok || query == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	pagination := Pagination{
+		Limit: maxTxsPerQuery,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxTxsPerQuery {
+		pagination.Limit = maxTxsPerQuery
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	// Run the handler
+	page, err := h.storage.SearchTxs(query, pagination.Cursor, pagination.Limit, pagination.Reverse())
+	if errors.Is(err, txSearch.ErrInvalidQuery) {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
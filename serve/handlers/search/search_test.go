@@ -0,0 +1,181 @@
+package search
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	txSearch "github.com/gnolang/tx-indexer/plugins/search"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestSearchTxs_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid query",
+			[]any{""},
+		},
+		{
+			"invalid query type",
+			[]any{10},
+		},
+		{
+			"invalid pagination",
+			[]any{"payment", "not an object"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.SearchTxsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestSearchTxs_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid query terms", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			searchTxsFn: func(_, _ string, _ int, _ bool) (*txSearch.Page, error) {
+				return nil, txSearch.ErrInvalidQuery
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.SearchTxsHandler(nil, []any{"!"})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			searchTxsFn: func(_, _ string, _ int, _ bool) (*txSearch.Page, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.SearchTxsHandler(nil, []any{"payment", map[string]any{"cursor": "invalid"}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				searchTxsFn: func(_, _ string, _ int, _ bool) (*txSearch.Page, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.SearchTxsHandler(nil, []any{"payment"})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("paginated transactions", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			query  = "rent payment"
+			cursor = "cursor"
+			limit  = 10
+
+			page = &txSearch.Page{
+				Txs: []*txSearch.Match{
+					{
+						TxHash: "hash",
+						Memo:   "Rent payment for March",
+						Height: 10,
+					},
+				},
+				Cursor: "next",
+			}
+
+			mockStorage = &mockStorage{
+				searchTxsFn: func(q, c string, l int, _ bool) (*txSearch.Page, error) {
+					require.Equal(t, query, q)
+					require.Equal(t, cursor, c)
+					require.Equal(t, limit, l)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.SearchTxsHandler(
+			nil,
+			[]any{query, map[string]any{"cursor": cursor, "limit": limit}},
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+
+	t.Run("limit capped", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			searchTxsFn: func(_, _ string, l int, _ bool) (*txSearch.Page, error) {
+				require.Equal(t, maxTxsPerQuery, l)
+
+				return &txSearch.Page{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.SearchTxsHandler(nil, []any{"payment", map[string]any{"limit": maxTxsPerQuery + 1}})
+		require.Nil(t, err)
+	})
+}
@@ -0,0 +1,23 @@
+package search
+
+import (
+	txSearch "github.com/gnolang/tx-indexer/plugins/search"
+	"github.com/gnolang/tx-indexer/query"
+)
+
+type Storage interface {
+	// SearchTxs returns a page of transactions containing all the query terms
+	SearchTxs(query, cursor string, limit int, reverse bool) (*txSearch.Page, error)
+}
+
+// Pagination is the transaction search pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of transactions in the page
+	Limit int `json:"limit"`
+
+	// Ordering is the ordering of the matching transactions, oldest first by default
+	query.Ordering
+}
@@ -0,0 +1,33 @@
+package series
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/plugins/rollups"
+)
+
+type (
+	getSeriesDelegate    func(string, string, time.Time, time.Time) ([]*rollups.Point, error)
+	getFeeSeriesDelegate func(string, string, time.Time, time.Time) ([]*rollups.Point, error)
+)
+
+type mockStorage struct {
+	getSeriesFn    getSeriesDelegate
+	getFeeSeriesFn getFeeSeriesDelegate
+}
+
+func (m *mockStorage) GetSeries(metric, interval string, from, to time.Time) ([]*rollups.Point, error) {
+	if m.getSeriesFn != nil {
+		return m.getSeriesFn(metric, interval, from, to)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetFeeSeries(denom, interval string, from, to time.Time) ([]*rollups.Point, error) {
+	if m.getFeeSeriesFn != nil {
+		return m.getFeeSeriesFn(denom, interval, from, to)
+	}
+
+	return nil, nil
+}
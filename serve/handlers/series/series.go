@@ -0,0 +1,152 @@
+package series
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gnolang/tx-indexer/plugins/rollups"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+var errInvalidTime = errors.New("invalid time")
+
+// maxPointsPerQuery is the maximum number of
+// time series points returned in a single query
+const maxPointsPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetBlockCountSeriesHandler returns the number of blocks per interval bucket
+func (h *Handler) GetBlockCountSeriesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return h.getSeries(rollups.MetricBlocks, params)
+}
+
+// GetTxCountSeriesHandler returns the number of transactions per interval bucket
+func (h *Handler) GetTxCountSeriesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return h.getSeries(rollups.MetricTxs, params)
+}
+
+// GetGasSeriesHandler returns the gas used per interval bucket
+func (h *Handler) GetGasSeriesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return h.getSeries(rollups.MetricGasUsed, params)
+}
+
+// GetActiveAddressSeriesHandler returns the number of active addresses per interval bucket
+func (h *Handler) GetActiveAddressSeriesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	return h.getSeries(rollups.MetricActiveAddresses, params)
+}
+
+// getSeries returns the metric time series of the interval and time range params
+func (h *Handler) getSeries(metric string, params []any) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 3 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	interval, from, to, paramErr := extractRange(params)
+	if paramErr != nil {
+		return nil, paramErr
+	}
+
+	// Run the handler
+	points, err := h.storage.GetSeries(metric, interval, from, to)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return points, nil
+}
+
+// GetFeeSeriesHandler returns the paid fees of the denomination per interval bucket
+func (h *Handler) GetFeeSeriesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 4 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	interval, from, to, paramErr := extractRange(params)
+	if paramErr != nil {
+		return nil, paramErr
+	}
+
+	denom, ok := params[3].(string)
+	if !ok || denom == "" {
+		return nil, spec.GenerateInvalidParamError(4)
+	}
+
+	// Run the handler
+	points, err := h.storage.GetFeeSeries(denom, interval, from, to)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return points, nil
+}
+
+// extractRange extracts the bucket interval, and the (RFC 3339) time range params
+func extractRange(params []any) (string, time.Time, time.Time, *spec.BaseJSONError) {
+	interval, ok := params[0].(string)
+	if !ok {
+		return "", time.Time{}, time.Time{}, spec.GenerateInvalidParamError(1)
+	}
+
+	length, ok := rollups.Intervals[interval]
+	if !ok {
+		return "", time.Time{}, time.Time{}, spec.GenerateInvalidParamError(1)
+	}
+
+	from, err := extractTime(params[1])
+	if err != nil || from.Unix() < 0 {
+		return "", time.Time{}, time.Time{}, spec.GenerateInvalidParamError(2)
+	}
+
+	to, err := extractTime(params[2])
+	if err != nil || to.Before(from) {
+		return "", time.Time{}, time.Time{}, spec.GenerateInvalidParamError(3)
+	}
+
+	// Make sure the range doesn't span too many buckets
+	seconds := int64(length / time.Second)
+
+	if to.Unix()/seconds-from.Unix()/seconds >= maxPointsPerQuery {
+		return "", time.Time{}, time.Time{}, spec.GenerateInvalidParamError(3)
+	}
+
+	return interval, from, to, nil
+}
+
+// extractTime parses the RFC 3339 time param
+func extractTime(param any) (time.Time, error) {
+	raw, ok := param.(string)
+	if !ok {
+		return time.Time{}, errInvalidTime
+	}
+
+	return time.Parse(time.RFC3339, raw)
+}
@@ -0,0 +1,224 @@
+package series
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/rollups"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetSeries_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from = "2024-03-01T00:00:00Z"
+		to   = "2024-03-02T00:00:00Z"
+	)
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{rollups.IntervalHour, from},
+		},
+		{
+			"invalid interval type",
+			[]any{10, from, to},
+		},
+		{
+			"unknown interval",
+			[]any{"week", from, to},
+		},
+		{
+			"invalid from time",
+			[]any{rollups.IntervalHour, "2024-03-01", to},
+		},
+		{
+			"invalid to time",
+			[]any{rollups.IntervalHour, from, 1709337600},
+		},
+		{
+			"inverted time range",
+			[]any{rollups.IntervalHour, to, from},
+		},
+		{
+			"too many points",
+			[]any{rollups.IntervalHour, from, "2024-06-01T00:00:00Z"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTxCountSeriesHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetSeries_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		to   = from.Add(24 * time.Hour)
+
+		params = []any{rollups.IntervalDay, from.Format(time.RFC3339), to.Format(time.RFC3339)}
+
+		points = []*rollups.Point{
+			{Time: from, Value: 10},
+			{Time: to, Value: 20},
+		}
+	)
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getSeriesFn: func(_, _ string, _, _ time.Time) ([]*rollups.Point, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetGasSeriesHandler(nil, params)
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	testTable := []struct {
+		handler func(h *Handler, params []any) (any, *spec.BaseJSONError)
+		name    string
+		metric  string
+	}{
+		{
+			func(h *Handler, params []any) (any, *spec.BaseJSONError) {
+				return h.GetBlockCountSeriesHandler(nil, params)
+			},
+			"block count series",
+			rollups.MetricBlocks,
+		},
+		{
+			func(h *Handler, params []any) (any, *spec.BaseJSONError) {
+				return h.GetTxCountSeriesHandler(nil, params)
+			},
+			"transaction count series",
+			rollups.MetricTxs,
+		},
+		{
+			func(h *Handler, params []any) (any, *spec.BaseJSONError) {
+				return h.GetGasSeriesHandler(nil, params)
+			},
+			"gas series",
+			rollups.MetricGasUsed,
+		},
+		{
+			func(h *Handler, params []any) (any, *spec.BaseJSONError) {
+				return h.GetActiveAddressSeriesHandler(nil, params)
+			},
+			"active address series",
+			rollups.MetricActiveAddresses,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockStorage := &mockStorage{
+				getSeriesFn: func(metric, interval string, rangeFrom, rangeTo time.Time) ([]*rollups.Point, error) {
+					require.Equal(t, testCase.metric, metric)
+					require.Equal(t, rollups.IntervalDay, interval)
+					require.True(t, from.Equal(rangeFrom))
+					require.True(t, to.Equal(rangeTo))
+
+					return points, nil
+				},
+			}
+
+			h := NewHandler(mockStorage)
+
+			response, err := testCase.handler(h, params)
+			require.Nil(t, err)
+
+			assert.Equal(t, points, response)
+		})
+	}
+}
+
+func TestGetFeeSeries_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		from = "2024-03-01T00:00:00Z"
+		to   = "2024-03-01T05:00:00Z"
+	)
+
+	t.Run("invalid denom", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{})
+
+		response, err := h.GetFeeSeriesHandler(nil, []any{rollups.IntervalHour, from, to, ""})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("fee series", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			denom  = "ugnot"
+			points = []*rollups.Point{
+				{Value: 1000},
+			}
+
+			mockStorage = &mockStorage{
+				getFeeSeriesFn: func(d, interval string, _, _ time.Time) ([]*rollups.Point, error) {
+					require.Equal(t, denom, d)
+					require.Equal(t, rollups.IntervalHour, interval)
+
+					return points, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetFeeSeriesHandler(nil, []any{rollups.IntervalHour, from, to, denom})
+		require.Nil(t, err)
+
+		assert.Equal(t, points, response)
+	})
+}
@@ -0,0 +1,15 @@
+package series
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/plugins/rollups"
+)
+
+type Storage interface {
+	// GetSeries returns the metric time series of the interval buckets covering the time range
+	GetSeries(metric, interval string, from, to time.Time) ([]*rollups.Point, error)
+
+	// GetFeeSeries returns the paid fees time series of the denomination
+	GetFeeSeries(denom, interval string, from, to time.Time) ([]*rollups.Point, error)
+}
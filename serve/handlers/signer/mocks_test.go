@@ -0,0 +1,41 @@
+package signer
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/signers"
+)
+
+type (
+	getTxSignersDelegate   func(string) (*signers.TxSigners, error)
+	getTxsBySignerDelegate func(string, string, int, bool) (*signers.Page, error)
+	getTxsByPubKeyDelegate func(string, string, int, bool) (*signers.Page, error)
+)
+
+type mockStorage struct {
+	getTxSignersFn   getTxSignersDelegate
+	getTxsBySignerFn getTxsBySignerDelegate
+	getTxsByPubKeyFn getTxsByPubKeyDelegate
+}
+
+func (m *mockStorage) GetTxSigners(hash string) (*signers.TxSigners, error) {
+	if m.getTxSignersFn != nil {
+		return m.getTxSignersFn(hash)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetTxsBySigner(address, cursor string, limit int, reverse bool) (*signers.Page, error) {
+	if m.getTxsBySignerFn != nil {
+		return m.getTxsBySignerFn(address, cursor, limit, reverse)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetTxsByPubKey(pubKey, cursor string, limit int, reverse bool) (*signers.Page, error) {
+	if m.getTxsByPubKeyFn != nil {
+		return m.getTxsByPubKeyFn(pubKey, cursor, limit, reverse)
+	}
+
+	return nil, nil
+}
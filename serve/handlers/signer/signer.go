@@ -0,0 +1,160 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxTxsPerQuery is the maximum number of
+// transactions returned in a single query
+const maxTxsPerQuery = 100
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetTxSignersHandler returns all the signers of the transaction,
+// including the multisig co-signers
+func (h *Handler) GetTxSignersHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	hash, ok := params[0].(string)
+	if !ok || hash == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	// Run the handler
+	txSigners, err := h.storage.GetTxSigners(hash)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	if txSigners == nil {
+		return nil, nil
+	}
+
+	return txSigners, nil
+}
+
+// GetTxsBySignerHandler returns a page of transactions signed by the address,
+// either as a signing account or as a multisig co-signer
+func (h *Handler) GetTxsBySignerHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	address, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if _, err := crypto.AddressFromBech32(address); err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	pagination := Pagination{
+		Limit: maxTxsPerQuery,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxTxsPerQuery {
+		pagination.Limit = maxTxsPerQuery
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	// Run the handler
+	page, err := h.storage.GetTxsBySigner(address, pagination.Cursor, pagination.Limit, pagination.Reverse())
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
+
+// GetTxsByPubKeyHandler returns a page of transactions signed by the (bech32) public key,
+// either as a signature key or as a multisig member key
+func (h *Handler) GetTxsByPubKeyHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	encodedKey, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	pubKey, err := crypto.PubKeyFromBech32(encodedKey)
+	if err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	pagination := Pagination{
+		Limit: maxTxsPerQuery,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxTxsPerQuery {
+		pagination.Limit = maxTxsPerQuery
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	// Run the handler, with the key in its indexed (canonical) encoding
+	page, err := h.storage.GetTxsByPubKey(crypto.PubKeyToBech32(pubKey), pagination.Cursor, pagination.Limit, pagination.Reverse())
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
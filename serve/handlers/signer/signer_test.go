@@ -0,0 +1,352 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins/signers"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetTxSigners_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid hash type",
+			[]any{10},
+		},
+		{
+			"empty hash",
+			[]any{""},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTxSignersHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTxSigners_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getTxSignersFn: func(_ string) (*signers.TxSigners, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxSignersHandler(nil, []any{"hash"})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("transaction not indexed", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{})
+
+		response, err := h.GetTxSignersHandler(nil, []any{"hash"})
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("transaction signers found", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			txSigners = &signers.TxSigners{
+				TxHash:      "hash",
+				Signers:     []string{crypto.Address{1}.String(), crypto.Address{2}.String()},
+				CoSigners:   []*signers.CoSigner{},
+				MultiSigner: true,
+			}
+
+			mockStorage = &mockStorage{
+				getTxSignersFn: func(hash string) (*signers.TxSigners, error) {
+					require.Equal(t, "hash", hash)
+
+					return txSigners, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxSignersHandler(nil, []any{"hash"})
+		require.Nil(t, err)
+
+		assert.Equal(t, txSigners, response)
+	})
+}
+
+func TestGetTxsBySigner_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid address type",
+			[]any{10},
+		},
+		{
+			"invalid address",
+			[]any{"address"},
+		},
+		{
+			"invalid pagination",
+			[]any{crypto.Address{1}.String(), "not an object"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTxsBySignerHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTxsBySigner_Handler(t *testing.T) {
+	t.Parallel()
+
+	address := crypto.Address{1}.String()
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxsBySignerFn: func(_, _ string, _ int, _ bool) (*signers.Page, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsBySignerHandler(nil, []any{address, map[string]any{"cursor": "invalid"}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getTxsBySignerFn: func(_, _ string, _ int, _ bool) (*signers.Page, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsBySignerHandler(nil, []any{address})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("signer transactions", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			page = &signers.Page{
+				Txs: []*signers.TxSigners{
+					{
+						TxHash:  "hash",
+						Signers: []string{address},
+						Height:  10,
+					},
+				},
+				Cursor: "000000000000000b00000000",
+			}
+
+			mockStorage = &mockStorage{
+				getTxsBySignerFn: func(a, cursor string, limit int, _ bool) (*signers.Page, error) {
+					require.Equal(t, address, a)
+					require.Equal(t, "", cursor)
+					require.Equal(t, 1, limit)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsBySignerHandler(nil, []any{address, map[string]any{"limit": 1}})
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+}
+
+func TestGetTxsByPubKey_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	pubKey := crypto.PubKeyToBech32(secp256k1.GenPrivKey().PubKey())
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid public key type",
+			[]any{10},
+		},
+		{
+			"invalid public key",
+			[]any{crypto.Address{1}.String()},
+		},
+		{
+			"invalid pagination",
+			[]any{pubKey, "not an object"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTxsByPubKeyHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTxsByPubKey_Handler(t *testing.T) {
+	t.Parallel()
+
+	pubKey := crypto.PubKeyToBech32(secp256k1.GenPrivKey().PubKey())
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxsByPubKeyFn: func(_, _ string, _ int, _ bool) (*signers.Page, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsByPubKeyHandler(nil, []any{pubKey, map[string]any{"cursor": "invalid"}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("public key transactions", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			page = &signers.Page{
+				Txs: []*signers.TxSigners{
+					{
+						TxHash:  "hash",
+						Signers: []string{crypto.Address{1}.String()},
+						PubKeys: []string{pubKey},
+						Height:  10,
+					},
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getTxsByPubKeyFn: func(key, cursor string, limit int, _ bool) (*signers.Page, error) {
+					require.Equal(t, pubKey, key)
+					require.Equal(t, "", cursor)
+					require.Equal(t, maxTxsPerQuery, limit)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTxsByPubKeyHandler(nil, []any{pubKey})
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+}
@@ -0,0 +1,29 @@
+package signer
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/signers"
+	"github.com/gnolang/tx-indexer/query"
+)
+
+type Storage interface {
+	// GetTxSigners returns the signers of the transaction with the hash, if indexed
+	GetTxSigners(hash string) (*signers.TxSigners, error)
+
+	// GetTxsBySigner returns a page of transactions signed by the address
+	GetTxsBySigner(address, cursor string, limit int, reverse bool) (*signers.Page, error)
+
+	// GetTxsByPubKey returns a page of transactions signed by the (bech32) public key
+	GetTxsByPubKey(pubKey, cursor string, limit int, reverse bool) (*signers.Page, error)
+}
+
+// Pagination is the signer transaction pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of transactions in the page
+	Limit int `json:"limit"`
+
+	// Ordering is the ordering of the transactions, oldest first by default
+	query.Ordering
+}
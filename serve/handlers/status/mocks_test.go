@@ -0,0 +1,53 @@
+package status
+
+type (
+	getLatestHeightDelegate      func() (uint64, error)
+	sizeDelegate                 func() (uint64, error)
+	getLatestBlockNumberDelegate func() (uint64, error)
+	numSubscriptionsDelegate     func() int
+)
+
+type mockStorage struct {
+	getLatestHeightFn getLatestHeightDelegate
+	sizeFn            sizeDelegate
+}
+
+func (m *mockStorage) GetLatestHeight() (uint64, error) {
+	if m.getLatestHeightFn != nil {
+		return m.getLatestHeightFn()
+	}
+
+	return 0, nil
+}
+
+func (m *mockStorage) Size() (uint64, error) {
+	if m.sizeFn != nil {
+		return m.sizeFn()
+	}
+
+	return 0, nil
+}
+
+type mockClient struct {
+	getLatestBlockNumberFn getLatestBlockNumberDelegate
+}
+
+func (m *mockClient) GetLatestBlockNumber() (uint64, error) {
+	if m.getLatestBlockNumberFn != nil {
+		return m.getLatestBlockNumberFn()
+	}
+
+	return 0, nil
+}
+
+type mockSubscriptions struct {
+	numSubscriptionsFn numSubscriptionsDelegate
+}
+
+func (m *mockSubscriptions) NumSubscriptions() int {
+	if m.numSubscriptionsFn != nil {
+		return m.numSubscriptionsFn()
+	}
+
+	return 0
+}
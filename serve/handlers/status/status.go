@@ -0,0 +1,79 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+type Handler struct {
+	storage       Storage
+	client        Client
+	subscriptions Subscriptions
+
+	startTime time.Time
+}
+
+func NewHandler(storage Storage, client Client, subscriptions Subscriptions) *Handler {
+	return &Handler{
+		storage:       storage,
+		client:        client,
+		subscriptions: subscriptions,
+		startTime:     time.Now(),
+	}
+}
+
+func (h *Handler) GetStatusHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 0 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Run the handler
+	response, err := h.getStatus()
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return response, nil
+}
+
+// getStatus assembles the current indexer status
+func (h *Handler) getStatus() (*Status, error) {
+	latestHeight, err := h.storage.GetLatestHeight()
+	if err != nil && !errors.Is(err, storageErrors.ErrNotFound) {
+		return nil, fmt.Errorf("unable to get latest height, %w", err)
+	}
+
+	remoteHeight, err := h.client.GetLatestBlockNumber()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get remote height, %w", err)
+	}
+
+	size, err := h.storage.Size()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get storage size, %w", err)
+	}
+
+	var lag uint64
+	if remoteHeight > latestHeight {
+		lag = remoteHeight - latestHeight
+	}
+
+	return &Status{
+		LatestHeight:  latestHeight,
+		RemoteHeight:  remoteHeight,
+		Lag:           lag,
+		StorageSize:   size,
+		Subscriptions: h.subscriptions.NumSubscriptions(),
+		StartedAt:     h.startTime.UTC().Format(time.RFC3339),
+		Uptime:        int64(time.Since(h.startTime).Seconds()),
+	}, nil
+}
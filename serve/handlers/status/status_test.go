@@ -0,0 +1,132 @@
+package status
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+func TestGetStatus_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockStorage{}, &mockClient{}, &mockSubscriptions{})
+
+	response, err := h.GetStatusHandler(nil, []any{1})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+}
+
+func TestGetStatus_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("remote fetch error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fetchErr = errors.New("random error")
+
+			mockClient = &mockClient{
+				getLatestBlockNumberFn: func() (uint64, error) {
+					return 0, fetchErr
+				},
+			}
+		)
+
+		h := NewHandler(&mockStorage{}, mockClient, &mockSubscriptions{})
+
+		response, err := h.GetStatusHandler(nil, []any{})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, fetchErr.Error())
+	})
+
+	t.Run("empty storage", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			remoteHeight = uint64(100)
+
+			mockStorage = &mockStorage{
+				getLatestHeightFn: func() (uint64, error) {
+					return 0, storageErrors.ErrNotFound
+				},
+			}
+
+			mockClient = &mockClient{
+				getLatestBlockNumberFn: func() (uint64, error) {
+					return remoteHeight, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, mockClient, &mockSubscriptions{})
+
+		responseRaw, err := h.GetStatusHandler(nil, []any{})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.(*Status)
+		require.True(t, ok)
+
+		assert.Equal(t, uint64(0), response.LatestHeight)
+		assert.Equal(t, remoteHeight, response.Lag)
+	})
+
+	t.Run("valid status", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			latestHeight     = uint64(90)
+			remoteHeight     = uint64(100)
+			size             = uint64(1024)
+			numSubscriptions = 5
+
+			mockStorage = &mockStorage{
+				getLatestHeightFn: func() (uint64, error) {
+					return latestHeight, nil
+				},
+				sizeFn: func() (uint64, error) {
+					return size, nil
+				},
+			}
+
+			mockClient = &mockClient{
+				getLatestBlockNumberFn: func() (uint64, error) {
+					return remoteHeight, nil
+				},
+			}
+
+			mockSubscriptions = &mockSubscriptions{
+				numSubscriptionsFn: func() int {
+					return numSubscriptions
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, mockClient, mockSubscriptions)
+
+		responseRaw, err := h.GetStatusHandler(nil, []any{})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.(*Status)
+		require.True(t, ok)
+
+		assert.Equal(t, latestHeight, response.LatestHeight)
+		assert.Equal(t, remoteHeight, response.RemoteHeight)
+		assert.Equal(t, remoteHeight-latestHeight, response.Lag)
+		assert.Equal(t, size, response.StorageSize)
+		assert.Equal(t, numSubscriptions, response.Subscriptions)
+		assert.NotEmpty(t, response.StartedAt)
+	})
+}
@@ -0,0 +1,30 @@
+package status
+
+type Storage interface {
+	// GetLatestHeight returns the latest block height from the storage
+	GetLatestHeight() (uint64, error)
+
+	// Size returns the approximate on-disk size of the storage, in bytes
+	Size() (uint64, error)
+}
+
+type Client interface {
+	// GetLatestBlockNumber returns the latest block height from the chain
+	GetLatestBlockNumber() (uint64, error)
+}
+
+type Subscriptions interface {
+	// NumSubscriptions returns the number of active subscriptions
+	NumSubscriptions() int
+}
+
+// Status is the indexer status overview
+type Status struct {
+	StartedAt     string `json:"startedAt"`
+	LatestHeight  uint64 `json:"latestHeight"`
+	RemoteHeight  uint64 `json:"remoteHeight"`
+	Lag           uint64 `json:"lag"`
+	StorageSize   uint64 `json:"storageSize"`
+	Subscriptions int    `json:"subscriptions"`
+	Uptime        int64  `json:"uptime"` // seconds
+}
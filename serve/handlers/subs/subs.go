@@ -1,6 +1,7 @@
 package subs
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/gnolang/tx-indexer/serve/encode"
@@ -11,6 +12,8 @@ import (
 	"github.com/gnolang/tx-indexer/serve/spec"
 )
 
+var errInvalidEventType = errors.New("invalid event type")
+
 type Handler struct {
 	connFetcher ConnectionFetcher
 
@@ -97,10 +100,7 @@ func (h *Handler) SubscribeHandler(
 ) (any, *spec.BaseJSONError) {
 	// This method can only be called through a WS connection
 	if !metadata.IsWS() {
-		return nil, spec.NewJSONError(
-			"Method only supported over WS",
-			spec.ServerErrorCode,
-		)
+		return nil, spec.GenerateMethodNotSupportedError()
 	}
 
 	// Check the params
@@ -114,7 +114,30 @@ func (h *Handler) SubscribeHandler(
 		return nil, spec.GenerateInvalidParamError(1)
 	}
 
-	subscriptionID, err := h.subscribe(*metadata.WebSocketID, eventType)
+	// Extract the optional event subscription filter
+	var options subscription.EventFilterOption
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &options); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	subscriptionID, err := h.subscribe(*metadata.WebSocketID, eventType, options)
+	if errors.Is(err, errInvalidEventType) {
+		return nil, spec.NewJSONErrorWithData(err.Error(), spec.InvalidParamsErrorCode, &spec.ErrorData{Param: 1})
+	}
+
+	var limitErr *filters.SubscriptionLimitError
+	if errors.As(err, &limitErr) {
+		scope := spec.ScopeGlobal
+		if limitErr.PerConn {
+			scope = spec.ScopeConnection
+		}
+
+		return nil, spec.GenerateSubscriptionLimitError(limitErr.Error(), scope, uint64(limitErr.Max))
+	}
+
 	if err != nil {
 		return nil, spec.NewJSONError(
 			fmt.Sprintf("unable to subscribe, %s", err.Error()),
@@ -125,7 +148,11 @@ func (h *Handler) SubscribeHandler(
 	return subscriptionID, nil
 }
 
-func (h *Handler) subscribe(connID, eventType string) (string, error) {
+func (h *Handler) subscribe(
+	connID,
+	eventType string,
+	options subscription.EventFilterOption,
+) (string, error) {
 	conn := h.connFetcher.GetWSConnection(connID)
 	if conn == nil {
 		return "", fmt.Errorf("WS connection with ID %s not found", connID)
@@ -133,11 +160,15 @@ func (h *Handler) subscribe(connID, eventType string) (string, error) {
 
 	switch eventType {
 	case subscription.NewHeadsEvent:
-		return h.filterManager.NewBlockSubscription(conn), nil
+		return h.filterManager.NewBlockSubscription(connID, conn)
 	case subscription.NewTransactionsEvent:
-		return h.filterManager.NewTransactionSubscription(conn), nil
+		return h.filterManager.NewTransactionSubscription(connID, conn)
+	case subscription.NewEventsEvent:
+		return h.filterManager.NewEventSubscription(connID, conn, options.Realm, options.Type)
+	case subscription.NewAlertsEvent:
+		return h.filterManager.NewAlertSubscription(connID, conn)
 	default:
-		return "", fmt.Errorf("invalid event type: %s", eventType)
+		return "", fmt.Errorf("%w: %s", errInvalidEventType, eventType)
 	}
 }
 
@@ -147,10 +178,7 @@ func (h *Handler) UnsubscribeHandler(
 ) (any, *spec.BaseJSONError) {
 	// This method can only be called through a WS connection
 	if !metadata.IsWS() {
-		return nil, spec.NewJSONError(
-			"Method only supported over WS",
-			spec.ServerErrorCode,
-		)
+		return nil, spec.GenerateMethodNotSupportedError()
 	}
 
 	// Check the params
@@ -186,6 +214,10 @@ func (h *Handler) GetFilterChangesHandler(_ *metadata.Metadata, params []any) (a
 
 	// Get filter by id
 	f, err := h.filterManager.GetFilter(id)
+	if errors.Is(err, filters.ErrFilterNotFound) {
+		return nil, spec.GenerateNotFoundError("filter", id)
+	}
+
 	if err != nil {
 		return nil, spec.GenerateResponseError(err)
 	}
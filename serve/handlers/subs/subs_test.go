@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/tx-indexer/events"
-	"github.com/gnolang/tx-indexer/internal/mock"
+	"github.com/gnolang/tx-indexer/mock"
 	"github.com/gnolang/tx-indexer/serve/conns"
 	"github.com/gnolang/tx-indexer/serve/filters"
 	"github.com/gnolang/tx-indexer/serve/filters/filter"
@@ -377,7 +377,7 @@ func TestSubscribe_InvalidParams(t *testing.T) {
 		// Check the error
 		require.NotNil(t, err)
 
-		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
 		assert.Contains(t, err.Message, fmt.Sprintf("invalid event type: %s", eventType))
 	})
 }
@@ -529,7 +529,7 @@ func TestSubscribeUnsubscribe_InvalidParams(t *testing.T) {
 			"not WS connection",
 			&metadata.Metadata{WebSocketID: nil},
 			[]any{},
-			spec.ServerErrorCode,
+			spec.MethodNotSupportedErrorCode,
 		},
 		{
 			"invalid param length",
@@ -622,3 +622,90 @@ func TestUnsubscribe_Valid(t *testing.T) {
 
 	assert.True(t, response)
 }
+
+func TestSubscribe_Limits(t *testing.T) {
+	t.Parallel()
+
+	subscribe := func(h *Handler, connID string) (any, *spec.BaseJSONError) {
+		return h.SubscribeHandler(
+			&metadata.Metadata{WebSocketID: &connID},
+			[]any{subscription.NewHeadsEvent},
+		)
+	}
+
+	newHandler := func(limiter *filters.SubscriptionLimiter) *Handler {
+		fm := filters.NewFilterManager(
+			context.Background(),
+			&mock.Storage{},
+			events.NewManager(),
+			filters.WithSubscriptionLimiter(limiter),
+		)
+
+		return NewHandler(fm, &mockConnectionFetcher{
+			getWSConnectionFn: func(_ string) conns.WSConnection {
+				return &mock.Conn{}
+			},
+		})
+	}
+
+	t.Run("per connection limit", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHandler(filters.NewSubscriptionLimiter(0, 1))
+
+		id, err := subscribe(h, "conn 1")
+		require.Nil(t, err)
+
+		// Make sure the connection is capped
+		response, err := subscribe(h, "conn 1")
+		assert.Nil(t, response)
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.SubscriptionLimitErrorCode, err.Code)
+		assert.Equal(t, spec.ReasonSubscriptionLimit, err.Data.Reason)
+		assert.Equal(t, spec.ScopeConnection, err.Data.Scope)
+		assert.Equal(t, uint64(1), *err.Data.Max)
+
+		// Make sure the other connections are not capped
+		_, err = subscribe(h, "conn 2")
+		assert.Nil(t, err)
+
+		// Make sure the unsubscribed connection can subscribe again
+		connID := "conn 1"
+
+		unsubscribed, err := h.UnsubscribeHandler(
+			&metadata.Metadata{WebSocketID: &connID},
+			[]any{id},
+		)
+		require.Nil(t, err)
+		require.Equal(t, true, unsubscribed)
+
+		_, err = subscribe(h, "conn 1")
+		assert.Nil(t, err)
+	})
+
+	t.Run("global limit", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHandler(filters.NewSubscriptionLimiter(2, 0))
+
+		_, err := subscribe(h, "conn 1")
+		require.Nil(t, err)
+
+		_, err = subscribe(h, "conn 2")
+		require.Nil(t, err)
+
+		_, err = subscribe(h, "conn 3")
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.SubscriptionLimitErrorCode, err.Code)
+		assert.Equal(t, spec.ScopeGlobal, err.Data.Scope)
+		assert.Equal(t, uint64(2), *err.Data.Max)
+
+		// Make sure the closed connection subscriptions are released
+		h.filterManager.UninstallConnSubscriptions("conn 1")
+
+		_, err = subscribe(h, "conn 3")
+		assert.Nil(t, err)
+	})
+}
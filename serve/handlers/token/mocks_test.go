@@ -0,0 +1,37 @@
+package token
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/grc20"
+)
+
+type (
+	getTokenTransfersDelegate func(string, string, string, int, bool) (*grc20.TransferPage, error)
+	getTokenHoldersDelegate   func(string, string, int) (*grc20.HolderPage, error)
+)
+
+type mockStorage struct {
+	getTokenTransfersFn getTokenTransfersDelegate
+	getTokenHoldersFn   getTokenHoldersDelegate
+}
+
+func (m *mockStorage) GetTokenTransfers(
+	token,
+	address,
+	cursor string,
+	limit int,
+	reverse bool,
+) (*grc20.TransferPage, error) {
+	if m.getTokenTransfersFn != nil {
+		return m.getTokenTransfersFn(token, address, cursor, limit, reverse)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetTokenHolders(token, cursor string, limit int) (*grc20.HolderPage, error) {
+	if m.getTokenHoldersFn != nil {
+		return m.getTokenHoldersFn(token, cursor, limit)
+	}
+
+	return nil, nil
+}
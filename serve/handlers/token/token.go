@@ -0,0 +1,139 @@
+package token
+
+import (
+	"errors"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxResultsPerQuery is the maximum number of
+// transfers or holders returned in a single page
+const maxResultsPerQuery = 1000
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetTokenTransfersHandler returns a page of the GRC20 token transfers,
+// optionally limited to the ones the given address participated in
+func (h *Handler) GetTokenTransfersHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 3 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	token, ok := params[0].(string)
+	if !ok || token == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var address string
+
+	if len(params) > 1 {
+		address, ok = params[1].(string)
+		if !ok {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		// The empty address stands for all the transfers
+		if address != "" {
+			if _, err := crypto.AddressFromBech32(address); err != nil {
+				return nil, spec.GenerateInvalidParamError(2)
+			}
+		}
+	}
+
+	pagination := Pagination{
+		Limit: maxResultsPerQuery,
+	}
+
+	if len(params) > 2 {
+		if err := spec.ParseObjectParameter(params[2], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(3)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxResultsPerQuery {
+		pagination.Limit = maxResultsPerQuery
+	}
+
+	if err := pagination.Validate(); err != nil {
+		return nil, spec.GenerateInvalidParamError(3)
+	}
+
+	// Run the handler
+	page, err := h.storage.GetTokenTransfers(
+		token,
+		address,
+		pagination.Cursor,
+		pagination.Limit,
+		pagination.Reverse(),
+	)
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(3)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
+
+// GetTokenHoldersHandler returns a page of the GRC20 token holders,
+// with the largest balances first
+func (h *Handler) GetTokenHoldersHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	token, ok := params[0].(string)
+	if !ok || token == "" {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	pagination := HolderPagination{
+		Limit: maxResultsPerQuery,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxResultsPerQuery {
+		pagination.Limit = maxResultsPerQuery
+	}
+
+	// Run the handler
+	page, err := h.storage.GetTokenHolders(token, pagination.Cursor, pagination.Limit)
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return page, nil
+}
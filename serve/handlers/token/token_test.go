@@ -0,0 +1,296 @@
+package token
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/plugins/grc20"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetTokenTransfers_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"too many params",
+			[]any{"gno.land/r/demo/foo20", crypto.Address{1}.String(), map[string]any{}, "extra"},
+		},
+		{
+			"invalid token",
+			[]any{10},
+		},
+		{
+			"invalid address",
+			[]any{"gno.land/r/demo/foo20", "not an address"},
+		},
+		{
+			"invalid pagination",
+			[]any{"gno.land/r/demo/foo20", "", "pagination"},
+		},
+		{
+			"invalid ordering",
+			[]any{"gno.land/r/demo/foo20", "", map[string]any{"sortBy": "gas"}},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTokenTransfersHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTokenTransfers_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getTokenTransfersFn: func(_, _, _ string, _ int, _ bool) (*grc20.TransferPage, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTokenTransfersHandler(nil, []any{"gno.land/r/demo/foo20"})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTokenTransfersFn: func(_, _, _ string, _ int, _ bool) (*grc20.TransferPage, error) {
+				return nil, cursors.ErrInvalid
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTokenTransfersHandler(
+			nil,
+			[]any{"gno.land/r/demo/foo20", "", map[string]any{"cursor": "invalid"}},
+		)
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("address transfers", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			token   = "gno.land/r/demo/foo20"
+			address = crypto.Address{1}.String()
+
+			page = &grc20.TransferPage{
+				Transfers: []*grc20.Transfer{
+					{
+						Token:  token,
+						To:     address,
+						Amount: "100",
+					},
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getTokenTransfersFn: func(
+					requestedToken,
+					a,
+					cursor string,
+					limit int,
+					reverse bool,
+				) (*grc20.TransferPage, error) {
+					require.Equal(t, token, requestedToken)
+					require.Equal(t, address, a)
+					require.Empty(t, cursor)
+					require.Equal(t, maxResultsPerQuery, limit)
+					require.False(t, reverse)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTokenTransfersHandler(nil, []any{token, address})
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+
+	t.Run("paginated transfers", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			token = "gno.land/r/demo/foo20"
+
+			page = &grc20.TransferPage{
+				Transfers: []*grc20.Transfer{
+					{
+						Token:  token,
+						Amount: "100",
+					},
+				},
+				Cursor: "next",
+			}
+
+			mockStorage = &mockStorage{
+				getTokenTransfersFn: func(
+					requestedToken,
+					address,
+					cursor string,
+					limit int,
+					reverse bool,
+				) (*grc20.TransferPage, error) {
+					require.Equal(t, token, requestedToken)
+					require.Empty(t, address)
+					require.Equal(t, "cursor", cursor)
+					require.Equal(t, 10, limit)
+					require.True(t, reverse)
+
+					return page, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetTokenTransfersHandler(
+			nil,
+			[]any{token, "", map[string]any{"cursor": "cursor", "limit": 10, "order": "desc"}},
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, page, response)
+	})
+}
+
+func TestGetTokenHolders_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid token",
+			[]any{""},
+		},
+		{
+			"invalid pagination",
+			[]any{"gno.land/r/demo/foo20", "pagination"},
+		},
+		{
+			"too many params",
+			[]any{"gno.land/r/demo/foo20", map[string]any{}, "extra"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetTokenHoldersHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTokenHolders_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token = "gno.land/r/demo/foo20"
+
+		page = &grc20.HolderPage{
+			Holders: []*grc20.Holder{
+				{
+					Address: crypto.Address{1}.String(),
+					Balance: "100",
+				},
+			},
+			Cursor: "next",
+		}
+
+		mockStorage = &mockStorage{
+			getTokenHoldersFn: func(requestedToken, cursor string, limit int) (*grc20.HolderPage, error) {
+				require.Equal(t, token, requestedToken)
+				require.Equal(t, "cursor", cursor)
+				require.Equal(t, maxResultsPerQuery, limit)
+
+				return page, nil
+			},
+		}
+	)
+
+	h := NewHandler(mockStorage)
+
+	response, err := h.GetTokenHoldersHandler(nil, []any{token, map[string]any{"cursor": "cursor"}})
+	require.Nil(t, err)
+
+	assert.Equal(t, page, response)
+
+	// Make sure the invalid cursors are rejected
+	mockStorage.getTokenHoldersFn = func(_, _ string, _ int) (*grc20.HolderPage, error) {
+		return nil, cursors.ErrInvalid
+	}
+
+	response, err = h.GetTokenHoldersHandler(nil, []any{token, map[string]any{"cursor": "invalid"}})
+	assert.Nil(t, response)
+
+	require.NotNil(t, err)
+
+	assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+}
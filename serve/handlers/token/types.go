@@ -0,0 +1,35 @@
+package token
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/grc20"
+	"github.com/gnolang/tx-indexer/query"
+)
+
+type Storage interface {
+	// GetTokenTransfers returns a page of token transfers, optionally for the given address
+	GetTokenTransfers(token, address, cursor string, limit int, reverse bool) (*grc20.TransferPage, error)
+
+	// GetTokenHolders returns a page of token holders, with the largest balances first
+	GetTokenHolders(token, cursor string, limit int) (*grc20.HolderPage, error)
+}
+
+// Pagination is the token transfer query pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of transfers in the page
+	Limit int `json:"limit"`
+
+	// Ordering is the ordering of the transfers, oldest first by default
+	query.Ordering
+}
+
+// HolderPagination is the token holder query pagination
+type HolderPagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of holders in the page
+	Limit int `json:"limit"`
+}
@@ -1,14 +1,40 @@
 package tx
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+type getBlockDelegate func(uint64) (*types.Block, error)
 
 type getTxDelegate func(uint64, uint32) (*types.TxResult, error)
 
 type getTxHashDelegate func(string) (*types.TxResult, error)
 
+type getLatestHeightDelegate func() (uint64, error)
+
+type txByAddressIteratorDelegate func(string, uint64, uint64) (storage.Iterator[*types.TxResult], error)
+
+type txIteratorDelegate func(uint64, uint64, uint32, uint32) (storage.Iterator[*types.TxResult], error)
+
 type mockStorage struct {
-	getTxFn     getTxDelegate
-	getTxHashFn getTxHashDelegate
+	getBlockFn                   getBlockDelegate
+	getTxFn                      getTxDelegate
+	getTxHashFn                  getTxHashDelegate
+	getLatestHeightFn            getLatestHeightDelegate
+	txByAddressIteratorFn        txByAddressIteratorDelegate
+	reverseTxByAddressIteratorFn txByAddressIteratorDelegate
+	txIteratorFn                 txIteratorDelegate
+	reverseTxIteratorFn          txIteratorDelegate
+}
+
+func (m *mockStorage) GetBlock(bn uint64) (*types.Block, error) {
+	if m.getBlockFn != nil {
+		return m.getBlockFn(bn)
+	}
+
+	return nil, nil
 }
 
 func (m *mockStorage) GetTx(bn uint64, ti uint32) (*types.TxResult, error) {
@@ -19,6 +45,14 @@ func (m *mockStorage) GetTx(bn uint64, ti uint32) (*types.TxResult, error) {
 	return nil, nil
 }
 
+func (m *mockStorage) GetLatestHeight() (uint64, error) {
+	if m.getLatestHeightFn != nil {
+		return m.getLatestHeightFn()
+	}
+
+	return 0, nil
+}
+
 func (m *mockStorage) GetTxByHash(h string) (*types.TxResult, error) {
 	if m.getTxHashFn != nil {
 		return m.getTxHashFn(h)
@@ -26,3 +60,81 @@ func (m *mockStorage) GetTxByHash(h string) (*types.TxResult, error) {
 
 	return nil, nil
 }
+
+func (m *mockStorage) TxByAddressIterator(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.txByAddressIteratorFn != nil {
+		return m.txByAddressIteratorFn(address, fromBlockNum, toBlockNum)
+	}
+
+	return &mockIterator{}, nil
+}
+
+func (m *mockStorage) TxIterator(
+	fromBlockNum,
+	toBlockNum uint64,
+	fromTxIndex,
+	toTxIndex uint32,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.txIteratorFn != nil {
+		return m.txIteratorFn(fromBlockNum, toBlockNum, fromTxIndex, toTxIndex)
+	}
+
+	return &mockIterator{}, nil
+}
+
+func (m *mockStorage) ReverseTxByAddressIterator(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.reverseTxByAddressIteratorFn != nil {
+		return m.reverseTxByAddressIteratorFn(address, fromBlockNum, toBlockNum)
+	}
+
+	return &mockIterator{}, nil
+}
+
+func (m *mockStorage) ReverseTxIterator(
+	fromBlockNum,
+	toBlockNum uint64,
+	fromTxIndex,
+	toTxIndex uint32,
+) (storage.Iterator[*types.TxResult], error) {
+	if m.reverseTxIteratorFn != nil {
+		return m.reverseTxIteratorFn(fromBlockNum, toBlockNum, fromTxIndex, toTxIndex)
+	}
+
+	return &mockIterator{}, nil
+}
+
+// mockIterator is a simple slice-backed iterator
+type mockIterator struct {
+	txs []*types.TxResult
+	pos int
+}
+
+func (m *mockIterator) Next() bool {
+	if m.pos >= len(m.txs) {
+		return false
+	}
+
+	m.pos++
+
+	return true
+}
+
+func (m *mockIterator) Error() error {
+	return nil
+}
+
+func (m *mockIterator) Value() (*types.TxResult, error) {
+	return m.txs[m.pos-1], nil
+}
+
+func (m *mockIterator) Close() error {
+	return nil
+}
@@ -6,20 +6,40 @@ import (
 	"strconv"
 
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 
+	"github.com/gnolang/tx-indexer/cursors"
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/query"
 	"github.com/gnolang/tx-indexer/serve/encode"
 	"github.com/gnolang/tx-indexer/serve/metadata"
 	"github.com/gnolang/tx-indexer/serve/spec"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
+const (
+	// maxTxsPerQuery is the maximum number of transactions returned by a single list query
+	maxTxsPerQuery = 1000
+
+	// maxTxsPerPage is the maximum number of transactions
+	// in a single page of the filter expression query
+	maxTxsPerPage = 100
+
+	// maxHashesPerQuery is the maximum number of
+	// transaction hashes fetched by a single query
+	maxHashesPerQuery = 100
+)
+
 type Handler struct {
 	storage Storage
+	decoder *decode.Decoder
 }
 
-func NewHandler(storage Storage) *Handler {
+// NewHandler creates the tx handler, matching the query filters with the txs decoded by the chain decoder
+func NewHandler(storage Storage, decoder *decode.Decoder) *Handler {
 	return &Handler{
 		storage: storage,
+		decoder: decoder,
 	}
 }
 
@@ -50,10 +70,10 @@ func (h *Handler) GetTxHandler(
 	}
 
 	if response == nil {
-		return nil, nil
+		return nil, h.checkSynced(blockNum)
 	}
 
-	encodedResponse, err := encode.PrepareValue(response)
+	encodedResponse, err := encode.EncodeValue(response)
 	if err != nil {
 		return nil, spec.GenerateResponseError(err)
 	}
@@ -86,7 +106,7 @@ func (h *Handler) GetTxByHashHandler(
 		return nil, nil
 	}
 
-	encodedResponse, err := encode.PrepareValue(response)
+	encodedResponse, err := encode.EncodeValue(response)
 	if err != nil {
 		return nil, spec.GenerateResponseError(err)
 	}
@@ -94,6 +114,273 @@ func (h *Handler) GetTxByHashHandler(
 	return encodedResponse, nil
 }
 
+// GetTxsByHashesHandler returns the transactions of the hashes, in the same order,
+// with null items for the transactions that are not found
+func (h *Handler) GetTxsByHashesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	hashes, ok := params[0].([]any)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if len(hashes) > maxHashesPerQuery {
+		return nil, spec.GenerateOutOfRangeParamError(1, maxHashesPerQuery)
+	}
+
+	txHashes := make([]string, 0, len(hashes))
+
+	for _, hash := range hashes {
+		txHash, ok := hash.(string)
+		if !ok {
+			return nil, spec.GenerateInvalidParamError(1)
+		}
+
+		txHashes = append(txHashes, txHash)
+	}
+
+	// Run the handler
+	encodedResponse := make([]encode.Value, len(txHashes))
+
+	for i, txHash := range txHashes {
+		tx, err := h.getTxByHash(txHash)
+		if err != nil {
+			return nil, spec.GenerateResponseError(err)
+		}
+
+		if tx == nil {
+			// Not found, left as null
+			continue
+		}
+
+		if encodedResponse[i], err = encode.EncodeValue(tx); err != nil {
+			return nil, spec.GenerateResponseError(err)
+		}
+	}
+
+	return encodedResponse, nil
+}
+
+// GetTxProofHandler returns the Merkle proof of the transaction
+// inclusion in its block, against the block header data hash
+func (h *Handler) GetTxProofHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	txHash, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	// Run the handler
+	response, err := h.getTxProof(txHash)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	if response == nil {
+		return nil, nil
+	}
+
+	return response, nil
+}
+
+func (h *Handler) GetTxsByAddressHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 5 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	address, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if _, err := crypto.AddressFromBech32(address); err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var (
+		fromBlockNum uint64
+		toBlockNum   uint64
+		err          error
+	)
+
+	// The optional params can be null, for setting the following ones
+	if len(params) > 1 && params[1] != nil {
+		fromBlockNum, err = toUint64(params[1])
+		if err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if len(params) > 2 && params[2] != nil {
+		toBlockNum, err = toUint64(params[2])
+		if err != nil {
+			return nil, spec.GenerateInvalidParamError(3)
+		}
+	}
+
+	var ordering query.Ordering
+
+	if len(params) > 3 && params[3] != nil {
+		if err := spec.ParseObjectParameter(params[3], &ordering); err != nil {
+			return nil, spec.GenerateInvalidParamError(4)
+		}
+	}
+
+	if err := ordering.Validate(query.SortGas); err != nil {
+		return nil, spec.GenerateInvalidParamError(4)
+	}
+
+	var cursor string
+
+	if len(params) > 4 && params[4] != nil {
+		if cursor, ok = params[4].(string); !ok {
+			return nil, spec.GenerateInvalidParamError(5)
+		}
+	}
+
+	// Run the handler
+	page, err := query.AddressFilter(address, fromBlockNum, toBlockNum, h.decoder).
+		Run(h.storage, ordering, cursor, maxTxsPerQuery)
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(5)
+	}
+
+	if errors.Is(err, query.ErrTooManyTxs) {
+		return nil, generateTooManyTxsError(2)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	response := &TxPage{
+		Txs:    make([]encode.Value, 0, len(page.Txs)),
+		Cursor: page.Cursor,
+	}
+
+	for _, tx := range page.Txs {
+		encodedTx, err := encode.EncodeValue(tx)
+		if err != nil {
+			return nil, spec.GenerateResponseError(err)
+		}
+
+		response.Txs = append(response.Txs, encodedTx)
+	}
+
+	return response, nil
+}
+
+// QueryTxsHandler returns a page of transactions matching the filter expression,
+// in the pagination ordering (oldest first by default)
+func (h *Handler) QueryTxsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	expression, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	filter, err := query.Parse(expression, h.decoder)
+	if err != nil {
+		jsonErr := spec.GenerateInvalidParamError(1)
+		jsonErr.Message = fmt.Sprintf("%s, %s", jsonErr.Message, err)
+
+		return nil, jsonErr
+	}
+
+	pagination := Pagination{
+		Limit: maxTxsPerPage,
+	}
+
+	if len(params) > 1 {
+		if err := spec.ParseObjectParameter(params[1], &pagination); err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	if pagination.Limit <= 0 || pagination.Limit > maxTxsPerPage {
+		pagination.Limit = maxTxsPerPage
+	}
+
+	if err := pagination.Validate(query.SortGas); err != nil {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	// Run the handler
+	page, err := filter.Run(h.storage, pagination.Ordering, pagination.Cursor, pagination.Limit)
+	if errors.Is(err, cursors.ErrInvalid) {
+		return nil, spec.GenerateInvalidParamError(2)
+	}
+
+	if errors.Is(err, query.ErrTooManyTxs) {
+		return nil, generateTooManyTxsError(1)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	response := &TxPage{
+		Txs:    make([]encode.Value, 0, len(page.Txs)),
+		Cursor: page.Cursor,
+	}
+
+	for _, tx := range page.Txs {
+		encodedTx, err := encode.EncodeValue(tx)
+		if err != nil {
+			return nil, spec.GenerateResponseError(err)
+		}
+
+		response.Txs = append(response.Txs, encodedTx)
+	}
+
+	return response, nil
+}
+
+// checkSynced returns the not synced error if the height of
+// the missing tx is above the latest indexed height
+func (h *Handler) checkSynced(blockNum uint64) *spec.BaseJSONError {
+	latest, err := h.storage.GetLatestHeight()
+
+	switch {
+	case errors.Is(err, storageErrors.ErrNotFound):
+		return spec.GenerateNotSyncedError(blockNum, 0)
+	case err != nil:
+		return spec.GenerateResponseError(err)
+	case blockNum > latest:
+		return spec.GenerateNotSyncedError(blockNum, latest)
+	default:
+		return nil
+	}
+}
+
 // getTx fetches the tx from storage, if any
 func (h *Handler) getTx(blockNum uint64, txIndex uint32) (*types.TxResult, error) {
 	tx, err := h.storage.GetTx(blockNum, txIndex)
@@ -126,6 +413,55 @@ func (h *Handler) getTxByHash(hash string) (*types.TxResult, error) {
 	return tx, nil
 }
 
+// getTxProof builds the Merkle proof of the tx inclusion from the stored block, if any
+func (h *Handler) getTxProof(hash string) (*TxProof, error) {
+	tx, err := h.getTxByHash(hash)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+
+	block, err := h.storage.GetBlock(uint64(tx.Height))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch block %d, %w", tx.Height, err)
+	}
+
+	if int(tx.Index) >= len(block.Txs) {
+		return nil, fmt.Errorf("transaction %d missing from block %d", tx.Index, tx.Height)
+	}
+
+	proof := block.Txs.Proof(int(tx.Index))
+
+	// Make sure the proof is valid for the block header
+	if err := proof.Validate(block.DataHash); err != nil {
+		return nil, fmt.Errorf("unable to prove transaction inclusion, %w", err)
+	}
+
+	return &TxProof{
+		TxHash:   proof.Data.Hash(),
+		DataHash: block.DataHash,
+		Proof: &Proof{
+			LeafHash: proof.Proof.LeafHash,
+			Aunts:    proof.Proof.Aunts,
+			Total:    proof.Proof.Total,
+			Index:    proof.Proof.Index,
+		},
+		Height: tx.Height,
+		Index:  tx.Index,
+	}, nil
+}
+
+// generateTooManyTxsError generates the JSON-RPC error of the sorted query
+// scanning too many transactions, caused by the param at the index
+func generateTooManyTxsError(index int) *spec.BaseJSONError {
+	maxValue := uint64(query.MaxScannedTxs)
+
+	return spec.NewJSONErrorWithData(
+		"Too many transactions to sort, narrow down the height range",
+		spec.OutOfRangeErrorCode,
+		&spec.ErrorData{Param: index, Max: &maxValue},
+	)
+}
+
 func toUint64(data any) (uint64, error) {
 	return strconv.ParseUint(fmt.Sprintf("%v", data), 10, 64)
 }
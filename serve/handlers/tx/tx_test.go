@@ -1,16 +1,20 @@
 package tx
 
 import (
-	"encoding/base64"
 	"errors"
 	"testing"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/gnolang/tx-indexer/serve/encode"
 	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/storage"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
@@ -37,7 +41,7 @@ func TestGetTx_InvalidParams(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			h := NewHandler(&mockStorage{})
+			h := NewHandler(&mockStorage{}, nil)
 
 			response, err := h.GetTxHandler(nil, testCase.params)
 			assert.Nil(t, response)
@@ -66,10 +70,13 @@ func TestGetBlock_Handler(t *testing.T) {
 
 					return nil, storageErrors.ErrNotFound
 				},
+				getLatestHeightFn: func() (uint64, error) {
+					return blockNum, nil
+				},
 			}
 		)
 
-		h := NewHandler(mockStorage)
+		h := NewHandler(mockStorage, nil)
 
 		response, err := h.GetTxHandler(nil, []any{blockNum, txIndex})
 
@@ -78,6 +85,26 @@ func TestGetBlock_Handler(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("tx not synced", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxFn: func(_ uint64, _ uint32) (*types.TxResult, error) {
+				return nil, storageErrors.ErrNotFound
+			},
+			getLatestHeightFn: func() (uint64, error) {
+				return 0, storageErrors.ErrNotFound
+			},
+		}
+
+		h := NewHandler(mockStorage, nil)
+
+		response, err := h.GetTxHandler(nil, []any{1, 0})
+		assert.Nil(t, response)
+
+		assert.Equal(t, spec.GenerateNotSyncedError(1, 0), err)
+	})
+
 	t.Run("random fetch error", func(t *testing.T) {
 		t.Parallel()
 
@@ -94,7 +121,7 @@ func TestGetBlock_Handler(t *testing.T) {
 			}
 		)
 
-		h := NewHandler(mockStorage)
+		h := NewHandler(mockStorage, nil)
 
 		response, err := h.GetTxHandler(nil, []any{blockNum, txIndex})
 		assert.Nil(t, response)
@@ -127,25 +154,21 @@ func TestGetBlock_Handler(t *testing.T) {
 			}
 		)
 
-		h := NewHandler(mockStorage)
+		h := NewHandler(mockStorage, nil)
 
 		responseRaw, err := h.GetTxHandler(nil, []any{blockNum, txIndex})
 		require.Nil(t, err)
 
 		require.NotNil(t, responseRaw)
 
-		// Make sure the response is valid (base64 + amino)
-		response, ok := responseRaw.(string)
+		// Make sure the response is valid (amino)
+		response, ok := responseRaw.(encode.Value)
 		require.True(t, ok)
 
-		// Decode from base64
-		encodedTxResult, decodeErr := base64.StdEncoding.DecodeString(response)
-		require.Nil(t, decodeErr)
-
 		// Decode from amino binary
 		var decodedTxResult types.TxResult
 
-		require.NoError(t, amino.Unmarshal(encodedTxResult, &decodedTxResult))
+		require.NoError(t, amino.Unmarshal(response, &decodedTxResult))
 
 		assert.Equal(t, txResult, &decodedTxResult)
 	})
@@ -169,26 +192,616 @@ func TestGetBlock_Handler(t *testing.T) {
 			}
 		)
 
-		h := NewHandler(mockStorage)
+		h := NewHandler(mockStorage, nil)
 
 		responseRaw, err := h.GetTxByHashHandler(nil, []any{hash})
 		require.Nil(t, err)
 
 		require.NotNil(t, responseRaw)
 
-		// Make sure the response is valid (base64 + amino)
-		response, ok := responseRaw.(string)
+		// Make sure the response is valid (amino)
+		response, ok := responseRaw.(encode.Value)
 		require.True(t, ok)
 
-		// Decode from base64
-		encodedTxResult, decodeErr := base64.StdEncoding.DecodeString(response)
-		require.Nil(t, decodeErr)
-
 		// Decode from amino binary
 		var decodedTxResult types.TxResult
 
-		require.NoError(t, amino.Unmarshal(encodedTxResult, &decodedTxResult))
+		require.NoError(t, amino.Unmarshal(response, &decodedTxResult))
 
 		assert.Equal(t, txResult, &decodedTxResult)
 	})
 }
+
+func TestGetTxsByAddress_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	address := crypto.Address{1}.String()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid address type",
+			[]any{1},
+		},
+		{
+			"invalid address",
+			[]any{"address"},
+		},
+		{
+			"invalid from block",
+			[]any{address, "from"},
+		},
+		{
+			"invalid to block",
+			[]any{address, "1", "to"},
+		},
+		{
+			"invalid ordering",
+			[]any{address, "1", "0", "ordering"},
+		},
+		{
+			"unsupported sort field",
+			[]any{address, "1", "0", map[string]any{"sortBy": "fee"}},
+		},
+		{
+			"invalid cursor type",
+			[]any{address, nil, nil, nil, 1},
+		},
+		{
+			"invalid cursor",
+			[]any{address, nil, nil, nil, "cursor"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{}, nil)
+
+			response, err := h.GetTxsByAddressHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetTxsByAddress_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("random fetch error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fetchErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				txByAddressIteratorFn: func(_ string, _, _ uint64) (storage.Iterator[*types.TxResult], error) {
+					return nil, fetchErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, nil)
+
+		response, err := h.GetTxsByAddressHandler(nil, []any{crypto.Address{1}.String()})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, fetchErr.Error())
+	})
+
+	t.Run("txs found in storage", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			address = crypto.Address{1}.String()
+
+			txs = []*types.TxResult{
+				{
+					Height: 10,
+				},
+				{
+					Height: 11,
+				},
+			}
+
+			mockStorage = &mockStorage{
+				txByAddressIteratorFn: func(
+					a string,
+					from,
+					to uint64,
+				) (storage.Iterator[*types.TxResult], error) {
+					require.Equal(t, address, a)
+					require.EqualValues(t, 5, from)
+					require.EqualValues(t, 20, to)
+
+					return &mockIterator{txs: txs}, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, nil)
+
+		responseRaw, err := h.GetTxsByAddressHandler(nil, []any{address, "5", "20"})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.(*TxPage)
+		require.True(t, ok)
+		require.Len(t, response.Txs, len(txs))
+
+		assert.Empty(t, response.Cursor)
+
+		for i, encoded := range response.Txs {
+			// Decode from amino binary
+			var decodedTxResult types.TxResult
+
+			require.NoError(t, amino.Unmarshal(encoded, &decodedTxResult))
+
+			assert.Equal(t, txs[i], &decodedTxResult)
+		}
+	})
+
+	t.Run("txs paginated", func(t *testing.T) {
+		t.Parallel()
+
+		txs := make([]*types.TxResult, 0, maxTxsPerQuery+1)
+
+		for i := 0; i <= maxTxsPerQuery; i++ {
+			txs = append(txs, &types.TxResult{
+				Height: int64(i + 1),
+			})
+		}
+
+		mockStorage := &mockStorage{
+			txByAddressIteratorFn: func(_ string, from, _ uint64) (storage.Iterator[*types.TxResult], error) {
+				return &mockIterator{txs: txs[from-1:]}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage, nil)
+
+		responseRaw, err := h.GetTxsByAddressHandler(nil, []any{crypto.Address{1}.String(), "1"})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.(*TxPage)
+		require.True(t, ok)
+
+		// Make sure the cursor of the next page is returned, instead of silently truncating the results
+		require.Len(t, response.Txs, maxTxsPerQuery)
+		require.NotEmpty(t, response.Cursor)
+
+		responseRaw, err = h.GetTxsByAddressHandler(nil, []any{crypto.Address{1}.String(), "1", nil, nil, response.Cursor})
+		require.Nil(t, err)
+
+		response, ok = responseRaw.(*TxPage)
+		require.True(t, ok)
+
+		require.Len(t, response.Txs, 1)
+		assert.Empty(t, response.Cursor)
+
+		var decodedTxResult types.TxResult
+
+		require.NoError(t, amino.Unmarshal(response.Txs[0], &decodedTxResult))
+
+		assert.Equal(t, txs[maxTxsPerQuery], &decodedTxResult)
+	})
+
+	t.Run("txs sorted by gas, descending", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			txs = []*types.TxResult{
+				{
+					Height:   10,
+					Response: abci.ResponseDeliverTx{GasUsed: 200},
+				},
+				{
+					Height:   11,
+					Response: abci.ResponseDeliverTx{GasUsed: 300},
+				},
+				{
+					Height:   12,
+					Response: abci.ResponseDeliverTx{GasUsed: 100},
+				},
+			}
+
+			mockStorage = &mockStorage{
+				txByAddressIteratorFn: func(_ string, _, _ uint64) (storage.Iterator[*types.TxResult], error) {
+					return &mockIterator{txs: txs}, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, nil)
+
+		responseRaw, err := h.GetTxsByAddressHandler(nil, []any{
+			crypto.Address{1}.String(),
+			"0",
+			"0",
+			map[string]any{"order": "desc", "sortBy": "gas"},
+		})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.(*TxPage)
+		require.True(t, ok)
+		require.Len(t, response.Txs, len(txs))
+
+		for i, expected := range []*types.TxResult{txs[1], txs[0], txs[2]} {
+			var decodedTxResult types.TxResult
+
+			require.NoError(t, amino.Unmarshal(response.Txs[i], &decodedTxResult))
+
+			assert.Equal(t, expected, &decodedTxResult)
+		}
+	})
+}
+
+func TestGetTxProof_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		txs = types.Txs{
+			[]byte("tx 1"),
+			[]byte("tx 2"),
+			[]byte("tx 3"),
+		}
+
+		block = &types.Block{
+			Header: types.Header{
+				Height:   10,
+				DataHash: txs.Hash(),
+			},
+			Data: types.Data{
+				Txs: txs,
+			},
+		}
+	)
+
+	t.Run("invalid params", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{}, nil)
+
+		for _, params := range [][]any{{}, {10}} {
+			response, err := h.GetTxProofHandler(nil, params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		}
+	})
+
+	t.Run("tx not found", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxHashFn: func(_ string) (*types.TxResult, error) {
+				return nil, storageErrors.ErrNotFound
+			},
+		}
+
+		h := NewHandler(mockStorage, nil)
+
+		response, err := h.GetTxProofHandler(nil, []any{"hash"})
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("block fetch error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fetchErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getTxHashFn: func(_ string) (*types.TxResult, error) {
+					return &types.TxResult{Height: 10}, nil
+				},
+				getBlockFn: func(_ uint64) (*types.Block, error) {
+					return nil, fetchErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, nil)
+
+		response, err := h.GetTxProofHandler(nil, []any{"hash"})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, fetchErr.Error())
+	})
+
+	t.Run("invalid data hash", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxHashFn: func(_ string) (*types.TxResult, error) {
+				return &types.TxResult{Height: 10, Index: 1, Tx: txs[1]}, nil
+			},
+			getBlockFn: func(_ uint64) (*types.Block, error) {
+				return &types.Block{
+					Header: types.Header{
+						Height:   10,
+						DataHash: []byte("invalid"),
+					},
+					Data: block.Data,
+				}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage, nil)
+
+		response, err := h.GetTxProofHandler(nil, []any{"hash"})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+	})
+
+	t.Run("valid proof", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getTxHashFn: func(_ string) (*types.TxResult, error) {
+				return &types.TxResult{Height: 10, Index: 1, Tx: txs[1]}, nil
+			},
+			getBlockFn: func(height uint64) (*types.Block, error) {
+				require.Equal(t, uint64(10), height)
+
+				return block, nil
+			},
+		}
+
+		h := NewHandler(mockStorage, nil)
+
+		responseRaw, err := h.GetTxProofHandler(nil, []any{"hash"})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.(*TxProof)
+		require.True(t, ok)
+
+		assert.Equal(t, txs[1].Hash(), response.TxHash)
+		assert.Equal(t, block.DataHash, response.DataHash)
+		assert.Equal(t, int64(10), response.Height)
+		assert.Equal(t, uint32(1), response.Index)
+
+		// Make sure the proof can be verified against the data hash
+		proof := types.TxProof{
+			RootHash: response.DataHash,
+			Data:     txs[1],
+			Proof: merkle.SimpleProof{
+				Total:    response.Proof.Total,
+				Index:    response.Proof.Index,
+				LeafHash: response.Proof.LeafHash,
+				Aunts:    response.Proof.Aunts,
+			},
+		}
+
+		assert.NoError(t, proof.Validate(block.DataHash))
+		assert.Equal(t, 3, response.Proof.Total)
+	})
+}
+
+func TestGetTxsByHashes_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid params", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{}, nil)
+
+		for _, params := range [][]any{{}, {"hash"}, {[]any{"hash", 10}}} {
+			response, err := h.GetTxsByHashesHandler(nil, params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		}
+	})
+
+	t.Run("too many hashes", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{}, nil)
+
+		hashes := make([]any, maxHashesPerQuery+1)
+		for i := range hashes {
+			hashes[i] = "hash"
+		}
+
+		response, err := h.GetTxsByHashesHandler(nil, []any{hashes})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.OutOfRangeErrorCode, err.Code)
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			fetchErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getTxHashFn: func(_ string) (*types.TxResult, error) {
+					return nil, fetchErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage, nil)
+
+		response, err := h.GetTxsByHashesHandler(nil, []any{[]any{"hash"}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, fetchErr.Error())
+	})
+
+	t.Run("found and missing txs", func(t *testing.T) {
+		t.Parallel()
+
+		txs := map[string]*types.TxResult{
+			"hash 1": {Height: 10},
+			"hash 3": {Height: 30, Index: 1},
+		}
+
+		mockStorage := &mockStorage{
+			getTxHashFn: func(hash string) (*types.TxResult, error) {
+				tx, ok := txs[hash]
+				if !ok {
+					return nil, storageErrors.ErrNotFound
+				}
+
+				return tx, nil
+			},
+		}
+
+		h := NewHandler(mockStorage, nil)
+
+		responseRaw, err := h.GetTxsByHashesHandler(nil, []any{[]any{"hash 1", "hash 2", "hash 3"}})
+		require.Nil(t, err)
+
+		response, ok := responseRaw.([]encode.Value)
+		require.True(t, ok)
+
+		require.Len(t, response, 3)
+
+		// Make sure the missing tx is marked as null
+		assert.Nil(t, response[1])
+
+		for i, hash := range []string{"hash 1", "hash 3"} {
+			var tx types.TxResult
+
+			require.NoError(t, amino.Unmarshal(response[2*i], &tx))
+
+			assert.Equal(t, txs[hash], &tx)
+		}
+	})
+}
+
+func TestQueryTxs_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid expression type",
+			[]any{1},
+		},
+		{
+			"invalid expression",
+			[]any{"height >"},
+		},
+		{
+			"invalid pagination",
+			[]any{"success=true", "pagination"},
+		},
+		{
+			"invalid cursor",
+			[]any{"success=true", map[string]any{"cursor": "cursor"}},
+		},
+		{
+			"invalid order",
+			[]any{"success=true", map[string]any{"order": "newest"}},
+		},
+		{
+			"invalid sorted cursor",
+			[]any{"success=true", map[string]any{"order": "desc", "cursor": "00"}},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{}, nil)
+
+			response, err := h.QueryTxsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestQueryTxs_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		txs = []*types.TxResult{
+			{
+				Height: 10,
+			},
+			{
+				Height: 11,
+			},
+			{
+				Height: 12,
+			},
+		}
+
+		mockStorage = &mockStorage{
+			txIteratorFn: func(from, to uint64, _, _ uint32) (storage.Iterator[*types.TxResult], error) {
+				// Make sure the scan is narrowed by the height conditions
+				require.EqualValues(t, 11, from)
+				require.EqualValues(t, 20, to)
+
+				return &mockIterator{txs: txs[1:]}, nil
+			},
+		}
+	)
+
+	h := NewHandler(mockStorage, nil)
+
+	responseRaw, err := h.QueryTxsHandler(nil, []any{
+		"height > 10 AND height <= 20 AND success = true",
+		map[string]any{"limit": 1},
+	})
+	require.Nil(t, err)
+
+	response, ok := responseRaw.(*TxPage)
+	require.True(t, ok)
+
+	require.Len(t, response.Txs, 1)
+	assert.NotEmpty(t, response.Cursor)
+
+	var decodedTxResult types.TxResult
+
+	require.NoError(t, amino.Unmarshal(response.Txs[0], &decodedTxResult))
+	assert.Equal(t, txs[1], &decodedTxResult)
+}
@@ -1,11 +1,101 @@
 package tx
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	"github.com/gnolang/tx-indexer/query"
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/storage"
+)
 
 type Storage interface {
+	// GetBlock returns specified block from permanent storage
+	GetBlock(uint64) (*types.Block, error)
+
 	// GetTx returns specified tx from permanent storage
 	GetTx(uint64, uint32) (*types.TxResult, error)
 
+	// GetLatestHeight returns the latest block height from the storage
+	GetLatestHeight() (uint64, error)
+
 	// GetTxByHash fetches the tx using the transaction hash
 	GetTxByHash(txHash string) (*types.TxResult, error)
+
+	// TxByAddressIterator iterates over transactions the address participated in,
+	// limiting the results to be between the provided block numbers
+	TxByAddressIterator(address string, fromBlockNum, toBlockNum uint64) (storage.Iterator[*types.TxResult], error)
+
+	// ReverseTxByAddressIterator iterates over transactions the address participated in
+	// as TxByAddressIterator, newest first
+	ReverseTxByAddressIterator(
+		address string,
+		fromBlockNum,
+		toBlockNum uint64,
+	) (storage.Iterator[*types.TxResult], error)
+
+	// TxIterator iterates over transactions, limiting the results to be between the provided block numbers
+	// and transaction indexes
+	TxIterator(fromBlockNum, toBlockNum uint64, fromTxIndex, toTxIndex uint32) (storage.Iterator[*types.TxResult], error)
+
+	// ReverseTxIterator iterates over transactions as TxIterator, newest first
+	ReverseTxIterator(
+		fromBlockNum,
+		toBlockNum uint64,
+		fromTxIndex,
+		toTxIndex uint32,
+	) (storage.Iterator[*types.TxResult], error)
+}
+
+// Pagination is the transaction query pagination
+type Pagination struct {
+	// Cursor is the cursor returned with the previous page, if any
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of transactions in the page
+	Limit int `json:"limit"`
+
+	// Ordering is the ordering of the transactions, oldest first by default
+	query.Ordering
+}
+
+// TxPage is a single page of the (encoded) transactions matching the query
+type TxPage struct {
+	Txs []encode.Value `json:"txs"`
+
+	// Cursor is the cursor for fetching the next page, if any
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// TxProof is the Merkle proof of the transaction inclusion in its block,
+// verifiable against the block header data hash
+type TxProof struct {
+	// TxHash is the transaction hash (the proof leaf)
+	TxHash []byte `json:"txHash"`
+
+	// DataHash is the block header data hash (the proof root)
+	DataHash []byte `json:"dataHash"`
+
+	// Proof is the simple Merkle proof of the transaction hash
+	Proof *Proof `json:"proof"`
+
+	// Height is the block height
+	Height int64 `json:"height"`
+
+	// Index is the transaction index in the block
+	Index uint32 `json:"index"`
+}
+
+// Proof is the simple Merkle proof
+type Proof struct {
+	// LeafHash is the hash of the proof leaf
+	LeafHash []byte `json:"leafHash"`
+
+	// Aunts are the sibling hashes on the path to the root, from the leaf up
+	Aunts [][]byte `json:"aunts"`
+
+	// Total is the number of leaves (transactions in the block)
+	Total int `json:"total"`
+
+	// Index is the leaf index
+	Index int `json:"index"`
 }
@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/validators"
+)
+
+type (
+	getValidatorsDelegate       func(uint64) (*validators.Set, error)
+	getValidatorChangesDelegate func(uint64, uint64, int) ([]*validators.Change, error)
+	getSigningDelegate          func(uint64) (*validators.Signing, error)
+	getSigningStatsDelegate     func(string, uint64) (*validators.SigningStats, error)
+)
+
+type mockStorage struct {
+	getValidatorsFn       getValidatorsDelegate
+	getValidatorChangesFn getValidatorChangesDelegate
+	getSigningFn          getSigningDelegate
+	getSigningStatsFn     getSigningStatsDelegate
+}
+
+func (m *mockStorage) GetValidators(height uint64) (*validators.Set, error) {
+	if m.getValidatorsFn != nil {
+		return m.getValidatorsFn(height)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetValidatorChanges(fromHeight, toHeight uint64, limit int) ([]*validators.Change, error) {
+	if m.getValidatorChangesFn != nil {
+		return m.getValidatorChangesFn(fromHeight, toHeight, limit)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetSigning(height uint64) (*validators.Signing, error) {
+	if m.getSigningFn != nil {
+		return m.getSigningFn(height)
+	}
+
+	return nil, nil
+}
+
+func (m *mockStorage) GetSigningStats(address string, window uint64) (*validators.SigningStats, error) {
+	if m.getSigningStatsFn != nil {
+		return m.getSigningStatsFn(address, window)
+	}
+
+	return nil, nil
+}
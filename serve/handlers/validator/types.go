@@ -0,0 +1,19 @@
+package validator
+
+import (
+	"github.com/gnolang/tx-indexer/plugins/validators"
+)
+
+type Storage interface {
+	// GetValidators returns the validator set at the height, if indexed
+	GetValidators(height uint64) (*validators.Set, error)
+
+	// GetValidatorChanges returns the validator set changes in the [fromHeight, toHeight) range
+	GetValidatorChanges(fromHeight, toHeight uint64, limit int) ([]*validators.Change, error)
+
+	// GetSigning returns the signing record of the block at the height, if indexed
+	GetSigning(height uint64) (*validators.Signing, error)
+
+	// GetSigningStats returns the signing statistics of the validator, over the window of the latest blocks
+	GetSigningStats(address string, window uint64) (*validators.SigningStats, error)
+}
@@ -0,0 +1,176 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+const (
+	// maxChangesPerQuery is the maximum number of
+	// validator set changes returned in a single query
+	maxChangesPerQuery = 100
+
+	// defaultStatsWindow is the default number of latest
+	// blocks the validator signing statistics are computed over
+	defaultStatsWindow = 100
+
+	// maxStatsWindow is the maximum signing statistics window
+	maxStatsWindow = 10_000
+)
+
+type Handler struct {
+	storage Storage
+}
+
+func NewHandler(storage Storage) *Handler {
+	return &Handler{
+		storage: storage,
+	}
+}
+
+// GetValidatorsHandler returns the validator set at the height
+func (h *Handler) GetValidatorsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	height, err := toUint64(params[0])
+	if err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	// Run the handler
+	set, err := h.storage.GetValidators(height)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	if set == nil {
+		return nil, nil
+	}
+
+	return set, nil
+}
+
+// GetValidatorChangesHandler returns the validator set changes, oldest first
+func (h *Handler) GetValidatorChangesHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	fromHeight, err := toUint64(params[0])
+	if err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	var toHeight uint64
+
+	if len(params) > 1 {
+		toHeight, err = toUint64(params[1])
+		if err != nil {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+	}
+
+	// Run the handler
+	changes, err := h.storage.GetValidatorChanges(fromHeight, toHeight, maxChangesPerQuery)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return changes, nil
+}
+
+// GetBlockSigningHandler returns the proposer and the
+// signing validators of the block at the height
+func (h *Handler) GetBlockSigningHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	height, err := toUint64(params[0])
+	if err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	// Run the handler
+	signing, err := h.storage.GetSigning(height)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	if signing == nil {
+		return nil, nil
+	}
+
+	return signing, nil
+}
+
+// GetValidatorStatsHandler returns the signing statistics
+// of the validator, over the window of the latest blocks
+func (h *Handler) GetValidatorStatsHandler(
+	_ *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	address, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if _, err := crypto.AddressFromBech32(address); err != nil {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	window := uint64(defaultStatsWindow)
+
+	if len(params) > 1 {
+		var err error
+
+		window, err = toUint64(params[1])
+		if err != nil || window == 0 {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		if window > maxStatsWindow {
+			return nil, spec.GenerateOutOfRangeParamError(2, maxStatsWindow)
+		}
+	}
+
+	// Run the handler
+	stats, err := h.storage.GetSigningStats(address, window)
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return stats, nil
+}
+
+func toUint64(data any) (uint64, error) {
+	return strconv.ParseUint(fmt.Sprintf("%v", data), 10, 64)
+}
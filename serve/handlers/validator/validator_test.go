@@ -0,0 +1,456 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/plugins/validators"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+func TestGetValidators_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid height",
+			[]any{"height"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetValidatorsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetValidators_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getValidatorsFn: func(_ uint64) (*validators.Set, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetValidatorsHandler(nil, []any{10})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("height not indexed", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{})
+
+		response, err := h.GetValidatorsHandler(nil, []any{10})
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("validator set found", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			set = &validators.Set{
+				Validators: []*validators.Validator{
+					{
+						Address:     "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5",
+						VotingPower: 10,
+					},
+				},
+				Height:           5,
+				TotalVotingPower: 10,
+			}
+
+			mockStorage = &mockStorage{
+				getValidatorsFn: func(height uint64) (*validators.Set, error) {
+					require.Equal(t, uint64(10), height)
+
+					return set, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetValidatorsHandler(nil, []any{"10"})
+		require.Nil(t, err)
+
+		assert.Equal(t, set, response)
+	})
+}
+
+func TestGetValidatorChanges_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid from height",
+			[]any{"from"},
+		},
+		{
+			"invalid to height",
+			[]any{1, "to"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetValidatorChangesHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetValidatorChanges_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getValidatorChangesFn: func(_, _ uint64, _ int) ([]*validators.Change, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetValidatorChangesHandler(nil, []any{0})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("changes in range", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			changes = []*validators.Change{
+				{
+					Height: 5,
+				},
+			}
+
+			mockStorage = &mockStorage{
+				getValidatorChangesFn: func(from, to uint64, limit int) ([]*validators.Change, error) {
+					require.Equal(t, uint64(1), from)
+					require.Equal(t, uint64(100), to)
+					require.Equal(t, maxChangesPerQuery, limit)
+
+					return changes, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetValidatorChangesHandler(nil, []any{1, "100"})
+		require.Nil(t, err)
+
+		assert.Equal(t, changes, response)
+	})
+}
+
+func TestGetBlockSigning_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid height",
+			[]any{"height"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetBlockSigningHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetBlockSigning_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getSigningFn: func(_ uint64) (*validators.Signing, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetBlockSigningHandler(nil, []any{10})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("block not indexed", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockStorage{})
+
+		response, err := h.GetBlockSigningHandler(nil, []any{10})
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("block signing found", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			signing = &validators.Signing{
+				Proposer: "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5",
+				Signed:   []string{"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5"},
+				Missed:   []string{},
+				Height:   10,
+			}
+
+			mockStorage = &mockStorage{
+				getSigningFn: func(height uint64) (*validators.Signing, error) {
+					require.Equal(t, uint64(10), height)
+
+					return signing, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetBlockSigningHandler(nil, []any{10})
+		require.Nil(t, err)
+
+		assert.Equal(t, signing, response)
+	})
+}
+
+func TestGetValidatorStats_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid address type",
+			[]any{10},
+		},
+		{
+			"invalid address",
+			[]any{"address"},
+		},
+		{
+			"invalid window",
+			[]any{crypto.Address{1}.String(), "window"},
+		},
+		{
+			"zero window",
+			[]any{crypto.Address{1}.String(), 0},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockStorage{})
+
+			response, err := h.GetValidatorStatsHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestGetValidatorStats_OutOfRangeParams(t *testing.T) {
+	t.Parallel()
+
+	h := NewHandler(&mockStorage{})
+
+	response, err := h.GetValidatorStatsHandler(nil, []any{crypto.Address{1}.String(), maxStatsWindow + 1})
+	assert.Nil(t, response)
+
+	assert.Equal(t, spec.GenerateOutOfRangeParamError(2, maxStatsWindow), err)
+}
+
+func TestGetValidatorStats_Handler(t *testing.T) {
+	t.Parallel()
+
+	address := crypto.Address{1}.String()
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			storageErr = errors.New("random error")
+
+			mockStorage = &mockStorage{
+				getSigningStatsFn: func(_ string, _ uint64) (*validators.SigningStats, error) {
+					return nil, storageErr
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetValidatorStatsHandler(nil, []any{address})
+		assert.Nil(t, response)
+
+		// Make sure the error is populated
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.ServerErrorCode, err.Code)
+		assert.Contains(t, err.Message, storageErr.Error())
+	})
+
+	t.Run("default window", func(t *testing.T) {
+		t.Parallel()
+
+		mockStorage := &mockStorage{
+			getSigningStatsFn: func(_ string, window uint64) (*validators.SigningStats, error) {
+				require.Equal(t, uint64(defaultStatsWindow), window)
+
+				return &validators.SigningStats{}, nil
+			},
+		}
+
+		h := NewHandler(mockStorage)
+
+		_, err := h.GetValidatorStatsHandler(nil, []any{address})
+		require.Nil(t, err)
+	})
+
+	t.Run("stats in window", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			stats = &validators.SigningStats{
+				Address:    address,
+				Proposed:   2,
+				Signed:     9,
+				Missed:     1,
+				Uptime:     0.9,
+				FromHeight: 91,
+				ToHeight:   100,
+			}
+
+			mockStorage = &mockStorage{
+				getSigningStatsFn: func(a string, window uint64) (*validators.SigningStats, error) {
+					require.Equal(t, address, a)
+					require.Equal(t, uint64(10), window)
+
+					return stats, nil
+				},
+			}
+		)
+
+		h := NewHandler(mockStorage)
+
+		response, err := h.GetValidatorStatsHandler(nil, []any{address, "10"})
+		require.Nil(t, err)
+
+		assert.Equal(t, stats, response)
+	})
+}
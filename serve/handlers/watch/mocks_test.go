@@ -0,0 +1,46 @@
+package watch
+
+import (
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/conns"
+)
+
+type (
+	addDelegate    func(string, []string, notify.Notifier) (string, error)
+	removeDelegate func(string, string) bool
+)
+
+type mockWatchlist struct {
+	addFn    addDelegate
+	removeFn removeDelegate
+}
+
+func (m *mockWatchlist) Add(owner string, addresses []string, notifier notify.Notifier) (string, error) {
+	if m.addFn != nil {
+		return m.addFn(owner, addresses, notifier)
+	}
+
+	return "", nil
+}
+
+func (m *mockWatchlist) Remove(owner, id string) bool {
+	if m.removeFn != nil {
+		return m.removeFn(owner, id)
+	}
+
+	return false
+}
+
+type getWSConnectionDelegate func(string) conns.WSConnection
+
+type mockConnectionFetcher struct {
+	getWSConnectionFn getWSConnectionDelegate
+}
+
+func (m *mockConnectionFetcher) GetWSConnection(id string) conns.WSConnection {
+	if m.getWSConnectionFn != nil {
+		return m.getWSConnectionFn(id)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/conns"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// wsNotifier delivers the notifications to the WS connection,
+// as subscription messages for the watch
+type wsNotifier struct {
+	conn conns.WSConnection
+}
+
+func (n *wsNotifier) Notify(_ context.Context, notification *notify.Notification) error {
+	if err := n.conn.WriteData(spec.NewJSONSubscribeResponse(notification.Source, notification)); err != nil {
+		return fmt.Errorf("%w, %w", notify.ErrClosed, err)
+	}
+
+	return nil
+}
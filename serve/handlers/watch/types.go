@@ -0,0 +1,22 @@
+package watch
+
+import (
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/conns"
+)
+
+// Watchlist is the watched address registry abstraction
+type Watchlist interface {
+	// Add starts watching the addresses for the owner, returning the watch ID
+	Add(owner string, addresses []string, notifier notify.Notifier) (string, error)
+
+	// Remove stops the watch of the owner with the ID
+	Remove(owner, id string) bool
+}
+
+// ConnectionFetcher is the WS connection manager abstraction
+type ConnectionFetcher interface {
+	// GetWSConnection returns the requested WS connection
+	// using the provided ID
+	GetWSConnection(string) conns.WSConnection
+}
@@ -0,0 +1,212 @@
+package watch
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/watchlist"
+)
+
+// maxWatchedAddresses is the maximum number of
+// addresses watched by a single watch
+const maxWatchedAddresses = 100
+
+// ConnOwner returns the watchlist owner of the watches delivered
+// over the WS connection, removed once the connection is closed
+func ConnOwner(connID string) string {
+	return "conn:" + connID
+}
+
+// callerOwner returns the watchlist owner of the webhook watches of the caller,
+// its API key, or its remote host without one
+func callerOwner(metadata *metadata.Metadata) string {
+	if metadata.APIKey != "" {
+		return "key:" + metadata.APIKey
+	}
+
+	host, _, err := net.SplitHostPort(metadata.RemoteAddr)
+	if err != nil {
+		host = metadata.RemoteAddr
+	}
+
+	return "host:" + host
+}
+
+type Handler struct {
+	watchlist   Watchlist
+	connFetcher ConnectionFetcher
+
+	// webhooks is a flag indicating if webhook watches are allowed
+	webhooks bool
+}
+
+func NewHandler(watchlist Watchlist, conns ConnectionFetcher, webhooks bool) *Handler {
+	return &Handler{
+		watchlist:   watchlist,
+		connFetcher: conns,
+		webhooks:    webhooks,
+	}
+}
+
+// WatchAddressesHandler starts watching the addresses, notifying the webhook (if set),
+// or the WS connection, whenever a watched address appears in a new transaction.
+// The webhook watches are capped per API key (or remote host), and the WS ones per connection
+func (h *Handler) WatchAddressesHandler(
+	metadata *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) < 1 || len(params) > 2 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	addresses, ok := parseAddresses(params[0])
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if len(params) > 1 {
+		webhookURL, ok := params[1].(string)
+		if !ok || !isWebhookURL(webhookURL) {
+			return nil, spec.GenerateInvalidParamError(2)
+		}
+
+		if !h.webhooks {
+			return nil, spec.NewJSONError(
+				"Webhooks are disabled",
+				spec.InvalidRequestErrorCode,
+			)
+		}
+
+		// The webhook host is checked again once resolved,
+		// so the names of the non-public addresses are rejected as well
+		return h.addWatch(
+			callerOwner(metadata),
+			addresses,
+			notify.NewWebhook(webhookURL, notify.WithPublicAddressesOnly()),
+		)
+	}
+
+	// Without a webhook, the notifications are delivered over WS
+	if !metadata.IsWS() {
+		return nil, spec.NewJSONError(
+			"Method only supported over WS, without a webhook",
+			spec.MethodNotSupportedErrorCode,
+		)
+	}
+
+	conn := h.connFetcher.GetWSConnection(*metadata.WebSocketID)
+	if conn == nil {
+		return nil, spec.NewJSONError(
+			"WS connection not found",
+			spec.ServerErrorCode,
+		)
+	}
+
+	return h.addWatch(ConnOwner(*metadata.WebSocketID), addresses, &wsNotifier{conn: conn})
+}
+
+// addWatch adds the watch of the owner to the watchlist, returning the watch ID
+func (h *Handler) addWatch(owner string, addresses []string, notifier notify.Notifier) (any, *spec.BaseJSONError) {
+	id, err := h.watchlist.Add(owner, addresses, notifier)
+	if errors.Is(err, watchlist.ErrTooManyOwnerWatches) {
+		return nil, spec.NewJSONError(
+			"Too many watches of the connection (or API key)",
+			spec.ServerErrorCode,
+		)
+	}
+
+	if errors.Is(err, watchlist.ErrTooManyWatches) {
+		return nil, spec.NewJSONError(
+			"Too many watches",
+			spec.ServerErrorCode,
+		)
+	}
+
+	if err != nil {
+		return nil, spec.GenerateResponseError(err)
+	}
+
+	return id, nil
+}
+
+// UnwatchAddressesHandler stops the watch with the given ID,
+// if it was added by the caller (over the same WS connection, for the WS watches)
+func (h *Handler) UnwatchAddressesHandler(
+	metadata *metadata.Metadata,
+	params []any,
+) (any, *spec.BaseJSONError) {
+	// Check the params
+	if len(params) != 1 {
+		return nil, spec.GenerateInvalidParamCountError()
+	}
+
+	// Extract the params
+	id, ok := params[0].(string)
+	if !ok {
+		return nil, spec.GenerateInvalidParamError(1)
+	}
+
+	if metadata.IsWS() && h.watchlist.Remove(ConnOwner(*metadata.WebSocketID), id) {
+		return true, nil
+	}
+
+	return h.watchlist.Remove(callerOwner(metadata), id), nil
+}
+
+// parseAddresses parses the list of (1 to maxWatchedAddresses) bech32 addresses
+func parseAddresses(param any) ([]string, bool) {
+	list, ok := param.([]any)
+	if !ok || len(list) == 0 || len(list) > maxWatchedAddresses {
+		return nil, false
+	}
+
+	addresses := make([]string, 0, len(list))
+
+	for _, item := range list {
+		address, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+
+		if _, err := crypto.AddressFromBech32(address); err != nil {
+			return nil, false
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, true
+}
+
+// isWebhookURL returns a flag indicating if the URL is a valid HTTP(S) webhook URL,
+// whose host is not localhost, or a loopback, link-local or private address
+func isWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return notify.IsPublicAddress(ip)
+	}
+
+	return true
+}
@@ -0,0 +1,293 @@
+package watch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/notify"
+	"github.com/gnolang/tx-indexer/serve/conns"
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/watchlist"
+)
+
+func TestWatchAddresses_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	address := crypto.Address{1}.String()
+
+	testTable := []struct {
+		name   string
+		params []any
+	}{
+		{
+			"invalid param length",
+			[]any{},
+		},
+		{
+			"invalid address list",
+			[]any{address},
+		},
+		{
+			"empty address list",
+			[]any{[]any{}},
+		},
+		{
+			"invalid address",
+			[]any{[]any{"invalid"}},
+		},
+		{
+			"invalid webhook",
+			[]any{[]any{address}, "ftp://example.com"},
+		},
+		{
+			"localhost webhook",
+			[]any{[]any{address}, "http://localhost:8080/hook"},
+		},
+		{
+			"loopback webhook",
+			[]any{[]any{address}, "http://127.0.0.1/hook"},
+		},
+		{
+			"link-local webhook",
+			[]any{[]any{address}, "http://169.254.169.254/latest"},
+		},
+		{
+			"private webhook",
+			[]any{[]any{address}, "https://[fd00::1]/hook"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(&mockWatchlist{}, &mockConnectionFetcher{}, true)
+
+			response, err := h.WatchAddressesHandler(nil, testCase.params)
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+		})
+	}
+}
+
+func TestWatchAddresses_Handler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		address    = crypto.Address{1}.String()
+		webhookURL = "https://example.com/hook"
+	)
+
+	t.Run("webhooks disabled", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockWatchlist{}, &mockConnectionFetcher{}, false)
+
+		response, err := h.WatchAddressesHandler(nil, []any{[]any{address}, webhookURL})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidRequestErrorCode, err.Code)
+	})
+
+	t.Run("webhook watch", func(t *testing.T) {
+		t.Parallel()
+
+		mockWatchlist := &mockWatchlist{
+			addFn: func(owner string, addresses []string, notifier notify.Notifier) (string, error) {
+				require.Equal(t, "host:1.2.3.4", owner)
+				require.Equal(t, []string{address}, addresses)
+				require.IsType(t, &notify.Webhook{}, notifier)
+
+				return "id", nil
+			},
+		}
+
+		h := NewHandler(mockWatchlist, &mockConnectionFetcher{}, true)
+
+		response, err := h.WatchAddressesHandler(metadata.NewMetadata("1.2.3.4:5678"), []any{[]any{address}, webhookURL})
+		require.Nil(t, err)
+
+		assert.Equal(t, "id", response)
+	})
+
+	t.Run("API key webhook watch", func(t *testing.T) {
+		t.Parallel()
+
+		mockWatchlist := &mockWatchlist{
+			addFn: func(owner string, _ []string, _ notify.Notifier) (string, error) {
+				require.Equal(t, "key:explorer", owner)
+
+				return "id", nil
+			},
+		}
+
+		h := NewHandler(mockWatchlist, &mockConnectionFetcher{}, true)
+
+		response, err := h.WatchAddressesHandler(
+			metadata.NewMetadata("1.2.3.4:5678", metadata.WithAPIKey("explorer")),
+			[]any{[]any{address}, webhookURL},
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, "id", response)
+	})
+
+	t.Run("too many watches", func(t *testing.T) {
+		t.Parallel()
+
+		for _, limitErr := range []error{watchlist.ErrTooManyWatches, watchlist.ErrTooManyOwnerWatches} {
+			mockWatchlist := &mockWatchlist{
+				addFn: func(_ string, _ []string, _ notify.Notifier) (string, error) {
+					return "", limitErr
+				},
+			}
+
+			h := NewHandler(mockWatchlist, &mockConnectionFetcher{}, true)
+
+			response, err := h.WatchAddressesHandler(metadata.NewMetadata(""), []any{[]any{address}, webhookURL})
+			assert.Nil(t, response)
+
+			require.NotNil(t, err)
+
+			assert.Equal(t, spec.ServerErrorCode, err.Code)
+		}
+	})
+
+	t.Run("WS watch over HTTP", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockWatchlist{}, &mockConnectionFetcher{}, true)
+
+		response, err := h.WatchAddressesHandler(metadata.NewMetadata(""), []any{[]any{address}})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.MethodNotSupportedErrorCode, err.Code)
+	})
+
+	t.Run("WS watch", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			wsID          = "ws-id"
+			capturedWrite any
+
+			notification = &notify.Notification{
+				Source: "id",
+				Topic:  "watchlist",
+			}
+
+			mockConn = &mock.Conn{
+				WriteDataFn: func(data any) error {
+					capturedWrite = data
+
+					return nil
+				},
+			}
+
+			mockConns = &mockConnectionFetcher{
+				getWSConnectionFn: func(id string) conns.WSConnection {
+					require.Equal(t, wsID, id)
+
+					return mockConn
+				},
+			}
+
+			mockWatchlist = &mockWatchlist{
+				addFn: func(owner string, _ []string, notifier notify.Notifier) (string, error) {
+					require.Equal(t, ConnOwner(wsID), owner)
+					require.NoError(t, notifier.Notify(context.Background(), notification))
+
+					return "id", nil
+				},
+			}
+		)
+
+		h := NewHandler(mockWatchlist, mockConns, false)
+
+		response, err := h.WatchAddressesHandler(
+			metadata.NewMetadata("", metadata.WithWebSocketID(wsID)),
+			[]any{[]any{address}},
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, "id", response)
+
+		// Make sure the notification is written as a subscription message
+		assert.Equal(t, spec.NewJSONSubscribeResponse("id", notification), capturedWrite)
+	})
+}
+
+func TestUnwatchAddresses_Handler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid params", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHandler(&mockWatchlist{}, &mockConnectionFetcher{}, false)
+
+		response, err := h.UnwatchAddressesHandler(nil, []any{10})
+		assert.Nil(t, response)
+
+		require.NotNil(t, err)
+
+		assert.Equal(t, spec.InvalidParamsErrorCode, err.Code)
+	})
+
+	t.Run("watch removed", func(t *testing.T) {
+		t.Parallel()
+
+		mockWatchlist := &mockWatchlist{
+			removeFn: func(owner, id string) bool {
+				return owner == "host:1.2.3.4" && id == "id"
+			},
+		}
+
+		h := NewHandler(mockWatchlist, &mockConnectionFetcher{}, false)
+
+		response, err := h.UnwatchAddressesHandler(metadata.NewMetadata("1.2.3.4:5678"), []any{"id"})
+		require.Nil(t, err)
+
+		assert.Equal(t, true, response)
+
+		// Make sure the watches of the other callers are not removed
+		response, err = h.UnwatchAddressesHandler(metadata.NewMetadata("5.6.7.8:5678"), []any{"id"})
+		require.Nil(t, err)
+
+		assert.Equal(t, false, response)
+	})
+
+	t.Run("WS watch removed", func(t *testing.T) {
+		t.Parallel()
+
+		mockWatchlist := &mockWatchlist{
+			removeFn: func(owner, id string) bool {
+				return owner == ConnOwner("ws-id") && id == "id"
+			},
+		}
+
+		h := NewHandler(mockWatchlist, &mockConnectionFetcher{}, false)
+
+		response, err := h.UnwatchAddressesHandler(
+			metadata.NewMetadata("1.2.3.4:5678", metadata.WithWebSocketID("ws-id")),
+			[]any{"id"},
+		)
+		require.Nil(t, err)
+
+		assert.Equal(t, true, response)
+	})
+}
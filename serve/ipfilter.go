@@ -0,0 +1,207 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// The headers carrying the client IP, set by the proxies
+const (
+	headerForwardedFor = "X-Forwarded-For"
+	headerRealIP       = "X-Real-IP"
+	headerTrueClientIP = "True-Client-IP"
+)
+
+// IPFilter restricts the served requests to the client IPs allowed by the CIDR lists.
+// The client IP is read from the forwarding headers only if the request comes from a trusted proxy
+type IPFilter struct {
+	allow          []netip.Prefix
+	deny           []netip.Prefix
+	trustedProxies []netip.Prefix
+}
+
+// NewIPFilter creates a new IP filter from the CIDR (or single IP) lists. The denied IPs are
+// always rejected, while an empty allowlist allows all the other IPs
+func NewIPFilter(allow, deny, trustedProxies []string) (*IPFilter, error) {
+	var (
+		f   = &IPFilter{}
+		err error
+	)
+
+	if f.allow, err = parsePrefixes(allow); err != nil {
+		return nil, fmt.Errorf("invalid allowlist, %w", err)
+	}
+
+	if f.deny, err = parsePrefixes(deny); err != nil {
+		return nil, fmt.Errorf("invalid denylist, %w", err)
+	}
+
+	if f.trustedProxies, err = parsePrefixes(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies, %w", err)
+	}
+
+	return f, nil
+}
+
+// parsePrefixes parses the CIDRs, with the single IPs as full length prefixes
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP %q", entry)
+			}
+
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", entry)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// contains checks if any of the prefixes contains the IP
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allowed checks if the client IP is allowed
+func (f *IPFilter) Allowed(ip netip.Addr) bool {
+	if contains(f.deny, ip) {
+		return false
+	}
+
+	return len(f.allow) == 0 || contains(f.allow, ip)
+}
+
+// ClientIP returns the client IP of the request. For the requests of the trusted proxies,
+// it's the last X-Forwarded-For IP that isn't a trusted proxy (or the X-Real-IP one).
+// An invalid IP is returned if the request remote address isn't an IP
+func (f *IPFilter) ClientIP(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	remote, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	remote = remote.Unmap()
+
+	if !contains(f.trustedProxies, remote) {
+		return remote
+	}
+
+	var (
+		forwarded = strings.Split(r.Header.Get(headerForwardedFor), ",")
+		client    = netip.Addr{}
+	)
+
+	// The proxies append the IPs they forward for, so the
+	// rightmost untrusted IP is the one seen by the trusted proxies
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+
+		client = ip.Unmap()
+
+		if !contains(f.trustedProxies, client) {
+			return client
+		}
+	}
+
+	if client.IsValid() {
+		return client
+	}
+
+	if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(headerRealIP))); err == nil {
+		return ip.Unmap()
+	}
+
+	return remote
+}
+
+// requestIP returns the client IP of the request, from the X-Real-IP header if trustProxies is set
+// (as the header is then resolved by the IP filter from the trusted proxies), or the remote address.
+// The client headers are never trusted otherwise, so they can't be spoofed to evade the per IP limits
+func requestIP(remoteAddr string, header http.Header, trustProxies bool) string {
+	if trustProxies && header != nil {
+		if ip := header.Get(headerRealIP); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+
+	return host
+}
+
+// Middleware rejects the requests of the client IPs not allowed, with the forbidden
+// JSON-RPC error. The forwarding headers of the allowed requests are replaced with the resolved
+// client IP, so the rate limits and WS connection limits apply to it, and can't be spoofed
+func (f *IPFilter) Middleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := f.ClientIP(r)
+
+			if !ip.IsValid() || !f.Allowed(ip) {
+				logger.Debug(
+					"rejected request",
+					zap.String("remote", r.RemoteAddr),
+					zap.String("client", ip.String()),
+				)
+
+				w.Header().Set("Content-Type", jsonMimeType)
+				w.WriteHeader(http.StatusForbidden)
+
+				response := spec.NewJSONResponse(0, nil, spec.GenerateForbiddenError())
+
+				if err := json.NewEncoder(w).Encode(response); err != nil {
+					logger.Debug("unable to write forbidden response", zap.Error(err))
+				}
+
+				return
+			}
+
+			r.Header.Del(headerForwardedFor)
+			r.Header.Del(headerTrueClientIP)
+			r.Header.Set(headerRealIP, ip.String())
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
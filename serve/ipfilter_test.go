@@ -0,0 +1,214 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/go-chi/httprate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewIPFilter_Invalid(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name           string
+		allow          []string
+		deny           []string
+		trustedProxies []string
+	}{
+		{
+			"invalid allowlist IP",
+			[]string{"10.0.0"},
+			nil,
+			nil,
+		},
+		{
+			"invalid denylist CIDR",
+			nil,
+			[]string{"10.0.0.0/33"},
+			nil,
+		},
+		{
+			"invalid trusted proxy",
+			nil,
+			nil,
+			[]string{"proxy"},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewIPFilter(testCase.allow, testCase.deny, testCase.trustedProxies)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIPFilter_Allowed(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewIPFilter(
+		[]string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"},
+		[]string{"10.0.5.0/24"},
+		nil,
+	)
+	require.NoError(t, err)
+
+	testTable := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"10.1.2.3", true},
+		{"192.168.1.10", true},
+		{"2001:db8::1", true},
+		{"10.0.5.7", false},
+		{"192.168.1.11", false},
+		{"8.8.8.8", false},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.ip, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.allowed, f.Allowed(netip.MustParseAddr(testCase.ip)))
+		})
+	}
+
+	t.Run("empty allowlist", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := NewIPFilter(nil, []string{"8.8.8.8"}, nil)
+		require.NoError(t, err)
+
+		assert.True(t, f.Allowed(netip.MustParseAddr("1.1.1.1")))
+		assert.False(t, f.Allowed(netip.MustParseAddr("8.8.8.8")))
+	})
+}
+
+func TestIPFilter_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewIPFilter(nil, nil, []string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		expected   string
+	}{
+		{
+			"direct request",
+			"1.1.1.1:1234",
+			"",
+			"",
+			"1.1.1.1",
+		},
+		{
+			"spoofed untrusted headers",
+			"1.1.1.1:1234",
+			"2.2.2.2",
+			"3.3.3.3",
+			"1.1.1.1",
+		},
+		{
+			"trusted proxy",
+			"10.0.0.1:1234",
+			"2.2.2.2",
+			"",
+			"2.2.2.2",
+		},
+		{
+			"trusted proxy chain",
+			"10.0.0.1:1234",
+			"5.5.5.5, 2.2.2.2, 10.0.0.2",
+			"",
+			"2.2.2.2",
+		},
+		{
+			"trusted proxy real IP",
+			"10.0.0.1:1234",
+			"",
+			"2.2.2.2",
+			"2.2.2.2",
+		},
+		{
+			"trusted proxy without headers",
+			"10.0.0.1:1234",
+			"",
+			"",
+			"10.0.0.1",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.RemoteAddr = testCase.remoteAddr
+
+			if testCase.forwarded != "" {
+				r.Header.Set(headerForwardedFor, testCase.forwarded)
+			}
+
+			if testCase.realIP != "" {
+				r.Header.Set(headerRealIP, testCase.realIP)
+			}
+
+			assert.Equal(t, testCase.expected, f.ClientIP(r).String())
+		})
+	}
+}
+
+func TestIPFilter_Middleware(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewIPFilter([]string{"2.2.2.2"}, nil, []string{"10.0.0.1"})
+	require.NoError(t, err)
+
+	var clientIP string
+
+	handler := f.Middleware(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//nolint:errcheck // the real IP key func never errors
+		clientIP, _ = httprate.KeyByRealIP(r)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Make sure the allowed client is served, with the resolved IP
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set(headerForwardedFor, "9.9.9.9, 2.2.2.2")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2.2.2.2", clientIP)
+
+	// Make sure the other clients are rejected
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.RemoteAddr = "3.3.3.3:1234"
+	r.Header.Set(headerForwardedFor, "2.2.2.2")
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "forbidden")
+}
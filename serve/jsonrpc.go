@@ -3,31 +3,53 @@ package serve
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/olahol/melody"
 	"go.uber.org/zap"
 
+	"github.com/gnolang/tx-indexer/decode"
 	"github.com/gnolang/tx-indexer/serve/conns"
 	"github.com/gnolang/tx-indexer/serve/conns/wsconn"
 	"github.com/gnolang/tx-indexer/serve/filters"
+	"github.com/gnolang/tx-indexer/serve/handlers/account"
+	"github.com/gnolang/tx-indexer/serve/handlers/apikey"
+	"github.com/gnolang/tx-indexer/serve/handlers/auditlog"
 	"github.com/gnolang/tx-indexer/serve/handlers/block"
+	"github.com/gnolang/tx-indexer/serve/handlers/chain"
+	"github.com/gnolang/tx-indexer/serve/handlers/failure"
+	"github.com/gnolang/tx-indexer/serve/handlers/fee"
+	"github.com/gnolang/tx-indexer/serve/handlers/gas"
+	"github.com/gnolang/tx-indexer/serve/handlers/leaderboard"
+	"github.com/gnolang/tx-indexer/serve/handlers/realm"
+	"github.com/gnolang/tx-indexer/serve/handlers/realmstats"
+	"github.com/gnolang/tx-indexer/serve/handlers/search"
+	"github.com/gnolang/tx-indexer/serve/handlers/series"
+	"github.com/gnolang/tx-indexer/serve/handlers/signer"
+	"github.com/gnolang/tx-indexer/serve/handlers/status"
 	"github.com/gnolang/tx-indexer/serve/handlers/subs"
+	"github.com/gnolang/tx-indexer/serve/handlers/token"
 	"github.com/gnolang/tx-indexer/serve/handlers/tx"
+	"github.com/gnolang/tx-indexer/serve/handlers/validator"
+	"github.com/gnolang/tx-indexer/serve/handlers/watch"
 	"github.com/gnolang/tx-indexer/serve/metadata"
 	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/serve/wire"
 	"github.com/gnolang/tx-indexer/serve/writer"
 	httpWriter "github.com/gnolang/tx-indexer/serve/writer/http"
-	wsWriter "github.com/gnolang/tx-indexer/serve/writer/ws"
 	"github.com/gnolang/tx-indexer/storage"
+	"github.com/gnolang/tx-indexer/watchlist"
 )
 
 const (
-	jsonMimeType       = "application/json" // Only JSON is supported
+	jsonMimeType       = "application/json" // Only JSON requests are supported
 	maxRequestBodySize = 1 << 20            // 1MB
 	wsIDKey            = "ws-id"            // key used for WS connection metadata
 )
@@ -54,26 +76,72 @@ type JSONRPC struct {
 
 	logger *zap.Logger
 
+	// storage is the storage exposed to request handlers, if any
+	storage storage.Reader
+
+	// decoder decodes the chain txs, for the query filters and the watchlist
+	decoder *decode.Decoder
+
 	// handlers are the registered method handlers
 	handlers handlers
 
+	// middlewares wrap the method handlers, in order
+	middlewares []Middleware
+
+	// filterManager keeps track of active filters and subscriptions,
+	// if the sub endpoints are registered
+	filterManager *filters.Manager
+
+	// watchlist keeps track of the watched addresses,
+	// if the watchlist endpoints are registered
+	watchlist *watchlist.Watchlist
+
+	// subscriptionLimiter caps the active subscriptions, if set
+	subscriptionLimiter *filters.SubscriptionLimiter
+
 	// ws handles incoming and active WS connections
 	ws *melody.Melody
+
+	// wsLimiter caps the open WS connections, if set
+	wsLimiter *WSConnLimiter
+
+	wsPingPeriod  time.Duration
+	wsIdleTimeout time.Duration
 }
 
 // NewJSONRPC creates a new instance of the JSONRPC server
 func NewJSONRPC(events Events, opts ...Option) *JSONRPC {
 	j := &JSONRPC{
-		logger:   zap.NewNop(),
-		handlers: newHandlers(),
-		ws:       melody.New(),
-		events:   events,
+		logger:        zap.NewNop(),
+		handlers:      newHandlers(),
+		ws:            melody.New(),
+		events:        events,
+		wsPingPeriod:  DefaultWSPingPeriod,
+		wsIdleTimeout: DefaultWSIdleTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(j)
 	}
 
+	// Set up the WS heartbeat. The pings need to be sent
+	// before the idle timeout, for the live clients to be kept
+	if j.wsPingPeriod >= j.wsIdleTimeout {
+		j.wsPingPeriod = j.wsIdleTimeout * 9 / 10
+	}
+
+	j.ws.Config.PingPeriod = j.wsPingPeriod
+	j.ws.Config.PongWait = j.wsIdleTimeout
+
+	// Set up the default middlewares, as the outermost ones
+	j.middlewares = append(
+		[]Middleware{
+			RecoveryMiddleware(j.logger),
+			LoggingMiddleware(j.logger),
+		},
+		j.middlewares...,
+	)
+
 	// Set up the WS connection manager
 	j.wsConns = wsconn.NewConns(j.logger)
 
@@ -101,12 +169,33 @@ func (j *JSONRPC) SetupRoutes(mux *chi.Mux) *chi.Mux {
 	return mux
 }
 
+// Use adds the method middlewares, applied to both HTTP and WS requests.
+// Middlewares should be added before the server starts handling requests
+func (j *JSONRPC) Use(middlewares ...Middleware) {
+	j.middlewares = append(j.middlewares, middlewares...)
+}
+
 // RegisterHandler registers a new method handler,
 // overwriting existing ones, if any
 func (j *JSONRPC) RegisterHandler(method string, handler Handler) {
 	j.handlers.addHandler(method, handler)
 }
 
+// RegisterRequestHandler registers a new method handler that has access
+// to the request context, params decoding and the storage, overwriting existing ones, if any
+func (j *JSONRPC) RegisterRequestHandler(method string, handler RequestHandler) {
+	j.RegisterHandler(
+		method,
+		func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			return handler(&Request{
+				Metadata: metadata,
+				Storage:  j.storage,
+				Params:   params,
+			})
+		},
+	)
+}
+
 // UnregisterHandler removes the method handler for the specified method, if any
 func (j *JSONRPC) UnregisterHandler(method string) {
 	j.handlers.removeHandler(method)
@@ -114,7 +203,7 @@ func (j *JSONRPC) UnregisterHandler(method string) {
 
 // RegisterTxEndpoints registers the transaction endpoints
 func (j *JSONRPC) RegisterTxEndpoints(db tx.Storage) {
-	txHandler := tx.NewHandler(db)
+	txHandler := tx.NewHandler(db, j.decoder)
 
 	j.RegisterHandler(
 		"getTxResult",
@@ -125,6 +214,26 @@ func (j *JSONRPC) RegisterTxEndpoints(db tx.Storage) {
 		"getTxResultByHash",
 		txHandler.GetTxByHashHandler,
 	)
+
+	j.RegisterHandler(
+		"getTxsByHashes",
+		txHandler.GetTxsByHashesHandler,
+	)
+
+	j.RegisterHandler(
+		"getTxsByAddress",
+		txHandler.GetTxsByAddressHandler,
+	)
+
+	j.RegisterHandler(
+		"getTxProof",
+		txHandler.GetTxProofHandler,
+	)
+
+	j.RegisterHandler(
+		"queryTxs",
+		txHandler.QueryTxsHandler,
+	)
 }
 
 // RegisterBlockEndpoints registers the block endpoints
@@ -135,10 +244,313 @@ func (j *JSONRPC) RegisterBlockEndpoints(db block.Storage) {
 		"getBlock",
 		blockHandler.GetBlockHandler,
 	)
+
+	j.RegisterHandler(
+		"getBlockHeaders",
+		blockHandler.GetBlockHeadersHandler,
+	)
+}
+
+// RegisterStatusEndpoints registers the indexer status endpoints
+func (j *JSONRPC) RegisterStatusEndpoints(db status.Storage, client status.Client) {
+	statusHandler := status.NewHandler(db, client, j)
+
+	j.RegisterHandler(
+		"getStatus",
+		statusHandler.GetStatusHandler,
+	)
+}
+
+// RegisterTokenEndpoints registers the GRC20 token endpoints
+func (j *JSONRPC) RegisterTokenEndpoints(db token.Storage) {
+	tokenHandler := token.NewHandler(db)
+
+	j.RegisterHandler(
+		"getTokenTransfers",
+		tokenHandler.GetTokenTransfersHandler,
+	)
+
+	j.RegisterHandler(
+		"getTokenHolders",
+		tokenHandler.GetTokenHoldersHandler,
+	)
+}
+
+// RegisterAccountEndpoints registers the native coin account endpoints
+func (j *JSONRPC) RegisterAccountEndpoints(db account.Storage) {
+	accountHandler := account.NewHandler(db)
+
+	j.RegisterHandler(
+		"getAccountHistory",
+		accountHandler.GetAccountHistoryHandler,
+	)
+
+	j.RegisterHandler(
+		"getAccountBalanceAt",
+		accountHandler.GetAccountBalanceAtHandler,
+	)
+}
+
+// RegisterRealmEndpoints registers the realm event endpoints
+func (j *JSONRPC) RegisterRealmEndpoints(db realm.Storage) {
+	realmHandler := realm.NewHandler(db)
+
+	j.RegisterHandler(
+		"getEvents",
+		realmHandler.GetEventsHandler,
+	)
+}
+
+// RegisterGasEndpoints registers the gas usage endpoints
+func (j *JSONRPC) RegisterGasEndpoints(db gas.Storage) {
+	gasHandler := gas.NewHandler(db)
+
+	j.RegisterHandler(
+		"getGasStats",
+		gasHandler.GetGasStatsHandler,
+	)
+}
+
+// RegisterFeeEndpoints registers the transaction fee endpoints
+func (j *JSONRPC) RegisterFeeEndpoints(db fee.Storage) {
+	feeHandler := fee.NewHandler(db)
+
+	j.RegisterHandler(
+		"getFeeStats",
+		feeHandler.GetFeeStatsHandler,
+	)
+}
+
+// RegisterFailureEndpoints registers the failed transaction error endpoints
+func (j *JSONRPC) RegisterFailureEndpoints(db failure.Storage) {
+	failureHandler := failure.NewHandler(db)
+
+	j.RegisterHandler(
+		"getErrorStats",
+		failureHandler.GetErrorStatsHandler,
+	)
+
+	j.RegisterHandler(
+		"getTxsByError",
+		failureHandler.GetTxsByErrorHandler,
+	)
+}
+
+// RegisterChainEndpoints registers the chain metrics endpoints
+func (j *JSONRPC) RegisterChainEndpoints(db chain.Storage) {
+	chainHandler := chain.NewHandler(db)
+
+	j.RegisterHandler(
+		"getChainStats",
+		chainHandler.GetChainStatsHandler,
+	)
+}
+
+// RegisterLeaderboardEndpoints registers the account activity leaderboard endpoints
+func (j *JSONRPC) RegisterLeaderboardEndpoints(db leaderboard.Storage) {
+	leaderboardHandler := leaderboard.NewHandler(db)
+
+	j.RegisterHandler(
+		"getTopAccounts",
+		leaderboardHandler.GetTopAccountsHandler,
+	)
+}
+
+// RegisterRealmStatsEndpoints registers the realm activity statistics endpoints
+func (j *JSONRPC) RegisterRealmStatsEndpoints(db realmstats.Storage) {
+	realmStatsHandler := realmstats.NewHandler(db)
+
+	j.RegisterHandler(
+		"getRealmStats",
+		realmStatsHandler.GetRealmStatsHandler,
+	)
+
+	j.RegisterHandler(
+		"getTopRealms",
+		realmStatsHandler.GetTopRealmsHandler,
+	)
+}
+
+// RegisterSeriesEndpoints registers the time series aggregation endpoints
+func (j *JSONRPC) RegisterSeriesEndpoints(db series.Storage) {
+	seriesHandler := series.NewHandler(db)
+
+	j.RegisterHandler(
+		"getBlockCountSeries",
+		seriesHandler.GetBlockCountSeriesHandler,
+	)
+
+	j.RegisterHandler(
+		"getTxCountSeries",
+		seriesHandler.GetTxCountSeriesHandler,
+	)
+
+	j.RegisterHandler(
+		"getGasSeries",
+		seriesHandler.GetGasSeriesHandler,
+	)
+
+	j.RegisterHandler(
+		"getFeeSeries",
+		seriesHandler.GetFeeSeriesHandler,
+	)
+
+	j.RegisterHandler(
+		"getActiveAddressSeries",
+		seriesHandler.GetActiveAddressSeriesHandler,
+	)
+}
+
+// RegisterSearchEndpoints registers the full-text transaction search endpoints
+func (j *JSONRPC) RegisterSearchEndpoints(db search.Storage) {
+	searchHandler := search.NewHandler(db)
+
+	j.RegisterHandler(
+		"searchTxs",
+		searchHandler.SearchTxsHandler,
+	)
+}
+
+// RegisterSignerEndpoints registers the transaction signer endpoints
+func (j *JSONRPC) RegisterSignerEndpoints(db signer.Storage) {
+	signerHandler := signer.NewHandler(db)
+
+	j.RegisterHandler(
+		"getTxSigners",
+		signerHandler.GetTxSignersHandler,
+	)
+
+	j.RegisterHandler(
+		"getTxsBySigner",
+		signerHandler.GetTxsBySignerHandler,
+	)
+
+	j.RegisterHandler(
+		"getTxsByPubKey",
+		signerHandler.GetTxsByPubKeyHandler,
+	)
+}
+
+// RegisterValidatorEndpoints registers the validator set and signing endpoints
+func (j *JSONRPC) RegisterValidatorEndpoints(db validator.Storage) {
+	validatorHandler := validator.NewHandler(db)
+
+	j.RegisterHandler(
+		"getValidators",
+		validatorHandler.GetValidatorsHandler,
+	)
+
+	j.RegisterHandler(
+		"getValidatorChanges",
+		validatorHandler.GetValidatorChangesHandler,
+	)
+
+	j.RegisterHandler(
+		"getBlockSigning",
+		validatorHandler.GetBlockSigningHandler,
+	)
+
+	j.RegisterHandler(
+		"getValidatorStats",
+		validatorHandler.GetValidatorStatsHandler,
+	)
+}
+
+// RegisterWatchlistEndpoints registers the address watchlist endpoints.
+// Webhook watches are only allowed if the webhooks flag is set. The watches are kept in memory,
+// so they don't survive a restart, and the WS watches are removed once their connection is closed
+func (j *JSONRPC) RegisterWatchlistEndpoints(webhooks bool) {
+	j.watchlist = watchlist.New(
+		context.Background(),
+		j.events,
+		watchlist.WithLogger(j.logger.Named("watchlist")),
+		watchlist.WithDecoder(j.decoder),
+	)
+
+	watchHandler := watch.NewHandler(j.watchlist, j.wsConns, webhooks)
+
+	j.RegisterHandler(
+		"watchAddresses",
+		watchHandler.WatchAddressesHandler,
+	)
+
+	j.RegisterHandler(
+		"unwatchAddresses",
+		watchHandler.UnwatchAddressesHandler,
+	)
+}
+
+// RegisterAPIKeyEndpoints registers the API key admin endpoints,
+// requiring the admin token (see AdminMiddleware)
+func (j *JSONRPC) RegisterAPIKeyEndpoints(manager apikey.Manager) {
+	apiKeyHandler := apikey.NewHandler(manager)
+
+	j.RegisterHandler(
+		AdminMethodPrefix+"createApiKey",
+		apiKeyHandler.CreateAPIKeyHandler,
+	)
+
+	j.RegisterHandler(
+		AdminMethodPrefix+"setApiKeyQuota",
+		apiKeyHandler.SetAPIKeyQuotaHandler,
+	)
+
+	j.RegisterHandler(
+		AdminMethodPrefix+"deleteApiKey",
+		apiKeyHandler.DeleteAPIKeyHandler,
+	)
+
+	j.RegisterHandler(
+		AdminMethodPrefix+"getApiKeyUsage",
+		apiKeyHandler.GetAPIKeyUsageHandler,
+	)
+}
+
+// RegisterAuditEndpoints registers the audit log admin endpoints,
+// requiring the admin token (see AdminMiddleware)
+func (j *JSONRPC) RegisterAuditEndpoints(db auditlog.Storage) {
+	auditHandler := auditlog.NewHandler(db)
+
+	j.RegisterHandler(
+		AdminMethodPrefix+"getAuditLog",
+		auditHandler.GetAuditLogHandler,
+	)
+}
+
+// CloseWSConnections closes the active WS connections with a going away
+// close frame, for the clients to reconnect elsewhere. New WS connections
+// are refused once closed, so it's meant to be called on shutdown
+func (j *JSONRPC) CloseWSConnections() {
+	j.logger.Info(
+		"closing WS connections",
+		zap.Int("connections", j.ws.Len()),
+	)
+
+	if err := j.ws.CloseWithMsg(
+		melody.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+	); err != nil && !errors.Is(err, melody.ErrClosed) {
+		j.logger.Error("unable to close WS connections", zap.Error(err))
+	}
+}
+
+// NumSubscriptions returns the number of active WS subscriptions
+func (j *JSONRPC) NumSubscriptions() int {
+	if j.filterManager == nil {
+		return 0
+	}
+
+	return j.filterManager.NumSubscriptions()
 }
 
 func (j *JSONRPC) RegisterSubEndpoints(db storage.Storage) {
-	fm := filters.NewFilterManager(context.Background(), db, j.events)
+	filterOpts := make([]filters.Option, 0, 1)
+
+	if j.subscriptionLimiter != nil {
+		filterOpts = append(filterOpts, filters.WithSubscriptionLimiter(j.subscriptionLimiter))
+	}
+
+	fm := filters.NewFilterManager(context.Background(), db, j.events, filterOpts...)
+	j.filterManager = fm
 
 	subsHandler := subs.NewHandler(
 		fm,
@@ -206,6 +618,16 @@ func (j *JSONRPC) setupWSListeners() {
 
 		// Remove the WS connection
 		j.wsConns.RemoveWSConnection(wsConnID)
+
+		// Remove the WS connection subscriptions, if any
+		if j.filterManager != nil {
+			j.filterManager.UninstallConnSubscriptions(wsConnID)
+		}
+
+		// Remove the WS connection watches, if any
+		if j.watchlist != nil {
+			j.watchlist.RemoveOwner(watch.ConnOwner(wsConnID))
+		}
 	})
 
 	// Set up the core message method handler
@@ -221,15 +643,21 @@ func (j *JSONRPC) setupWSListeners() {
 		wsIDRaw, _ := s.Get(wsIDKey)
 		wsConnID := wsIDRaw.(string)
 
-		// Handle the request
-		j.handleRequest(
-			metadata.NewMetadata(
-				s.RemoteAddr().String(),
-				metadata.WithWebSocketID(wsConnID),
-			),
-			wsWriter.New(j.logger, s),
-			requests,
-		)
+		// Handle the request, ahead of the notifications
+		// written to the connection in the meantime
+		j.wsConns.HandleWSRequest(wsConnID, func(w writer.ResponseWriter) {
+			j.handleRequest(
+				metadata.NewMetadata(
+					s.RemoteAddr().String(),
+					metadata.WithWebSocketID(wsConnID),
+					metadata.WithContext(s.Request.Context()),
+					metadata.WithHeader(s.Request.Header),
+					metadata.WithAPIKey(apiKeyName(s.Request.Context())),
+				),
+				w,
+				requests,
+			)
+		})
 	})
 }
 
@@ -257,17 +685,37 @@ func (j *JSONRPC) handleHTTPRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Negotiate the response encoding
+	format := wire.Negotiate(r.Header.Get("Accept"))
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Add("Vary", "Accept")
+
 	// Handle the request
-	w.Header().Set("Content-Type", jsonMimeType)
 	j.handleRequest(
-		metadata.NewMetadata(r.RemoteAddr),
-		httpWriter.New(j.logger, w),
+		metadata.NewMetadata(
+			r.RemoteAddr,
+			metadata.WithContext(r.Context()),
+			metadata.WithHeader(r.Header),
+			metadata.WithAPIKey(apiKeyName(r.Context())),
+		),
+		httpWriter.New(j.logger, w, format),
 		requests,
 	)
 }
 
 // handleWSRequest handles incoming WS requests
 func (j *JSONRPC) handleWSRequest(w http.ResponseWriter, r *http.Request) {
+	if j.wsLimiter != nil {
+		release, ok := j.wsLimiter.admit(w, r, j.logger)
+		if !ok {
+			return
+		}
+
+		// The connection is held until the session ends
+		defer release()
+	}
+
 	if err := j.ws.HandleRequest(w, r); err != nil {
 		j.logger.Error(
 			"unable to initialize WS connection",
@@ -311,12 +759,6 @@ func (j *JSONRPC) handleRequest(
 		// Run the method methodHandler
 		handleResp, handleErr := j.route(metadata, baseRequest)
 		if handleErr != nil {
-			j.logger.Debug(
-				"unable to handle JSON-RPC request",
-				zap.Any("request", baseRequest),
-				zap.Any("error", handleErr),
-			)
-
 			responses[i] = spec.NewJSONResponse(
 				baseRequest.ID,
 				nil,
@@ -326,11 +768,6 @@ func (j *JSONRPC) handleRequest(
 			continue
 		}
 
-		j.logger.Debug(
-			"handled request",
-			zap.Any("request", baseRequest),
-		)
-
 		responses[i] = spec.NewJSONResponse(
 			baseRequest.ID,
 			handleResp,
@@ -357,13 +794,10 @@ func (j *JSONRPC) route(
 	// Get the appropriate handler
 	handler := j.handlers[request.Method]
 	if handler == nil {
-		return nil, spec.NewJSONError(
-			"Method handler not set",
-			spec.MethodNotFoundErrorCode,
-		)
+		return nil, spec.GenerateMethodNotFoundError(request.Method)
 	}
 
-	return handler(metadata, request.Params)
+	return applyMiddlewares(request.Method, handler, j.middlewares)(metadata, request.Params)
 }
 
 // isValidBaseRequest validates that the base JSON request is valid
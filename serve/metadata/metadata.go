@@ -1,9 +1,20 @@
 package metadata
 
+import (
+	"context"
+	"net/http"
+)
+
 // Metadata houses the active request metadata
 type Metadata struct {
+	ctx context.Context
+
 	WebSocketID *string
+	Header      http.Header
 	RemoteAddr  string
+
+	// APIKey is the name of the API key authenticating the request, if any
+	APIKey string
 }
 
 // NewMetadata creates a new request metadata object
@@ -24,3 +35,13 @@ func NewMetadata(remoteAddr string, opts ...Option) *Metadata {
 func (m *Metadata) IsWS() bool {
 	return m.WebSocketID != nil
 }
+
+// Context returns the request context.
+// The background context is returned if none is set
+func (m *Metadata) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+
+	return m.ctx
+}
@@ -35,4 +35,14 @@ func TestMetadata_NewMetadata(t *testing.T) {
 		assert.True(t, m.IsWS())
 		assert.Equal(t, wsID, *m.WebSocketID)
 	})
+
+	t.Run("API key metadata", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewMetadata("remote address", WithAPIKey("key"))
+
+		require.NotNil(t, m)
+
+		assert.Equal(t, "key", m.APIKey)
+	})
 }
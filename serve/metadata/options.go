@@ -1,5 +1,10 @@
 package metadata
 
+import (
+	"context"
+	"net/http"
+)
+
 type Option func(m *Metadata)
 
 // WithWebSocketID sets the WS connection ID
@@ -9,3 +14,27 @@ func WithWebSocketID(id string) Option {
 		m.WebSocketID = &id
 	}
 }
+
+// WithContext sets the request context
+// for the connection metadata
+func WithContext(ctx context.Context) Option {
+	return func(m *Metadata) {
+		m.ctx = ctx
+	}
+}
+
+// WithHeader sets the request (or WS handshake) headers
+// for the connection metadata
+func WithHeader(header http.Header) Option {
+	return func(m *Metadata) {
+		m.Header = header
+	}
+}
+
+// WithAPIKey sets the name of the API key
+// authenticating the request (or WS handshake)
+func WithAPIKey(name string) Option {
+	return func(m *Metadata) {
+		m.APIKey = name
+	}
+}
@@ -0,0 +1,70 @@
+package serve
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// Metrics are the JSON-RPC method metrics, shared by the servers of all the indexed chains
+type Metrics struct {
+	latency *prometheus.HistogramVec
+	errors  *prometheus.CounterVec
+}
+
+// NewMetrics creates the JSON-RPC method metrics, and registers them with the registerer
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "tx_indexer",
+				Subsystem: "serve",
+				Name:      "request_duration_seconds",
+				Help:      "The latency of the JSON-RPC method requests, by chain, method and transport",
+				Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"chain", "method", "transport"},
+		),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "tx_indexer",
+				Subsystem: "serve",
+				Name:      "request_errors_total",
+				Help:      "The number of failed JSON-RPC method requests, by chain, method and error code",
+			},
+			[]string{"chain", "method", "code"},
+		),
+	}
+
+	registerer.MustRegister(m.latency, m.errors)
+
+	return m
+}
+
+// Middleware records the method requests of the chain, over both HTTP and WS
+func (m *Metrics) Middleware(chain string) Middleware {
+	return func(method string, next Handler) Handler {
+		return func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			start := time.Now()
+
+			response, err := next(metadata, params)
+
+			transport := "http"
+			if metadata.IsWS() {
+				transport = "ws"
+			}
+
+			m.latency.WithLabelValues(chain, method, transport).Observe(time.Since(start).Seconds())
+
+			if err != nil {
+				m.errors.WithLabelValues(chain, method, strconv.Itoa(err.Code)).Inc()
+			}
+
+			return response, err
+		}
+	}
+}
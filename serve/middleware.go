@@ -0,0 +1,79 @@
+package serve
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// Middleware wraps the method handler, for both HTTP and WS requests.
+// Middlewares are applied in the order they were added, the first one being the outermost
+type Middleware func(method string, next Handler) Handler
+
+// LoggingMiddleware logs the handled method requests
+func LoggingMiddleware(logger *zap.Logger) Middleware {
+	return func(method string, next Handler) Handler {
+		return func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			start := time.Now()
+
+			response, err := next(metadata, params)
+			if err != nil {
+				logger.Debug(
+					"unable to handle JSON-RPC request",
+					zap.String("method", method),
+					zap.String("remote", metadata.RemoteAddr),
+					zap.Any("params", params),
+					zap.Any("error", err),
+				)
+
+				return nil, err
+			}
+
+			logger.Debug(
+				"handled request",
+				zap.String("method", method),
+				zap.String("remote", metadata.RemoteAddr),
+				zap.Any("params", params),
+				zap.Duration("duration", time.Since(start)),
+			)
+
+			return response, nil
+		}
+	}
+}
+
+// RecoveryMiddleware recovers from method handler panics, returning a generic server error instead.
+// The panic value (and stack) is only logged, so the internal details are not leaked to the clients
+func RecoveryMiddleware(logger *zap.Logger) Middleware {
+	return func(method string, next Handler) Handler {
+		return func(metadata *metadata.Metadata, params []any) (response any, err *spec.BaseJSONError) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error(
+						"method handler panicked",
+						zap.String("method", method),
+						zap.Any("panic", r),
+						zap.Stack("stack"),
+					)
+
+					response = nil
+					err = spec.GenerateInternalError()
+				}
+			}()
+
+			return next(metadata, params)
+		}
+	}
+}
+
+// applyMiddlewares wraps the handler with the given middlewares
+func applyMiddlewares(method string, handler Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](method, handler)
+	}
+
+	return handler
+}
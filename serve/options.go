@@ -1,6 +1,14 @@
 package serve
 
-import "go.uber.org/zap"
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/serve/filters"
+	"github.com/gnolang/tx-indexer/storage"
+)
 
 type Option func(s *JSONRPC)
 
@@ -11,3 +19,59 @@ func WithLogger(logger *zap.Logger) Option {
 		s.logger = logger
 	}
 }
+
+// WithStorage sets the storage exposed
+// to the request handlers
+func WithStorage(storage storage.Reader) Option {
+	return func(s *JSONRPC) {
+		s.storage = storage
+	}
+}
+
+// WithDecoder sets the chain decoder of the txs,
+// matched by the query filters and the watchlist
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(s *JSONRPC) {
+		s.decoder = decoder
+	}
+}
+
+// WithMiddleware adds the method middlewares to the JSON-RPC server,
+// after the default recovery and logging middlewares
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(s *JSONRPC) {
+		s.middlewares = append(s.middlewares, middlewares...)
+	}
+}
+
+// WithWSPingPeriod sets the period of the pings
+// sent to the WS clients
+func WithWSPingPeriod(period time.Duration) Option {
+	return func(s *JSONRPC) {
+		s.wsPingPeriod = period
+	}
+}
+
+// WithWSIdleTimeout sets the time the WS connections are kept
+// open without a pong from the client, before being closed
+func WithWSIdleTimeout(timeout time.Duration) Option {
+	return func(s *JSONRPC) {
+		s.wsIdleTimeout = timeout
+	}
+}
+
+// WithWSConnLimiter sets the limiter of the open WS connections.
+// Unlimited by default
+func WithWSConnLimiter(limiter *WSConnLimiter) Option {
+	return func(s *JSONRPC) {
+		s.wsLimiter = limiter
+	}
+}
+
+// WithSubscriptionLimiter sets the limiter of the active WS subscriptions.
+// Unlimited by default
+func WithSubscriptionLimiter(limiter *filters.SubscriptionLimiter) Option {
+	return func(s *JSONRPC) {
+		s.subscriptionLimiter = limiter
+	}
+}
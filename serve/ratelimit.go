@@ -0,0 +1,110 @@
+package serve
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/httprate"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// rateLimitWindow is the window of the request rate limits
+const rateLimitWindow = time.Minute
+
+// RateLimitMiddleware limits the number of HTTP requests allowed per minute, per client IP.
+// The client IP is the request remote address, unless trustProxies is set, in which case
+// it's read from the X-Real-IP header, resolved by the IP filter from the trusted proxies
+func RateLimitMiddleware(limit int, trustProxies bool, logger *zap.Logger) func(http.Handler) http.Handler {
+	keyFn := func(r *http.Request) (string, error) {
+		return requestIP(r.RemoteAddr, r.Header, trustProxies), nil
+	}
+
+	return httprate.Limit(
+		limit,
+		rateLimitWindow,
+		httprate.WithKeyFuncs(keyFn),
+		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			logger.Debug("too many requests", zap.String("from", requestIP(r.RemoteAddr, r.Header, trustProxies)))
+
+			// send a JSON-RPC error response, which also gives
+			// more info when using the graphQL explorer
+			w.Header().Set("Content-Type", jsonMimeType)
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			response := spec.NewJSONResponse(
+				0,
+				nil,
+				spec.GenerateRateLimitedError(int(rateLimitWindow.Seconds())),
+			)
+
+			if err := json.NewEncoder(w).Encode(response); err != nil {
+				logger.Debug("unable to write rate limit response", zap.Error(err))
+			}
+		}),
+	)
+}
+
+// WSRateLimitMiddleware limits the number of WS method requests allowed per minute, per client IP,
+// as the WS requests share the single HTTP request of the handshake. The HTTP requests are skipped,
+// as they're limited by the RateLimitMiddleware. The client IP is resolved the same way
+func WSRateLimitMiddleware(limit int, trustProxies bool) Middleware {
+	l := &methodRateLimiter{
+		limit:  limit,
+		counts: make(map[string]int),
+		now:    time.Now,
+	}
+
+	return func(_ string, next Handler) Handler {
+		return func(metadata *metadata.Metadata, params []any) (any, *spec.BaseJSONError) {
+			if !metadata.IsWS() {
+				return next(metadata, params)
+			}
+
+			retryAfter, ok := l.allow(requestIP(metadata.RemoteAddr, metadata.Header, trustProxies))
+			if !ok {
+				return nil, spec.GenerateRateLimitedError(int(math.Ceil(retryAfter.Seconds())))
+			}
+
+			return next(metadata, params)
+		}
+	}
+}
+
+// methodRateLimiter counts the requests per client IP, in fixed windows
+type methodRateLimiter struct {
+	limit int
+	now   func() time.Time
+
+	windowStart time.Time
+	counts      map[string]int
+
+	mux sync.Mutex
+}
+
+// allow accounts for the request of the IP, if it's within the window limit.
+// Otherwise, it returns the time until the window resets
+func (l *methodRateLimiter) allow(ip string) (time.Duration, bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	now := l.now()
+
+	if now.Sub(l.windowStart) >= rateLimitWindow {
+		l.windowStart = now
+		l.counts = make(map[string]int)
+	}
+
+	if l.counts[ip] >= l.limit {
+		return l.windowStart.Add(rateLimitWindow).Sub(now), false
+	}
+
+	l.counts[ip]++
+
+	return 0, true
+}
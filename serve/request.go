@@ -0,0 +1,58 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gnolang/tx-indexer/serve/metadata"
+	"github.com/gnolang/tx-indexer/serve/spec"
+	"github.com/gnolang/tx-indexer/storage"
+)
+
+// RequestHandler executes a method using the request,
+// that exposes the request context, params and storage
+type RequestHandler func(request *Request) (any, *spec.BaseJSONError)
+
+// Request is the incoming method request
+type Request struct {
+	// Metadata is the request metadata
+	Metadata *metadata.Metadata
+
+	// Storage is the indexer storage, if set for the JSON-RPC server
+	Storage storage.Reader
+
+	// Params are the raw request params
+	Params []any
+}
+
+// Context returns the request context, which is cancelled
+// when the HTTP request or WS connection is closed
+func (r *Request) Context() context.Context {
+	return r.Metadata.Context()
+}
+
+// DecodeParams decodes the positional request params into the given destinations,
+// in order. Params that are not present are considered optional, and their destinations are left unchanged
+func (r *Request) DecodeParams(destinations ...any) *spec.BaseJSONError {
+	if len(r.Params) > len(destinations) {
+		return spec.GenerateInvalidParamCountError()
+	}
+
+	for index, param := range r.Params {
+		if err := decodeParam(param, destinations[index]); err != nil {
+			return spec.GenerateInvalidParamError(index + 1)
+		}
+	}
+
+	return nil
+}
+
+// decodeParam decodes the raw JSON param into the destination
+func decodeParam(param, destination any) error {
+	marshaled, err := json.Marshal(param)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(marshaled, destination)
+}
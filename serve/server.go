@@ -13,16 +13,79 @@ import (
 
 const (
 	DefaultListenAddress = "0.0.0.0:8546"
+
+	// DefaultShutdownTimeout is the default time the in-flight
+	// requests are given to finish on shutdown
+	DefaultShutdownTimeout = 30 * time.Second
 )
 
 type HTTPServer struct {
 	h      http.Handler
 	logger *zap.Logger
 	addr   string
+
+	// listener is the listener the server accepts on, if set.
+	// The server listens on the address otherwise
+	listener net.Listener
+
+	// startHooks are called once the server listens
+	startHooks []func()
+
+	// shutdownHooks are called on shutdown, once
+	// the in-flight HTTP requests are finished
+	shutdownHooks []func()
+
+	shutdownTimeout time.Duration
+}
+
+type ServerOption func(s *HTTPServer)
+
+// WithShutdownTimeout sets the time the in-flight
+// requests are given to finish on shutdown
+func WithShutdownTimeout(timeout time.Duration) ServerOption {
+	return func(s *HTTPServer) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithListener sets the listener the server accepts on
+// (ex. inherited from the parent process), in place of the listen address
+func WithListener(listener net.Listener) ServerOption {
+	return func(s *HTTPServer) {
+		s.listener = listener
+	}
 }
 
-func NewHTTPServer(h http.Handler, addr string, logger *zap.Logger) *HTTPServer {
-	return &HTTPServer{h: h, addr: addr, logger: logger}
+// WithStartHooks adds the hooks called once the server listens
+// (ex. for signaling the service readiness)
+func WithStartHooks(hooks ...func()) ServerOption {
+	return func(s *HTTPServer) {
+		s.startHooks = append(s.startHooks, hooks...)
+	}
+}
+
+// WithShutdownHooks adds the hooks called on shutdown, once the in-flight
+// HTTP requests are finished (ex. for closing the WS connections,
+// which are not tracked by the HTTP server)
+func WithShutdownHooks(hooks ...func()) ServerOption {
+	return func(s *HTTPServer) {
+		s.shutdownHooks = append(s.shutdownHooks, hooks...)
+	}
+}
+
+func NewHTTPServer(h http.Handler, addr string, logger *zap.Logger, opts ...ServerOption) *HTTPServer {
+	s := &HTTPServer{
+		h:               h,
+		addr:            addr,
+		logger:          logger,
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Serve serves the JSON-RPC server
@@ -38,9 +101,13 @@ func (s *HTTPServer) Serve(ctx context.Context) error {
 	group.Go(func() error {
 		defer s.logger.Info("HTTP server shut down")
 
-		ln, err := net.Listen("tcp", faucet.Addr)
-		if err != nil {
-			return err
+		ln := s.listener
+		if ln == nil {
+			var err error
+
+			if ln, err = net.Listen("tcp", faucet.Addr); err != nil {
+				return err
+			}
 		}
 
 		s.logger.Info(
@@ -48,6 +115,10 @@ func (s *HTTPServer) Serve(ctx context.Context) error {
 			zap.String("address", ln.Addr().String()),
 		)
 
+		for _, hook := range s.startHooks {
+			hook()
+		}
+
 		if err := faucet.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			return err
 		}
@@ -60,10 +131,22 @@ func (s *HTTPServer) Serve(ctx context.Context) error {
 
 		s.logger.Info("HTTP server to be shut down")
 
-		wsCtx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		// Stop accepting new connections, and wait for the in-flight requests
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
 		defer cancel()
 
-		return faucet.Shutdown(wsCtx)
+		shutdownErr := faucet.Shutdown(shutdownCtx)
+		if shutdownErr != nil {
+			s.logger.Warn("in-flight requests not finished before the shutdown timeout")
+
+			shutdownErr = faucet.Close()
+		}
+
+		for _, hook := range s.shutdownHooks {
+			hook()
+		}
+
+		return shutdownErr
 	})
 
 	return group.Wait()
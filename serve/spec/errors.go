@@ -1,9 +1,261 @@
 package spec
 
+import "fmt"
+
+// The error codes of the JSON-RPC responses. The codes above -32000
+// are the JSON-RPC 2.0 standard ones, and the codes below are the
+// indexer server errors, which are stable and safe to branch on
 const (
 	ParseErrorCode          int = -32700
 	InvalidParamsErrorCode  int = -32602
 	MethodNotFoundErrorCode int = -32601
 	InvalidRequestErrorCode int = -32600
-	ServerErrorCode         int = -32000
+
+	// ServerErrorCode is the internal server error
+	ServerErrorCode int = -32000
+
+	// NotFoundErrorCode is the error of the missing
+	// resource (ex. an unknown filter or subscription ID)
+	NotFoundErrorCode int = -32001
+
+	// NotSyncedErrorCode is the error of the queried height,
+	// not yet indexed
+	NotSyncedErrorCode int = -32002
+
+	// OutOfRangeErrorCode is the error of the valid param,
+	// exceeding its allowed range
+	OutOfRangeErrorCode int = -32003
+
+	// MethodNotSupportedErrorCode is the error of the method,
+	// not supported over the request transport (ex. WS only methods over HTTP)
+	MethodNotSupportedErrorCode int = -32004
+
+	// RateLimitedErrorCode is the error of the request
+	// over the rate limit
+	RateLimitedErrorCode int = -32005
+
+	// SubscriptionLimitErrorCode is the error of the subscription
+	// over the active subscription limits
+	SubscriptionLimitErrorCode int = -32006
+
+	// ForbiddenErrorCode is the error of the request
+	// from a client IP that isn't allowed
+	ForbiddenErrorCode int = -32007
+
+	// UnauthorizedErrorCode is the error of the request
+	// with a missing or invalid API key (or admin token)
+	UnauthorizedErrorCode int = -32008
+
+	// QuotaExceededErrorCode is the error of the request
+	// over the usage quota of its API key
+	QuotaExceededErrorCode int = -32009
 )
+
+// The machine-readable reasons of the errors, set in the error data
+const (
+	ReasonParse              = "parse_error"
+	ReasonInvalidRequest     = "invalid_request"
+	ReasonMethodNotFound     = "method_not_found"
+	ReasonInvalidParams      = "invalid_params"
+	ReasonInternal           = "internal"
+	ReasonNotFound           = "not_found"
+	ReasonNotSynced          = "not_synced"
+	ReasonOutOfRange         = "out_of_range"
+	ReasonMethodNotSupported = "method_not_supported"
+	ReasonRateLimited        = "rate_limited"
+	ReasonSubscriptionLimit  = "subscription_limit"
+	ReasonForbidden          = "forbidden"
+	ReasonUnauthorized       = "unauthorized"
+	ReasonQuotaExceeded      = "quota_exceeded"
+)
+
+// The scopes of the exceeded subscription limits and quotas
+const (
+	ScopeConnection = "connection"
+	ScopeGlobal     = "global"
+	ScopeDaily      = "daily"
+	ScopeMonthly    = "monthly"
+)
+
+// reasons are the error reasons of the codes
+var reasons = map[int]string{
+	ParseErrorCode:              ReasonParse,
+	InvalidRequestErrorCode:     ReasonInvalidRequest,
+	MethodNotFoundErrorCode:     ReasonMethodNotFound,
+	InvalidParamsErrorCode:      ReasonInvalidParams,
+	ServerErrorCode:             ReasonInternal,
+	NotFoundErrorCode:           ReasonNotFound,
+	NotSyncedErrorCode:          ReasonNotSynced,
+	OutOfRangeErrorCode:         ReasonOutOfRange,
+	MethodNotSupportedErrorCode: ReasonMethodNotSupported,
+	RateLimitedErrorCode:        ReasonRateLimited,
+	SubscriptionLimitErrorCode:  ReasonSubscriptionLimit,
+	ForbiddenErrorCode:          ReasonForbidden,
+	UnauthorizedErrorCode:       ReasonUnauthorized,
+	QuotaExceededErrorCode:      ReasonQuotaExceeded,
+}
+
+// ErrorData is the machine-readable data of the JSON-RPC errors.
+// Only the fields relevant to the error reason are set
+type ErrorData struct {
+	// Max is the maximum value of the out of range param,
+	// or the exceeded subscription limit or quota
+	Max *uint64 `json:"max,omitempty"`
+
+	// LatestHeight is the latest indexed height, for the not synced errors
+	LatestHeight *uint64 `json:"latestHeight,omitempty"`
+
+	// Reason is the error reason, one of the Reason constants
+	Reason string `json:"reason"`
+
+	// Method is the not found or not supported method
+	Method string `json:"method,omitempty"`
+
+	// Scope is the scope of the exceeded subscription limit
+	// or quota, one of the Scope constants
+	Scope string `json:"scope,omitempty"`
+
+	// Resource is the type of the not found resource (ex. "filter"),
+	// or the resource of the exceeded quota ("requests" or "bytes")
+	Resource string `json:"resource,omitempty"`
+
+	// ID is the ID of the not found resource
+	ID string `json:"id,omitempty"`
+
+	// Param is the (1-based) index of the invalid param
+	Param int `json:"param,omitempty"`
+
+	// RetryAfter is the number of seconds after which the rate
+	// limited (or over the quota) requests can be retried
+	RetryAfter int `json:"retryAfter,omitempty"`
+}
+
+// NewJSONError creates a new JSON-RPC error,
+// with the error data reason of the code
+func NewJSONError(message string, code int) *BaseJSONError {
+	return NewJSONErrorWithData(message, code, &ErrorData{
+		Reason: reasons[code],
+	})
+}
+
+// NewJSONErrorWithData creates a new JSON-RPC error,
+// with the given error data
+func NewJSONErrorWithData(message string, code int, data *ErrorData) *BaseJSONError {
+	if data.Reason == "" {
+		data.Reason = reasons[code]
+	}
+
+	return &BaseJSONError{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	}
+}
+
+// GenerateMethodNotFoundError generates the JSON-RPC method not found error
+func GenerateMethodNotFoundError(method string) *BaseJSONError {
+	return NewJSONErrorWithData(
+		"Method handler not set",
+		MethodNotFoundErrorCode,
+		&ErrorData{Method: method},
+	)
+}
+
+// GenerateMethodNotSupportedError generates the JSON-RPC error
+// of the WS only methods, called over HTTP
+func GenerateMethodNotSupportedError() *BaseJSONError {
+	return NewJSONError(
+		"Method only supported over WS",
+		MethodNotSupportedErrorCode,
+	)
+}
+
+// GenerateNotFoundError generates the JSON-RPC error of the missing resource
+func GenerateNotFoundError(resource, id string) *BaseJSONError {
+	return NewJSONErrorWithData(
+		fmt.Sprintf("%s %s not found", resource, id),
+		NotFoundErrorCode,
+		&ErrorData{Resource: resource, ID: id},
+	)
+}
+
+// GenerateNotSyncedError generates the JSON-RPC error
+// of the height not yet indexed
+func GenerateNotSyncedError(height, latestHeight uint64) *BaseJSONError {
+	return NewJSONErrorWithData(
+		fmt.Sprintf("Height %d not yet indexed, the latest indexed height is %d", height, latestHeight),
+		NotSyncedErrorCode,
+		&ErrorData{LatestHeight: &latestHeight},
+	)
+}
+
+// GenerateOutOfRangeParamError generates the JSON-RPC error
+// of the param exceeding its maximum value
+func GenerateOutOfRangeParamError(index int, maxValue uint64) *BaseJSONError {
+	return NewJSONErrorWithData(
+		fmt.Sprintf(
+			"Out of range %s parameter, the maximum is %d",
+			getOrdinalSuffix(index),
+			maxValue,
+		),
+		OutOfRangeErrorCode,
+		&ErrorData{Param: index, Max: &maxValue},
+	)
+}
+
+// GenerateInternalError generates the JSON-RPC error
+// of the request that failed unexpectedly, without the internal details
+func GenerateInternalError() *BaseJSONError {
+	return NewJSONError(
+		"Internal server error",
+		ServerErrorCode,
+	)
+}
+
+// GenerateRateLimitedError generates the JSON-RPC error
+// of the rate limited request
+func GenerateRateLimitedError(retryAfter int) *BaseJSONError {
+	return NewJSONErrorWithData(
+		"Too many requests",
+		RateLimitedErrorCode,
+		&ErrorData{RetryAfter: retryAfter},
+	)
+}
+
+// GenerateForbiddenError generates the JSON-RPC error
+// of the request from a client IP that isn't allowed
+func GenerateForbiddenError() *BaseJSONError {
+	return NewJSONError(
+		"Access denied",
+		ForbiddenErrorCode,
+	)
+}
+
+// GenerateUnauthorizedError generates the JSON-RPC error
+// of the request with a missing or invalid credential
+func GenerateUnauthorizedError(message string) *BaseJSONError {
+	return NewJSONError(
+		message,
+		UnauthorizedErrorCode,
+	)
+}
+
+// GenerateQuotaExceededError generates the JSON-RPC error
+// of the request over the usage quota of the scope
+func GenerateQuotaExceededError(scope, resource string, quota uint64, retryAfter int) *BaseJSONError {
+	return NewJSONErrorWithData(
+		fmt.Sprintf("The %s %s quota of %d is exceeded", scope, resource, quota),
+		QuotaExceededErrorCode,
+		&ErrorData{Scope: scope, Resource: resource, Max: &quota, RetryAfter: retryAfter},
+	)
+}
+
+// GenerateSubscriptionLimitError generates the JSON-RPC error
+// of the subscription over the limit of the scope
+func GenerateSubscriptionLimitError(message, scope string, maxSubscriptions uint64) *BaseJSONError {
+	return NewJSONErrorWithData(
+		message,
+		SubscriptionLimitErrorCode,
+		&ErrorData{Scope: scope, Max: &maxSubscriptions},
+	)
+}
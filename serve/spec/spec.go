@@ -38,25 +38,26 @@ type BaseJSONResponse struct {
 	BaseJSON
 }
 
-// BaseJSONSubscribeResponse defines the base JSON response
-// format for subscribe responses
+// BaseJSONSubscribeResponse defines the base JSON format of the subscription
+// events, delivered as JSON-RPC 2.0 notifications (without an ID)
 type BaseJSONSubscribeResponse struct {
 	Params  *SubscribeResponse `json:"params"`
 	JSONRPC string             `json:"jsonrpc"`
 	Method  string             `json:"method"`
 }
 
-// SubscribeResponse defines the subscribe response data
+// SubscribeResponse defines the subscription event data,
+// with the ID of the subscription returned by the subscribe call
 type SubscribeResponse struct {
-	Result       any    `json:"result,omitempty"`
+	Result       any    `json:"result"`
 	Subscription string `json:"subscription"`
 }
 
 // BaseJSONError defines the base JSON response error format
 type BaseJSONError struct {
-	Data    any    `json:"data,omitempty"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Data    *ErrorData `json:"data,omitempty"`
+	Message string     `json:"message"`
+	Code    int        `json:"code"`
 }
 
 // NewJSONRequest creates a new JSON-RPC request
@@ -91,14 +92,6 @@ func NewJSONResponse(
 	}
 }
 
-// NewJSONError creates a new JSON-RPC error
-func NewJSONError(message string, code int) *BaseJSONError {
-	return &BaseJSONError{
-		Code:    code,
-		Message: message,
-	}
-}
-
 // NewJSONSubscribeResponse creates a new JSON-RPC response for the subscribe responses.
 // These WS responses (for this method only) are different from any other response
 func NewJSONSubscribeResponse(id string, result any) *BaseJSONSubscribeResponse {
@@ -119,12 +112,13 @@ func GenerateResponseError(err error) *BaseJSONError {
 
 // GenerateInvalidParamError generates the JSON-RPC invalid param error response
 func GenerateInvalidParamError(index int) *BaseJSONError {
-	return NewJSONError(
+	return NewJSONErrorWithData(
 		fmt.Sprintf(
 			"Invalid %s parameter",
 			getOrdinalSuffix(index),
 		),
 		InvalidParamsErrorCode,
+		&ErrorData{Param: index},
 	)
 }
 
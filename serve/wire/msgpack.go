@@ -0,0 +1,244 @@
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// marshalMsgpack encodes the response in MessagePack, keeping the JSON field names.
+// The Amino encoded values are encoded as raw binary (instead of base64 strings),
+// and the other results are transcoded from their JSON encoding
+func marshalMsgpack(response any) ([]byte, error) {
+	var (
+		value any
+		err   error
+	)
+
+	switch r := response.(type) {
+	case *spec.BaseJSONResponse:
+		value, err = msgpackResponse(r)
+	case spec.BaseJSONResponses:
+		values := make([]any, len(r))
+
+		for i, single := range r {
+			if values[i], err = msgpackResponse(single); err != nil {
+				break
+			}
+		}
+
+		value = values
+	default:
+		err = fmt.Errorf("%w, %T", errUnsupportedResponse, response)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appendMsgpack(nil, value)
+}
+
+// msgpackResponse converts the response to its generic (JSON) value
+func msgpackResponse(response *spec.BaseJSONResponse) (map[string]any, error) {
+	result, err := genericValue(response.Result)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert result, %w", err)
+	}
+
+	value := map[string]any{
+		"jsonrpc": response.JSONRPC,
+		"result":  result,
+	}
+
+	if response.ID != 0 {
+		value["id"] = uint64(response.ID)
+	}
+
+	if response.Error != nil {
+		if value["error"], err = genericValue(response.Error); err != nil {
+			return nil, fmt.Errorf("unable to convert error, %w", err)
+		}
+	}
+
+	return value, nil
+}
+
+// genericValue converts the value to its generic JSON value
+// (nil, bool, json.Number, string, []any or map[string]any),
+// keeping the Amino encoded values as raw bytes
+func genericValue(value any) (any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case encode.Value:
+		return []byte(v), nil
+	case []encode.Value:
+		values := make([]any, len(v))
+
+		for i, item := range v {
+			if item != nil {
+				values[i] = []byte(item)
+			}
+		}
+
+		return values, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var generic any
+
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
+
+// appendMsgpack appends the MessagePack encoding of the generic value
+func appendMsgpack(b []byte, value any) ([]byte, error) {
+	var err error
+
+	switch v := value.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+
+		return append(b, 0xc2), nil
+	case uint64:
+		return appendMsgpackUint(b, v), nil
+	case json.Number:
+		return appendMsgpackNumber(b, v)
+	case string:
+		return appendMsgpackString(b, v), nil
+	case []byte:
+		b = appendMsgpackLength(b, len(v), 0, 0xc4, 0xc5, 0xc6)
+
+		return append(b, v...), nil
+	case []any:
+		b = appendMsgpackLength(b, len(v), 0x90, 0, 0xdc, 0xdd)
+
+		for _, item := range v {
+			if b, err = appendMsgpack(b, item); err != nil {
+				return nil, err
+			}
+		}
+
+		return b, nil
+	case map[string]any:
+		b = appendMsgpackLength(b, len(v), 0x80, 0, 0xde, 0xdf)
+
+		// The keys are sorted, for a deterministic encoding
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			b = appendMsgpackString(b, key)
+
+			if b, err = appendMsgpack(b, v[key]); err != nil {
+				return nil, err
+			}
+		}
+
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%w, %T", errUnsupportedResponse, value)
+	}
+}
+
+// appendMsgpackNumber appends the JSON number, as an integer if possible
+func appendMsgpackNumber(b []byte, n json.Number) ([]byte, error) {
+	if i, err := n.Int64(); err == nil {
+		if i >= 0 {
+			return appendMsgpackUint(b, uint64(i)), nil
+		}
+
+		return appendMsgpackInt(b, i), nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q, %w", n, err)
+	}
+
+	b = append(b, 0xcb)
+
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f)), nil
+}
+
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v < 1<<7:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+	}
+}
+
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	b = appendMsgpackLength(b, len(s), 0xa0, 0xd9, 0xda, 0xdb)
+
+	return append(b, s...)
+}
+
+// appendMsgpackLength appends the header of the sized value, using the smallest
+// of its fixed (up to 31 items, 15 for arrays and maps), 8, 16 and 32 bit (0 if
+// not available) length headers
+func appendMsgpackLength(b []byte, length int, fix, len8, len16, len32 byte) []byte {
+	fixMax := 31
+	if fix == 0x90 || fix == 0x80 {
+		fixMax = 15
+	}
+
+	switch {
+	case fix != 0 && length <= fixMax:
+		return append(b, fix|byte(length))
+	case len8 != 0 && length <= math.MaxUint8:
+		return append(b, len8, byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, len16), uint16(length))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, len32), uint32(length))
+	}
+}
@@ -0,0 +1,114 @@
+package wire
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// The protobuf responses are encoded following the
+// schemas in response.proto. The fields are numbered as in the schemas
+
+// marshalProtobuf encodes the response in protobuf. The batch
+// responses are encoded as a Responses message
+func marshalProtobuf(response any) ([]byte, error) {
+	responses, err := responseList(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, single := response.(*spec.BaseJSONResponse); single {
+		return appendProtoResponse(nil, responses[0])
+	}
+
+	var b []byte
+
+	for _, r := range responses {
+		encoded, err := appendProtoResponse(nil, r)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+
+	return b, nil
+}
+
+func appendProtoResponse(b []byte, r *spec.BaseJSONResponse) ([]byte, error) {
+	if r.JSONRPC != "" {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendString(b, r.JSONRPC)
+	}
+
+	if r.ID != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(r.ID))
+	}
+
+	switch result := r.Result.(type) {
+	case nil:
+	case encode.Value:
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, result)
+	case []encode.Value:
+		var values []byte
+
+		for _, value := range result {
+			values = protowire.AppendTag(values, 1, protowire.BytesType)
+			values = protowire.AppendBytes(values, value)
+		}
+
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, values)
+	default:
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode result, %w", err)
+		}
+
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+
+	if r.Error != nil {
+		encoded, err := appendProtoError(nil, r.Error)
+		if err != nil {
+			return nil, err
+		}
+
+		b = protowire.AppendTag(b, 6, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+
+	return b, nil
+}
+
+func appendProtoError(b []byte, e *spec.BaseJSONError) ([]byte, error) {
+	if e.Code != 0 {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(int64(e.Code)))
+	}
+
+	if e.Message != "" {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, e.Message)
+	}
+
+	if e.Data != nil {
+		encoded, err := json.Marshal(e.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode error data, %w", err)
+		}
+
+		b = protowire.AppendTag(b, 3, protowire.BytesType)
+		b = protowire.AppendBytes(b, encoded)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,97 @@
+// Package wire implements the alternative (binary) encodings of the JSON-RPC responses,
+// negotiated with the HTTP clients using the Accept header
+package wire
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// Format is the wire format of the JSON-RPC responses
+type Format int
+
+const (
+	// FormatJSON is the default JSON encoding
+	FormatJSON Format = iota
+
+	// FormatMsgpack is the MessagePack encoding of the JSON responses,
+	// with the Amino encoded values as raw binary
+	FormatMsgpack
+
+	// FormatProtobuf is the protobuf encoding of the responses,
+	// following the schemas in response.proto
+	FormatProtobuf
+)
+
+const (
+	jsonMimeType     = "application/json"
+	msgpackMimeType  = "application/msgpack"
+	protobufMimeType = "application/x-protobuf"
+)
+
+var errUnsupportedResponse = errors.New("unsupported response type")
+
+// mimeTypes are the supported media types of the formats
+var mimeTypes = map[string]Format{
+	"*/*":                   FormatJSON,
+	"application/*":         FormatJSON,
+	jsonMimeType:            FormatJSON,
+	msgpackMimeType:         FormatMsgpack,
+	"application/x-msgpack": FormatMsgpack,
+	protobufMimeType:        FormatProtobuf,
+	"application/protobuf":  FormatProtobuf,
+}
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatMsgpack:
+		return msgpackMimeType
+	case FormatProtobuf:
+		return protobufMimeType
+	default:
+		return jsonMimeType
+	}
+}
+
+// Negotiate returns the format requested in the Accept header.
+// The first supported media type wins, falling back to JSON
+func Negotiate(accept string) Format {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+
+		if format, ok := mimeTypes[strings.ToLower(strings.TrimSpace(mediaType))]; ok {
+			return format
+		}
+	}
+
+	return FormatJSON
+}
+
+// Marshal encodes the JSON-RPC response (single or batch) in the format
+func Marshal(format Format, response any) ([]byte, error) {
+	switch format {
+	case FormatMsgpack:
+		return marshalMsgpack(response)
+	case FormatProtobuf:
+		return marshalProtobuf(response)
+	default:
+		return json.Marshal(response)
+	}
+}
+
+// responseList returns the responses of the single or batch response
+func responseList(response any) ([]*spec.BaseJSONResponse, error) {
+	switch r := response.(type) {
+	case *spec.BaseJSONResponse:
+		return []*spec.BaseJSONResponse{r}, nil
+	case spec.BaseJSONResponses:
+		return r, nil
+	default:
+		return nil, fmt.Errorf("%w, %T", errUnsupportedResponse, response)
+	}
+}
@@ -0,0 +1,220 @@
+package wire
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/gnolang/tx-indexer/serve/encode"
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// decodeProto decodes the protobuf message into its bytes and varint fields
+func decodeProto(t *testing.T, b []byte) map[protowire.Number][]any {
+	t.Helper()
+
+	fields := make(map[protowire.Number][]any)
+
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+
+		b = b[n:]
+
+		var value any
+
+		switch typ {
+		case protowire.VarintType:
+			value, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected field type %d", typ)
+		}
+
+		require.GreaterOrEqual(t, n, 0)
+
+		b = b[n:]
+		fields[num] = append(fields[num], value)
+	}
+
+	return fields
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		accept   string
+		expected Format
+	}{
+		{"no header", "", FormatJSON},
+		{"any", "*/*", FormatJSON},
+		{"json", "application/json", FormatJSON},
+		{"msgpack", "application/msgpack", FormatMsgpack},
+		{"legacy msgpack", "application/x-msgpack", FormatMsgpack},
+		{"protobuf", "application/x-protobuf", FormatProtobuf},
+		{"protobuf with params", "Application/Protobuf; q=0.9", FormatProtobuf},
+		{"first supported", "text/html, application/msgpack, application/json", FormatMsgpack},
+		{"unsupported", "text/html", FormatJSON},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, Negotiate(testCase.accept))
+		})
+	}
+}
+
+func TestMarshal_Msgpack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("amino value", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := Marshal(FormatMsgpack, spec.NewJSONResponse(1, encode.Value{0x0a, 0x01}, nil))
+		require.NoError(t, err)
+
+		expected := []byte{0x83}
+		expected = append(expected, 0xa2, 'i', 'd', 0x01)
+		expected = append(expected, 0xa7, 'j', 's', 'o', 'n', 'r', 'p', 'c', 0xa3, '2', '.', '0')
+		expected = append(expected, 0xa6, 'r', 'e', 's', 'u', 'l', 't', 0xc4, 0x02, 0x0a, 0x01)
+
+		assert.Equal(t, expected, encoded)
+	})
+
+	t.Run("batch with JSON results and errors", func(t *testing.T) {
+		t.Parallel()
+
+		responses := spec.BaseJSONResponses{
+			spec.NewJSONResponse(1, []encode.Value{{0x01}, nil}, nil),
+			spec.NewJSONResponse(2, nil, &spec.BaseJSONError{Message: "failed", Code: spec.ServerErrorCode}),
+			spec.NewJSONResponse(3, map[string]any{"count": 300, "ok": true}, nil),
+		}
+
+		encoded, err := Marshal(FormatMsgpack, responses)
+		require.NoError(t, err)
+
+		expected := []byte{0x93}
+
+		// Amino value list, with a missing (nil) value
+		expected = append(expected, 0x83, 0xa2, 'i', 'd', 0x01)
+		expected = append(expected, 0xa7, 'j', 's', 'o', 'n', 'r', 'p', 'c', 0xa3, '2', '.', '0')
+		expected = append(expected, 0xa6, 'r', 'e', 's', 'u', 'l', 't', 0x92, 0xc4, 0x01, 0x01, 0xc0)
+
+		// Error, with the negative code
+		expected = append(expected, 0x84)
+		expected = append(expected, 0xa5, 'e', 'r', 'r', 'o', 'r', 0x82)
+		expected = append(expected, 0xa4, 'c', 'o', 'd', 'e', 0xd1, 0x83, 0x00)
+		expected = append(expected, 0xa7, 'm', 'e', 's', 's', 'a', 'g', 'e', 0xa6, 'f', 'a', 'i', 'l', 'e', 'd')
+		expected = append(expected, 0xa2, 'i', 'd', 0x02)
+		expected = append(expected, 0xa7, 'j', 's', 'o', 'n', 'r', 'p', 'c', 0xa3, '2', '.', '0')
+		expected = append(expected, 0xa6, 'r', 'e', 's', 'u', 'l', 't', 0xc0)
+
+		// JSON result
+		expected = append(expected, 0x83, 0xa2, 'i', 'd', 0x03)
+		expected = append(expected, 0xa7, 'j', 's', 'o', 'n', 'r', 'p', 'c', 0xa3, '2', '.', '0')
+		expected = append(expected, 0xa6, 'r', 'e', 's', 'u', 'l', 't', 0x82)
+		expected = append(expected, 0xa5, 'c', 'o', 'u', 'n', 't', 0xcd, 0x01, 0x2c)
+		expected = append(expected, 0xa2, 'o', 'k', 0xc3)
+
+		assert.Equal(t, expected, encoded)
+	})
+
+	t.Run("unsupported response", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Marshal(FormatMsgpack, "response")
+		assert.ErrorIs(t, err, errUnsupportedResponse)
+	})
+}
+
+func TestMarshal_Protobuf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("amino value", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := Marshal(FormatProtobuf, spec.NewJSONResponse(7, encode.Value{0x0a, 0x01}, nil))
+		require.NoError(t, err)
+
+		fields := decodeProto(t, encoded)
+
+		assert.Equal(t, []any{[]byte(spec.JSONRPCVersion)}, fields[1])
+		assert.Equal(t, []any{uint64(7)}, fields[2])
+		assert.Equal(t, []any{[]byte{0x0a, 0x01}}, fields[3])
+		assert.NotContains(t, fields, protowire.Number(6))
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		t.Parallel()
+
+		responses := spec.BaseJSONResponses{
+			spec.NewJSONResponse(1, []encode.Value{{0x01}, {0x02}}, nil),
+			spec.NewJSONResponse(2, []encode.Value{}, nil),
+			spec.NewJSONResponse(3, map[string]any{"count": 300}, nil),
+			spec.NewJSONResponse(4, nil, spec.NewJSONError("failed", spec.ServerErrorCode)),
+		}
+
+		encoded, err := Marshal(FormatProtobuf, responses)
+		require.NoError(t, err)
+
+		batch := decodeProto(t, encoded)[1]
+		require.Len(t, batch, len(responses))
+
+		// Amino value lists, empty lists included
+		values := decodeProto(t, decodeProto(t, batch[0].([]byte))[4][0].([]byte))
+		assert.Equal(t, []any{[]byte{0x01}, []byte{0x02}}, values[1])
+
+		assert.Equal(t, []any{[]byte{}}, decodeProto(t, batch[1].([]byte))[4])
+
+		// JSON result
+		result := decodeProto(t, batch[2].([]byte))[5]
+		require.Len(t, result, 1)
+
+		assert.JSONEq(t, `{"count": 300}`, string(result[0].([]byte)))
+
+		// Error
+		errFields := decodeProto(t, decodeProto(t, batch[3].([]byte))[6][0].([]byte))
+
+		assert.Equal(t, spec.ServerErrorCode, int(int64(errFields[1][0].(uint64))))
+		assert.Equal(t, []any{[]byte("failed")}, errFields[2])
+		assert.JSONEq(t, `{"reason": "internal"}`, string(errFields[3][0].([]byte)))
+	})
+}
+
+func TestMarshal_JSON(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := Marshal(FormatJSON, spec.NewJSONResponse(1, encode.Value{0x0a, 0x01}, nil))
+	require.NoError(t, err)
+
+	var response struct {
+		Result string `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal(encoded, &response))
+
+	// The Amino values are base64 encoded
+	assert.Equal(t, "CgE=", response.Result)
+
+	// The missing values of the lists are null
+	encoded, err = Marshal(FormatJSON, spec.NewJSONResponse(2, []encode.Value{{0x0a, 0x01}, nil}, nil))
+	require.NoError(t, err)
+
+	var listResponse struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	require.NoError(t, json.Unmarshal(encoded, &listResponse))
+
+	assert.JSONEq(t, `["CgE=", null]`, string(listResponse.Result))
+}
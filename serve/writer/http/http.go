@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/gnolang/tx-indexer/serve/wire"
 	"github.com/gnolang/tx-indexer/serve/writer"
 	"go.uber.org/zap"
 )
@@ -13,20 +14,46 @@ var _ writer.ResponseWriter = (*ResponseWriter)(nil)
 type ResponseWriter struct {
 	logger *zap.Logger
 
-	w http.ResponseWriter
+	w      http.ResponseWriter
+	format wire.Format
 }
 
-func New(logger *zap.Logger, w http.ResponseWriter) ResponseWriter {
+func New(logger *zap.Logger, w http.ResponseWriter, format wire.Format) ResponseWriter {
 	return ResponseWriter{
 		logger: logger.Named("http-writer"),
 		w:      w,
+		format: format,
 	}
 }
 
 func (h ResponseWriter) WriteResponse(response any) {
-	if err := json.NewEncoder(h.w).Encode(response); err != nil {
+	if h.format == wire.FormatJSON {
+		if err := json.NewEncoder(h.w).Encode(response); err != nil {
+			h.logger.Info(
+				"unable to encode JSON response",
+				zap.Error(err),
+			)
+		}
+
+		return
+	}
+
+	encoded, err := wire.Marshal(h.format, response)
+	if err != nil {
+		h.logger.Error(
+			"unable to encode response",
+			zap.String("content_type", h.format.ContentType()),
+			zap.Error(err),
+		)
+
+		http.Error(h.w, "unable to encode response", http.StatusInternalServerError)
+
+		return
+	}
+
+	if _, err := h.w.Write(encoded); err != nil {
 		h.logger.Info(
-			"unable to encode JSON response",
+			"unable to write response",
 			zap.Error(err),
 		)
 	}
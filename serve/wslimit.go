@@ -0,0 +1,147 @@
+package serve
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWSPingPeriod is the default period
+	// of the pings sent to the WS clients
+	DefaultWSPingPeriod = 54 * time.Second
+
+	// DefaultWSIdleTimeout is the default time the WS connections are kept
+	// open without a pong from the client, before being closed
+	DefaultWSIdleTimeout = 60 * time.Second
+)
+
+var (
+	errWSConnLimit      = errors.New("too many WS connections")
+	errWSConnLimitPerIP = errors.New("too many WS connections from the IP")
+)
+
+// WSConnLimiter caps the number of open WS connections,
+// globally and per client IP. It can be shared by multiple servers,
+// for the limits to apply to all of them
+type WSConnLimiter struct {
+	perIP map[string]int
+
+	// trustProxies is a flag indicating if the client IPs are
+	// read from the X-Real-IP header, resolved by the IP filter
+	trustProxies bool
+
+	total    int
+	maxTotal int
+	maxPerIP int
+
+	mux sync.Mutex
+}
+
+// NewWSConnLimiter creates a new WS connection limiter, with the given global and per IP limits.
+// A 0 limit is unlimited. The client IPs are the request remote addresses, unless trustProxies is set,
+// in which case they're read from the X-Real-IP header, resolved by the IP filter from the trusted proxies
+func NewWSConnLimiter(maxConns, maxConnsPerIP int, trustProxies bool) *WSConnLimiter {
+	return &WSConnLimiter{
+		perIP:        make(map[string]int),
+		trustProxies: trustProxies,
+		maxTotal:     maxConns,
+		maxPerIP:     maxConnsPerIP,
+	}
+}
+
+// clientIP returns the client IP the connection of the request is accounted to
+func (l *WSConnLimiter) clientIP(r *http.Request) string {
+	return requestIP(r.RemoteAddr, r.Header, l.trustProxies)
+}
+
+// acquire reserves a connection for the IP,
+// if it's within the limits
+func (l *WSConnLimiter) acquire(ip string) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return errWSConnLimit
+	}
+
+	if l.maxPerIP > 0 && l.perIP[ip] >= l.maxPerIP {
+		return errWSConnLimitPerIP
+	}
+
+	l.total++
+	l.perIP[ip]++
+
+	return nil
+}
+
+// release releases the connection of the IP
+func (l *WSConnLimiter) release(ip string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.total--
+
+	l.perIP[ip]--
+	if l.perIP[ip] <= 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// admit reserves the connection of the WS request, writing the limit error response if it's over the limits.
+// Returns the release func of the admitted connection, and a flag indicating if it was admitted
+func (l *WSConnLimiter) admit(w http.ResponseWriter, r *http.Request, logger *zap.Logger) (func(), bool) {
+	ip := l.clientIP(r)
+
+	if err := l.acquire(ip); err != nil {
+		logger.Debug(
+			"WS connection rejected",
+			zap.String("from", ip),
+			zap.Error(err),
+		)
+
+		http.Error(w, err.Error(), limitStatus(err))
+
+		return nil, false
+	}
+
+	return func() {
+		l.release(ip)
+	}, true
+}
+
+// Middleware caps the WS connections of the handler, for the servers other than the JSON-RPC one
+// (ex. GraphQL). The WS connections are held until the handler returns, and the other requests are passed through
+func (l *WSConnLimiter) Middleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !websocket.IsWebSocketUpgrade(r) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			release, ok := l.admit(w, r, logger)
+			if !ok {
+				return
+			}
+
+			defer release()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitStatus returns the HTTP status of the limit error
+func limitStatus(err error) int {
+	if errors.Is(err, errWSConnLimitPerIP) {
+		return http.StatusTooManyRequests
+	}
+
+	return http.StatusServiceUnavailable
+}
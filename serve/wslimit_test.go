@@ -0,0 +1,177 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWSConnLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("per IP limit", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewWSConnLimiter(0, 2, false)
+
+		require.NoError(t, l.acquire("1.1.1.1"))
+		require.NoError(t, l.acquire("1.1.1.1"))
+
+		// Make sure the IP is capped, but not the others
+		assert.ErrorIs(t, l.acquire("1.1.1.1"), errWSConnLimitPerIP)
+		assert.NoError(t, l.acquire("2.2.2.2"))
+
+		// Make sure the released connections free up the IP
+		l.release("1.1.1.1")
+
+		assert.NoError(t, l.acquire("1.1.1.1"))
+	})
+
+	t.Run("global limit", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewWSConnLimiter(2, 0, false)
+
+		require.NoError(t, l.acquire("1.1.1.1"))
+		require.NoError(t, l.acquire("2.2.2.2"))
+
+		assert.ErrorIs(t, l.acquire("3.3.3.3"), errWSConnLimit)
+
+		l.release("2.2.2.2")
+
+		assert.NoError(t, l.acquire("3.3.3.3"))
+		assert.Len(t, l.perIP, 2)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		l := NewWSConnLimiter(0, 0, false)
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.acquire("1.1.1.1"))
+		}
+	})
+}
+
+func TestWSConnLimiter_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	r.RemoteAddr = "1.1.1.1:1234"
+	r.Header.Set(headerRealIP, "2.2.2.2")
+
+	// Make sure the client headers are ignored, unless resolved by the IP filter
+	assert.Equal(t, "1.1.1.1", NewWSConnLimiter(0, 1, false).clientIP(r))
+	assert.Equal(t, "2.2.2.2", NewWSConnLimiter(0, 1, true).clientIP(r))
+}
+
+func TestWSConnLimiter_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		l = NewWSConnLimiter(0, 1, false)
+
+		heldCh    = make(chan struct{})
+		releaseCh = make(chan struct{})
+	)
+
+	// The held requests are served until released, the same as the WS connections
+	handler := l.Middleware(zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Hold") != "" {
+			close(heldCh)
+			<-releaseCh
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(upgrade, hold bool) int {
+		r := httptest.NewRequest(http.MethodGet, "/graphql/query", nil)
+		r.RemoteAddr = "1.1.1.1:1234"
+
+		if upgrade {
+			r.Header.Set("Connection", "upgrade")
+			r.Header.Set("Upgrade", "websocket")
+		}
+
+		if hold {
+			r.Header.Set("X-Hold", "true")
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w.Code
+	}
+
+	// Hold the only allowed connection
+	doneCh := make(chan struct{})
+
+	go func() {
+		defer close(doneCh)
+
+		request(true, true)
+	}()
+
+	<-heldCh
+
+	// Make sure the next connection is rejected, and the other requests are passed through
+	assert.Equal(t, http.StatusTooManyRequests, request(true, false))
+	assert.Equal(t, http.StatusOK, request(false, false))
+
+	// Make sure the connection is released once the handler returns
+	close(releaseCh)
+	<-doneCh
+
+	assert.Equal(t, http.StatusOK, request(true, false))
+}
+
+func TestWS_ConnLimit(t *testing.T) {
+	t.Parallel()
+
+	s := setupTestWebServer(t, func(s *JSONRPC) {
+		s.wsLimiter = NewWSConnLimiter(0, 1, false)
+	})
+	defer s.stop()
+
+	url := strings.Replace(s.address(), "http://", "ws://", 1) + "/ws"
+
+	// Open the only allowed connection
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+
+	// Make sure the next connection is rejected
+	rejected, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if rejected != nil {
+		_ = rejected.Close()
+	}
+
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	_ = resp.Body.Close()
+
+	// Make sure the connection is accepted once the previous one is closed
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		next, _, dialErr := websocket.DefaultDialer.Dial(url, nil)
+		if dialErr != nil {
+			return false
+		}
+
+		_ = next.Close()
+
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
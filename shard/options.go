@@ -0,0 +1,25 @@
+package shard
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+type Option func(r *Router)
+
+// WithLogger sets the logger to be used
+// with the router
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Router) {
+		r.logger = logger
+	}
+}
+
+// WithHTTPClient sets the HTTP client
+// used for querying the shards
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Router) {
+		r.client = client
+	}
+}
@@ -0,0 +1,871 @@
+package shard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// maxRequestSize is the maximum size of the routed request body
+const maxRequestSize = 1 << 20
+
+var (
+	errNoShards          = errors.New("no shards configured")
+	errOverlappingShards = errors.New("shard height ranges overlap")
+	errOpenShard         = errors.New("only the latest shard can index up to the chain tip")
+	errInvalidCursor     = errors.New("invalid router cursor")
+)
+
+// forwardedHeaders are the request headers forwarded to the shards,
+// carrying the caller credentials and the client IP
+var forwardedHeaders = []string{
+	"X-API-Key",
+	"X-Admin-Token",
+	"X-Admin-Actor",
+	"X-Real-IP",
+	"User-Agent",
+}
+
+// headerForwardedFor is the header of the forwarded client IPs,
+// appended with the address of the routed request
+const headerForwardedFor = "X-Forwarded-For"
+
+// heightMethods are the methods whose first param is the queried height,
+// routed to the shard of the height
+var heightMethods = map[string]struct{}{
+	"getBlock":        {},
+	"getTxResult":     {},
+	"getValidators":   {},
+	"getBlockSigning": {},
+}
+
+// hashMethods are the methods querying a tx by hash,
+// routed to all shards until the tx is found
+var hashMethods = map[string]struct{}{
+	"getTxResultByHash": {},
+	"getTxProof":        {},
+	"getTxSigners":      {},
+}
+
+// batchHashMethods are the methods querying a list of txs by hash,
+// routed to all shards, with each tx taken from the shard it's found on
+var batchHashMethods = map[string]struct{}{
+	"getTxsByHashes": {},
+}
+
+// rangeMethod is the param layout of a height range method
+type rangeMethod struct {
+	// index is the param index of the range start
+	index int
+
+	// ordering is the param index of the (optional) result ordering, -1 if none
+	ordering int
+
+	// cursor is the param index of the (optional) page cursor, -1 for the list results
+	cursor int
+
+	// field is the result field of the page items, if paginated
+	field string
+}
+
+// rangeMethods are the methods whose params contain the queried height range,
+// routed to the overlapping shards, in height order (or the reverse one, for the newest first ordering).
+// The list results are concatenated, while the pages span the shards (see aggregatePages)
+var rangeMethods = map[string]rangeMethod{
+	"getTxsByAddress":     {index: 1, ordering: 3, cursor: 4, field: "txs"},
+	"getBlockHeaders":     {index: 0, ordering: 2, cursor: -1},
+	"getValidatorChanges": {index: 0, ordering: -1, cursor: -1},
+}
+
+// pagedMethod is the param and result layout of a cursor paginated method
+type pagedMethod struct {
+	// index is the param index of the pagination object
+	index int
+
+	// field is the result field of the page items
+	field string
+}
+
+// pagedMethods are the methods paginating over the whole indexed history, oldest first (or newest first),
+// routed to the shards in height order (or the reverse one, for the newest first ordering). The router cursor points to the shard of the next page
+// (and to the shard cursor, see routerCursor), so the pages span the shards
+var pagedMethods = map[string]pagedMethod{
+	"getTxsBySigner": {index: 1, field: "txs"},
+	"getTxsByPubKey": {index: 1, field: "txs"},
+	"getTxsByError":  {index: 1, field: "txs"},
+	"getEvents":      {index: 2, field: "events"},
+}
+
+// seriesMethods are the time series methods, routed to all shards. The values
+// of the buckets returned by multiple shards (spanning the shard boundaries) are summed
+var seriesMethods = map[string]struct{}{
+	"getBlockCountSeries":    {},
+	"getTxCountSeries":       {},
+	"getGasSeries":           {},
+	"getFeeSeries":           {},
+	"getActiveAddressSeries": {},
+}
+
+// tipMethods are the methods served by the latest shard, which follows the chain tip:
+// the chain status, the statistics of the latest blocks, the plugin state and the admin methods.
+// The unknown (custom) methods are served by the latest shard as well
+var tipMethods = map[string]struct{}{
+	"getStatus":            {},
+	"queryTxs":             {},
+	"searchTxs":            {},
+	"getTokenTransfers":    {},
+	"getTokenHolders":      {},
+	"getAccountHistory":    {},
+	"getAccountBalanceAt":  {},
+	"getGasStats":          {},
+	"getFeeStats":          {},
+	"getErrorStats":        {},
+	"getChainStats":        {},
+	"getTopAccounts":       {},
+	"getRealmStats":        {},
+	"getTopRealms":         {},
+	"getValidatorStats":    {},
+	"watchAddresses":       {},
+	"unwatchAddresses":     {},
+	"subscribe":            {},
+	"unsubscribe":          {},
+	"newBlockFilter":       {},
+	"newTransactionFilter": {},
+	"getFilterChanges":     {},
+	"uninstallFilter":      {},
+}
+
+// Shard is an indexer instance, indexing a height range of the chain history
+type Shard struct {
+	// URL is the JSON-RPC URL of the shard indexer
+	URL string
+
+	// From is the first indexed height
+	From uint64
+
+	// To is the last indexed height, 0 for the latest shard,
+	// following the chain tip
+	To uint64
+}
+
+// contains returns a flag indicating if the shard indexes the height
+func (s Shard) contains(height uint64) bool {
+	return height >= s.From && (s.To == 0 || height <= s.To)
+}
+
+// overlaps returns a flag indicating if the shard indexes any height
+// of the range. A 0 range end means the chain tip
+func (s Shard) overlaps(from, to uint64) bool {
+	return (to == 0 || to >= s.From) && (s.To == 0 || from <= s.To)
+}
+
+// Router is the JSON-RPC router of the sharded indexer, which routes
+// the queries to the shards indexing the queried heights, and aggregates
+// the results. The queries not tied to heights are routed to the latest
+// shard, which follows the chain tip
+type Router struct {
+	client *http.Client
+	logger *zap.Logger
+
+	shards []Shard
+}
+
+// NewRouter creates a new router over the given shards,
+// whose height ranges can't overlap
+func NewRouter(shards []Shard, opts ...Option) (*Router, error) {
+	if len(shards) == 0 {
+		return nil, errNoShards
+	}
+
+	sorted := make([]Shard, len(shards))
+	copy(sorted, shards)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].From < sorted[j].From
+	})
+
+	for i, s := range sorted[:len(sorted)-1] {
+		if s.To == 0 {
+			return nil, errOpenShard
+		}
+
+		if s.To >= sorted[i+1].From {
+			return nil, fmt.Errorf("%w, %s and %s", errOverlappingShards, s.URL, sorted[i+1].URL)
+		}
+	}
+
+	r := &Router{
+		client: http.DefaultClient,
+		logger: zap.NewNop(),
+		shards: sorted,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST requests are routed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "unable to read request", http.StatusBadRequest)
+
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	var (
+		header   = shardHeader(req)
+		response any
+	)
+
+	if len(body) > 0 && body[0] == '[' {
+		var requests spec.BaseJSONRequests
+
+		if err := json.Unmarshal(body, &requests); err != nil {
+			response = spec.NewJSONResponse(0, nil, spec.NewJSONError(err.Error(), spec.ParseErrorCode))
+		} else {
+			responses := make(spec.BaseJSONResponses, len(requests))
+
+			for i, request := range requests {
+				responses[i] = r.route(req.Context(), header, request)
+			}
+
+			response = responses
+		}
+	} else {
+		var request *spec.BaseJSONRequest
+
+		if err := json.Unmarshal(body, &request); err != nil || request == nil {
+			response = spec.NewJSONResponse(0, nil, spec.NewJSONError("invalid request", spec.ParseErrorCode))
+		} else {
+			response = r.route(req.Context(), header, request)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		r.logger.Error("unable to write response", zap.Error(err))
+	}
+}
+
+// shardHeader returns the headers of the request forwarded to the shards. The remote address
+// of the request is appended to the forwarded client IPs, as by the proxies
+func shardHeader(req *http.Request) http.Header {
+	header := http.Header{}
+
+	for _, name := range forwardedHeaders {
+		if value := req.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+
+	remote, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		remote = req.RemoteAddr
+	}
+
+	forwarded := remote
+	if prior := req.Header.Get(headerForwardedFor); prior != "" {
+		forwarded = prior + ", " + remote
+	}
+
+	header.Set(headerForwardedFor, forwarded)
+
+	return header
+}
+
+// route routes the request to the shards indexing the queried heights
+func (r *Router) route(ctx context.Context, header http.Header, req *spec.BaseJSONRequest) *spec.BaseJSONResponse {
+	if _, ok := heightMethods[req.Method]; ok && len(req.Params) > 0 {
+		height, err := toUint64(req.Params[0])
+		if err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(1))
+		}
+
+		for _, s := range r.shards {
+			if s.contains(height) {
+				return r.forward(ctx, header, s, req)
+			}
+		}
+
+		// The height isn't indexed by any shard
+		return spec.NewJSONResponse(req.ID, nil, nil)
+	}
+
+	if _, ok := hashMethods[req.Method]; ok {
+		return r.find(ctx, header, req)
+	}
+
+	if _, ok := batchHashMethods[req.Method]; ok {
+		return r.findEach(ctx, header, req)
+	}
+
+	if method, ok := rangeMethods[req.Method]; ok {
+		return r.aggregate(ctx, header, req, method)
+	}
+
+	if method, ok := pagedMethods[req.Method]; ok {
+		return r.paginate(ctx, header, req, method)
+	}
+
+	if _, ok := seriesMethods[req.Method]; ok {
+		return r.mergeSeries(ctx, header, req)
+	}
+
+	return r.forward(ctx, header, r.shards[len(r.shards)-1], req)
+}
+
+// find routes the request to all shards, returning the first found result
+func (r *Router) find(ctx context.Context, header http.Header, req *spec.BaseJSONRequest) *spec.BaseJSONResponse {
+	responses := r.forwardAll(ctx, header, req)
+
+	for _, response := range responses {
+		if response.Error == nil && !isNull(response.Result) {
+			return response
+		}
+	}
+
+	// Not found, or failed on all shards
+	for _, response := range responses {
+		if response.Error != nil {
+			return response
+		}
+	}
+
+	return responses[0]
+}
+
+// findEach routes the request to all shards, merging the list results
+// by taking each item from the first shard it's found on
+func (r *Router) findEach(ctx context.Context, header http.Header, req *spec.BaseJSONRequest) *spec.BaseJSONResponse {
+	var results []json.RawMessage
+
+	for _, response := range r.forwardAll(ctx, header, req) {
+		if response.Error != nil {
+			// The items missing on the failed shard can't be told apart
+			return response
+		}
+
+		shardResults, err := listResults(response)
+		if err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateResponseError(err))
+		}
+
+		if results == nil {
+			results = shardResults
+
+			continue
+		}
+
+		for i, result := range shardResults {
+			if i < len(results) && isNull(results[i]) {
+				results[i] = result
+			}
+		}
+	}
+
+	if results == nil {
+		results = make([]json.RawMessage, 0)
+	}
+
+	return spec.NewJSONResponse(req.ID, results, nil)
+}
+
+// forwardAll forwards the request to all shards concurrently, returning their responses
+func (r *Router) forwardAll(
+	ctx context.Context,
+	header http.Header,
+	req *spec.BaseJSONRequest,
+) []*spec.BaseJSONResponse {
+	var (
+		responses = make([]*spec.BaseJSONResponse, len(r.shards))
+		wg        sync.WaitGroup
+	)
+
+	for i, s := range r.shards {
+		wg.Add(1)
+
+		go func(i int, s Shard) {
+			defer wg.Done()
+
+			responses[i] = r.forward(ctx, header, s, req)
+		}(i, s)
+	}
+
+	wg.Wait()
+
+	return responses
+}
+
+// aggregate routes the request to the shards overlapping the queried height range,
+// concatenating the list results in height order (or the reverse one, for the newest first ordering)
+func (r *Router) aggregate(
+	ctx context.Context,
+	header http.Header,
+	req *spec.BaseJSONRequest,
+	method rangeMethod,
+) *spec.BaseJSONResponse {
+	var (
+		from, to uint64
+		err      error
+	)
+
+	// The optional params can be null, for setting the following ones
+	if len(req.Params) > method.index && req.Params[method.index] != nil {
+		if from, err = toUint64(req.Params[method.index]); err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.index+1))
+		}
+	}
+
+	if len(req.Params) > method.index+1 && req.Params[method.index+1] != nil {
+		if to, err = toUint64(req.Params[method.index+1]); err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.index+2))
+		}
+	}
+
+	shards := make([]Shard, 0, len(r.shards))
+
+	for _, s := range r.shards {
+		if s.overlaps(from, to) {
+			shards = append(shards, s)
+		}
+	}
+
+	if method.ordering >= 0 && len(req.Params) > method.ordering && isDescending(req.Params[method.ordering]) {
+		slices.Reverse(shards)
+	}
+
+	if method.cursor >= 0 {
+		return r.aggregatePages(ctx, header, req, method, shards)
+	}
+
+	results := make([]json.RawMessage, 0)
+
+	for _, s := range shards {
+		response := r.forward(ctx, header, s, req)
+		if response.Error != nil {
+			return response
+		}
+
+		shardResults, err := listResults(response)
+		if err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateResponseError(err))
+		}
+
+		results = append(results, shardResults...)
+	}
+
+	return spec.NewJSONResponse(req.ID, results, nil)
+}
+
+// aggregatePages routes the paginated range request to the overlapping shards in order,
+// starting from the shard of the router cursor. The shards are queried until one returns any item,
+// with the page cursor pointing to the shard of the next page (and to the shard cursor, see routerCursor)
+func (r *Router) aggregatePages(
+	ctx context.Context,
+	header http.Header,
+	req *spec.BaseJSONRequest,
+	method rangeMethod,
+	shards []Shard,
+) *spec.BaseJSONResponse {
+	var cursor string
+
+	if len(req.Params) > method.cursor && req.Params[method.cursor] != nil {
+		var ok bool
+
+		if cursor, ok = req.Params[method.cursor].(string); !ok {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.cursor+1))
+		}
+	}
+
+	shard, shardCursor, err := parseRouterCursor(cursor, max(len(shards), 1))
+	if err != nil {
+		return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.cursor+1))
+	}
+
+	var (
+		items = make([]json.RawMessage, 0)
+		next  string
+	)
+
+	for ; shard < len(shards); shard++ {
+		params := slices.Clone(req.Params)
+		for len(params) <= method.cursor {
+			params = append(params, nil)
+		}
+
+		params[method.cursor] = shardCursor
+
+		response := r.forward(ctx, header, shards[shard], spec.NewJSONRequest(req.ID, req.Method, params))
+		if response.Error != nil {
+			return response
+		}
+
+		shardItems, pageCursor, err := pageResults(response, method.field)
+		if err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateResponseError(err))
+		}
+
+		items = append(items, shardItems...)
+
+		if pageCursor != "" {
+			// The shard has more items
+			next = routerCursor(shard, pageCursor)
+
+			break
+		}
+
+		shardCursor = ""
+
+		if len(items) > 0 {
+			// The page continues on the next shard
+			if shard+1 < len(shards) {
+				next = routerCursor(shard+1, "")
+			}
+
+			break
+		}
+	}
+
+	page := map[string]any{
+		method.field: items,
+	}
+
+	if next != "" {
+		page["cursor"] = next
+	}
+
+	return spec.NewJSONResponse(req.ID, page, nil)
+}
+
+// isDescending checks if the ordering param sets the newest first order
+func isDescending(param any) bool {
+	ordering, ok := param.(map[string]any)
+
+	return ok && ordering["order"] == "desc"
+}
+
+// paginate routes the request to the shards in height order (or the reverse one, for the newest first ordering),
+// starting from the shard of the router cursor. The shards are queried until the page is filled (or, without a page limit, until a shard returns any item),
+// with the page cursor pointing to the shard of the next page
+func (r *Router) paginate(
+	ctx context.Context,
+	header http.Header,
+	req *spec.BaseJSONRequest,
+	method pagedMethod,
+) *spec.BaseJSONResponse {
+	pagination := make(map[string]any)
+
+	if len(req.Params) > method.index {
+		var ok bool
+
+		if pagination, ok = req.Params[method.index].(map[string]any); !ok {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.index+1))
+		}
+	}
+
+	var (
+		cursor, _ = pagination["cursor"].(string)
+		limit     int
+	)
+
+	if value, ok := pagination["limit"]; ok {
+		parsed, err := strconv.Atoi(fmt.Sprintf("%v", value))
+		if err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.index+1))
+		}
+
+		limit = max(parsed, 0)
+	}
+
+	shards := slices.Clone(r.shards)
+	if isDescending(pagination) {
+		slices.Reverse(shards)
+	}
+
+	shard, shardCursor, err := parseRouterCursor(cursor, len(shards))
+	if err != nil {
+		return spec.NewJSONResponse(req.ID, nil, spec.GenerateInvalidParamError(method.index+1))
+	}
+
+	var (
+		items = make([]json.RawMessage, 0)
+		next  string
+	)
+
+	for ; shard < len(shards); shard++ {
+		shardPagination := maps.Clone(pagination)
+		shardPagination["cursor"] = shardCursor
+
+		if limit > 0 {
+			shardPagination["limit"] = limit - len(items)
+		}
+
+		params := slices.Clone(req.Params)
+		for len(params) <= method.index {
+			params = append(params, nil)
+		}
+
+		params[method.index] = shardPagination
+
+		response := r.forward(ctx, header, shards[shard], spec.NewJSONRequest(req.ID, req.Method, params))
+		if response.Error != nil {
+			return response
+		}
+
+		shardItems, pageCursor, err := pageResults(response, method.field)
+		if err != nil {
+			return spec.NewJSONResponse(req.ID, nil, spec.GenerateResponseError(err))
+		}
+
+		items = append(items, shardItems...)
+
+		if pageCursor != "" {
+			// The shard has more items
+			next = routerCursor(shard, pageCursor)
+
+			break
+		}
+
+		shardCursor = ""
+
+		if (limit > 0 && len(items) >= limit) || (limit == 0 && len(items) > 0) {
+			// The page is filled, and continues on the next shard
+			if shard+1 < len(shards) {
+				next = routerCursor(shard+1, "")
+			}
+
+			break
+		}
+	}
+
+	page := map[string]any{
+		method.field: items,
+	}
+
+	if next != "" {
+		page["cursor"] = next
+	}
+
+	return spec.NewJSONResponse(req.ID, page, nil)
+}
+
+// pageResults decodes the items (under the field) and the next page cursor of the shard page
+func pageResults(response *spec.BaseJSONResponse, field string) ([]json.RawMessage, string, error) {
+	var page map[string]json.RawMessage
+
+	if raw, ok := response.Result.(json.RawMessage); ok && !isNull(raw) {
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var (
+		items  []json.RawMessage
+		cursor string
+	)
+
+	if raw, ok := page[field]; ok && !isNull(raw) {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if raw, ok := page["cursor"]; ok && !isNull(raw) {
+		if err := json.Unmarshal(raw, &cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return items, cursor, nil
+}
+
+// routerCursor returns the router cursor of the shard page
+func routerCursor(shard int, cursor string) string {
+	return strconv.Itoa(shard) + ":" + cursor
+}
+
+// parseRouterCursor parses the router cursor, returning the shard and its page cursor.
+// An empty cursor points to the first page of the first shard
+func parseRouterCursor(cursor string, shards int) (int, string, error) {
+	if cursor == "" {
+		return 0, "", nil
+	}
+
+	index, shardCursor, ok := strings.Cut(cursor, ":")
+	if !ok {
+		return 0, "", errInvalidCursor
+	}
+
+	shard, err := strconv.Atoi(index)
+	if err != nil || shard < 0 || shard >= shards {
+		return 0, "", errInvalidCursor
+	}
+
+	return shard, shardCursor, nil
+}
+
+// seriesPoint is a time series bucket, as returned by the shards
+type seriesPoint struct {
+	Time  time.Time `json:"time"`
+	Value uint64    `json:"value"`
+}
+
+// mergeSeries routes the request to all shards, merging the time series buckets.
+// The bucket values returned by multiple shards are summed, so the active addresses
+// of a bucket spanning the shard boundary can be counted on both shards
+func (r *Router) mergeSeries(ctx context.Context, header http.Header, req *spec.BaseJSONRequest) *spec.BaseJSONResponse {
+	// The buckets are keyed by their start instant, regardless of the time zone
+	buckets := make(map[int64]*seriesPoint)
+
+	for _, response := range r.forwardAll(ctx, header, req) {
+		if response.Error != nil {
+			return response
+		}
+
+		var points []*seriesPoint
+
+		if raw, ok := response.Result.(json.RawMessage); ok && !isNull(raw) {
+			if err := json.Unmarshal(raw, &points); err != nil {
+				return spec.NewJSONResponse(req.ID, nil, spec.GenerateResponseError(err))
+			}
+		}
+
+		for _, point := range points {
+			if bucket, ok := buckets[point.Time.UnixNano()]; ok {
+				bucket.Value += point.Value
+
+				continue
+			}
+
+			buckets[point.Time.UnixNano()] = point
+		}
+	}
+
+	points := make([]*seriesPoint, 0, len(buckets))
+
+	for _, point := range buckets {
+		points = append(points, point)
+	}
+
+	slices.SortFunc(points, func(a, b *seriesPoint) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return spec.NewJSONResponse(req.ID, points, nil)
+}
+
+// listResults decodes the list result of the shard response
+func listResults(response *spec.BaseJSONResponse) ([]json.RawMessage, error) {
+	var results []json.RawMessage
+
+	if raw, ok := response.Result.(json.RawMessage); ok && !isNull(raw) {
+		if err := json.Unmarshal(raw, &results); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// forward forwards the request to the shard, returning its response
+func (r *Router) forward(
+	ctx context.Context,
+	header http.Header,
+	s Shard,
+	req *spec.BaseJSONRequest,
+) *spec.BaseJSONResponse {
+	result, jsonErr, err := r.call(ctx, header, s, req)
+	if err != nil {
+		r.logger.Error(
+			"unable to forward request",
+			zap.String("shard", s.URL),
+			zap.String("method", req.Method),
+			zap.Error(err),
+		)
+
+		return spec.NewJSONResponse(req.ID, nil, spec.GenerateResponseError(err))
+	}
+
+	return spec.NewJSONResponse(req.ID, result, jsonErr)
+}
+
+func (r *Router) call(
+	ctx context.Context,
+	header http.Header,
+	s Shard,
+	req *spec.BaseJSONRequest,
+) (json.RawMessage, *spec.BaseJSONError, error) {
+	body, err := json.Marshal(spec.NewJSONRequest(req.ID, req.Method, req.Params))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpReq.Header = header.Clone()
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to query shard, %w", err)
+	}
+
+	defer httpResp.Body.Close()
+
+	var response struct {
+		Error  *spec.BaseJSONError `json:"error"`
+		Result json.RawMessage     `json:"result"`
+	}
+
+	if err := json.NewDecoder(httpResp.Body).Decode(&response); err != nil {
+		return nil, nil, fmt.Errorf("unable to decode shard response, %w", err)
+	}
+
+	return response.Result, response.Error, nil
+}
+
+// isNull returns a flag indicating if the raw result is empty
+func isNull(result any) bool {
+	if result == nil {
+		return true
+	}
+
+	raw, ok := result.(json.RawMessage)
+
+	return ok && (len(raw) == 0 || string(raw) == "null")
+}
+
+func toUint64(data any) (uint64, error) {
+	return strconv.ParseUint(fmt.Sprintf("%v", data), 10, 64)
+}
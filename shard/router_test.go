@@ -0,0 +1,715 @@
+package shard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/serve/spec"
+)
+
+// mockShard is a shard indexer, answering with the given handler
+type mockShard struct {
+	handleFn func(req *spec.BaseJSONRequest) (any, *spec.BaseJSONError)
+
+	methods []string
+	headers []http.Header
+	mux     sync.Mutex
+}
+
+func (m *mockShard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req *spec.BaseJSONRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	m.mux.Lock()
+	m.methods = append(m.methods, req.Method)
+	m.headers = append(m.headers, r.Header.Clone())
+	m.mux.Unlock()
+
+	result, jsonErr := m.handleFn(req)
+
+	_ = json.NewEncoder(w).Encode(spec.NewJSONResponse(req.ID, result, jsonErr))
+}
+
+func (m *mockShard) requestHeaders() []http.Header {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.headers
+}
+
+func (m *mockShard) calls() []string {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.methods
+}
+
+// newShards starts the shard servers of the given height ranges,
+// answering the height and range queries with the queried shard range
+func newShards(t *testing.T, ranges ...[2]uint64) ([]Shard, []*mockShard) {
+	t.Helper()
+
+	var (
+		shards = make([]Shard, 0, len(ranges))
+		mocks  = make([]*mockShard, 0, len(ranges))
+	)
+
+	for _, r := range ranges {
+		name := fmt.Sprintf("%d-%d", r[0], r[1])
+
+		m := &mockShard{
+			handleFn: func(req *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+				switch req.Method {
+				case "getValidatorChanges", "getBlockHeaders":
+					return []string{name}, nil
+				case "getTxsByAddress":
+					return map[string]any{"txs": []string{name}}, nil
+				case "getTxResultByHash":
+					return nil, nil
+				default:
+					return name, nil
+				}
+			},
+		}
+
+		srv := httptest.NewServer(m)
+		t.Cleanup(srv.Close)
+
+		shards = append(shards, Shard{URL: srv.URL, From: r[0], To: r[1]})
+		mocks = append(mocks, m)
+	}
+
+	return shards, mocks
+}
+
+// query sends the JSON-RPC request to the router
+func query(t *testing.T, r *Router, req any) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	return rec.Body.Bytes()
+}
+
+type response struct {
+	Error  *spec.BaseJSONError `json:"error"`
+	Result json.RawMessage     `json:"result"`
+}
+
+func decodeResponse(t *testing.T, data []byte) response {
+	t.Helper()
+
+	var resp response
+
+	require.NoError(t, json.Unmarshal(data, &resp))
+
+	return resp
+}
+
+func TestNewRouter_InvalidShards(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		err    error
+		shards []Shard
+	}{
+		{
+			"no shards",
+			errNoShards,
+			nil,
+		},
+		{
+			"overlapping shards",
+			errOverlappingShards,
+			[]Shard{
+				{URL: "a", From: 0, To: 100},
+				{URL: "b", From: 100},
+			},
+		},
+		{
+			"multiple tip shards",
+			errOpenShard,
+			[]Shard{
+				{URL: "a", From: 0},
+				{URL: "b", From: 100},
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewRouter(testCase.shards)
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
+
+func TestRouter_HeightQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, _ := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 199}, [2]uint64{200, 0})
+
+	// Shuffled, to make sure the shards are ordered by height
+	r, err := NewRouter([]Shard{shards[2], shards[0], shards[1]})
+	require.NoError(t, err)
+
+	testTable := []struct {
+		expected string
+		height   uint64
+	}{
+		{`"0-99"`, 0},
+		{`"0-99"`, 99},
+		{`"100-199"`, 100},
+		{`"200-0"`, 200},
+		{`"200-0"`, 100000},
+	}
+
+	for _, testCase := range testTable {
+		resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getBlock", []any{testCase.height})))
+
+		require.Nil(t, resp.Error)
+		assert.JSONEq(t, testCase.expected, string(resp.Result))
+	}
+}
+
+func TestRouter_UnindexedHeight(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{100, 199})
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getTxResult", []any{10, 0})))
+
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "null", string(resp.Result))
+
+	// Make sure no shard was queried
+	assert.Empty(t, mocks[0].calls())
+}
+
+func TestRouter_HashQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 199}, [2]uint64{200, 0})
+
+	// Only the middle shard indexes the tx
+	mocks[1].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return "found", nil
+	}
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getTxResultByHash", []any{"hash"})))
+
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `"found"`, string(resp.Result))
+
+	// Make sure all shards were queried
+	for _, m := range mocks {
+		assert.Equal(t, []string{"getTxResultByHash"}, m.calls())
+	}
+}
+
+func TestRouter_BatchHashQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 199}, [2]uint64{200, 0})
+
+	// Each shard indexes some of the txs
+	mocks[0].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return []any{"tx 1", nil, nil}, nil
+	}
+
+	mocks[1].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return []any{nil, nil, "tx 3"}, nil
+	}
+
+	mocks[2].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return []any{nil, nil, nil}, nil
+	}
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	params := []any{[]any{"hash 1", "hash 2", "hash 3"}}
+
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getTxsByHashes", params)))
+
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `["tx 1", null, "tx 3"]`, string(resp.Result))
+
+	// Make sure all shards were queried
+	for _, m := range mocks {
+		assert.Equal(t, []string{"getTxsByHashes"}, m.calls())
+	}
+
+	// Make sure the shard errors are returned
+	mocks[2].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return nil, spec.NewJSONError("shard error", spec.ServerErrorCode)
+	}
+
+	resp = decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getTxsByHashes", params)))
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "shard error", resp.Error.Message)
+}
+
+func TestRouter_RangeQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 199}, [2]uint64{200, 0})
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name     string
+		expected string
+		params   []any
+	}{
+		{
+			"whole chain",
+			`["0-99", "100-199", "200-0"]`,
+			[]any{"address"},
+		},
+		{
+			"single shard",
+			`["100-199"]`,
+			[]any{"address", 120, 150},
+		},
+		{
+			"range to the tip",
+			`["100-199", "200-0"]`,
+			[]any{"address", 150},
+		},
+		{
+			"range across shards",
+			`["0-99", "100-199"]`,
+			[]any{"address", 50, 199},
+		},
+		{
+			"newest first",
+			`["100-199", "0-99"]`,
+			[]any{"address", 50, 199, map[string]any{"order": "desc"}},
+		},
+	}
+
+	// queryPages fetches all the pages of the shard txs
+	queryPages := func(params []any) []string {
+		t.Helper()
+
+		var (
+			txs    = make([]string, 0)
+			cursor = ""
+		)
+
+		for range 5 {
+			pageParams := slices.Clone(params)
+			for len(pageParams) < 5 {
+				pageParams = append(pageParams, nil)
+			}
+
+			pageParams[4] = cursor
+
+			resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getTxsByAddress", pageParams)))
+			require.Nil(t, resp.Error)
+
+			var page struct {
+				Cursor string   `json:"cursor"`
+				Txs    []string `json:"txs"`
+			}
+
+			require.NoError(t, json.Unmarshal(resp.Result, &page))
+
+			txs = append(txs, page.Txs...)
+
+			if cursor = page.Cursor; cursor == "" {
+				break
+			}
+		}
+
+		return txs
+	}
+
+	// Make sure the pages span the overlapping shards
+	for _, testCase := range testTable {
+		txs, err := json.Marshal(queryPages(testCase.params))
+		require.NoError(t, err)
+
+		assert.JSONEq(t, testCase.expected, string(txs), testCase.name)
+	}
+
+	// Make sure the ranges starting at the first param are routed
+	blockTestTable := []struct {
+		name     string
+		method   string
+		expected string
+		params   []any
+	}{
+		{
+			"validator changes to the tip",
+			"getValidatorChanges",
+			`["100-199", "200-0"]`,
+			[]any{150},
+		},
+		{
+			"block headers across shards",
+			"getBlockHeaders",
+			`["0-99", "100-199"]`,
+			[]any{50, 150},
+		},
+		{
+			"block headers to the tip",
+			"getBlockHeaders",
+			`["100-199", "200-0"]`,
+			[]any{150, 0},
+		},
+		{
+			"block headers newest first",
+			"getBlockHeaders",
+			`["200-0", "100-199", "0-99"]`,
+			[]any{0, 0, map[string]any{"order": "desc"}},
+		},
+	}
+
+	for _, testCase := range blockTestTable {
+		resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, testCase.method, testCase.params)))
+
+		require.Nil(t, resp.Error, testCase.name)
+		assert.JSONEq(t, testCase.expected, string(resp.Result), testCase.name)
+	}
+
+	// Make sure the shard page cursors are followed
+	mocks[1].handleFn = func(req *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		if req.Params[4] == "next" {
+			return map[string]any{"txs": []string{"100-199 next"}}, nil
+		}
+
+		return map[string]any{"txs": []string{"100-199"}, "cursor": "next"}, nil
+	}
+
+	assert.Equal(t, []string{"0-99", "100-199", "100-199 next", "200-0"}, queryPages([]any{"address"}))
+
+	// Make sure the shard errors are returned
+	mocks[1].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return nil, spec.NewJSONError("shard error", spec.ServerErrorCode)
+	}
+
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getBlockHeaders", []any{0})))
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "shard error", resp.Error.Message)
+
+	resp = decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getTxsByAddress", []any{"address", 120})))
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "shard error", resp.Error.Message)
+}
+
+func TestRouter_TipQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 0})
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(1, "getStatus", nil)))
+
+	require.Nil(t, resp.Error)
+	assert.JSONEq(t, `"100-0"`, string(resp.Result))
+
+	assert.Empty(t, mocks[0].calls())
+	assert.Equal(t, []string{"getStatus"}, mocks[1].calls())
+}
+
+func TestRouter_Batch(t *testing.T) {
+	t.Parallel()
+
+	shards, _ := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 0})
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	requests := spec.BaseJSONRequests{
+		spec.NewJSONRequest(1, "getBlock", []any{10}),
+		spec.NewJSONRequest(2, "getBlock", []any{110}),
+	}
+
+	var responses []response
+
+	require.NoError(t, json.Unmarshal(query(t, r, requests), &responses))
+	require.Len(t, responses, len(requests))
+
+	assert.JSONEq(t, `"0-99"`, string(responses[0].Result))
+	assert.JSONEq(t, `"100-0"`, string(responses[1].Result))
+}
+
+func TestRouter_ForwardedHeaders(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 0})
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	body, err := json.Marshal(spec.NewJSONRequest(1, "getTxResultByHash", []any{"hash"}))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.RemoteAddr = "10.0.0.1:43210"
+	req.Header.Set("X-API-Key", "api key")
+	req.Header.Set("X-Admin-Token", "admin token")
+	req.Header.Set("X-Real-IP", "203.0.113.10")
+	req.Header.Set("X-Forwarded-For", "203.0.113.10")
+	req.Header.Set("Cookie", "session")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// Make sure the credentials and the client IP are forwarded to all the queried shards
+	for _, m := range mocks {
+		headers := m.requestHeaders()
+		require.Len(t, headers, 1)
+
+		assert.Equal(t, "api key", headers[0].Get("X-API-Key"))
+		assert.Equal(t, "admin token", headers[0].Get("X-Admin-Token"))
+		assert.Equal(t, "203.0.113.10", headers[0].Get("X-Real-IP"))
+		assert.Equal(t, "203.0.113.10, 10.0.0.1", headers[0].Get("X-Forwarded-For"))
+		assert.Empty(t, headers[0].Get("Cookie"))
+	}
+}
+
+func TestRouter_PagedQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 199}, [2]uint64{200, 0})
+
+	// pageFn returns the pages of the shard items, of the requested limit
+	pageFn := func(items ...string) func(req *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return func(req *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+			pagination, _ := req.Params[1].(map[string]any)
+
+			start, _ := strconv.Atoi(fmt.Sprintf("%v", pagination["cursor"]))
+
+			limit := 100
+			if value, ok := pagination["limit"]; ok {
+				limit, _ = strconv.Atoi(fmt.Sprintf("%v", value))
+			}
+
+			end := min(start+limit, len(items))
+			page := map[string]any{"txs": items[start:end]}
+
+			if end < len(items) {
+				page["cursor"] = strconv.Itoa(end)
+			}
+
+			return page, nil
+		}
+	}
+
+	mocks[0].handleFn = pageFn("tx 1", "tx 2", "tx 3")
+	mocks[1].handleFn = pageFn()
+	mocks[2].handleFn = pageFn("tx 4", "tx 5")
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	// walk fetches the pages of the ordering, until the last one
+	walk := func(order string) []string {
+		var (
+			txs    = make([]string, 0)
+			cursor = ""
+		)
+
+		for range 5 {
+			resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(
+				1,
+				"getTxsBySigner",
+				[]any{"address", map[string]any{"cursor": cursor, "limit": 2, "order": order}},
+			)))
+			require.Nil(t, resp.Error)
+
+			var page struct {
+				Cursor string   `json:"cursor"`
+				Txs    []string `json:"txs"`
+			}
+
+			require.NoError(t, json.Unmarshal(resp.Result, &page))
+			require.LessOrEqual(t, len(page.Txs), 2)
+
+			txs = append(txs, page.Txs...)
+
+			if cursor = page.Cursor; cursor == "" {
+				break
+			}
+		}
+
+		assert.Empty(t, cursor)
+
+		return txs
+	}
+
+	// Make sure the pages span the shards, skipping the empty ones
+	assert.Equal(t, []string{"tx 1", "tx 2", "tx 3", "tx 4", "tx 5"}, walk("asc"))
+
+	// Make sure the newest first pages start from the latest shard
+	assert.Equal(t, []string{"tx 4", "tx 5", "tx 1", "tx 2", "tx 3"}, walk("desc"))
+
+	// Make sure the invalid router cursors are rejected
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(
+		1,
+		"getTxsByError",
+		[]any{"code", map[string]any{"cursor": "7:"}},
+	)))
+
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, spec.InvalidParamsErrorCode, resp.Error.Code)
+}
+
+func TestRouter_SeriesQueries(t *testing.T) {
+	t.Parallel()
+
+	shards, mocks := newShards(t, [2]uint64{0, 99}, [2]uint64{100, 0})
+
+	// The second bucket spans the shard boundary
+	mocks[0].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return []map[string]any{
+			{"time": "2024-01-01T00:00:00Z", "value": 10},
+			{"time": "2024-01-01T01:00:00Z", "value": 4},
+		}, nil
+	}
+
+	mocks[1].handleFn = func(_ *spec.BaseJSONRequest) (any, *spec.BaseJSONError) {
+		return []map[string]any{
+			{"time": "2024-01-01T01:00:00Z", "value": 6},
+			{"time": "2024-01-01T02:00:00Z", "value": 8},
+		}, nil
+	}
+
+	r, err := NewRouter(shards)
+	require.NoError(t, err)
+
+	resp := decodeResponse(t, query(t, r, spec.NewJSONRequest(
+		1,
+		"getTxCountSeries",
+		[]any{"hour", "2024-01-01T00:00:00Z", "2024-01-01T03:00:00Z"},
+	)))
+
+	require.Nil(t, resp.Error)
+	assert.JSONEq(
+		t,
+		`[
+			{"time": "2024-01-01T00:00:00Z", "value": 10},
+			{"time": "2024-01-01T01:00:00Z", "value": 10},
+			{"time": "2024-01-01T02:00:00Z", "value": 8}
+		]`,
+		string(resp.Result),
+	)
+}
+
+// servedMethods returns the methods registered by the JSON-RPC server
+func servedMethods(t *testing.T) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../serve/jsonrpc.go", nil, 0)
+	require.NoError(t, err)
+
+	methods := make([]string, 0)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (selector.Sel.Name != "RegisterHandler" && selector.Sel.Name != "RegisterRequestHandler") {
+			return true
+		}
+
+		// The (prefixed) admin methods are served by the latest shard
+		literal, ok := call.Args[0].(*ast.BasicLit)
+		if !ok {
+			return true
+		}
+
+		method, err := strconv.Unquote(literal.Value)
+		require.NoError(t, err)
+
+		methods = append(methods, method)
+
+		return true
+	})
+
+	return methods
+}
+
+func TestRouter_RoutedMethods(t *testing.T) {
+	t.Parallel()
+
+	methods := servedMethods(t)
+	require.NotEmpty(t, methods)
+
+	// Make sure every served method is routed on purpose, so the new methods querying
+	// the heights (or the history) aren't served by the latest shard alone
+	for _, method := range methods {
+		routed := 0
+
+		for _, routes := range []map[string]struct{}{
+			heightMethods,
+			hashMethods,
+			batchHashMethods,
+			seriesMethods,
+			tipMethods,
+		} {
+			if _, ok := routes[method]; ok {
+				routed++
+			}
+		}
+
+		if _, ok := rangeMethods[method]; ok {
+			routed++
+		}
+
+		if _, ok := pagedMethods[method]; ok {
+			routed++
+		}
+
+		assert.Equal(t, 1, routed, "method %s is not routed exactly once", method)
+	}
+}
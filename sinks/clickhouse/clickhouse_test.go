@@ -0,0 +1,193 @@
+package clickhouse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockServer is a mock ClickHouse HTTP interface, capturing the queries
+type mockServer struct {
+	*httptest.Server
+
+	queries []string
+	rows    map[string]int
+
+	failInserts bool
+
+	mux sync.Mutex
+}
+
+// newMockServer creates a new mock ClickHouse HTTP interface
+func newMockServer(t *testing.T) *mockServer {
+	t.Helper()
+
+	m := &mockServer{
+		queries: make([]string, 0),
+		rows:    make(map[string]int),
+	}
+
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mux.Lock()
+		defer m.mux.Unlock()
+
+		query := r.URL.Query().Get("query")
+		m.queries = append(m.queries, query)
+
+		if !strings.HasPrefix(query, "INSERT") {
+			return
+		}
+
+		if m.failInserts {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Code: 241. DB::Exception: Memory limit exceeded"))
+
+			return
+		}
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			m.rows[query]++
+		}
+	}))
+
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// newBlock creates a new block with the given number of transactions
+func newBlock(height int64, numTxs int) (*types.Block, []*types.TxResult) {
+	results := make([]*types.TxResult, 0, numTxs)
+
+	for i := 0; i < numTxs; i++ {
+		results = append(results, &types.TxResult{
+			Height: height,
+			Index:  uint32(i),
+			Tx:     []byte{byte(height), byte(i)},
+		})
+	}
+
+	return &types.Block{Header: types.Header{Height: height}}, results
+}
+
+func TestSink_Init(t *testing.T) {
+	t.Parallel()
+
+	server := newMockServer(t)
+
+	require.NoError(t, New(server.URL, WithDatabase("gno"), WithTablePrefix("dev_")).Init(context.Background()))
+
+	require.Len(t, server.queries, 3)
+
+	for _, query := range server.queries {
+		assert.True(t, strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS `gno`.`dev_"))
+		assert.Contains(t, query, "ENGINE = ReplacingMergeTree")
+	}
+}
+
+func TestSink_Save(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rows batched", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			server = newMockServer(t)
+			sink   = New(server.URL, WithBatchSize(5), WithFlushInterval(time.Hour))
+		)
+
+		// Buffer the first block
+		block, results := newBlock(1, 3)
+		require.NoError(t, sink.Save(context.Background(), block, results))
+
+		assert.Empty(t, server.queries)
+
+		// Fill up the batch
+		block, results = newBlock(2, 2)
+		require.NoError(t, sink.Save(context.Background(), block, results))
+
+		require.Len(t, server.queries, 1)
+		assert.Equal(t, 5, server.rows[insertQuery("`default`.`txs`")])
+	})
+
+	t.Run("flush interval elapsed", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			server = newMockServer(t)
+			sink   = New(server.URL, WithFlushInterval(0))
+		)
+
+		block, results := newBlock(1, 1)
+		require.NoError(t, sink.Save(context.Background(), block, results))
+
+		assert.Equal(t, 1, server.rows[insertQuery("`default`.`txs`")])
+	})
+
+	t.Run("explicit flush", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			server = newMockServer(t)
+			sink   = New(server.URL, WithFlushInterval(time.Hour))
+		)
+
+		block, results := newBlock(1, 2)
+		require.NoError(t, sink.Save(context.Background(), block, results))
+		require.NoError(t, sink.Flush(context.Background()))
+
+		assert.Equal(t, 2, server.rows[insertQuery("`default`.`txs`")])
+
+		// Make sure nothing is left to flush
+		require.NoError(t, sink.Flush(context.Background()))
+		assert.Len(t, server.queries, 1)
+	})
+
+	t.Run("failed inserts retried", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			server = newMockServer(t)
+			sink   = New(server.URL, WithFlushInterval(0))
+		)
+
+		server.failInserts = true
+
+		block, results := newBlock(1, 2)
+		assert.ErrorContains(t, sink.Save(context.Background(), block, results), "Memory limit exceeded")
+
+		server.failInserts = false
+
+		block, results = newBlock(2, 1)
+		require.NoError(t, sink.Save(context.Background(), block, results))
+
+		// Make sure the rows of both blocks were inserted
+		assert.Equal(t, 3, server.rows[insertQuery("`default`.`txs`")])
+	})
+
+	t.Run("buffered rows dropped", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			server = newMockServer(t)
+			sink   = New(server.URL, WithBatchSize(1), WithFlushInterval(0))
+		)
+
+		server.failInserts = true
+
+		block, results := newBlock(1, maxBufferedBatches)
+
+		assert.ErrorContains(t, sink.Save(context.Background(), block, results), "dropped")
+		assert.Zero(t, sink.pending)
+	})
+}
@@ -0,0 +1,58 @@
+package clickhouse
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+type Option func(s *Sink)
+
+// WithDecoder sets the chain decoder
+// of the flattened transactions
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(s *Sink) {
+		s.decoder = decoder
+	}
+}
+
+// WithHTTPClient sets the HTTP client used
+// for the ClickHouse requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Sink) {
+		s.client = client
+	}
+}
+
+// WithDatabase sets the ClickHouse database
+// the sink tables are created in
+func WithDatabase(database string) Option {
+	return func(s *Sink) {
+		s.database = database
+	}
+}
+
+// WithTablePrefix sets the prefix of the
+// transaction, message and event table names
+func WithTablePrefix(prefix string) Option {
+	return func(s *Sink) {
+		s.prefix = prefix
+	}
+}
+
+// WithBatchSize sets the number of buffered
+// rows that triggers inserting them
+func WithBatchSize(size int) Option {
+	return func(s *Sink) {
+		s.batchSize = size
+	}
+}
+
+// WithFlushInterval sets the maximum time
+// between inserting the buffered rows
+func WithFlushInterval(interval time.Duration) Option {
+	return func(s *Sink) {
+		s.flushInterval = interval
+	}
+}
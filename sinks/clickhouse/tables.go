@@ -0,0 +1,53 @@
+package clickhouse
+
+import (
+	"fmt"
+)
+
+// tableTxs, tableMsgs and tableEvents are the sink table names (without the prefix)
+const (
+	tableTxs    = "txs"
+	tableMsgs   = "msgs"
+	tableEvents = "events"
+)
+
+// tableSchemas are the sink table columns and sorting keys.
+// The tables use the ReplacingMergeTree engine, so rows inserted again
+// for the same chain position are eventually deduplicated
+var tableSchemas = map[string]struct {
+	columns string
+	orderBy string
+}{
+	tableTxs: {
+		columns: "`time` DateTime64(3, 'UTC'), `hash` String, `error` String, `memo` String, `fee` String, " +
+			"`msgTypes` Array(LowCardinality(String)), `realms` Array(String), `addresses` Array(String), " +
+			"`height` UInt64, `gasWanted` Int64, `gasUsed` Int64, `index` UInt32, `success` Bool",
+		orderBy: "`height`, `index`",
+	},
+	tableMsgs: {
+		columns: "`time` DateTime64(3, 'UTC'), `txHash` String, `type` LowCardinality(String), `signer` String, " +
+			"`realm` String, `func` String, `amount` String, `height` UInt64, `index` UInt32, `msgIndex` UInt32",
+		orderBy: "`height`, `index`, `msgIndex`",
+	},
+	tableEvents: {
+		columns: "`time` DateTime64(3, 'UTC'), `txHash` String, `realm` String, `type` LowCardinality(String), " +
+			"`func` String, `attrKeys` Array(String), `attrValues` Array(String), `height` UInt64, `index` UInt32, " +
+			"`eventIndex` UInt32",
+		orderBy: "`height`, `index`, `eventIndex`",
+	},
+}
+
+// createTableQuery returns the query creating the table, if it doesn't exist
+func createTableQuery(table, columns, orderBy string) string {
+	return fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (%s) ENGINE = ReplacingMergeTree ORDER BY (%s)",
+		table,
+		columns,
+		orderBy,
+	)
+}
+
+// insertQuery returns the query inserting JSON rows into the table
+func insertQuery(table string) string {
+	return fmt.Sprintf("INSERT INTO %s FORMAT JSONEachRow", table)
+}
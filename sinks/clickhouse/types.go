@@ -0,0 +1,44 @@
+package clickhouse
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/sinks"
+)
+
+// eventRow is the realm event row, with the attributes flattened into key and value columns
+type eventRow struct {
+	Time       time.Time `json:"time"`
+	TxHash     string    `json:"txHash"`
+	Realm      string    `json:"realm"`
+	Type       string    `json:"type"`
+	Func       string    `json:"func"`
+	AttrKeys   []string  `json:"attrKeys"`
+	AttrValues []string  `json:"attrValues"`
+	Height     int64     `json:"height"`
+	Index      uint32    `json:"index"`
+	EventIndex uint32    `json:"eventIndex"`
+}
+
+// newEventRow creates the event row from the flattened event
+func newEventRow(event *sinks.Event) *eventRow {
+	row := &eventRow{
+		Time:       event.Time,
+		TxHash:     event.TxHash,
+		Realm:      event.Realm,
+		Type:       event.Type,
+		Func:       event.Func,
+		AttrKeys:   make([]string, 0, len(event.Attrs)),
+		AttrValues: make([]string, 0, len(event.Attrs)),
+		Height:     event.Height,
+		Index:      event.Index,
+		EventIndex: event.EventIndex,
+	}
+
+	for _, attr := range event.Attrs {
+		row.AttrKeys = append(row.AttrKeys, attr.Key)
+		row.AttrValues = append(row.AttrValues, attr.Value)
+	}
+
+	return row
+}
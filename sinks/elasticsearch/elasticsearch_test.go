@@ -0,0 +1,145 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSink_Init(t *testing.T) {
+	t.Parallel()
+
+	var (
+		created = make([]string, 0)
+		mux     sync.Mutex
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			// Only the block index exists
+			if r.URL.Path == "/chain-blocks" {
+				w.WriteHeader(http.StatusOK)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusNotFound)
+		case http.MethodPut:
+			var mappings map[string]any
+
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&mappings))
+			assert.Contains(t, mappings, "mappings")
+
+			mux.Lock()
+			created = append(created, r.URL.Path)
+			mux.Unlock()
+
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, New(server.URL, WithIndexPrefix("chain")).Init(context.Background()))
+
+	assert.ElementsMatch(t, []string{"/chain-txs", "/chain-events"}, created)
+}
+
+func TestSink_Save(t *testing.T) {
+	t.Parallel()
+
+	var (
+		block = &types.Block{
+			Header: types.Header{
+				Height: 10,
+			},
+		}
+
+		results = []*types.TxResult{
+			{
+				Height: 10,
+				Index:  0,
+				Tx:     []byte("tx"),
+			},
+		}
+	)
+
+	t.Run("block mirrored", func(t *testing.T) {
+		t.Parallel()
+
+		lines := make([]map[string]any, 0)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/_bulk", r.URL.Path)
+			assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var line map[string]any
+
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+
+				lines = append(lines, line)
+			}
+
+			_, _ = w.Write([]byte(`{"errors": false, "items": []}`))
+		}))
+		defer server.Close()
+
+		require.NoError(t, New(server.URL).Save(context.Background(), block, results))
+
+		// Make sure the block and the transaction were indexed
+		require.Len(t, lines, 4)
+
+		assert.Equal(
+			t,
+			map[string]any{"index": map[string]any{"_index": "tx-indexer-blocks", "_id": "10"}},
+			lines[0],
+		)
+		assert.Equal(t, float64(10), lines[1]["height"])
+
+		assert.Equal(
+			t,
+			map[string]any{"index": map[string]any{"_index": "tx-indexer-txs", "_id": "10-0"}},
+			lines[2],
+		)
+		assert.Equal(t, true, lines[3]["success"])
+	})
+
+	t.Run("bulk errors", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{
+				"errors": true,
+				"items": [{"index": {"status": 400, "error": {"type": "mapper_parsing_exception"}}}]
+			}`))
+		}))
+		defer server.Close()
+
+		err := New(server.URL).Save(context.Background(), block, results)
+		assert.ErrorContains(t, err, "mapper_parsing_exception")
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		err := New(server.URL).Save(context.Background(), block, results)
+		assert.ErrorContains(t, err, "401")
+	})
+}
@@ -0,0 +1,60 @@
+package elasticsearch
+
+// blockMappings are the block index mappings
+const blockMappings = `{
+  "mappings": {
+    "properties": {
+      "time": {"type": "date"},
+      "hash": {"type": "keyword"},
+      "chainID": {"type": "keyword"},
+      "proposer": {"type": "keyword"},
+      "height": {"type": "long"},
+      "numTxs": {"type": "long"},
+      "totalTxs": {"type": "long"}
+    }
+  }
+}`
+
+// txMappings are the transaction index mappings
+const txMappings = `{
+  "mappings": {
+    "properties": {
+      "time": {"type": "date"},
+      "hash": {"type": "keyword"},
+      "error": {"type": "text"},
+      "memo": {"type": "text"},
+      "fee": {"type": "keyword"},
+      "msgTypes": {"type": "keyword"},
+      "realms": {"type": "keyword"},
+      "addresses": {"type": "keyword"},
+      "height": {"type": "long"},
+      "gasWanted": {"type": "long"},
+      "gasUsed": {"type": "long"},
+      "index": {"type": "integer"},
+      "success": {"type": "boolean"}
+    }
+  }
+}`
+
+// eventMappings are the realm event index mappings
+const eventMappings = `{
+  "mappings": {
+    "properties": {
+      "time": {"type": "date"},
+      "realm": {"type": "keyword"},
+      "type": {"type": "keyword"},
+      "func": {"type": "keyword"},
+      "attrs": {
+        "type": "nested",
+        "properties": {
+          "key": {"type": "keyword"},
+          "value": {"type": "keyword"}
+        }
+      },
+      "txHash": {"type": "keyword"},
+      "height": {"type": "long"},
+      "index": {"type": "integer"},
+      "eventIndex": {"type": "integer"}
+    }
+  }
+}`
@@ -0,0 +1,33 @@
+package elasticsearch
+
+import (
+	"net/http"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+type Option func(s *Sink)
+
+// WithDecoder sets the chain decoder
+// of the flattened transactions
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(s *Sink) {
+		s.decoder = decoder
+	}
+}
+
+// WithHTTPClient sets the HTTP client used
+// for the Elasticsearch requests
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Sink) {
+		s.client = client
+	}
+}
+
+// WithIndexPrefix sets the prefix of the
+// block, transaction and event index names
+func WithIndexPrefix(prefix string) Option {
+	return func(s *Sink) {
+		s.prefix = prefix
+	}
+}
@@ -0,0 +1,30 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+)
+
+// bulkResponse is the bulk API response
+type bulkResponse struct {
+	Items  []map[string]*bulkItem `json:"items"`
+	Errors bool                   `json:"errors"`
+}
+
+// bulkItem is the result of a single bulk action
+type bulkItem struct {
+	Error  json.RawMessage `json:"error,omitempty"`
+	Status int             `json:"status"`
+}
+
+// firstError returns the error of the first failed bulk action
+func (r *bulkResponse) firstError() string {
+	for _, item := range r.Items {
+		for _, result := range item {
+			if len(result.Error) != 0 {
+				return string(result.Error)
+			}
+		}
+	}
+
+	return "bulk request failed"
+}
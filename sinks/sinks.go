@@ -0,0 +1,199 @@
+// Package sinks flattens the indexed chain data into block, transaction, message and event records,
+// for mirroring it into external stores. The sinks are run as fetcher post-save hooks
+package sinks
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/plugins/realmevents"
+)
+
+// Block is the flattened block record
+type Block struct {
+	Time     time.Time `json:"time"`
+	Hash     string    `json:"hash"`
+	ChainID  string    `json:"chainID"`
+	Proposer string    `json:"proposer"`
+	Height   int64     `json:"height"`
+	NumTxs   int64     `json:"numTxs"`
+	TotalTxs int64     `json:"totalTxs"`
+}
+
+// Tx is the flattened transaction record
+type Tx struct {
+	Time      time.Time `json:"time"`
+	Hash      string    `json:"hash"`
+	Error     string    `json:"error,omitempty"`
+	Memo      string    `json:"memo,omitempty"`
+	Fee       string    `json:"fee,omitempty"`
+	MsgTypes  []string  `json:"msgTypes"`
+	Realms    []string  `json:"realms"`
+	Addresses []string  `json:"addresses"`
+	Height    int64     `json:"height"`
+	GasWanted int64     `json:"gasWanted"`
+	GasUsed   int64     `json:"gasUsed"`
+	Index     uint32    `json:"index"`
+	Success   bool      `json:"success"`
+}
+
+// Msg is the flattened transaction message record
+type Msg struct {
+	Time     time.Time `json:"time"`
+	TxHash   string    `json:"txHash"`
+	Type     string    `json:"type"`
+	Signer   string    `json:"signer,omitempty"`
+	Realm    string    `json:"realm,omitempty"`
+	Func     string    `json:"func,omitempty"`
+	Amount   string    `json:"amount,omitempty"`
+	Height   int64     `json:"height"`
+	Index    uint32    `json:"index"`
+	MsgIndex uint32    `json:"msgIndex"`
+}
+
+// Event is the flattened realm event record
+type Event struct {
+	Time time.Time `json:"time"`
+
+	*realmevents.Event
+}
+
+// Records are the flattened records of a single block
+type Records struct {
+	Block  *Block
+	Txs    []*Tx
+	Msgs   []*Msg
+	Events []*Event
+}
+
+// Flatten flattens the block and its transaction results into records.
+// Transactions that can't be decoded (by the chain decoder) only have their execution data set
+func Flatten(block *types.Block, results []*types.TxResult, decoder *decode.Decoder) *Records {
+	records := &Records{
+		Block: &Block{
+			Time:     block.Time,
+			Hash:     base64.StdEncoding.EncodeToString(block.Hash()),
+			ChainID:  block.ChainID,
+			Proposer: block.ProposerAddress.String(),
+			Height:   block.Height,
+			NumTxs:   block.NumTxs,
+			TotalTxs: block.TotalTxs,
+		},
+		Txs:    make([]*Tx, 0, len(results)),
+		Msgs:   make([]*Msg, 0),
+		Events: make([]*Event, 0),
+	}
+
+	for _, result := range results {
+		tx := &Tx{
+			Time:      block.Time,
+			Hash:      base64.StdEncoding.EncodeToString(result.Tx.Hash()),
+			MsgTypes:  make([]string, 0),
+			Realms:    make([]string, 0),
+			Addresses: make([]string, 0),
+			Height:    result.Height,
+			GasWanted: result.Response.GasWanted,
+			GasUsed:   result.Response.GasUsed,
+			Index:     result.Index,
+			Success:   result.Response.IsOK(),
+		}
+
+		if result.Response.IsErr() {
+			tx.Error = result.Response.Error.Error()
+		}
+
+		if decoded, err := decoder.TxResult(result); err == nil {
+			tx.Memo = decoded.GetMemo()
+			tx.Fee = decoded.Fee.GasFee.String()
+
+			for _, address := range decode.Addresses(decoded) {
+				tx.Addresses = append(tx.Addresses, address.String())
+			}
+
+			for msgIndex, msg := range decoded.GetMsgs() {
+				m := flattenMsg(msg)
+
+				m.Time = block.Time
+				m.TxHash = tx.Hash
+				m.Height = result.Height
+				m.Index = result.Index
+				m.MsgIndex = uint32(msgIndex)
+
+				tx.MsgTypes = appendUnique(tx.MsgTypes, m.Type)
+
+				if m.Realm != "" {
+					tx.Realms = appendUnique(tx.Realms, m.Realm)
+				}
+
+				records.Msgs = append(records.Msgs, m)
+			}
+		}
+
+		records.Txs = append(records.Txs, tx)
+
+		for _, event := range realmevents.FromTxResult(result) {
+			records.Events = append(records.Events, &Event{
+				Time:  block.Time,
+				Event: event,
+			})
+		}
+	}
+
+	return records
+}
+
+// flattenMsg flattens the message specific data
+func flattenMsg(msg std.Msg) *Msg {
+	m := &Msg{
+		Type: msg.Type(),
+	}
+
+	if signers := msg.GetSigners(); len(signers) != 0 {
+		m.Signer = signers[0].String()
+	}
+
+	switch msg := msg.(type) {
+	case bank.MsgSend:
+		m.Amount = coins(msg.Amount)
+	case vm.MsgCall:
+		m.Realm = msg.PkgPath
+		m.Func = msg.Func
+		m.Amount = coins(msg.Send)
+	case vm.MsgAddPackage:
+		if msg.Package != nil {
+			m.Realm = msg.Package.Path
+		}
+
+		m.Amount = coins(msg.Deposit)
+	case vm.MsgRun:
+		m.Amount = coins(msg.Send)
+	}
+
+	return m
+}
+
+// coins returns the coins string, empty if there are none
+func coins(c std.Coins) string {
+	if c.IsZero() {
+		return ""
+	}
+
+	return c.String()
+}
+
+// appendUnique appends the value to the list, if it's not already in it
+func appendUnique(list []string, value string) []string {
+	for _, item := range list {
+		if item == value {
+			return list
+		}
+	}
+
+	return append(list, value)
+}
@@ -0,0 +1,131 @@
+package sinks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/mock"
+)
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+		realm = "gno.land/r/demo/boards"
+
+		blockTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		block = &types.Block{
+			Header: types.Header{
+				ChainID:  "dev",
+				Height:   10,
+				Time:     blockTime,
+				NumTxs:   2,
+				TotalTxs: 20,
+			},
+		}
+
+		results = []*types.TxResult{
+			{
+				Height: 10,
+				Index:  0,
+				Tx: amino.MustMarshal(std.Tx{
+					Msgs: []std.Msg{
+						bank.MsgSend{
+							FromAddress: alice,
+							ToAddress:   bob,
+							Amount:      std.Coins{{Denom: "ugnot", Amount: 100}},
+						},
+						vm.MsgCall{
+							Caller:  alice,
+							PkgPath: realm,
+							Func:    "CreatePost",
+						},
+					},
+					Fee:  std.Fee{GasWanted: 100000, GasFee: std.Coin{Denom: "ugnot", Amount: 10}},
+					Memo: "hello",
+				}),
+				Response: abci.ResponseDeliverTx{
+					ResponseBase: abci.ResponseBase{
+						Events: []abci.Event{
+							mock.GnoEvent{Type: "PostCreated", PkgPath: realm, Func: "CreatePost"},
+						},
+					},
+					GasWanted: 100000,
+					GasUsed:   50000,
+				},
+			},
+			{
+				Height: 10,
+				Index:  1,
+				Tx:     []byte("invalid"),
+				Response: abci.ResponseDeliverTx{
+					ResponseBase: abci.ResponseBase{
+						Error: abci.StringError("out of gas"),
+					},
+				},
+			},
+		}
+	)
+
+	records := Flatten(block, results, nil)
+
+	// Make sure the block is flattened
+	assert.Equal(t, "dev", records.Block.ChainID)
+	assert.Equal(t, int64(10), records.Block.Height)
+	assert.Equal(t, blockTime, records.Block.Time)
+	assert.Equal(t, int64(20), records.Block.TotalTxs)
+
+	// Make sure the transactions are flattened
+	require.Len(t, records.Txs, 2)
+
+	tx := records.Txs[0]
+
+	assert.True(t, tx.Success)
+	assert.Equal(t, "hello", tx.Memo)
+	assert.Equal(t, "10ugnot", tx.Fee)
+	assert.Equal(t, []string{"send", "exec"}, tx.MsgTypes)
+	assert.Equal(t, []string{realm}, tx.Realms)
+	assert.ElementsMatch(t, []string{alice.String(), bob.String()}, tx.Addresses)
+	assert.Equal(t, int64(50000), tx.GasUsed)
+
+	failedTx := records.Txs[1]
+
+	assert.False(t, failedTx.Success)
+	assert.Equal(t, "out of gas", failedTx.Error)
+	assert.Equal(t, uint32(1), failedTx.Index)
+	assert.Empty(t, failedTx.MsgTypes)
+
+	// Make sure the messages are flattened
+	require.Len(t, records.Msgs, 2)
+
+	assert.Equal(t, "send", records.Msgs[0].Type)
+	assert.Equal(t, alice.String(), records.Msgs[0].Signer)
+	assert.Equal(t, "100ugnot", records.Msgs[0].Amount)
+	assert.Equal(t, tx.Hash, records.Msgs[0].TxHash)
+
+	assert.Equal(t, "exec", records.Msgs[1].Type)
+	assert.Equal(t, realm, records.Msgs[1].Realm)
+	assert.Equal(t, "CreatePost", records.Msgs[1].Func)
+	assert.Empty(t, records.Msgs[1].Amount)
+	assert.Equal(t, uint32(1), records.Msgs[1].MsgIndex)
+
+	// Make sure the events are flattened
+	require.Len(t, records.Events, 1)
+
+	assert.Equal(t, "PostCreated", records.Events[0].Type)
+	assert.Equal(t, realm, records.Events[0].Realm)
+	assert.Equal(t, blockTime, records.Events[0].Time)
+}
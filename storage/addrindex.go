@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// keyAddressIndexBuilt is the lookup key for the marker of the namespaces
+// whose txs are all indexed by address
+const keyAddressIndexBuilt = "/meta/ai"
+
+// ErrAddressIndexMissing is returned by the address queries of the namespaces holding txs
+// saved before the address index was introduced, until the index is built (see MigrateAddressIndex)
+var ErrAddressIndexMissing = errors.New("the address index is not built, run the migrate command")
+
+func keyAddressIndex(ns []byte) []byte {
+	key := slices.Clone(ns)
+	key = append(key, keyAddressIndexBuilt...)
+
+	return key
+}
+
+// initAddressIndex marks the address index of the empty namespace as built,
+// as the txs saved from now on are indexed along with them. The namespaces
+// already holding txs are only marked once migrated (see MigrateAddressIndex)
+func initAddressIndex(db *pebble.DB, ns []byte, readOnly bool) error {
+	if readOnly {
+		return nil
+	}
+
+	if _, err := get(db, keyAddressIndex(ns)); !errors.Is(err, storageErrors.ErrNotFound) {
+		return err
+	}
+
+	if _, err := get(db, keyLatest(ns)); !errors.Is(err, storageErrors.ErrNotFound) {
+		return err
+	}
+
+	return saveAddressIndex(db, ns)
+}
+
+// addressIndexPending returns a flag indicating if the namespace holds txs
+// not indexed by address: the namespace has data, but the index is not marked as built
+func addressIndexPending(r pebble.Reader, ns []byte) (bool, error) {
+	_, err := get(r, keyAddressIndex(ns))
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return false, fmt.Errorf("unable to fetch address index marker, %w", err)
+	}
+
+	_, err = get(r, keyLatest(ns))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	return true, nil
+}
+
+// saveAddressIndex marks the address index of the namespace as built
+func saveAddressIndex(w pebble.Writer, ns []byte) error {
+	if err := w.Set(keyAddressIndex(ns), []byte{1}, pebble.Sync); err != nil {
+		return fmt.Errorf("unable to save address index marker, %w", err)
+	}
+
+	return nil
+}
+
+// MigrateAddressIndex builds the address index entries of the txs saved before the address index
+// was introduced, in both storage tiers, returning the number of indexed txs. The address queries
+// of the namespace fail with ErrAddressIndexMissing until the migration completes.
+// The migration can be resumed if interrupted, and does nothing once the index is built
+func (s *Pebble) MigrateAddressIndex() (int, error) {
+	pending, err := addressIndexPending(s.db, s.ns)
+	if err != nil || !pending {
+		return 0, err
+	}
+
+	prefix, kindOffset := prefixKeyTxs, -1
+	if s.schema == schemaV2 {
+		prefix, kindOffset = prefixKeyHeights, heightKindOffset(s.ns)
+	}
+
+	indexTx := func(b *pebble.Batch, key, value []byte) (bool, error) {
+		if kindOffset >= 0 && key[kindOffset] != kindTx {
+			return false, nil
+		}
+
+		tx, err := decodeTx(value)
+		if err != nil {
+			return false, err
+		}
+
+		// The hash index entry is rewritten as is
+		for _, indexKey := range txIndexKeys(s.ns, s.decoder, tx) {
+			if err := b.Set(indexKey, key, nil); err != nil {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}
+
+	indexed := 0
+
+	// The cold tier is indexed first, as its heights are below the hot tier ones
+	if s.cold != nil {
+		if indexed, err = rewrite(s.cold, s.ns, prefix, indexTx); err != nil {
+			return indexed, fmt.Errorf("unable to index cold tier txs, %w", err)
+		}
+	}
+
+	hot, err := rewrite(s.db, s.ns, prefix, indexTx)
+	if err != nil {
+		return indexed + hot, fmt.Errorf("unable to index txs, %w", err)
+	}
+
+	if err := saveAddressIndex(s.db, s.ns); err != nil {
+		return indexed + hot, err
+	}
+
+	return indexed + hot, nil
+}
+
+// checkAddressIndex makes sure all the txs of the namespace are indexed by address
+func (s *Pebble) checkAddressIndex() error {
+	pending, err := addressIndexPending(s.db, s.ns)
+	if err != nil {
+		return err
+	}
+
+	if pending {
+		return ErrAddressIndexMissing
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dropAddressIndex removes the address index of the namespace,
+// as saved by the versions predating it
+func dropAddressIndex(t *testing.T, s *Pebble) {
+	t.Helper()
+
+	lower := encodeStringAscending(slices.Clone(s.ns), prefixKeyTxByAddress)
+
+	require.NoError(t, s.db.DeleteRange(lower, prefixUpperBound(lower), pebble.Sync))
+	require.NoError(t, s.db.Delete(keyAddressIndex(s.ns), pebble.Sync))
+}
+
+func TestStorage_MigrateAddressIndex(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	db, err := NewPebble(path, WithNamespace("chain"))
+	require.NoError(t, err)
+
+	blocks, txs := generateChain(t, 5, 3)
+
+	saveChain(t, db, blocks, txs)
+
+	// Make sure the new namespaces are indexed from the start
+	assert.Equal(t, len(txs), countAddressTxs(t, db, crypto.Address{2}))
+
+	indexed, err := db.MigrateAddressIndex()
+	require.NoError(t, err)
+
+	assert.Zero(t, indexed)
+
+	dropAddressIndex(t, db)
+	require.NoError(t, db.Close())
+
+	// Make sure the address queries fail until the index is built,
+	// even once the storage is reopened
+	db, err = NewPebble(path, WithNamespace("chain"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	_, err = db.TxByAddressIterator(crypto.Address{2}.String(), 0, 0)
+	assert.ErrorIs(t, err, ErrAddressIndexMissing)
+
+	indexed, err = db.MigrateAddressIndex()
+	require.NoError(t, err)
+
+	assert.Equal(t, len(txs), indexed)
+
+	assert.Equal(t, len(txs), countAddressTxs(t, db, crypto.Address{1}))
+	assert.Equal(t, len(txs), countAddressTxs(t, db, crypto.Address{2}))
+	assert.Zero(t, countAddressTxs(t, db, crypto.Address{3}))
+}
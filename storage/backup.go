@@ -0,0 +1,422 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/multierr"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// backupMagic prefixes every backup, followed by the format version
+var backupMagic = []byte("txidxbak")
+
+const (
+	// backupFormatVersion is the version of the backup format
+	backupFormatVersion = 1
+
+	// maxBackupRecordSize is the maximum size of a backup key or value
+	maxBackupRecordSize = 1 << 30
+)
+
+var (
+	ErrInvalidBackup       = errors.New("invalid backup")
+	ErrBackupOutOfOrder    = errors.New("backup does not follow the latest saved height")
+	ErrBackupNotEmpty      = errors.New("full backups are restored only to an empty namespace")
+	ErrBackupSchemaChanged = errors.New("backup key schema differs from the storage key schema")
+)
+
+// BackupInfo describes the data contained in a backup
+type BackupInfo struct {
+	// From is the first backed up height.
+	// Full backups start from height 0
+	From uint64
+
+	// To is the latest saved height at the time of the backup,
+	// which is the checkpoint of the following incremental backup
+	To uint64
+
+	// Schema is the key schema version of the backed up data
+	Schema uint64
+
+	// Records is the number of key-value pairs in the backup
+	Records int
+}
+
+// Incremental returns a flag indicating if the backup
+// only contains the heights since a previous backup
+func (i *BackupInfo) Incremental() bool {
+	return i.From > 0
+}
+
+// Backup writes the namespace data of the heights starting from the given one.
+// A full backup (from height 0) contains all the namespace data, while an incremental
+// backup (from the height following the previous backup checkpoint) only contains the
+// blocks, txs and index entries of the new heights. The plugin data isn't saved by height,
+// so it is contained in every backup
+func (s *Pebble) Backup(w io.Writer, fromHeight uint64) (*BackupInfo, error) {
+	snap := s.db.NewSnapshot()
+	defer snap.Close()
+
+	latest, err := get(snap, keyLatest(s.ns))
+	if err != nil && !errors.Is(err, storageErrors.ErrNotFound) {
+		return nil, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	info := &BackupInfo{
+		From:   fromHeight,
+		Schema: uint64(s.schema),
+	}
+
+	if latest != nil {
+		if _, info.To, err = decodeUint64Ascending(latest); err != nil {
+			return nil, fmt.Errorf("unable to decode latest height, %w", err)
+		}
+	}
+
+	bw := &backupWriter{w: bufio.NewWriter(w), ns: s.ns}
+
+	if err := bw.writeHeader(info); err != nil {
+		return nil, err
+	}
+
+	bw.write(keySchema(s.ns), encodeUint64Ascending(nil, uint64(s.schema)))
+
+	if fromHeight <= info.To {
+		if err := s.backupTiers(snap, bw, fromHeight, info.To); err != nil {
+			return nil, err
+		}
+	}
+
+	pluginsPrefix := encodeStringAscending(slices.Clone(s.ns), prefixKeyPlugins)
+	if err := bw.writeRange(snap, pluginsPrefix, prefixUpperBound(pluginsPrefix), nil); err != nil {
+		return nil, err
+	}
+
+	// The latest height is restored last, so interrupted restores can be repeated
+	if latest != nil {
+		bw.write(keyLatest(s.ns), latest)
+	}
+
+	if err := bw.close(); err != nil {
+		return nil, fmt.Errorf("unable to write backup, %w", err)
+	}
+
+	info.Records = bw.records
+
+	return info, nil
+}
+
+// backupTiers writes the height range data, from the cold tier (if any) and the hot tier
+func (s *Pebble) backupTiers(snap *pebble.Snapshot, bw *backupWriter, from, to uint64) error {
+	if s.cold == nil {
+		return s.backupHeights(snap, bw, from, to)
+	}
+
+	boundary, err := coldBoundary(snap, s.ns)
+	if err != nil {
+		return err
+	}
+
+	if from < boundary {
+		coldSnap := s.cold.NewSnapshot()
+		defer coldSnap.Close()
+
+		if err := s.backupHeights(coldSnap, bw, from, min(boundary-1, to)); err != nil {
+			return err
+		}
+	}
+
+	if to < boundary {
+		return nil
+	}
+
+	return s.backupHeights(snap, bw, max(from, boundary), to)
+}
+
+// backupHeights writes the blocks and txs of the height range, along with the tx index entries
+func (s *Pebble) backupHeights(snap *pebble.Snapshot, bw *backupWriter, from, to uint64) error {
+	writeIndexes := func(key, value []byte) error {
+		tx, err := decodeTx(value)
+		if err != nil {
+			return fmt.Errorf("unable to decode tx, %w", err)
+		}
+
+		for _, indexKey := range txIndexKeys(s.ns, s.decoder, tx) {
+			bw.write(indexKey, key)
+		}
+
+		return nil
+	}
+
+	if s.schema == schemaV2 {
+		kindOffset := heightKindOffset(s.ns)
+
+		// The blocks are interleaved with their txs
+		return bw.writeRange(snap, keyHeight(s.ns, from), keyHeight(s.ns, to+1), func(key, value []byte) error {
+			if key[kindOffset] != kindTx {
+				return nil
+			}
+
+			return writeIndexes(key, value)
+		})
+	}
+
+	if err := bw.writeRange(snap, keyBlock(s.ns, from), keyBlock(s.ns, to+1), nil); err != nil {
+		return err
+	}
+
+	return bw.writeRange(snap, keyTx(s.ns, from, 0), keyTx(s.ns, to+1, 0), writeIndexes)
+}
+
+// Restore restores the namespace data from the backup.
+// Full backups are restored to an empty namespace, while incremental
+// backups are restored in order, each following the latest saved height.
+// The latest height is restored last, so interrupted restores can be repeated
+func (s *Pebble) Restore(r io.Reader) (*BackupInfo, error) {
+	br := &backupReader{r: bufio.NewReader(r)}
+
+	info, err := br.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.GetLatestHeight()
+	empty := errors.Is(err, storageErrors.ErrNotFound)
+
+	if err != nil && !empty {
+		return nil, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	switch {
+	case !info.Incremental() && !empty:
+		return nil, ErrBackupNotEmpty
+	case info.Incremental() && (empty || latest+1 != info.From):
+		return nil, fmt.Errorf("%w, backup from height %d", ErrBackupOutOfOrder, info.From)
+	case info.Incremental() && schemaVersion(info.Schema) != s.schema:
+		return nil, ErrBackupSchemaChanged
+	}
+
+	b := s.db.NewBatch()
+
+	for {
+		key, value, err := br.readRecord()
+		if err != nil {
+			return nil, multierr.Append(err, b.Close())
+		}
+
+		if key == nil {
+			break
+		}
+
+		if err := b.Set(append(slices.Clone(s.ns), key...), value, nil); err != nil {
+			return nil, multierr.Append(err, b.Close())
+		}
+
+		info.Records++
+
+		if b.Count() < migrateBatchSize {
+			continue
+		}
+
+		if err := b.Commit(pebble.Sync); err != nil {
+			return nil, multierr.Append(err, b.Close())
+		}
+
+		if err := b.Close(); err != nil {
+			return nil, err
+		}
+
+		b = s.db.NewBatch()
+	}
+
+	if err := b.Commit(pebble.Sync); err != nil {
+		return nil, multierr.Append(err, b.Close())
+	}
+
+	if err := b.Close(); err != nil {
+		return nil, err
+	}
+
+	// Full backups of the legacy key schema switch the (empty) namespace to it
+	if schemaVersion(info.Schema) != s.schema {
+		s.schema = schemaVersion(info.Schema)
+	}
+
+	return info, nil
+}
+
+// ReadBackupInfo reads the backup header, describing the backed up heights.
+// The number of records is not part of the header
+func ReadBackupInfo(r io.Reader) (*BackupInfo, error) {
+	return (&backupReader{r: bufio.NewReader(r)}).readHeader()
+}
+
+// backupWriter writes the backup records, relative to the namespace.
+// Each record is a length prefixed key and value, and the records
+// are terminated by an empty key
+type backupWriter struct {
+	w  *bufio.Writer
+	ns []byte
+
+	records int
+	err     error
+}
+
+func (bw *backupWriter) writeHeader(info *BackupInfo) error {
+	header := slices.Clone(backupMagic)
+	header = append(header, backupFormatVersion)
+	header = binary.AppendUvarint(header, info.From)
+	header = binary.AppendUvarint(header, info.To)
+	header = binary.AppendUvarint(header, info.Schema)
+
+	if _, err := bw.w.Write(header); err != nil {
+		return fmt.Errorf("unable to write backup header, %w", err)
+	}
+
+	return nil
+}
+
+// write writes the key-value pair. The first write error is returned on close
+func (bw *backupWriter) write(key, value []byte) {
+	if bw.err != nil {
+		return
+	}
+
+	record := binary.AppendUvarint(nil, uint64(len(key)-len(bw.ns)))
+	record = append(record, key[len(bw.ns):]...)
+	record = binary.AppendUvarint(record, uint64(len(value)))
+
+	if _, bw.err = bw.w.Write(record); bw.err != nil {
+		return
+	}
+
+	if _, bw.err = bw.w.Write(value); bw.err != nil {
+		return
+	}
+
+	bw.records++
+}
+
+// writeRange writes the key-value pairs of the key range,
+// calling the callback (if any) for each written pair
+func (bw *backupWriter) writeRange(
+	snap *pebble.Snapshot,
+	lowerBound,
+	upperBound []byte,
+	onWrite func(key, value []byte) error,
+) error {
+	it, err := snap.NewIter(&pebble.IterOptions{
+		LowerBound: lowerBound,
+		UpperBound: upperBound,
+	})
+	if err != nil {
+		return err
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		bw.write(it.Key(), it.Value())
+
+		if onWrite == nil {
+			continue
+		}
+
+		if err := onWrite(it.Key(), it.Value()); err != nil {
+			return multierr.Append(err, it.Close())
+		}
+	}
+
+	if bw.err != nil {
+		return multierr.Append(fmt.Errorf("unable to write backup, %w", bw.err), it.Close())
+	}
+
+	return it.Close()
+}
+
+// close terminates the records, and flushes the backup
+func (bw *backupWriter) close() error {
+	if bw.err != nil {
+		return bw.err
+	}
+
+	if err := bw.w.WriteByte(0); err != nil {
+		return err
+	}
+
+	return bw.w.Flush()
+}
+
+// backupReader reads the backup written by the backupWriter
+type backupReader struct {
+	r *bufio.Reader
+}
+
+func (br *backupReader) readHeader() (*BackupInfo, error) {
+	header := make([]byte, len(backupMagic)+1)
+
+	if _, err := io.ReadFull(br.r, header); err != nil {
+		return nil, fmt.Errorf("%w, unable to read header, %w", ErrInvalidBackup, err)
+	}
+
+	if !bytes.Equal(header[:len(backupMagic)], backupMagic) {
+		return nil, fmt.Errorf("%w, unknown format", ErrInvalidBackup)
+	}
+
+	if version := header[len(backupMagic)]; version != backupFormatVersion {
+		return nil, fmt.Errorf("%w, unsupported format version %d", ErrInvalidBackup, version)
+	}
+
+	var (
+		info = &BackupInfo{}
+		err  error
+	)
+
+	for _, field := range []*uint64{&info.From, &info.To, &info.Schema} {
+		if *field, err = binary.ReadUvarint(br.r); err != nil {
+			return nil, fmt.Errorf("%w, unable to read header, %w", ErrInvalidBackup, err)
+		}
+	}
+
+	return info, nil
+}
+
+// readRecord reads the next key-value pair, returning a nil key once all are read
+func (br *backupReader) readRecord() ([]byte, []byte, error) {
+	key, err := br.readField()
+	if err != nil || len(key) == 0 {
+		return nil, nil, err
+	}
+
+	value, err := br.readField()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, value, nil
+}
+
+func (br *backupReader) readField() ([]byte, error) {
+	size, err := binary.ReadUvarint(br.r)
+	if err != nil {
+		return nil, fmt.Errorf("%w, unable to read record, %w", ErrInvalidBackup, err)
+	}
+
+	if size > maxBackupRecordSize {
+		return nil, fmt.Errorf("%w, record too large", ErrInvalidBackup)
+	}
+
+	field := make([]byte, size)
+
+	if _, err := io.ReadFull(br.r, field); err != nil {
+		return nil, fmt.Errorf("%w, unable to read record, %w", ErrInvalidBackup, err)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_Backup(t *testing.T) {
+	t.Parallel()
+
+	blocks, txs := generateChain(t, 6, 3)
+
+	testTable := []struct {
+		name   string
+		schema schemaVersion
+	}{
+		{
+			"legacy key schema",
+			schemaV1,
+		},
+		{
+			"current key schema",
+			schemaV2,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewPebble(t.TempDir(), WithNamespace("source"))
+			require.NoError(t, err)
+
+			defer func() {
+				assert.NoError(t, s.Close())
+			}()
+
+			require.NoError(t, saveSchema(s.db, s.ns, testCase.schema))
+
+			s.schema = testCase.schema
+
+			// Save most of the chain, and make a full backup
+			saveChain(t, s, blocks[:4], txs[:12])
+
+			wb := s.WriteBatch()
+			require.NoError(t, wb.SetPluginValue("plugin", []byte("key"), []byte("value")))
+			require.NoError(t, wb.Commit())
+
+			var full bytes.Buffer
+
+			fullInfo, err := s.Backup(&full, 0)
+			require.NoError(t, err)
+
+			assert.False(t, fullInfo.Incremental())
+			assert.Equal(t, uint64(4), fullInfo.To)
+
+			// Save the rest of the chain, and make an incremental backup
+			saveChain(t, s, blocks[4:], txs[12:])
+
+			var incremental bytes.Buffer
+
+			incrementalInfo, err := s.Backup(&incremental, fullInfo.To+1)
+			require.NoError(t, err)
+
+			assert.True(t, incrementalInfo.Incremental())
+			assert.Equal(t, uint64(6), incrementalInfo.To)
+
+			// Make sure the incremental backup only contains the new heights
+			assert.Less(t, incrementalInfo.Records, fullInfo.Records)
+
+			info, err := ReadBackupInfo(bytes.NewReader(incremental.Bytes()))
+			require.NoError(t, err)
+
+			assert.Equal(t, incrementalInfo.From, info.From)
+			assert.Equal(t, incrementalInfo.To, info.To)
+
+			// Restore the backups to another namespace
+			restored, err := s.WithNamespace("restored")
+			require.NoError(t, err)
+
+			// Make sure the backups are restored in order
+			_, err = restored.Restore(bytes.NewReader(incremental.Bytes()))
+			assert.ErrorIs(t, err, ErrBackupOutOfOrder)
+
+			info, err = restored.Restore(bytes.NewReader(full.Bytes()))
+			require.NoError(t, err)
+
+			assert.Equal(t, fullInfo.Records, info.Records)
+
+			_, err = restored.Restore(bytes.NewReader(full.Bytes()))
+			assert.ErrorIs(t, err, ErrBackupNotEmpty)
+
+			info, err = restored.Restore(bytes.NewReader(incremental.Bytes()))
+			require.NoError(t, err)
+
+			assert.Equal(t, incrementalInfo.Records, info.Records)
+
+			_, err = restored.Restore(bytes.NewReader(incremental.Bytes()))
+			assert.ErrorIs(t, err, ErrBackupOutOfOrder)
+
+			assert.Equal(t, testCase.schema, restored.schema)
+
+			latest, err := restored.GetLatestHeight()
+			require.NoError(t, err)
+
+			assert.Equal(t, uint64(6), latest)
+
+			value, err := restored.GetPluginValue("plugin", []byte("key"))
+			require.NoError(t, err)
+
+			assert.Equal(t, []byte("value"), value)
+
+			assertChain(t, restored, blocks, txs)
+		})
+	}
+}
+
+func TestStorage_Restore_Invalid(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	blocks, txs := generateChain(t, 2, 1)
+	saveChain(t, s, blocks, txs)
+
+	var backup bytes.Buffer
+
+	_, err = s.Backup(&backup, 0)
+	require.NoError(t, err)
+
+	restored, err := s.WithNamespace("restored")
+	require.NoError(t, err)
+
+	_, err = restored.Restore(bytes.NewReader([]byte("not a backup")))
+	assert.ErrorIs(t, err, ErrInvalidBackup)
+
+	// Make sure truncated backups are not restored
+	_, err = restored.Restore(bytes.NewReader(backup.Bytes()[:backup.Len()-10]))
+	assert.ErrorIs(t, err, ErrInvalidBackup)
+
+	_, err = restored.GetLatestHeight()
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// changefeed publishes the committed write batches to the subscribers
+// of the namespace, for streaming them to the standby instances
+type changefeed struct {
+	subscribers map[*changeSubscription]struct{}
+	mux         sync.Mutex
+}
+
+// changeSubscription is a subscription to the committed write batches of a namespace
+type changeSubscription struct {
+	ch chan []byte
+	ns string
+}
+
+func newChangefeed() *changefeed {
+	return &changefeed{
+		subscribers: make(map[*changeSubscription]struct{}),
+	}
+}
+
+// commit commits the batch, publishing it to the namespace subscribers. The batch is captured before
+// the commit, as the large batches are emptied once committed. The commits are serialized with the
+// subscriptions, so the subscribers receive the batches in the commit order, and don't miss the batches
+// committed once subscribed. The batch is only committed if the changefeed is nil
+func (f *changefeed) commit(ns []byte, b *pebble.Batch) error {
+	if f == nil {
+		return b.Commit(pebble.Sync)
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	var repr []byte
+
+	for sub := range f.subscribers {
+		if sub.ns == string(ns) {
+			repr = slices.Clone(b.Repr())
+
+			break
+		}
+	}
+
+	if err := b.Commit(pebble.Sync); err != nil {
+		return err
+	}
+
+	if repr != nil {
+		f.publish(ns, repr)
+	}
+
+	return nil
+}
+
+// publish publishes the committed batch to the namespace subscribers.
+// Subscribers that fall behind are dropped, closing their channel
+func (f *changefeed) publish(ns []byte, repr []byte) {
+	for sub := range f.subscribers {
+		if sub.ns != string(ns) {
+			continue
+		}
+
+		select {
+		case sub.ch <- repr:
+		default:
+			delete(f.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (f *changefeed) subscribe(ns []byte, size int) *changeSubscription {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	sub := &changeSubscription{
+		ch: make(chan []byte, size),
+		ns: string(ns),
+	}
+
+	f.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+func (f *changefeed) unsubscribe(sub *changeSubscription) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if _, ok := f.subscribers[sub]; !ok {
+		// Already dropped
+		return
+	}
+
+	delete(f.subscribers, sub)
+	close(sub.ch)
+}
+
+// SubscribeChanges subscribes to the write batches committed to the namespace,
+// returning the channel of the encoded batches, applied to a standby with ApplyChanges.
+// The channel is closed when the subscriber falls more than the given number of batches behind,
+// or once the subscription is cancelled with the returned function
+func (s *Pebble) SubscribeChanges(size int) (<-chan []byte, func()) {
+	sub := s.feed.subscribe(s.ns, size)
+
+	return sub.ch, func() {
+		s.feed.unsubscribe(sub)
+	}
+}
+
+// ApplyChanges applies the encoded write batch published by the primary
+// storage changefeed. The primary and standby namespaces must match
+func (s *Pebble) ApplyChanges(batch []byte) error {
+	b := s.db.NewBatch()
+
+	if err := b.SetRepr(batch); err != nil {
+		_ = b.Close()
+
+		return err
+	}
+
+	if err := s.feed.commit(s.ns, b); err != nil {
+		_ = b.Close()
+
+		return err
+	}
+
+	return b.Close()
+}
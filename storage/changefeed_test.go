@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage_Changefeed(t *testing.T) {
+	t.Parallel()
+
+	primary, err := NewPebble(t.TempDir(), WithNamespace("chain"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, primary.Close())
+	}()
+
+	standby, err := NewPebble(t.TempDir(), WithNamespace("chain"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, standby.Close())
+	}()
+
+	other, err := primary.WithNamespace("other")
+	require.NoError(t, err)
+
+	changes, unsubscribe := primary.SubscribeChanges(2)
+	defer unsubscribe()
+
+	blocks, txs := generateChain(t, 5, 3)
+
+	// Make sure only the namespace batches are published
+	saveChain(t, other, blocks, txs)
+	saveChain(t, primary, blocks, txs)
+
+	require.Len(t, changes, 1)
+
+	// Make sure the standby applies the published batch
+	require.NoError(t, standby.ApplyChanges(<-changes))
+
+	assertChain(t, standby, blocks, txs)
+
+	latest, err := standby.GetLatestHeight()
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), latest)
+
+	// Make sure subscribers falling behind are dropped
+	for i := 0; i < 3; i++ {
+		saveChain(t, primary, blocks, txs)
+	}
+
+	require.Len(t, changes, 2)
+
+	<-changes
+	<-changes
+
+	_, ok := <-changes
+	assert.False(t, ok)
+}
+
+func TestStorage_Changefeed_LargeBatch(t *testing.T) {
+	t.Parallel()
+
+	primary, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, primary.Close())
+	}()
+
+	standby, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, standby.Close())
+	}()
+
+	changes, unsubscribe := primary.SubscribeChanges(1)
+	defer unsubscribe()
+
+	// Make sure the batches emptied by the commit (over the
+	// large batch threshold of the memtable) are published in full
+	blocks, txs := generateChain(t, 2000, 2)
+	saveChain(t, primary, blocks, txs)
+
+	require.NoError(t, standby.ApplyChanges(<-changes))
+
+	assertChain(t, standby, blocks, txs)
+}
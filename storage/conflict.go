@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// ConflictPolicy is the handling of the block and tx saves
+// conflicting with the data already saved for the height / hash
+type ConflictPolicy int
+
+const (
+	// ConflictOverwrite overwrites the saved data, without checking it
+	ConflictOverwrite ConflictPolicy = iota
+
+	// ConflictSkip keeps the saved data, skipping the conflicting save with ErrSkipped
+	ConflictSkip
+
+	// ConflictError fails the conflicting save with ErrConflict
+	ConflictError
+)
+
+// checkConflict checks the value saved under the key, if any, against the saved item.
+// Saving identical data is skipped (as a no-op) with ErrSkipped, so the callers
+// don't process the already saved data again
+func (b *PebbleBatch) checkConflict(key []byte, equalFn func(saved []byte) (bool, error), item string) error {
+	if b.conflicts == ConflictOverwrite {
+		return nil
+	}
+
+	saved, err := get(b.b, key)
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to fetch saved %s, %w", item, err)
+	}
+
+	equal, err := equalFn(saved)
+	if err != nil {
+		return fmt.Errorf("unable to compare saved %s, %w", item, err)
+	}
+
+	if equal || b.conflicts == ConflictSkip {
+		return fmt.Errorf("%w, %s", storageErrors.ErrSkipped, item)
+	}
+
+	return fmt.Errorf("%w, %s", storageErrors.ErrConflict, item)
+}
+
+// checkBlockConflict checks the block saved for the height, if any, against the given one
+func (b *PebbleBatch) checkBlockConflict(key []byte, block *types.Block) error {
+	return b.checkConflict(key, func(saved []byte) (bool, error) {
+		savedBlock, err := decodeBlock(saved)
+		if err != nil {
+			return false, err
+		}
+
+		return bytes.Equal(marshalBlockProto(savedBlock), marshalBlockProto(block)), nil
+	}, fmt.Sprintf("block %d", block.Height))
+}
+
+// checkTxConflict checks the tx saved for the height and index, if any, against the given one,
+// along with the tx saved for the hash
+func (b *PebbleBatch) checkTxConflict(key []byte, tx *types.TxResult) error {
+	err := b.checkConflict(key, func(saved []byte) (bool, error) {
+		savedTx, err := decodeTx(saved)
+		if err != nil {
+			return false, err
+		}
+
+		savedProto, err := marshalTxProto(savedTx)
+		if err != nil {
+			return false, err
+		}
+
+		txProto, err := marshalTxProto(tx)
+		if err != nil {
+			return false, err
+		}
+
+		return bytes.Equal(savedProto, txProto), nil
+	}, fmt.Sprintf("tx %d-%d", tx.Height, tx.Index))
+	if err != nil {
+		return err
+	}
+
+	if b.conflicts == ConflictOverwrite {
+		return nil
+	}
+
+	// Make sure the hash isn't saved for another tx
+	hash := base64.StdEncoding.EncodeToString(tx.Tx.Hash())
+
+	savedKey, err := get(b.b, keyHashTx(b.ns, hash))
+
+	switch {
+	case errors.Is(err, storageErrors.ErrNotFound), err == nil && bytes.Equal(savedKey, key):
+		return nil
+	case err != nil:
+		return fmt.Errorf("unable to fetch saved tx %s, %w", hash, err)
+	case b.conflicts == ConflictSkip:
+		return fmt.Errorf("%w, tx %s", storageErrors.ErrSkipped, hash)
+	default:
+		return fmt.Errorf("%w, tx %s", storageErrors.ErrConflict, hash)
+	}
+}
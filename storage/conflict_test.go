@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+func TestStorage_ConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name   string
+		policy ConflictPolicy
+	}{
+		{"overwrite", ConflictOverwrite},
+		{"skip", ConflictSkip},
+		{"error", ConflictError},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewPebble(t.TempDir(), WithConflictPolicy(testCase.policy))
+			require.NoError(t, err)
+
+			defer func() {
+				assert.NoError(t, s.Close())
+			}()
+
+			blocks, txs := generateChain(t, 5, 2)
+			saveChain(t, s, blocks, txs)
+
+			// Make sure saving identical data is a no-op,
+			// skipped unless the saved data is overwritten
+			wb := s.WriteBatch()
+
+			for _, block := range blocks {
+				if testCase.policy == ConflictOverwrite {
+					require.NoError(t, wb.SetBlock(block))
+				} else {
+					require.ErrorIs(t, wb.SetBlock(block), storageErrors.ErrSkipped)
+				}
+			}
+
+			for _, tx := range txs {
+				if testCase.policy == ConflictOverwrite {
+					require.NoError(t, wb.SetTx(tx))
+				} else {
+					require.ErrorIs(t, wb.SetTx(tx), storageErrors.ErrSkipped)
+				}
+			}
+
+			require.NoError(t, wb.Commit())
+			assertChain(t, s, blocks, txs)
+
+			var (
+				changedBlock = &types.Block{
+					Header: types.Header{
+						Height:  blocks[0].Height,
+						ChainID: "changed",
+					},
+				}
+
+				changedTx = &types.TxResult{
+					Height:   txs[0].Height,
+					Index:    txs[0].Index,
+					Tx:       txs[1].Tx,
+					Response: txs[0].Response,
+				}
+
+				// Same tx as an already saved one, at another height
+				duplicateTx = &types.TxResult{
+					Height: txs[2].Height + 100,
+					Index:  txs[2].Index,
+					Tx:     txs[2].Tx,
+				}
+			)
+
+			wb = s.WriteBatch()
+
+			var (
+				blockErr     = wb.SetBlock(changedBlock)
+				txErr        = wb.SetTx(changedTx)
+				duplicateErr = wb.SetTx(duplicateTx)
+			)
+
+			require.NoError(t, wb.Commit())
+
+			savedBlock, err := s.GetBlock(uint64(changedBlock.Height))
+			require.NoError(t, err)
+
+			savedTx, err := s.GetTx(uint64(changedTx.Height), changedTx.Index)
+			require.NoError(t, err)
+
+			_, duplicateGetErr := s.GetTx(uint64(duplicateTx.Height), duplicateTx.Index)
+
+			switch testCase.policy {
+			case ConflictOverwrite:
+				require.NoError(t, blockErr)
+				require.NoError(t, txErr)
+				require.NoError(t, duplicateErr)
+
+				assert.Equal(t, changedBlock, savedBlock)
+				assert.Equal(t, changedTx, savedTx)
+				assert.NoError(t, duplicateGetErr)
+			case ConflictSkip:
+				require.ErrorIs(t, blockErr, storageErrors.ErrSkipped)
+				require.ErrorIs(t, txErr, storageErrors.ErrSkipped)
+				require.ErrorIs(t, duplicateErr, storageErrors.ErrSkipped)
+
+				assert.Equal(t, blocks[0], savedBlock)
+				assert.Equal(t, txs[0], savedTx)
+				assert.ErrorIs(t, duplicateGetErr, storageErrors.ErrNotFound)
+			case ConflictError:
+				assert.ErrorIs(t, blockErr, storageErrors.ErrConflict)
+				assert.ErrorIs(t, txErr, storageErrors.ErrConflict)
+				assert.ErrorIs(t, duplicateErr, storageErrors.ErrConflict)
+
+				assert.Equal(t, blocks[0], savedBlock)
+				assert.Equal(t, txs[0], savedTx)
+				assert.ErrorIs(t, duplicateGetErr, storageErrors.ErrNotFound)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"unsafe"
 
+	"github.com/DataDog/zstd"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/pkg/errors"
@@ -225,13 +226,76 @@ func decodeBytesInternal(
 	}
 }
 
-// encodeBlock encodes the block in Amino binary
-func encodeBlock(block *types.Block) ([]byte, error) {
-	return amino.Marshal(block)
+// The leading (format) byte of the block and tx result values.
+// Values without a format byte are legacy Amino values, whose encoding never starts with one
+const (
+	formatProtobuf     byte = 0x01 // protobuf encoded value
+	formatProtobufZstd byte = 0x02 // zstd compressed, protobuf encoded value
+)
+
+// isLegacyValue returns a flag indicating if the stored value is Amino encoded
+func isLegacyValue(value []byte) bool {
+	return len(value) == 0 || (value[0] != formatProtobuf && value[0] != formatProtobufZstd)
+}
+
+// encodeValue prefixes the protobuf encoded value with its format byte,
+// compressing it first if the compression level is set.
+// Values that don't shrink when compressed are stored uncompressed
+func encodeValue(value []byte, compressionLevel int) ([]byte, error) {
+	if compressionLevel > 0 {
+		compressed, err := zstd.CompressLevel(nil, value, compressionLevel)
+		if err != nil {
+			return nil, fmt.Errorf("unable to compress value, %w", err)
+		}
+
+		if len(compressed) < len(value) {
+			return append([]byte{formatProtobufZstd}, compressed...), nil
+		}
+	}
+
+	return append([]byte{formatProtobuf}, value...), nil
+}
+
+// decodeValue returns the protobuf encoded value, decompressing it if needed
+func decodeValue(value []byte) ([]byte, error) {
+	if value[0] == formatProtobuf {
+		return value[1:], nil
+	}
+
+	decompressed, err := zstd.Decompress(nil, value[1:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress value, %w", err)
+	}
+
+	return decompressed, nil
+}
+
+// encodeBlock encodes the block in protobuf
+func encodeBlock(block *types.Block, compressionLevel int) ([]byte, error) {
+	return encodeValue(marshalBlockProto(block), compressionLevel)
 }
 
-// decodeBlock decodes the Amino encoded block
+// decodeBlock decodes the protobuf (or legacy Amino) encoded block
 func decodeBlock(encodedBlock []byte) (*types.Block, error) {
+	if isLegacyValue(encodedBlock) {
+		return decodeAminoBlock(encodedBlock)
+	}
+
+	value, err := decodeValue(encodedBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := unmarshalBlockProto(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal protobuf block, %w", err)
+	}
+
+	return block, nil
+}
+
+// decodeAminoBlock decodes the Amino encoded block
+func decodeAminoBlock(encodedBlock []byte) (*types.Block, error) {
 	var block types.Block
 
 	if err := amino.Unmarshal(encodedBlock, &block); err != nil {
@@ -241,13 +305,37 @@ func decodeBlock(encodedBlock []byte) (*types.Block, error) {
 	return &block, nil
 }
 
-// encodeTx encodes the tx result in Amino binary
-func encodeTx(tx *types.TxResult) ([]byte, error) {
-	return amino.Marshal(tx)
+// encodeTx encodes the tx result in protobuf
+func encodeTx(tx *types.TxResult, compressionLevel int) ([]byte, error) {
+	encodedTx, err := marshalTxProto(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeValue(encodedTx, compressionLevel)
 }
 
-// decodeTx decodes the Amino encoded tx result
+// decodeTx decodes the protobuf (or legacy Amino) encoded tx result
 func decodeTx(encodedTx []byte) (*types.TxResult, error) {
+	if isLegacyValue(encodedTx) {
+		return decodeAminoTx(encodedTx)
+	}
+
+	value, err := decodeValue(encodedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := unmarshalTxProto(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal protobuf tx, %w", err)
+	}
+
+	return tx, nil
+}
+
+// decodeAminoTx decodes the Amino encoded tx result
+func decodeAminoTx(encodedTx []byte) (*types.TxResult, error) {
 	var tx types.TxResult
 
 	if err := amino.Unmarshal(encodedTx, &tx); err != nil {
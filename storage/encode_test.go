@@ -0,0 +1,364 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateFullBlock generates a block with all the fields set
+func generateFullBlock(height int64) *types.Block {
+	blockID := types.BlockID{
+		Hash: []byte("block hash"),
+		PartsHeader: types.PartSetHeader{
+			Total: 2,
+			Hash:  []byte("parts hash"),
+		},
+	}
+
+	return &types.Block{
+		Header: types.Header{
+			Version:            "v1",
+			ChainID:            "dev",
+			Height:             height,
+			Time:               time.Unix(1700000000, 42).UTC(),
+			NumTxs:             2,
+			TotalTxs:           10,
+			AppVersion:         "v2",
+			LastBlockID:        blockID,
+			LastCommitHash:     []byte("last commit hash"),
+			DataHash:           []byte("data hash"),
+			ValidatorsHash:     []byte("validators hash"),
+			NextValidatorsHash: []byte("next validators hash"),
+			ConsensusHash:      []byte("consensus hash"),
+			AppHash:            []byte("app hash"),
+			LastResultsHash:    []byte("last results hash"),
+			ProposerAddress:    crypto.Address{1},
+		},
+		Data: types.Data{
+			Txs: types.Txs{[]byte("tx 1"), []byte("tx 2")},
+		},
+		LastCommit: &types.Commit{
+			BlockID: blockID,
+			Precommits: []*types.CommitSig{
+				{
+					Type:             2,
+					Height:           height - 1,
+					Round:            1,
+					BlockID:          blockID,
+					Timestamp:        time.Unix(1700000000, 0).UTC(),
+					ValidatorAddress: crypto.Address{2},
+					ValidatorIndex:   0,
+					Signature:        []byte("signature"),
+				},
+				nil, // absent validator
+			},
+		},
+	}
+}
+
+// generateFullTx generates a tx result with all the fields set
+func generateFullTx(height int64) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Index:  1,
+		Tx:     []byte("tx"),
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Error:  abci.StringError("error"),
+				Data:   []byte("data"),
+				Events: []abci.Event{abci.EventString("event")},
+				Log:    "log",
+				Info:   "info",
+			},
+			GasWanted: 100,
+			GasUsed:   50,
+		},
+	}
+}
+
+// legacyBlock returns a copy of the block with no absent precommits
+func legacyBlock(block *types.Block) *types.Block {
+	commit := *block.LastCommit
+	commit.Precommits = commit.Precommits[:1]
+
+	return &types.Block{
+		Header:     block.Header,
+		Data:       block.Data,
+		LastCommit: &commit,
+	}
+}
+
+func TestEncode_Block(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		block *types.Block
+		name  string
+	}{
+		{
+			generateFullBlock(10),
+			"full block",
+		},
+		{
+			&types.Block{
+				Header: types.Header{
+					Height: 1,
+				},
+			},
+			"empty block",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := encodeBlock(testCase.block, DefaultCompressionLevel)
+			require.NoError(t, err)
+
+			assert.False(t, isLegacyValue(encoded))
+
+			decoded, err := decodeBlock(encoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.block, decoded)
+		})
+	}
+}
+
+func TestEncode_Tx(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		tx   *types.TxResult
+		name string
+	}{
+		{
+			generateFullTx(10),
+			"full tx result",
+		},
+		{
+			&types.TxResult{
+				Height: 1,
+				Tx:     []byte("tx"),
+			},
+			"empty tx result",
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := encodeTx(testCase.tx, DefaultCompressionLevel)
+			require.NoError(t, err)
+
+			assert.False(t, isLegacyValue(encoded))
+
+			decoded, err := decodeTx(encoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.tx, decoded)
+		})
+	}
+}
+
+func TestEncode_Legacy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		block = generateFullBlock(10)
+		tx    = generateFullTx(10)
+	)
+
+	encodedBlock, err := amino.Marshal(legacyBlock(block))
+	require.NoError(t, err)
+
+	encodedTx, err := amino.Marshal(tx)
+	require.NoError(t, err)
+
+	// Make sure the legacy Amino values are still decoded
+	require.True(t, isLegacyValue(encodedBlock))
+	require.True(t, isLegacyValue(encodedTx))
+
+	decodedBlock, err := decodeBlock(encodedBlock)
+	require.NoError(t, err)
+
+	assert.Equal(t, block.Header, decodedBlock.Header)
+
+	decodedTx, err := decodeTx(encodedTx)
+	require.NoError(t, err)
+
+	assert.Equal(t, tx, decodedTx)
+}
+
+func TestStorage_MigrateEncoding(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var (
+		blockNum = 5
+
+		blocks = make([]*types.Block, 0, blockNum)
+		txs    = make([]*types.TxResult, 0, blockNum)
+	)
+
+	// Save legacy Amino values, and a protobuf value
+	for i := 1; i <= blockNum; i++ {
+		block := generateFullBlock(int64(i))
+		tx := generateFullTx(int64(i))
+
+		blocks = append(blocks, block)
+		txs = append(txs, tx)
+
+		encodedBlock, err := amino.Marshal(legacyBlock(block))
+		require.NoError(t, err)
+
+		encodedTx, err := amino.Marshal(tx)
+		require.NoError(t, err)
+
+		if i == blockNum {
+			encodedBlock, err = encodeBlock(block, 0)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, s.db.Set(s.schema.keyBlock(s.ns, uint64(i)), encodedBlock, nil))
+		require.NoError(t, s.db.Set(s.schema.keyTx(s.ns, uint64(i), tx.Index), encodedTx, nil))
+	}
+
+	// Make sure only the legacy values are migrated
+	migrated, err := s.MigrateEncoding()
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*blockNum-1, migrated)
+
+	for i := 1; i <= blockNum; i++ {
+		value, err := get(s.db, s.schema.keyBlock(s.ns, uint64(i)))
+		require.NoError(t, err)
+
+		assert.False(t, isLegacyValue(value))
+
+		block, err := s.GetBlock(uint64(i))
+		require.NoError(t, err)
+
+		assert.Equal(t, blocks[i-1].Header, block.Header)
+
+		tx, err := s.GetTx(uint64(i), txs[i-1].Index)
+		require.NoError(t, err)
+
+		assert.Equal(t, txs[i-1], tx)
+	}
+
+	// Make sure a second migration is a no-op
+	migrated, err = s.MigrateEncoding()
+	require.NoError(t, err)
+
+	assert.Zero(t, migrated)
+}
+
+func TestEncode_Compression(t *testing.T) {
+	t.Parallel()
+
+	block := generateFullBlock(10)
+	block.Txs = types.Txs{bytes.Repeat([]byte("compressible tx "), 100)}
+
+	t.Run("compressed value", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := encodeBlock(block, DefaultCompressionLevel)
+		require.NoError(t, err)
+
+		uncompressed, err := encodeBlock(block, 0)
+		require.NoError(t, err)
+
+		// Make sure the value is compressed
+		assert.Equal(t, formatProtobufZstd, encoded[0])
+		assert.Equal(t, formatProtobuf, uncompressed[0])
+		assert.Less(t, len(encoded), len(uncompressed))
+
+		decoded, err := decodeBlock(encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, block, decoded)
+	})
+
+	t.Run("incompressible value", func(t *testing.T) {
+		t.Parallel()
+
+		tx := &types.TxResult{
+			Height: 1,
+			Tx:     []byte("tx"),
+		}
+
+		encoded, err := encodeTx(tx, DefaultCompressionLevel)
+		require.NoError(t, err)
+
+		// Make sure the small value is stored uncompressed
+		assert.Equal(t, formatProtobuf, encoded[0])
+
+		decoded, err := decodeTx(encoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, tx, decoded)
+	})
+
+	t.Run("mixed values", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewPebble(t.TempDir(), WithCompressionLevel(0))
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		compressed := generateFullBlock(11)
+		compressed.Txs = block.Txs
+
+		// Save an uncompressed and a compressed block
+		wb := s.WriteBatch()
+		require.NoError(t, wb.SetBlock(block))
+		require.NoError(t, wb.Commit())
+
+		s.compressionLevel = DefaultCompressionLevel
+		wb = s.WriteBatch()
+
+		require.NoError(t, wb.SetBlock(compressed))
+		require.NoError(t, wb.Commit())
+
+		// Make sure both are readable
+		it, err := s.BlockIterator(10, 12)
+		require.NoError(t, err)
+
+		defer it.Close()
+
+		decoded := make([]*types.Block, 0, 2)
+
+		for it.Next() {
+			b, err := it.Value()
+			require.NoError(t, err)
+
+			decoded = append(decoded, b)
+		}
+
+		assert.Equal(t, []*types.Block{block, compressed}, decoded)
+	})
+}
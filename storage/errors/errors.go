@@ -2,4 +2,8 @@ package errors
 
 import "github.com/gnolang/gno/tm2/pkg/errors"
 
-var ErrNotFound = errors.New("item not found in storage")
+var (
+	ErrNotFound = errors.New("item not found in storage")
+	ErrConflict = errors.New("item already saved in storage with different data")
+	ErrSkipped  = errors.New("item already saved in storage, save skipped")
+)
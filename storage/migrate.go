@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/multierr"
+)
+
+// migrateBatchSize is the number of values rewritten in a single batch
+const migrateBatchSize = 1000
+
+// rewriteFn adds the rewrite of the key-value pair to the batch,
+// returning a flag indicating if the pair was rewritten
+type rewriteFn func(b *pebble.Batch, key, value []byte) (bool, error)
+
+// MigrateEncoding rewrites the legacy Amino encoded blocks and tx results
+// of the storage namespace in the current (protobuf, compressed) encoding,
+// returning the number of rewritten values
+func (s *Pebble) MigrateEncoding() (int, error) {
+	reencodeBlock := func(b *pebble.Batch, key, value []byte) (bool, error) {
+		if !isLegacyValue(value) {
+			return false, nil
+		}
+
+		block, err := decodeAminoBlock(value)
+		if err != nil {
+			return false, err
+		}
+
+		encodedBlock, err := encodeBlock(block, s.compressionLevel)
+		if err != nil {
+			return false, err
+		}
+
+		return true, b.Set(key, encodedBlock, nil)
+	}
+
+	reencodeTx := func(b *pebble.Batch, key, value []byte) (bool, error) {
+		if !isLegacyValue(value) {
+			return false, nil
+		}
+
+		tx, err := decodeAminoTx(value)
+		if err != nil {
+			return false, err
+		}
+
+		encodedTx, err := encodeTx(tx, s.compressionLevel)
+		if err != nil {
+			return false, err
+		}
+
+		return true, b.Set(key, encodedTx, nil)
+	}
+
+	if s.schema == schemaV2 {
+		kindOffset := heightKindOffset(s.ns)
+
+		migrated, err := rewrite(s.db, s.ns, prefixKeyHeights, func(b *pebble.Batch, key, value []byte) (bool, error) {
+			if key[kindOffset] == kindBlock {
+				return reencodeBlock(b, key, value)
+			}
+
+			return reencodeTx(b, key, value)
+		})
+		if err != nil {
+			return migrated, fmt.Errorf("unable to migrate encoding, %w", err)
+		}
+
+		return migrated, nil
+	}
+
+	blocks, err := rewrite(s.db, s.ns, prefixKeyBlocks, reencodeBlock)
+	if err != nil {
+		return blocks, fmt.Errorf("unable to migrate blocks, %w", err)
+	}
+
+	txs, err := rewrite(s.db, s.ns, prefixKeyTxs, reencodeTx)
+	if err != nil {
+		return blocks + txs, fmt.Errorf("unable to migrate txs, %w", err)
+	}
+
+	return blocks + txs, nil
+}
+
+// MigrateSchema moves the blocks and tx results of the storage namespace
+// to the current (v2) key schema, where the data of each height is contiguous,
+// returning the number of moved values. The migration can be resumed if interrupted,
+// and the namespace only switches to the new schema once it completes
+func (s *Pebble) MigrateSchema() (int, error) {
+	if s.schema == schemaV2 {
+		return 0, nil
+	}
+
+	blocks, err := rewrite(s.db, s.ns, prefixKeyBlocks, func(b *pebble.Batch, key, value []byte) (bool, error) {
+		blockNum, _, err := decodeDataKey(s.ns, key)
+		if err != nil {
+			return false, err
+		}
+
+		return true, multierr.Combine(
+			b.Set(keyHeightBlock(s.ns, blockNum), value, nil),
+			b.Delete(key, nil),
+		)
+	})
+	if err != nil {
+		return blocks, fmt.Errorf("unable to migrate blocks, %w", err)
+	}
+
+	txs, err := rewrite(s.db, s.ns, prefixKeyTxs, func(b *pebble.Batch, key, value []byte) (bool, error) {
+		blockNum, rest, err := decodeDataKey(s.ns, key)
+		if err != nil {
+			return false, err
+		}
+
+		_, txIndex, err := decodeUint32Ascending(rest)
+		if err != nil {
+			return false, err
+		}
+
+		tx, err := decodeTx(value)
+		if err != nil {
+			return false, err
+		}
+
+		txKey := keyHeightTx(s.ns, blockNum, txIndex)
+		hashKey := keyHashTx(s.ns, base64.StdEncoding.EncodeToString(tx.Tx.Hash()))
+
+		// The hash index is moved in the same batch as the tx
+		return true, multierr.Combine(
+			b.Set(txKey, value, nil),
+			b.Set(hashKey, txKey, nil),
+			b.Delete(key, nil),
+		)
+	})
+	if err != nil {
+		return blocks + txs, fmt.Errorf("unable to migrate txs, %w", err)
+	}
+
+	// The address index entries are keyed by height, so they are
+	// pointed to the new tx keys (again, if resumed)
+	_, err = rewrite(s.db, s.ns, prefixKeyTxByAddress, func(b *pebble.Batch, key, _ []byte) (bool, error) {
+		rest, _, err := decodeUnsafeStringAscending(key[len(s.ns):], nil)
+		if err != nil {
+			return false, err
+		}
+
+		rest, _, err = decodeUnsafeStringAscending(rest, nil)
+		if err != nil {
+			return false, err
+		}
+
+		rest, blockNum, err := decodeUint64Ascending(rest)
+		if err != nil {
+			return false, err
+		}
+
+		_, txIndex, err := decodeUint32Ascending(rest)
+		if err != nil {
+			return false, err
+		}
+
+		return true, b.Set(key, keyHeightTx(s.ns, blockNum, txIndex), nil)
+	})
+	if err != nil {
+		return blocks + txs, fmt.Errorf("unable to migrate address index, %w", err)
+	}
+
+	if err := saveSchema(s.db, s.ns, schemaV2); err != nil {
+		return blocks + txs, err
+	}
+
+	s.schema = schemaV2
+
+	return blocks + txs, nil
+}
+
+// decodeDataKey decodes the height of the schema v1 block or tx key,
+// returning it along with the rest of the key
+func decodeDataKey(ns, key []byte) (uint64, []byte, error) {
+	rest, _, err := decodeUnsafeStringAscending(key[len(ns):], nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rest, blockNum, err := decodeUint64Ascending(rest)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return blockNum, rest, nil
+}
+
+// rewrite rewrites the key-value pairs of the namespace under the given key prefix
+// in batches, returning the number of rewritten pairs
+func rewrite(db *pebble.DB, ns []byte, keyPrefix string, rewriteFn rewriteFn) (int, error) {
+	prefix := encodeStringAscending(slices.Clone(ns), keyPrefix)
+
+	snap := db.NewSnapshot()
+	defer snap.Close()
+
+	it, err := snap.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		rewritten = 0
+		pending   = 0
+		b         = db.NewBatch()
+	)
+
+	for it.First(); it.Valid(); it.Next() {
+		ok, err := rewriteFn(b, it.Key(), it.Value())
+		if err != nil {
+			return rewritten, multierr.Combine(
+				fmt.Errorf("unable to rewrite value, %w", err),
+				b.Close(),
+				it.Close(),
+			)
+		}
+
+		if !ok {
+			continue
+		}
+
+		pending++
+
+		if pending < migrateBatchSize {
+			continue
+		}
+
+		if err := b.Commit(pebble.Sync); err != nil {
+			return rewritten, multierr.Combine(err, b.Close(), it.Close())
+		}
+
+		if err := b.Close(); err != nil {
+			return rewritten, multierr.Combine(err, it.Close())
+		}
+
+		rewritten += pending
+		pending = 0
+
+		b = db.NewBatch()
+	}
+
+	if err := b.Commit(pebble.Sync); err != nil {
+		return rewritten, multierr.Combine(err, b.Close(), it.Close())
+	}
+
+	rewritten += pending
+
+	return rewritten, multierr.Combine(b.Close(), it.Close())
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/objstore"
+)
+
+// DefaultCompressionLevel is the default zstd level
+// of the stored block and tx result values
+const DefaultCompressionLevel = 3
+
+type Option func(s *Pebble)
+
+// WithNamespace sets the key namespace (chain / network identifier)
+// used to prefix all storage keys
+func WithNamespace(namespace string) Option {
+	return func(s *Pebble) {
+		s.namespace = namespace
+		s.ns = keyNamespace(namespace)
+	}
+}
+
+// WithCompressionLevel sets the zstd level used to compress
+// the stored block and tx result values. Level 0 disables compression
+func WithCompressionLevel(level int) Option {
+	return func(s *Pebble) {
+		s.compressionLevel = level
+	}
+}
+
+// WithReadOnly opens the storage in read-only mode, for serving an existing DB.
+// The storage writes fail
+func WithReadOnly() Option {
+	return func(s *Pebble) {
+		s.readOnly = true
+	}
+}
+
+// WithConflictPolicy sets the handling of the block and tx saves conflicting
+// with the data already saved for the height / hash. Saving identical data is a no-op
+// for all policies other than ConflictOverwrite, the default one
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(s *Pebble) {
+		s.conflicts = policy
+	}
+}
+
+// WithDecoder sets the chain decoder of the txs, applying the decoding rules of the chain upgrades
+// for their address index entries. The namespace views share it (see Pebble.WithDecoder)
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(s *Pebble) {
+		s.decoder = decoder
+	}
+}
+
+// WithColdTier sets the cold tier DB path, holding the heights moved out of the storage DB
+// (see MoveToColdTier), compressed with the given zstd level. The reads span both tiers
+func WithColdTier(path string, compressionLevel int) Option {
+	return func(s *Pebble) {
+		s.coldPath = path
+		s.coldCompressionLevel = compressionLevel
+	}
+}
+
+// WithPruneArchive sets the object store the pruned heights are archived to before being
+// removed (see Prune), under the given key prefix. The archived segments are restored
+// on demand, once read
+func WithPruneArchive(store objstore.Store, prefix string) Option {
+	return func(s *Pebble) {
+		s.archive = &pruneArchive{
+			store:     store,
+			prefix:    prefix,
+			restoring: make(map[string]chan struct{}),
+			now:       time.Now,
+		}
+	}
+}
+
+// WithRestoredSegmentTTL sets the time the segments restored from the prune archive
+// are kept for, before being pruned again (see Prune)
+func WithRestoredSegmentTTL(ttl time.Duration) Option {
+	return func(s *Pebble) {
+		s.restoredTTL = ttl
+	}
+}
+
+// WithRestoreTimeout sets the timeout of fetching a single segment from the prune archive,
+// for the reads of the pruned heights. The reads fail once the fetch times out
+func WithRestoreTimeout(timeout time.Duration) Option {
+	return func(s *Pebble) {
+		s.restoreTimeout = timeout
+	}
+}
+
+// WithRestoreLimit sets the maximum number of segments restored from the prune archive per minute,
+// shared by the namespace views. The reads of the pruned heights over the limit fail with ErrRestoreLimit.
+// Zero disables the on-demand restores
+func WithRestoreLimit(segmentsPerMinute int) Option {
+	return func(s *Pebble) {
+		s.restoreLimit = segmentsPerMinute
+	}
+}
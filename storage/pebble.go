@@ -1,15 +1,19 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"time"
 
 	"github.com/cockroachdb/pebble"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"go.uber.org/multierr"
 
+	"github.com/gnolang/tx-indexer/decode"
 	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
 )
 
@@ -26,10 +30,42 @@ const (
 
 	// prefixKeyTxByHash is a secondary index to query transaction by hash
 	prefixKeyTxByHash = "/index/txh/"
+
+	// prefixKeyTxByAddress is a secondary index to query transactions
+	// by the addresses participating in them
+	prefixKeyTxByAddress = "/index/txa/"
+
+	// prefixKeyNamespace is the prefix for all keys of a namespace (chain / network)
+	prefixKeyNamespace = "/ns/"
+
+	// prefixKeyPlugins is the prefix for the data saved by indexer plugins,
+	// each one using its own sub-namespace
+	prefixKeyPlugins = "/plugins/"
 )
 
-func keyTx(blockNum uint64, txIndex uint32) []byte {
+// keyNamespace returns the key prefix for the given namespace.
+// The empty namespace has no prefix, to keep compatibility with existing DBs
+func keyNamespace(namespace string) []byte {
+	if namespace == "" {
+		return nil
+	}
+
 	var key []byte
+	key = encodeStringAscending(key, prefixKeyNamespace)
+	key = encodeStringAscending(key, namespace)
+
+	return key
+}
+
+func keyLatest(ns []byte) []byte {
+	key := slices.Clone(ns)
+	key = append(key, keyLatestHeight...)
+
+	return key
+}
+
+func keyTx(ns []byte, blockNum uint64, txIndex uint32) []byte {
+	key := slices.Clone(ns)
 	key = encodeStringAscending(key, prefixKeyTxs)
 	key = encodeUint64Ascending(key, blockNum)
 	key = encodeUint32Ascending(key, txIndex)
@@ -37,47 +73,198 @@ func keyTx(blockNum uint64, txIndex uint32) []byte {
 	return key
 }
 
-func keyHashTx(hash string) []byte {
-	var key []byte
+func keyHashTx(ns []byte, hash string) []byte {
+	key := slices.Clone(ns)
 	key = encodeStringAscending(key, prefixKeyTxByHash)
 	key = encodeStringAscending(key, hash)
 
 	return key
 }
 
-func keyBlock(blockNum uint64) []byte {
-	var key []byte
+func keyAddressTx(ns []byte, address string, blockNum uint64, txIndex uint32) []byte {
+	key := slices.Clone(ns)
+	key = encodeStringAscending(key, prefixKeyTxByAddress)
+	key = encodeStringAscending(key, address)
+	key = encodeUint64Ascending(key, blockNum)
+	key = encodeUint32Ascending(key, txIndex)
+
+	return key
+}
+
+func keyBlock(ns []byte, blockNum uint64) []byte {
+	key := slices.Clone(ns)
 	key = encodeStringAscending(key, prefixKeyBlocks)
 	key = encodeUint64Ascending(key, blockNum)
 
 	return key
 }
 
+// keyPluginPrefix returns the key prefix for all the plugin data
+func keyPluginPrefix(ns []byte, plugin string) []byte {
+	key := slices.Clone(ns)
+	key = encodeStringAscending(key, prefixKeyPlugins)
+	key = encodeStringAscending(key, plugin)
+
+	return key
+}
+
+func keyPlugin(ns []byte, plugin string, k []byte) []byte {
+	return append(keyPluginPrefix(ns, plugin), k...)
+}
+
 var _ Storage = &Pebble{}
 
 // Pebble is the instance of an embedded storage
 type Pebble struct {
 	db *pebble.DB
+
+	// namespace is the chain / network identifier,
+	// whose encoded form (ns) prefixes all keys
+	namespace string
+	ns        []byte
+
+	// view is set for namespace views sharing the parent DB,
+	// which are not in charge of closing it
+	view bool
+
+	// compressionLevel is the zstd level of the
+	// stored block and tx result values, if any
+	compressionLevel int
+
+	// readOnly marks the storage opened in read-only mode
+	readOnly bool
+
+	// conflicts is the handling of the saves conflicting with the saved data
+	conflicts ConflictPolicy
+
+	// schema is the key schema version of the namespace data
+	schema schemaVersion
+
+	// feed publishes the committed write batches,
+	// shared by the namespace views
+	feed *changefeed
+
+	// cold is the cold tier DB, holding the heights moved out of
+	// the (hot) DB, compressed with the cold compression level, if any
+	cold                 *pebble.DB
+	coldPath             string
+	coldCompressionLevel int
+
+	// archive is the object store the pruned heights are archived to,
+	// and restored from on demand, if any
+	archive *pruneArchive
+
+	// restoredTTL is the time the restored segments are kept for,
+	// restoreLimit the maximum number of segments restored per minute,
+	// and restoreTimeout the timeout of fetching a single segment
+	restoredTTL    time.Duration
+	restoreLimit   int
+	restoreTimeout time.Duration
+
+	// decoder decodes the txs of the chain, for their address index entries
+	decoder *decode.Decoder
 }
 
 // NewPebble creates a new storage instance at the given path
-func NewPebble(path string) (*Pebble, error) {
+func NewPebble(path string, opts ...Option) (*Pebble, error) {
+	s := &Pebble{
+		compressionLevel: DefaultCompressionLevel,
+		feed:             newChangefeed(),
+		restoredTTL:      DefaultRestoredSegmentTTL,
+		restoreLimit:     DefaultRestoreLimit,
+		restoreTimeout:   DefaultRestoreTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	db, err := pebble.Open(path, &pebble.Options{
 		// TODO: EventListener
 		// Start with defaults
+		ReadOnly: s.readOnly,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("unable to create DB, %w", err)
 	}
 
-	return &Pebble{
-		db: db,
-	}, nil
+	s.db = db
+
+	if s.coldPath != "" {
+		if s.cold, err = pebble.Open(s.coldPath, &pebble.Options{ReadOnly: s.readOnly}); err != nil {
+			return nil, multierr.Append(fmt.Errorf("unable to create cold tier DB, %w", err), db.Close())
+		}
+	}
+
+	if s.schema, err = loadSchema(db, s.ns, s.readOnly); err != nil {
+		return nil, multierr.Combine(err, s.Close())
+	}
+
+	if err := initAddressIndex(db, s.ns, s.readOnly); err != nil {
+		return nil, multierr.Combine(err, s.Close())
+	}
+
+	return s, nil
+}
+
+// Namespace returns the storage key namespace (chain / network identifier)
+func (s *Pebble) Namespace() string {
+	return s.namespace
+}
+
+// WithNamespace returns a view of the storage that shares the same underlying DB,
+// but whose keys are prefixed with the given namespace. Closing the view does not close the DB
+func (s *Pebble) WithNamespace(namespace string) (*Pebble, error) {
+	view := &Pebble{
+		db:        s.db,
+		namespace: namespace,
+		ns:        keyNamespace(namespace),
+		view:      true,
+
+		compressionLevel: s.compressionLevel,
+		readOnly:         s.readOnly,
+		conflicts:        s.conflicts,
+		feed:             s.feed,
+
+		cold:                 s.cold,
+		coldPath:             s.coldPath,
+		coldCompressionLevel: s.coldCompressionLevel,
+
+		archive:        s.archive,
+		restoredTTL:    s.restoredTTL,
+		restoreLimit:   s.restoreLimit,
+		restoreTimeout: s.restoreTimeout,
+
+		decoder: s.decoder,
+	}
+
+	var err error
+
+	if view.schema, err = loadSchema(s.db, view.ns, s.readOnly); err != nil {
+		return nil, err
+	}
+
+	if err := initAddressIndex(s.db, view.ns, s.readOnly); err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+// WithDecoder returns a view of the storage that shares the same underlying DB (and namespace),
+// but decodes the txs with the given chain decoder. Closing the view does not close the DB
+func (s *Pebble) WithDecoder(decoder *decode.Decoder) *Pebble {
+	view := *s
+
+	view.view = true
+	view.decoder = decoder
+
+	return &view
 }
 
 // GetLatestHeight fetches the latest saved height from storage
 func (s *Pebble) GetLatestHeight() (uint64, error) {
-	height, c, err := s.db.Get([]byte(keyLatestHeight))
+	height, c, err := s.db.Get(keyLatest(s.ns))
 	if errors.Is(err, pebble.ErrNotFound) {
 		return 0, storageErrors.ErrNotFound
 	}
@@ -95,7 +282,28 @@ func (s *Pebble) GetLatestHeight() (uint64, error) {
 
 // GetBlock fetches the specified block from storage, if any
 func (s *Pebble) GetBlock(blockNum uint64) (*types.Block, error) {
-	block, c, err := s.db.Get(keyBlock(blockNum))
+	block, err := s.getBlock(s.db, blockNum)
+	if errors.Is(err, storageErrors.ErrNotFound) && s.cold != nil {
+		block, err = s.getBlock(s.cold, blockNum)
+	}
+
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// The block might be pruned
+		restored, restoreErr := s.restorePruned(blockNum, blockNum+1)
+		if restoreErr != nil {
+			return nil, restoreErr
+		}
+
+		if restored {
+			return s.GetBlock(blockNum)
+		}
+	}
+
+	return block, err
+}
+
+func (s *Pebble) getBlock(r pebble.Reader, blockNum uint64) (*types.Block, error) {
+	block, c, err := r.Get(s.schema.keyBlock(s.ns, blockNum))
 	if errors.Is(err, pebble.ErrNotFound) {
 		return nil, storageErrors.ErrNotFound
 	}
@@ -111,7 +319,28 @@ func (s *Pebble) GetBlock(blockNum uint64) (*types.Block, error) {
 
 // GetTx fetches the specified tx result from storage, if any
 func (s *Pebble) GetTx(blockNum uint64, index uint32) (*types.TxResult, error) {
-	tx, c, err := s.db.Get(keyTx(blockNum, index))
+	tx, err := s.getTx(s.db, blockNum, index)
+	if errors.Is(err, storageErrors.ErrNotFound) && s.cold != nil {
+		tx, err = s.getTx(s.cold, blockNum, index)
+	}
+
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		// The tx might be pruned
+		restored, restoreErr := s.restorePruned(blockNum, blockNum+1)
+		if restoreErr != nil {
+			return nil, restoreErr
+		}
+
+		if restored {
+			return s.GetTx(blockNum, index)
+		}
+	}
+
+	return tx, err
+}
+
+func (s *Pebble) getTx(r pebble.Reader, blockNum uint64, index uint32) (*types.TxResult, error) {
+	tx, c, err := r.Get(s.schema.keyTx(s.ns, blockNum, index))
 	if errors.Is(err, pebble.ErrNotFound) {
 		return nil, storageErrors.ErrNotFound
 	}
@@ -126,7 +355,62 @@ func (s *Pebble) GetTx(blockNum uint64, index uint32) (*types.TxResult, error) {
 }
 
 func (s *Pebble) GetTxByHash(txHash string) (*types.TxResult, error) {
-	txKey, ch, err := s.db.Get(keyHashTx(txHash))
+	tx, err := s.getTxByHash(s.db, txHash)
+	if errors.Is(err, storageErrors.ErrNotFound) && s.cold != nil {
+		tx, err = s.getTxByHash(s.cold, txHash)
+	}
+
+	if errors.Is(err, storageErrors.ErrNotFound) && s.archive != nil {
+		// The tx might be pruned, with its hash index entry kept
+		height, found, heightErr := s.hashTxHeight(txHash)
+		if heightErr != nil {
+			return nil, heightErr
+		}
+
+		if !found {
+			return nil, err
+		}
+
+		restored, restoreErr := s.restorePruned(height, height+1)
+		if restoreErr != nil {
+			return nil, restoreErr
+		}
+
+		if restored {
+			return s.GetTxByHash(txHash)
+		}
+	}
+
+	return tx, err
+}
+
+// hashTxHeight returns the tx height of the hash index entry, from either tier
+func (s *Pebble) hashTxHeight(txHash string) (uint64, bool, error) {
+	txKey, err := get(s.db, keyHashTx(s.ns, txHash))
+	if errors.Is(err, storageErrors.ErrNotFound) && s.cold != nil {
+		txKey, err = get(s.cold, keyHashTx(s.ns, txHash))
+	}
+
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	heightPrefix := encodeStringAscending(slices.Clone(s.ns), prefixKeyHeights)
+	if !bytes.HasPrefix(txKey, heightPrefix) {
+		return 0, false, nil
+	}
+
+	_, height, err := decodeUint64Ascending(txKey[len(heightPrefix):])
+
+	return height, err == nil, err
+}
+
+func (s *Pebble) getTxByHash(r pebble.Reader, txHash string) (*types.TxResult, error) {
+	txKey, ch, err := r.Get(keyHashTx(s.ns, txHash))
 	if errors.Is(err, pebble.ErrNotFound) {
 		return nil, storageErrors.ErrNotFound
 	}
@@ -135,7 +419,7 @@ func (s *Pebble) GetTxByHash(txHash string) (*types.TxResult, error) {
 		return nil, err
 	}
 
-	tx, c, err := s.db.Get(txKey)
+	tx, c, err := r.Get(txKey)
 
 	// Close after using the txKey array output
 	defer ch.Close()
@@ -154,15 +438,46 @@ func (s *Pebble) GetTxByHash(txHash string) (*types.TxResult, error) {
 }
 
 func (s *Pebble) BlockIterator(fromBlockNum, toBlockNum uint64) (Iterator[*types.Block], error) {
-	fromKey := keyBlock(fromBlockNum)
+	return s.blocks(fromBlockNum, toBlockNum, false)
+}
+
+// ReverseBlockIterator iterates over Blocks, newest first, limiting the results
+// to be between the provided block numbers (as BlockIterator)
+func (s *Pebble) ReverseBlockIterator(fromBlockNum, toBlockNum uint64) (Iterator[*types.Block], error) {
+	return s.blocks(fromBlockNum, toBlockNum, true)
+}
 
+func (s *Pebble) blocks(fromBlockNum, toBlockNum uint64, reverse bool) (Iterator[*types.Block], error) {
 	if toBlockNum == 0 {
 		toBlockNum = math.MaxInt64
 	}
 
-	toKey := keyBlock(toBlockNum)
+	newIter := func(snap *pebble.Snapshot, from, to uint64) (Iterator[*types.Block], error) {
+		return s.blockIterator(snap, from, to, reverse)
+	}
 
-	snap := s.db.NewSnapshot()
+	return tieredIterator(s, fromBlockNum, toBlockNum, reverse, newIter)
+}
+
+func (s *Pebble) blockIterator(
+	snap *pebble.Snapshot,
+	fromBlockNum,
+	toBlockNum uint64,
+	reverse bool,
+) (Iterator[*types.Block], error) {
+	var (
+		fromKey = keyBlock(s.ns, fromBlockNum)
+		toKey   = keyBlock(s.ns, toBlockNum)
+
+		kindOffset = -1
+	)
+
+	if s.schema == schemaV2 {
+		// The blocks are interleaved with their txs
+		fromKey = keyHeight(s.ns, fromBlockNum)
+		toKey = keyHeight(s.ns, toBlockNum)
+		kindOffset = heightKindOffset(s.ns)
+	}
 
 	it, err := snap.NewIter(&pebble.IterOptions{
 		LowerBound: fromKey,
@@ -172,7 +487,7 @@ func (s *Pebble) BlockIterator(fromBlockNum, toBlockNum uint64) (Iterator[*types
 		return nil, multierr.Append(snap.Close(), err)
 	}
 
-	return &PebbleBlockIter{i: it, s: snap}, nil
+	return &PebbleBlockIter{i: it, s: snap, kindOffset: kindOffset, reverse: reverse}, nil
 }
 
 func (s *Pebble) TxIterator(
@@ -181,8 +496,27 @@ func (s *Pebble) TxIterator(
 	fromTxIndex,
 	toTxIndex uint32,
 ) (Iterator[*types.TxResult], error) {
-	fromKey := keyTx(fromBlockNum, fromTxIndex)
+	return s.txs(fromBlockNum, toBlockNum, fromTxIndex, toTxIndex, false)
+}
+
+// ReverseTxIterator iterates over transactions, newest first, limiting the results
+// to be between the provided block numbers and transaction indexes (as TxIterator)
+func (s *Pebble) ReverseTxIterator(
+	fromBlockNum,
+	toBlockNum uint64,
+	fromTxIndex,
+	toTxIndex uint32,
+) (Iterator[*types.TxResult], error) {
+	return s.txs(fromBlockNum, toBlockNum, fromTxIndex, toTxIndex, true)
+}
 
+func (s *Pebble) txs(
+	fromBlockNum,
+	toBlockNum uint64,
+	fromTxIndex,
+	toTxIndex uint32,
+	reverse bool,
+) (Iterator[*types.TxResult], error) {
 	if toBlockNum == 0 {
 		toBlockNum = math.MaxInt64
 	}
@@ -191,10 +525,39 @@ func (s *Pebble) TxIterator(
 		toTxIndex = math.MaxUint32
 	}
 
-	toKey := keyTx(toBlockNum, toTxIndex)
+	newIter := func(snap *pebble.Snapshot, from, to uint64) (Iterator[*types.TxResult], error) {
+		// The tier boundary splits the range at the boundary height
+		var fromIndex, toIndex uint32
 
-	snap := s.db.NewSnapshot()
+		if from == fromBlockNum {
+			fromIndex = fromTxIndex
+		}
+
+		if to == toBlockNum {
+			toIndex = toTxIndex
+		}
 
+		return s.txIterator(
+			snap,
+			s.schema.keyTx(s.ns, from, fromIndex),
+			s.schema.keyTx(s.ns, to, toIndex),
+			fromTxIndex,
+			toTxIndex,
+			reverse,
+		)
+	}
+
+	return tieredIterator(s, fromBlockNum, toBlockNum, reverse, newIter)
+}
+
+func (s *Pebble) txIterator(
+	snap *pebble.Snapshot,
+	fromKey,
+	toKey []byte,
+	fromTxIndex,
+	toTxIndex uint32,
+	reverse bool,
+) (Iterator[*types.TxResult], error) {
 	it, err := snap.NewIter(&pebble.IterOptions{
 		LowerBound: fromKey,
 		UpperBound: toKey,
@@ -203,16 +566,138 @@ func (s *Pebble) TxIterator(
 		return nil, multierr.Append(snap.Close(), err)
 	}
 
-	return &PebbleTxIter{i: it, s: snap, fromIndex: fromTxIndex, toIndex: toTxIndex}, nil
+	return &PebbleTxIter{
+		i:         it,
+		s:         snap,
+		ns:        s.ns,
+		schema:    s.schema,
+		fromIndex: fromTxIndex,
+		toIndex:   toTxIndex,
+		reverse:   reverse,
+	}, nil
+}
+
+// TxByAddressIterator iterates over transactions the address participated in, limiting the results
+// to be between the provided block numbers. Fails with ErrAddressIndexMissing until the txs saved
+// before the address index was introduced are indexed (see MigrateAddressIndex)
+func (s *Pebble) TxByAddressIterator(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+) (Iterator[*types.TxResult], error) {
+	return s.addressTxs(address, fromBlockNum, toBlockNum, false)
+}
+
+// ReverseTxByAddressIterator iterates over transactions the address participated in, newest first,
+// limiting the results to be between the provided block numbers (as TxByAddressIterator)
+func (s *Pebble) ReverseTxByAddressIterator(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+) (Iterator[*types.TxResult], error) {
+	return s.addressTxs(address, fromBlockNum, toBlockNum, true)
+}
+
+func (s *Pebble) addressTxs(
+	address string,
+	fromBlockNum,
+	toBlockNum uint64,
+	reverse bool,
+) (Iterator[*types.TxResult], error) {
+	if err := s.checkAddressIndex(); err != nil {
+		return nil, err
+	}
+
+	if toBlockNum == 0 {
+		toBlockNum = math.MaxInt64
+	}
+
+	newIter := func(snap *pebble.Snapshot, from, to uint64) (Iterator[*types.TxResult], error) {
+		it, err := snap.NewIter(&pebble.IterOptions{
+			LowerBound: keyAddressTx(s.ns, address, from, 0),
+			UpperBound: keyAddressTx(s.ns, address, to, 0),
+		})
+		if err != nil {
+			return nil, multierr.Append(snap.Close(), err)
+		}
+
+		return &PebbleIndexTxIter{i: it, s: snap, reverse: reverse}, nil
+	}
+
+	return tieredIterator(s, fromBlockNum, toBlockNum, reverse, newIter)
+}
+
+// GetPluginValue fetches the value saved by the plugin under the given key, if any
+func (s *Pebble) GetPluginValue(plugin string, key []byte) ([]byte, error) {
+	return get(s.db, keyPlugin(s.ns, plugin, key))
+}
+
+// PluginIterator iterates over the plugin key-value pairs,
+// limiting the results to be between the provided keys. A nil toKey means no upper bound
+func (s *Pebble) PluginIterator(plugin string, fromKey, toKey []byte) (Iterator[*KeyValue], error) {
+	return s.pluginIterator(plugin, fromKey, toKey, false)
+}
+
+// ReversePluginIterator iterates over the plugin key-value pairs, last key first,
+// limiting the results to be between the provided keys (as PluginIterator)
+func (s *Pebble) ReversePluginIterator(plugin string, fromKey, toKey []byte) (Iterator[*KeyValue], error) {
+	return s.pluginIterator(plugin, fromKey, toKey, true)
+}
+
+func (s *Pebble) pluginIterator(plugin string, fromKey, toKey []byte, reverse bool) (Iterator[*KeyValue], error) {
+	prefix := keyPluginPrefix(s.ns, plugin)
+
+	upperBound := keyPlugin(s.ns, plugin, toKey)
+	if toKey == nil {
+		upperBound = prefixUpperBound(prefix)
+	}
+
+	snap := s.db.NewSnapshot()
+
+	it, err := snap.NewIter(&pebble.IterOptions{
+		LowerBound: keyPlugin(s.ns, plugin, fromKey),
+		UpperBound: upperBound,
+	})
+	if err != nil {
+		return nil, multierr.Append(snap.Close(), err)
+	}
+
+	return &PebbleKVIter{i: it, s: snap, prefix: prefix, reverse: reverse}, nil
 }
 
 func (s *Pebble) WriteBatch() Batch {
 	return &PebbleBatch{
-		b: s.db.NewBatch(),
+		b:                s.db.NewIndexedBatch(),
+		ns:               s.ns,
+		schema:           s.schema,
+		compressionLevel: s.compressionLevel,
+		conflicts:        s.conflicts,
+		feed:             s.feed,
+		decoder:          s.decoder,
+	}
+}
+
+// Size returns the approximate on-disk size of the DB (including the cold tier), in bytes
+func (s *Pebble) Size() (uint64, error) {
+	size := s.db.Metrics().DiskSpaceUsage()
+
+	if s.cold != nil {
+		size += s.cold.Metrics().DiskSpaceUsage()
 	}
+
+	return size, nil
 }
 
 func (s *Pebble) Close() error {
+	if s.view {
+		// The parent storage is in charge of the DB
+		return nil
+	}
+
+	if s.cold != nil {
+		return multierr.Append(s.db.Close(), s.cold.Close())
+	}
+
 	return s.db.Close()
 }
 
@@ -222,17 +707,34 @@ type PebbleBlockIter struct {
 	i *pebble.Iterator
 	s *pebble.Snapshot
 
-	init bool
+	// kindOffset is the offset of the data kind byte in the keys,
+	// for blocks interleaved with other data (-1 otherwise)
+	kindOffset int
+
+	init    bool
+	reverse bool
 }
 
 func (pi *PebbleBlockIter) Next() bool {
-	if !pi.init {
-		pi.init = true
+	for {
+		var valid bool
 
-		return pi.i.First()
-	}
+		if !pi.init {
+			pi.init = true
+			valid = first(pi.i, pi.reverse)
+		} else {
+			valid = pi.i.Valid() && next(pi.i, pi.reverse)
+		}
 
-	return pi.i.Valid() && pi.i.Next()
+		if !valid {
+			return false
+		}
+
+		// Skip the non-block data
+		if pi.kindOffset < 0 || pi.i.Key()[pi.kindOffset] == kindBlock {
+			return true
+		}
+	}
 }
 
 func (pi *PebbleBlockIter) Error() error {
@@ -253,26 +755,29 @@ type PebbleTxIter struct {
 	nextError error
 	i         *pebble.Iterator
 	s         *pebble.Snapshot
+	ns        []byte
+	schema    schemaVersion
 	fromIndex uint32
 	toIndex   uint32
 	init      bool
+	reverse   bool
 }
 
 func (pi *PebbleTxIter) Next() bool {
 	for {
 		if !pi.init {
-			if !pi.i.First() {
+			if !first(pi.i, pi.reverse) {
 				return false
 			}
 
 			pi.init = true
-		} else if !pi.i.Next() {
+		} else if !next(pi.i, pi.reverse) {
 			return false
 		}
 
 		var buf []byte
 
-		key, _, err := decodeUnsafeStringAscending(pi.i.Key(), buf)
+		key, _, err := decodeUnsafeStringAscending(pi.i.Key()[len(pi.ns):], buf)
 		if err != nil {
 			pi.nextError = err
 
@@ -286,6 +791,15 @@ func (pi *PebbleTxIter) Next() bool {
 			return false
 		}
 
+		if pi.schema == schemaV2 {
+			// Skip the non-tx data
+			if len(key) == 0 || key[0] != kindTx {
+				continue
+			}
+
+			key = key[1:]
+		}
+
 		_, txIdx, err := decodeUint32Ascending(key)
 		if err != nil {
 			pi.nextError = err
@@ -315,26 +829,156 @@ func (pi *PebbleTxIter) Close() error {
 	return multierr.Append(pi.i.Close(), pi.s.Close())
 }
 
+var _ Iterator[*types.TxResult] = &PebbleIndexTxIter{}
+
+// PebbleIndexTxIter iterates over a secondary index,
+// whose values are the primary transaction keys.
+// The index entries are ordered by height, so the transactions are read
+// by a single data iterator seeking forward (backward in reverse), instead of point lookups
+type PebbleIndexTxIter struct {
+	i    *pebble.Iterator
+	data *pebble.Iterator
+	s    *pebble.Snapshot
+
+	init    bool
+	reverse bool
+}
+
+func (pi *PebbleIndexTxIter) Next() bool {
+	if !pi.init {
+		pi.init = true
+
+		return first(pi.i, pi.reverse)
+	}
+
+	return pi.i.Valid() && next(pi.i, pi.reverse)
+}
+
+func (pi *PebbleIndexTxIter) Error() error {
+	return pi.i.Error()
+}
+
+func (pi *PebbleIndexTxIter) Value() (*types.TxResult, error) {
+	if pi.data == nil {
+		data, err := pi.s.NewIter(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		pi.data = data
+	}
+
+	key := pi.i.Value()
+
+	if !pi.data.SeekGE(key) || !bytes.Equal(pi.data.Key(), key) {
+		if err := pi.data.Error(); err != nil {
+			return nil, err
+		}
+
+		return nil, storageErrors.ErrNotFound
+	}
+
+	return decodeTx(pi.data.Value())
+}
+
+func (pi *PebbleIndexTxIter) Close() error {
+	var err error
+
+	if pi.data != nil {
+		err = pi.data.Close()
+	}
+
+	return multierr.Combine(err, pi.i.Close(), pi.s.Close())
+}
+
+var _ Iterator[*KeyValue] = &PebbleKVIter{}
+
+// PebbleKVIter iterates over raw key-value pairs,
+// stripping the common key prefix
+type PebbleKVIter struct {
+	i      *pebble.Iterator
+	s      *pebble.Snapshot
+	prefix []byte
+
+	init    bool
+	reverse bool
+}
+
+func (pi *PebbleKVIter) Next() bool {
+	if !pi.init {
+		pi.init = true
+
+		return first(pi.i, pi.reverse)
+	}
+
+	return pi.i.Valid() && next(pi.i, pi.reverse)
+}
+
+func (pi *PebbleKVIter) Error() error {
+	return pi.i.Error()
+}
+
+func (pi *PebbleKVIter) Value() (*KeyValue, error) {
+	return &KeyValue{
+		Key:   slices.Clone(pi.i.Key()[len(pi.prefix):]),
+		Value: slices.Clone(pi.i.Value()),
+	}, nil
+}
+
+func (pi *PebbleKVIter) Close() error {
+	return multierr.Append(pi.i.Close(), pi.s.Close())
+}
+
+// first positions the iterator at its first entry, the last one in reverse
+func first(i *pebble.Iterator, reverse bool) bool {
+	if reverse {
+		return i.Last()
+	}
+
+	return i.First()
+}
+
+// next moves the iterator to its next entry, the previous one in reverse
+func next(i *pebble.Iterator, reverse bool) bool {
+	if reverse {
+		return i.Prev()
+	}
+
+	return i.Next()
+}
+
 var _ Batch = &PebbleBatch{}
 
 type PebbleBatch struct {
-	b *pebble.Batch
+	b  *pebble.Batch
+	ns []byte
+
+	schema           schemaVersion
+	compressionLevel int
+	conflicts        ConflictPolicy
+
+	feed    *changefeed
+	decoder *decode.Decoder
 }
 
 func (b *PebbleBatch) SetLatestHeight(h uint64) error {
 	var val []byte
 	val = encodeUint64Ascending(val, h)
 
-	return b.b.Set([]byte(keyLatestHeight), val, pebble.NoSync)
+	return b.b.Set(keyLatest(b.ns), val, pebble.NoSync)
 }
 
 func (b *PebbleBatch) SetBlock(block *types.Block) error {
-	eb, err := encodeBlock(block)
+	eb, err := encodeBlock(block, b.compressionLevel)
 	if err != nil {
 		return err
 	}
 
-	key := keyBlock(uint64(block.Height))
+	key := b.schema.keyBlock(b.ns, uint64(block.Height))
+
+	if err := b.checkBlockConflict(key, block); err != nil {
+		return err
+	}
 
 	return b.b.Set(
 		key,
@@ -344,19 +988,24 @@ func (b *PebbleBatch) SetBlock(block *types.Block) error {
 }
 
 func (b *PebbleBatch) SetTx(tx *types.TxResult) error {
-	encodedTx, err := encodeTx(tx)
+	encodedTx, err := encodeTx(tx, b.compressionLevel)
 	if err != nil {
 		return err
 	}
 
-	key := keyTx(uint64(tx.Height), tx.Index)
+	key := b.schema.keyTx(b.ns, uint64(tx.Height), tx.Index)
 
-	// write secondary index to be able to query by tx hash
-	hashIndexKey := keyHashTx(base64.StdEncoding.EncodeToString(tx.Tx.Hash()))
-	if err := b.b.Set(hashIndexKey, key, pebble.NoSync); err != nil {
+	if err := b.checkTxConflict(key, tx); err != nil {
 		return err
 	}
 
+	// write the secondary indexes, pointing to the tx key
+	for _, indexKey := range txIndexKeys(b.ns, b.decoder, tx) {
+		if err := b.b.Set(indexKey, key, pebble.NoSync); err != nil {
+			return err
+		}
+	}
+
 	return b.b.Set(
 		key,
 		encodedTx,
@@ -364,11 +1013,75 @@ func (b *PebbleBatch) SetTx(tx *types.TxResult) error {
 	)
 }
 
+// SetPluginValue saves the plugin value under the given key
+func (b *PebbleBatch) SetPluginValue(plugin string, key, value []byte) error {
+	return b.b.Set(keyPlugin(b.ns, plugin, key), value, pebble.NoSync)
+}
+
+// GetPluginValue fetches the plugin value under the given key,
+// taking into account the data not yet committed in the batch
+func (b *PebbleBatch) GetPluginValue(plugin string, key []byte) ([]byte, error) {
+	return get(b.b, keyPlugin(b.ns, plugin, key))
+}
+
+// DeletePluginValue removes the plugin value under the given key
+func (b *PebbleBatch) DeletePluginValue(plugin string, key []byte) error {
+	return b.b.Delete(keyPlugin(b.ns, plugin, key), pebble.NoSync)
+}
+
 func (b *PebbleBatch) Commit() error {
-	return b.b.Commit(pebble.Sync)
+	return b.feed.commit(b.ns, b.b)
 }
 
 // Rollback closes the pebble batch without persisting any data. error output is always nil.
 func (b *PebbleBatch) Rollback() error {
 	return b.b.Close()
 }
+
+// txIndexKeys returns the secondary index keys of the tx: the hash index key,
+// followed by the key for each participating address.
+// Transactions that can't be decoded (by the chain decoder) are not indexed by address
+func txIndexKeys(ns []byte, decoder *decode.Decoder, tx *types.TxResult) [][]byte {
+	keys := [][]byte{
+		keyHashTx(ns, base64.StdEncoding.EncodeToString(tx.Tx.Hash())),
+	}
+
+	if stdTx, err := decoder.TxResult(tx); err == nil {
+		for _, address := range decode.Addresses(stdTx) {
+			keys = append(keys, keyAddressTx(ns, address.String(), uint64(tx.Height), tx.Index))
+		}
+	}
+
+	return keys
+}
+
+// get fetches a copy of the value under the given key
+func get(r pebble.Reader, key []byte) ([]byte, error) {
+	value, c, err := r.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, storageErrors.ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer c.Close()
+
+	return slices.Clone(value), nil
+}
+
+// prefixUpperBound returns the smallest key greater than all the keys with the given prefix
+func prefixUpperBound(prefix []byte) []byte {
+	upper := slices.Clone(prefix)
+
+	for i := len(upper) - 1; i >= 0; i-- {
+		upper[i]++
+		if upper[i] != 0 {
+			return upper[:i+1]
+		}
+	}
+
+	// The prefix is all 0xff, no upper bound
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,6 +25,204 @@ func TestStorage_New(t *testing.T) {
 	assert.NoError(t, s.Close())
 }
 
+func TestStorage_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	s, err := NewPebble(path, WithNamespace("chain"))
+	require.NoError(t, err)
+
+	blocks, txs := generateChain(t, 5, 3)
+	saveChain(t, s, blocks, txs)
+
+	require.NoError(t, s.Close())
+
+	s, err = NewPebble(path, WithNamespace("chain"), WithReadOnly())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Make sure the existing data is readable
+	assertChain(t, s, blocks, txs)
+
+	// Make sure empty namespaces are readable
+	empty, err := s.WithNamespace("empty")
+	require.NoError(t, err)
+
+	_, err = empty.GetLatestHeight()
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	// Make sure the writes fail
+	wb := s.WriteBatch()
+	require.NoError(t, wb.SetLatestHeight(10))
+
+	assert.Error(t, wb.Commit())
+
+	// Make sure missing DBs are not created
+	_, err = NewPebble(t.TempDir()+"/missing", WithReadOnly())
+	assert.Error(t, err)
+}
+
+func TestStorage_PluginValues(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var (
+		plugin = "counter"
+		other  = "other"
+	)
+
+	wb := s.WriteBatch()
+
+	for i := byte(0); i < 5; i++ {
+		require.NoError(t, wb.SetPluginValue(plugin, []byte{i}, []byte{i + 1}))
+	}
+
+	require.NoError(t, wb.SetPluginValue(other, []byte{0}, []byte("other")))
+
+	// Make sure uncommitted values are visible to the batch
+	value, err := wb.GetPluginValue(plugin, []byte{2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3}, value)
+
+	// Make sure deleted values are gone
+	require.NoError(t, wb.DeletePluginValue(plugin, []byte{4}))
+
+	_, err = wb.GetPluginValue(plugin, []byte{4})
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	// Make sure uncommitted values are not visible to the storage
+	_, err = s.GetPluginValue(plugin, []byte{0})
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	require.NoError(t, wb.Commit())
+
+	value, err = s.GetPluginValue(plugin, []byte{0})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1}, value)
+
+	value, err = s.GetPluginValue(other, []byte{0})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("other"), value)
+
+	// Make sure the iterator is limited to the plugin namespace
+	it, err := s.PluginIterator(plugin, nil, nil)
+	require.NoError(t, err)
+
+	keys := make([][]byte, 0, 4)
+
+	for it.Next() {
+		kv, err := it.Value()
+		require.NoError(t, err)
+
+		assert.Equal(t, []byte{kv.Key[0] + 1}, kv.Value)
+
+		keys = append(keys, kv.Key)
+	}
+
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+
+	assert.Equal(t, [][]byte{{0}, {1}, {2}, {3}}, keys)
+
+	// Make sure the iterator range is respected
+	it, err = s.PluginIterator(plugin, []byte{1}, []byte{3})
+	require.NoError(t, err)
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+
+	assert.Equal(t, 2, count)
+}
+
+func TestStorage_Namespace(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir(), WithNamespace("test4"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	other, err := s.WithNamespace("portal-loop")
+	require.NoError(t, err)
+
+	assert.Equal(t, "test4", s.Namespace())
+	assert.Equal(t, "portal-loop", other.Namespace())
+
+	blocks := generateRandomBlocks(t, 10)
+	txs := generateRandomTxs(t, 10)
+
+	wb := s.WriteBatch()
+
+	for i, block := range blocks {
+		require.NoError(t, wb.SetBlock(block))
+		require.NoError(t, wb.SetTx(txs[i]))
+	}
+
+	require.NoError(t, wb.SetLatestHeight(10))
+	require.NoError(t, wb.Commit())
+
+	// Make sure the data is present in the original namespace
+	latest, err := s.GetLatestHeight()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), latest)
+
+	for i, block := range blocks {
+		savedBlock, err := s.GetBlock(uint64(block.Height))
+		require.NoError(t, err)
+		assert.Equal(t, block, savedBlock)
+
+		savedTx, err := s.GetTx(uint64(txs[i].Height), txs[i].Index)
+		require.NoError(t, err)
+		assert.Equal(t, txs[i], savedTx)
+	}
+
+	it, err := s.TxIterator(0, 0, 0, 0)
+	require.NoError(t, err)
+
+	txCount := 0
+	for it.Next() {
+		txCount++
+	}
+
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+
+	assert.Equal(t, len(txs), txCount)
+
+	// Make sure the data is not present in the other namespace
+	_, err = other.GetLatestHeight()
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	_, err = other.GetBlock(uint64(blocks[0].Height))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	_, err = other.GetTx(uint64(txs[0].Height), txs[0].Index)
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	// Closing the view should not close the DB
+	require.NoError(t, other.Close())
+
+	_, err = s.GetLatestHeight()
+	assert.NoError(t, err)
+}
+
 func TestStorage_LatestHeight(t *testing.T) {
 	t.Parallel()
 
@@ -107,6 +307,80 @@ func TestStorage_Tx(t *testing.T) {
 	}
 }
 
+func TestStorage_TxByAddress(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var (
+		sender    = crypto.Address{1}
+		recipient = crypto.Address{2}
+		unrelated = crypto.Address{3}
+
+		txs = make([]*types.TxResult, 0, 10)
+	)
+
+	for i := 0; i < 10; i++ {
+		tx := &std.Tx{
+			Msgs: []std.Msg{
+				bank.MsgSend{
+					FromAddress: sender,
+					ToAddress:   recipient,
+				},
+			},
+			Memo: fmt.Sprintf("tx %d", i),
+		}
+
+		txs = append(txs, &types.TxResult{
+			Height: int64(i),
+			Index:  0,
+			Tx:     amino.MustMarshal(tx),
+		})
+	}
+
+	wb := s.WriteBatch()
+
+	for _, tx := range txs {
+		require.NoError(t, wb.SetTx(tx))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	collect := func(address crypto.Address, from, to uint64) []*types.TxResult {
+		t.Helper()
+
+		it, err := s.TxByAddressIterator(address.String(), from, to)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, it.Close())
+		}()
+
+		results := make([]*types.TxResult, 0)
+
+		for it.Next() {
+			tx, err := it.Value()
+			require.NoError(t, err)
+
+			results = append(results, tx)
+		}
+
+		require.NoError(t, it.Error())
+
+		return results
+	}
+
+	assert.Equal(t, txs, collect(sender, 0, 0))
+	assert.Equal(t, txs, collect(recipient, 0, 0))
+	assert.Equal(t, txs[2:5], collect(sender, 2, 5))
+	assert.Empty(t, collect(unrelated, 0, 0))
+}
+
 func TestStorageIters(t *testing.T) {
 	t.Parallel()
 
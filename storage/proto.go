@@ -0,0 +1,495 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The block and tx result values are encoded following the
+// protobuf schemas in values.proto. The fields are numbered as in the schemas
+
+var errInvalidAddress = errors.New("invalid address length")
+
+// protoField is a decoded protobuf field
+type protoField struct {
+	bytes  []byte
+	varint uint64
+	num    protowire.Number
+}
+
+// consumeProtoFields decodes the protobuf message fields,
+// invoking the callback for each one
+func consumeProtoFields(b []byte, fieldFn func(field protoField) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		b = b[n:]
+
+		field := protoField{num: num}
+
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			// Unknown field type, skipped
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		b = b[n:]
+
+		if err := fieldFn(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendVarintField appends the varint field, if set
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+
+	return protowire.AppendVarint(b, v)
+}
+
+// appendBytesField appends the bytes field, if set
+func appendBytesField(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+
+	return appendMessageField(b, num, v)
+}
+
+// appendMessageField appends the embedded message (or bytes) field, even if empty
+func appendMessageField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+
+	return protowire.AppendBytes(b, v)
+}
+
+// cloneBytes copies the decoded bytes, which reference the storage buffer
+func cloneBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return append([]byte(nil), b...)
+}
+
+func marshalTimestamp(t time.Time) []byte {
+	var b []byte
+
+	b = appendVarintField(b, 1, uint64(t.Unix()))
+	b = appendVarintField(b, 2, uint64(t.Nanosecond()))
+
+	return b
+}
+
+func unmarshalTimestamp(b []byte) (time.Time, error) {
+	var seconds, nanos int64
+
+	err := consumeProtoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			seconds = int64(field.varint)
+		case 2:
+			nanos = int64(field.varint)
+		}
+
+		return nil
+	})
+
+	return time.Unix(seconds, nanos).UTC(), err
+}
+
+func marshalBlockID(id types.BlockID) []byte {
+	var parts []byte
+
+	parts = appendVarintField(parts, 1, uint64(id.PartsHeader.Total))
+	parts = appendBytesField(parts, 2, id.PartsHeader.Hash)
+
+	var b []byte
+
+	b = appendBytesField(b, 1, id.Hash)
+	b = appendBytesField(b, 2, parts)
+
+	return b
+}
+
+func unmarshalBlockID(b []byte) (types.BlockID, error) {
+	var id types.BlockID
+
+	err := consumeProtoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			id.Hash = cloneBytes(field.bytes)
+		case 2:
+			return consumeProtoFields(field.bytes, func(field protoField) error {
+				switch field.num {
+				case 1:
+					id.PartsHeader.Total = int(field.varint)
+				case 2:
+					id.PartsHeader.Hash = cloneBytes(field.bytes)
+				}
+
+				return nil
+			})
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+func unmarshalAddress(b []byte) (crypto.Address, error) {
+	var address crypto.Address
+
+	if len(b) == 0 {
+		return address, nil
+	}
+
+	if len(b) != len(address) {
+		return address, errInvalidAddress
+	}
+
+	copy(address[:], b)
+
+	return address, nil
+}
+
+func marshalHeader(h *types.Header) []byte {
+	var b []byte
+
+	b = appendBytesField(b, 1, []byte(h.Version))
+	b = appendBytesField(b, 2, []byte(h.ChainID))
+	b = appendVarintField(b, 3, uint64(h.Height))
+	b = appendMessageField(b, 4, marshalTimestamp(h.Time))
+	b = appendVarintField(b, 5, uint64(h.NumTxs))
+	b = appendVarintField(b, 6, uint64(h.TotalTxs))
+	b = appendBytesField(b, 7, []byte(h.AppVersion))
+	b = appendBytesField(b, 8, marshalBlockID(h.LastBlockID))
+	b = appendBytesField(b, 9, h.LastCommitHash)
+	b = appendBytesField(b, 10, h.DataHash)
+	b = appendBytesField(b, 11, h.ValidatorsHash)
+	b = appendBytesField(b, 12, h.NextValidatorsHash)
+	b = appendBytesField(b, 13, h.ConsensusHash)
+	b = appendBytesField(b, 14, h.AppHash)
+	b = appendBytesField(b, 15, h.LastResultsHash)
+
+	if !h.ProposerAddress.IsZero() {
+		b = appendBytesField(b, 16, h.ProposerAddress[:])
+	}
+
+	return b
+}
+
+func unmarshalHeader(b []byte, h *types.Header) error {
+	return consumeProtoFields(b, func(field protoField) error {
+		var err error
+
+		switch field.num {
+		case 1:
+			h.Version = string(field.bytes)
+		case 2:
+			h.ChainID = string(field.bytes)
+		case 3:
+			h.Height = int64(field.varint)
+		case 4:
+			h.Time, err = unmarshalTimestamp(field.bytes)
+		case 5:
+			h.NumTxs = int64(field.varint)
+		case 6:
+			h.TotalTxs = int64(field.varint)
+		case 7:
+			h.AppVersion = string(field.bytes)
+		case 8:
+			h.LastBlockID, err = unmarshalBlockID(field.bytes)
+		case 9:
+			h.LastCommitHash = cloneBytes(field.bytes)
+		case 10:
+			h.DataHash = cloneBytes(field.bytes)
+		case 11:
+			h.ValidatorsHash = cloneBytes(field.bytes)
+		case 12:
+			h.NextValidatorsHash = cloneBytes(field.bytes)
+		case 13:
+			h.ConsensusHash = cloneBytes(field.bytes)
+		case 14:
+			h.AppHash = cloneBytes(field.bytes)
+		case 15:
+			h.LastResultsHash = cloneBytes(field.bytes)
+		case 16:
+			h.ProposerAddress, err = unmarshalAddress(field.bytes)
+		}
+
+		return err
+	})
+}
+
+func marshalCommitSig(sig *types.CommitSig) []byte {
+	var b []byte
+
+	b = appendVarintField(b, 1, uint64(sig.Type))
+	b = appendVarintField(b, 2, uint64(sig.Height))
+	b = appendVarintField(b, 3, uint64(sig.Round))
+	b = appendBytesField(b, 4, marshalBlockID(sig.BlockID))
+	b = appendMessageField(b, 5, marshalTimestamp(sig.Timestamp))
+
+	if !sig.ValidatorAddress.IsZero() {
+		b = appendBytesField(b, 6, sig.ValidatorAddress[:])
+	}
+
+	b = appendVarintField(b, 7, uint64(sig.ValidatorIndex))
+	b = appendBytesField(b, 8, sig.Signature)
+
+	return b
+}
+
+func unmarshalCommitSig(b []byte) (*types.CommitSig, error) {
+	sig := &types.CommitSig{}
+
+	err := consumeProtoFields(b, func(field protoField) error {
+		var err error
+
+		switch field.num {
+		case 1:
+			sig.Type = types.SignedMsgType(field.varint)
+		case 2:
+			sig.Height = int64(field.varint)
+		case 3:
+			sig.Round = int(field.varint)
+		case 4:
+			sig.BlockID, err = unmarshalBlockID(field.bytes)
+		case 5:
+			sig.Timestamp, err = unmarshalTimestamp(field.bytes)
+		case 6:
+			sig.ValidatorAddress, err = unmarshalAddress(field.bytes)
+		case 7:
+			sig.ValidatorIndex = int(field.varint)
+		case 8:
+			sig.Signature = cloneBytes(field.bytes)
+		}
+
+		return err
+	})
+
+	return sig, err
+}
+
+func marshalCommit(commit *types.Commit) []byte {
+	var b []byte
+
+	b = appendBytesField(b, 1, marshalBlockID(commit.BlockID))
+
+	for _, sig := range commit.Precommits {
+		var precommit []byte
+
+		// Absent validators have no signature
+		if sig != nil {
+			precommit = appendMessageField(precommit, 1, marshalCommitSig(sig))
+		}
+
+		b = appendMessageField(b, 2, precommit)
+	}
+
+	return b
+}
+
+func unmarshalCommit(b []byte) (*types.Commit, error) {
+	commit := &types.Commit{}
+
+	err := consumeProtoFields(b, func(field protoField) error {
+		var err error
+
+		switch field.num {
+		case 1:
+			commit.BlockID, err = unmarshalBlockID(field.bytes)
+		case 2:
+			var sig *types.CommitSig
+
+			err = consumeProtoFields(field.bytes, func(field protoField) error {
+				var sigErr error
+
+				if field.num == 1 {
+					sig, sigErr = unmarshalCommitSig(field.bytes)
+				}
+
+				return sigErr
+			})
+
+			commit.Precommits = append(commit.Precommits, sig)
+		}
+
+		return err
+	})
+
+	return commit, err
+}
+
+// marshalBlockProto encodes the block following the Block protobuf schema
+func marshalBlockProto(block *types.Block) []byte {
+	var b []byte
+
+	b = appendMessageField(b, 1, marshalHeader(&block.Header))
+
+	for _, tx := range block.Txs {
+		b = appendMessageField(b, 2, tx)
+	}
+
+	if block.LastCommit != nil {
+		b = appendMessageField(b, 3, marshalCommit(block.LastCommit))
+	}
+
+	return b
+}
+
+// unmarshalBlockProto decodes the block following the Block protobuf schema
+func unmarshalBlockProto(b []byte) (*types.Block, error) {
+	block := &types.Block{}
+
+	err := consumeProtoFields(b, func(field protoField) error {
+		var err error
+
+		switch field.num {
+		case 1:
+			err = unmarshalHeader(field.bytes, &block.Header)
+		case 2:
+			block.Txs = append(block.Txs, cloneBytes(field.bytes))
+		case 3:
+			block.LastCommit, err = unmarshalCommit(field.bytes)
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+func marshalResponse(response *abci.ResponseDeliverTx) ([]byte, error) {
+	var b []byte
+
+	if response.Error != nil {
+		encodedErr, err := amino.MarshalAny(response.Error)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode response error, %w", err)
+		}
+
+		b = appendMessageField(b, 1, encodedErr)
+	}
+
+	b = appendBytesField(b, 2, response.Data)
+
+	for _, event := range response.Events {
+		encodedEvent, err := amino.MarshalAny(event)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode response event, %w", err)
+		}
+
+		b = appendMessageField(b, 3, encodedEvent)
+	}
+
+	b = appendBytesField(b, 4, []byte(response.Log))
+	b = appendBytesField(b, 5, []byte(response.Info))
+	b = appendVarintField(b, 6, uint64(response.GasWanted))
+	b = appendVarintField(b, 7, uint64(response.GasUsed))
+
+	return b, nil
+}
+
+func unmarshalResponse(b []byte, response *abci.ResponseDeliverTx) error {
+	return consumeProtoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			if err := amino.UnmarshalAny(field.bytes, &response.Error); err != nil {
+				return fmt.Errorf("unable to decode response error, %w", err)
+			}
+		case 2:
+			response.Data = cloneBytes(field.bytes)
+		case 3:
+			var event abci.Event
+
+			if err := amino.UnmarshalAny(field.bytes, &event); err != nil {
+				return fmt.Errorf("unable to decode response event, %w", err)
+			}
+
+			response.Events = append(response.Events, event)
+		case 4:
+			response.Log = string(field.bytes)
+		case 5:
+			response.Info = string(field.bytes)
+		case 6:
+			response.GasWanted = int64(field.varint)
+		case 7:
+			response.GasUsed = int64(field.varint)
+		}
+
+		return nil
+	})
+}
+
+// marshalTxProto encodes the tx result following the TxResult protobuf schema
+func marshalTxProto(tx *types.TxResult) ([]byte, error) {
+	response, err := marshalResponse(&tx.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+
+	b = appendVarintField(b, 1, uint64(tx.Height))
+	b = appendVarintField(b, 2, uint64(tx.Index))
+	b = appendBytesField(b, 3, tx.Tx)
+	b = appendBytesField(b, 4, response)
+
+	return b, nil
+}
+
+// unmarshalTxProto decodes the tx result following the TxResult protobuf schema
+func unmarshalTxProto(b []byte) (*types.TxResult, error) {
+	tx := &types.TxResult{}
+
+	err := consumeProtoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			tx.Height = int64(field.varint)
+		case 2:
+			tx.Index = uint32(field.varint)
+		case 3:
+			tx.Tx = cloneBytes(field.bytes)
+		case 4:
+			return unmarshalResponse(field.bytes, &tx.Response)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
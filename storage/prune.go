@@ -0,0 +1,653 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/DataDog/zstd"
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/multierr"
+
+	"github.com/gnolang/tx-indexer/objstore"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	// PruneSegmentHeights is the number of heights of a pruned (archived) segment.
+	// The heights are pruned in whole segments, aligned to the segment size
+	PruneSegmentHeights = 1000
+
+	// keyPruneBoundary is the lookup key for the first height that isn't pruned
+	keyPruneBoundary = "/meta/pb"
+
+	// prefixKeyRestored is the prefix of the markers of the archived segments
+	// restored on demand, by the segment first height
+	prefixKeyRestored = "/meta/rs/"
+
+	// archiveCompressionLevel is the zstd level of the archived segments
+	archiveCompressionLevel = 19
+
+	// maxRestoredSegments is the maximum number of archived segments restored
+	// for a single read. Reads spanning more pruned segments are not restored
+	maxRestoredSegments = 10
+
+	// DefaultRestoredSegmentTTL is the default time the restored segments
+	// are kept for, before being pruned again
+	DefaultRestoredSegmentTTL = 24 * time.Hour
+
+	// DefaultRestoreLimit is the default maximum number of
+	// archived segments restored on demand per minute
+	DefaultRestoreLimit = 30
+
+	// DefaultRestoreTimeout is the default timeout of
+	// fetching a single archived segment, restored on demand
+	DefaultRestoreTimeout = 30 * time.Second
+
+	// restoreWindow is the window of the restore limit
+	restoreWindow = time.Minute
+)
+
+var errPruneSchema = errors.New("pruning requires the current key schema, migrate the storage first")
+
+// ErrRestoreLimit is returned for the reads of the pruned heights
+// once the restore limit of the prune archive is reached
+var ErrRestoreLimit = errors.New("pruned heights restore limit reached, retry later")
+
+// pruneArchive is the object store the pruned segments are archived to,
+// shared by the namespace views
+type pruneArchive struct {
+	store  objstore.Store
+	prefix string
+
+	// mux serializes the segment restore commits (and the restored segment prunes).
+	// The segments are fetched without holding it, so slow fetches don't block the other reads
+	mux sync.Mutex
+
+	// restoring is the done channels of the segments being restored, by archive key,
+	// so the concurrent reads of a segment wait for its restore instead of fetching it again
+	restoring map[string]chan struct{}
+
+	// restores is the number of segments restored since the start of the restore limit window
+	windowStart time.Time
+	restores    int
+
+	// now is the clock of the restore times
+	now func() time.Time
+}
+
+// allowRestore returns a flag indicating if a segment can be restored under the
+// given per-minute limit, counting the restore if so. Requires the mux to be held
+func (a *pruneArchive) allowRestore(limit int) bool {
+	now := a.now()
+
+	if now.Sub(a.windowStart) >= restoreWindow {
+		a.windowStart = now
+		a.restores = 0
+	}
+
+	if a.restores >= limit {
+		return false
+	}
+
+	a.restores++
+
+	return true
+}
+
+func keyPrune(ns []byte) []byte {
+	key := slices.Clone(ns)
+	key = append(key, keyPruneBoundary...)
+
+	return key
+}
+
+func keyRestored(ns []byte, from uint64) []byte {
+	key := slices.Clone(ns)
+	key = encodeStringAscending(key, prefixKeyRestored)
+	key = encodeUint64Ascending(key, from)
+
+	return key
+}
+
+// pruneBoundary returns the first height that isn't pruned.
+// No height is pruned if the boundary isn't set
+func pruneBoundary(r pebble.Reader, ns []byte) (uint64, error) {
+	value, err := get(r, keyPrune(ns))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch prune boundary, %w", err)
+	}
+
+	_, boundary, err := decodeUint64Ascending(value)
+
+	return boundary, err
+}
+
+// PruneBoundary returns the first height that isn't pruned
+func (s *Pebble) PruneBoundary() (uint64, error) {
+	return pruneBoundary(s.db, s.ns)
+}
+
+// Prune removes the blocks and txs (along with their index entries) older than the given
+// number of most recent heights from both storage tiers, in whole segments. If the prune
+// archive is set, each segment is uploaded to it before being removed, and is restored from it
+// on demand (the tx hash index entries are kept, for restoring the txs by hash). The restored
+// segments are pruned again once expired. The plugin data is kept. Returns the number of pruned heights
+func (s *Pebble) Prune(ctx context.Context, keepHeights uint64) (uint64, error) {
+	if s.schema != schemaV2 {
+		return 0, errPruneSchema
+	}
+
+	latest, err := s.GetLatestHeight()
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	if latest < keepHeights {
+		return 0, nil
+	}
+
+	// Only the whole segments are pruned
+	target := (latest - keepHeights + 1) / PruneSegmentHeights * PruneSegmentHeights
+
+	start, err := pruneBoundary(s.db, s.ns)
+	if err != nil {
+		return 0, err
+	}
+
+	for from := start; from < target; from += PruneSegmentHeights {
+		if err := ctx.Err(); err != nil {
+			return from - start, err
+		}
+
+		to := from + PruneSegmentHeights
+
+		if s.archive != nil {
+			if err := s.archiveSegment(ctx, from, to); err != nil {
+				return from - start, fmt.Errorf("unable to archive heights %d-%d, %w", from, to-1, err)
+			}
+		}
+
+		// The boundary is moved along with the removal
+		setBoundary := func(b *pebble.Batch) error {
+			return b.Set(keyPrune(s.ns), encodeUint64Ascending(nil, to), nil)
+		}
+
+		if err := s.pruneHeights(from, to, setBoundary); err != nil {
+			return from - start, fmt.Errorf("unable to prune heights %d-%d, %w", from, to-1, err)
+		}
+	}
+
+	pruned := max(target, start) - start
+
+	if s.archive == nil {
+		return pruned, nil
+	}
+
+	repruned, err := s.pruneRestored(ctx)
+
+	return pruned + repruned, err
+}
+
+// pruneRestored prunes again the restored segments older than the restored segment TTL.
+// Returns the number of pruned heights
+func (s *Pebble) pruneRestored(ctx context.Context) (uint64, error) {
+	expired, err := s.expiredSegments()
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch restored segments, %w", err)
+	}
+
+	s.archive.mux.Lock()
+	defer s.archive.mux.Unlock()
+
+	pruned := uint64(0)
+
+	for _, from := range expired {
+		if err := ctx.Err(); err != nil {
+			return pruned, err
+		}
+
+		to := from + PruneSegmentHeights
+
+		// The marker is removed along with the heights, so the segment is restored again once read
+		deleteMarker := func(b *pebble.Batch) error {
+			return b.Delete(keyRestored(s.ns, from), nil)
+		}
+
+		if err := s.pruneHeights(from, to, deleteMarker); err != nil {
+			return pruned, fmt.Errorf("unable to prune restored heights %d-%d, %w", from, to-1, err)
+		}
+
+		pruned += PruneSegmentHeights
+	}
+
+	return pruned, nil
+}
+
+// expiredSegments returns the first heights of the restored segments older than the restored segment TTL.
+// The markers without the restore time (saved by the earlier versions) are expired
+func (s *Pebble) expiredSegments() ([]uint64, error) {
+	var (
+		prefix = encodeStringAscending(slices.Clone(s.ns), prefixKeyRestored)
+		now    = s.archive.now()
+	)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixUpperBound(prefix),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make([]uint64, 0)
+
+	for it.First(); it.Valid(); it.Next() {
+		_, from, err := decodeUint64Ascending(it.Key()[len(prefix):])
+		if err != nil {
+			return nil, multierr.Append(err, it.Close())
+		}
+
+		if len(it.Value()) != 0 {
+			_, restoredAt, err := decodeUint64Ascending(it.Value())
+			if err != nil {
+				return nil, multierr.Append(err, it.Close())
+			}
+
+			if now.Sub(time.Unix(int64(restoredAt), 0)) < s.restoredTTL {
+				continue
+			}
+		}
+
+		expired = append(expired, from)
+	}
+
+	return expired, multierr.Append(it.Error(), it.Close())
+}
+
+// segmentKey returns the archive object key of the segment starting at the height
+func (s *Pebble) segmentKey(from uint64) string {
+	name := fmt.Sprintf("%012d-%012d.seg.zst", from, from+PruneSegmentHeights-1)
+
+	return s.archive.prefix + path.Join(s.namespace, name)
+}
+
+// archiveSegment uploads the height range [from, to) to the prune archive, as a compressed
+// height range backup (without the plugin data), read from both storage tiers
+func (s *Pebble) archiveSegment(ctx context.Context, from, to uint64) error {
+	var (
+		buf bytes.Buffer
+		zw  = zstd.NewWriterLevel(&buf, archiveCompressionLevel)
+		bw  = &backupWriter{w: bufio.NewWriter(zw), ns: s.ns}
+	)
+
+	snap := s.db.NewSnapshot()
+	defer snap.Close()
+
+	info := &BackupInfo{
+		From:   from,
+		To:     to - 1,
+		Schema: uint64(s.schema),
+	}
+
+	if err := bw.writeHeader(info); err != nil {
+		return multierr.Append(err, zw.Close())
+	}
+
+	if err := s.backupTiers(snap, bw, from, to-1); err != nil {
+		return multierr.Append(err, zw.Close())
+	}
+
+	if err := multierr.Append(bw.close(), zw.Close()); err != nil {
+		return fmt.Errorf("unable to write segment, %w", err)
+	}
+
+	return s.archive.store.Put(ctx, s.segmentKey(from), buf.Bytes())
+}
+
+// pruneHeights removes the height range [from, to) from both storage tiers, along with
+// the tx index entries. The removals of both tiers (along with the finish writes, such as the boundary update)
+// are published to the changefeed, as the restored segments are, so the standby instances prune the heights as well
+func (s *Pebble) pruneHeights(from, to uint64, finish func(b *pebble.Batch) error) error {
+	if s.cold != nil {
+		b := s.cold.NewBatch()
+
+		if err := s.pruneTier(s.cold, b, from, to); err != nil {
+			return multierr.Append(err, b.Close())
+		}
+
+		// The standby tiers are their own, so the cold tier removal is applied to the standby DB
+		if err := s.feed.commit(s.ns, b); err != nil {
+			return multierr.Append(err, b.Close())
+		}
+
+		if err := b.Close(); err != nil {
+			return err
+		}
+	}
+
+	b := s.db.NewBatch()
+
+	if err := s.pruneTier(s.db, b, from, to); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	if err := finish(b); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	if err := s.feed.commit(s.ns, b); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	return b.Close()
+}
+
+// pruneTier adds the removal of the height range [from, to) of the tier DB to the batch.
+// The tx hash index entries are kept if the prune archive is set, for restoring the txs by hash
+func (s *Pebble) pruneTier(db *pebble.DB, b *pebble.Batch, from, to uint64) error {
+	var (
+		lower = keyHeight(s.ns, from)
+		upper = keyHeight(s.ns, to)
+
+		kindOffset = heightKindOffset(s.ns)
+	)
+
+	it, err := db.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: upper,
+	})
+	if err != nil {
+		return err
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		if it.Key()[kindOffset] != kindTx {
+			continue
+		}
+
+		tx, err := decodeTx(it.Value())
+		if err != nil {
+			return multierr.Append(fmt.Errorf("unable to decode tx, %w", err), it.Close())
+		}
+
+		indexKeys := txIndexKeys(s.ns, s.decoder, tx)
+		if s.archive != nil {
+			indexKeys = indexKeys[1:]
+		}
+
+		for _, indexKey := range indexKeys {
+			if err := b.Delete(indexKey, nil); err != nil {
+				return multierr.Append(err, it.Close())
+			}
+		}
+	}
+
+	if err := multierr.Append(it.Error(), it.Close()); err != nil {
+		return err
+	}
+
+	return b.DeleteRange(lower, upper, nil)
+}
+
+// restorePruned restores the archived segments of the pruned heights in the range [from, to),
+// if they aren't restored already. Reads spanning more than the maximum restored segments
+// are not restored, and ErrRestoreLimit is returned once the restore limit is reached.
+// Returns a flag indicating if any segment was restored
+func (s *Pebble) restorePruned(from, to uint64) (bool, error) {
+	if s.archive == nil || s.readOnly || s.restoreLimit <= 0 || from >= to {
+		return false, nil
+	}
+
+	boundary, err := pruneBoundary(s.db, s.ns)
+	if err != nil || from >= boundary {
+		return false, err
+	}
+
+	var (
+		first = from / PruneSegmentHeights
+		last  = (min(to, boundary) - 1) / PruneSegmentHeights
+	)
+
+	if last-first+1 > maxRestoredSegments {
+		return false, nil
+	}
+
+	restored := false
+
+	for segment := first; segment <= last; segment++ {
+		ok, err := s.restoreSegment(segment * PruneSegmentHeights)
+		if err != nil {
+			return restored, fmt.Errorf("unable to restore pruned heights %d-%d, %w",
+				segment*PruneSegmentHeights, (segment+1)*PruneSegmentHeights-1, err)
+		}
+
+		restored = restored || ok
+	}
+
+	return restored, nil
+}
+
+// restoreSegment restores the archived segment starting at the height, if it isn't restored already,
+// within the per-minute restore limit. The segment is fetched within the restore timeout, without holding
+// the archive lock, and the concurrent reads of the segment wait for its restore. Segments pruned without
+// being archived are skipped. Returns a flag indicating if the segment was restored (by any of the reads)
+func (s *Pebble) restoreSegment(from uint64) (bool, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), s.restoreTimeout)
+	defer cancelFn()
+
+	var (
+		key    = s.segmentKey(from)
+		waited = false
+	)
+
+	s.archive.mux.Lock()
+
+	for {
+		// The segments restored while waiting are reported as restored, for the read to be repeated
+		if _, err := get(s.db, keyRestored(s.ns, from)); !errors.Is(err, storageErrors.ErrNotFound) {
+			s.archive.mux.Unlock()
+
+			return waited && err == nil, err
+		}
+
+		done, restoring := s.archive.restoring[key]
+		if !restoring {
+			break
+		}
+
+		// Wait for the concurrent restore of the segment, and check its marker again
+		s.archive.mux.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+
+		waited = true
+
+		s.archive.mux.Lock()
+	}
+
+	if !s.archive.allowRestore(s.restoreLimit) {
+		s.archive.mux.Unlock()
+
+		return false, ErrRestoreLimit
+	}
+
+	done := make(chan struct{})
+	s.archive.restoring[key] = done
+
+	s.archive.mux.Unlock()
+
+	defer func() {
+		s.archive.mux.Lock()
+		delete(s.archive.restoring, key)
+		s.archive.mux.Unlock()
+
+		close(done)
+	}()
+
+	hot, cold, err := s.fetchSegment(ctx, from)
+	if err != nil || hot == nil {
+		return false, err
+	}
+
+	closeBatches := func() error {
+		if cold == nil {
+			return hot.Close()
+		}
+
+		return multierr.Append(hot.Close(), cold.Close())
+	}
+
+	// The restore is committed under the lock, so it's serialized with the restored segment prunes
+	s.archive.mux.Lock()
+	defer s.archive.mux.Unlock()
+
+	// The marker (holding the restore time, for pruning the segment again once expired) is saved along with
+	// the hot tier writes, once the cold tier ones are committed, so failed restores are repeated
+	restoredAt := encodeUint64Ascending(nil, uint64(s.archive.now().Unix()))
+
+	if err := hot.Set(keyRestored(s.ns, from), restoredAt, nil); err != nil {
+		return false, multierr.Append(err, closeBatches())
+	}
+
+	// The cold tier is written first, as it's read below the hot tier boundary.
+	// The restored heights are published to the changefeed, so the standby instances restore them as well.
+	// The standby tiers are their own, so the cold tier writes are applied to the standby DB
+	if cold != nil {
+		if err := s.feed.commit(s.ns, cold); err != nil {
+			return false, multierr.Append(err, closeBatches())
+		}
+	}
+
+	if err := s.feed.commit(s.ns, hot); err != nil {
+		return false, multierr.Append(err, closeBatches())
+	}
+
+	return true, closeBatches()
+}
+
+// fetchSegment fetches the archived segment starting at the height, returning the (uncommitted) writes
+// of its heights and index entries to the hot and cold (if any) tiers. Each height is restored to the tier
+// it's read from. Returns nil batches if the segment was pruned without being archived
+func (s *Pebble) fetchSegment(ctx context.Context, from uint64) (*pebble.Batch, *pebble.Batch, error) {
+	rc, err := s.archive.store.Get(ctx, s.segmentKey(from))
+	if errors.Is(err, objstore.ErrNotFound) {
+		return nil, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rc.Close()
+
+	zr := zstd.NewReader(rc)
+	defer zr.Close()
+
+	br := &backupReader{r: bufio.NewReader(zr)}
+
+	info, err := br.readHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if info.From != from || schemaVersion(info.Schema) != s.schema {
+		return nil, nil, fmt.Errorf("%w, unexpected segment from height %d", ErrInvalidBackup, info.From)
+	}
+
+	// The heights below the cold tier boundary (if any) are restored to the cold tier
+	coldFrom := uint64(0)
+
+	if s.cold != nil {
+		if coldFrom, err = coldBoundary(s.db, s.ns); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var (
+		hot  = s.db.NewBatch()
+		cold *pebble.Batch
+
+		heightPrefix = encodeStringAscending(slices.Clone(s.ns), prefixKeyHeights)
+		kindOffset   = heightKindOffset(s.ns)
+	)
+
+	if s.cold != nil {
+		cold = s.cold.NewBatch()
+	}
+
+	closeBatches := func() error {
+		if cold == nil {
+			return hot.Close()
+		}
+
+		return multierr.Append(hot.Close(), cold.Close())
+	}
+
+	for {
+		key, value, err := br.readRecord()
+		if err != nil {
+			return nil, nil, multierr.Append(err, closeBatches())
+		}
+
+		if key == nil {
+			break
+		}
+
+		key = append(slices.Clone(s.ns), key...)
+
+		// The index entries are rebuilt along with the txs, in their tier
+		if !bytes.HasPrefix(key, heightPrefix) || len(key) <= kindOffset {
+			continue
+		}
+
+		_, height, err := decodeUint64Ascending(key[len(heightPrefix):])
+		if err != nil {
+			return nil, nil, multierr.Append(err, closeBatches())
+		}
+
+		b := hot
+		if height < coldFrom {
+			b = cold
+		}
+
+		if err := b.Set(key, value, nil); err != nil {
+			return nil, nil, multierr.Append(err, closeBatches())
+		}
+
+		if key[kindOffset] != kindTx {
+			continue
+		}
+
+		tx, err := decodeTx(value)
+		if err != nil {
+			return nil, nil, multierr.Append(fmt.Errorf("unable to decode tx, %w", err), closeBatches())
+		}
+
+		for _, indexKey := range txIndexKeys(s.ns, s.decoder, tx) {
+			if err := b.Set(indexKey, key, nil); err != nil {
+				return nil, nil, multierr.Append(err, closeBatches())
+			}
+		}
+	}
+
+	return hot, cold, nil
+}
@@ -0,0 +1,357 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/objstore"
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+func TestStorage_Prune(t *testing.T) {
+	t.Parallel()
+
+	t.Run("archived heights", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		s, err := NewPebble(
+			filepath.Join(dir, "hot"),
+			WithNamespace("chain"),
+			WithColdTier(filepath.Join(dir, "cold"), DefaultColdCompressionLevel),
+			WithPruneArchive(objstore.NewDir(filepath.Join(dir, "archive")), "segments/"),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		blocks, txs := generateChain(t, 2500, 2)
+		saveChain(t, s, blocks, txs)
+
+		// The pruned segment spans both tiers
+		_, err = s.MoveToColdTier(2000)
+		require.NoError(t, err)
+
+		pruned, err := s.Prune(context.Background(), 1000)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(PruneSegmentHeights), pruned)
+
+		boundary, err := s.PruneBoundary()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(PruneSegmentHeights), boundary)
+
+		// Make sure only the whole segments are pruned
+		pruned, err = s.Prune(context.Background(), 1000)
+		require.NoError(t, err)
+
+		assert.Zero(t, pruned)
+
+		// Make sure the pruned heights are removed from both tiers, along with the index entries
+		_, err = s.getBlock(s.cold, 500)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		_, err = s.getBlock(s.db, 999)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		// The tx hash index entries are kept, for restoring the txs by hash
+		_, err = s.getTxByHash(s.db, base64.StdEncoding.EncodeToString(txs[998].Tx.Hash()))
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		_, err = s.getTxByHash(s.cold, base64.StdEncoding.EncodeToString(txs[998].Tx.Hash()))
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		_, err = os.Stat(filepath.Join(dir, "archive", "segments", "chain", "000000000000-000000000999.seg.zst"))
+		require.NoError(t, err)
+
+		// Make sure the pruned segment is restored once read
+		block, err := s.GetBlock(500)
+		require.NoError(t, err)
+
+		assert.Equal(t, blocks[499], block)
+
+		assertChain(t, s, blocks, txs)
+	})
+
+	t.Run("restored segments", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		s, err := NewPebble(
+			filepath.Join(dir, "hot"),
+			WithNamespace("chain"),
+			WithPruneArchive(objstore.NewDir(filepath.Join(dir, "archive")), ""),
+			WithRestoreLimit(1),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		now := time.Now()
+		s.archive.now = func() time.Time {
+			return now
+		}
+
+		blocks, txs := generateChain(t, 3100, 1)
+		saveChain(t, s, blocks, txs)
+
+		_, err = s.Prune(context.Background(), 100)
+		require.NoError(t, err)
+
+		// Make sure the pruned txs are restored by hash
+		tx, err := s.GetTxByHash(base64.StdEncoding.EncodeToString(txs[499].Tx.Hash()))
+		require.NoError(t, err)
+
+		assert.Equal(t, txs[499], tx)
+
+		// Make sure the restores over the limit are rejected
+		_, err = s.GetBlock(1500)
+		assert.ErrorIs(t, err, ErrRestoreLimit)
+
+		now = now.Add(restoreWindow)
+
+		block, err := s.GetBlock(1500)
+		require.NoError(t, err)
+
+		assert.Equal(t, blocks[1499], block)
+
+		// Make sure the restored segments are kept until expired
+		pruned, err := s.Prune(context.Background(), 100)
+		require.NoError(t, err)
+
+		assert.Zero(t, pruned)
+
+		now = now.Add(DefaultRestoredSegmentTTL)
+
+		pruned, err = s.Prune(context.Background(), 100)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2*PruneSegmentHeights), pruned)
+
+		for _, height := range []uint64{500, 1500} {
+			_, err = s.getBlock(s.db, height)
+			assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+		}
+
+		// Make sure the pruned again segments are restored once read
+		tx, err = s.GetTxByHash(base64.StdEncoding.EncodeToString(txs[499].Tx.Hash()))
+		require.NoError(t, err)
+
+		assert.Equal(t, txs[499], tx)
+
+		// Make sure the archived namespace keeps the prune boundary
+		require.NoError(t, s.Archive("archived"))
+
+		archived, err := s.WithNamespace("archived")
+		require.NoError(t, err)
+
+		boundary, err := archived.PruneBoundary()
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(3*PruneSegmentHeights), boundary)
+	})
+
+	t.Run("standby", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		primary, err := NewPebble(
+			filepath.Join(dir, "hot"),
+			WithColdTier(filepath.Join(dir, "cold"), DefaultColdCompressionLevel),
+			WithPruneArchive(objstore.NewDir(filepath.Join(dir, "archive")), ""),
+		)
+		require.NoError(t, err)
+
+		standby, err := NewPebble(filepath.Join(dir, "standby"))
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, primary.Close())
+			assert.NoError(t, standby.Close())
+		}()
+
+		// The chain batches are applied at once, so the queue holds all of them
+		changes, unsubscribe := primary.SubscribeChanges(10_000)
+		defer unsubscribe()
+
+		// applyChanges applies the published primary changes to the standby
+		applyChanges := func() {
+			for {
+				select {
+				case batch, ok := <-changes:
+					require.True(t, ok, "standby dropped")
+					require.NoError(t, standby.ApplyChanges(batch))
+				default:
+					return
+				}
+			}
+		}
+
+		blocks, txs := generateChain(t, 1500, 2)
+		saveChain(t, primary, blocks, txs)
+
+		_, err = primary.MoveToColdTier(1200)
+		require.NoError(t, err)
+
+		_, err = primary.Prune(context.Background(), 100)
+		require.NoError(t, err)
+
+		applyChanges()
+
+		// Make sure the standby prunes the heights along with the primary,
+		// including the index entries of the heights pruned from the primary cold tier
+		_, err = standby.GetBlock(500)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		it, err := standby.TxByAddressIterator(crypto.Address{1}.String(), 1, 999)
+		assert.Empty(t, collect(t, it, err))
+
+		// Make sure the standby restores the heights along with the primary,
+		// including the ones restored to the primary cold tier
+		_, err = primary.GetBlock(500)
+		require.NoError(t, err)
+
+		applyChanges()
+
+		for _, height := range []uint64{1, 500, 999} {
+			block, err := standby.GetBlock(height)
+			require.NoError(t, err)
+
+			assert.Equal(t, blocks[height-1], block)
+		}
+
+		assertChain(t, standby, blocks, txs)
+	})
+
+	t.Run("restore timeout", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			dir = t.TempDir()
+
+			archive = objstore.NewDir(filepath.Join(dir, "archive"))
+			started = make(chan struct{})
+			blocked = make(chan struct{})
+		)
+
+		defer close(blocked)
+
+		s, err := NewPebble(
+			filepath.Join(dir, "hot"),
+			WithPruneArchive(&mockStore{
+				getFn: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					// The first segment fetch never completes
+					if strings.HasPrefix(key, "000000000000-") {
+						close(started)
+
+						select {
+						case <-ctx.Done():
+							return nil, ctx.Err()
+						case <-blocked:
+						}
+					}
+
+					return archive.Get(ctx, key)
+				},
+				putFn: archive.Put,
+			}, ""),
+			WithRestoreTimeout(200*time.Millisecond),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		blocks, txs := generateChain(t, 2100, 1)
+		saveChain(t, s, blocks, txs)
+
+		_, err = s.Prune(context.Background(), 100)
+		require.NoError(t, err)
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			_, err := s.GetBlock(500)
+
+			errCh <- err
+		}()
+
+		<-started
+
+		// Make sure the slow fetch doesn't block the restores of the other segments
+		block, err := s.GetBlock(1500)
+		require.NoError(t, err)
+
+		assert.Equal(t, blocks[1499], block)
+
+		// Make sure the slow fetch times out
+		select {
+		case err := <-errCh:
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(5 * time.Second):
+			t.Fatal("restore not timed out")
+		}
+	})
+
+	t.Run("no archive", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewPebble(t.TempDir())
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		blocks, txs := generateChain(t, 2100, 1)
+		saveChain(t, s, blocks, txs)
+
+		pruned, err := s.Prune(context.Background(), 100)
+		require.NoError(t, err)
+
+		assert.Equal(t, uint64(2*PruneSegmentHeights), pruned)
+
+		_, err = s.GetBlock(1999)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		_, err = s.GetTx(1999, 0)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		block, err := s.GetBlock(2000)
+		require.NoError(t, err)
+
+		assert.Equal(t, blocks[1999], block)
+	})
+}
+
+type mockStore struct {
+	getFn func(context.Context, string) (io.ReadCloser, error)
+	putFn func(context.Context, string, []byte) error
+}
+
+func (m *mockStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return m.getFn(ctx, key)
+}
+
+func (m *mockStore) Put(ctx context.Context, key string, data []byte) error {
+	return m.putFn(ctx, key, data)
+}
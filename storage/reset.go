@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/multierr"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+var errArchiveExists = errors.New("the archive namespace already holds indexed data")
+
+// dataPrefixes are the key prefixes of the indexed namespace data
+var dataPrefixes = []string{
+	prefixKeyBlocks,
+	prefixKeyTxs,
+	prefixKeyHeights,
+	prefixKeyTxByHash,
+	prefixKeyTxByAddress,
+	prefixKeyPlugins,
+}
+
+// Wipe deletes all the indexed data of the namespace (the blocks, txs, their index entries
+// and the plugin data) from both storage tiers, for indexing the chain again from scratch.
+// The other namespaces are not affected
+func (s *Pebble) Wipe() error {
+	// The cold tier is wiped first, as its data
+	// is only read below the hot tier boundary
+	if s.cold != nil {
+		if err := wipeNamespace(s.cold, s.ns, nil); err != nil {
+			return fmt.Errorf("unable to wipe cold tier, %w", err)
+		}
+	}
+
+	if err := wipeNamespace(s.db, s.ns, s.feed); err != nil {
+		return fmt.Errorf("unable to wipe storage, %w", err)
+	}
+
+	return nil
+}
+
+// Archive moves all the indexed data of the namespace to the given (empty) namespace,
+// in both storage tiers, keeping the data of a reset chain queryable.
+// The namespace is empty once archived
+func (s *Pebble) Archive(namespace string) error {
+	target := keyNamespace(namespace)
+
+	if _, err := get(s.db, keyLatest(target)); !errors.Is(err, storageErrors.ErrNotFound) {
+		if err == nil {
+			err = errArchiveExists
+		}
+
+		return fmt.Errorf("unable to archive to namespace %s, %w", namespace, err)
+	}
+
+	if s.cold != nil {
+		if err := copyNamespace(s.cold, s.ns, target); err != nil {
+			return fmt.Errorf("unable to archive cold tier, %w", err)
+		}
+	}
+
+	if err := copyNamespace(s.db, s.ns, target); err != nil {
+		return fmt.Errorf("unable to archive storage, %w", err)
+	}
+
+	return s.Wipe()
+}
+
+// wipeNamespace deletes the indexed data of the namespace from the DB,
+// publishing the deletion to the changefeed, if any
+func wipeNamespace(db *pebble.DB, ns []byte, feed *changefeed) error {
+	b := db.NewBatch()
+
+	for _, prefix := range dataPrefixes {
+		lower := encodeStringAscending(slices.Clone(ns), prefix)
+
+		if err := b.DeleteRange(lower, prefixUpperBound(lower), nil); err != nil {
+			return multierr.Append(err, b.Close())
+		}
+	}
+
+	for _, key := range [][]byte{keyLatest(ns), keyBoundary(ns), keyPrune(ns)} {
+		if err := b.Delete(key, nil); err != nil {
+			return multierr.Append(err, b.Close())
+		}
+	}
+
+	restored := encodeStringAscending(slices.Clone(ns), prefixKeyRestored)
+
+	if err := b.DeleteRange(restored, prefixUpperBound(restored), nil); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	if err := feed.commit(ns, b); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	return b.Close()
+}
+
+// copyNamespace copies the indexed data (along with the latest height, tier boundary,
+// prune boundary, schema version and address index marker) of the namespace to the target namespace, in batches
+func copyNamespace(db *pebble.DB, ns, target []byte) error {
+	snap := db.NewSnapshot()
+	defer snap.Close()
+
+	var (
+		pending = 0
+		b       = db.NewBatch()
+	)
+
+	// copyRange copies the key-value pairs in the range [lower, upper).
+	// The index values (tx keys) are moved to the target namespace as well
+	copyRange := func(lower, upper []byte, index bool) error {
+		it, err := snap.NewIter(&pebble.IterOptions{
+			LowerBound: lower,
+			UpperBound: upper,
+		})
+		if err != nil {
+			return err
+		}
+
+		for it.First(); it.Valid(); it.Next() {
+			key, value := append(slices.Clone(target), it.Key()[len(ns):]...), it.Value()
+			if index {
+				value = append(slices.Clone(target), value[len(ns):]...)
+			}
+
+			if err := b.Set(key, value, nil); err != nil {
+				return multierr.Append(err, it.Close())
+			}
+
+			if pending++; pending < migrateBatchSize {
+				continue
+			}
+
+			committed := b
+
+			pending = 0
+			b = db.NewBatch()
+
+			if err := multierr.Append(committed.Commit(pebble.Sync), committed.Close()); err != nil {
+				return multierr.Append(err, it.Close())
+			}
+		}
+
+		return multierr.Append(it.Error(), it.Close())
+	}
+
+	for _, prefix := range dataPrefixes {
+		lower := encodeStringAscending(slices.Clone(ns), prefix)
+
+		index := prefix == prefixKeyTxByHash || prefix == prefixKeyTxByAddress
+
+		if err := copyRange(lower, prefixUpperBound(lower), index); err != nil {
+			return multierr.Append(err, b.Close())
+		}
+	}
+
+	for _, key := range [][]byte{keyLatest(ns), keyBoundary(ns), keyPrune(ns), keySchema(ns), keyAddressIndex(ns)} {
+		if err := copyRange(key, append(slices.Clone(key), 0), false); err != nil {
+			return multierr.Append(err, b.Close())
+		}
+	}
+
+	if err := b.Commit(pebble.Sync); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	return b.Close()
+}
+
+// WipePlugin deletes the data of the plugin from the namespace,
+// for rebuilding it from the indexed blocks
+func (s *Pebble) WipePlugin(plugin string) error {
+	b := s.db.NewBatch()
+
+	lower := keyPluginPrefix(s.ns, plugin)
+
+	if err := b.DeleteRange(lower, prefixUpperBound(lower), nil); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	if err := s.feed.commit(s.ns, b); err != nil {
+		return multierr.Append(fmt.Errorf("unable to wipe plugin %s, %w", plugin, err), b.Close())
+	}
+
+	return b.Close()
+}
+
+// HasPluginData returns a flag indicating if any data of the plugin is saved in the namespace
+func (s *Pebble) HasPluginData(plugin string) (bool, error) {
+	lower := keyPluginPrefix(s.ns, plugin)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: prefixUpperBound(lower),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	found := it.First()
+
+	return found, multierr.Append(it.Error(), it.Close())
+}
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// assertWiped makes sure the storage holds none of the chain data
+func assertWiped(t *testing.T, s *Pebble) {
+	t.Helper()
+
+	_, err := s.GetLatestHeight()
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	for height := uint64(1); height <= 5; height++ {
+		_, err = s.GetBlock(height)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+		_, err = s.GetTx(height, 0)
+		assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+	}
+
+	assert.Equal(t, 0, countAddressTxs(t, s, crypto.Address{1}))
+
+	_, err = s.GetPluginValue("plugin", []byte("key"))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+}
+
+func TestStorage_Wipe(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	testTable := []struct {
+		name      string
+		namespace string
+		cold      bool
+	}{
+		{
+			"no namespace",
+			"",
+			false,
+		},
+		{
+			"namespace",
+			"test5",
+			false,
+		},
+		{
+			"cold tier",
+			"test5",
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(dir, testCase.name)
+
+			db, err := NewPebble(
+				filepath.Join(path, "hot"),
+				WithColdTier(filepath.Join(path, "cold"), DefaultColdCompressionLevel),
+			)
+			require.NoError(t, err)
+
+			defer func() {
+				assert.NoError(t, db.Close())
+			}()
+
+			s, err := db.WithNamespace(testCase.namespace)
+			require.NoError(t, err)
+
+			other, err := db.WithNamespace("other")
+			require.NoError(t, err)
+
+			blocks, txs := generateChain(t, 5, 3)
+
+			saveChain(t, s, blocks, txs)
+			saveChain(t, other, blocks, txs)
+
+			wb := s.WriteBatch()
+			require.NoError(t, wb.SetPluginValue("plugin", []byte("key"), []byte("value")))
+			require.NoError(t, wb.Commit())
+
+			if testCase.cold {
+				_, err := s.MoveToColdTier(2)
+				require.NoError(t, err)
+			}
+
+			require.NoError(t, s.Wipe())
+
+			// Make sure the namespace data is wiped,
+			// and the other namespace is untouched
+			assertWiped(t, s)
+			assertChain(t, other, blocks, txs)
+
+			for _, tx := range txs {
+				_, err = s.GetTxByHash(base64.StdEncoding.EncodeToString(tx.Tx.Hash()))
+				assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+			}
+
+			// Make sure the chain can be indexed again
+			saveChain(t, s, blocks, txs)
+			assertChain(t, s, blocks, txs)
+		})
+	}
+}
+
+func TestStorage_Archive(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	db, err := NewPebble(
+		filepath.Join(path, "hot"),
+		WithColdTier(filepath.Join(path, "cold"), DefaultColdCompressionLevel),
+	)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	blocks, txs := generateChain(t, 5, 3)
+
+	saveChain(t, db, blocks, txs)
+
+	_, err = db.MoveToColdTier(2)
+	require.NoError(t, err)
+
+	require.NoError(t, db.Archive("test5-1"))
+
+	// Make sure the data is moved to the archive namespace
+	assertWiped(t, db)
+
+	archive, err := db.WithNamespace("test5-1")
+	require.NoError(t, err)
+
+	assertChain(t, archive, blocks, txs)
+
+	latest, err := archive.GetLatestHeight()
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(5), latest)
+
+	// Make sure the archive is not overwritten
+	saveChain(t, db, blocks, txs)
+
+	assert.ErrorIs(t, db.Archive("test5-1"), errArchiveExists)
+	assertChain(t, db, blocks, txs)
+}
+
+func TestStorage_WipePlugin(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	wb := db.WriteBatch()
+
+	for _, plugin := range []string{"plugin", "plugins", "other"} {
+		require.NoError(t, wb.SetPluginValue(plugin, []byte("key"), []byte("value")))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	require.NoError(t, db.WipePlugin("plugin"))
+
+	_, err = db.GetPluginValue("plugin", []byte("key"))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	found, err := db.HasPluginData("plugin")
+	require.NoError(t, err)
+
+	assert.False(t, found)
+
+	// Make sure the other plugins are not affected
+	for _, plugin := range []string{"plugins", "other"} {
+		value, err := db.GetPluginValue(plugin, []byte("key"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []byte("value"), value)
+
+		found, err := db.HasPluginData(plugin)
+		require.NoError(t, err)
+
+		assert.True(t, found)
+	}
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// BlockResults fetches the saved transaction results of the block, in index order
+func BlockResults(r Reader, block *types.Block) ([]*types.TxResult, error) {
+	results := make([]*types.TxResult, 0, block.NumTxs)
+
+	for index := range uint32(block.NumTxs) {
+		result, err := r.GetTx(uint64(block.Height), index)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch tx %d of block %d, %w", index, block.Height, err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"go.uber.org/multierr"
+)
+
+// ReplaceHeight replaces the saved block and txs of the height (along with their index entries)
+// with the given ones, in the storage tier holding the height. The latest height is unchanged.
+// The plugin data, aggregated over the whole chain, is not rebuilt (see plugins.Replay)
+func (s *Pebble) ReplaceHeight(block *types.Block, txs []*types.TxResult) error {
+	height := uint64(block.Height)
+
+	db, compressionLevel := s.db, s.compressionLevel
+
+	if s.cold != nil {
+		boundary, err := coldBoundary(s.db, s.ns)
+		if err != nil {
+			return err
+		}
+
+		if height < boundary {
+			db, compressionLevel = s.cold, s.coldCompressionLevel
+		}
+	}
+
+	b := &PebbleBatch{
+		b:                db.NewIndexedBatch(),
+		ns:               s.ns,
+		schema:           s.schema,
+		compressionLevel: compressionLevel,
+		conflicts:        ConflictOverwrite, // the height data is replaced
+		feed:             s.feed,
+		decoder:          s.decoder,
+	}
+
+	if err := s.deleteHeight(b.b, height); err != nil {
+		return multierr.Append(fmt.Errorf("unable to delete height %d, %w", height, err), b.Rollback())
+	}
+
+	if err := b.SetBlock(block); err != nil {
+		return multierr.Append(err, b.Rollback())
+	}
+
+	for _, tx := range txs {
+		if err := b.SetTx(tx); err != nil {
+			return multierr.Append(err, b.Rollback())
+		}
+	}
+
+	if err := b.Commit(); err != nil {
+		return multierr.Append(err, b.Rollback())
+	}
+
+	return b.Rollback()
+}
+
+// deleteHeight deletes the saved block and txs of the height, along with their index entries
+func (s *Pebble) deleteHeight(b *pebble.Batch, height uint64) error {
+	var (
+		lower = keyHeight(s.ns, height)
+		upper = keyHeight(s.ns, height+1)
+
+		kindOffset = heightKindOffset(s.ns)
+	)
+
+	if s.schema == schemaV1 {
+		lower, upper, kindOffset = keyTx(s.ns, height, 0), keyTx(s.ns, height+1, 0), -1
+
+		if err := b.Delete(keyBlock(s.ns, height), nil); err != nil {
+			return err
+		}
+	}
+
+	it, err := b.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: upper,
+	})
+	if err != nil {
+		return err
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		// Skip the non-tx data
+		if kindOffset >= 0 && it.Key()[kindOffset] != kindTx {
+			continue
+		}
+
+		tx, err := decodeTx(it.Value())
+		if err != nil {
+			return multierr.Append(fmt.Errorf("unable to decode tx, %w", err), it.Close())
+		}
+
+		for _, indexKey := range txIndexKeys(s.ns, s.decoder, tx) {
+			if err := b.Delete(indexKey, nil); err != nil {
+				return multierr.Append(err, it.Close())
+			}
+		}
+	}
+
+	if err := multierr.Append(it.Error(), it.Close()); err != nil {
+		return err
+	}
+
+	return b.DeleteRange(lower, upper, nil)
+}
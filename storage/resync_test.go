@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// countAddressTxs returns the number of txs the address participated in
+func countAddressTxs(t *testing.T, s *Pebble, address crypto.Address) int {
+	t.Helper()
+
+	it, err := s.TxByAddressIterator(address.String(), 0, 0)
+	require.NoError(t, err)
+
+	defer it.Close()
+
+	count := 0
+
+	for it.Next() {
+		_, err := it.Value()
+		require.NoError(t, err)
+
+		count++
+	}
+
+	require.NoError(t, it.Error())
+
+	return count
+}
+
+func TestStorage_ReplaceHeight(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	testTable := []struct {
+		name   string
+		schema schemaVersion
+		cold   bool
+	}{
+		{
+			"legacy key schema",
+			schemaV1,
+			false,
+		},
+		{
+			"current key schema",
+			schemaV2,
+			false,
+		},
+		{
+			"cold tier",
+			schemaV2,
+			true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(dir, testCase.name)
+
+			s, err := NewPebble(
+				filepath.Join(path, "hot"),
+				WithColdTier(filepath.Join(path, "cold"), DefaultColdCompressionLevel),
+			)
+			require.NoError(t, err)
+
+			defer func() {
+				assert.NoError(t, s.Close())
+			}()
+
+			require.NoError(t, saveSchema(s.db, s.ns, testCase.schema))
+
+			s.schema = testCase.schema
+
+			blocks, txs := generateChain(t, 5, 3)
+			saveChain(t, s, blocks, txs)
+
+			if testCase.cold {
+				_, err := s.MoveToColdTier(2)
+				require.NoError(t, err)
+			}
+
+			// Replace height 3, with a single tx of other addresses
+			block := &types.Block{
+				Header: types.Header{
+					Height: 3,
+					NumTxs: 1,
+				},
+			}
+
+			tx := &types.TxResult{
+				Height: 3,
+				Index:  0,
+				Tx: amino.MustMarshal(&std.Tx{
+					Msgs: []std.Msg{
+						bank.MsgSend{
+							FromAddress: crypto.Address{3},
+							ToAddress:   crypto.Address{4},
+						},
+					},
+					Memo: "replaced",
+				}),
+			}
+
+			require.NoError(t, s.ReplaceHeight(block, []*types.TxResult{tx}))
+
+			// Make sure the height data is replaced
+			saved, err := s.GetBlock(3)
+			require.NoError(t, err)
+
+			assert.Equal(t, block, saved)
+
+			savedTx, err := s.GetTx(3, 0)
+			require.NoError(t, err)
+
+			assert.Equal(t, tx, savedTx)
+
+			_, err = s.GetTx(3, 1)
+			assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+			// Make sure the indexes are rebuilt
+			for _, replaced := range txs[6:9] {
+				_, err = s.GetTxByHash(base64.StdEncoding.EncodeToString(replaced.Tx.Hash()))
+				assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+			}
+
+			savedTx, err = s.GetTxByHash(base64.StdEncoding.EncodeToString(tx.Tx.Hash()))
+			require.NoError(t, err)
+
+			assert.Equal(t, tx, savedTx)
+
+			assert.Equal(t, 12, countAddressTxs(t, s, crypto.Address{1}))
+			assert.Equal(t, 1, countAddressTxs(t, s, crypto.Address{3}))
+
+			// Make sure the other heights are untouched
+			assertHeights := append(append([]*types.TxResult{}, txs[:6]...), txs[9:]...)
+
+			for _, saved := range assertHeights {
+				got, err := s.GetTx(uint64(saved.Height), saved.Index)
+				require.NoError(t, err)
+
+				assert.Equal(t, saved, got)
+			}
+
+			latest, err := s.GetLatestHeight()
+			require.NoError(t, err)
+
+			assert.Equal(t, uint64(5), latest)
+		})
+	}
+}
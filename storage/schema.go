@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	// keySchemaVersion is the lookup key for the
+	// key schema version of the namespace data
+	keySchemaVersion = "/meta/sv"
+
+	// prefixKeyHeights is the prefix for the data saved by height (schema v2).
+	// Each block is followed by its transactions
+	prefixKeyHeights = "/data/h/"
+)
+
+// The kinds of data saved by height (schema v2)
+const (
+	kindBlock byte = 0x00
+	kindTx    byte = 0x01
+)
+
+// schemaVersion is the version of the storage key schema
+type schemaVersion uint64
+
+const (
+	// schemaV1 saves the blocks and txs under separate prefixes,
+	// so reading a height range requires a scan for each
+	schemaV1 schemaVersion = 1
+
+	// schemaV2 saves the blocks and txs under a common height prefix,
+	// so the data of a height range is contiguous and read in a single scan
+	schemaV2 schemaVersion = 2
+)
+
+func keySchema(ns []byte) []byte {
+	key := slices.Clone(ns)
+	key = append(key, keySchemaVersion...)
+
+	return key
+}
+
+// keyHeight returns the key prefix for the data of the given height (schema v2)
+func keyHeight(ns []byte, blockNum uint64) []byte {
+	key := slices.Clone(ns)
+	key = encodeStringAscending(key, prefixKeyHeights)
+	key = encodeUint64Ascending(key, blockNum)
+
+	return key
+}
+
+func keyHeightBlock(ns []byte, blockNum uint64) []byte {
+	return append(keyHeight(ns, blockNum), kindBlock)
+}
+
+func keyHeightTx(ns []byte, blockNum uint64, txIndex uint32) []byte {
+	key := append(keyHeight(ns, blockNum), kindTx)
+	key = encodeUint32Ascending(key, txIndex)
+
+	return key
+}
+
+// heightKindOffset returns the offset of the data kind byte in the height keys (schema v2)
+func heightKindOffset(ns []byte) int {
+	return len(keyHeight(ns, 0))
+}
+
+// keyBlock returns the block key, following the schema
+func (v schemaVersion) keyBlock(ns []byte, blockNum uint64) []byte {
+	if v == schemaV1 {
+		return keyBlock(ns, blockNum)
+	}
+
+	return keyHeightBlock(ns, blockNum)
+}
+
+// keyTx returns the tx result key, following the schema
+func (v schemaVersion) keyTx(ns []byte, blockNum uint64, txIndex uint32) []byte {
+	if v == schemaV1 {
+		return keyTx(ns, blockNum, txIndex)
+	}
+
+	return keyHeightTx(ns, blockNum, txIndex)
+}
+
+// loadSchema loads the key schema version of the namespace.
+// Namespaces with data but no saved version predate the versioning, and use schema v1.
+// Empty namespaces use the latest schema, saved unless the DB is read-only
+func loadSchema(db *pebble.DB, ns []byte, readOnly bool) (schemaVersion, error) {
+	value, err := get(db, keySchema(ns))
+	if err == nil {
+		_, version, decodeErr := decodeUint64Ascending(value)
+		if decodeErr != nil {
+			return 0, fmt.Errorf("unable to decode schema version, %w", decodeErr)
+		}
+
+		return schemaVersion(version), nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, fmt.Errorf("unable to fetch schema version, %w", err)
+	}
+
+	_, err = get(db, keyLatest(ns))
+	if err == nil {
+		return schemaV1, nil
+	}
+
+	if !errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	if readOnly {
+		return schemaV2, nil
+	}
+
+	if err := saveSchema(db, ns, schemaV2); err != nil {
+		return 0, err
+	}
+
+	return schemaV2, nil
+}
+
+// saveSchema saves the key schema version of the namespace
+func saveSchema(w pebble.Writer, ns []byte, version schemaVersion) error {
+	if err := w.Set(keySchema(ns), encodeUint64Ascending(nil, uint64(version)), pebble.Sync); err != nil {
+		return fmt.Errorf("unable to save schema version, %w", err)
+	}
+
+	return nil
+}
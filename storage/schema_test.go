@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateChain generates dummy blocks, each with the given number of transfer txs
+func generateChain(t *testing.T, blockNum, txsPerBlock int) ([]*types.Block, []*types.TxResult) {
+	t.Helper()
+
+	var (
+		blocks = make([]*types.Block, 0, blockNum)
+		txs    = make([]*types.TxResult, 0, blockNum*txsPerBlock)
+	)
+
+	for height := 1; height <= blockNum; height++ {
+		blocks = append(blocks, &types.Block{
+			Header: types.Header{
+				Height: int64(height),
+				NumTxs: int64(txsPerBlock),
+			},
+		})
+
+		for index := 0; index < txsPerBlock; index++ {
+			tx := &std.Tx{
+				Msgs: []std.Msg{
+					bank.MsgSend{
+						FromAddress: crypto.Address{1},
+						ToAddress:   crypto.Address{2},
+					},
+				},
+				Memo: fmt.Sprintf("tx %d-%d", height, index),
+			}
+
+			txs = append(txs, &types.TxResult{
+				Height: int64(height),
+				Index:  uint32(index),
+				Tx:     amino.MustMarshal(tx),
+			})
+		}
+	}
+
+	return blocks, txs
+}
+
+// saveChain saves the blocks and txs in a single batch
+func saveChain(t *testing.T, s *Pebble, blocks []*types.Block, txs []*types.TxResult) {
+	t.Helper()
+
+	wb := s.WriteBatch()
+
+	for _, block := range blocks {
+		require.NoError(t, wb.SetBlock(block))
+	}
+
+	for _, tx := range txs {
+		require.NoError(t, wb.SetTx(tx))
+	}
+
+	require.NoError(t, wb.SetLatestHeight(uint64(blocks[len(blocks)-1].Height)))
+	require.NoError(t, wb.Commit())
+}
+
+// assertChain makes sure the blocks and txs are readable from storage
+func assertChain(t *testing.T, s *Pebble, blocks []*types.Block, txs []*types.TxResult) {
+	t.Helper()
+
+	for _, block := range blocks {
+		saved, err := s.GetBlock(uint64(block.Height))
+		require.NoError(t, err)
+
+		assert.Equal(t, block, saved)
+	}
+
+	for _, tx := range txs {
+		saved, err := s.GetTx(uint64(tx.Height), tx.Index)
+		require.NoError(t, err)
+
+		assert.Equal(t, tx, saved)
+
+		saved, err = s.GetTxByHash(base64.StdEncoding.EncodeToString(tx.Tx.Hash()))
+		require.NoError(t, err)
+
+		assert.Equal(t, tx, saved)
+	}
+
+	// Make sure the iterators only return the requested data
+	blockIt, err := s.BlockIterator(2, 4)
+	require.NoError(t, err)
+
+	iterated := make([]*types.Block, 0, 2)
+
+	for blockIt.Next() {
+		block, err := blockIt.Value()
+		require.NoError(t, err)
+
+		iterated = append(iterated, block)
+	}
+
+	require.NoError(t, blockIt.Error())
+	require.NoError(t, blockIt.Close())
+
+	assert.Equal(t, blocks[1:3], iterated)
+
+	txIt, err := s.TxIterator(2, 4, 1, 2)
+	require.NoError(t, err)
+
+	iteratedTxs := make([]*types.TxResult, 0, 2)
+
+	for txIt.Next() {
+		tx, err := txIt.Value()
+		require.NoError(t, err)
+
+		iteratedTxs = append(iteratedTxs, tx)
+	}
+
+	require.NoError(t, txIt.Error())
+	require.NoError(t, txIt.Close())
+
+	require.Len(t, iteratedTxs, 3)
+
+	for index, tx := range iteratedTxs {
+		assert.Equal(t, int64(index+2), tx.Height)
+		assert.Equal(t, uint32(1), tx.Index)
+	}
+
+	addressIt, err := s.TxByAddressIterator(crypto.Address{1}.String(), 0, 0)
+	require.NoError(t, err)
+
+	iteratedTxs = make([]*types.TxResult, 0, len(txs))
+
+	for addressIt.Next() {
+		tx, err := addressIt.Value()
+		require.NoError(t, err)
+
+		iteratedTxs = append(iteratedTxs, tx)
+	}
+
+	require.NoError(t, addressIt.Error())
+	require.NoError(t, addressIt.Close())
+
+	assert.Equal(t, txs, iteratedTxs)
+}
+
+func TestSchema_Versions(t *testing.T) {
+	t.Parallel()
+
+	blocks, txs := generateChain(t, 5, 3)
+
+	t.Run("new namespace", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewPebble(t.TempDir())
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		// Make sure new data uses the latest schema
+		assert.Equal(t, schemaV2, s.schema)
+
+		saveChain(t, s, blocks, txs)
+		assertChain(t, s, blocks, txs)
+	})
+
+	t.Run("legacy namespace", func(t *testing.T) {
+		t.Parallel()
+
+		path := t.TempDir()
+
+		s, err := NewPebble(path)
+		require.NoError(t, err)
+
+		// Simulate a DB predating the schema versioning
+		require.NoError(t, s.db.Delete(keySchema(s.ns), nil))
+		require.NoError(t, s.db.Set(keyLatest(s.ns), encodeUint64Ascending(nil, 1), nil))
+		require.NoError(t, s.Close())
+
+		s, err = NewPebble(path)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		assert.Equal(t, schemaV1, s.schema)
+
+		saveChain(t, s, blocks, txs)
+		assertChain(t, s, blocks, txs)
+	})
+}
+
+func TestStorage_MigrateSchema(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir()
+
+	s, err := NewPebble(path, WithNamespace("test"))
+	require.NoError(t, err)
+
+	// Save the chain in the legacy schema
+	require.NoError(t, saveSchema(s.db, s.ns, schemaV1))
+
+	s.schema = schemaV1
+
+	blocks, txs := generateChain(t, 5, 3)
+	saveChain(t, s, blocks, txs)
+
+	migrated, err := s.MigrateSchema()
+	require.NoError(t, err)
+
+	assert.Equal(t, len(blocks)+len(txs), migrated)
+	assert.Equal(t, schemaV2, s.schema)
+
+	// Make sure the legacy keys are gone
+	_, err = get(s.db, keyBlock(s.ns, 1))
+	assert.Error(t, err)
+
+	_, err = get(s.db, keyTx(s.ns, 1, 0))
+	assert.Error(t, err)
+
+	assertChain(t, s, blocks, txs)
+
+	require.NoError(t, s.Close())
+
+	// Make sure the schema version is persisted
+	s, err = NewPebble(path, WithNamespace("test"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	assert.Equal(t, schemaV2, s.schema)
+
+	assertChain(t, s, blocks, txs)
+
+	migrated, err = s.MigrateSchema()
+	require.NoError(t, err)
+
+	assert.Zero(t, migrated)
+}
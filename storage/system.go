@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/multierr"
+)
+
+// prefixKeySystem is the prefix of the indexer's own data (the API keys, the admin audit log),
+// each component using its own sub-namespace. The system data is kept outside of the chain namespaces,
+// so it's not wiped or archived on chain resets, and not backed up or streamed to the standbys
+const prefixKeySystem = "/system/"
+
+// SystemStore is the keyspace of a component's system data
+type SystemStore interface {
+	// Get fetches the value saved under the given key
+	Get(key []byte) ([]byte, error)
+
+	// Iterator iterates over the key-value pairs, limiting the results
+	// to be between the provided keys. A nil toKey means no upper bound
+	Iterator(fromKey, toKey []byte) (Iterator[*KeyValue], error)
+
+	// WriteBatch provides a batch of the system data writes
+	WriteBatch() SystemBatch
+}
+
+// SystemBatch is a batch of the system data writes,
+// committed (or cancelled) all at the same time
+type SystemBatch interface {
+	// Set saves the value under the given key
+	Set(key, value []byte) error
+
+	// Delete removes the value under the given key
+	Delete(key []byte) error
+
+	// Commit stores the batch writes
+	Commit() error
+
+	// Rollback closes the batch, without persisting the writes (if not committed)
+	Rollback() error
+}
+
+var _ SystemStore = &System{}
+
+// System is the keyspace of a component's system data, shared by all the DB namespaces
+type System struct {
+	db     *pebble.DB
+	prefix []byte
+}
+
+// keySystemPrefix returns the key prefix of the component's system data
+func keySystemPrefix(component string) []byte {
+	var key []byte
+	key = encodeStringAscending(key, prefixKeySystem)
+	key = encodeStringAscending(key, component)
+
+	return key
+}
+
+// System returns the keyspace of the component's system data
+func (s *Pebble) System(component string) *System {
+	return &System{
+		db:     s.db,
+		prefix: keySystemPrefix(component),
+	}
+}
+
+func (s *System) key(k []byte) []byte {
+	return append(slices.Clone(s.prefix), k...)
+}
+
+// Get fetches the value saved under the given key, if any
+func (s *System) Get(key []byte) ([]byte, error) {
+	return get(s.db, s.key(key))
+}
+
+// Iterator iterates over the key-value pairs, limiting the results
+// to be between the provided keys. A nil toKey means no upper bound
+func (s *System) Iterator(fromKey, toKey []byte) (Iterator[*KeyValue], error) {
+	upperBound := s.key(toKey)
+	if toKey == nil {
+		upperBound = prefixUpperBound(s.prefix)
+	}
+
+	snap := s.db.NewSnapshot()
+
+	it, err := snap.NewIter(&pebble.IterOptions{
+		LowerBound: s.key(fromKey),
+		UpperBound: upperBound,
+	})
+	if err != nil {
+		return nil, multierr.Append(snap.Close(), err)
+	}
+
+	return &PebbleKVIter{i: it, s: snap, prefix: s.prefix}, nil
+}
+
+// WriteBatch provides a batch of the system data writes.
+// The committed batches are not published to the changefeed
+func (s *System) WriteBatch() SystemBatch {
+	return &systemBatch{
+		b:      s.db.NewBatch(),
+		system: s,
+	}
+}
+
+type systemBatch struct {
+	b      *pebble.Batch
+	system *System
+}
+
+func (b *systemBatch) Set(key, value []byte) error {
+	return b.b.Set(b.system.key(key), value, pebble.NoSync)
+}
+
+func (b *systemBatch) Delete(key []byte) error {
+	return b.b.Delete(b.system.key(key), pebble.NoSync)
+}
+
+func (b *systemBatch) Commit() error {
+	return b.b.Commit(pebble.Sync)
+}
+
+func (b *systemBatch) Rollback() error {
+	return b.b.Close()
+}
+
+// MovePluginToSystem moves the plugin data of the namespace to the component's system data,
+// for migrating the system data saved as plugin data by the earlier versions. The plugin data
+// is only copied if the component has no system data yet, and is removed in any case
+func (s *Pebble) MovePluginToSystem(plugin, component string) error {
+	system := s.System(component)
+
+	migrated, err := system.hasData()
+	if err != nil {
+		return err
+	}
+
+	if !migrated {
+		if err := s.copyPluginToSystem(plugin, system); err != nil {
+			return fmt.Errorf("unable to move plugin %s data, %w", plugin, err)
+		}
+	}
+
+	return s.WipePlugin(plugin)
+}
+
+// copyPluginToSystem copies the plugin data of the namespace to the system keyspace
+func (s *Pebble) copyPluginToSystem(plugin string, system *System) error {
+	lower := keyPluginPrefix(s.ns, plugin)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: prefixUpperBound(lower),
+	})
+	if err != nil {
+		return err
+	}
+
+	b := s.db.NewBatch()
+
+	for it.First(); it.Valid(); it.Next() {
+		if err := b.Set(system.key(it.Key()[len(lower):]), it.Value(), nil); err != nil {
+			return multierr.Combine(err, it.Close(), b.Close())
+		}
+	}
+
+	if err := multierr.Append(it.Error(), it.Close()); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	if err := b.Commit(pebble.Sync); err != nil {
+		return multierr.Append(err, b.Close())
+	}
+
+	return b.Close()
+}
+
+// hasData returns a flag indicating if any data is saved in the system keyspace
+func (s *System) hasData() (bool, error) {
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: s.prefix,
+		UpperBound: prefixUpperBound(s.prefix),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	found := it.First()
+
+	return found, multierr.Append(it.Error(), it.Close())
+}
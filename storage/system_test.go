@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+// setSystemValue saves the value in the component's system data
+func setSystemValue(t *testing.T, s *Pebble, component string, key, value []byte) {
+	t.Helper()
+
+	wb := s.System(component).WriteBatch()
+	require.NoError(t, wb.Set(key, value))
+	require.NoError(t, wb.Commit())
+	require.NoError(t, wb.Rollback())
+}
+
+// assertSystemValue makes sure the component's system data holds the value
+func assertSystemValue(t *testing.T, s *Pebble, component string, key, value []byte) {
+	t.Helper()
+
+	saved, err := s.System(component).Get(key)
+	require.NoError(t, err)
+
+	assert.Equal(t, value, saved)
+}
+
+func TestStorage_System(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewPebble(t.TempDir())
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	chain, err := db.WithNamespace("chain")
+	require.NoError(t, err)
+
+	changes, unsubscribe := db.SubscribeChanges(10)
+	defer unsubscribe()
+
+	setSystemValue(t, db, "keys", []byte("k/1"), []byte("value 1"))
+	setSystemValue(t, db, "keys", []byte("k/2"), []byte("value 2"))
+	setSystemValue(t, db, "other", []byte("k/1"), []byte("other"))
+
+	// Make sure the system data is not published to the changefeed
+	assert.Empty(t, changes)
+
+	// Make sure the system data is shared by the namespaces
+	assertSystemValue(t, chain, "keys", []byte("k/1"), []byte("value 1"))
+
+	// Make sure the components are iterated separately
+	it, err := db.System("keys").Iterator([]byte("k/"), nil)
+	require.NoError(t, err)
+
+	keys := make([]string, 0)
+
+	for it.Next() {
+		kv, err := it.Value()
+		require.NoError(t, err)
+
+		keys = append(keys, string(kv.Key))
+	}
+
+	require.NoError(t, it.Error())
+	require.NoError(t, it.Close())
+
+	assert.Equal(t, []string{"k/1", "k/2"}, keys)
+
+	// Make sure the system data survives the chain resets,
+	// and is not part of the backups
+	blocks, txs := generateChain(t, 5, 3)
+
+	saveChain(t, db, blocks, txs)
+
+	var backup bytes.Buffer
+
+	_, err = db.Backup(&backup, 0)
+	require.NoError(t, err)
+
+	assert.NotContains(t, backup.String(), "value 1")
+
+	require.NoError(t, db.Archive("archive"))
+
+	saveChain(t, db, blocks, txs)
+	require.NoError(t, db.Wipe())
+
+	assertSystemValue(t, db, "keys", []byte("k/1"), []byte("value 1"))
+	assertSystemValue(t, db, "other", []byte("k/1"), []byte("other"))
+
+	// Make sure the deletes are applied
+	wb := db.System("keys").WriteBatch()
+	require.NoError(t, wb.Delete([]byte("k/1")))
+	require.NoError(t, wb.Commit())
+	require.NoError(t, wb.Rollback())
+
+	_, err = db.System("keys").Get([]byte("k/1"))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+}
+
+func TestStorage_MovePluginToSystem(t *testing.T) {
+	t.Parallel()
+
+	db, err := NewPebble(t.TempDir(), WithNamespace("chain"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, db.Close())
+	}()
+
+	wb := db.WriteBatch()
+	require.NoError(t, wb.SetPluginValue("keys", []byte("k/1"), []byte("value 1")))
+	require.NoError(t, wb.SetPluginValue("other", []byte("k/1"), []byte("other")))
+	require.NoError(t, wb.Commit())
+
+	require.NoError(t, db.MovePluginToSystem("keys", "keys"))
+
+	// Make sure the plugin data is moved, and the other plugins are not affected
+	assertSystemValue(t, db, "keys", []byte("k/1"), []byte("value 1"))
+
+	_, err = db.GetPluginValue("keys", []byte("k/1"))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	value, err := db.GetPluginValue("other", []byte("k/1"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("other"), value)
+
+	// Make sure the migrated system data is not overwritten
+	wb = db.WriteBatch()
+	require.NoError(t, wb.SetPluginValue("keys", []byte("k/1"), []byte("stale")))
+	require.NoError(t, wb.Commit())
+
+	require.NoError(t, db.MovePluginToSystem("keys", "keys"))
+
+	assertSystemValue(t, db, "keys", []byte("k/1"), []byte("value 1"))
+
+	_, err = db.GetPluginValue("keys", []byte("k/1"))
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+}
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/cockroachdb/pebble"
+	"go.uber.org/multierr"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+const (
+	// DefaultHotHeights is the default number of most recent
+	// heights kept in the hot tier
+	DefaultHotHeights = 100_000
+
+	// DefaultColdCompressionLevel is the default zstd level
+	// of the block and tx result values moved to the cold tier
+	DefaultColdCompressionLevel = 19
+
+	// keyColdBoundary is the lookup key for the first height kept in the hot tier.
+	// The lower heights are moved to the cold tier
+	keyColdBoundary = "/meta/cb"
+
+	// coldTierChunkSize is the number of heights moved to the cold tier at once
+	coldTierChunkSize = 1000
+)
+
+var (
+	errNoColdTier     = errors.New("no cold tier configured")
+	errColdTierSchema = errors.New("the cold tier requires the current key schema, migrate the storage first")
+)
+
+func keyBoundary(ns []byte) []byte {
+	key := slices.Clone(ns)
+	key = append(key, keyColdBoundary...)
+
+	return key
+}
+
+// coldBoundary returns the first height kept in the hot tier, read from the hot tier.
+// All heights are hot if no height was moved to the cold tier
+func coldBoundary(r pebble.Reader, ns []byte) (uint64, error) {
+	value, err := get(r, keyBoundary(ns))
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch cold tier boundary, %w", err)
+	}
+
+	_, boundary, err := decodeUint64Ascending(value)
+
+	return boundary, err
+}
+
+// MoveToColdTier moves the blocks and txs (along with their index entries) older
+// than the given number of most recent heights to the cold tier, compressed with the
+// cold tier compression level. The reads span both tiers. Returns the number of moved heights
+func (s *Pebble) MoveToColdTier(hotHeights uint64) (uint64, error) {
+	if s.cold == nil {
+		return 0, errNoColdTier
+	}
+
+	if s.schema != schemaV2 {
+		return 0, errColdTierSchema
+	}
+
+	latest, err := s.GetLatestHeight()
+	if errors.Is(err, storageErrors.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch latest height, %w", err)
+	}
+
+	if latest < hotHeights {
+		return 0, nil
+	}
+
+	target := latest - hotHeights + 1
+
+	start, err := coldBoundary(s.db, s.ns)
+	if err != nil {
+		return 0, err
+	}
+
+	for boundary := start; boundary < target; {
+		next := min(boundary+coldTierChunkSize, target)
+
+		if err := s.moveHeights(boundary, next); err != nil {
+			return boundary - start, fmt.Errorf("unable to move heights %d-%d, %w", boundary, next-1, err)
+		}
+
+		boundary = next
+	}
+
+	return max(target, start) - start, nil
+}
+
+// moveHeights moves the height range [from, to) to the cold tier.
+// The cold tier is written first, so the data is always readable from one of the tiers.
+// The move is not published to the changefeed, as it doesn't change the namespace data:
+// the standby instances keep the heights in their own tiers (moved with their own cold tier settings)
+func (s *Pebble) moveHeights(from, to uint64) error {
+	var (
+		cold = s.cold.NewBatch()
+		hot  = s.db.NewBatch()
+
+		kindOffset = heightKindOffset(s.ns)
+	)
+
+	defer func() {
+		_ = cold.Close()
+		_ = hot.Close()
+	}()
+
+	var (
+		lower = keyHeight(s.ns, from)
+		upper = keyHeight(s.ns, to)
+	)
+
+	it, err := s.db.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: upper,
+	})
+	if err != nil {
+		return err
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		key := slices.Clone(it.Key())
+
+		var value []byte
+
+		switch key[kindOffset] {
+		case kindBlock:
+			block, err := decodeBlock(it.Value())
+			if err != nil {
+				return multierr.Append(fmt.Errorf("unable to decode block, %w", err), it.Close())
+			}
+
+			if value, err = encodeBlock(block, s.coldCompressionLevel); err != nil {
+				return multierr.Append(err, it.Close())
+			}
+		case kindTx:
+			tx, err := decodeTx(it.Value())
+			if err != nil {
+				return multierr.Append(fmt.Errorf("unable to decode tx, %w", err), it.Close())
+			}
+
+			if value, err = encodeTx(tx, s.coldCompressionLevel); err != nil {
+				return multierr.Append(err, it.Close())
+			}
+
+			// The index entries are moved along with the tx
+			for _, indexKey := range txIndexKeys(s.ns, s.decoder, tx) {
+				if err := multierr.Append(cold.Set(indexKey, key, nil), hot.Delete(indexKey, nil)); err != nil {
+					return multierr.Append(err, it.Close())
+				}
+			}
+		default:
+			value = slices.Clone(it.Value())
+		}
+
+		if err := cold.Set(key, value, nil); err != nil {
+			return multierr.Append(err, it.Close())
+		}
+	}
+
+	if err := multierr.Append(it.Error(), it.Close()); err != nil {
+		return err
+	}
+
+	if err := cold.Commit(pebble.Sync); err != nil {
+		return fmt.Errorf("unable to write cold tier, %w", err)
+	}
+
+	// The moved heights are removed from the hot tier,
+	// along with the boundary update
+	if err := hot.DeleteRange(lower, upper, nil); err != nil {
+		return err
+	}
+
+	if err := hot.Set(keyBoundary(s.ns), encodeUint64Ascending(nil, to), nil); err != nil {
+		return err
+	}
+
+	return hot.Commit(pebble.Sync)
+}
+
+// tieredIterator creates the iterator over the height range [from, to), newest first if reversed,
+// spanning the cold and hot tiers (and the prune archive, see restorePruned). The boundary is read from the hot tier
+// snapshot, so the moved heights are read from the cold tier
+func tieredIterator[T any](
+	s *Pebble,
+	from,
+	to uint64,
+	reverse bool,
+	newIter func(snap *pebble.Snapshot, from, to uint64) (Iterator[T], error),
+) (Iterator[T], error) {
+	// The archived pruned heights of the range are restored first
+	if _, err := s.restorePruned(from, to); err != nil {
+		return nil, err
+	}
+
+	snap := s.db.NewSnapshot()
+
+	if s.cold == nil {
+		return newIter(snap, from, to)
+	}
+
+	boundary, err := coldBoundary(snap, s.ns)
+	if err != nil {
+		return nil, multierr.Append(err, snap.Close())
+	}
+
+	if from >= boundary {
+		return newIter(snap, from, to)
+	}
+
+	coldSnap := s.cold.NewSnapshot()
+
+	if to < boundary {
+		if err := snap.Close(); err != nil {
+			return nil, multierr.Append(err, coldSnap.Close())
+		}
+
+		return newIter(coldSnap, from, to)
+	}
+
+	coldIt, err := newIter(coldSnap, from, boundary)
+	if err != nil {
+		return nil, multierr.Append(err, snap.Close())
+	}
+
+	hotIt, err := newIter(snap, boundary, to)
+	if err != nil {
+		return nil, multierr.Append(err, coldIt.Close())
+	}
+
+	if reverse {
+		// The hot tier holds the newest heights
+		return &concatIter[T]{iters: []Iterator[T]{hotIt, coldIt}}, nil
+	}
+
+	return &concatIter[T]{iters: []Iterator[T]{coldIt, hotIt}}, nil
+}
+
+var _ Iterator[any] = &concatIter[any]{}
+
+// concatIter iterates over the iterators in order
+type concatIter[T any] struct {
+	iters []Iterator[T]
+	cur   int
+}
+
+func (ci *concatIter[T]) Next() bool {
+	for ; ci.cur < len(ci.iters); ci.cur++ {
+		it := ci.iters[ci.cur]
+
+		if it.Next() {
+			return true
+		}
+
+		if it.Error() != nil {
+			return false
+		}
+	}
+
+	return false
+}
+
+func (ci *concatIter[T]) Error() error {
+	return ci.iters[min(ci.cur, len(ci.iters)-1)].Error()
+}
+
+func (ci *concatIter[T]) Value() (T, error) {
+	return ci.iters[ci.cur].Value()
+}
+
+func (ci *concatIter[T]) Close() error {
+	var err error
+
+	for _, it := range ci.iters {
+		err = multierr.Append(err, it.Close())
+	}
+
+	return err
+}
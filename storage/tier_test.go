@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	storageErrors "github.com/gnolang/tx-indexer/storage/errors"
+)
+
+func TestStorage_ColdTier(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s, err := NewPebble(
+		filepath.Join(dir, "hot"),
+		WithNamespace("chain"),
+		WithColdTier(filepath.Join(dir, "cold"), DefaultColdCompressionLevel),
+	)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	blocks, txs := generateChain(t, 10, 3)
+	saveChain(t, s, blocks, txs)
+
+	// Keep the 4 most recent heights hot
+	moved, err := s.MoveToColdTier(4)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(7), moved)
+
+	// Make sure the moved heights are no longer hot
+	_, err = s.getBlock(s.db, 6)
+	assert.ErrorIs(t, err, storageErrors.ErrNotFound)
+
+	_, err = s.getBlock(s.db, 7)
+	assert.NoError(t, err)
+
+	// Make sure the reads span both tiers
+	assertChain(t, s, blocks, txs)
+
+	blockIt, err := s.BlockIterator(0, 0)
+	require.NoError(t, err)
+
+	iterated := make([]*types.Block, 0, len(blocks))
+
+	for blockIt.Next() {
+		block, err := blockIt.Value()
+		require.NoError(t, err)
+
+		iterated = append(iterated, block)
+	}
+
+	require.NoError(t, blockIt.Error())
+	require.NoError(t, blockIt.Close())
+
+	assert.Equal(t, blocks, iterated)
+
+	txIt, err := s.TxIterator(5, 8, 2, 3)
+	require.NoError(t, err)
+
+	iteratedTxs := make([]*types.TxResult, 0, 4)
+
+	for txIt.Next() {
+		tx, err := txIt.Value()
+		require.NoError(t, err)
+
+		iteratedTxs = append(iteratedTxs, tx)
+	}
+
+	require.NoError(t, txIt.Error())
+	require.NoError(t, txIt.Close())
+
+	assert.Equal(t, []*types.TxResult{txs[14], txs[17], txs[20], txs[23]}, iteratedTxs)
+
+	addressIt, err := s.TxByAddressIterator(crypto.Address{1}.String(), 0, 0)
+	require.NoError(t, err)
+
+	iteratedTxs = make([]*types.TxResult, 0, len(txs))
+
+	for addressIt.Next() {
+		tx, err := addressIt.Value()
+		require.NoError(t, err)
+
+		iteratedTxs = append(iteratedTxs, tx)
+	}
+
+	require.NoError(t, addressIt.Error())
+	require.NoError(t, addressIt.Close())
+
+	assert.Equal(t, txs, iteratedTxs)
+
+	// Make sure the cold heights are backed up
+	var backup bytes.Buffer
+
+	_, err = s.Backup(&backup, 0)
+	require.NoError(t, err)
+
+	restored, err := NewPebble(t.TempDir(), WithNamespace("chain"))
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, restored.Close())
+	}()
+
+	_, err = restored.Restore(&backup)
+	require.NoError(t, err)
+
+	assertChain(t, restored, blocks, txs)
+
+	// Make sure the moved heights are not moved again
+	moved, err = s.MoveToColdTier(4)
+	require.NoError(t, err)
+
+	assert.Zero(t, moved)
+}
+
+// collect reads the iterated values
+func collect[T any](t *testing.T, it Iterator[T], err error) []T {
+	t.Helper()
+
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, it.Close())
+	}()
+
+	values := make([]T, 0)
+
+	for it.Next() {
+		value, err := it.Value()
+		require.NoError(t, err)
+
+		values = append(values, value)
+	}
+
+	require.NoError(t, it.Error())
+
+	return values
+}
+
+func TestStorage_ReverseIterators(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s, err := NewPebble(
+		filepath.Join(dir, "hot"),
+		WithNamespace("chain"),
+		WithColdTier(filepath.Join(dir, "cold"), DefaultColdCompressionLevel),
+	)
+	require.NoError(t, err)
+
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	blocks, txs := generateChain(t, 10, 3)
+	saveChain(t, s, blocks, txs)
+
+	_, err = s.MoveToColdTier(4)
+	require.NoError(t, err)
+
+	// Make sure the reverse iterators span both tiers, newest first
+	reversedBlocks := slices.Clone(blocks)
+	slices.Reverse(reversedBlocks)
+
+	blockIt, err := s.ReverseBlockIterator(0, 0)
+	assert.Equal(t, reversedBlocks, collect(t, blockIt, err))
+
+	reversedTxs := slices.Clone(txs)
+	slices.Reverse(reversedTxs)
+
+	addressIt, err := s.ReverseTxByAddressIterator(crypto.Address{1}.String(), 0, 0)
+	assert.Equal(t, reversedTxs, collect(t, addressIt, err))
+
+	// Make sure the ranges match the forward iterators
+	txIt, err := s.ReverseTxIterator(5, 8, 2, 3)
+	assert.Equal(t, []*types.TxResult{txs[23], txs[20], txs[17], txs[14]}, collect(t, txIt, err))
+
+	addressIt, err = s.ReverseTxByAddressIterator(crypto.Address{1}.String(), 6, 8)
+	assert.Equal(t, []*types.TxResult{txs[20], txs[19], txs[18], txs[17], txs[16], txs[15]}, collect(t, addressIt, err))
+
+	// Make sure the plugin data is iterated last key first
+	wb := s.WriteBatch()
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, wb.SetPluginValue("plugin", []byte(key), []byte(key)))
+	}
+
+	require.NoError(t, wb.Commit())
+
+	kvIt, err := s.ReversePluginIterator("plugin", []byte("a"), []byte("c"))
+
+	keys := make([]string, 0)
+	for _, kv := range collect(t, kvIt, err) {
+		keys = append(keys, string(kv.Key))
+	}
+
+	assert.Equal(t, []string{"b", "a"}, keys)
+}
+
+func TestStorage_ColdTier_Invalid(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no cold tier", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := NewPebble(t.TempDir())
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		_, err = s.MoveToColdTier(DefaultHotHeights)
+		assert.ErrorIs(t, err, errNoColdTier)
+	})
+
+	t.Run("legacy key schema", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+
+		s, err := NewPebble(
+			filepath.Join(dir, "hot"),
+			WithColdTier(filepath.Join(dir, "cold"), DefaultColdCompressionLevel),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			assert.NoError(t, s.Close())
+		}()
+
+		s.schema = schemaV1
+
+		_, err = s.MoveToColdTier(DefaultHotHeights)
+		assert.ErrorIs(t, err, errColdTierSchema)
+	})
+}
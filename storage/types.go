@@ -31,9 +31,44 @@ type Reader interface {
 	// BlockIterator iterates over Blocks, limiting the results to be between the provided block numbers
 	BlockIterator(fromBlockNum, toBlockNum uint64) (Iterator[*types.Block], error)
 
+	// ReverseBlockIterator iterates over Blocks as BlockIterator, newest first
+	ReverseBlockIterator(fromBlockNum, toBlockNum uint64) (Iterator[*types.Block], error)
+
 	// TxIterator iterates over transactions, limiting the results to be between the provided block numbers
 	// and transaction indexes
 	TxIterator(fromBlockNum, toBlockNum uint64, fromTxIndex, toTxIndex uint32) (Iterator[*types.TxResult], error)
+
+	// ReverseTxIterator iterates over transactions as TxIterator, newest first
+	ReverseTxIterator(
+		fromBlockNum,
+		toBlockNum uint64,
+		fromTxIndex,
+		toTxIndex uint32,
+	) (Iterator[*types.TxResult], error)
+
+	// TxByAddressIterator iterates over transactions the address participated in,
+	// limiting the results to be between the provided block numbers
+	TxByAddressIterator(address string, fromBlockNum, toBlockNum uint64) (Iterator[*types.TxResult], error)
+
+	// ReverseTxByAddressIterator iterates over transactions the address participated in
+	// as TxByAddressIterator, newest first
+	ReverseTxByAddressIterator(address string, fromBlockNum, toBlockNum uint64) (Iterator[*types.TxResult], error)
+
+	// GetPluginValue fetches the value saved by the plugin under the given key
+	GetPluginValue(plugin string, key []byte) ([]byte, error)
+
+	// PluginIterator iterates over the plugin key-value pairs, limiting the results
+	// to be between the provided keys (relative to the plugin namespace)
+	PluginIterator(plugin string, fromKey, toKey []byte) (Iterator[*KeyValue], error)
+
+	// ReversePluginIterator iterates over the plugin key-value pairs as PluginIterator, last key first
+	ReversePluginIterator(plugin string, fromKey, toKey []byte) (Iterator[*KeyValue], error)
+}
+
+// KeyValue is a raw key-value pair
+type KeyValue struct {
+	Key   []byte
+	Value []byte
 }
 
 type Iterator[T any] interface {
@@ -54,11 +89,21 @@ type Writer interface {
 type Batch interface {
 	// SetLatestHeight saves the latest block height to the storage
 	SetLatestHeight(uint64) error
-	// SetBlock saves the block to the permanent storage
+	// SetBlock saves the block to the permanent storage.
+	// Fails with ErrSkipped if the save is skipped, see ConflictPolicy
 	SetBlock(block *types.Block) error
-	// SetTx saves the transaction to the permanent storage
+	// SetTx saves the transaction to the permanent storage.
+	// Fails with ErrSkipped if the save is skipped, see ConflictPolicy
 	SetTx(tx *types.TxResult) error
 
+	// SetPluginValue saves the value under the plugin's own namespace
+	SetPluginValue(plugin string, key, value []byte) error
+	// GetPluginValue fetches the value from the plugin's own namespace,
+	// including the changes not yet committed
+	GetPluginValue(plugin string, key []byte) ([]byte, error)
+	// DeletePluginValue removes the value from the plugin's own namespace
+	DeletePluginValue(plugin string, key []byte) error
+
 	// Commit stores all the provided info on the storage and make
 	// it available for other storage readers
 	Commit() error
@@ -0,0 +1,71 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+const (
+	listenPIDEnv = "LISTEN_PID"
+	listenFDsEnv = "LISTEN_FDS"
+
+	// listenFDsStart is the first passed file descriptor
+	// (following stdin, stdout and stderr)
+	listenFDsStart = 3
+)
+
+// Listeners returns the listeners passed to the process with socket activation
+// (by systemd, or by a parent process handing off its listeners), if any.
+// The passed listeners are only returned once, and not inherited by the child processes
+func Listeners() ([]net.Listener, error) {
+	defer func() {
+		_ = os.Unsetenv(listenPIDEnv)
+		_ = os.Unsetenv(listenFDsEnv)
+	}()
+
+	count, err := listenFDs(os.Getenv(listenPIDEnv), os.Getenv(listenFDsEnv), os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, count)
+
+	for fd := listenFDsStart; fd < listenFDsStart+count; fd++ {
+		file := os.NewFile(uintptr(fd), fmt.Sprintf("listener-%d", fd))
+
+		ln, err := net.FileListener(file)
+
+		// The listener holds its own copy of the descriptor
+		_ = file.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to create listener from descriptor %d, %w", fd, err)
+		}
+
+		listeners = append(listeners, ln)
+	}
+
+	return listeners, nil
+}
+
+// listenFDs returns the number of the descriptors passed to the given process.
+// The descriptors are passed to the process if the PID is not set (handoff),
+// or matches the process PID (systemd)
+func listenFDs(pid, fds string, processPID int) (int, error) {
+	if fds == "" {
+		return 0, nil
+	}
+
+	if pid != "" && pid != strconv.Itoa(processPID) {
+		return 0, nil
+	}
+
+	count, err := strconv.Atoi(fds)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid %s value %q", listenFDsEnv, fds)
+	}
+
+	return count, nil
+}
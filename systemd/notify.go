@@ -0,0 +1,87 @@
+// Package systemd implements the systemd service notifications (sd_notify),
+// for the Type=notify startup signaling and the service watchdog,
+// and the socket activation (passed listeners)
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The notified service states
+const (
+	StateReady    = "READY=1"
+	StateStopping = "STOPPING=1"
+	StateWatchdog = "WATCHDOG=1"
+)
+
+const (
+	notifySocketEnv = "NOTIFY_SOCKET"
+	watchdogUsecEnv = "WATCHDOG_USEC"
+	watchdogPIDEnv  = "WATCHDOG_PID"
+)
+
+// StateMainPID returns the state notifying the service main process PID,
+// for the process taking over the service from its parent
+func StateMainPID(pid int) string {
+	return fmt.Sprintf("MAINPID=%d", pid)
+}
+
+// Notify notifies systemd of the service state.
+// It's a no-op if the service is not run by systemd (the notify socket is not set)
+func Notify(state string) error {
+	return notify(os.Getenv(notifySocketEnv), state)
+}
+
+// notify sends the state to the notify socket, if any
+func notify(socket, state string) error {
+	if socket == "" {
+		return nil
+	}
+
+	// Abstract socket addresses start with a null byte
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("unable to dial notify socket, %w", err)
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("unable to write notification, %w", err)
+	}
+
+	return nil
+}
+
+// WatchdogTimeout returns the timeout of the service watchdog
+// (WatchdogSec), if enabled for the process. Returns 0 otherwise
+func WatchdogTimeout() (time.Duration, error) {
+	return watchdogTimeout(os.Getenv(watchdogUsecEnv), os.Getenv(watchdogPIDEnv), os.Getpid())
+}
+
+// watchdogTimeout parses the watchdog timeout, enabled for the given process
+func watchdogTimeout(usec, pid string, processPID int) (time.Duration, error) {
+	if usec == "" {
+		return 0, nil
+	}
+
+	// The watchdog is for another process
+	if pid != "" && pid != strconv.Itoa(processPID) {
+		return 0, nil
+	}
+
+	timeout, err := strconv.ParseUint(usec, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse %s, %w", watchdogUsecEnv, err)
+	}
+
+	return time.Duration(timeout) * time.Microsecond, nil
+}
@@ -0,0 +1,13 @@
+package systemd
+
+import "go.uber.org/zap"
+
+type Option func(w *Watchdog)
+
+// WithLogger sets the logger to be used
+// with the watchdog
+func WithLogger(logger *zap.Logger) Option {
+	return func(w *Watchdog) {
+		w.logger = logger
+	}
+}
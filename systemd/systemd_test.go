@@ -0,0 +1,219 @@
+package systemd
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNotifySocket creates a new notify socket, returning its path
+func newNotifySocket(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+
+	// The socket paths are limited in length, so a short temp dir is used
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	socket := filepath.Join(dir, "notify")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return socket, conn
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not run by systemd", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, notify("", StateReady))
+	})
+
+	t.Run("notified state", func(t *testing.T) {
+		t.Parallel()
+
+		socket, conn := newNotifySocket(t)
+
+		require.NoError(t, notify(socket, StateReady))
+
+		buf := make([]byte, 64)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		n, err := conn.Read(buf)
+		require.NoError(t, err)
+
+		assert.Equal(t, StateReady, string(buf[:n]))
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Error(t, notify(filepath.Join(t.TempDir(), "missing"), StateReady))
+	})
+}
+
+func TestWatchdogTimeout(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		usec     string
+		pid      string
+		expected time.Duration
+	}{
+		{"disabled", "", "", 0},
+		{"enabled", "30000000", "", 30 * time.Second},
+		{"enabled for the process", "1000000", "100", time.Second},
+		{"enabled for another process", "1000000", "200", 0},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			timeout, err := watchdogTimeout(testCase.usec, testCase.pid, 100)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, timeout)
+		})
+	}
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := watchdogTimeout("invalid", "", 100)
+		assert.Error(t, err)
+	})
+}
+
+func TestWatchdog_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alive bool
+		pings int
+
+		mux sync.Mutex
+	)
+
+	w := NewWatchdog(20*time.Millisecond, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+
+		return alive
+	})
+
+	w.notifyFn = func(state string) error {
+		mux.Lock()
+		defer mux.Unlock()
+
+		assert.Equal(t, StateWatchdog, state)
+
+		pings++
+
+		return nil
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	done := make(chan error)
+
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	// Make sure the watchdog is not pinged while not alive
+	time.Sleep(100 * time.Millisecond)
+
+	mux.Lock()
+	assert.Zero(t, pings)
+
+	alive = true
+	mux.Unlock()
+
+	// Make sure the watchdog is pinged once alive
+	require.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+
+		return pings > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancelFn()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchdog not stopped")
+	}
+}
+
+func TestWatchdog_Disabled(t *testing.T) {
+	t.Parallel()
+
+	w := NewWatchdog(0, func() bool {
+		t.Fatal("unexpected liveness check")
+
+		return true
+	})
+
+	assert.NoError(t, w.Run(context.Background()))
+}
+
+func TestListenFDs(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		pid      string
+		fds      string
+		expected int
+	}{
+		{"not passed", "", "", 0},
+		{"passed by systemd", "100", "2", 2},
+		{"passed to another process", "200", "2", 0},
+		{"passed by the parent process", "", "1", 1},
+	}
+
+	for _, testCase := range testTable {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			count, err := listenFDs(testCase.pid, testCase.fds, 100)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expected, count)
+		})
+	}
+
+	t.Run("invalid count", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := listenFDs("", "-1", 100)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,68 @@
+package systemd
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AliveFn returns a flag indicating if the service is alive,
+// and the watchdog should be pinged
+type AliveFn func() bool
+
+// Watchdog pings the systemd service watchdog while the service is alive,
+// so systemd restarts the service once it's wedged, and stops pinging
+type Watchdog struct {
+	logger *zap.Logger
+
+	aliveFn  AliveFn
+	notifyFn func(state string) error
+
+	timeout time.Duration
+}
+
+// NewWatchdog creates a new watchdog, with the given watchdog timeout
+// (see WatchdogTimeout), pinged while the service is alive
+func NewWatchdog(timeout time.Duration, aliveFn AliveFn, opts ...Option) *Watchdog {
+	w := &Watchdog{
+		logger:   zap.NewNop(),
+		aliveFn:  aliveFn,
+		notifyFn: Notify,
+		timeout:  timeout,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Run pings the watchdog at half the timeout (as systemd recommends),
+// until the context is cancelled. The pings are skipped while the service is not alive
+func (w *Watchdog) Run(ctx context.Context) error {
+	if w.timeout <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(w.timeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if !w.aliveFn() {
+				w.logger.Warn("service not alive, skipping the watchdog ping")
+
+				continue
+			}
+
+			if err := w.notifyFn(StateWatchdog); err != nil {
+				w.logger.Error("unable to ping watchdog", zap.Error(err))
+			}
+		}
+	}
+}
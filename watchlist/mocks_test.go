@@ -0,0 +1,21 @@
+package watchlist
+
+import (
+	"context"
+
+	"github.com/gnolang/tx-indexer/notify"
+)
+
+type notifyDelegate func(context.Context, *notify.Notification) error
+
+type mockNotifier struct {
+	notifyFn notifyDelegate
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, notification *notify.Notification) error {
+	if m.notifyFn != nil {
+		return m.notifyFn(ctx, notification)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package watchlist
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+)
+
+type Option func(w *Watchlist)
+
+// WithLogger sets the logger to be used
+// with the watchlist
+func WithLogger(logger *zap.Logger) Option {
+	return func(w *Watchlist) {
+		w.logger = logger
+	}
+}
+
+// WithNotifyTimeout sets the timeout for
+// delivering a single notification
+func WithNotifyTimeout(timeout time.Duration) Option {
+	return func(w *Watchlist) {
+		w.notifyTimeout = timeout
+	}
+}
+
+// WithMaxWatches sets the maximum number
+// of active watches. 0 means no limit
+func WithMaxWatches(maxWatches int) Option {
+	return func(w *Watchlist) {
+		w.maxWatches = maxWatches
+	}
+}
+
+// WithMaxOwnerWatches sets the maximum number
+// of active watches per owner. 0 means no limit
+func WithMaxOwnerWatches(maxWatches int) Option {
+	return func(w *Watchlist) {
+		w.maxOwnerWatches = maxWatches
+	}
+}
+
+// WithQueueSize sets the number of notifications
+// queued for delivery per watch
+func WithQueueSize(size int) Option {
+	return func(w *Watchlist) {
+		w.queueSize = size
+	}
+}
+
+// WithDecoder sets the chain decoder of the txs,
+// whose addresses are matched with the watches
+func WithDecoder(decoder *decode.Decoder) Option {
+	return func(w *Watchlist) {
+		w.decoder = decoder
+	}
+}
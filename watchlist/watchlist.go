@@ -0,0 +1,310 @@
+// Package watchlist keeps track of the watched addresses, and notifies
+// the watchers whenever a watched address appears in a new transaction.
+// The watches are kept in memory, so they don't survive a restart
+package watchlist
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gnolang/tx-indexer/decode"
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/notify"
+	commonTypes "github.com/gnolang/tx-indexer/types"
+)
+
+const (
+	// Topic is the watchlist notification topic
+	Topic = "watchlist"
+
+	// DefaultNotifyTimeout is the default timeout for delivering a single notification
+	DefaultNotifyTimeout = 10 * time.Second
+
+	// DefaultMaxWatches is the default maximum number of active watches
+	DefaultMaxWatches = 1000
+
+	// DefaultMaxOwnerWatches is the default maximum number of active watches per owner
+	DefaultMaxOwnerWatches = 10
+
+	// DefaultQueueSize is the default number of notifications queued
+	// for delivery per watch, before the new notifications are dropped
+	DefaultQueueSize = 100
+)
+
+var (
+	// ErrTooManyWatches is the error of adding a watch over the maximum number of watches
+	ErrTooManyWatches = errors.New("too many watches")
+
+	// ErrTooManyOwnerWatches is the error of adding a watch over the maximum number of watches of the owner
+	ErrTooManyOwnerWatches = errors.New("too many watches of the owner")
+)
+
+// watch is a single set of watched addresses, with the notifier for them.
+// The notifications are delivered in order, from the watch queue
+type watch struct {
+	addresses map[string]struct{}
+	notifier  notify.Notifier
+	owner     string
+
+	queue    chan *notify.Notification
+	cancelFn context.CancelFunc
+}
+
+// Watchlist keeps track of the watched addresses
+type Watchlist struct {
+	logger *zap.Logger
+	events Events
+
+	ctx     context.Context
+	watches map[string]*watch
+	owners  map[string]int // the number of watches, by owner
+	decoder *decode.Decoder
+
+	notifyTimeout   time.Duration
+	maxWatches      int
+	maxOwnerWatches int
+	queueSize       int
+
+	sync.RWMutex
+}
+
+// New creates a new watchlist, notifying the watchers of
+// the new transactions until the context is cancelled
+func New(ctx context.Context, events Events, opts ...Option) *Watchlist {
+	w := &Watchlist{
+		logger:          zap.NewNop(),
+		events:          events,
+		ctx:             ctx,
+		watches:         make(map[string]*watch),
+		owners:          make(map[string]int),
+		notifyTimeout:   DefaultNotifyTimeout,
+		maxWatches:      DefaultMaxWatches,
+		maxOwnerWatches: DefaultMaxOwnerWatches,
+		queueSize:       DefaultQueueSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	// Subscribe to new blocks
+	go w.subscribeToEvents(ctx)
+
+	return w
+}
+
+// Add starts watching the addresses for the owner (ex. the WS connection, or the API key), returning the watch ID.
+// Returns ErrTooManyOwnerWatches if the maximum number of watches of the owner is reached,
+// and ErrTooManyWatches if the maximum number of all watches is reached
+func (w *Watchlist) Add(owner string, addresses []string, notifier notify.Notifier) (string, error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.maxOwnerWatches > 0 && w.owners[owner] >= w.maxOwnerWatches {
+		return "", ErrTooManyOwnerWatches
+	}
+
+	if w.maxWatches > 0 && len(w.watches) >= w.maxWatches {
+		return "", ErrTooManyWatches
+	}
+
+	watched := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		watched[address] = struct{}{}
+	}
+
+	id := uuid.New().String()
+
+	ctx, cancelFn := context.WithCancel(w.ctx)
+
+	added := &watch{
+		addresses: watched,
+		notifier:  notifier,
+		owner:     owner,
+		queue:     make(chan *notify.Notification, w.queueSize),
+		cancelFn:  cancelFn,
+	}
+
+	w.watches[id] = added
+	w.owners[owner]++
+
+	go w.deliverQueued(ctx, id, added)
+
+	return id, nil
+}
+
+// Remove stops the watch of the owner with the ID.
+// Returns a flag indicating if the watch was present and removed
+func (w *Watchlist) Remove(owner, id string) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	removed, exists := w.watches[id]
+	if !exists || removed.owner != owner {
+		return false
+	}
+
+	w.remove(id, removed)
+
+	return true
+}
+
+// RemoveOwner stops all the watches of the owner (ex. once its WS connection is closed)
+func (w *Watchlist) RemoveOwner(owner string) {
+	w.Lock()
+	defer w.Unlock()
+
+	for id, removed := range w.watches {
+		if removed.owner == owner {
+			w.remove(id, removed)
+		}
+	}
+}
+
+// remove stops the watch with the ID. The lock is expected to be held
+func (w *Watchlist) remove(id string, removed *watch) {
+	removed.cancelFn()
+	delete(w.watches, id)
+
+	w.owners[removed.owner]--
+	if w.owners[removed.owner] <= 0 {
+		delete(w.owners, removed.owner)
+	}
+}
+
+// Len returns the number of active watches
+func (w *Watchlist) Len() int {
+	w.RLock()
+	defer w.RUnlock()
+
+	return len(w.watches)
+}
+
+// subscribeToEvents notifies the watchers of the transactions in the new blocks
+func (w *Watchlist) subscribeToEvents(ctx context.Context) {
+	subscription := w.events.Subscribe([]events.Type{commonTypes.NewBlockEvent})
+	defer w.events.CancelSubscription(subscription.ID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, more := <-subscription.SubCh:
+			if !more {
+				return
+			}
+
+			newBlock, ok := event.(*commonTypes.NewBlock)
+			if !ok {
+				continue
+			}
+
+			for _, txResult := range newBlock.Results {
+				w.notifyTx(txResult)
+			}
+		}
+	}
+}
+
+// notifyTx queues the notifications of the watchers of the addresses in the transaction.
+// The notifications are delivered outside of the lock, so slow watchers don't block the others
+func (w *Watchlist) notifyTx(txResult *types.TxResult) {
+	tx, err := w.decoder.TxResult(txResult)
+	if err != nil {
+		// Transactions that can't be decoded are not matched
+		return
+	}
+
+	addresses := decode.Addresses(tx)
+	if len(addresses) == 0 {
+		return
+	}
+
+	w.RLock()
+	defer w.RUnlock()
+
+	for id, watch := range w.watches {
+		matched := make([]string, 0)
+
+		for _, address := range addresses {
+			if _, ok := watch.addresses[address.String()]; ok {
+				matched = append(matched, address.String())
+			}
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+
+		sort.Strings(matched)
+
+		notification := &notify.Notification{
+			Topic:     Topic,
+			Source:    id,
+			Message:   "watched address appeared in a new transaction",
+			Height:    txResult.Height,
+			Index:     txResult.Index,
+			TxHash:    base64.StdEncoding.EncodeToString(txResult.Tx.Hash()),
+			Addresses: matched,
+		}
+
+		select {
+		case watch.queue <- notification:
+		default:
+			w.logger.Warn(
+				"dropped watchlist notification, the watch queue is full",
+				zap.String("watch", id),
+				zap.Int64("height", txResult.Height),
+			)
+		}
+	}
+}
+
+// deliverQueued delivers the queued notifications of the watch in order,
+// until the watch is removed. The watch is removed if the notifier is closed
+func (w *Watchlist) deliverQueued(ctx context.Context, id string, watch *watch) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-watch.queue:
+			if closed := w.deliver(ctx, id, watch.notifier, notification); closed {
+				w.Remove(watch.owner, id)
+
+				return
+			}
+		}
+	}
+}
+
+// deliver delivers the notification, returning a flag indicating if the notifier is closed
+func (w *Watchlist) deliver(
+	ctx context.Context,
+	id string,
+	notifier notify.Notifier,
+	notification *notify.Notification,
+) bool {
+	notifyCtx, cancelFn := context.WithTimeout(ctx, w.notifyTimeout)
+	defer cancelFn()
+
+	err := notifier.Notify(notifyCtx, notification)
+	if err == nil {
+		return false
+	}
+
+	w.logger.Warn(
+		"unable to deliver watchlist notification",
+		zap.String("watch", id),
+		zap.Error(err),
+	)
+
+	return errors.Is(err, notify.ErrClosed)
+}
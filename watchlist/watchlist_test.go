@@ -0,0 +1,258 @@
+package watchlist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/tx-indexer/events"
+	"github.com/gnolang/tx-indexer/mock"
+	"github.com/gnolang/tx-indexer/notify"
+	commonTypes "github.com/gnolang/tx-indexer/types"
+)
+
+// newTxResult creates a new bank send transaction result
+func newTxResult(height int64, from, to crypto.Address) *types.TxResult {
+	return &types.TxResult{
+		Height: height,
+		Tx: amino.MustMarshal(std.Tx{
+			Msgs: []std.Msg{bank.MsgSend{FromAddress: from, ToAddress: to}},
+		}),
+	}
+}
+
+// newWatchlist creates a new watchlist, fed with the returned block channel
+func newWatchlist(t *testing.T, opts ...Option) (*Watchlist, chan events.Event) {
+	t.Helper()
+
+	var (
+		blockCh = make(chan events.Event)
+
+		mockEvents = &mock.Events{
+			SubscribeFn: func(_ []events.Type) *events.Subscription {
+				return &events.Subscription{
+					SubCh: blockCh,
+				}
+			},
+		}
+	)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	return New(ctx, mockEvents, opts...), blockCh
+}
+
+func TestWatchlist_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+		carol = crypto.Address{3}
+
+		notificationsCh = make(chan *notify.Notification, 10)
+	)
+
+	w, blockCh := newWatchlist(t)
+
+	id, err := w.Add(
+		"owner",
+		[]string{bob.String(), carol.String()},
+		&mockNotifier{
+			notifyFn: func(_ context.Context, notification *notify.Notification) error {
+				notificationsCh <- notification
+
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	blockCh <- &commonTypes.NewBlock{
+		Block: &types.Block{},
+		Results: []*types.TxResult{
+			newTxResult(1, alice, crypto.Address{4}),
+			newTxResult(1, alice, bob),
+		},
+	}
+
+	select {
+	case notification := <-notificationsCh:
+		assert.Equal(t, Topic, notification.Topic)
+		assert.Equal(t, id, notification.Source)
+		assert.Equal(t, int64(1), notification.Height)
+		assert.Equal(t, []string{bob.String()}, notification.Addresses)
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification not delivered")
+	}
+
+	// Only the matching transaction is notified
+	assert.Empty(t, notificationsCh)
+}
+
+func TestWatchlist_RemoveClosed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+	)
+
+	w, blockCh := newWatchlist(t)
+
+	id, err := w.Add(
+		"owner",
+		[]string{alice.String()},
+		&mockNotifier{
+			notifyFn: func(_ context.Context, _ *notify.Notification) error {
+				return notify.ErrClosed
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, w.Len())
+
+	blockCh <- &commonTypes.NewBlock{
+		Block:   &types.Block{},
+		Results: []*types.TxResult{newTxResult(1, alice, bob)},
+	}
+
+	// The watch with the closed notifier is removed
+	assert.Eventually(t, func() bool {
+		return w.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.False(t, w.Remove("owner", id))
+}
+
+func TestWatchlist_SlowWatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.Address{1}
+		bob   = crypto.Address{2}
+
+		blockedCh       = make(chan struct{})
+		notificationsCh = make(chan *notify.Notification, 10)
+	)
+
+	defer close(blockedCh)
+
+	w, blockCh := newWatchlist(t, WithQueueSize(1))
+
+	// Add a watch whose deliveries never complete
+	_, err := w.Add(
+		"owner",
+		[]string{alice.String()},
+		&mockNotifier{
+			notifyFn: func(_ context.Context, _ *notify.Notification) error {
+				<-blockedCh
+
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = w.Add(
+		"owner",
+		[]string{alice.String()},
+		&mockNotifier{
+			notifyFn: func(_ context.Context, notification *notify.Notification) error {
+				notificationsCh <- notification
+
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	// Make sure the slow watch doesn't block the other watches,
+	// or the new blocks (its queue overflows, dropping the notifications)
+	for height := int64(1); height <= 3; height++ {
+		blockCh <- &commonTypes.NewBlock{
+			Block:   &types.Block{},
+			Results: []*types.TxResult{newTxResult(height, alice, bob)},
+		}
+
+		select {
+		case notification := <-notificationsCh:
+			assert.Equal(t, height, notification.Height)
+		case <-time.After(5 * time.Second):
+			t.Fatal("notification not delivered")
+		}
+	}
+}
+
+func TestWatchlist_MaxWatches(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newWatchlist(t, WithMaxWatches(1))
+
+	id, err := w.Add("owner", []string{crypto.Address{1}.String()}, &mockNotifier{})
+	require.NoError(t, err)
+
+	// Make sure the watches over the limit are rejected
+	_, err = w.Add("owner", []string{crypto.Address{2}.String()}, &mockNotifier{})
+	assert.ErrorIs(t, err, ErrTooManyWatches)
+
+	// Make sure the removed watches free up the slots
+	require.True(t, w.Remove("owner", id))
+
+	_, err = w.Add("owner", []string{crypto.Address{2}.String()}, &mockNotifier{})
+	assert.NoError(t, err)
+}
+
+func TestWatchlist_MaxOwnerWatches(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newWatchlist(t, WithMaxOwnerWatches(1))
+
+	id, err := w.Add("alice", []string{crypto.Address{1}.String()}, &mockNotifier{})
+	require.NoError(t, err)
+
+	// Make sure the watches of the owner over the limit are rejected
+	_, err = w.Add("alice", []string{crypto.Address{2}.String()}, &mockNotifier{})
+	assert.ErrorIs(t, err, ErrTooManyOwnerWatches)
+
+	// Make sure the other owners are not limited
+	_, err = w.Add("bob", []string{crypto.Address{2}.String()}, &mockNotifier{})
+	require.NoError(t, err)
+
+	// Make sure the watches are only removed by their owner
+	assert.False(t, w.Remove("bob", id))
+	require.True(t, w.Remove("alice", id))
+
+	_, err = w.Add("alice", []string{crypto.Address{2}.String()}, &mockNotifier{})
+	assert.NoError(t, err)
+}
+
+func TestWatchlist_RemoveOwner(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newWatchlist(t)
+
+	for range 3 {
+		_, err := w.Add("conn", []string{crypto.Address{1}.String()}, &mockNotifier{})
+		require.NoError(t, err)
+	}
+
+	id, err := w.Add("other", []string{crypto.Address{1}.String()}, &mockNotifier{})
+	require.NoError(t, err)
+
+	// Make sure only the watches of the owner are removed
+	w.RemoveOwner("conn")
+
+	assert.Equal(t, 1, w.Len())
+	assert.True(t, w.Remove("other", id))
+}